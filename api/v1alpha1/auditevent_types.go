@@ -0,0 +1,110 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AuditEventKind is the string representation of an AuditEvent.
+	AuditEventKind = "AuditEvent"
+
+	// AuditActionLabelKey is the label holding the action of the AuditEvent.
+	AuditActionLabelKey = "k0rdent.mirantis.com/audit-action"
+	// AuditSubjectLabelKey is the label holding the name of the subject of the AuditEvent.
+	AuditSubjectLabelKey = "k0rdent.mirantis.com/audit-subject"
+)
+
+// AuditAction is the material action recorded in the AuditEvent.
+type AuditAction string
+
+const (
+	// AuditActionTemplateUpgrade is recorded upon change of the ClusterTemplate of a ClusterDeployment.
+	AuditActionTemplateUpgrade AuditAction = "TemplateUpgrade"
+	// AuditActionCredentialChange is recorded upon change of the Credential of a ClusterDeployment.
+	AuditActionCredentialChange AuditAction = "CredentialChange"
+	// AuditActionClusterDelete is recorded upon deletion of a ClusterDeployment.
+	AuditActionClusterDelete AuditAction = "ClusterDelete"
+	// AuditActionServiceRollout is recorded upon change of the services of
+	// a ClusterDeployment or a MultiClusterService.
+	AuditActionServiceRollout AuditAction = "ServiceRollout"
+	// AuditActionReleaseUpgrade is recorded upon change of the Release of the Management.
+	AuditActionReleaseUpgrade AuditAction = "ReleaseUpgrade"
+)
+
+// AuditSubject references the object the action has been performed on.
+type AuditSubject struct {
+	// Kind of the object.
+	Kind string `json:"kind"`
+	// Namespace of the object, empty for the cluster-scoped objects.
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the object.
+	Name string `json:"name"`
+}
+
+// AuditActor describes what performed the action.
+type AuditActor struct {
+	// Controller is the name of the kcm controller that performed the change.
+	Controller string `json:"controller,omitempty"`
+}
+
+// AuditEventSpec defines the recorded action.
+type AuditEventSpec struct {
+	// +kubebuilder:validation:Enum=TemplateUpgrade;CredentialChange;ClusterDelete;ServiceRollout;ReleaseUpgrade
+
+	// Action is the type of the recorded action.
+	Action AuditAction `json:"action"`
+	// Timestamp is the time the action has been performed at.
+	Timestamp metav1.Time `json:"timestamp"`
+	// Details hold the action-specific data, e.g. the previous and the new values.
+	Details map[string]string `json:"details,omitempty"`
+	// Message is a human-readable description of the action.
+	Message string `json:"message,omitempty"`
+	// Subject is the object the action has been performed on.
+	Subject AuditSubject `json:"subject"`
+	// Actor is what performed the action.
+	Actor AuditActor `json:"actor"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:shortName=audit
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.action`,description="Recorded action"
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.subject.kind`,description="Kind of the subject"
+// +kubebuilder:printcolumn:name="Subject",type=string,JSONPath=`.spec.subject.name`,description="Name of the subject"
+// +kubebuilder:printcolumn:name="Controller",type=string,JSONPath=`.spec.actor.controller`,description="Controller that performed the change"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.spec.timestamp`,description="Time elapsed since the action"
+
+// AuditEvent is the Schema for the auditevents API
+type AuditEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AuditEventSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AuditEventList contains a list of AuditEvent
+type AuditEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuditEvent `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuditEvent{}, &AuditEventList{})
+}
@@ -0,0 +1,110 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterDeploymentRevisionKind is the string representation of a ClusterDeploymentRevision.
+	ClusterDeploymentRevisionKind = "ClusterDeploymentRevision"
+
+	// ClusterDeploymentNameLabel is the label of the objects belonging to the ClusterDeployment.
+	ClusterDeploymentNameLabel = "k0rdent.mirantis.com/cluster-deployment"
+
+	// ClusterDeploymentRollbackAnnotation requests the rollback of the
+	// ClusterDeployment to the revision of the given number.
+	ClusterDeploymentRollbackAnnotation = "k0rdent.mirantis.com/rollback-to-revision"
+)
+
+// RevisionOutcome is the outcome of the application of the revision.
+type RevisionOutcome string
+
+const (
+	// RevisionOutcomeProgressing is the outcome of the revision being applied.
+	RevisionOutcomeProgressing RevisionOutcome = "Progressing"
+	// RevisionOutcomeSucceeded is the outcome of the revision the cluster has
+	// become ready with.
+	RevisionOutcomeSucceeded RevisionOutcome = "Succeeded"
+	// RevisionOutcomeFailed is the outcome of the revision failed to be applied.
+	RevisionOutcomeFailed RevisionOutcome = "Failed"
+	// RevisionOutcomeSuperseded is the outcome of the revision replaced by
+	// the next one before its application has completed.
+	RevisionOutcomeSuperseded RevisionOutcome = "Superseded"
+)
+
+// ClusterDeploymentRevisionSpec defines the applied spec of the ClusterDeployment.
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+type ClusterDeploymentRevisionSpec struct {
+	// Config is the configuration of the ClusterTemplate applied by the revision.
+	Config *apiextensionsv1.JSON `json:"config,omitempty"`
+	// ClusterDeploymentName is the name of the ClusterDeployment the revision belongs to.
+	ClusterDeploymentName string `json:"clusterDeploymentName"`
+	// Template is the name of the ClusterTemplate applied by the revision.
+	Template string `json:"template"`
+	// Credential is the name of the Credential applied by the revision.
+	Credential string `json:"credential,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+
+	// Revision is the sequence number of the revision.
+	Revision int64 `json:"revision"`
+}
+
+// ClusterDeploymentRevisionStatus defines the outcome of the application of the revision.
+type ClusterDeploymentRevisionStatus struct {
+	// AppliedTime is the time the application of the revision has been started at.
+	AppliedTime *metav1.Time `json:"appliedTime,omitempty"`
+	// CompletionTime is the time the application of the revision has completed at.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Outcome is the outcome of the application of the revision.
+	Outcome RevisionOutcome `json:"outcome,omitempty"`
+	// Message is the details of the outcome, e.g. the failure.
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=cdrev
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterDeploymentName`,description="Name of the ClusterDeployment"
+// +kubebuilder:printcolumn:name="Revision",type=integer,JSONPath=`.spec.revision`,description="Sequence number of the revision"
+// +kubebuilder:printcolumn:name="Template",type=string,JSONPath=`.spec.template`,description="ClusterTemplate applied by the revision"
+// +kubebuilder:printcolumn:name="Outcome",type=string,JSONPath=`.status.outcome`,description="Outcome of the application of the revision"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Time elapsed since object creation"
+
+// ClusterDeploymentRevision is the Schema for the clusterdeploymentrevisions API
+type ClusterDeploymentRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterDeploymentRevisionSpec   `json:"spec,omitempty"`
+	Status ClusterDeploymentRevisionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterDeploymentRevisionList contains a list of ClusterDeploymentRevision
+type ClusterDeploymentRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterDeploymentRevision `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterDeploymentRevision{}, &ClusterDeploymentRevisionList{})
+}
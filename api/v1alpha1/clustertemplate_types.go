@@ -58,6 +58,10 @@ type ClusterTemplateStatus struct {
 	KubernetesVersion string `json:"k8sVersion,omitempty"`
 	// Providers represent required CAPI providers.
 	Providers Providers `json:"providers,omitempty"`
+	// Usage is the number of the ClusterDeployments referencing the template
+	// either in the spec or as the applied one, the template is safe to be
+	// removed once it is not referenced anymore.
+	Usage *TemplateUsage `json:"usage,omitempty"`
 
 	TemplateStatusCommon `json:",inline"`
 }
@@ -107,9 +111,11 @@ func (t *ClusterTemplate) GetCommonStatus() *TemplateStatusCommon {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=clustertmpl
 // +kubebuilder:printcolumn:name="valid",type="boolean",JSONPath=".status.valid",description="Valid",priority=0
+// +kubebuilder:printcolumn:name="clusterDeployments",type="integer",JSONPath=".status.usage.clusterDeployments",description="Number of the ClusterDeployments referencing the template",priority=0
 // +kubebuilder:printcolumn:name="validationError",type="string",JSONPath=".status.validationError",description="Validation Error",priority=1
 // +kubebuilder:printcolumn:name="description",type="string",JSONPath=".status.description",description="Description",priority=1
 
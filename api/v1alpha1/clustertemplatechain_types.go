@@ -33,6 +33,7 @@ func (t *ClusterTemplateChain) GetSpec() *TemplateChainSpec {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 
 // ClusterTemplateChain is the Schema for the clustertemplatechains API
 type ClusterTemplateChain struct {
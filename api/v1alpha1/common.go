@@ -28,6 +28,27 @@ const (
 	ProgressingReason string = "Progressing"
 )
 
+// The reasons of the failures classified by their causes. The failures of the
+// QuotaExceeded and Timeout reasons are expected to be resolved by retrying
+// later, the ones of the AuthFailure and InvalidConfig reasons require the
+// spec or the credentials to be fixed.
+const (
+	// QuotaExceededReason indicates a failure caused by the exhausted quota
+	// of the management cluster or of the cloud account.
+	QuotaExceededReason string = "QuotaExceeded"
+
+	// AuthFailureReason indicates a failure caused by the rejected
+	// authentication or authorization, e.g. with the expired credentials.
+	AuthFailureReason string = "AuthFailure"
+
+	// TimeoutReason indicates a failure caused by the timed out request.
+	TimeoutReason string = "Timeout"
+
+	// InvalidConfigReason indicates a failure caused by the invalid
+	// configuration of the object or of the referenced objects.
+	InvalidConfigReason string = "InvalidConfig"
+)
+
 // ReadyCondition indicates a resource is ready and fully reconciled.
 const ReadyCondition string = "Ready"
 
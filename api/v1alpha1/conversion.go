@@ -0,0 +1,37 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// v1alpha1 is the storage version of the API and the hub the other versions
+// are converted to and from, see the conversion of the v1beta1 package.
+
+func (*AccessManagement) Hub()          {}
+func (*AuditEvent) Hub()                {}
+func (*ClusterDeployment) Hub()         {}
+func (*ClusterDeploymentRevision) Hub() {}
+func (*ClusterQuota) Hub()              {}
+func (*ClusterTemplate) Hub()           {}
+func (*ClusterTemplateChain) Hub()      {}
+func (*Credential) Hub()                {}
+func (*Diagnostics) Hub()               {}
+func (*Management) Hub()                {}
+func (*ManagementBackup) Hub()          {}
+func (*ManagementRestore) Hub()         {}
+func (*MultiClusterService) Hub()       {}
+func (*ProviderTemplate) Hub()          {}
+func (*Region) Hub()                    {}
+func (*Release) Hub()                   {}
+func (*ServiceTemplate) Hub()           {}
+func (*ServiceTemplateChain) Hub()      {}
@@ -26,6 +26,12 @@ const (
 	CredentialReadyCondition = "CredentialReady"
 	// CredentialPropagatedCondition indicates that CCM credentials were delivered to managed cluster
 	CredentialsPropagatedCondition = "CredentialsApplied"
+	// CredentialValidCondition indicates the credentials of the identity are not about to expire.
+	CredentialValidCondition = "CredentialValid"
+	// CredentialExpiringReason declares that the credentials of the identity are about to expire.
+	CredentialExpiringReason = "Expiring"
+	// CredentialExpiredReason declares that the credentials of the identity have expired.
+	CredentialExpiredReason = "Expired"
 )
 
 // CredentialSpec defines the desired state of Credential
@@ -34,6 +40,10 @@ type CredentialSpec struct {
 	IdentityRef *corev1.ObjectReference `json:"identityRef"`
 	// Description of the Credential object
 	Description string `json:"description,omitempty"` // WARN: noop
+	// ExpirationTime is the time the credentials of the identity expire at,
+	// e.g. of the temporary cloud access keys. The Credential is reported as
+	// expiring before it by the CredentialValid condition.
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
 }
 
 // CredentialStatus defines the observed state of Credential
@@ -46,6 +56,7 @@ type CredentialStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=cred
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.ready`
@@ -17,6 +17,7 @@ package v1alpha1
 import (
 	"context"
 	"errors"
+	"slices"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,6 +35,7 @@ func SetupIndexers(ctx context.Context, mgr ctrl.Manager) error {
 		setupServiceTemplateChainIndexer,
 		setupClusterTemplateProvidersIndexer,
 		setupMultiClusterServiceServicesIndexer,
+		setupMultiClusterServiceClustersIndexer,
 		setupOwnerReferenceIndexers,
 		setupManagementBackupIndexer,
 		setupManagementBackupAutoUpgradesIndexer,
@@ -214,6 +216,32 @@ func ExtractServiceTemplateNamesFromMultiClusterService(rawObj client.Object) []
 	return templates
 }
 
+// MultiClusterServiceClustersIndexKey indexer field name to extract the clusters the services of a MultiClusterService object are deployed to.
+const MultiClusterServiceClustersIndexKey = ".status.services[].cluster"
+
+func setupMultiClusterServiceClustersIndexer(ctx context.Context, mgr ctrl.Manager) error {
+	return mgr.GetFieldIndexer().IndexField(ctx, &MultiClusterService{}, MultiClusterServiceClustersIndexKey, ExtractClustersFromMultiClusterService)
+}
+
+// ExtractClustersFromMultiClusterService returns the clusters in the namespace/name
+// format the MultiClusterService object reports the state of its services on.
+func ExtractClustersFromMultiClusterService(rawObj client.Object) []string {
+	mcs, ok := rawObj.(*MultiClusterService)
+	if !ok {
+		return nil
+	}
+
+	clusters := make([]string, 0, len(mcs.Status.Services))
+	for _, s := range mcs.Status.Services {
+		cluster := client.ObjectKey{Namespace: s.ClusterNamespace, Name: s.ClusterName}.String()
+		if !slices.Contains(clusters, cluster) {
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	return clusters
+}
+
 // ownerref indexers
 
 // OwnerRefIndexKey indexer field name to extract ownerReference names from objects
@@ -22,11 +22,26 @@ import (
 )
 
 const (
+	// ManagementBackupKind is the string representation of a ManagementBackup.
+	ManagementBackupKind = "ManagementBackup"
+
 	// Name to label most of the KCM-related components.
 	// Mostly utilized by the backup feature.
 	GenericComponentNameLabel = "k0rdent.mirantis.com/component"
 	// Component label value for the KCM-related components.
 	GenericComponentLabelValueKCM = "kcm"
+
+	// ManagementBackupScheduleLabelKey is the label of the [ManagementBackup] objects
+	// created from the backup schedules of the [Management] holding its name.
+	ManagementBackupScheduleLabelKey = "k0rdent.mirantis.com/backup-schedule"
+
+	// LastBackupSucceededCondition indicates whether the most recently
+	// finished backup of the [ManagementBackup] has succeeded.
+	LastBackupSucceededCondition = "LastBackupSucceeded"
+	// BackupSucceededReason is the reason of the succeeded backup.
+	BackupSucceededReason = "BackupSucceeded"
+	// BackupFailedReason is the reason of the failed backup.
+	BackupFailedReason = "BackupFailed"
 )
 
 // ManagementBackupSpec defines the desired state of ManagementBackup
@@ -41,6 +56,30 @@ type ManagementBackupSpec struct {
 	// should be created and stored in the [ManagementBackup] storage location if not default
 	// before the [Management] release upgrade.
 	PerformOnManagementUpgrade bool `json:"performOnManagementUpgrade,omitempty"`
+	// Retention is the period the backups are kept for before they are
+	// garbage-collected by Velero. Defaults to 30 days.
+	Retention *metav1.Duration `json:"retention,omitempty"`
+	// Scope narrows the backup down to a part of the management state,
+	// the whole state is backed up if not set.
+	Scope *ManagementBackupScope `json:"scope,omitempty"`
+}
+
+// ManagementBackupScope defines the part of the management state to back up.
+type ManagementBackupScope struct {
+	// ClusterDeploymentSelector selects the [ClusterDeployment] objects the
+	// clusters of which are backed up, all of them if not set. The CAPI
+	// objects of the unselected clusters and the providers used only by
+	// them are not backed up, the [ClusterDeployment] objects themselves are
+	// backed up along with the rest of the kcm objects.
+	ClusterDeploymentSelector *metav1.LabelSelector `json:"clusterDeploymentSelector,omitempty"`
+	// IncludedNamespaces lists the namespaces to back up, all of them if empty.
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+	// ExcludedNamespaces lists the namespaces not to back up.
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+	// Providers lists the names of the CAPI providers, e.g. infrastructure-aws,
+	// the objects of which are backed up, all of the providers used by the
+	// backed up clusters if empty. The core CAPI provider is always backed up.
+	Providers []string `json:"providers,omitempty"`
 }
 
 // ManagementBackupStatus defines the observed state of ManagementBackup
@@ -54,8 +93,22 @@ type ManagementBackupStatus struct {
 	LastBackup *velerov1.BackupStatus `json:"lastBackup,omitempty"`
 	// Name of most recently created [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.Backup].
 	LastBackupName string `json:"lastBackupName,omitempty"`
+	// LastSuccessfulBackupTime is the completion time of the most recently
+	// succeeded [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.Backup].
+	LastSuccessfulBackupTime *metav1.Time `json:"lastSuccessfulBackupTime,omitempty"`
+	// LastSuccessfulBackupName is the name of the most recently succeeded
+	// [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.Backup].
+	LastSuccessfulBackupName string `json:"lastSuccessfulBackupName,omitempty"`
 	// Error stores messages in case of failed backup creation.
 	Error string `json:"error,omitempty"`
+	// Conditions contains details for the current state of the [ManagementBackup].
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// LastSuccessfulBackupItems is the number of the items backed up by the
+	// most recently succeeded [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.Backup].
+	LastSuccessfulBackupItems int32 `json:"lastSuccessfulBackupItems,omitempty"`
+	// ConsecutiveFailures is the number of the backups failed in a row
+	// since the last succeeded one.
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
 }
 
 // IsSchedule checks if an instance of [ManagementBackup] is schedulable.
@@ -74,11 +127,14 @@ func (s *ManagementBackup) TimestampedBackupName(timestamp time.Time) string {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,shortName=kcmbackup;mgmtbackup
 // +kubebuilder:printcolumn:name="LastBackupStatus",type=string,JSONPath=`.status.lastBackup.phase`,description="Status of last backup run",priority=0
 // +kubebuilder:printcolumn:name="NextBackup",type=string,JSONPath=`.status.nextAttempt`,description="Next scheduled attempt to back up",priority=0
 // +kubebuilder:printcolumn:name="SinceLastBackup",type=date,JSONPath=`.status.lastBackupTime`,description="Time elapsed since last backup run",priority=1
+// +kubebuilder:printcolumn:name="SinceLastSuccess",type=date,JSONPath=`.status.lastSuccessfulBackupTime`,description="Time elapsed since last succeeded backup",priority=1
+// +kubebuilder:printcolumn:name="Failures",type=integer,JSONPath=`.status.consecutiveFailures`,description="Number of backups failed in a row",priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Time elapsed since object creation",priority=0
 // +kubebuilder:printcolumn:name="Error",type=string,JSONPath=`.status.error`,description="Error during creation",priority=1
 
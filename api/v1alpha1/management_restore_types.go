@@ -0,0 +1,187 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ManagementRestoreKind is the string representation of a ManagementRestore.
+const ManagementRestoreKind = "ManagementRestore"
+
+// ManagementRestorePhase is the phase of the [ManagementRestore].
+type ManagementRestorePhase string
+
+const (
+	// ManagementRestorePhaseAnalyzing means the backup contents are being
+	// compared with the objects of the management cluster.
+	ManagementRestorePhaseAnalyzing ManagementRestorePhase = "Analyzing"
+	// ManagementRestorePhaseAnalyzed means the report has been built for the
+	// dry-run and nothing is going to be restored.
+	ManagementRestorePhaseAnalyzed ManagementRestorePhase = "Analyzed"
+	// ManagementRestorePhaseAwaitingConfirmation means the restore is going
+	// to overwrite the existing objects and waits for the confirmation.
+	ManagementRestorePhaseAwaitingConfirmation ManagementRestorePhase = "AwaitingConfirmation"
+	// ManagementRestorePhaseRestoring means the Velero Restore is in progress.
+	ManagementRestorePhaseRestoring ManagementRestorePhase = "Restoring"
+	// ManagementRestorePhaseCompleted means the Velero Restore has completed.
+	ManagementRestorePhaseCompleted ManagementRestorePhase = "Completed"
+	// ManagementRestorePhaseFailed means either the analysis or the Velero Restore has failed.
+	ManagementRestorePhaseFailed ManagementRestorePhase = "Failed"
+)
+
+// ManagementRestoreAction is the action the restore takes on an object of the backup.
+type ManagementRestoreAction string
+
+const (
+	// ManagementRestoreActionCreate means the object does not exist and is created.
+	ManagementRestoreActionCreate ManagementRestoreAction = "Create"
+	// ManagementRestoreActionOverwrite means the object exists, differs from
+	// the backed up one and is overwritten.
+	ManagementRestoreActionOverwrite ManagementRestoreAction = "Overwrite"
+	// ManagementRestoreActionConflict means the object exists, differs from
+	// the backed up one and is kept as is since the existing objects are not
+	// updated by the restore.
+	ManagementRestoreActionConflict ManagementRestoreAction = "Conflict"
+)
+
+// ManagementRestoreSpec defines the desired state of ManagementRestore
+// +kubebuilder:validation:XValidation:rule="has(self.backupName) != has(self.pointInTime)",message="exactly one of backupName or pointInTime must be set"
+type ManagementRestoreSpec struct {
+	// PointInTime restores the last backup of the [ManagementBackup]
+	// started by the given time.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="pointInTime is immutable"
+	PointInTime *ManagementRestorePointInTime `json:"pointInTime,omitempty"`
+	// ClusterDeployment narrows the restore down to the given [ClusterDeployment],
+	// its [Credential] with the identity and the objects of its cluster.
+	// The whole backup is restored if not set.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterDeployment is immutable"
+	ClusterDeployment *ManagementRestoreClusterDeployment `json:"clusterDeployment,omitempty"`
+	// BackupName is the name of the Velero Backup to restore, e.g. the
+	// last backup of a [ManagementBackup].
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="backupName is immutable"
+	BackupName string `json:"backupName,omitempty"`
+	// ExistingResourcePolicy defines whether the existing objects differing
+	// from the backed up ones are overwritten (update) or kept as is (none).
+	// +kubebuilder:default:=none
+	// +kubebuilder:validation:Enum=none;update
+	ExistingResourcePolicy velerov1.PolicyType `json:"existingResourcePolicy,omitempty"`
+	// DryRun only reports the changes the restore would make.
+	DryRun bool `json:"dryRun,omitempty"`
+	// ConfirmOverwrite confirms the restore overwriting the existing
+	// objects listed in the report, the restore is not started otherwise.
+	ConfirmOverwrite bool `json:"confirmOverwrite,omitempty"`
+}
+
+// ManagementRestorePointInTime defines the backup to restore by the time.
+type ManagementRestorePointInTime struct {
+	// Time is the time the state of the management cluster is restored to.
+	Time metav1.Time `json:"time"`
+	// ManagementBackup is the name of the [ManagementBackup] the backups of which are restored.
+	// +kubebuilder:validation:MinLength=1
+	ManagementBackup string `json:"managementBackup"`
+}
+
+// ManagementRestoreClusterDeployment references the restored [ClusterDeployment].
+type ManagementRestoreClusterDeployment struct {
+	// Namespace of the [ClusterDeployment].
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// Name of the [ClusterDeployment].
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// ManagementRestoreItem is an object of the backup the restore takes an
+// action other than creation on.
+type ManagementRestoreItem struct {
+	// APIVersion of the object.
+	APIVersion string `json:"apiVersion"`
+	// Kind of the object.
+	Kind string `json:"kind"`
+	// Namespace of the object, empty for the cluster-scoped objects.
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the object.
+	Name string `json:"name"`
+	// Action is the action the restore takes on the object.
+	Action ManagementRestoreAction `json:"action"`
+}
+
+// ManagementRestoreReport is the comparison of the backup contents with the
+// objects of the management cluster.
+type ManagementRestoreReport struct {
+	// GeneratedAt is the time the report has been built at.
+	GeneratedAt metav1.Time `json:"generatedAt"`
+	// Items lists the objects to be overwritten or in conflict.
+	Items []ManagementRestoreItem `json:"items,omitempty"`
+	// ToCreate is the number of the objects to be created.
+	ToCreate int32 `json:"toCreate"`
+	// ToOverwrite is the number of the existing objects to be overwritten.
+	ToOverwrite int32 `json:"toOverwrite"`
+	// Conflicts is the number of the existing objects differing from the
+	// backed up ones which are kept as is.
+	Conflicts int32 `json:"conflicts"`
+	// Unchanged is the number of the existing objects equal to the backed up ones.
+	Unchanged int32 `json:"unchanged"`
+}
+
+// ManagementRestoreStatus defines the observed state of ManagementRestore
+type ManagementRestoreStatus struct {
+	// Report is the report of the changes the restore makes.
+	Report *ManagementRestoreReport `json:"report,omitempty"`
+	// Phase is the current phase of the restore.
+	Phase ManagementRestorePhase `json:"phase,omitempty"`
+	// BackupName is the name of the Velero Backup being restored.
+	BackupName string `json:"backupName,omitempty"`
+	// RestoreName is the name of the Velero Restore created.
+	RestoreName string `json:"restoreName,omitempty"`
+	// Error is the error the restore has failed with.
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=kcmrestore
+// +kubebuilder:printcolumn:name="Backup",type=string,JSONPath=`.status.backupName`,description="Name of the restored backup"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="Phase of the restore"
+// +kubebuilder:printcolumn:name="Create",type=integer,JSONPath=`.status.report.toCreate`,description="Number of the objects to be created"
+// +kubebuilder:printcolumn:name="Overwrite",type=integer,JSONPath=`.status.report.toOverwrite`,description="Number of the objects to be overwritten"
+// +kubebuilder:printcolumn:name="Conflicts",type=integer,JSONPath=`.status.report.conflicts`,description="Number of the objects in conflict"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Time elapsed since object creation"
+
+// ManagementRestore is the Schema for the managementrestores API
+type ManagementRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ManagementRestoreSpec   `json:"spec,omitempty"`
+	Status ManagementRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ManagementRestoreList contains a list of ManagementRestore
+type ManagementRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ManagementRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ManagementRestore{}, &ManagementRestoreList{})
+}
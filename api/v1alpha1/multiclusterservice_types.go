@@ -136,6 +136,31 @@ type ServiceStatus struct {
 	ClusterNamespace string `json:"clusterNamespace,omitempty"`
 	// Conditions contains details for the current state of managed services.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// HelmReleases contains the state of the Helm releases of the services
+	// on the cluster. Set only for the services of the ClusterDeployments
+	// once the cluster is ready.
+	HelmReleases []ServiceHelmReleaseStatus `json:"helmReleases,omitempty"`
+}
+
+// ServiceHelmReleaseStatus is the state of the last revision of the Helm
+// release of the service on the cluster.
+type ServiceHelmReleaseStatus struct {
+	// LastDeployed is the time the last revision has been deployed at.
+	LastDeployed *metav1.Time `json:"lastDeployed,omitempty"`
+	// Name of the release.
+	Name string `json:"name"`
+	// Namespace of the release.
+	Namespace string `json:"namespace"`
+	// ChartVersion is the version of the chart of the last revision.
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// AppVersion is the version of the application of the chart of the last revision.
+	AppVersion string `json:"appVersion,omitempty"`
+	// Status of the last revision, e.g. deployed, failed or pending-upgrade.
+	Status string `json:"status,omitempty"`
+	// FailureMessage is the description of the failure of the last revision.
+	FailureMessage string `json:"failureMessage,omitempty"`
+	// Revision is the number of the last revision.
+	Revision int32 `json:"revision,omitempty"`
 }
 
 // MultiClusterServiceStatus defines the observed state of MultiClusterService.
@@ -149,6 +174,7 @@ type MultiClusterServiceStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,shortName=mcs
 // +kubebuilder:printcolumn:name="Services",type="string",JSONPath=`.status.conditions[?(@.type=="ServicesInReadyState")].message`,description="Number of ready out of total services",priority=0
@@ -164,6 +190,10 @@ type MultiClusterService struct {
 	Status MultiClusterServiceStatus `json:"status,omitempty"`
 }
 
+func (in *MultiClusterService) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
 // +kubebuilder:object:root=true
 
 // MultiClusterServiceList contains a list of MultiClusterService
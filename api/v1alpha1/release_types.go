@@ -15,6 +15,7 @@
 package v1alpha1
 
 import (
+	"github.com/Masterminds/semver/v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,8 +26,61 @@ const (
 	TemplatesCreatedCondition = "TemplatesCreated"
 	// TemplatesValidCondition indicates that all templates associated with the Release are valid.
 	TemplatesValidCondition = "TemplatesValid"
+
+	// ReleaseChangesAnnotation is the annotation of the KCM chart listing
+	// the changes of the Release as a YAML list of the ReleaseChange objects.
+	ReleaseChangesAnnotation = "k0rdent.mirantis.com/changes"
+	// ReleaseUpgradeStepsAnnotation is the annotation of the KCM chart listing
+	// the manual steps required by the upgrade to the Release as a YAML list of strings.
+	ReleaseUpgradeStepsAnnotation = "k0rdent.mirantis.com/upgrade-steps"
 )
 
+// ReleaseChangeKind is the kind of the change of the Release.
+type ReleaseChangeKind string
+
+const (
+	ReleaseChangeAdded      ReleaseChangeKind = "added"
+	ReleaseChangeChanged    ReleaseChangeKind = "changed"
+	ReleaseChangeDeprecated ReleaseChangeKind = "deprecated"
+	ReleaseChangeRemoved    ReleaseChangeKind = "removed"
+	ReleaseChangeFixed      ReleaseChangeKind = "fixed"
+	ReleaseChangeSecurity   ReleaseChangeKind = "security"
+)
+
+// ReleaseChannel is the channel a Release is published to.
+type ReleaseChannel string
+
+const (
+	// ReleaseChannelStable is the channel of the generally available Releases.
+	ReleaseChannelStable ReleaseChannel = "stable"
+	// ReleaseChannelRC is the channel of the release candidates.
+	ReleaseChannelRC ReleaseChannel = "rc"
+	// ReleaseChannelNightly is the channel of the nightly builds.
+	ReleaseChannelNightly ReleaseChannel = "nightly"
+)
+
+var releaseChannelStability = map[ReleaseChannel]int{
+	ReleaseChannelStable:  2,
+	ReleaseChannelRC:      1,
+	ReleaseChannelNightly: 0,
+}
+
+// Includes reports whether the Releases of the other channel are picked up
+// by the subscribers of the channel. The channel includes the Releases of
+// the channels at least as stable as itself, e.g. rc includes stable.
+// The Releases with no channel belong to the stable channel.
+func (c ReleaseChannel) Includes(other ReleaseChannel) bool {
+	if other == "" {
+		other = ReleaseChannelStable
+	}
+	stability, ok := releaseChannelStability[c]
+	if !ok {
+		return false
+	}
+	otherStability, ok := releaseChannelStability[other]
+	return ok && otherStability >= stability
+}
+
 // ReleaseSpec defines the desired state of Release
 type ReleaseSpec struct {
 	// Version of the KCM Release in the semver format.
@@ -35,6 +89,11 @@ type ReleaseSpec struct {
 	KCM CoreProviderTemplate `json:"kcm"`
 	// CAPI references the Cluster API template.
 	CAPI CoreProviderTemplate `json:"capi"`
+	// +kubebuilder:validation:Enum=stable;rc;nightly
+	// +kubebuilder:default:=stable
+
+	// Channel is the release channel the Release is published to.
+	Channel ReleaseChannel `json:"channel,omitempty"`
 	// Providers contains a list of Providers associated with the Release.
 	Providers []NamedProviderTemplate `json:"providers,omitempty"`
 }
@@ -68,8 +127,49 @@ func (in *Release) Templates() []string {
 	return templates
 }
 
+// IsDowngradeFrom reports whether the version of the Release is lower than
+// the version of the given one. The Releases with the versions not in the
+// semver format are never treated as downgrades.
+func (in *Release) IsDowngradeFrom(current *Release) bool {
+	version, err := semver.NewVersion(in.Spec.Version)
+	if err != nil {
+		return false
+	}
+	currentVersion, err := semver.NewVersion(current.Spec.Version)
+	if err != nil {
+		return false
+	}
+	return version.LessThan(currentVersion)
+}
+
+// ReleaseChange is the change introduced by the Release.
+type ReleaseChange struct {
+	// +kubebuilder:validation:Enum=added;changed;deprecated;removed;fixed;security
+
+	// Kind of the change.
+	Kind ReleaseChangeKind `json:"kind"`
+	// Description of the change.
+	Description string `json:"description"`
+	// Breaking indicates whether the change is not backward compatible.
+	Breaking bool `json:"breaking,omitempty"`
+}
+
+// ReleaseNotes describes the changes of the Release and its upgrade.
+type ReleaseNotes struct {
+	// ChartVersion is the version of the KCM chart the notes are fetched from.
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// Changes is the changelog of the Release.
+	Changes []ReleaseChange `json:"changes,omitempty"`
+	// UpgradeSteps lists the manual steps required by the upgrade to the Release.
+	UpgradeSteps []string `json:"upgradeSteps,omitempty"`
+	// Breaking indicates whether any of the changes is not backward compatible.
+	Breaking bool `json:"breaking,omitempty"`
+}
+
 // ReleaseStatus defines the observed state of Release
 type ReleaseStatus struct {
+	// Notes are the release notes fetched from the KCM chart of the Release.
+	Notes *ReleaseNotes `json:"notes,omitempty"`
 	// Conditions contains details for the current state of the Release
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// ObservedGeneration is the last observed generation.
@@ -79,6 +179,7 @@ type ReleaseStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
 
@@ -123,6 +123,11 @@ type ServiceTemplateStatus struct {
 	// SourceStatus reflects the status of the source.
 	SourceStatus *SourceStatus `json:"sourceStatus,omitempty"`
 
+	// Usage is the number of the ClusterDeployments and MultiClusterServices
+	// referencing the template, the template is safe to be removed once it is
+	// not referenced anymore.
+	Usage *TemplateUsage `json:"usage,omitempty"`
+
 	TemplateStatusCommon `json:",inline"`
 }
 
@@ -178,9 +183,12 @@ func (t *ServiceTemplate) GetCommonStatus() *TemplateStatusCommon {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=svctmpl
 // +kubebuilder:printcolumn:name="valid",type="boolean",JSONPath=".status.valid",description="Valid",priority=0
+// +kubebuilder:printcolumn:name="clusterDeployments",type="integer",JSONPath=".status.usage.clusterDeployments",description="Number of the ClusterDeployments referencing the template",priority=0
+// +kubebuilder:printcolumn:name="multiClusterServices",type="integer",JSONPath=".status.usage.multiClusterServices",description="Number of the MultiClusterServices referencing the template",priority=0
 // +kubebuilder:printcolumn:name="validationError",type="string",JSONPath=".status.validationError",description="Validation Error",priority=1
 // +kubebuilder:printcolumn:name="description",type="string",JSONPath=".status.description",description="Description",priority=1
 
@@ -33,6 +33,7 @@ func (t *ServiceTemplateChain) GetSpec() *TemplateChainSpec {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 
 // ServiceTemplateChain is the Schema for the servicetemplatechains API
 type ServiceTemplateChain struct {
@@ -91,6 +91,16 @@ type TemplateValidationStatus struct {
 	Valid bool `json:"valid"`
 }
 
+// TemplateUsage is the number of the objects referencing the template.
+type TemplateUsage struct {
+	// ClusterDeployments is the number of the ClusterDeployments referencing
+	// the template.
+	ClusterDeployments int32 `json:"clusterDeployments"`
+	// MultiClusterServices is the number of the MultiClusterServices
+	// referencing the template.
+	MultiClusterServices int32 `json:"multiClusterServices,omitempty"`
+}
+
 func getProvidersList(providers Providers, annotations map[string]string) Providers {
 	const multiProviderSeparator = ","
 
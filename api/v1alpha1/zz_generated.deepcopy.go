@@ -165,41 +165,40 @@ func (in *AccessRule) DeepCopy() *AccessRule {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AvailableUpgrade) DeepCopyInto(out *AvailableUpgrade) {
+func (in *AuditActor) DeepCopyInto(out *AuditActor) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvailableUpgrade.
-func (in *AvailableUpgrade) DeepCopy() *AvailableUpgrade {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditActor.
+func (in *AuditActor) DeepCopy() *AuditActor {
 	if in == nil {
 		return nil
 	}
-	out := new(AvailableUpgrade)
+	out := new(AuditActor)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterDeployment) DeepCopyInto(out *ClusterDeployment) {
+func (in *AuditEvent) DeepCopyInto(out *AuditEvent) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeployment.
-func (in *ClusterDeployment) DeepCopy() *ClusterDeployment {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditEvent.
+func (in *AuditEvent) DeepCopy() *AuditEvent {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterDeployment)
+	out := new(AuditEvent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterDeployment) DeepCopyObject() runtime.Object {
+func (in *AuditEvent) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -207,31 +206,31 @@ func (in *ClusterDeployment) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterDeploymentList) DeepCopyInto(out *ClusterDeploymentList) {
+func (in *AuditEventList) DeepCopyInto(out *AuditEventList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterDeployment, len(*in))
+		*out = make([]AuditEvent, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentList.
-func (in *ClusterDeploymentList) DeepCopy() *ClusterDeploymentList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditEventList.
+func (in *AuditEventList) DeepCopy() *AuditEventList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterDeploymentList)
+	out := new(AuditEventList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterDeploymentList) DeepCopyObject() runtime.Object {
+func (in *AuditEventList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -239,323 +238,281 @@ func (in *ClusterDeploymentList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterDeploymentSpec) DeepCopyInto(out *ClusterDeploymentSpec) {
+func (in *AuditEventSpec) DeepCopyInto(out *AuditEventSpec) {
 	*out = *in
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = new(apiextensionsv1.JSON)
-		(*in).DeepCopyInto(*out)
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	if in.Details != nil {
+		in, out := &in.Details, &out.Details
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	in.ServiceSpec.DeepCopyInto(&out.ServiceSpec)
+	out.Subject = in.Subject
+	out.Actor = in.Actor
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentSpec.
-func (in *ClusterDeploymentSpec) DeepCopy() *ClusterDeploymentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditEventSpec.
+func (in *AuditEventSpec) DeepCopy() *AuditEventSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterDeploymentSpec)
+	out := new(AuditEventSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterDeploymentStatus) DeepCopyInto(out *ClusterDeploymentStatus) {
+func (in *AuditSubject) DeepCopyInto(out *AuditSubject) {
 	*out = *in
-	if in.Services != nil {
-		in, out := &in.Services, &out.Services
-		*out = make([]ServiceStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.AvailableUpgrades != nil {
-		in, out := &in.AvailableUpgrades, &out.AvailableUpgrades
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentStatus.
-func (in *ClusterDeploymentStatus) DeepCopy() *ClusterDeploymentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSubject.
+func (in *AuditSubject) DeepCopy() *AuditSubject {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterDeploymentStatus)
+	out := new(AuditSubject)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplate) DeepCopyInto(out *ClusterTemplate) {
+func (in *AvailableUpgrade) DeepCopyInto(out *AvailableUpgrade) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplate.
-func (in *ClusterTemplate) DeepCopy() *ClusterTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvailableUpgrade.
+func (in *AvailableUpgrade) DeepCopy() *AvailableUpgrade {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplate)
+	out := new(AvailableUpgrade)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplate) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateChain) DeepCopyInto(out *ClusterTemplateChain) {
+func (in *BackupEncryption) DeepCopyInto(out *BackupEncryption) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.RepositoryPasswordSecretRef != nil {
+		in, out := &in.RepositoryPasswordSecretRef, &out.RepositoryPasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateChain.
-func (in *ClusterTemplateChain) DeepCopy() *ClusterTemplateChain {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupEncryption.
+func (in *BackupEncryption) DeepCopy() *BackupEncryption {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateChain)
+	out := new(BackupEncryption)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateChain) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateChainList) DeepCopyInto(out *ClusterTemplateChainList) {
+func (in *BackupStorage) DeepCopyInto(out *BackupStorage) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClusterTemplateChain, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateChainList.
-func (in *ClusterTemplateChainList) DeepCopy() *ClusterTemplateChainList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStorage.
+func (in *BackupStorage) DeepCopy() *BackupStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateChainList)
+	out := new(BackupStorage)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateChainList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
+func (in *CRDChange) DeepCopyInto(out *CRDChange) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClusterTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.AddedVersions != nil {
+		in, out := &in.AddedVersions, &out.AddedVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedVersions != nil {
+		in, out := &in.RemovedVersions, &out.RemovedVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateList.
-func (in *ClusterTemplateList) DeepCopy() *ClusterTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CRDChange.
+func (in *CRDChange) DeepCopy() *CRDChange {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateList)
+	out := new(CRDChange)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateSpec) DeepCopyInto(out *ClusterTemplateSpec) {
+func (in *CertificateRotationSpec) DeepCopyInto(out *CertificateRotationSpec) {
 	*out = *in
-	in.Helm.DeepCopyInto(&out.Helm)
-	if in.ProviderContracts != nil {
-		in, out := &in.ProviderContracts, &out.ProviderContracts
-		*out = make(CompatibilityContracts, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Providers != nil {
-		in, out := &in.Providers, &out.Providers
-		*out = make(Providers, len(*in))
-		copy(*out, *in)
+	if in.RotateBefore != nil {
+		in, out := &in.RotateBefore, &out.RotateBefore
+		*out = new(v1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSpec.
-func (in *ClusterTemplateSpec) DeepCopy() *ClusterTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRotationSpec.
+func (in *CertificateRotationSpec) DeepCopy() *CertificateRotationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateSpec)
+	out := new(CertificateRotationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateStatus) DeepCopyInto(out *ClusterTemplateStatus) {
+func (in *CertificatesStatus) DeepCopyInto(out *CertificatesStatus) {
 	*out = *in
-	if in.ProviderContracts != nil {
-		in, out := &in.ProviderContracts, &out.ProviderContracts
-		*out = make(CompatibilityContracts, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Providers != nil {
-		in, out := &in.Providers, &out.Providers
-		*out = make(Providers, len(*in))
-		copy(*out, *in)
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
 	}
-	in.TemplateStatusCommon.DeepCopyInto(&out.TemplateStatusCommon)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateStatus.
-func (in *ClusterTemplateStatus) DeepCopy() *ClusterTemplateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesStatus.
+func (in *CertificatesStatus) DeepCopy() *CertificatesStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateStatus)
+	out := new(CertificatesStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in CompatibilityContracts) DeepCopyInto(out *CompatibilityContracts) {
-	{
-		in := &in
-		*out = make(CompatibilityContracts, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+func (in *CloudQuota) DeepCopyInto(out *CloudQuota) {
+	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompatibilityContracts.
-func (in CompatibilityContracts) DeepCopy() CompatibilityContracts {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudQuota.
+func (in *CloudQuota) DeepCopy() *CloudQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(CompatibilityContracts)
+	out := new(CloudQuota)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Component) DeepCopyInto(out *Component) {
+func (in *CloudQuotaCollection) DeepCopyInto(out *CloudQuotaCollection) {
 	*out = *in
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = new(apiextensionsv1.JSON)
-		(*in).DeepCopyInto(*out)
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Component.
-func (in *Component) DeepCopy() *Component {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudQuotaCollection.
+func (in *CloudQuotaCollection) DeepCopy() *CloudQuotaCollection {
 	if in == nil {
 		return nil
 	}
-	out := new(Component)
+	out := new(CloudQuotaCollection)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+func (in *ClusterAuthentication) DeepCopyInto(out *ClusterAuthentication) {
 	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCAuthentication)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
-func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAuthentication.
+func (in *ClusterAuthentication) DeepCopy() *ClusterAuthentication {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentStatus)
+	out := new(ClusterAuthentication)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Core) DeepCopyInto(out *Core) {
+func (in *ClusterBackup) DeepCopyInto(out *ClusterBackup) {
 	*out = *in
-	in.KCM.DeepCopyInto(&out.KCM)
-	in.CAPI.DeepCopyInto(&out.CAPI)
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.IncludedNamespaces != nil {
+		in, out := &in.IncludedNamespaces, &out.IncludedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedNamespaces != nil {
+		in, out := &in.ExcludedNamespaces, &out.ExcludedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Core.
-func (in *Core) DeepCopy() *Core {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackup.
+func (in *ClusterBackup) DeepCopy() *ClusterBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(Core)
+	out := new(ClusterBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CoreProviderTemplate) DeepCopyInto(out *CoreProviderTemplate) {
+func (in *ClusterBackupStatus) DeepCopyInto(out *ClusterBackupStatus) {
 	*out = *in
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreProviderTemplate.
-func (in *CoreProviderTemplate) DeepCopy() *CoreProviderTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupStatus.
+func (in *ClusterBackupStatus) DeepCopy() *ClusterBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CoreProviderTemplate)
+	out := new(ClusterBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Credential) DeepCopyInto(out *Credential) {
+func (in *ClusterDeployment) DeepCopyInto(out *ClusterDeployment) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -563,18 +520,18 @@ func (in *Credential) DeepCopyInto(out *Credential) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Credential.
-func (in *Credential) DeepCopy() *Credential {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeployment.
+func (in *ClusterDeployment) DeepCopy() *ClusterDeployment {
 	if in == nil {
 		return nil
 	}
-	out := new(Credential)
+	out := new(ClusterDeployment)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Credential) DeepCopyObject() runtime.Object {
+func (in *ClusterDeployment) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -582,31 +539,31 @@ func (in *Credential) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CredentialList) DeepCopyInto(out *CredentialList) {
+func (in *ClusterDeploymentList) DeepCopyInto(out *ClusterDeploymentList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Credential, len(*in))
+		*out = make([]ClusterDeployment, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialList.
-func (in *CredentialList) DeepCopy() *CredentialList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentList.
+func (in *ClusterDeploymentList) DeepCopy() *ClusterDeploymentList {
 	if in == nil {
 		return nil
 	}
-	out := new(CredentialList)
+	out := new(ClusterDeploymentList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CredentialList) DeepCopyObject() runtime.Object {
+func (in *ClusterDeploymentList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -614,28 +571,201 @@ func (in *CredentialList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CredentialSpec) DeepCopyInto(out *CredentialSpec) {
+func (in *ClusterDeploymentRevision) DeepCopyInto(out *ClusterDeploymentRevision) {
 	*out = *in
-	if in.IdentityRef != nil {
-		in, out := &in.IdentityRef, &out.IdentityRef
-		*out = new(corev1.ObjectReference)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialSpec.
-func (in *CredentialSpec) DeepCopy() *CredentialSpec {
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentRevision.
+func (in *ClusterDeploymentRevision) DeepCopy() *ClusterDeploymentRevision {
 	if in == nil {
 		return nil
 	}
-	out := new(CredentialSpec)
+	out := new(ClusterDeploymentRevision)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterDeploymentRevision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CredentialStatus) DeepCopyInto(out *CredentialStatus) {
+func (in *ClusterDeploymentRevisionList) DeepCopyInto(out *ClusterDeploymentRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterDeploymentRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentRevisionList.
+func (in *ClusterDeploymentRevisionList) DeepCopy() *ClusterDeploymentRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeploymentRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterDeploymentRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeploymentRevisionSpec) DeepCopyInto(out *ClusterDeploymentRevisionSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentRevisionSpec.
+func (in *ClusterDeploymentRevisionSpec) DeepCopy() *ClusterDeploymentRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeploymentRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeploymentRevisionStatus) DeepCopyInto(out *ClusterDeploymentRevisionStatus) {
+	*out = *in
+	if in.AppliedTime != nil {
+		in, out := &in.AppliedTime, &out.AppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentRevisionStatus.
+func (in *ClusterDeploymentRevisionStatus) DeepCopy() *ClusterDeploymentRevisionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeploymentRevisionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeploymentSpec) DeepCopyInto(out *ClusterDeploymentSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ServiceSpec.DeepCopyInto(&out.ServiceSpec)
+	if in.Compliance != nil {
+		in, out := &in.Compliance, &out.Compliance
+		*out = new(ComplianceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateRotation != nil {
+		in, out := &in.CertificateRotation, &out.CertificateRotation
+		*out = new(CertificateRotationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(ClusterAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]ClusterBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EtcdBackup != nil {
+		in, out := &in.EtcdBackup, &out.EtcdBackup
+		*out = new(EtcdBackupSpec)
+		**out = **in
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentSpec.
+func (in *ClusterDeploymentSpec) DeepCopy() *ClusterDeploymentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeploymentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeploymentStatus) DeepCopyInto(out *ClusterDeploymentStatus) {
 	*out = *in
+	if in.Compliance != nil {
+		in, out := &in.Compliance, &out.Compliance
+		*out = new(ComplianceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(CertificatesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]ClusterBackupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EtcdBackup != nil {
+		in, out := &in.EtcdBackup, &out.EtcdBackup
+		*out = new(EtcdBackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastHeartbeat != nil {
+		in, out := &in.LastHeartbeat, &out.LastHeartbeat
+		*out = (*in).DeepCopy()
+	}
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(CostEstimate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]ServiceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -643,108 +773,161 @@ func (in *CredentialStatus) DeepCopyInto(out *CredentialStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AvailableUpgrades != nil {
+		in, out := &in.AvailableUpgrades, &out.AvailableUpgrades
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradeStartTime != nil {
+		in, out := &in.UpgradeStartTime, &out.UpgradeStartTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialStatus.
-func (in *CredentialStatus) DeepCopy() *CredentialStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentStatus.
+func (in *ClusterDeploymentStatus) DeepCopy() *ClusterDeploymentStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CredentialStatus)
+	out := new(ClusterDeploymentStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmbeddedBucketSpec) DeepCopyInto(out *EmbeddedBucketSpec) {
+func (in *ClusterQuota) DeepCopyInto(out *ClusterQuota) {
 	*out = *in
-	in.BucketSpec.DeepCopyInto(&out.BucketSpec)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedBucketSpec.
-func (in *EmbeddedBucketSpec) DeepCopy() *EmbeddedBucketSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuota.
+func (in *ClusterQuota) DeepCopy() *ClusterQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(EmbeddedBucketSpec)
+	out := new(ClusterQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmbeddedGitRepositorySpec) DeepCopyInto(out *EmbeddedGitRepositorySpec) {
+func (in *ClusterQuotaList) DeepCopyInto(out *ClusterQuotaList) {
 	*out = *in
-	in.GitRepositorySpec.DeepCopyInto(&out.GitRepositorySpec)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedGitRepositorySpec.
-func (in *EmbeddedGitRepositorySpec) DeepCopy() *EmbeddedGitRepositorySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuotaList.
+func (in *ClusterQuotaList) DeepCopy() *ClusterQuotaList {
 	if in == nil {
 		return nil
 	}
-	out := new(EmbeddedGitRepositorySpec)
+	out := new(ClusterQuotaList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmbeddedOCIRepositorySpec) DeepCopyInto(out *EmbeddedOCIRepositorySpec) {
+func (in *ClusterQuotaSpec) DeepCopyInto(out *ClusterQuotaSpec) {
 	*out = *in
-	in.OCIRepositorySpec.DeepCopyInto(&out.OCIRepositorySpec)
+	if in.MaxClusterDeployments != nil {
+		in, out := &in.MaxClusterDeployments, &out.MaxClusterDeployments
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxNodes != nil {
+		in, out := &in.MaxNodes, &out.MaxNodes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllowedInstanceTypes != nil {
+		in, out := &in.AllowedInstanceTypes, &out.AllowedInstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedOCIRepositorySpec.
-func (in *EmbeddedOCIRepositorySpec) DeepCopy() *EmbeddedOCIRepositorySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuotaSpec.
+func (in *ClusterQuotaSpec) DeepCopy() *ClusterQuotaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EmbeddedOCIRepositorySpec)
+	out := new(ClusterQuotaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HelmSpec) DeepCopyInto(out *HelmSpec) {
+func (in *ClusterQuotaStatus) DeepCopyInto(out *ClusterQuotaStatus) {
 	*out = *in
-	if in.ChartSpec != nil {
-		in, out := &in.ChartSpec, &out.ChartSpec
-		*out = new(apiv1.HelmChartSpec)
-		(*in).DeepCopyInto(*out)
+	out.Used = in.Used
+	if in.ExceededBy != nil {
+		in, out := &in.ExceededBy, &out.ExceededBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ChartRef != nil {
-		in, out := &in.ChartRef, &out.ChartRef
-		*out = new(v2.CrossNamespaceSourceReference)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmSpec.
-func (in *HelmSpec) DeepCopy() *HelmSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuotaStatus.
+func (in *ClusterQuotaStatus) DeepCopy() *ClusterQuotaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HelmSpec)
+	out := new(ClusterQuotaStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalSourceRef) DeepCopyInto(out *LocalSourceRef) {
+func (in *ClusterQuotaUsage) DeepCopyInto(out *ClusterQuotaUsage) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalSourceRef.
-func (in *LocalSourceRef) DeepCopy() *LocalSourceRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuotaUsage.
+func (in *ClusterQuotaUsage) DeepCopy() *ClusterQuotaUsage {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalSourceRef)
+	out := new(ClusterQuotaUsage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Management) DeepCopyInto(out *Management) {
+func (in *ClusterTemplate) DeepCopyInto(out *ClusterTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -752,18 +935,18 @@ func (in *Management) DeepCopyInto(out *Management) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Management.
-func (in *Management) DeepCopy() *Management {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplate.
+func (in *ClusterTemplate) DeepCopy() *ClusterTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(Management)
+	out := new(ClusterTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Management) DeepCopyObject() runtime.Object {
+func (in *ClusterTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -771,220 +954,1969 @@ func (in *Management) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagementBackup) DeepCopyInto(out *ManagementBackup) {
+func (in *ClusterTemplateChain) DeepCopyInto(out *ClusterTemplateChain) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateChain.
+func (in *ClusterTemplateChain) DeepCopy() *ClusterTemplateChain {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateChain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateChain) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateChainList) DeepCopyInto(out *ClusterTemplateChainList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateChain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateChainList.
+func (in *ClusterTemplateChainList) DeepCopy() *ClusterTemplateChainList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateChainList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateChainList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateList.
+func (in *ClusterTemplateList) DeepCopy() *ClusterTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateSpec) DeepCopyInto(out *ClusterTemplateSpec) {
+	*out = *in
+	in.Helm.DeepCopyInto(&out.Helm)
+	if in.ProviderContracts != nil {
+		in, out := &in.ProviderContracts, &out.ProviderContracts
+		*out = make(CompatibilityContracts, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make(Providers, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSpec.
+func (in *ClusterTemplateSpec) DeepCopy() *ClusterTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateStatus) DeepCopyInto(out *ClusterTemplateStatus) {
+	*out = *in
+	if in.ProviderContracts != nil {
+		in, out := &in.ProviderContracts, &out.ProviderContracts
+		*out = make(CompatibilityContracts, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make(Providers, len(*in))
+		copy(*out, *in)
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(TemplateUsage)
+		**out = **in
+	}
+	in.TemplateStatusCommon.DeepCopyInto(&out.TemplateStatusCommon)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateStatus.
+func (in *ClusterTemplateStatus) DeepCopy() *ClusterTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in CompatibilityContracts) DeepCopyInto(out *CompatibilityContracts) {
+	{
+		in := &in
+		*out = make(CompatibilityContracts, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompatibilityContracts.
+func (in CompatibilityContracts) DeepCopy() CompatibilityContracts {
+	if in == nil {
+		return nil
+	}
+	out := new(CompatibilityContracts)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceSpec) DeepCopyInto(out *ComplianceSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSpec.
+func (in *ComplianceSpec) DeepCopy() *ComplianceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceStatus) DeepCopyInto(out *ComplianceStatus) {
+	*out = *in
+	if in.LastScanTime != nil {
+		in, out := &in.LastScanTime, &out.LastScanTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailedChecks != nil {
+		in, out := &in.FailedChecks, &out.FailedChecks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceStatus.
+func (in *ComplianceStatus) DeepCopy() *ComplianceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Component) DeepCopyInto(out *Component) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ImageOverride, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Component.
+func (in *Component) DeepCopy() *Component {
+	if in == nil {
+		return nil
+	}
+	out := new(Component)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentChange) DeepCopyInto(out *ComponentChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentChange.
+func (in *ComponentChange) DeepCopy() *ComponentChange {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+	if in.ReadySince != nil {
+		in, out := &in.ReadySince, &out.ReadySince
+		*out = (*in).DeepCopy()
+	}
+	if in.LastErrorTime != nil {
+		in, out := &in.LastErrorTime, &out.LastErrorTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Concurrency) DeepCopyInto(out *Concurrency) {
+	*out = *in
+	if in.ClusterDeployment != nil {
+		in, out := &in.ClusterDeployment, &out.ClusterDeployment
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MultiClusterService != nil {
+		in, out := &in.MultiClusterService, &out.MultiClusterService
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Concurrency.
+func (in *Concurrency) DeepCopy() *Concurrency {
+	if in == nil {
+		return nil
+	}
+	out := new(Concurrency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Core) DeepCopyInto(out *Core) {
+	*out = *in
+	in.KCM.DeepCopyInto(&out.KCM)
+	in.CAPI.DeepCopyInto(&out.CAPI)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Core.
+func (in *Core) DeepCopy() *Core {
+	if in == nil {
+		return nil
+	}
+	out := new(Core)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoreProviderTemplate) DeepCopyInto(out *CoreProviderTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreProviderTemplate.
+func (in *CoreProviderTemplate) DeepCopy() *CoreProviderTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(CoreProviderTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimate) DeepCopyInto(out *CostEstimate) {
+	*out = *in
+	if in.UnpricedInstanceTypes != nil {
+		in, out := &in.UnpricedInstanceTypes, &out.UnpricedInstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimate.
+func (in *CostEstimate) DeepCopy() *CostEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimation) DeepCopyInto(out *CostEstimation) {
+	*out = *in
+	if in.PriceLists != nil {
+		in, out := &in.PriceLists, &out.PriceLists
+		*out = make([]PriceList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimation.
+func (in *CostEstimation) DeepCopy() *CostEstimation {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Credential) DeepCopyInto(out *Credential) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Credential.
+func (in *Credential) DeepCopy() *Credential {
+	if in == nil {
+		return nil
+	}
+	out := new(Credential)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Credential) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialCloudQuotas) DeepCopyInto(out *CredentialCloudQuotas) {
+	*out = *in
+	in.LastCollectionTime.DeepCopyInto(&out.LastCollectionTime)
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = make([]CloudQuota, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialCloudQuotas.
+func (in *CredentialCloudQuotas) DeepCopy() *CredentialCloudQuotas {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialCloudQuotas)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialList) DeepCopyInto(out *CredentialList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Credential, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialList.
+func (in *CredentialList) DeepCopy() *CredentialList {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CredentialList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialSpec) DeepCopyInto(out *CredentialSpec) {
+	*out = *in
+	if in.IdentityRef != nil {
+		in, out := &in.IdentityRef, &out.IdentityRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialSpec.
+func (in *CredentialSpec) DeepCopy() *CredentialSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialStatus) DeepCopyInto(out *CredentialStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialStatus.
+func (in *CredentialStatus) DeepCopy() *CredentialStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Debug) DeepCopyInto(out *Debug) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Debug.
+func (in *Debug) DeepCopy() *Debug {
+	if in == nil {
+		return nil
+	}
+	out := new(Debug)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticFinding) DeepCopyInto(out *DiagnosticFinding) {
+	*out = *in
+	if in.Object != nil {
+		in, out := &in.Object, &out.Object
+		*out = new(DiagnosticObject)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticFinding.
+func (in *DiagnosticFinding) DeepCopy() *DiagnosticFinding {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticFinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticObject) DeepCopyInto(out *DiagnosticObject) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticObject.
+func (in *DiagnosticObject) DeepCopy() *DiagnosticObject {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Diagnostics) DeepCopyInto(out *Diagnostics) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Diagnostics.
+func (in *Diagnostics) DeepCopy() *Diagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(Diagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Diagnostics) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticsList) DeepCopyInto(out *DiagnosticsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Diagnostics, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticsList.
+func (in *DiagnosticsList) DeepCopy() *DiagnosticsList {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DiagnosticsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticsSpec) DeepCopyInto(out *DiagnosticsSpec) {
+	*out = *in
+	if in.StuckFinalizerThreshold != nil {
+		in, out := &in.StuckFinalizerThreshold, &out.StuckFinalizerThreshold
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]DiagnosticCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticsSpec.
+func (in *DiagnosticsSpec) DeepCopy() *DiagnosticsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticsStatus) DeepCopyInto(out *DiagnosticsStatus) {
+	*out = *in
+	if in.LastRunTime != nil {
+		in, out := &in.LastRunTime, &out.LastRunTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Findings != nil {
+		in, out := &in.Findings, &out.Findings
+		*out = make([]DiagnosticFinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticsStatus.
+func (in *DiagnosticsStatus) DeepCopy() *DiagnosticsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailReceiver) DeepCopyInto(out *EmailReceiver) {
+	*out = *in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailReceiver.
+func (in *EmailReceiver) DeepCopy() *EmailReceiver {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailReceiver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmbeddedBucketSpec) DeepCopyInto(out *EmbeddedBucketSpec) {
+	*out = *in
+	in.BucketSpec.DeepCopyInto(&out.BucketSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedBucketSpec.
+func (in *EmbeddedBucketSpec) DeepCopy() *EmbeddedBucketSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EmbeddedBucketSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmbeddedGitRepositorySpec) DeepCopyInto(out *EmbeddedGitRepositorySpec) {
+	*out = *in
+	in.GitRepositorySpec.DeepCopyInto(&out.GitRepositorySpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedGitRepositorySpec.
+func (in *EmbeddedGitRepositorySpec) DeepCopy() *EmbeddedGitRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EmbeddedGitRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmbeddedOCIRepositorySpec) DeepCopyInto(out *EmbeddedOCIRepositorySpec) {
+	*out = *in
+	in.OCIRepositorySpec.DeepCopyInto(&out.OCIRepositorySpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedOCIRepositorySpec.
+func (in *EmbeddedOCIRepositorySpec) DeepCopy() *EmbeddedOCIRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EmbeddedOCIRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupSpec) DeepCopyInto(out *EtcdBackupSpec) {
+	*out = *in
+	out.Storage = in.Storage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupSpec.
+func (in *EtcdBackupSpec) DeepCopy() *EtcdBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupStatus) DeepCopyInto(out *EtcdBackupStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupStatus.
+func (in *EtcdBackupStatus) DeepCopy() *EtcdBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupStorage) DeepCopyInto(out *EtcdBackupStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupStorage.
+func (in *EtcdBackupStorage) DeepCopy() *EtcdBackupStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetSummary) DeepCopyInto(out *FleetSummary) {
+	*out = *in
+	if in.ClustersByProvider != nil {
+		in, out := &in.ClustersByProvider, &out.ClustersByProvider
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ClustersByTemplate != nil {
+		in, out := &in.ClustersByTemplate, &out.ClustersByTemplate
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ClustersByPhase != nil {
+		in, out := &in.ClustersByPhase, &out.ClustersByPhase
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetSummary.
+func (in *FleetSummary) DeepCopy() *FleetSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalValues) DeepCopyInto(out *GlobalValues) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalValues.
+func (in *GlobalValues) DeepCopy() *GlobalValues {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalValues)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmSpec) DeepCopyInto(out *HelmSpec) {
+	*out = *in
+	if in.ChartSpec != nil {
+		in, out := &in.ChartSpec, &out.ChartSpec
+		*out = new(apiv1.HelmChartSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ChartRef != nil {
+		in, out := &in.ChartRef, &out.ChartRef
+		*out = new(v2.CrossNamespaceSourceReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmSpec.
+func (in *HelmSpec) DeepCopy() *HelmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HighAvailability) DeepCopyInto(out *HighAvailability) {
+	*out = *in
+	if in.LeaderElection != nil {
+		in, out := &in.LeaderElection, &out.LeaderElection
+		*out = new(LeaderElection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WebhookMinAvailable != nil {
+		in, out := &in.WebhookMinAvailable, &out.WebhookMinAvailable
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HighAvailability.
+func (in *HighAvailability) DeepCopy() *HighAvailability {
+	if in == nil {
+		return nil
+	}
+	out := new(HighAvailability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageOverride) DeepCopyInto(out *ImageOverride) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageOverride.
+func (in *ImageOverride) DeepCopy() *ImageOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVerification) DeepCopyInto(out *ImageVerification) {
+	*out = *in
+	if in.IgnoredImages != nil {
+		in, out := &in.IgnoredImages, &out.IgnoredImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerification.
+func (in *ImageVerification) DeepCopy() *ImageVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Intervals) DeepCopyInto(out *Intervals) {
+	*out = *in
+	if in.Resync != nil {
+		in, out := &in.Resync, &out.Resync
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ClusterDeploymentRequeue != nil {
+		in, out := &in.ClusterDeploymentRequeue, &out.ClusterDeploymentRequeue
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ManagementRequeue != nil {
+		in, out := &in.ManagementRequeue, &out.ManagementRequeue
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TemplateRequeue != nil {
+		in, out := &in.TemplateRequeue, &out.TemplateRequeue
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Intervals.
+func (in *Intervals) DeepCopy() *Intervals {
+	if in == nil {
+		return nil
+	}
+	out := new(Intervals)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElection) DeepCopyInto(out *LeaderElection) {
+	*out = *in
+	if in.LeaseDuration != nil {
+		in, out := &in.LeaseDuration, &out.LeaseDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RenewDeadline != nil {
+		in, out := &in.RenewDeadline, &out.RenewDeadline
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RetryPeriod != nil {
+		in, out := &in.RetryPeriod, &out.RetryPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElection.
+func (in *LeaderElection) DeepCopy() *LeaderElection {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalSourceRef) DeepCopyInto(out *LocalSourceRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalSourceRef.
+func (in *LocalSourceRef) DeepCopy() *LocalSourceRef {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalSourceRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Management) DeepCopyInto(out *Management) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Management.
+func (in *Management) DeepCopy() *Management {
+	if in == nil {
+		return nil
+	}
+	out := new(Management)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Management) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementBackup) DeepCopyInto(out *ManagementBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementBackup.
+func (in *ManagementBackup) DeepCopy() *ManagementBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ManagementBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementBackupList) DeepCopyInto(out *ManagementBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ManagementBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementBackupList.
+func (in *ManagementBackupList) DeepCopy() *ManagementBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ManagementBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementBackupSchedule) DeepCopyInto(out *ManagementBackupSchedule) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementBackupSchedule.
+func (in *ManagementBackupSchedule) DeepCopy() *ManagementBackupSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementBackupSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementBackupScope) DeepCopyInto(out *ManagementBackupScope) {
+	*out = *in
+	if in.ClusterDeploymentSelector != nil {
+		in, out := &in.ClusterDeploymentSelector, &out.ClusterDeploymentSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IncludedNamespaces != nil {
+		in, out := &in.IncludedNamespaces, &out.IncludedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedNamespaces != nil {
+		in, out := &in.ExcludedNamespaces, &out.ExcludedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementBackupScope.
+func (in *ManagementBackupScope) DeepCopy() *ManagementBackupScope {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementBackupScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementBackupSpec) DeepCopyInto(out *ManagementBackupSpec) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = new(ManagementBackupScope)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementBackupSpec.
+func (in *ManagementBackupSpec) DeepCopy() *ManagementBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementBackupStatus) DeepCopyInto(out *ManagementBackupStatus) {
+	*out = *in
+	if in.NextAttempt != nil {
+		in, out := &in.NextAttempt, &out.NextAttempt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastBackup != nil {
+		in, out := &in.LastBackup, &out.LastBackup
+		*out = new(velerov1.BackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastSuccessfulBackupTime != nil {
+		in, out := &in.LastSuccessfulBackupTime, &out.LastSuccessfulBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementBackupStatus.
+func (in *ManagementBackupStatus) DeepCopy() *ManagementBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementList) DeepCopyInto(out *ManagementList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Management, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementList.
+func (in *ManagementList) DeepCopy() *ManagementList {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ManagementList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementRestore) DeepCopyInto(out *ManagementRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementRestore.
+func (in *ManagementRestore) DeepCopy() *ManagementRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ManagementRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementRestoreClusterDeployment) DeepCopyInto(out *ManagementRestoreClusterDeployment) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementRestoreClusterDeployment.
+func (in *ManagementRestoreClusterDeployment) DeepCopy() *ManagementRestoreClusterDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementRestoreClusterDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementRestoreItem) DeepCopyInto(out *ManagementRestoreItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementRestoreItem.
+func (in *ManagementRestoreItem) DeepCopy() *ManagementRestoreItem {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementRestoreItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementRestoreList) DeepCopyInto(out *ManagementRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ManagementRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementRestoreList.
+func (in *ManagementRestoreList) DeepCopy() *ManagementRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ManagementRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementRestorePointInTime) DeepCopyInto(out *ManagementRestorePointInTime) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementRestorePointInTime.
+func (in *ManagementRestorePointInTime) DeepCopy() *ManagementRestorePointInTime {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementRestorePointInTime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementRestoreReport) DeepCopyInto(out *ManagementRestoreReport) {
+	*out = *in
+	in.GeneratedAt.DeepCopyInto(&out.GeneratedAt)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ManagementRestoreItem, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementRestoreReport.
+func (in *ManagementRestoreReport) DeepCopy() *ManagementRestoreReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementRestoreReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementRestoreSpec) DeepCopyInto(out *ManagementRestoreSpec) {
+	*out = *in
+	if in.PointInTime != nil {
+		in, out := &in.PointInTime, &out.PointInTime
+		*out = new(ManagementRestorePointInTime)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterDeployment != nil {
+		in, out := &in.ClusterDeployment, &out.ClusterDeployment
+		*out = new(ManagementRestoreClusterDeployment)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementRestoreSpec.
+func (in *ManagementRestoreSpec) DeepCopy() *ManagementRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementRestoreStatus) DeepCopyInto(out *ManagementRestoreStatus) {
+	*out = *in
+	if in.Report != nil {
+		in, out := &in.Report, &out.Report
+		*out = new(ManagementRestoreReport)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementRestoreStatus.
+func (in *ManagementRestoreStatus) DeepCopy() *ManagementRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementSpec) DeepCopyInto(out *ManagementSpec) {
+	*out = *in
+	if in.ReleaseChannel != nil {
+		in, out := &in.ReleaseChannel, &out.ReleaseChannel
+		*out = new(ReleaseChannelSubscription)
+		**out = **in
+	}
+	if in.Core != nil {
+		in, out := &in.Core, &out.Core
+		*out = new(Core)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]Provider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DisabledComponents != nil {
+		in, out := &in.DisabledComponents, &out.DisabledComponents
+		*out = make([]OptionalComponent, len(*in))
+		copy(*out, *in)
+	}
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]ManagementBackupSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BackupEncryption != nil {
+		in, out := &in.BackupEncryption, &out.BackupEncryption
+		*out = new(BackupEncryption)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupStorage != nil {
+		in, out := &in.BackupStorage, &out.BackupStorage
+		*out = new(BackupStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(StandbyManagement)
+		**out = **in
+	}
+	if in.HighAvailability != nil {
+		in, out := &in.HighAvailability, &out.HighAvailability
+		*out = new(HighAvailability)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryMirror != nil {
+		in, out := &in.RegistryMirror, &out.RegistryMirror
+		*out = new(RegistryMirror)
+		**out = **in
+	}
+	if in.Global != nil {
+		in, out := &in.Global, &out.Global
+		*out = new(GlobalValues)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Telemetry != nil {
+		in, out := &in.Telemetry, &out.Telemetry
+		*out = new(Telemetry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(ImageVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = new(NetworkPolicies)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloudQuotaCollection != nil {
+		in, out := &in.CloudQuotaCollection, &out.CloudQuotaCollection
+		*out = new(CloudQuotaCollection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(Tracing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(Notifications)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CostEstimation != nil {
+		in, out := &in.CostEstimation, &out.CostEstimation
+		*out = new(CostEstimation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Observability != nil {
+		in, out := &in.Observability, &out.Observability
+		*out = new(Observability)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Debug != nil {
+		in, out := &in.Debug, &out.Debug
+		*out = new(Debug)
+		**out = **in
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(Concurrency)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Intervals != nil {
+		in, out := &in.Intervals, &out.Intervals
+		*out = new(Intervals)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementSpec.
+func (in *ManagementSpec) DeepCopy() *ManagementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementStatus) DeepCopyInto(out *ManagementStatus) {
+	*out = *in
+	if in.CAPIContracts != nil {
+		in, out := &in.CAPIContracts, &out.CAPIContracts
+		*out = make(map[string]CompatibilityContracts, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(CompatibilityContracts, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make(map[string]ComponentStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpgradeReport != nil {
+		in, out := &in.UpgradeReport, &out.UpgradeReport
+		*out = new(UpgradeReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloudQuotas != nil {
+		in, out := &in.CloudQuotas, &out.CloudQuotas
+		*out = make([]CredentialCloudQuotas, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Fleet != nil {
+		in, out := &in.Fleet, &out.Fleet
+		*out = new(FleetSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(StandbyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AvailableProviders != nil {
+		in, out := &in.AvailableProviders, &out.AvailableProviders
+		*out = make(Providers, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementStatus.
+func (in *ManagementStatus) DeepCopy() *ManagementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiClusterService) DeepCopyInto(out *MultiClusterService) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterService.
+func (in *MultiClusterService) DeepCopy() *MultiClusterService {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiClusterService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MultiClusterService) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiClusterServiceList) DeepCopyInto(out *MultiClusterServiceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MultiClusterService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterServiceList.
+func (in *MultiClusterServiceList) DeepCopy() *MultiClusterServiceList {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiClusterServiceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MultiClusterServiceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiClusterServiceSpec) DeepCopyInto(out *MultiClusterServiceSpec) {
+	*out = *in
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+	in.ServiceSpec.DeepCopyInto(&out.ServiceSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterServiceSpec.
+func (in *MultiClusterServiceSpec) DeepCopy() *MultiClusterServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiClusterServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiClusterServiceStatus) DeepCopyInto(out *MultiClusterServiceStatus) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]ServiceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterServiceStatus.
+func (in *MultiClusterServiceStatus) DeepCopy() *MultiClusterServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiClusterServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamedProviderTemplate) DeepCopyInto(out *NamedProviderTemplate) {
+	*out = *in
+	out.CoreProviderTemplate = in.CoreProviderTemplate
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamedProviderTemplate.
+func (in *NamedProviderTemplate) DeepCopy() *NamedProviderTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NamedProviderTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicies) DeepCopyInto(out *NetworkPolicies) {
+	*out = *in
+	if in.IngressNamespaceSelector != nil {
+		in, out := &in.IngressNamespaceSelector, &out.IngressNamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WebhookPorts != nil {
+		in, out := &in.WebhookPorts, &out.WebhookPorts
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicies.
+func (in *NetworkPolicies) DeepCopy() *NetworkPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationReceiver) DeepCopyInto(out *NotificationReceiver) {
+	*out = *in
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(SlackReceiver)
+		**out = **in
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookReceiver)
+		**out = **in
+	}
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(EmailReceiver)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]NotificationEvent, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementBackup.
-func (in *ManagementBackup) DeepCopy() *ManagementBackup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationReceiver.
+func (in *NotificationReceiver) DeepCopy() *NotificationReceiver {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagementBackup)
+	out := new(NotificationReceiver)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ManagementBackup) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagementBackupList) DeepCopyInto(out *ManagementBackupList) {
+func (in *Notifications) DeepCopyInto(out *Notifications) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ManagementBackup, len(*in))
+	if in.CredentialExpiryThreshold != nil {
+		in, out := &in.CredentialExpiryThreshold, &out.CredentialExpiryThreshold
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Receivers != nil {
+		in, out := &in.Receivers, &out.Receivers
+		*out = make([]NotificationReceiver, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementBackupList.
-func (in *ManagementBackupList) DeepCopy() *ManagementBackupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notifications.
+func (in *Notifications) DeepCopy() *Notifications {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagementBackupList)
+	out := new(Notifications)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ManagementBackupList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagementBackupSpec) DeepCopyInto(out *ManagementBackupSpec) {
+func (in *OIDCAuthentication) DeepCopyInto(out *OIDCAuthentication) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementBackupSpec.
-func (in *ManagementBackupSpec) DeepCopy() *ManagementBackupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCAuthentication.
+func (in *OIDCAuthentication) DeepCopy() *OIDCAuthentication {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagementBackupSpec)
+	out := new(OIDCAuthentication)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagementBackupStatus) DeepCopyInto(out *ManagementBackupStatus) {
+func (in *OTLPExporter) DeepCopyInto(out *OTLPExporter) {
 	*out = *in
-	if in.NextAttempt != nil {
-		in, out := &in.NextAttempt, &out.NextAttempt
-		*out = (*in).DeepCopy()
-	}
-	if in.LastBackupTime != nil {
-		in, out := &in.LastBackupTime, &out.LastBackupTime
-		*out = (*in).DeepCopy()
-	}
-	if in.LastBackup != nil {
-		in, out := &in.LastBackup, &out.LastBackup
-		*out = new(velerov1.BackupStatus)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementBackupStatus.
-func (in *ManagementBackupStatus) DeepCopy() *ManagementBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OTLPExporter.
+func (in *OTLPExporter) DeepCopy() *OTLPExporter {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagementBackupStatus)
+	out := new(OTLPExporter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagementList) DeepCopyInto(out *ManagementList) {
+func (in *Observability) DeepCopyInto(out *Observability) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Management, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+	out.Backend = in.Backend
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementList.
-func (in *ManagementList) DeepCopy() *ManagementList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observability.
+func (in *Observability) DeepCopy() *Observability {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagementList)
+	out := new(Observability)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ManagementList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagementSpec) DeepCopyInto(out *ManagementSpec) {
+func (in *ObservabilityBackend) DeepCopyInto(out *ObservabilityBackend) {
 	*out = *in
-	if in.Core != nil {
-		in, out := &in.Core, &out.Core
-		*out = new(Core)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Providers != nil {
-		in, out := &in.Providers, &out.Providers
-		*out = make([]Provider, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementSpec.
-func (in *ManagementSpec) DeepCopy() *ManagementSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityBackend.
+func (in *ObservabilityBackend) DeepCopy() *ObservabilityBackend {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagementSpec)
+	out := new(ObservabilityBackend)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagementStatus) DeepCopyInto(out *ManagementStatus) {
+func (in *PriceList) DeepCopyInto(out *PriceList) {
 	*out = *in
-	if in.CAPIContracts != nil {
-		in, out := &in.CAPIContracts, &out.CAPIContracts
-		*out = make(map[string]CompatibilityContracts, len(*in))
-		for key, val := range *in {
-			var outVal map[string]string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = make(CompatibilityContracts, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val
-				}
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.Components != nil {
-		in, out := &in.Components, &out.Components
-		*out = make(map[string]ComponentStatus, len(*in))
+	if in.HourlyPrices != nil {
+		in, out := &in.HourlyPrices, &out.HourlyPrices
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriceList.
+func (in *PriceList) DeepCopy() *PriceList {
+	if in == nil {
+		return nil
 	}
-	if in.AvailableProviders != nil {
-		in, out := &in.AvailableProviders, &out.AvailableProviders
-		*out = make(Providers, len(*in))
+	out := new(PriceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Provider) DeepCopyInto(out *Provider) {
+	*out = *in
+	in.Component.DeepCopyInto(&out.Component)
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementStatus.
-func (in *ManagementStatus) DeepCopy() *ManagementStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Provider.
+func (in *Provider) DeepCopy() *Provider {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagementStatus)
+	out := new(Provider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MultiClusterService) DeepCopyInto(out *MultiClusterService) {
+func (in *ProviderTemplate) DeepCopyInto(out *ProviderTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -992,18 +2924,18 @@ func (in *MultiClusterService) DeepCopyInto(out *MultiClusterService) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterService.
-func (in *MultiClusterService) DeepCopy() *MultiClusterService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderTemplate.
+func (in *ProviderTemplate) DeepCopy() *ProviderTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(MultiClusterService)
+	out := new(ProviderTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MultiClusterService) DeepCopyObject() runtime.Object {
+func (in *ProviderTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1011,31 +2943,31 @@ func (in *MultiClusterService) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MultiClusterServiceList) DeepCopyInto(out *MultiClusterServiceList) {
+func (in *ProviderTemplateList) DeepCopyInto(out *ProviderTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MultiClusterService, len(*in))
+		*out = make([]ProviderTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterServiceList.
-func (in *MultiClusterServiceList) DeepCopy() *MultiClusterServiceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderTemplateList.
+func (in *ProviderTemplateList) DeepCopy() *ProviderTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(MultiClusterServiceList)
+	out := new(ProviderTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MultiClusterServiceList) DeepCopyObject() runtime.Object {
+func (in *ProviderTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1043,136 +2975,148 @@ func (in *MultiClusterServiceList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MultiClusterServiceSpec) DeepCopyInto(out *MultiClusterServiceSpec) {
+func (in *ProviderTemplateSpec) DeepCopyInto(out *ProviderTemplateSpec) {
 	*out = *in
-	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
-	in.ServiceSpec.DeepCopyInto(&out.ServiceSpec)
+	in.Helm.DeepCopyInto(&out.Helm)
+	if in.CAPIContracts != nil {
+		in, out := &in.CAPIContracts, &out.CAPIContracts
+		*out = make(CompatibilityContracts, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make(Providers, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterServiceSpec.
-func (in *MultiClusterServiceSpec) DeepCopy() *MultiClusterServiceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderTemplateSpec.
+func (in *ProviderTemplateSpec) DeepCopy() *ProviderTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MultiClusterServiceSpec)
+	out := new(ProviderTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MultiClusterServiceStatus) DeepCopyInto(out *MultiClusterServiceStatus) {
+func (in *ProviderTemplateStatus) DeepCopyInto(out *ProviderTemplateStatus) {
 	*out = *in
-	if in.Services != nil {
-		in, out := &in.Services, &out.Services
-		*out = make([]ServiceStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.CAPIContracts != nil {
+		in, out := &in.CAPIContracts, &out.CAPIContracts
+		*out = make(CompatibilityContracts, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make(Providers, len(*in))
+		copy(*out, *in)
 	}
+	in.TemplateStatusCommon.DeepCopyInto(&out.TemplateStatusCommon)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterServiceStatus.
-func (in *MultiClusterServiceStatus) DeepCopy() *MultiClusterServiceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderTemplateStatus.
+func (in *ProviderTemplateStatus) DeepCopy() *ProviderTemplateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MultiClusterServiceStatus)
+	out := new(ProviderTemplateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamedProviderTemplate) DeepCopyInto(out *NamedProviderTemplate) {
-	*out = *in
-	out.CoreProviderTemplate = in.CoreProviderTemplate
+func (in Providers) DeepCopyInto(out *Providers) {
+	{
+		in := &in
+		*out = make(Providers, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamedProviderTemplate.
-func (in *NamedProviderTemplate) DeepCopy() *NamedProviderTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Providers.
+func (in Providers) DeepCopy() Providers {
 	if in == nil {
 		return nil
 	}
-	out := new(NamedProviderTemplate)
+	out := new(Providers)
 	in.DeepCopyInto(out)
-	return out
+	return *out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Provider) DeepCopyInto(out *Provider) {
+func (in *Region) DeepCopyInto(out *Region) {
 	*out = *in
-	in.Component.DeepCopyInto(&out.Component)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Provider.
-func (in *Provider) DeepCopy() *Provider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Region.
+func (in *Region) DeepCopy() *Region {
 	if in == nil {
 		return nil
 	}
-	out := new(Provider)
+	out := new(Region)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Region) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProviderTemplate) DeepCopyInto(out *ProviderTemplate) {
+func (in *RegionClusterDeployment) DeepCopyInto(out *RegionClusterDeployment) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderTemplate.
-func (in *ProviderTemplate) DeepCopy() *ProviderTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionClusterDeployment.
+func (in *RegionClusterDeployment) DeepCopy() *RegionClusterDeployment {
 	if in == nil {
 		return nil
 	}
-	out := new(ProviderTemplate)
+	out := new(RegionClusterDeployment)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProviderTemplate) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProviderTemplateList) DeepCopyInto(out *ProviderTemplateList) {
+func (in *RegionList) DeepCopyInto(out *RegionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ProviderTemplate, len(*in))
+		*out = make([]Region, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderTemplateList.
-func (in *ProviderTemplateList) DeepCopy() *ProviderTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionList.
+func (in *RegionList) DeepCopy() *RegionList {
 	if in == nil {
 		return nil
 	}
-	out := new(ProviderTemplateList)
+	out := new(RegionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProviderTemplateList) DeepCopyObject() runtime.Object {
+func (in *RegionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1180,78 +3124,80 @@ func (in *ProviderTemplateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProviderTemplateSpec) DeepCopyInto(out *ProviderTemplateSpec) {
+func (in *RegionSpec) DeepCopyInto(out *RegionSpec) {
 	*out = *in
-	in.Helm.DeepCopyInto(&out.Helm)
-	if in.CAPIContracts != nil {
-		in, out := &in.CAPIContracts, &out.CAPIContracts
-		*out = make(CompatibilityContracts, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Providers != nil {
-		in, out := &in.Providers, &out.Providers
-		*out = make(Providers, len(*in))
+	if in.ClusterTemplates != nil {
+		in, out := &in.ClusterTemplates, &out.ClusterTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceTemplates != nil {
+		in, out := &in.ServiceTemplates, &out.ServiceTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderTemplateSpec.
-func (in *ProviderTemplateSpec) DeepCopy() *ProviderTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionSpec.
+func (in *RegionSpec) DeepCopy() *RegionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ProviderTemplateSpec)
+	out := new(RegionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProviderTemplateStatus) DeepCopyInto(out *ProviderTemplateStatus) {
+func (in *RegionStatus) DeepCopyInto(out *RegionStatus) {
 	*out = *in
-	if in.CAPIContracts != nil {
-		in, out := &in.CAPIContracts, &out.CAPIContracts
-		*out = make(CompatibilityContracts, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Providers != nil {
-		in, out := &in.Providers, &out.Providers
-		*out = make(Providers, len(*in))
+	if in.ClusterDeployments != nil {
+		in, out := &in.ClusterDeployments, &out.ClusterDeployments
+		*out = make([]RegionClusterDeployment, len(*in))
 		copy(*out, *in)
 	}
-	in.TemplateStatusCommon.DeepCopyInto(&out.TemplateStatusCommon)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderTemplateStatus.
-func (in *ProviderTemplateStatus) DeepCopy() *ProviderTemplateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionStatus.
+func (in *RegionStatus) DeepCopy() *RegionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ProviderTemplateStatus)
+	out := new(RegionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in Providers) DeepCopyInto(out *Providers) {
-	{
-		in := &in
-		*out = make(Providers, len(*in))
-		copy(*out, *in)
-	}
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Providers.
-func (in Providers) DeepCopy() Providers {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
 	if in == nil {
 		return nil
 	}
-	out := new(Providers)
+	out := new(RegistryMirror)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -1281,6 +3227,36 @@ func (in *Release) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseChange) DeepCopyInto(out *ReleaseChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseChange.
+func (in *ReleaseChange) DeepCopy() *ReleaseChange {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseChannelSubscription) DeepCopyInto(out *ReleaseChannelSubscription) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseChannelSubscription.
+func (in *ReleaseChannelSubscription) DeepCopy() *ReleaseChannelSubscription {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseChannelSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReleaseList) DeepCopyInto(out *ReleaseList) {
 	*out = *in
@@ -1313,6 +3289,31 @@ func (in *ReleaseList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseNotes) DeepCopyInto(out *ReleaseNotes) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]ReleaseChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradeSteps != nil {
+		in, out := &in.UpgradeSteps, &out.UpgradeSteps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseNotes.
+func (in *ReleaseNotes) DeepCopy() *ReleaseNotes {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseNotes)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReleaseSpec) DeepCopyInto(out *ReleaseSpec) {
 	*out = *in
@@ -1338,6 +3339,11 @@ func (in *ReleaseSpec) DeepCopy() *ReleaseSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReleaseStatus) DeepCopyInto(out *ReleaseStatus) {
 	*out = *in
+	if in.Notes != nil {
+		in, out := &in.Notes, &out.Notes
+		*out = new(ReleaseNotes)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -1407,6 +3413,25 @@ func (in *Service) DeepCopy() *Service {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceHelmReleaseStatus) DeepCopyInto(out *ServiceHelmReleaseStatus) {
+	*out = *in
+	if in.LastDeployed != nil {
+		in, out := &in.LastDeployed, &out.LastDeployed
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceHelmReleaseStatus.
+func (in *ServiceHelmReleaseStatus) DeepCopy() *ServiceHelmReleaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceHelmReleaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
 	*out = *in
@@ -1456,6 +3481,13 @@ func (in *ServiceStatus) DeepCopyInto(out *ServiceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HelmReleases != nil {
+		in, out := &in.HelmReleases, &out.HelmReleases
+		*out = make([]ServiceHelmReleaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceStatus.
@@ -1623,6 +3655,11 @@ func (in *ServiceTemplateStatus) DeepCopyInto(out *ServiceTemplateStatus) {
 		*out = new(SourceStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(TemplateUsage)
+		**out = **in
+	}
 	in.TemplateStatusCommon.DeepCopyInto(&out.TemplateStatusCommon)
 }
 
@@ -1636,6 +3673,21 @@ func (in *ServiceTemplateStatus) DeepCopy() *ServiceTemplateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackReceiver) DeepCopyInto(out *SlackReceiver) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackReceiver.
+func (in *SlackReceiver) DeepCopy() *SlackReceiver {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackReceiver)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SourceSpec) DeepCopyInto(out *SourceSpec) {
 	*out = *in
@@ -1688,6 +3740,44 @@ func (in *SourceStatus) DeepCopy() *SourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StandbyManagement) DeepCopyInto(out *StandbyManagement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StandbyManagement.
+func (in *StandbyManagement) DeepCopy() *StandbyManagement {
+	if in == nil {
+		return nil
+	}
+	out := new(StandbyManagement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StandbyStatus) DeepCopyInto(out *StandbyStatus) {
+	*out = *in
+	if in.LastRestoreTime != nil {
+		in, out := &in.LastRestoreTime, &out.LastRestoreTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PromotionTime != nil {
+		in, out := &in.PromotionTime, &out.PromotionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StandbyStatus.
+func (in *StandbyStatus) DeepCopy() *StandbyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StandbyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SupportedTemplate) DeepCopyInto(out *SupportedTemplate) {
 	*out = *in
@@ -1733,6 +3823,51 @@ func (in *TargetNamespaces) DeepCopy() *TargetNamespaces {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Telemetry) DeepCopyInto(out *Telemetry) {
+	*out = *in
+	if in.Exporters != nil {
+		in, out := &in.Exporters, &out.Exporters
+		*out = new(TelemetryExporters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make([]TelemetryCategory, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Telemetry.
+func (in *Telemetry) DeepCopy() *Telemetry {
+	if in == nil {
+		return nil
+	}
+	out := new(Telemetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TelemetryExporters) DeepCopyInto(out *TelemetryExporters) {
+	*out = *in
+	if in.OTLP != nil {
+		in, out := &in.OTLP, &out.OTLP
+		*out = new(OTLPExporter)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TelemetryExporters.
+func (in *TelemetryExporters) DeepCopy() *TelemetryExporters {
+	if in == nil {
+		return nil
+	}
+	out := new(TelemetryExporters)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateChainSpec) DeepCopyInto(out *TemplateChainSpec) {
 	*out = *in
@@ -1781,6 +3916,21 @@ func (in *TemplateStatusCommon) DeepCopy() *TemplateStatusCommon {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateUsage) DeepCopyInto(out *TemplateUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateUsage.
+func (in *TemplateUsage) DeepCopy() *TemplateUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateValidationStatus) DeepCopyInto(out *TemplateValidationStatus) {
 	*out = *in
@@ -1795,3 +3945,70 @@ func (in *TemplateValidationStatus) DeepCopy() *TemplateValidationStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tracing) DeepCopyInto(out *Tracing) {
+	*out = *in
+	if in.SamplingPercentage != nil {
+		in, out := &in.SamplingPercentage, &out.SamplingPercentage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tracing.
+func (in *Tracing) DeepCopy() *Tracing {
+	if in == nil {
+		return nil
+	}
+	out := new(Tracing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeReport) DeepCopyInto(out *UpgradeReport) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.CRDs != nil {
+		in, out := &in.CRDs, &out.CRDs
+		*out = make([]CRDChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AffectedClusters != nil {
+		in, out := &in.AffectedClusters, &out.AffectedClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeReport.
+func (in *UpgradeReport) DeepCopy() *UpgradeReport {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookReceiver) DeepCopyInto(out *WebhookReceiver) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookReceiver.
+func (in *WebhookReceiver) DeepCopy() *WebhookReceiver {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookReceiver)
+	in.DeepCopyInto(out)
+	return out
+}
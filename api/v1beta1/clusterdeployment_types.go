@@ -0,0 +1,426 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	BlockingFinalizer          = "k0rdent.mirantis.com/cleanup"
+	ClusterDeploymentFinalizer = "k0rdent.mirantis.com/cluster-deployment"
+
+	FluxHelmChartNameKey      = "helm.toolkit.fluxcd.io/name"
+	FluxHelmChartNamespaceKey = "helm.toolkit.fluxcd.io/namespace"
+
+	KCMManagedLabelKey   = "k0rdent.mirantis.com/managed"
+	KCMManagedLabelValue = "true"
+
+	ClusterNameLabelKey = "cluster.x-k8s.io/cluster-name"
+
+	// ClusterDeploymentNamespaceLabel is the label of the objects created
+	// outside of the namespace of the ClusterDeployment they belong to.
+	ClusterDeploymentNamespaceLabel = "k0rdent.mirantis.com/cluster-deployment-namespace"
+
+	// ClusterBackupNameLabel is the label of the Velero Schedules of the
+	// scheduled backups of the ClusterDeployment holding the name of the backup.
+	ClusterBackupNameLabel = "k0rdent.mirantis.com/cluster-backup"
+
+	// CorrelationIDAnnotation holds the ID correlating the logs, events and
+	// objects of one provisioning attempt of the ClusterDeployment.
+	CorrelationIDAnnotation = "k0rdent.mirantis.com/correlation-id"
+)
+
+const (
+	// ClusterDeploymentKind is the string representation of a ClusterDeployment.
+	ClusterDeploymentKind = "ClusterDeployment"
+	// TemplateReadyCondition indicates the referenced Template exists and valid.
+	TemplateReadyCondition = "TemplateReady"
+	// HelmChartReadyCondition indicates the corresponding HelmChart is valid and ready.
+	HelmChartReadyCondition = "HelmChartReady"
+	// HelmReleaseReadyCondition indicates the corresponding HelmRelease is ready and fully reconciled.
+	HelmReleaseReadyCondition = "HelmReleaseReady"
+	// SveltosClusterReadyCondition indicates the sveltos cluster is valid and ready.
+	SveltosClusterReadyCondition = "SveltosClusterReady"
+	// CompliancePassedCondition indicates the last compliance scan of the cluster found no failed checks.
+	CompliancePassedCondition = "CompliancePassed"
+	// ComplianceChecksFailedReason declares that some of the compliance checks failed.
+	ComplianceChecksFailedReason = "ChecksFailed"
+	// CertificatesValidCondition indicates the certificates of the cluster are not about to expire.
+	CertificatesValidCondition = "CertificatesValid"
+	// CertificatesExpiringReason declares that the certificates of the cluster are about to expire.
+	CertificatesExpiringReason = "Expiring"
+	// CertificatesRotatingReason declares that the rotation of the certificates of the cluster has been triggered.
+	CertificatesRotatingReason = "Rotating"
+	// ReachableCondition indicates the API server of the cluster is reachable and ready.
+	ReachableCondition = "Reachable"
+	// UnreachableReason declares that the API server of the cluster cannot be connected to.
+	UnreachableReason = "Unreachable"
+	// ComponentsUnhealthyReason declares that some of the readiness checks of the API server of the cluster failed.
+	ComponentsUnhealthyReason = "ComponentsUnhealthy"
+	// ManagementBackupsSyncedCondition indicates the Velero Schedules of the Management scoped backups are synced.
+	ManagementBackupsSyncedCondition = "ManagementBackupsSynced"
+	// WorkloadBackupsSyncedCondition indicates the Velero Schedules of the Workload scoped backups are synced.
+	WorkloadBackupsSyncedCondition = "WorkloadBackupsSynced"
+)
+
+// ClusterDeploymentSpec defines the desired state of ClusterDeployment
+type ClusterDeploymentSpec struct {
+	// Config allows to provide parameters for template customization.
+	// If no Config provided, the field will be populated with the default values for
+	// the template and DryRun will be enabled.
+	Config *apiextensionsv1.JSON `json:"config,omitempty"`
+
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+
+	// Template is a reference to a Template object located in the same namespace.
+	Template string `json:"template"`
+	// Name reference to the related Credentials object.
+	Credential string `json:"credential,omitempty"`
+	// +kubebuilder:default:=true
+
+	// PropagateCredentials indicates whether credentials should be propagated
+	// for use by CCM (Cloud Controller Manager).
+	PropagateCredentials bool `json:"propagateCredentials,omitempty"`
+	// ServiceSpec is spec related to deployment of services.
+	ServiceSpec ServiceSpec `json:"serviceSpec,omitempty"`
+	// Compliance enables the periodic CIS benchmark scanning of the cluster.
+	Compliance *ComplianceSpec `json:"compliance,omitempty"`
+	// CertificateRotation enables the automated rotation of the cluster
+	// certificates before they expire.
+	CertificateRotation *CertificateRotationSpec `json:"certificateRotation,omitempty"`
+	// Authentication configures the authentication of the users of the
+	// cluster API server. It takes precedence over the k0s.auth values of
+	// the Config and is supported by the standalone control plane templates.
+	Authentication *ClusterAuthentication `json:"authentication,omitempty"`
+	// +listType=map
+	// +listMapKey=name
+
+	// Backups declares the scheduled backups of the cluster.
+	Backups []ClusterBackup `json:"backups,omitempty"`
+	// EtcdBackup enables the scheduled snapshots of etcd of the cluster
+	// uploaded to the object storage.
+	EtcdBackup *EtcdBackupSpec `json:"etcdBackup,omitempty"`
+
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+
+	// RevisionHistoryLimit is the number of the ClusterDeploymentRevisions
+	// retained to allow the rollback.
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// DryRun specifies whether the template should be applied after validation or only validated.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// ClusterAuthentication defines the authentication of the cluster API server users.
+// +kubebuilder:validation:XValidation:rule="has(self.oidc) != has(self.config)",message="exactly one of oidc or config must be specified"
+type ClusterAuthentication struct {
+	// Config is the structured authentication configuration
+	// (AuthenticationConfiguration of the apiserver.config.k8s.io API group)
+	// passed to the API server as is.
+	Config *apiextensionsv1.JSON `json:"config,omitempty"`
+	// OIDC configures the OpenID Connect provider the users are authenticated by.
+	OIDC *OIDCAuthentication `json:"oidc,omitempty"`
+}
+
+// OIDCAuthentication defines the OpenID Connect provider the users are authenticated by.
+type OIDCAuthentication struct {
+	// +kubebuilder:validation:Pattern=`^https://`
+
+	// IssuerURL is the URL of the provider, only the https scheme is accepted.
+	IssuerURL string `json:"issuerURL"`
+	// +kubebuilder:validation:MinLength=1
+
+	// ClientID is the client ID the ID tokens must be issued for.
+	ClientID string `json:"clientID"`
+	// CertificateAuthority is the PEM-encoded certificate authority
+	// to verify the provider with. Defaults to the host's root CAs.
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+	// +kubebuilder:default:=sub
+
+	// UsernameClaim is the claim of the ID token used as the user name.
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+	// UsernamePrefix is prepended to the user names to prevent clashes
+	// with the existing names, e.g. "oidc:".
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+	// GroupsClaim is the claim of the ID token used as the user groups.
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// GroupsPrefix is prepended to the group names to prevent clashes
+	// with the existing names, e.g. "oidc:".
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
+}
+
+// CertificateRotationSpec defines the automated rotation of the cluster certificates.
+type CertificateRotationSpec struct {
+	// RotateBefore is the period before the expiration of the certificates
+	// the rollout of the control plane is triggered at. Defaults to 720h.
+	RotateBefore *metav1.Duration `json:"rotateBefore,omitempty"`
+}
+
+// CertificatesStatus holds the expiration of the cluster certificates.
+type CertificatesStatus struct {
+	// ExpirationTime is the earliest expiration time of the control plane certificates.
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+	// LastRotationTime is the time the last rotation has been triggered at.
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+	// DaysRemaining is the number of days left until the expiration
+	// as of the last check.
+	DaysRemaining int32 `json:"daysRemaining"`
+}
+
+// CostEstimate holds the estimated cost of the cluster.
+type CostEstimate struct {
+	// MonthlyCost is the estimated monthly cost of the node pools of the
+	// instance types found in the price list.
+	MonthlyCost string `json:"monthlyCost"`
+	// Currency of the cost.
+	Currency string `json:"currency,omitempty"`
+	// UnpricedInstanceTypes lists the instance types of the node pools missing
+	// from the price list, their cost is not included into the estimate.
+	UnpricedInstanceTypes []string `json:"unpricedInstanceTypes,omitempty"`
+}
+
+// ComplianceSpec defines the CIS benchmark scanning of the cluster.
+type ComplianceSpec struct {
+	// Interval is the period between the scans.
+	// Defaults to 24h.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Benchmark is the kube-bench benchmark version to run the checks of, e.g. cis-1.9.
+	// If empty, the benchmark is detected by kube-bench from the Kubernetes version.
+	Benchmark string `json:"benchmark,omitempty"`
+	// +kubebuilder:default:="docker.io/aquasec/kube-bench:v0.10.4"
+
+	// Image is the kube-bench container image.
+	Image string `json:"image,omitempty"`
+}
+
+// ComplianceStatus holds the summary of the last compliance scan of the cluster.
+type ComplianceStatus struct {
+	// LastScanTime is the time the last scan has been completed at.
+	LastScanTime *metav1.Time `json:"lastScanTime,omitempty"`
+	// Benchmark is the benchmark version the checks have been run of.
+	Benchmark string `json:"benchmark,omitempty"`
+	// FailedChecks lists the numbers of the failed checks.
+	FailedChecks []string `json:"failedChecks,omitempty"`
+	// Passed is the number of the passed checks.
+	Passed int32 `json:"passed"`
+	// Failed is the number of the failed checks.
+	Failed int32 `json:"failed"`
+	// Warned is the number of the checks that require a manual verification.
+	Warned int32 `json:"warned"`
+	// Info is the number of the informational checks.
+	Info int32 `json:"info"`
+}
+
+// ClusterBackupScope is the scope of the backups of the cluster.
+type ClusterBackupScope string
+
+const (
+	// ClusterBackupScopeManagement backs up the objects of the
+	// ClusterDeployment in the management cluster.
+	ClusterBackupScopeManagement ClusterBackupScope = "Management"
+	// ClusterBackupScopeWorkload backs up the resources of the cluster with
+	// Velero running in the cluster.
+	ClusterBackupScopeWorkload ClusterBackupScope = "Workload"
+)
+
+// ClusterBackup defines the scheduled backup of the cluster.
+type ClusterBackup struct {
+	// Retention is the period the backups are kept for before they are
+	// garbage-collected by Velero. Defaults to 30 days.
+	Retention *metav1.Duration `json:"retention,omitempty"`
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=20
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+
+	// Name of the backup, unique among the backups of the cluster.
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinLength=1
+
+	// Schedule is a Cron expression defining when to back up.
+	Schedule string `json:"schedule"`
+	// +kubebuilder:default:=Management
+	// +kubebuilder:validation:Enum=Management;Workload
+
+	// Scope of the backup. The Management scope backs up the objects of the
+	// ClusterDeployment in the management cluster, the Workload scope backs
+	// up the resources of the cluster itself with Velero, which has to be
+	// installed in the cluster, e.g. as a service.
+	Scope ClusterBackupScope `json:"scope,omitempty"`
+	// StorageLocation is the name of the Velero BackupStorageLocation the
+	// backups are stored in, the default one if empty. The location is
+	// looked up in the cluster the backups are taken by.
+	StorageLocation string `json:"storageLocation,omitempty"`
+	// +kubebuilder:default:=velero
+
+	// VeleroNamespace is the namespace Velero is installed in in the
+	// cluster, only used by the Workload scope.
+	VeleroNamespace string `json:"veleroNamespace,omitempty"`
+	// IncludedNamespaces lists the namespaces of the cluster to back up,
+	// all of them if empty, only used by the Workload scope.
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+	// ExcludedNamespaces lists the namespaces of the cluster not to back up,
+	// only used by the Workload scope.
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+}
+
+// ClusterBackupStatus holds the state of the scheduled backup of the cluster.
+type ClusterBackupStatus struct {
+	// LastBackupTime is the time the last backup has been started at.
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+	// Name of the backup.
+	Name string `json:"name"`
+	// Scope of the backup.
+	Scope ClusterBackupScope `json:"scope"`
+	// Schedule is the namespaced name of the Velero Schedule of the backup
+	// in the cluster the backups are taken by.
+	Schedule string `json:"schedule,omitempty"`
+	// Phase is the phase of the Velero Schedule of the backup.
+	Phase string `json:"phase,omitempty"`
+	// Error is the error preventing the backups from being scheduled.
+	Error string `json:"error,omitempty"`
+}
+
+// EtcdBackupSpec defines the scheduled snapshots of etcd of the cluster.
+type EtcdBackupSpec struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Schedule is a Cron expression defining when to take the snapshots.
+	Schedule string `json:"schedule"`
+	// Storage is the S3-compatible object storage the snapshots are uploaded to.
+	Storage EtcdBackupStorage `json:"storage"`
+	// +kubebuilder:default:="registry.k8s.io/etcd:3.5.21-0"
+
+	// Image is the etcd image the snapshots are taken with.
+	Image string `json:"image,omitempty"`
+	// +kubebuilder:default:="docker.io/amazon/aws-cli:2.27.0"
+
+	// UploadImage is the AWS CLI image the snapshots are uploaded with.
+	UploadImage string `json:"uploadImage,omitempty"`
+	// Suspend suspends the snapshots, the uploaded ones are kept.
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// EtcdBackupStorage defines the S3-compatible object storage the etcd
+// snapshots are uploaded to.
+type EtcdBackupStorage struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Bucket is the name of the bucket the snapshots are uploaded to.
+	Bucket string `json:"bucket"`
+	// Prefix is the prefix of the keys of the snapshots in the bucket.
+	// Defaults to the namespace and the name of the ClusterDeployment.
+	Prefix string `json:"prefix,omitempty"`
+	// Endpoint is the URL of the S3-compatible storage, AWS S3 if empty.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Region of the bucket.
+	Region string `json:"region,omitempty"`
+	// +kubebuilder:validation:MinLength=1
+
+	// CredentialsSecret is the name of the Secret in the namespace of the
+	// ClusterDeployment holding the access keys of the storage in the
+	// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY keys.
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// EtcdBackupStatus holds the state of the scheduled etcd snapshots of the cluster.
+type EtcdBackupStatus struct {
+	// LastScheduleTime is the time the last snapshot has been started at.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime is the time the last snapshot has been uploaded at.
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// Error is the error preventing the snapshots from being scheduled.
+	Error string `json:"error,omitempty"`
+}
+
+// ClusterDeploymentStatus defines the observed state of ClusterDeployment
+type ClusterDeploymentStatus struct {
+	// Compliance contains the summary of the last compliance scan of the cluster.
+	Compliance *ComplianceStatus `json:"compliance,omitempty"`
+	// Certificates contains the expiration of the cluster certificates.
+	Certificates *CertificatesStatus `json:"certificates,omitempty"`
+	// Backups contains the state of the scheduled backups of the cluster.
+	Backups []ClusterBackupStatus `json:"backups,omitempty"`
+	// EtcdBackup contains the state of the scheduled etcd snapshots of the cluster.
+	EtcdBackup *EtcdBackupStatus `json:"etcdBackup,omitempty"`
+	// LastHeartbeat is the time the API server of the cluster has been
+	// found reachable and ready at last.
+	LastHeartbeat *metav1.Time `json:"lastHeartbeat,omitempty"`
+	// Cost contains the estimated cost of the cluster, set only if the cost
+	// estimation is enabled in the Management.
+	Cost *CostEstimate `json:"cost,omitempty"`
+	// Services contains details for the state of services.
+	Services []ServiceStatus `json:"services,omitempty"`
+	// Currently compatible exact Kubernetes version of the cluster. Being set only if
+	// provided by the corresponding ClusterTemplate.
+	KubernetesVersion string `json:"k8sVersion,omitempty"`
+	// Conditions contains details for the current state of the ClusterDeployment.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// AvailableUpgrades is the list of ClusterTemplate names to which
+	// this cluster can be upgraded. It can be an empty array, which means no upgrades are
+	// available.
+	AvailableUpgrades []string `json:"availableUpgrades,omitempty"`
+	// AppliedTemplate is the name of the ClusterTemplate the cluster has been
+	// successfully deployed with at last.
+	AppliedTemplate string `json:"appliedTemplate,omitempty"`
+	// UpgradeStartTime is the time the upgrade of the cluster to the
+	// ClusterTemplate other than the applied one has been started at.
+	UpgradeStartTime *metav1.Time `json:"upgradeStartTime,omitempty"`
+	// CorrelationID is the ID the logs, events, the HelmRelease and the
+	// Profile of the current provisioning attempt are marked with.
+	CorrelationID string `json:"correlationID,omitempty"`
+	// Revision is the sequence number of the ClusterDeploymentRevision
+	// of the current spec.
+	Revision int64 `json:"revision,omitempty"`
+	// ObservedGeneration is the last observed generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=clusterd;cld
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Shows readiness of the ClusterDeployment",priority=0
+// +kubebuilder:printcolumn:name="Services",type="string",JSONPath=`.status.conditions[?(@.type=="ServicesInReadyState")].message`,description="Number of ready out of total services",priority=0
+// +kubebuilder:printcolumn:name="Template",type="string",JSONPath=`.spec.template`,description="ClusterTemplate used for the ClusterDeployment",priority=0
+// +kubebuilder:printcolumn:name="Messages",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].message`,description="Shows either readiness or error messages from child objects",priority=0
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Time elapsed since object creation",priority=0
+// +kubebuilder:printcolumn:name="Certificates",type="integer",JSONPath=`.status.certificates.daysRemaining`,description="Days remaining until the cluster certificates expire",priority=1
+// +kubebuilder:printcolumn:name="Monthly cost",type="string",JSONPath=`.status.cost.monthlyCost`,description="Estimated monthly cost of the cluster",priority=1
+// +kubebuilder:printcolumn:name="DryRun",type="string",JSONPath=`.spec.dryRun`,description="Dry Run",priority=1
+
+// ClusterDeployment is the Schema for the ClusterDeployments API
+type ClusterDeployment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterDeploymentSpec   `json:"spec,omitempty"`
+	Status ClusterDeploymentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterDeploymentList contains a list of ClusterDeployment
+type ClusterDeploymentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterDeployment `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterDeployment{}, &ClusterDeploymentList{})
+}
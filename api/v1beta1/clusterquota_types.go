@@ -0,0 +1,107 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterQuotaKind is the string representation of a ClusterQuota.
+	ClusterQuotaKind = "ClusterQuota"
+
+	// ClusterQuotaSatisfiedCondition indicates that the ClusterDeployment
+	// fits into all of the ClusterQuotas defined in its namespace.
+	ClusterQuotaSatisfiedCondition = "ClusterQuotaSatisfied"
+	// ClusterQuotaExceededReason declares that a ClusterQuota is exceeded.
+	ClusterQuotaExceededReason = "QuotaExceeded"
+)
+
+// ClusterQuotaSpec defines the limits applied to the ClusterDeployments
+// in the namespace of the ClusterQuota.
+type ClusterQuotaSpec struct {
+	// +kubebuilder:validation:Minimum=0
+
+	// MaxClusterDeployments is the maximum number of ClusterDeployments
+	// that are allowed to be deployed in the namespace.
+	// ClusterDeployments in the dry-run mode are not counted.
+	MaxClusterDeployments *int32 `json:"maxClusterDeployments,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// MaxNodes is the maximum total number of nodes (control plane and workers)
+	// of all of the ClusterDeployments in the namespace.
+	// The number of nodes is taken from the controlPlaneNumber and workersNumber
+	// values of the ClusterDeployment configuration.
+	MaxNodes *int32 `json:"maxNodes,omitempty"`
+
+	// AllowedInstanceTypes is the list of instance types (or their families
+	// if specified with the trailing wildcard, e.g. "t3.*") the ClusterDeployments
+	// are allowed to use. The instance types are taken from the instanceType,
+	// vmSize, machineType and flavor values of the ClusterDeployment configuration.
+	// If empty, any instance type is allowed.
+	AllowedInstanceTypes []string `json:"allowedInstanceTypes,omitempty"`
+}
+
+// ClusterQuotaUsage holds the amount of resources consumed in the namespace.
+type ClusterQuotaUsage struct {
+	// ClusterDeployments is the number of deployed ClusterDeployments.
+	ClusterDeployments int32 `json:"clusterDeployments"`
+	// Nodes is the total number of nodes of the deployed ClusterDeployments.
+	Nodes int32 `json:"nodes"`
+}
+
+// ClusterQuotaStatus defines the observed state of ClusterQuota
+type ClusterQuotaStatus struct {
+	// Used is the current consumption of the resources limited by the ClusterQuota.
+	Used ClusterQuotaUsage `json:"used,omitempty"`
+	// ExceededBy lists the ClusterDeployments that do not fit into the ClusterQuota.
+	ExceededBy []string `json:"exceededBy,omitempty"`
+	// Conditions contains details for the current state of the ClusterQuota.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the last observed generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=cquota
+// +kubebuilder:printcolumn:name="Clusters",type=integer,JSONPath=`.status.used.clusterDeployments`,description="Number of deployed ClusterDeployments"
+// +kubebuilder:printcolumn:name="Max clusters",type=integer,JSONPath=`.spec.maxClusterDeployments`,description="Maximum number of ClusterDeployments"
+// +kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=`.status.used.nodes`,description="Total number of nodes"
+// +kubebuilder:printcolumn:name="Max nodes",type=integer,JSONPath=`.spec.maxNodes`,description="Maximum total number of nodes"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Time elapsed since object creation"
+
+// ClusterQuota is the Schema for the clusterquotas API
+type ClusterQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterQuotaSpec   `json:"spec,omitempty"`
+	Status ClusterQuotaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterQuotaList contains a list of ClusterQuota
+type ClusterQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterQuota{}, &ClusterQuotaList{})
+}
@@ -0,0 +1,96 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Denotes the clustertemplate resource Kind.
+	ClusterTemplateKind = "ClusterTemplate"
+	// ChartAnnotationKubernetesVersion is an annotation containing the Kubernetes exact version in the SemVer format associated with a ClusterTemplate.
+	ChartAnnotationKubernetesVersion = "k0rdent.mirantis.com/k8s-version"
+)
+
+// ClusterTemplateSpec defines the desired state of ClusterTemplate
+type ClusterTemplateSpec struct {
+	Helm HelmSpec `json:"helm"`
+	// Holds key-value pairs with compatibility [contract versions],
+	// where the key is the name of the provider,
+	// and the value is the provider contract version
+	// required to be supported by the provider.
+	//
+	// [contract versions]: https://cluster-api.sigs.k8s.io/developer/providers/contracts
+	ProviderContracts CompatibilityContracts `json:"providerContracts,omitempty"`
+	// Kubernetes exact version in the SemVer format provided by this ClusterTemplate.
+	KubernetesVersion string `json:"k8sVersion,omitempty"`
+	// Providers represent required CAPI providers.
+	// Should be set if not present in the Helm chart metadata.
+	Providers Providers `json:"providers,omitempty"`
+}
+
+// ClusterTemplateStatus defines the observed state of ClusterTemplate
+type ClusterTemplateStatus struct {
+	// Holds key-value pairs with compatibility [contract versions],
+	// where the key is the name of the provider,
+	// and the value is the provider contract version
+	// required to be supported by the provider.
+	//
+	// [contract versions]: https://cluster-api.sigs.k8s.io/developer/providers/contracts
+	ProviderContracts CompatibilityContracts `json:"providerContracts,omitempty"`
+	// Kubernetes exact version in the SemVer format provided by this ClusterTemplate.
+	KubernetesVersion string `json:"k8sVersion,omitempty"`
+	// Providers represent required CAPI providers.
+	Providers Providers `json:"providers,omitempty"`
+	// Usage is the number of the ClusterDeployments referencing the template
+	// either in the spec or as the applied one, the template is safe to be
+	// removed once it is not referenced anymore.
+	Usage *TemplateUsage `json:"usage,omitempty"`
+
+	TemplateStatusCommon `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=clustertmpl
+// +kubebuilder:printcolumn:name="valid",type="boolean",JSONPath=".status.valid",description="Valid",priority=0
+// +kubebuilder:printcolumn:name="clusterDeployments",type="integer",JSONPath=".status.usage.clusterDeployments",description="Number of the ClusterDeployments referencing the template",priority=0
+// +kubebuilder:printcolumn:name="validationError",type="string",JSONPath=".status.validationError",description="Validation Error",priority=1
+// +kubebuilder:printcolumn:name="description",type="string",JSONPath=".status.description",description="Description",priority=1
+
+// ClusterTemplate is the Schema for the clustertemplates API
+type ClusterTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Spec is immutable"
+
+	Spec   ClusterTemplateSpec   `json:"spec,omitempty"`
+	Status ClusterTemplateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterTemplateList contains a list of ClusterTemplate
+type ClusterTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterTemplate{}, &ClusterTemplateList{})
+}
@@ -0,0 +1,123 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// The v1beta1 objects are converted to and from the v1alpha1 hub by their
+// JSON representation, the fields of both versions are the same except for
+// the deprecated fields removed in v1beta1, which are dropped upon the
+// conversion to v1beta1 and left unset upon the conversion back.
+
+// convert converts the src object into the dst one keeping the
+// apiVersion and the kind of the latter.
+func convert(src, dst runtime.Object) error {
+	gvk := dst.GetObjectKind().GroupVersionKind()
+
+	b, err := json.Marshal(src)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %T: %w", src, err)
+	}
+	// the fields unset in src are not kept in dst
+	reflect.ValueOf(dst).Elem().SetZero()
+	if err := json.Unmarshal(b, dst); err != nil {
+		return fmt.Errorf("failed to unmarshal %T into %T: %w", src, dst, err)
+	}
+
+	dst.GetObjectKind().SetGroupVersionKind(gvk)
+	return nil
+}
+
+func (obj *AccessManagement) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *AccessManagement) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *AuditEvent) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *AuditEvent) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *ClusterDeployment) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *ClusterDeployment) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *ClusterDeploymentRevision) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *ClusterDeploymentRevision) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *ClusterQuota) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *ClusterQuota) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *ClusterTemplate) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *ClusterTemplate) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *ClusterTemplateChain) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *ClusterTemplateChain) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *Credential) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *Credential) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *Diagnostics) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *Diagnostics) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *Management) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *Management) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *ManagementBackup) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *ManagementBackup) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *ManagementRestore) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *ManagementRestore) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *MultiClusterService) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *MultiClusterService) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *ProviderTemplate) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *ProviderTemplate) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *Region) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *Region) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *Release) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *Release) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *ServiceTemplate) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *ServiceTemplate) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+func (obj *ServiceTemplateChain) ConvertTo(hub conversion.Hub) error   { return convert(obj, hub) }
+func (obj *ServiceTemplateChain) ConvertFrom(hub conversion.Hub) error { return convert(hub, obj) }
+
+var (
+	_ conversion.Convertible = (*AccessManagement)(nil)
+	_ conversion.Convertible = (*AuditEvent)(nil)
+	_ conversion.Convertible = (*ClusterDeployment)(nil)
+	_ conversion.Convertible = (*ClusterDeploymentRevision)(nil)
+	_ conversion.Convertible = (*ClusterQuota)(nil)
+	_ conversion.Convertible = (*ClusterTemplate)(nil)
+	_ conversion.Convertible = (*ClusterTemplateChain)(nil)
+	_ conversion.Convertible = (*Credential)(nil)
+	_ conversion.Convertible = (*Diagnostics)(nil)
+	_ conversion.Convertible = (*Management)(nil)
+	_ conversion.Convertible = (*ManagementBackup)(nil)
+	_ conversion.Convertible = (*ManagementRestore)(nil)
+	_ conversion.Convertible = (*MultiClusterService)(nil)
+	_ conversion.Convertible = (*ProviderTemplate)(nil)
+	_ conversion.Convertible = (*Region)(nil)
+	_ conversion.Convertible = (*Release)(nil)
+	_ conversion.Convertible = (*ServiceTemplate)(nil)
+	_ conversion.Convertible = (*ServiceTemplateChain)(nil)
+)
@@ -0,0 +1,239 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	fuzz "github.com/google/gofuzz"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+func TestConversion(t *testing.T) {
+	now := metav1.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		hub  conversion.Hub
+		// spoke is the empty object of the version the hub is converted to
+		spoke conversion.Convertible
+		// expected is the hub converted back, the deprecated fields are dropped
+		expected conversion.Hub
+	}{
+		{
+			name: "cluster deployment",
+			hub: &kcmv1alpha1.ClusterDeployment{
+				TypeMeta:   metav1.TypeMeta{APIVersion: kcmv1alpha1.GroupVersion.String(), Kind: kcmv1alpha1.ClusterDeploymentKind},
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "team", Labels: map[string]string{"team": "a"}},
+				Spec: kcmv1alpha1.ClusterDeploymentSpec{
+					Config:     &apiextensionsv1.JSON{Raw: []byte(`{"workersNumber":3}`)},
+					Template:   "aws-standalone-cp",
+					Credential: "aws",
+				},
+				Status: kcmv1alpha1.ClusterDeploymentStatus{
+					Conditions: []metav1.Condition{{Type: kcmv1alpha1.ReadyCondition, Status: metav1.ConditionTrue, Reason: kcmv1alpha1.SucceededReason, LastTransitionTime: now}},
+				},
+			},
+			spoke: new(ClusterDeployment),
+		},
+		{
+			name: "management without the backup name",
+			hub: &kcmv1alpha1.Management{
+				TypeMeta:   metav1.TypeMeta{APIVersion: kcmv1alpha1.GroupVersion.String(), Kind: kcmv1alpha1.ManagementKind},
+				ObjectMeta: metav1.ObjectMeta{Name: kcmv1alpha1.ManagementName},
+				Spec: kcmv1alpha1.ManagementSpec{
+					Release:   "kcm-1-0-0",
+					Providers: []kcmv1alpha1.Provider{{Name: "cluster-api-provider-aws", DependsOn: []string{"cluster-api"}}},
+				},
+				Status: kcmv1alpha1.ManagementStatus{BackupName: "daily", Release: "kcm-1-0-0"},
+			},
+			spoke: new(Management),
+			expected: &kcmv1alpha1.Management{
+				TypeMeta:   metav1.TypeMeta{APIVersion: kcmv1alpha1.GroupVersion.String(), Kind: kcmv1alpha1.ManagementKind},
+				ObjectMeta: metav1.ObjectMeta{Name: kcmv1alpha1.ManagementName},
+				Spec: kcmv1alpha1.ManagementSpec{
+					Release:   "kcm-1-0-0",
+					Providers: []kcmv1alpha1.Provider{{Name: "cluster-api-provider-aws", DependsOn: []string{"cluster-api"}}},
+				},
+				Status: kcmv1alpha1.ManagementStatus{Release: "kcm-1-0-0"},
+			},
+		},
+		{
+			name: "diagnostics",
+			hub: &kcmv1alpha1.Diagnostics{
+				TypeMeta:   metav1.TypeMeta{APIVersion: kcmv1alpha1.GroupVersion.String(), Kind: "Diagnostics"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Status: kcmv1alpha1.DiagnosticsStatus{
+					Error:    "failed",
+					Errors:   1,
+					Findings: []kcmv1alpha1.DiagnosticFinding{{Check: kcmv1alpha1.DiagnosticCheckWebhooks, Severity: kcmv1alpha1.DiagnosticSeverityError, Message: "failed"}},
+				},
+			},
+			spoke: new(Diagnostics),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.expected
+			if expected == nil {
+				expected = tt.hub.DeepCopyObject().(conversion.Hub)
+			}
+
+			gvk := GroupVersion.WithKind(tt.hub.GetObjectKind().GroupVersionKind().Kind)
+			tt.spoke.GetObjectKind().SetGroupVersionKind(gvk)
+			if err := tt.spoke.ConvertFrom(tt.hub); err != nil {
+				t.Fatalf("failed to convert from the hub: %v", err)
+			}
+			if got := tt.spoke.GetObjectKind().GroupVersionKind(); got != gvk {
+				t.Errorf("expected the converted object to be of %s, got %s", gvk, got)
+			}
+
+			hub := tt.hub.DeepCopyObject().(conversion.Hub)
+			hub.GetObjectKind().SetGroupVersionKind(expected.GetObjectKind().GroupVersionKind())
+			if err := tt.spoke.ConvertTo(hub); err != nil {
+				t.Fatalf("failed to convert to the hub: %v", err)
+			}
+			if !equality.Semantic.DeepEqual(hub, expected) {
+				t.Errorf("expected the object converted back to be\n%#v\ngot\n%#v", expected, hub)
+			}
+		})
+	}
+}
+
+// conversionFuzzIterations is the number of the random objects of each kind
+// converted to detect the drift of the fields of the versions.
+const conversionFuzzIterations = 100
+
+func TestConversionRoundTrip(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := kcmv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add v1alpha1 to scheme: %v", err)
+	}
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add v1beta1 to scheme: %v", err)
+	}
+
+	f := fuzzer.FuzzerFor(fuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, conversionFuzzerFuncs), rand.NewSource(rand.Int63()), serializer.NewCodecFactory(scheme))
+
+	for gvk, typ := range scheme.AllKnownTypes() {
+		if gvk.GroupVersion() != GroupVersion || typ.PkgPath() != reflect.TypeFor[Credential]().PkgPath() || strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+
+		t.Run(gvk.Kind, func(t *testing.T) {
+			hubGVK := kcmv1alpha1.GroupVersion.WithKind(gvk.Kind)
+			if _, ok := newObject(t, scheme, gvk).(conversion.Convertible); !ok {
+				t.Fatalf("%s is not convertible", gvk)
+			}
+			for range conversionFuzzIterations {
+				// hub to spoke to hub, the fields removed from v1beta1 are not set
+				hub := newObject(t, scheme, hubGVK).(conversion.Hub)
+				f.Fuzz(hub)
+				clearDeprecatedFields(hub)
+				hub.GetObjectKind().SetGroupVersionKind(hubGVK)
+
+				spoke := newObject(t, scheme, gvk).(conversion.Convertible)
+				spoke.GetObjectKind().SetGroupVersionKind(gvk)
+				if err := spoke.ConvertFrom(hub); err != nil {
+					t.Fatalf("failed to convert from the hub: %v", err)
+				}
+				converted := newObject(t, scheme, hubGVK).(conversion.Hub)
+				converted.GetObjectKind().SetGroupVersionKind(hubGVK)
+				if err := spoke.ConvertTo(converted); err != nil {
+					t.Fatalf("failed to convert to the hub: %v", err)
+				}
+				if !equality.Semantic.DeepEqual(hub, converted) {
+					t.Fatalf("the hub has changed after the round trip:\n%s", cmp.Diff(hub, converted))
+				}
+
+				// spoke to hub to spoke
+				spoke = newObject(t, scheme, gvk).(conversion.Convertible)
+				f.Fuzz(spoke)
+				spoke.GetObjectKind().SetGroupVersionKind(gvk)
+
+				hub = newObject(t, scheme, hubGVK).(conversion.Hub)
+				hub.GetObjectKind().SetGroupVersionKind(hubGVK)
+				if err := spoke.ConvertTo(hub); err != nil {
+					t.Fatalf("failed to convert to the hub: %v", err)
+				}
+				convertedSpoke := newObject(t, scheme, gvk).(conversion.Convertible)
+				convertedSpoke.GetObjectKind().SetGroupVersionKind(gvk)
+				if err := convertedSpoke.ConvertFrom(hub); err != nil {
+					t.Fatalf("failed to convert from the hub: %v", err)
+				}
+				if !equality.Semantic.DeepEqual(spoke, convertedSpoke) {
+					t.Fatalf("the object has changed after the round trip:\n%s", cmp.Diff(spoke, convertedSpoke))
+				}
+			}
+		})
+	}
+}
+
+// conversionFuzzerFuncs fill the free-form fields with valid JSON, so they
+// survive the conversion unchanged.
+func conversionFuzzerFuncs(serializer.CodecFactory) []any {
+	return []any{
+		func(j *apiextensionsv1.JSON, c fuzz.Continue) {
+			j.Raw = randomJSON(c)
+		},
+		func(r *runtime.RawExtension, c fuzz.Continue) {
+			r.Raw = randomJSON(c)
+		},
+	}
+}
+
+// randomJSON returns the JSON object encoded the same way as by the
+// conversion, so it is compared byte by byte.
+func randomJSON(c fuzz.Continue) []byte {
+	b, err := json.Marshal(map[string]string{c.RandString(): c.RandString()})
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// clearDeprecatedFields unsets the v1alpha1 fields removed from v1beta1.
+func clearDeprecatedFields(hub conversion.Hub) {
+	if obj, ok := hub.(*kcmv1alpha1.Management); ok {
+		obj.Status.BackupName = ""
+	}
+}
+
+func newObject(t *testing.T, scheme *runtime.Scheme, gvk schema.GroupVersionKind) runtime.Object {
+	t.Helper()
+
+	obj, err := scheme.New(gvk)
+	if err != nil {
+		t.Fatalf("failed to create %s: %v", gvk, err)
+	}
+	return obj
+}
@@ -0,0 +1,145 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DiagnosticsKind is the string representation of a Diagnostics.
+	DiagnosticsKind = "Diagnostics"
+
+	// DiagnosticsRunAnnotation requests the checks of the Diagnostics to be
+	// run again once its value differs from the last handled one.
+	DiagnosticsRunAnnotation = "k0rdent.mirantis.com/run-diagnostics"
+)
+
+// DiagnosticCheck is the check run by the Diagnostics.
+type DiagnosticCheck string
+
+const (
+	// DiagnosticCheckCRDVersions reports the CRDs with objects stored in
+	// the versions not served or in several versions.
+	DiagnosticCheckCRDVersions DiagnosticCheck = "CRDVersions"
+	// DiagnosticCheckWebhooks reports the admission webhooks the services
+	// of which are missing, have no ready endpoints or are not reachable.
+	DiagnosticCheckWebhooks DiagnosticCheck = "Webhooks"
+	// DiagnosticCheckProviders reports the components of the Management
+	// failed to be installed.
+	DiagnosticCheckProviders DiagnosticCheck = "Providers"
+	// DiagnosticCheckStuckFinalizers reports the objects being deleted for
+	// longer than the threshold because of their finalizers.
+	DiagnosticCheckStuckFinalizers DiagnosticCheck = "StuckFinalizers"
+	// DiagnosticCheckOrphanedHelmReleases reports the HelmReleases managed
+	// by kcm the owners of which no longer exist.
+	DiagnosticCheckOrphanedHelmReleases DiagnosticCheck = "OrphanedHelmReleases"
+)
+
+// DiagnosticSeverity is the severity of the finding.
+type DiagnosticSeverity string
+
+const (
+	// DiagnosticSeverityError is the severity of the findings breaking the
+	// management cluster.
+	DiagnosticSeverityError DiagnosticSeverity = "Error"
+	// DiagnosticSeverityWarning is the severity of the findings which are
+	// likely to break the management cluster.
+	DiagnosticSeverityWarning DiagnosticSeverity = "Warning"
+)
+
+// DiagnosticsSpec defines the checks to run.
+type DiagnosticsSpec struct {
+	// StuckFinalizerThreshold is the time an object can be deleted for before
+	// it is reported as stuck on its finalizers.
+	// +kubebuilder:default:="30m"
+	StuckFinalizerThreshold *metav1.Duration `json:"stuckFinalizerThreshold,omitempty"`
+	// +listType=set
+	// +kubebuilder:validation:items:Enum=CRDVersions;Webhooks;Providers;StuckFinalizers;OrphanedHelmReleases
+
+	// Checks lists the checks to run, all of them are run if empty.
+	Checks []DiagnosticCheck `json:"checks,omitempty"`
+}
+
+// DiagnosticObject references the object the finding is about.
+type DiagnosticObject struct {
+	// Kind of the object.
+	Kind string `json:"kind"`
+	// Namespace of the object, empty for the cluster-scoped objects.
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the object.
+	Name string `json:"name"`
+}
+
+// DiagnosticFinding is the problem found by the check.
+type DiagnosticFinding struct {
+	// Object is the object the finding is about.
+	Object *DiagnosticObject `json:"object,omitempty"`
+	// Check is the check the finding has been reported by.
+	Check DiagnosticCheck `json:"check"`
+	// Severity of the finding.
+	Severity DiagnosticSeverity `json:"severity"`
+	// Message is a human-readable description of the finding.
+	Message string `json:"message"`
+}
+
+// DiagnosticsStatus defines the report of the last run of the checks.
+type DiagnosticsStatus struct {
+	// LastRunTime is the time the checks have been run at last.
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+	// LastHandledRunRequest is the value of the run annotation the checks
+	// have been run for at last.
+	LastHandledRunRequest string `json:"lastHandledRunRequest,omitempty"`
+	// Error is the error preventing the checks from being run.
+	Error string `json:"error,omitempty"`
+	// Findings lists the findings of the checks.
+	Findings []DiagnosticFinding `json:"findings,omitempty"`
+	// ObservedGeneration is the last observed generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Errors is the number of the findings of the Error severity.
+	Errors int32 `json:"errors"`
+	// Warnings is the number of the findings of the Warning severity.
+	Warnings int32 `json:"warnings"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=diag
+// +kubebuilder:printcolumn:name="Errors",type=integer,JSONPath=`.status.errors`,description="Number of the errors found"
+// +kubebuilder:printcolumn:name="Warnings",type=integer,JSONPath=`.status.warnings`,description="Number of the warnings found"
+// +kubebuilder:printcolumn:name="Last run",type=date,JSONPath=`.status.lastRunTime`,description="Time elapsed since the last run of the checks"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Time elapsed since object creation"
+
+// Diagnostics is the Schema for the diagnostics API
+type Diagnostics struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DiagnosticsSpec   `json:"spec,omitempty"`
+	Status DiagnosticsStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DiagnosticsList contains a list of Diagnostics
+type DiagnosticsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Diagnostics `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Diagnostics{}, &DiagnosticsList{})
+}
@@ -0,0 +1,143 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ManagementBackupKind is the string representation of a ManagementBackup.
+	ManagementBackupKind = "ManagementBackup"
+
+	// Name to label most of the KCM-related components.
+	// Mostly utilized by the backup feature.
+	GenericComponentNameLabel = "k0rdent.mirantis.com/component"
+	// Component label value for the KCM-related components.
+	GenericComponentLabelValueKCM = "kcm"
+
+	// ManagementBackupScheduleLabelKey is the label of the [ManagementBackup] objects
+	// created from the backup schedules of the [Management] holding its name.
+	ManagementBackupScheduleLabelKey = "k0rdent.mirantis.com/backup-schedule"
+
+	// LastBackupSucceededCondition indicates whether the most recently
+	// finished backup of the [ManagementBackup] has succeeded.
+	LastBackupSucceededCondition = "LastBackupSucceeded"
+	// BackupSucceededReason is the reason of the succeeded backup.
+	BackupSucceededReason = "BackupSucceeded"
+	// BackupFailedReason is the reason of the failed backup.
+	BackupFailedReason = "BackupFailed"
+)
+
+// ManagementBackupSpec defines the desired state of ManagementBackup
+type ManagementBackupSpec struct {
+	// StorageLocation is the name of a [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.StorageLocation]
+	// where the backup should be stored.
+	StorageLocation string `json:"storageLocation,omitempty"`
+	// Schedule is a Cron expression defining when to run the scheduled [ManagementBackup].
+	// If not set, the object is considered to be run only once.
+	Schedule string `json:"schedule,omitempty"`
+	// PerformOnManagementUpgrade indicates that a single [ManagementBackup]
+	// should be created and stored in the [ManagementBackup] storage location if not default
+	// before the [Management] release upgrade.
+	PerformOnManagementUpgrade bool `json:"performOnManagementUpgrade,omitempty"`
+	// Retention is the period the backups are kept for before they are
+	// garbage-collected by Velero. Defaults to 30 days.
+	Retention *metav1.Duration `json:"retention,omitempty"`
+	// Scope narrows the backup down to a part of the management state,
+	// the whole state is backed up if not set.
+	Scope *ManagementBackupScope `json:"scope,omitempty"`
+}
+
+// ManagementBackupScope defines the part of the management state to back up.
+type ManagementBackupScope struct {
+	// ClusterDeploymentSelector selects the [ClusterDeployment] objects the
+	// clusters of which are backed up, all of them if not set. The CAPI
+	// objects of the unselected clusters and the providers used only by
+	// them are not backed up, the [ClusterDeployment] objects themselves are
+	// backed up along with the rest of the kcm objects.
+	ClusterDeploymentSelector *metav1.LabelSelector `json:"clusterDeploymentSelector,omitempty"`
+	// IncludedNamespaces lists the namespaces to back up, all of them if empty.
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+	// ExcludedNamespaces lists the namespaces not to back up.
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+	// Providers lists the names of the CAPI providers, e.g. infrastructure-aws,
+	// the objects of which are backed up, all of the providers used by the
+	// backed up clusters if empty. The core CAPI provider is always backed up.
+	Providers []string `json:"providers,omitempty"`
+}
+
+// ManagementBackupStatus defines the observed state of ManagementBackup
+type ManagementBackupStatus struct {
+	// NextAttempt indicates the time when the next backup will be created.
+	// Always absent for a single [ManagementBackup].
+	NextAttempt *metav1.Time `json:"nextAttempt,omitempty"`
+	// Time of the most recently created [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.Backup].
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+	// Most recently [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.Backup] that has been created.
+	LastBackup *velerov1.BackupStatus `json:"lastBackup,omitempty"`
+	// Name of most recently created [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.Backup].
+	LastBackupName string `json:"lastBackupName,omitempty"`
+	// LastSuccessfulBackupTime is the completion time of the most recently
+	// succeeded [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.Backup].
+	LastSuccessfulBackupTime *metav1.Time `json:"lastSuccessfulBackupTime,omitempty"`
+	// LastSuccessfulBackupName is the name of the most recently succeeded
+	// [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.Backup].
+	LastSuccessfulBackupName string `json:"lastSuccessfulBackupName,omitempty"`
+	// Error stores messages in case of failed backup creation.
+	Error string `json:"error,omitempty"`
+	// Conditions contains details for the current state of the [ManagementBackup].
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// LastSuccessfulBackupItems is the number of the items backed up by the
+	// most recently succeeded [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.Backup].
+	LastSuccessfulBackupItems int32 `json:"lastSuccessfulBackupItems,omitempty"`
+	// ConsecutiveFailures is the number of the backups failed in a row
+	// since the last succeeded one.
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=kcmbackup;mgmtbackup
+// +kubebuilder:printcolumn:name="LastBackupStatus",type=string,JSONPath=`.status.lastBackup.phase`,description="Status of last backup run",priority=0
+// +kubebuilder:printcolumn:name="NextBackup",type=string,JSONPath=`.status.nextAttempt`,description="Next scheduled attempt to back up",priority=0
+// +kubebuilder:printcolumn:name="SinceLastBackup",type=date,JSONPath=`.status.lastBackupTime`,description="Time elapsed since last backup run",priority=1
+// +kubebuilder:printcolumn:name="SinceLastSuccess",type=date,JSONPath=`.status.lastSuccessfulBackupTime`,description="Time elapsed since last succeeded backup",priority=1
+// +kubebuilder:printcolumn:name="Failures",type=integer,JSONPath=`.status.consecutiveFailures`,description="Number of backups failed in a row",priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Time elapsed since object creation",priority=0
+// +kubebuilder:printcolumn:name="Error",type=string,JSONPath=`.status.error`,description="Error during creation",priority=1
+
+// ManagementBackup is the Schema for the managementbackups API
+type ManagementBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ManagementBackupSpec   `json:"spec,omitempty"`
+	Status ManagementBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ManagementBackupList contains a list of ManagementBackup
+type ManagementBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ManagementBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ManagementBackup{}, &ManagementBackupList{})
+}
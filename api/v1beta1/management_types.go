@@ -0,0 +1,1047 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	CoreKCMName = "kcm"
+
+	CoreCAPIName = "capi"
+
+	ManagementKind      = "Management"
+	ManagementName      = "kcm"
+	ManagementFinalizer = "k0rdent.mirantis.com/management"
+
+	// ReleaseApprovalAnnotation is the annotation of the Management approving
+	// the upgrade to the Release of the subscribed channel with the given name.
+	ReleaseApprovalAnnotation = "k0rdent.mirantis.com/approved-release"
+	// SkipPreflightAnnotation is the annotation of the Management allowing
+	// the Release upgrade despite the failed preflight checks if set to "true".
+	SkipPreflightAnnotation = "k0rdent.mirantis.com/skip-preflight"
+	// AllowDowngradeAnnotation is the annotation of the Management allowing
+	// the change of the Release to the one of a lower version if set to "true".
+	AllowDowngradeAnnotation = "k0rdent.mirantis.com/allow-downgrade"
+
+	// ProviderComponentLabelKey is the label of the objects of the providers
+	// installed with the CAPIOperator lifecycle holding the component name.
+	ProviderComponentLabelKey = "k0rdent.mirantis.com/provider-component"
+)
+
+// ManagementSpec defines the desired state of Management
+type ManagementSpec struct {
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+
+	// Release references the Release object.
+	Release string `json:"release"`
+	// ReleaseChannel subscribes the Management to the release channel.
+	// The newer Releases of the channel are picked up automatically
+	// once they are ready.
+	ReleaseChannel *ReleaseChannelSubscription `json:"releaseChannel,omitempty"`
+	// UpgradeDryRun enables the review of the Release upgrades. Once the
+	// Release is changed, the report of the upgrade is produced in the status
+	// and the upgrade is performed only after the Management is annotated with
+	// the k0rdent.mirantis.com/approved-release annotation set to its name.
+	UpgradeDryRun bool `json:"upgradeDryRun,omitempty"`
+	// Core holds the core Management components that are mandatory.
+	// If not specified, will be populated with the default values.
+	Core *Core `json:"core,omitempty"`
+
+	// Providers is the list of supported CAPI providers.
+	Providers []Provider `json:"providers,omitempty"`
+
+	// +kubebuilder:validation:Enum=Helm;CAPIOperator
+	// +kubebuilder:default=Helm
+
+	// ProviderLifecycle defines how the CAPI providers are installed. With
+	// the Helm lifecycle each of the providers is installed by the HelmRelease
+	// of its ProviderTemplate chart. With the CAPIOperator lifecycle the
+	// Cluster API Operator provider objects rendered from the chart are created
+	// directly and their lifecycle is delegated to the Cluster API Operator.
+	ProviderLifecycle ProviderLifecycle `json:"providerLifecycle,omitempty"`
+
+	// DisabledComponents is the list of the optional components of the
+	// KCM chart to uninstall. The component cannot be disabled while any
+	// of the objects relying on it exist.
+	DisabledComponents []OptionalComponent `json:"disabledComponents,omitempty"`
+
+	// +listType=map
+	// +listMapKey=name
+
+	// Backups is the list of the backup schedules of the Management. The
+	// scheduled ManagementBackup objects are created for each of them and
+	// removed once the schedule is removed from the list.
+	Backups []ManagementBackupSchedule `json:"backups,omitempty"`
+
+	// BackupEncryption defines the encryption at rest of the backups of the
+	// Management. The ManagementBackups are not created until the encryption
+	// is applied.
+	BackupEncryption *BackupEncryption `json:"backupEncryption,omitempty"`
+
+	// BackupStorage configures the Velero provider plugin and the default
+	// BackupStorageLocation of the backups from a Credential instead of
+	// configuring Velero manually.
+	BackupStorage *BackupStorage `json:"backupStorage,omitempty"`
+
+	// Standby runs the management cluster as the standby of the primary one,
+	// which continuously restores the backups of the primary cluster and is
+	// promoted to the primary cluster once the primary one is lost.
+	Standby *StandbyManagement `json:"standby,omitempty"`
+
+	// HighAvailability defines the replicas and the leader election of the
+	// KCM controller manager. The values take precedence over the KCM config.
+	HighAvailability *HighAvailability `json:"highAvailability,omitempty"`
+
+	// RegistryMirror defines the registry the Helm charts and the container
+	// images of the components and of the templates are pulled from instead
+	// of the default ones, e.g. in the air-gapped environments.
+	RegistryMirror *RegistryMirror `json:"registryMirror,omitempty"`
+
+	// Global defines the values applied to all of the components and of the
+	// providers, e.g. the scheduling constraints of the platform. The values
+	// are set under the "global" key of the Helm values of each of them, the
+	// values of the component configuration take precedence.
+	Global *GlobalValues `json:"global,omitempty"`
+
+	// Telemetry defines the policy of the telemetry data collection.
+	// If not set, all of the data is collected and sent online.
+	Telemetry *Telemetry `json:"telemetry,omitempty"`
+
+	// ImageVerification defines the policy of the verification of the container
+	// images signatures of the Management components before their installation.
+	// If not set, the images are not verified.
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty"`
+
+	// NetworkPolicies configures the NetworkPolicies restricting the traffic
+	// of the Management components in their namespaces.
+	// If not set, no NetworkPolicies are created.
+	NetworkPolicies *NetworkPolicies `json:"networkPolicies,omitempty"`
+
+	// CloudQuotaCollection enables the periodic collection of the cloud quotas
+	// of the accounts the Credentials give access to. The remaining headroom
+	// is reported in the status and the ClusterDeployments likely exceeding
+	// it are warned about on creation. If not set, the quotas are not collected.
+	CloudQuotaCollection *CloudQuotaCollection `json:"cloudQuotaCollection,omitempty"`
+
+	// +kubebuilder:validation:Enum=baseline;restricted
+
+	// SecurityProfile is the hardening profile applied to the workloads of the
+	// Management components and of the services deployed to the managed clusters.
+	// The hardened security contexts are injected into all of the containers.
+	// If not set, the workloads are deployed as is.
+	SecurityProfile SecurityProfile `json:"securityProfile,omitempty"`
+
+	// FIPS enables the FIPS-compliant mode for regulated environments.
+	// The KCM controller is deployed from the FIPS-validated crypto build
+	// of its image and the global.fips value is set for all of the components.
+	FIPS bool `json:"fips,omitempty"`
+
+	// Tracing configures the export of the OpenTelemetry traces of the
+	// reconciliation of the Management, of the ClusterDeployments and of the
+	// MultiClusterServices. If not set, the traces are not exported.
+	Tracing *Tracing `json:"tracing,omitempty"`
+
+	// Notifications configures the receivers of the notifications about the
+	// failures of the ClusterDeployments and of the ManagementBackups, the
+	// completed upgrades of the clusters and the expiring Credentials.
+	// If not set, no notifications are sent.
+	Notifications *Notifications `json:"notifications,omitempty"`
+
+	// CostEstimation enables the estimation of the monthly cost of the
+	// ClusterDeployments from the prices of the instance types of their node
+	// pools. The estimate is reported in the status and the ClusterDeployments
+	// exceeding the budget are warned about on admission. If not set, the
+	// cost is not estimated.
+	CostEstimation *CostEstimation `json:"costEstimation,omitempty"`
+
+	// Observability enables the forwarding of the logs and the metrics of
+	// the managed clusters to the OTLP backend. The collector ServiceTemplate
+	// is deployed to the selected clusters and pointed at the backend with
+	// the kcm-observability MultiClusterService. If not set, the collector is
+	// not deployed.
+	Observability *Observability `json:"observability,omitempty"`
+
+	// Debug enables the profiling of the KCM controller manager for the
+	// performance investigations. The values take precedence over the KCM
+	// config. If not set, the profiling is disabled.
+	Debug *Debug `json:"debug,omitempty"`
+
+	// Concurrency defines the number of the objects reconciled concurrently
+	// by the controllers of the KCM controller manager. The values take
+	// precedence over the KCM config. If not set, the objects of each of the
+	// controllers are reconciled one at a time.
+	Concurrency *Concurrency `json:"concurrency,omitempty"`
+
+	// Intervals defines the intervals of the periodic resyncs and requeues
+	// of the controllers of the KCM controller manager, so the large
+	// installations can trade the freshness of the statuses for the load of
+	// the API server. The values take precedence over the KCM config. If not
+	// set, the defaults of the controllers are used.
+	Intervals *Intervals `json:"intervals,omitempty"`
+}
+
+// Intervals defines the intervals of the periodic reconciliations of the
+// controllers of the KCM controller manager.
+type Intervals struct {
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="the interval must be positive"
+
+	// Resync is the interval all of the watched objects are reconciled at
+	// regardless of their changes, e.g. to correct the drift of the objects
+	// managed by them. Defaults to 10h.
+	Resync *metav1.Duration `json:"resync,omitempty"`
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="the interval must be positive"
+
+	// ClusterDeploymentRequeue is the interval the ClusterDeployments are
+	// requeued at until their HelmReleases and services are ready.
+	// Defaults to 10s.
+	ClusterDeploymentRequeue *metav1.Duration `json:"clusterDeploymentRequeue,omitempty"`
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="the interval must be positive"
+
+	// ManagementRequeue is the interval the Management is requeued at until
+	// its components are ready. Defaults to 10s.
+	ManagementRequeue *metav1.Duration `json:"managementRequeue,omitempty"`
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="the interval must be positive"
+
+	// TemplateRequeue is the interval the templates are requeued at to
+	// revalidate them once their dependencies, e.g. the Management, appear.
+	// Defaults to 1m.
+	TemplateRequeue *metav1.Duration `json:"templateRequeue,omitempty"`
+}
+
+// Concurrency defines the maximum numbers of the concurrent reconciliations
+// of the controllers of the KCM controller manager.
+type Concurrency struct {
+	// +kubebuilder:validation:Minimum=1
+
+	// ClusterDeployment is the number of the ClusterDeployments reconciled concurrently.
+	ClusterDeployment *int32 `json:"clusterDeployment,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+
+	// MultiClusterService is the number of the MultiClusterServices reconciled concurrently.
+	MultiClusterService *int32 `json:"multiClusterService,omitempty"`
+}
+
+// Debug defines the profiling settings of the KCM controller manager.
+type Debug struct {
+	// Pprof enables the pprof endpoints of the KCM controller manager. They
+	// are bound to the loopback interface of its pod and are reachable only by
+	// forwarding the port of the pod, e.g. with "kubectl port-forward", so the
+	// access is authorized by the RBAC of the pods/portforward subresource.
+	Pprof bool `json:"pprof,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+
+	// BlockProfileRate is the rate of the sampling of the blocking events in
+	// the block profile, one event per the given number of nanoseconds spent
+	// blocked is sampled. If not set or 0, the block profiling is disabled.
+	BlockProfileRate int32 `json:"blockProfileRate,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+
+	// MutexProfileFraction is the rate of the sampling of the mutex contention
+	// events in the mutex profile, one of the given number of the events is
+	// sampled. If not set or 0, the mutex profiling is disabled.
+	MutexProfileFraction int32 `json:"mutexProfileFraction,omitempty"`
+}
+
+// GlobalValues defines the Helm values applied to all of the Management components.
+type GlobalValues struct {
+	// Resources are the compute resources of the containers of the components.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// NodeSelector constrains the pods of the components to the matching nodes.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// PriorityClassName is the name of the PriorityClass of the pods of the components.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Tolerations allow the pods of the components to be scheduled to the tainted nodes.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// ImagePullSecrets are the Secrets the images of the components are pulled with.
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+}
+
+// ManagementBackupSchedule defines the scheduled backups of the Management.
+type ManagementBackupSchedule struct {
+	// +kubebuilder:validation:MaxLength=48
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+
+	// Name is the name of the ManagementBackup created for the schedule.
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinLength=1
+
+	// Schedule is a Cron expression defining when to run the backups.
+	Schedule string `json:"schedule"`
+	// StorageLocation is the name of the Velero BackupStorageLocation the backups are stored in.
+	// If not set, the default one is used.
+	StorageLocation string `json:"storageLocation,omitempty"`
+	// Retention is the period the backups are kept for before they are
+	// garbage-collected by Velero. Defaults to 30 days.
+	Retention *metav1.Duration `json:"retention,omitempty"`
+	// PerformOnManagementUpgrade additionally creates the backup before the Management release upgrade.
+	PerformOnManagementUpgrade bool `json:"performOnManagementUpgrade,omitempty"`
+}
+
+// BackupEncryption defines the encryption at rest of the backups.
+type BackupEncryption struct {
+	// KMSKeyID is the key the backups are encrypted with by the object storage
+	// on the server side. It is the ID or the ARN of the AWS KMS key for the aws
+	// provider of the BackupStorageLocation and the resource name of the Cloud
+	// KMS key for the gcp provider. The key is set to the config of the
+	// BackupStorageLocation of the backups.
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+	// RepositoryPasswordSecretRef references the key of the Secret in the
+	// system namespace holding the password the kopia or restic repositories
+	// of the backed up volumes are encrypted with. It has to be set before the
+	// volumes are backed up for the first time, the existing repositories are
+	// not accessible with a changed password.
+	RepositoryPasswordSecretRef *corev1.SecretKeySelector `json:"repositoryPasswordSecretRef,omitempty"`
+}
+
+// BackupStorage defines the object storage the backups are stored in.
+type BackupStorage struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Credential is the name of the Credential in the system namespace the
+	// object storage is accessed with. The Credentials of the
+	// AWSClusterStaticIdentity, of the AzureClusterIdentity of the
+	// ServicePrincipal type and of the Secret of the GCP service account are
+	// supported.
+	Credential string `json:"credential"`
+	// +kubebuilder:validation:MinLength=1
+
+	// Bucket is the name of the bucket, the blob container for Azure, the
+	// backups are stored in.
+	Bucket string `json:"bucket"`
+	// Prefix is the path in the bucket the backups are stored under.
+	Prefix string `json:"prefix,omitempty"`
+	// Config is the provider-specific config of the BackupStorageLocation,
+	// e.g. the region for AWS or the resourceGroup, the storageAccount and
+	// the subscriptionId for Azure.
+	Config map[string]string `json:"config,omitempty"`
+	// PluginImage overrides the image of the Velero plugin of the provider.
+	PluginImage string `json:"pluginImage,omitempty"`
+}
+
+// StandbyManagement defines the standby mode of the management cluster.
+type StandbyManagement struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// BackupSchedule is the name of the scheduled ManagementBackup of the
+	// primary cluster the backups of which are restored. The backups are
+	// synced by Velero from the BackupStorageLocation shared with the
+	// primary cluster.
+	BackupSchedule string `json:"backupSchedule"`
+	// Promote promotes the standby cluster to the primary one: the restores
+	// are stopped and the restored clusters, their Sveltos registrations
+	// and HelmReleases paused while on standby are resumed. The primary
+	// cluster has to be lost or shut down, both of the clusters manage the
+	// same clusters otherwise.
+	Promote bool `json:"promote,omitempty"`
+}
+
+// StandbyStatus defines the state of the standby management cluster.
+type StandbyStatus struct {
+	// LastRestoreTime is the time the last restore has been started at.
+	LastRestoreTime *metav1.Time `json:"lastRestoreTime,omitempty"`
+	// PromotionTime is the time the standby cluster has been promoted at.
+	PromotionTime *metav1.Time `json:"promotionTime,omitempty"`
+	// LastRestoredBackup is the name of the last restored backup of the primary cluster.
+	LastRestoredBackup string `json:"lastRestoredBackup,omitempty"`
+	// LastRestoreName is the name of the Velero Restore of the last restored backup.
+	LastRestoreName string `json:"lastRestoreName,omitempty"`
+	// LastRestorePhase is the phase of the Velero Restore of the last restored backup.
+	LastRestorePhase velerov1.RestorePhase `json:"lastRestorePhase,omitempty"`
+	// Error is the error of the last restore or of the promotion.
+	Error string `json:"error,omitempty"`
+}
+
+// TelemetryMode is the mode of the telemetry data collection.
+type TelemetryMode string
+
+const (
+	// TelemetryModeDisabled disables the telemetry data collection.
+	TelemetryModeDisabled TelemetryMode = "Disabled"
+	// TelemetryModeLocal aggregates the telemetry data locally, the data
+	// is only exposed via the configured exporters.
+	TelemetryModeLocal TelemetryMode = "Local"
+	// TelemetryModeOnline sends the telemetry data online in addition to
+	// exposing it via the configured exporters.
+	TelemetryModeOnline TelemetryMode = "Online"
+)
+
+// TelemetryCategory is the category of the telemetry data.
+// +kubebuilder:validation:Enum=lifecycle;inventory
+type TelemetryCategory string
+
+const (
+	// TelemetryCategoryLifecycle is the category of the ClusterDeployments lifecycle events.
+	TelemetryCategoryLifecycle TelemetryCategory = "lifecycle"
+	// TelemetryCategoryInventory is the category of the periodic heartbeats
+	// of the ClusterDeployments along with their templates and providers.
+	TelemetryCategoryInventory TelemetryCategory = "inventory"
+)
+
+// Telemetry defines the policy of the telemetry data collection.
+type Telemetry struct {
+	// Exporters defines the exporters the telemetry data is exposed via.
+	Exporters *TelemetryExporters `json:"exporters,omitempty"`
+	// +kubebuilder:validation:Enum=Disabled;Local;Online
+	// +kubebuilder:default:=Online
+
+	// Mode is the mode of the telemetry data collection.
+	Mode TelemetryMode `json:"mode,omitempty"`
+	// Categories lists the categories of the collected data.
+	// If not set, all of the categories are collected.
+	Categories []TelemetryCategory `json:"categories,omitempty"`
+}
+
+// TelemetryExporters defines the exporters of the telemetry data.
+type TelemetryExporters struct {
+	// OTLP exports the telemetry events as the OpenTelemetry log records.
+	OTLP *OTLPExporter `json:"otlp,omitempty"`
+	// Prometheus exposes the aggregated telemetry data as the metrics of the controller.
+	Prometheus bool `json:"prometheus,omitempty"`
+}
+
+// OTLPExporter defines the OpenTelemetry collector the data is exported to.
+type OTLPExporter struct {
+	// +kubebuilder:validation:Pattern=`^https?://.+$`
+
+	// Endpoint is the base URL of the OTLP/HTTP receiver of the collector,
+	// e.g. http://otel-collector.monitoring:4318.
+	Endpoint string `json:"endpoint"`
+}
+
+// RegistryMirror defines the mirror registry of the charts and the images.
+type RegistryMirror struct {
+	// +kubebuilder:validation:Pattern=`^(oci|https?)://.+$`
+
+	// ChartsURL is the URL of the Helm repository the charts are pulled from,
+	// prefixed with oci:// for the OCI registries.
+	ChartsURL string `json:"chartsURL"`
+	// ImageRegistry is the host of the registry replacing the registries of
+	// the container images, e.g. registry.local:5000. The global.imageRegistry
+	// value is set for all of the components. If not set, the images are not rewritten.
+	ImageRegistry string `json:"imageRegistry,omitempty"`
+	// CredentialsSecretRef is the name of the Secret in the system namespace
+	// holding the credentials of the registry. The Secret is used both to
+	// pull the charts and as the image pull secret of the components.
+	CredentialsSecretRef string `json:"credentialsSecretRef,omitempty"`
+	// CASecretRef is the name of the Secret in the system namespace holding
+	// the CA certificate of the registry under the ca.crt key.
+	CASecretRef string `json:"caSecretRef,omitempty"`
+	// Insecure allows connecting to the registry over plain HTTP.
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.webhookMinAvailable) || (has(self.replicas) && self.webhookMinAvailable < self.replicas)",message="webhookMinAvailable must be less than replicas"
+
+// HighAvailability defines the high availability settings of the KCM controller manager.
+type HighAvailability struct {
+	// LeaderElection defines the leader election timings of the replicas.
+	LeaderElection *LeaderElection `json:"leaderElection,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+
+	// Replicas is the number of the KCM controller manager replicas. Only the
+	// leader runs the controllers while all of the replicas serve the admission webhooks.
+	Replicas *int32 `json:"replicas,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+
+	// WebhookMinAvailable is the number of the replicas serving the admission
+	// webhooks kept available during the voluntary disruptions, e.g. node drains.
+	// If not set, no PodDisruptionBudget is created.
+	WebhookMinAvailable *int32 `json:"webhookMinAvailable,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.leaseDuration) || !has(self.renewDeadline) || duration(self.renewDeadline) < duration(self.leaseDuration)",message="renewDeadline must be less than leaseDuration"
+
+// LeaderElection defines the leader election timings.
+type LeaderElection struct {
+	// LeaseDuration is the duration the candidates wait before taking over
+	// the leadership of the non-renewed lease. Defaults to 15s.
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+	// RenewDeadline is the duration the leader retries to renew the lease
+	// before giving up the leadership. Defaults to 10s.
+	RenewDeadline *metav1.Duration `json:"renewDeadline,omitempty"`
+	// RetryPeriod is the duration the candidates wait between the attempts
+	// to acquire or renew the lease. Defaults to 2s.
+	RetryPeriod *metav1.Duration `json:"retryPeriod,omitempty"`
+}
+
+// ReleaseApproval is the approval mode of the upgrades to the Releases of the subscribed channel.
+type ReleaseApproval string
+
+const (
+	// ReleaseApprovalAutomatic upgrades to the newer Releases as soon as they are ready.
+	ReleaseApprovalAutomatic ReleaseApproval = "Automatic"
+	// ReleaseApprovalManual upgrades to the newer Releases once approved.
+	ReleaseApprovalManual ReleaseApproval = "Manual"
+)
+
+// ReleaseChannelSubscription defines the subscription of the Management to the release channel.
+type ReleaseChannelSubscription struct {
+	// +kubebuilder:validation:Enum=stable;rc;nightly
+
+	// Channel is the release channel the Management is subscribed to.
+	// The channel includes the Releases of the more stable channels,
+	// e.g. the rc one includes the stable Releases.
+	Channel ReleaseChannel `json:"channel"`
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	// +kubebuilder:default:=Automatic
+
+	// Approval is the approval mode of the upgrades. With the Manual one,
+	// the available Release is reported in the status and the upgrade is
+	// performed once the Management is annotated with the
+	// k0rdent.mirantis.com/approved-release annotation set to its name.
+	Approval ReleaseApproval `json:"approval,omitempty"`
+}
+
+const (
+	// AllComponentsHealthyReason surfaces overall readiness of Management's components.
+	AllComponentsHealthyReason = "AllComponentsHealthy"
+	// NotAllComponentsHealthyReason documents a condition not in Status=True because one or more components are failing.
+	NotAllComponentsHealthyReason = "NotAllComponentsHealthy"
+
+	// PreflightPassedCondition indicates whether the management cluster has
+	// passed the preflight checks of the upgrade to the requested Release.
+	PreflightPassedCondition = "PreflightPassed"
+	// PreflightChecksFailedReason documents the failed preflight checks blocking the upgrade.
+	PreflightChecksFailedReason = "PreflightChecksFailed"
+	// PreflightChecksSkippedReason documents the preflight checks skipped with the annotation.
+	PreflightChecksSkippedReason = "PreflightChecksSkipped"
+
+	// UpgradeApprovedCondition indicates whether the upgrade to the requested
+	// Release is approved after the review of its report.
+	UpgradeApprovedCondition = "UpgradeApproved"
+	// UpgradeApprovalPendingReason documents the upgrade reported and waiting for the approval.
+	UpgradeApprovalPendingReason = "UpgradeApprovalPending"
+)
+
+// Core represents a structure describing core Management components.
+type Core struct {
+	// KCM represents the core KCM component and references the KCM template.
+	KCM Component `json:"kcm,omitempty"`
+	// CAPI represents the core Cluster API component and references the Cluster API template.
+	CAPI Component `json:"capi,omitempty"`
+}
+
+// Component represents KCM management component
+type Component struct {
+	// Config allows to provide parameters for management component customization.
+	// If no Config provided, the field will be populated with the default
+	// values for the template.
+	Config *apiextensionsv1.JSON `json:"config,omitempty"`
+	// Template is the name of the Template associated with this component.
+	// If not specified, will be taken from the Release object.
+	Template string `json:"template,omitempty"`
+
+	// +listType=map
+	// +listMapKey=name
+
+	// Images lists the overrides of the container images of the rendered
+	// manifests of the component, e.g. to pull only some of the images from
+	// an internal mirror. The images are matched after the registry mirror
+	// is applied.
+	Images []ImageOverride `json:"images,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="has(self.newName) || has(self.newTag) || has(self.digest)",message="at least one of newName, newTag or digest must be set"
+
+// ImageOverride replaces the container image of a component.
+type ImageOverride struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Name is the name of the overridden image without the tag,
+	// e.g. registry.k8s.io/cluster-api/cluster-api-controller.
+	Name string `json:"name"`
+	// NewName replaces the name of the image, e.g. with the repository of the mirror.
+	NewName string `json:"newName,omitempty"`
+	// NewTag replaces the tag of the image.
+	NewTag string `json:"newTag,omitempty"`
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+
+	// Digest replaces the tag of the image with the digest, takes precedence over NewTag.
+	Digest string `json:"digest,omitempty"`
+}
+
+type Provider struct {
+	Component `json:",inline"`
+	// Name of the provider.
+	Name string `json:"name"`
+	// DependsOn lists the names of the other providers which are installed
+	// and ready before the provider is installed or upgraded. The providers
+	// are always installed after the core CAPI component.
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Disabled uninstalls the provider keeping it in the list along with
+	// its configuration. The provider cannot be disabled while it is in use
+	// by any ClusterDeployment.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// OptionalComponent is the name of the optional component of the KCM chart.
+// +kubebuilder:validation:Enum=velero
+type OptionalComponent string
+
+// OptionalComponentVelero is the Velero component the Management backups rely on.
+const OptionalComponentVelero OptionalComponent = "velero"
+
+// ImageVerification defines the policy of the verification of the cosign
+// signatures of the container images.
+type ImageVerification struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// SecretRef is the name of the Secret in the system namespace holding
+	// the PEM-encoded cosign public keys the images are signed with.
+	// Each data entry of the Secret may hold one or more keys.
+	SecretRef string `json:"secretRef"`
+
+	// IgnoredImages is the list of the image patterns, e.g. "docker.io/library/*",
+	// excluded from the verification.
+	IgnoredImages []string `json:"ignoredImages,omitempty"`
+
+	// Enforce refuses the installation of the components with unverified
+	// images. Otherwise the verification failures are only reported.
+	Enforce bool `json:"enforce,omitempty"`
+}
+
+// ProviderLifecycle is the way the CAPI providers are installed.
+type ProviderLifecycle string
+
+const (
+	// ProviderLifecycleHelm installs the providers with the HelmReleases.
+	ProviderLifecycleHelm ProviderLifecycle = "Helm"
+	// ProviderLifecycleCAPIOperator installs the providers with the Cluster
+	// API Operator provider objects managed by the Management directly.
+	ProviderLifecycleCAPIOperator ProviderLifecycle = "CAPIOperator"
+)
+
+// SecurityProfile is the name of the hardening profile of the workloads.
+type SecurityProfile string
+
+const (
+	// SecurityProfileBaseline enables the RuntimeDefault seccomp profile
+	// and forbids the privileged containers and the privilege escalation.
+	SecurityProfileBaseline SecurityProfile = "baseline"
+	// SecurityProfileRestricted in addition to the baseline profile requires
+	// the containers to run as non-root with the read-only root filesystem
+	// and all of the capabilities dropped.
+	SecurityProfileRestricted SecurityProfile = "restricted"
+)
+
+// NetworkPolicies configures the NetworkPolicies of the Management components.
+// The components are allowed to communicate with each other, to reach any
+// destination (e.g. the API server, registries and cloud APIs) and to be
+// reached on the admission webhook ports.
+type NetworkPolicies struct {
+	// IngressNamespaceSelector selects the namespaces, e.g. the monitoring one,
+	// additionally allowed to reach any port of the Management components.
+	IngressNamespaceSelector *metav1.LabelSelector `json:"ingressNamespaceSelector,omitempty"`
+
+	// +kubebuilder:validation:items:Minimum=1
+	// +kubebuilder:validation:items:Maximum=65535
+
+	// WebhookPorts is the list of the admission webhooks ports reachable from any source
+	// since the API server usually cannot be selected by namespace or pod selectors.
+	// Defaults to 9443 and 10250 used by KCM, CAPI providers and cert-manager.
+	WebhookPorts []int32 `json:"webhookPorts,omitempty"`
+}
+
+// ManagementStatus defines the observed state of Management
+type ManagementStatus struct {
+	// For each CAPI provider name holds its compatibility [contract versions]
+	// in a key-value pairs, where the key is the core CAPI contract version,
+	// and the value is an underscore-delimited (_) list of provider contract versions
+	// supported by the core CAPI.
+	//
+	// [contract versions]: https://cluster-api.sigs.k8s.io/developer/providers/contracts
+	CAPIContracts map[string]CompatibilityContracts `json:"capiContracts,omitempty"`
+	// Components indicates the status of installed KCM components and CAPI providers.
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=32
+
+	// Conditions represents the observations of a Management's current state.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Release indicates the current Release object.
+	Release string `json:"release,omitempty"`
+	// AvailableRelease is the newer Release of the subscribed channel
+	// that is pending the approval of the upgrade.
+	AvailableRelease string `json:"availableRelease,omitempty"`
+	// UpgradeReport is the report of the upgrade to the requested Release
+	// pending the approval, set if the upgrade dry-run is enabled.
+	UpgradeReport *UpgradeReport `json:"upgradeReport,omitempty"`
+	// CloudQuotas is the cloud quotas collected per Credential, set if the
+	// cloud quota collection is enabled.
+	CloudQuotas []CredentialCloudQuotas `json:"cloudQuotas,omitempty"`
+	// Fleet is the summary of the state of the ClusterDeployments of all
+	// of the namespaces and of their services.
+	Fleet *FleetSummary `json:"fleet,omitempty"`
+	// Standby is the state of the standby management cluster, set if the standby mode is enabled.
+	Standby *StandbyStatus `json:"standby,omitempty"`
+	// AvailableProviders holds all available CAPI providers.
+	AvailableProviders Providers `json:"availableProviders,omitempty"`
+	// ObservedGeneration is the last observed generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// CloudQuotaCollection defines the collection of the cloud quotas.
+type CloudQuotaCollection struct {
+	// Interval is the period the quotas are collected with. Defaults to 1h.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// Tracing defines the export of the OpenTelemetry traces.
+type Tracing struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Endpoint is the host:port of the OTLP gRPC collector the traces are exported to.
+	Endpoint string `json:"endpoint"`
+	// Insecure disables TLS of the connection to the collector.
+	Insecure bool `json:"insecure,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+
+	// SamplingPercentage is the percentage of the traced reconciliations.
+	// Defaults to 100.
+	SamplingPercentage *int32 `json:"samplingPercentage,omitempty"`
+}
+
+// NotificationEvent is the event the notification is sent about.
+type NotificationEvent string
+
+const (
+	// NotificationEventClusterDeploymentFailed is sent once the revision of
+	// the ClusterDeployment has failed to be applied.
+	NotificationEventClusterDeploymentFailed NotificationEvent = "ClusterDeploymentFailed"
+	// NotificationEventUpgradeSucceeded is sent once the cluster has been
+	// upgraded to the new ClusterTemplate.
+	NotificationEventUpgradeSucceeded NotificationEvent = "UpgradeSucceeded"
+	// NotificationEventBackupFailed is sent once the backup of the
+	// ManagementBackup has failed.
+	NotificationEventBackupFailed NotificationEvent = "BackupFailed"
+	// NotificationEventCredentialExpiring is sent once the Credential is
+	// about to expire or has expired.
+	NotificationEventCredentialExpiring NotificationEvent = "CredentialExpiring"
+)
+
+// Notifications defines the receivers of the notifications.
+type Notifications struct {
+	// CredentialExpiryThreshold is the period before the expiration of the
+	// Credential it is reported as expiring at. Defaults to 168h.
+	CredentialExpiryThreshold *metav1.Duration `json:"credentialExpiryThreshold,omitempty"`
+
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+
+	// Receivers lists the receivers the notifications are sent to.
+	Receivers []NotificationReceiver `json:"receivers"`
+}
+
+// NotificationReceiver defines where the notifications are sent to.
+// +kubebuilder:validation:XValidation:rule="[has(self.slack), has(self.webhook), has(self.email)].filter(x, x).size() == 1",message="exactly one of slack, webhook or email must be specified"
+type NotificationReceiver struct {
+	// Slack sends the notifications to the Slack incoming webhook.
+	Slack *SlackReceiver `json:"slack,omitempty"`
+	// Webhook posts the notifications as JSON to the generic webhook.
+	Webhook *WebhookReceiver `json:"webhook,omitempty"`
+	// Email sends the notifications via the SMTP server.
+	Email *EmailReceiver `json:"email,omitempty"`
+
+	// +kubebuilder:validation:MinLength=1
+
+	// Name of the receiver.
+	Name string `json:"name"`
+
+	// +listType=set
+	// +kubebuilder:validation:items:Enum=ClusterDeploymentFailed;UpgradeSucceeded;BackupFailed;CredentialExpiring
+
+	// Events lists the events sent to the receiver, all of them are sent if empty.
+	Events []NotificationEvent `json:"events,omitempty"`
+}
+
+// SlackReceiver defines the Slack incoming webhook.
+type SlackReceiver struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// URLSecretRef is the name of the Secret in the system namespace holding
+	// the URL of the incoming webhook under the url key.
+	URLSecretRef string `json:"urlSecretRef"`
+}
+
+// WebhookReceiver defines the generic webhook.
+type WebhookReceiver struct {
+	// +kubebuilder:validation:Pattern=`^https?://.+$`
+
+	// URL the notifications are posted to.
+	URL string `json:"url"`
+	// AuthorizationSecretRef is the name of the Secret in the system namespace
+	// holding the value of the Authorization header under the authorization key.
+	AuthorizationSecretRef string `json:"authorizationSecretRef,omitempty"`
+}
+
+// EmailReceiver defines the SMTP server and the recipients of the emails.
+type EmailReceiver struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Address is the host:port of the SMTP server.
+	Address string `json:"address"`
+
+	// +kubebuilder:validation:MinLength=1
+
+	// From is the address of the sender.
+	From string `json:"from"`
+
+	// +kubebuilder:validation:MinItems=1
+
+	// To lists the addresses of the recipients.
+	To []string `json:"to"`
+	// CredentialsSecretRef is the name of the Secret in the system namespace
+	// holding the username and password keys to authenticate to the SMTP server.
+	CredentialsSecretRef string `json:"credentialsSecretRef,omitempty"`
+}
+
+// CostEstimation defines the price lists and the budget of the ClusterDeployments.
+type CostEstimation struct {
+	// +kubebuilder:validation:Pattern=`^[0-9]+([.][0-9]+)?$`
+
+	// MonthlyBudget is the estimated monthly cost of a single ClusterDeployment
+	// above which its creation and update are warned about.
+	MonthlyBudget string `json:"monthlyBudget,omitempty"`
+
+	// +kubebuilder:default:=USD
+
+	// Currency of the prices.
+	Currency string `json:"currency,omitempty"`
+
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=provider
+
+	// PriceLists lists the prices of the instance types of the infrastructure providers.
+	PriceLists []PriceList `json:"priceLists"`
+}
+
+// PriceList defines the prices of the instance types of the infrastructure provider.
+type PriceList struct {
+	// +kubebuilder:validation:XValidation:rule="self.all(k, self[k].matches('^[0-9]+([.][0-9]+)?$'))",message="prices must be non-negative decimal numbers"
+
+	// HourlyPrices maps the instance types to their hourly prices.
+	HourlyPrices map[string]string `json:"hourlyPrices"`
+
+	// +kubebuilder:validation:MinLength=1
+
+	// Provider is the name of the infrastructure provider without the
+	// infrastructure- prefix, e.g. aws.
+	Provider string `json:"provider"`
+}
+
+// Observability defines the collector forwarding the telemetry of the
+// managed clusters and the backend it is forwarded to.
+type Observability struct {
+	// ClusterSelector selects the clusters the collector is deployed to
+	// the same way as the one of the MultiClusterService.
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// Backend is the OTLP backend the telemetry is forwarded to.
+	Backend ObservabilityBackend `json:"backend"`
+
+	// Template is the name of the ServiceTemplate of the collector in the
+	// system namespace. Defaults to the one shipped with the Release.
+	Template string `json:"template,omitempty"`
+
+	// DisableLogs stops the forwarding of the logs of the pods.
+	DisableLogs bool `json:"disableLogs,omitempty"`
+	// DisableMetrics stops the forwarding of the metrics of the nodes, pods
+	// and containers collected from the kubelets.
+	DisableMetrics bool `json:"disableMetrics,omitempty"`
+}
+
+// ObservabilityBackend defines the OTLP backend.
+type ObservabilityBackend struct {
+	// +kubebuilder:validation:Pattern=`^https?://`
+
+	// Endpoint is the URL of the OTLP/HTTP endpoint of the backend.
+	Endpoint string `json:"endpoint"`
+	// AuthorizationSecretRef is the name of the Secret in the system namespace
+	// holding the value of the Authorization header sent to the backend under
+	// the authorization key.
+	AuthorizationSecretRef string `json:"authorizationSecretRef,omitempty"`
+	// InsecureSkipVerify disables the verification of the certificate of the backend.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// FleetSummary is the roll-up of the state of the ClusterDeployments.
+type FleetSummary struct {
+	// ClustersByProvider is the number of the clusters per infrastructure
+	// provider of their ClusterTemplates, e.g. aws.
+	ClustersByProvider map[string]int32 `json:"clustersByProvider,omitempty"`
+	// ClustersByTemplate is the number of the clusters per ClusterTemplate
+	// they are deployed with, or are being deployed with if not deployed yet.
+	ClustersByTemplate map[string]int32 `json:"clustersByTemplate,omitempty"`
+	// ClustersByPhase is the number of the clusters per phase, one of
+	// Provisioning, Upgrading, Ready, Failed or Deleting.
+	ClustersByPhase map[string]int32 `json:"clustersByPhase,omitempty"`
+	// Clusters is the total number of the clusters.
+	Clusters int32 `json:"clusters"`
+	// UnreachableClusters is the number of the clusters the API servers of
+	// which are not reachable or not healthy.
+	UnreachableClusters int32 `json:"unreachableClusters"`
+	// UpgradesInProgress is the number of the clusters being upgraded to
+	// another ClusterTemplate.
+	UpgradesInProgress int32 `json:"upgradesInProgress"`
+	// Services is the total number of the services of the clusters.
+	Services int32 `json:"services"`
+	// ServicesPending is the number of the services not deployed yet or
+	// failed to be deployed.
+	ServicesPending int32 `json:"servicesPending"`
+}
+
+// CredentialCloudQuotas is the cloud quotas of the account a Credential gives access to.
+type CredentialCloudQuotas struct {
+	// LastCollectionTime is the time the quotas were collected at.
+	LastCollectionTime metav1.Time `json:"lastCollectionTime"`
+	// Credential is the Credential in the namespace/name format.
+	Credential string `json:"credential"`
+	// Provider is the name of the cloud provider the quotas are collected from.
+	Provider string `json:"provider"`
+	// Error is the error of the last collection, if any.
+	Error string `json:"error,omitempty"`
+	// Quotas lists the quotas of the cloud resources.
+	Quotas []CloudQuota `json:"quotas,omitempty"`
+}
+
+// CloudQuota is the quota of a cloud resource.
+type CloudQuota struct {
+	// Resource is the name of the cloud resource.
+	Resource CloudQuotaResource `json:"resource"`
+	// Limit is the maximum amount of the resource, -1 if unlimited.
+	Limit int64 `json:"limit"`
+	// Used is the amount of the resource in use.
+	Used int64 `json:"used"`
+	// Remaining is the amount of the resource left, -1 if unlimited.
+	Remaining int64 `json:"remaining"`
+}
+
+// CloudQuotaResource is the name of the cloud resource a quota is set for.
+type CloudQuotaResource string
+
+const (
+	// CloudQuotaInstances is the number of the virtual machines.
+	CloudQuotaInstances CloudQuotaResource = "instances"
+	// CloudQuotaVCPUs is the number of the virtual CPUs.
+	CloudQuotaVCPUs CloudQuotaResource = "vcpus"
+	// CloudQuotaPublicIPs is the number of the public (floating or elastic) IPs.
+	CloudQuotaPublicIPs CloudQuotaResource = "publicIPs"
+	// CloudQuotaLoadBalancers is the number of the load balancers.
+	CloudQuotaLoadBalancers CloudQuotaResource = "loadBalancers"
+)
+
+// UpgradeReport is the report of the changes made by the Release upgrade.
+type UpgradeReport struct {
+	// Release is the name of the Release the upgrade is reported to.
+	Release string `json:"release"`
+	// Components lists the components changed by the upgrade.
+	Components []ComponentChange `json:"components,omitempty"`
+	// CRDs lists the changes of the CustomResourceDefinitions installed by
+	// the charts of the changed components.
+	CRDs []CRDChange `json:"crds,omitempty"`
+	// AffectedClusters lists the ClusterDeployments relying on the providers
+	// of the changed components in the namespace/name format.
+	AffectedClusters []string `json:"affectedClusters,omitempty"`
+}
+
+// ComponentChange is the change of the Management component made by the Release upgrade.
+type ComponentChange struct {
+	// Name is the name of the component.
+	Name string `json:"name"`
+	// FromTemplate is the name of the installed ProviderTemplate of the
+	// component, empty if the component is installed by the upgrade.
+	FromTemplate string `json:"fromTemplate,omitempty"`
+	// ToTemplate is the name of the ProviderTemplate the component is
+	// upgraded to, empty if the component is removed by the upgrade.
+	ToTemplate string `json:"toTemplate,omitempty"`
+	// FromChartVersion is the version of the installed chart of the component.
+	FromChartVersion string `json:"fromChartVersion,omitempty"`
+	// ToChartVersion is the version of the chart the component is upgraded to.
+	ToChartVersion string `json:"toChartVersion,omitempty"`
+}
+
+// CRDChange is the change of the CustomResourceDefinition made by the Release upgrade.
+type CRDChange struct {
+	// Name is the name of the CustomResourceDefinition.
+	Name string `json:"name"`
+	// Component is the name of the component installing the CustomResourceDefinition.
+	Component string `json:"component"`
+	// AddedVersions lists the versions added by the upgrade.
+	AddedVersions []string `json:"addedVersions,omitempty"`
+	// RemovedVersions lists the versions removed by the upgrade.
+	RemovedVersions []string `json:"removedVersions,omitempty"`
+	// Added is whether the CustomResourceDefinition is installed by the upgrade.
+	Added bool `json:"added,omitempty"`
+}
+
+// ComponentStatus is the status of Management component installation
+type ComponentStatus struct {
+	// ReadySince is the time the component has become ready at.
+	ReadySince *metav1.Time `json:"readySince,omitempty"`
+	// LastErrorTime is the time the last error has been observed at.
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+	// Template is the name of the Template associated with this component.
+	Template string `json:"template,omitempty"`
+	// Version is the version of the chart installed by the component HelmRelease.
+	Version string `json:"version,omitempty"`
+	// Error stores as error message in case of failed installation
+	Error string `json:"error,omitempty"`
+	// LastError is the last error of the component, preserved after it has recovered.
+	LastError string `json:"lastError,omitempty"`
+	// Conditions are the conditions of the component HelmRelease.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Success represents if a component installation was successful
+	Success bool `json:"success,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=kcm-mgmt;mgmt,scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status",description="Overall readiness of the Management resource"
+// +kubebuilder:printcolumn:name="Release",type="string",JSONPath=".status.release",description="Current release version"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of Management"
+
+// Management is the Schema for the managements API
+type Management struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ManagementSpec   `json:"spec,omitempty"`
+	Status ManagementStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ManagementList contains a list of Management
+type ManagementList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Management `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Management{}, &ManagementList{})
+}
@@ -0,0 +1,116 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RegionKind is the string representation of a Region.
+	RegionKind = "Region"
+	// RegionFinalizer is the finalizer of the Region uninstalling its KCM instance.
+	RegionFinalizer = "k0rdent.mirantis.com/region"
+
+	// RegionKCMInstalledCondition indicates whether the KCM instance is installed
+	// to the regional cluster.
+	RegionKCMInstalledCondition = "KCMInstalled"
+	// RegionObjectsDistributedCondition indicates whether the templates and the
+	// credentials are distributed to the regional cluster.
+	RegionObjectsDistributedCondition = "ObjectsDistributed"
+)
+
+// +kubebuilder:validation:XValidation:rule="has(self.kubeConfigSecretRef) != has(self.clusterDeployment)",message="exactly one of spec.kubeConfigSecretRef or spec.clusterDeployment must be set"
+
+// RegionSpec defines the regional management cluster and the objects
+// distributed to it.
+type RegionSpec struct {
+	// KubeConfigSecretRef is the name of the Secret in the system namespace
+	// holding the kubeconfig of the enrolled regional cluster under the "value" key.
+	// Mutually exclusive with ClusterDeployment.
+	KubeConfigSecretRef string `json:"kubeConfigSecretRef,omitempty"`
+	// ClusterDeployment is the name of the ClusterDeployment in the system
+	// namespace the regional cluster is deployed with.
+	// Mutually exclusive with KubeConfigSecretRef.
+	ClusterDeployment string `json:"clusterDeployment,omitempty"`
+
+	// Config is the values of the KCM chart installed to the regional cluster.
+	// The chart is taken from the KCM template of the Management.
+	Config *apiextensionsv1.JSON `json:"config,omitempty"`
+
+	// ClusterTemplates lists the names of the ClusterTemplates in the system
+	// namespace distributed to the system namespace of the regional cluster.
+	ClusterTemplates []string `json:"clusterTemplates,omitempty"`
+	// ServiceTemplates lists the names of the ServiceTemplates in the system
+	// namespace distributed to the system namespace of the regional cluster.
+	ServiceTemplates []string `json:"serviceTemplates,omitempty"`
+	// Credentials lists the names of the Credentials in the system namespace
+	// distributed to the system namespace of the regional cluster along with
+	// their identity objects and the Secrets the identities reference.
+	Credentials []string `json:"credentials,omitempty"`
+}
+
+// RegionClusterDeployment is the ClusterDeployment of the regional cluster.
+type RegionClusterDeployment struct {
+	// Namespace of the ClusterDeployment.
+	Namespace string `json:"namespace"`
+	// Name of the ClusterDeployment.
+	Name string `json:"name"`
+	// Template is the ClusterTemplate the ClusterDeployment is deployed with.
+	Template string `json:"template"`
+	// Ready is whether the ClusterDeployment is ready.
+	Ready bool `json:"ready"`
+}
+
+// RegionStatus defines the observed state of Region
+type RegionStatus struct {
+	// ClusterDeployments is the inventory of the ClusterDeployments managed
+	// by the regional KCM instance.
+	ClusterDeployments []RegionClusterDeployment `json:"clusterDeployments,omitempty"`
+	// Conditions contains details for the current state of the Region.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the last observed generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Installed",type=string,JSONPath=`.status.conditions[?(@.type=="KCMInstalled")].status`,description="Whether the KCM instance is installed"
+// +kubebuilder:printcolumn:name="Distributed",type=string,JSONPath=`.status.conditions[?(@.type=="ObjectsDistributed")].status`,description="Whether the objects are distributed"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Time elapsed since object creation"
+
+// Region is the Schema for the regions API
+type Region struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RegionSpec   `json:"spec,omitempty"`
+	Status RegionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RegionList contains a list of Region
+type RegionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Region `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Region{}, &RegionList{})
+}
@@ -0,0 +1,149 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ReleaseKind = "Release"
+
+	// TemplatesCreatedCondition indicates that all templates associated with the Release are created.
+	TemplatesCreatedCondition = "TemplatesCreated"
+	// TemplatesValidCondition indicates that all templates associated with the Release are valid.
+	TemplatesValidCondition = "TemplatesValid"
+
+	// ReleaseChangesAnnotation is the annotation of the KCM chart listing
+	// the changes of the Release as a YAML list of the ReleaseChange objects.
+	ReleaseChangesAnnotation = "k0rdent.mirantis.com/changes"
+	// ReleaseUpgradeStepsAnnotation is the annotation of the KCM chart listing
+	// the manual steps required by the upgrade to the Release as a YAML list of strings.
+	ReleaseUpgradeStepsAnnotation = "k0rdent.mirantis.com/upgrade-steps"
+)
+
+// ReleaseChangeKind is the kind of the change of the Release.
+type ReleaseChangeKind string
+
+const (
+	ReleaseChangeAdded      ReleaseChangeKind = "added"
+	ReleaseChangeChanged    ReleaseChangeKind = "changed"
+	ReleaseChangeDeprecated ReleaseChangeKind = "deprecated"
+	ReleaseChangeRemoved    ReleaseChangeKind = "removed"
+	ReleaseChangeFixed      ReleaseChangeKind = "fixed"
+	ReleaseChangeSecurity   ReleaseChangeKind = "security"
+)
+
+// ReleaseChannel is the channel a Release is published to.
+type ReleaseChannel string
+
+const (
+	// ReleaseChannelStable is the channel of the generally available Releases.
+	ReleaseChannelStable ReleaseChannel = "stable"
+	// ReleaseChannelRC is the channel of the release candidates.
+	ReleaseChannelRC ReleaseChannel = "rc"
+	// ReleaseChannelNightly is the channel of the nightly builds.
+	ReleaseChannelNightly ReleaseChannel = "nightly"
+)
+
+// ReleaseSpec defines the desired state of Release
+type ReleaseSpec struct {
+	// Version of the KCM Release in the semver format.
+	Version string `json:"version"`
+	// KCM references the KCM template.
+	KCM CoreProviderTemplate `json:"kcm"`
+	// CAPI references the Cluster API template.
+	CAPI CoreProviderTemplate `json:"capi"`
+	// +kubebuilder:validation:Enum=stable;rc;nightly
+	// +kubebuilder:default:=stable
+
+	// Channel is the release channel the Release is published to.
+	Channel ReleaseChannel `json:"channel,omitempty"`
+	// Providers contains a list of Providers associated with the Release.
+	Providers []NamedProviderTemplate `json:"providers,omitempty"`
+}
+
+type CoreProviderTemplate struct {
+	// Template references the Template associated with the provider.
+	Template string `json:"template"`
+}
+
+type NamedProviderTemplate struct {
+	CoreProviderTemplate `json:",inline"`
+	// Name of the provider.
+	Name string `json:"name"`
+}
+
+// ReleaseChange is the change introduced by the Release.
+type ReleaseChange struct {
+	// +kubebuilder:validation:Enum=added;changed;deprecated;removed;fixed;security
+
+	// Kind of the change.
+	Kind ReleaseChangeKind `json:"kind"`
+	// Description of the change.
+	Description string `json:"description"`
+	// Breaking indicates whether the change is not backward compatible.
+	Breaking bool `json:"breaking,omitempty"`
+}
+
+// ReleaseNotes describes the changes of the Release and its upgrade.
+type ReleaseNotes struct {
+	// ChartVersion is the version of the KCM chart the notes are fetched from.
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// Changes is the changelog of the Release.
+	Changes []ReleaseChange `json:"changes,omitempty"`
+	// UpgradeSteps lists the manual steps required by the upgrade to the Release.
+	UpgradeSteps []string `json:"upgradeSteps,omitempty"`
+	// Breaking indicates whether any of the changes is not backward compatible.
+	Breaking bool `json:"breaking,omitempty"`
+}
+
+// ReleaseStatus defines the observed state of Release
+type ReleaseStatus struct {
+	// Notes are the release notes fetched from the KCM chart of the Release.
+	Notes *ReleaseNotes `json:"notes,omitempty"`
+	// Conditions contains details for the current state of the Release
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the last observed generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Ready indicates whether KCM is ready to be upgraded to this Release.
+	Ready bool `json:"ready,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// Release is the Schema for the releases API
+type Release struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReleaseSpec   `json:"spec,omitempty"`
+	Status ReleaseStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReleaseList contains a list of Release
+type ReleaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Release `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Release{}, &ReleaseList{})
+}
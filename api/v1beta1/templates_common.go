@@ -0,0 +1,69 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	helmcontrollerv2 "github.com/fluxcd/helm-controller/api/v2"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// +kubebuilder:validation:XValidation:rule="(has(self.chartSpec) && !has(self.chartRef)) || (!has(self.chartSpec) && has(self.chartRef))", message="either chartSpec or chartRef must be set"
+
+// HelmSpec references a Helm chart representing the KCM template
+type HelmSpec struct {
+	// ChartSpec defines the desired state of the HelmChart to be created by the controller
+	ChartSpec *sourcev1.HelmChartSpec `json:"chartSpec,omitempty"`
+
+	// ChartRef is a reference to a source controller resource containing the
+	// Helm chart representing the template.
+	ChartRef *helmcontrollerv2.CrossNamespaceSourceReference `json:"chartRef,omitempty"`
+}
+
+// TemplateStatusCommon defines the observed state of Template common for all Template types
+type TemplateStatusCommon struct {
+	// Config demonstrates available parameters for template customization,
+	// that can be used when creating ClusterDeployment objects.
+	Config *apiextensionsv1.JSON `json:"config,omitempty"`
+	// ChartRef is a reference to a source controller resource containing the
+	// Helm chart representing the template.
+	ChartRef *helmcontrollerv2.CrossNamespaceSourceReference `json:"chartRef,omitempty"`
+	// ChartVersion represents the version of the Helm Chart associated with this template.
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// Description contains information about the template.
+	Description string `json:"description,omitempty"`
+
+	TemplateValidationStatus `json:",inline"`
+
+	// ObservedGeneration is the last observed generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+type TemplateValidationStatus struct {
+	// ValidationError provides information regarding issues encountered during template validation.
+	ValidationError string `json:"validationError,omitempty"`
+	// Valid indicates whether the template passed validation or not.
+	Valid bool `json:"valid"`
+}
+
+// TemplateUsage is the number of the objects referencing the template.
+type TemplateUsage struct {
+	// ClusterDeployments is the number of the ClusterDeployments referencing
+	// the template.
+	ClusterDeployments int32 `json:"clusterDeployments"`
+	// MultiClusterServices is the number of the MultiClusterServices
+	// referencing the template.
+	MultiClusterServices int32 `json:"multiClusterServices,omitempty"`
+}
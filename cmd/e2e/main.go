@@ -0,0 +1,79 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The e2e command runs the compiled e2e suite against the existing management
+// cluster with kcm installed, so the conformance of the own deployments of kcm
+// is checked without the Makefile of the project.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/K0rdent/kcm/test/e2e/runner"
+)
+
+func main() {
+	if err := run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	defaultSuite, err := runner.DefaultSuite()
+	if err != nil {
+		return err
+	}
+
+	// the flags of Ginkgo are registered on the default flag set by the
+	// packages of the suite, so the own flag set is used
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	var (
+		opts      = runner.Options{Stdout: os.Stdout, Stderr: os.Stderr}
+		providers string
+	)
+	flags.StringVar(&providers, "providers", "", "Comma-separated list of the providers the specs of which are run, any of aws, azure, vsphere, adopted, remote, docker and in-memory. All of the specs are run if empty.")
+	flags.StringVar(&opts.LabelFilter, "label-filter", "", "Ginkgo label filter of the specs run in addition to the providers, e.g. 'type:hosted'.")
+	flags.StringVar(&opts.ConfigFile, "config", "", "Path to the testing configuration, the default one is used if empty.")
+	flags.StringVar(&opts.ArtifactsDir, "artifacts-dir", "e2e-report", "Directory the reports, the logs and the resource dumps are written to.")
+	flags.StringVar(&opts.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig of the management cluster, the KUBECONFIG env var or the default one is used if empty.")
+	flags.StringVar(&opts.Suite, "suite", defaultSuite, "Path to the compiled e2e suite.")
+	flags.DurationVar(&opts.Timeout, "timeout", 3*time.Hour, "Timeout of the whole run.")
+	flags.BoolVar(&opts.NoCleanup, "no-cleanup", false, "Keep the clusters and the namespaces of the specs.")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "Only list the specs which would be run.")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		return err
+	}
+	if providers != "" {
+		opts.Providers = strings.Split(providers, ",")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	return runner.Run(ctx, opts)
+}
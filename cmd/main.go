@@ -15,11 +15,17 @@
 package main
 
 import (
+	"context"
+	"crypto/fips140"
 	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	goruntime "runtime"
 	"strings"
+	"time"
 
 	hcv2 "github.com/fluxcd/helm-controller/api/v2"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
@@ -37,17 +43,27 @@ import (
 	capioperatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	kcmv1beta1 "github.com/K0rdent/kcm/api/v1beta1"
+	"github.com/K0rdent/kcm/internal/audit"
 	"github.com/K0rdent/kcm/internal/build"
+	"github.com/K0rdent/kcm/internal/cloudapi"
 	"github.com/K0rdent/kcm/internal/controller"
 	"github.com/K0rdent/kcm/internal/helm"
+	kcmmetrics "github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/internal/notifications"
 	"github.com/K0rdent/kcm/internal/providers"
+	"github.com/K0rdent/kcm/internal/record"
+	"github.com/K0rdent/kcm/internal/sharding"
 	"github.com/K0rdent/kcm/internal/telemetry"
+	"github.com/K0rdent/kcm/internal/tracing"
 	"github.com/K0rdent/kcm/internal/utils"
 	kcmwebhook "github.com/K0rdent/kcm/internal/webhook"
 )
@@ -70,6 +86,7 @@ func init() {
 	// velero deps
 
 	utilruntime.Must(kcmv1.AddToScheme(scheme))
+	utilruntime.Must(kcmv1beta1.AddToScheme(scheme))
 	utilruntime.Must(sourcev1.AddToScheme(scheme))
 	utilruntime.Must(sourcev1beta2.AddToScheme(scheme))
 	utilruntime.Must(hcv2.AddToScheme(scheme))
@@ -81,30 +98,53 @@ func init() {
 
 func main() {
 	var (
-		metricsAddr                string
-		probeAddr                  string
-		secureMetrics              bool
-		enableHTTP2                bool
-		defaultRegistryURL         string
-		insecureRegistry           bool
-		registryCredentialsSecret  string
-		createManagement           bool
-		createAccessManagement     bool
-		createRelease              bool
-		createTemplates            bool
-		validateClusterUpgradePath bool
-		kcmTemplatesChartName      string
-		enableTelemetry            bool
-		enableWebhook              bool
-		webhookPort                int
-		webhookCertDir             string
-		pprofBindAddress           string
-		leaderElectionNamespace    string
+		metricsAddr                    string
+		probeAddr                      string
+		secureMetrics                  bool
+		enableHTTP2                    bool
+		defaultRegistryURL             string
+		insecureRegistry               bool
+		registryCredentialsSecret      string
+		createManagement               bool
+		createAccessManagement         bool
+		createRelease                  bool
+		createTemplates                bool
+		validateClusterUpgradePath     bool
+		kcmTemplatesChartName          string
+		enableTelemetry                bool
+		enableStorageMigration         bool
+		enableStateMetrics             bool
+		enableWebhook                  bool
+		webhookPort                    int
+		webhookCertDir                 string
+		pprofBindAddress               string
+		blockProfileRate               int
+		mutexProfileFraction           int
+		leaderElectionNamespace        string
+		leaseDuration                  time.Duration
+		renewDeadline                  time.Duration
+		retryPeriod                    time.Duration
+		requireFIPS                    bool
+		auditRetention                 time.Duration
+		healthProbeInterval            time.Duration
+		clusterDeploymentConcurrency   int
+		multiClusterServiceConcurrency int
+		clusterDeploymentShards        int
+		syncPeriod                     time.Duration
+		clusterDeploymentRequeue       time.Duration
+		managementRequeue              time.Duration
+		templateRequeue                time.Duration
+		chartCacheDir                  string
+		chartCacheSizeLimit            int64
+		cloudAPIOptions                = cloudapi.DefaultOptions
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "", "The namespace to use for leader election.")
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second, "The duration the candidates wait before taking over the leadership of the non-renewed lease.")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second, "The duration the leader retries to renew the lease before giving up the leadership.")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second, "The duration the candidates wait between the attempts to acquire or renew the lease.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", false,
 		"If set the metrics endpoint is served securely")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
@@ -122,12 +162,32 @@ func main() {
 	flag.BoolVar(&validateClusterUpgradePath, "validate-cluster-upgrade-path", true, "Specifies whether the ClusterDeployment upgrade path should be validated.")
 	flag.StringVar(&kcmTemplatesChartName, "kcm-templates-chart-name", "kcm-templates",
 		"The name of the helm chart with KCM Templates.")
-	flag.BoolVar(&enableTelemetry, "enable-telemetry", true, "Collect and send telemetry data.")
+	flag.BoolVar(&enableTelemetry, "enable-telemetry", true, "Collect and send telemetry data according to the telemetry policy of the Management, false disables the periodic heartbeats regardless of it.")
+	flag.BoolVar(&enableStorageMigration, "enable-storage-migration", true, "Migrate the objects of the kcm and CAPI CRDs stored in several versions to the storage version.")
+	flag.BoolVar(&enableStateMetrics, "enable-state-metrics", true, "Export the fields and the conditions of the ClusterDeployments, Credentials, Releases and MultiClusterServices as metrics.")
 	flag.BoolVar(&enableWebhook, "enable-webhook", true, "Enable admission webhook.")
 	flag.IntVar(&webhookPort, "webhook-port", 9443, "Admission webhook port.")
 	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs/",
 		"Webhook cert dir, only used when webhook-port is specified.")
 	flag.StringVar(&pprofBindAddress, "pprof-bind-address", "", "The TCP address that the controller should bind to for serving pprof, \"0\" or empty value disables pprof")
+	flag.IntVar(&blockProfileRate, "block-profile-rate", 0, "The rate of the sampling of the blocking events in the block profile in nanoseconds, 0 disables the block profiling.")
+	flag.IntVar(&mutexProfileFraction, "mutex-profile-fraction", 0, "The fraction of the mutex contention events sampled in the mutex profile, 0 disables the mutex profiling.")
+	flag.DurationVar(&auditRetention, "audit-retention", 30*24*time.Hour, "The period the AuditEvent objects are kept for, 0 disables pruning of the audit trail.")
+	flag.DurationVar(&healthProbeInterval, "cluster-health-probe-interval", time.Minute, "The interval between the probes of the API servers of the managed clusters, 0 disables the probing.")
+	flag.IntVar(&clusterDeploymentConcurrency, "clusterdeployment-concurrency", 1, "The number of the ClusterDeployments reconciled concurrently.")
+	flag.IntVar(&multiClusterServiceConcurrency, "multiclusterservice-concurrency", 1, "The number of the MultiClusterServices reconciled concurrently.")
+	flag.IntVar(&clusterDeploymentShards, "clusterdeployment-shards", 1, "The number of the shards the ClusterDeployments are split into, each reconciled by the replica holding the lease of the shard, 1 reconciles all of them by the leader.")
+	flag.DurationVar(&syncPeriod, "sync-period", 10*time.Hour, "The interval all of the watched objects are reconciled at regardless of their changes.")
+	flag.DurationVar(&clusterDeploymentRequeue, "clusterdeployment-requeue-interval", 10*time.Second, "The interval the ClusterDeployments are requeued at until they are ready.")
+	flag.DurationVar(&managementRequeue, "management-requeue-interval", 10*time.Second, "The interval the Management is requeued at until it is ready.")
+	flag.DurationVar(&templateRequeue, "template-requeue-interval", time.Minute, "The interval the templates are requeued at to be revalidated.")
+	flag.StringVar(&chartCacheDir, "chart-cache-dir", filepath.Join(os.TempDir(), "kcm-charts"), "The directory the downloaded Helm chart archives are cached in.")
+	flag.Int64Var(&chartCacheSizeLimit, "chart-cache-size-limit", 512, "The limit of the total size of the cached Helm chart archives in MiB, 0 disables the caching.")
+	flag.Float64Var(&cloudAPIOptions.QPS, "cloud-api-qps", cloudapi.DefaultOptions.QPS, "The maximum average rate of the direct calls of the cloud APIs per Credential, 0 disables the rate limiting.")
+	flag.IntVar(&cloudAPIOptions.Burst, "cloud-api-burst", cloudapi.DefaultOptions.Burst, "The maximum number of the direct calls of the cloud APIs per Credential made at once.")
+	flag.IntVar(&cloudAPIOptions.FailureThreshold, "cloud-api-failure-threshold", cloudapi.DefaultOptions.FailureThreshold, "The number of the consecutive failures of the calls of the cloud APIs per Credential the calls are suspended after, 0 disables the suspension.")
+	flag.DurationVar(&cloudAPIOptions.OpenDuration, "cloud-api-suspension-duration", cloudapi.DefaultOptions.OpenDuration, "The duration the calls of the cloud APIs per Credential are suspended for after the repeated failures.")
+	flag.BoolVar(&requireFIPS, "require-fips", false, "Refuse to start if the FIPS 140-3 mode of the Go cryptographic module is not enabled.")
 
 	opts := zap.Options{
 		Development: true,
@@ -155,6 +215,14 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if requireFIPS && !fips140.Enabled() {
+		setupLog.Error(errors.New("FIPS 140-3 mode is not enabled"), "the controller is required to run in the FIPS mode, run the image built with GOFIPS140")
+		os.Exit(1)
+	}
+
+	goruntime.SetBlockProfileRate(blockProfileRate)
+	goruntime.SetMutexProfileFraction(mutexProfileFraction)
+
 	determinedRepositoryType, err := utils.DetermineDefaultRepositoryType(defaultRegistryURL)
 	if err != nil {
 		setupLog.Error(err, "failed to determine default repository type")
@@ -188,6 +256,9 @@ func main() {
 		LeaderElection:          true,
 		LeaderElectionID:        "31c555b4.k0rdent.mirantis.com",
 		LeaderElectionNamespace: leaderElectionNamespace,
+		LeaseDuration:           &leaseDuration,
+		RenewDeadline:           &renewDeadline,
+		RetryPeriod:             &retryPeriod,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -203,16 +274,23 @@ func main() {
 		PprofBindAddress: pprofBindAddress,
 
 		Cache: cache.Options{
+			SyncPeriod:       &syncPeriod,
 			DefaultTransform: cache.TransformStripManagedFields(),
+			ByObject:         utils.CacheByObject(),
+		},
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: utils.UncachedObjects(),
+			},
 		},
 	}
 
 	if enableWebhook {
-		managerOpts.WebhookServer = webhook.NewServer(webhook.Options{
+		managerOpts.WebhookServer = kcmwebhook.NewInstrumentedServer(webhook.NewServer(webhook.Options{
 			Port:    webhookPort,
 			TLSOpts: tlsOpts,
 			CertDir: webhookCertDir,
-		})
+		}))
 	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), managerOpts)
@@ -221,6 +299,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	record.InitFromRecorder(mgr.GetEventRecorderFor("kcm-controller-manager"))
+
+	if chartCacheSizeLimit > 0 {
+		chartCache, err := helm.NewChartCache(chartCacheDir, chartCacheSizeLimit<<20)
+		if err != nil {
+			setupLog.Error(err, "unable to create chart cache")
+			os.Exit(1)
+		}
+		helm.SetChartCache(chartCache)
+	}
+
+	if enableStateMetrics {
+		ctrlmetrics.Registry.MustRegister(kcmmetrics.NewStateCollector(mgr.GetClient()))
+	}
+
 	ctx := ctrl.SetupSignalHandler()
 	if err = kcmv1.SetupIndexers(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to setup indexers")
@@ -228,11 +321,49 @@ func main() {
 	}
 
 	currentNamespace := utils.CurrentNamespace()
+	notifier := &notifications.Notifier{Client: mgr.GetClient(), SystemNamespace: currentNamespace}
+
+	if clusterDeploymentShards > 1 {
+		shardLeaseNamespace := leaderElectionNamespace
+		if shardLeaseNamespace == "" {
+			shardLeaseNamespace = currentNamespace
+		}
+		sharder, err := sharding.New(mgr.GetConfig(), sharding.Options{
+			Namespace:     shardLeaseNamespace,
+			LeaseName:     "kcm-clusterdeployment-shard",
+			Shards:        clusterDeploymentShards,
+			LeaseDuration: leaseDuration,
+			RenewDeadline: renewDeadline,
+			RetryPeriod:   retryPeriod,
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to create ClusterDeployment sharder")
+			os.Exit(1)
+		}
+		if err = mgr.Add(sharder); err != nil {
+			setupLog.Error(err, "unable to add ClusterDeployment sharder")
+			os.Exit(1)
+		}
+		if err = mgr.Add(&controller.ShardedClusterDeploymentController{
+			Manager: mgr,
+			Reconciler: &controller.ClusterDeploymentReconciler{
+				Notifier:                notifier,
+				SystemNamespace:         currentNamespace,
+				MaxConcurrentReconciles: clusterDeploymentConcurrency,
+				RequeueInterval:         clusterDeploymentRequeue,
+				Sharder:                 sharder,
+			},
+		}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterDeployment")
+			os.Exit(1)
+		}
+	}
 
 	templateReconciler := controller.TemplateReconciler{
 		Client:           mgr.GetClient(),
 		CreateManagement: createManagement,
 		SystemNamespace:  currentNamespace,
+		RequeueInterval:  templateRequeue,
 		DefaultRegistryConfig: helm.DefaultRegistryConfig{
 			URL:               defaultRegistryURL,
 			RepoType:          determinedRepositoryType,
@@ -262,6 +393,23 @@ func main() {
 	if err = (&controller.ManagementReconciler{
 		SystemNamespace:        currentNamespace,
 		CreateAccessManagement: createAccessManagement,
+		DefaultRegistryConfig: helm.DefaultRegistryConfig{
+			URL:               defaultRegistryURL,
+			RepoType:          determinedRepositoryType,
+			CredentialsSecret: registryCredentialsSecret,
+			Insecure:          insecureRegistry,
+		},
+		AuditRecorder: &audit.Recorder{
+			Client:          mgr.GetClient(),
+			SystemNamespace: currentNamespace,
+			Controller:      "management",
+		},
+		Notifier:                         notifier,
+		ClusterDeploymentConcurrency:     clusterDeploymentConcurrency,
+		MultiClusterServiceConcurrency:   multiClusterServiceConcurrency,
+		ShardedClusterDeployments:        clusterDeploymentShards > 1,
+		RequeueInterval:                  managementRequeue,
+		ClusterDeploymentRequeueInterval: clusterDeploymentRequeue,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Management")
 		os.Exit(1)
@@ -309,6 +457,10 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Release")
 		os.Exit(1)
 	}
+	if err = (&controller.ReleaseChannelReconciler{}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ReleaseChannel")
+		os.Exit(1)
+	}
 
 	if enableTelemetry {
 		if err = mgr.Add(&telemetry.Tracker{
@@ -323,18 +475,104 @@ func main() {
 	if err = (&controller.CredentialReconciler{
 		SystemNamespace: currentNamespace,
 		Client:          mgr.GetClient(),
+		Notifier:        notifier,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Credential")
 		os.Exit(1)
 	}
 
+	if err = (&controller.ClusterQuotaReconciler{}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterQuota")
+		os.Exit(1)
+	}
+
+	if err = (&controller.ComplianceReconciler{}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Compliance")
+		os.Exit(1)
+	}
+	if err = (&controller.CloudQuotaReconciler{
+		Limiter: cloudapi.NewLimiter(cloudAPIOptions),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CloudQuota")
+		os.Exit(1)
+	}
+	if err = (&controller.FleetSummaryReconciler{}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FleetSummary")
+		os.Exit(1)
+	}
+	if err = (&controller.ClusterBackupReconciler{SystemNamespace: currentNamespace}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterBackup")
+		os.Exit(1)
+	}
+	if err = (&controller.EtcdBackupReconciler{}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "EtcdBackup")
+		os.Exit(1)
+	}
+	if err = (&controller.TemplateUsageReconciler{SystemNamespace: currentNamespace}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TemplateUsage")
+		os.Exit(1)
+	}
+	if err = (&controller.DiagnosticsReconciler{}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Diagnostics")
+		os.Exit(1)
+	}
+	if enableStorageMigration {
+		if err = (&controller.StorageMigrationReconciler{}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "StorageMigration")
+			os.Exit(1)
+		}
+	}
+	if err = (&controller.CertificatesReconciler{}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Certificates")
+		os.Exit(1)
+	}
+	if healthProbeInterval > 0 {
+		if err = (&controller.HealthReconciler{
+			ProbeInterval: healthProbeInterval,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Health")
+			os.Exit(1)
+		}
+	}
+	if err = (&controller.RegionReconciler{
+		SystemNamespace: currentNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Region")
+		os.Exit(1)
+	}
+
+	if auditRetention > 0 {
+		if err = mgr.Add(&audit.Pruner{
+			Client:    mgr.GetClient(),
+			Retention: auditRetention,
+		}); err != nil {
+			setupLog.Error(err, "unable to create audit pruner")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controller.ManagementBackupReconciler{
 		Client:          mgr.GetClient(),
+		Notifier:        notifier,
 		SystemNamespace: currentNamespace,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ManagementBackup")
 		os.Exit(1)
 	}
+	if err = (&controller.ManagementRestoreReconciler{
+		Client:          mgr.GetClient(),
+		SystemNamespace: currentNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ManagementRestore")
+		os.Exit(1)
+	}
+	if err = (&controller.ManagementStandbyReconciler{
+		Client:          mgr.GetClient(),
+		SystemNamespace: currentNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ManagementStandby")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -358,9 +596,16 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	// flush the spans buffered by the exporter
+	if err := tracing.Configure(context.Background(), nil); err != nil {
+		setupLog.Error(err, "failed to shut down tracing")
+	}
 }
 
 func setupWebhooks(mgr ctrl.Manager, currentNamespace string, validateClusterUpgradePath bool) error {
+	kcmwebhook.SetupConversionWebhookWithManager(mgr)
+
 	if err := (&kcmwebhook.ClusterDeploymentValidator{ValidateClusterUpgradePath: validateClusterUpgradePath}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "ClusterDeployment")
 		return err
@@ -0,0 +1,57 @@
+#!/bin/sh
+# Copyright 2024
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Adds the conversion webhook of the controller manager to the generated kcm
+# CRDs served in several versions, the CA bundle of the webhook is injected by
+# cert-manager. The Helm templating is kept in the comments and in the quoted
+# values, so the CRDs remain valid YAML installed by envtest as is, which
+# drops the conversion of the kinds not convertible in its scheme.
+
+set -eu
+
+# Directory containing the generated kcm CRDs
+CRD_DIR=${CRD_DIR:-templates/provider/kcm/templates/crds}
+
+for crd in "$CRD_DIR"/*.yaml; do
+    if [ "$(grep -c -e '^    name: v[0-9]' -e '^  - name: v[0-9]' "$crd")" -lt 2 ] || grep -q 'cert-manager.io/inject-ca-from' "$crd"; then
+        continue
+    fi
+
+    awk '
+    /^    controller-gen.kubebuilder.io\/version:/ {
+        print
+        print "    # {{- if .Values.admissionWebhook.enabled }}"
+        print "    cert-manager.io/inject-ca-from: '\''{{ include \"kcm.webhook.certNamespace\" . }}/{{ include \"kcm.webhook.certName\" . }}'\''"
+        print "    # {{- end }}"
+        next
+    }
+    /^  group:/ {
+        print "  # {{- if .Values.admissionWebhook.enabled }}"
+        print "  conversion:"
+        print "    strategy: Webhook"
+        print "    webhook:"
+        print "      clientConfig:"
+        print "        service:"
+        print "          name: '\''{{ include \"kcm.webhook.serviceName\" . }}'\''"
+        print "          namespace: '\''{{ include \"kcm.webhook.serviceNamespace\" . }}'\''"
+        print "          path: /convert"
+        print "      conversionReviewVersions:"
+        print "      - v1"
+        print "  # {{- end }}"
+    }
+    { print }
+    ' "$crd" > "$crd.tmp"
+    mv "$crd.tmp" "$crd"
+done
@@ -20,11 +20,15 @@ set -eu
 TEMPLATES_DIR=${TEMPLATES_DIR:-templates}
 # Output directory for the generated Template manifests
 TEMPLATES_OUTPUT_DIR=${TEMPLATES_OUTPUT_DIR:-templates/provider/kcm-templates/files/templates}
+# Directory of the test fixtures the templates of the charts annotated with
+# k0rdent.mirantis.com/fixture are generated into instead of the default ones
+FIXTURES_DIR=${FIXTURES_DIR:-test/e2e/fixtures}
 # The name of the KCM templates helm chart
 KCM_TEMPLATES_CHART_NAME='kcm-templates'
 
 mkdir -p $TEMPLATES_OUTPUT_DIR
 rm -f $TEMPLATES_OUTPUT_DIR/*.yaml
+rm -f $FIXTURES_DIR/*/templates/*.yaml
 
 for type in "$TEMPLATES_DIR"/*; do
     kind="$(echo "${type#*/}Template" | awk '{$1=toupper(substr($1,0,1))substr($1,2)}1')"
@@ -32,15 +36,23 @@ for type in "$TEMPLATES_DIR"/*; do
         if [ -d "$chart" ]; then
             name=$(grep '^name:' $chart/Chart.yaml | awk '{print $2}')
             if [ "$name" = "$KCM_TEMPLATES_CHART_NAME" ]; then continue; fi
+            # the library charts are only the dependencies of the other charts
+            if grep -q '^type: library' $chart/Chart.yaml; then continue; fi
             version=$(grep '^version:' $chart/Chart.yaml | awk '{print $2}')
             template_name=$name-$(echo "$version" | sed 's/^v//; s/\./-/g')
             if [ "$kind" = "ProviderTemplate" ]; then file_name=$name; else file_name=$template_name; fi
+            output_dir=$TEMPLATES_OUTPUT_DIR
+            fixture=$(grep '^  k0rdent.mirantis.com/fixture:' $chart/Chart.yaml | awk '{print $2}')
+            if [ -n "$fixture" ]; then
+                output_dir=$FIXTURES_DIR/$fixture/templates
+                mkdir -p $output_dir
+            fi
 
-            cat <<EOF > $TEMPLATES_OUTPUT_DIR/$file_name.yaml
+            cat <<EOF > $output_dir/$file_name.yaml
 apiVersion: k0rdent.mirantis.com/v1alpha1
 kind: $kind
 EOF
-            cat <<EOF >> $TEMPLATES_OUTPUT_DIR/$file_name.yaml
+            cat <<EOF >> $output_dir/$file_name.yaml
 metadata:
   name: $template_name
   annotations:
@@ -56,7 +68,7 @@ spec:
         name: kcm-templates
 EOF
 
-            echo "Generated $TEMPLATES_OUTPUT_DIR/$name.yaml"
+            echo "Generated $output_dir/$file_name.yaml"
         fi
     done
 done
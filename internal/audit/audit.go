@@ -0,0 +1,144 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records the material actions performed on the kcm objects
+// as AuditEvent objects and prunes them after the retention period.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// Recorder creates the AuditEvent objects. A nil Recorder records nothing.
+type Recorder struct {
+	client.Client
+
+	// SystemNamespace is the namespace the events of the cluster-scoped subjects are stored in.
+	SystemNamespace string
+	// Controller is the name of the controller recorded as the actor.
+	Controller string
+}
+
+// Record stores the action performed on the given subject. The actions are
+// recorded by the controllers once the change has been persisted, the
+// controller of the Recorder is recorded as the actor.
+func (r *Recorder) Record(ctx context.Context, action kcm.AuditAction, subject kcm.AuditSubject, message string, details map[string]string) error {
+	if r == nil {
+		return nil
+	}
+
+	namespace := subject.Namespace
+	if namespace == "" {
+		namespace = r.SystemNamespace
+	}
+
+	labels := map[string]string{
+		kcm.KCMManagedLabelKey:  kcm.KCMManagedLabelValue,
+		kcm.AuditActionLabelKey: string(action),
+	}
+	if len(validation.IsValidLabelValue(subject.Name)) == 0 {
+		labels[kcm.AuditSubjectLabelKey] = subject.Name
+	}
+
+	event := &kcm.AuditEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: strings.ToLower(subject.Kind) + "-",
+			Namespace:    namespace,
+			Labels:       labels,
+		},
+		Spec: kcm.AuditEventSpec{
+			Action:    action,
+			Subject:   subject,
+			Actor:     kcm.AuditActor{Controller: r.Controller},
+			Message:   message,
+			Details:   details,
+			Timestamp: metav1.Now(),
+		},
+	}
+
+	if err := r.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record %s audit event of the %s %s: %w", action, subject.Kind, client.ObjectKey{Namespace: subject.Namespace, Name: subject.Name}, err)
+	}
+
+	return nil
+}
+
+// Pruner periodically deletes the AuditEvent objects older than the retention period.
+type Pruner struct {
+	client.Client
+
+	// Retention is the period the AuditEvent objects are kept for.
+	Retention time.Duration
+}
+
+const pruneInterval = time.Hour
+
+// Start implements the manager.Runnable interface.
+func (p *Pruner) Start(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	for {
+		select {
+		case <-timer.C:
+			p.Tick(ctx)
+			timer.Reset(pruneInterval)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Tick deletes the expired AuditEvent objects once.
+func (p *Pruner) Tick(ctx context.Context) {
+	l := ctrl.LoggerFrom(ctx).WithName("audit pruner")
+
+	deleted, err := p.prune(ctx, time.Now().Add(-p.Retention))
+	if err != nil {
+		l.Error(err, "failed to prune audit events")
+		return
+	}
+	if deleted > 0 {
+		l.Info("Pruned expired audit events", "count", deleted)
+	}
+}
+
+func (p *Pruner) prune(ctx context.Context, before time.Time) (int, error) {
+	events := new(kcm.AuditEventList)
+	if err := p.List(ctx, events, client.MatchingLabels{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue}); err != nil {
+		return 0, fmt.Errorf("failed to list AuditEvents: %w", err)
+	}
+
+	deleted := 0
+	for _, event := range events.Items {
+		if !event.Spec.Timestamp.Time.Before(before) {
+			continue
+		}
+
+		if err := p.Delete(ctx, &event); client.IgnoreNotFound(err) != nil {
+			return deleted, fmt.Errorf("failed to delete AuditEvent %s: %w", client.ObjectKeyFromObject(&event), err)
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
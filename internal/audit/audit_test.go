@@ -0,0 +1,107 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestRecorder_Record(t *testing.T) {
+	const systemNamespace = "kcm-system"
+
+	tests := []struct {
+		name          string
+		subject       kcm.AuditSubject
+		wantNamespace string
+	}{
+		{
+			name:          "namespaced object",
+			subject:       kcm.AuditSubject{Kind: kcm.ClusterDeploymentKind, Namespace: "test", Name: "cluster"},
+			wantNamespace: "test",
+		},
+		{
+			name:          "cluster-scoped object",
+			subject:       kcm.AuditSubject{Kind: kcm.ManagementKind, Name: kcm.ManagementName},
+			wantNamespace: systemNamespace,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			recorder := &Recorder{Client: cl, SystemNamespace: systemNamespace, Controller: "management"}
+
+			g.Expect(recorder.Record(t.Context(), kcm.AuditActionTemplateUpgrade, tt.subject, "upgraded", map[string]string{"newTemplate": "t2"})).To(Succeed())
+
+			events := new(kcm.AuditEventList)
+			g.Expect(cl.List(t.Context(), events)).To(Succeed())
+			g.Expect(events.Items).To(HaveLen(1))
+			event := events.Items[0]
+			g.Expect(event.Namespace).To(Equal(tt.wantNamespace))
+			g.Expect(event.Labels).To(HaveKeyWithValue(kcm.AuditActionLabelKey, string(kcm.AuditActionTemplateUpgrade)))
+			g.Expect(event.Labels).To(HaveKeyWithValue(kcm.AuditSubjectLabelKey, tt.subject.Name))
+			g.Expect(event.Spec.Subject).To(Equal(tt.subject))
+			g.Expect(event.Spec.Actor).To(Equal(kcm.AuditActor{Controller: "management"}))
+			g.Expect(event.Spec.Details).To(HaveKeyWithValue("newTemplate", "t2"))
+		})
+	}
+
+	t.Run("nil recorder", func(t *testing.T) {
+		var recorder *Recorder
+		NewWithT(t).Expect(recorder.Record(t.Context(), kcm.AuditActionClusterDelete, kcm.AuditSubject{}, "", nil)).To(Succeed())
+	})
+}
+
+func TestPruner_prune(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	newEvent := func(name string, age time.Duration) client.Object {
+		return &kcm.AuditEvent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				Labels:    map[string]string{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue},
+			},
+			Spec: kcm.AuditEventSpec{Timestamp: metav1.NewTime(now.Add(-age))},
+		}
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		newEvent("expired", 48*time.Hour),
+		newEvent("recent", time.Hour),
+	).Build()
+
+	pruner := &Pruner{Client: cl, Retention: 24 * time.Hour}
+	deleted, err := pruner.prune(t.Context(), now.Add(-pruner.Retention))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(Equal(1))
+
+	events := new(kcm.AuditEventList)
+	g.Expect(cl.List(t.Context(), events)).To(Succeed())
+	g.Expect(events.Items).To(HaveLen(1))
+	g.Expect(events.Items[0].Name).To(Equal("recent"))
+}
@@ -0,0 +1,120 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package certificates tracks the expiration of the certificates of the
+// managed clusters and triggers their rotation.
+package certificates
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const dialTimeout = 10 * time.Second
+
+// ServerCertificateExpiry returns the expiration time of the serving
+// certificate of the API server the given config points to.
+func ServerCertificateExpiry(ctx context.Context, config *rest.Config) (time.Time, error) {
+	tlsConfig, err := rest.TLSConfigFor(config)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get TLS config of the API server: %w", err)
+	}
+	if tlsConfig == nil {
+		return time.Time{}, errors.New("API server is not served over TLS")
+	}
+
+	host, err := url.Parse(config.Host)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse API server URL %s: %w", config.Host, err)
+	}
+	address := host.Host
+	if host.Port() == "" {
+		address = net.JoinHostPort(host.Hostname(), "443")
+	}
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: dialTimeout}, Config: tlsConfig}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to connect to the API server %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	peerCertificates := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(peerCertificates) == 0 {
+		return time.Time{}, fmt.Errorf("API server %s presented no certificates", address)
+	}
+
+	return peerCertificates[0].NotAfter, nil
+}
+
+// MachinesExpiry returns the earliest expiration time of the certificates
+// of the given machines reported by the control plane provider or set with
+// the certificates expiry annotation. Zero time is returned if none is known.
+func MachinesExpiry(machines []clusterapiv1beta1.Machine) (time.Time, error) {
+	var earliest time.Time
+	for _, machine := range machines {
+		var expiry time.Time
+		if machine.Status.CertificatesExpiryDate != nil {
+			expiry = machine.Status.CertificatesExpiryDate.Time
+		}
+		if annotation, ok := machine.Annotations[clusterapiv1beta1.MachineCertificatesExpiryDateAnnotation]; ok {
+			t, err := time.Parse(time.RFC3339, annotation)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("failed to parse %s annotation of the Machine %s: %w", clusterapiv1beta1.MachineCertificatesExpiryDateAnnotation, client.ObjectKeyFromObject(&machine), err)
+			}
+			expiry = t
+		}
+
+		if !expiry.IsZero() && (earliest.IsZero() || expiry.Before(earliest)) {
+			earliest = expiry
+		}
+	}
+
+	return earliest, nil
+}
+
+// TriggerRollout requests the rollout of the control plane machines of the
+// given cluster setting the rolloutAfter field of the control plane object.
+// The field is optional in the Cluster API control plane contract, the
+// control planes that do not support it are rejected with an error.
+func TriggerRollout(ctx context.Context, cl client.Client, cluster *clusterapiv1beta1.Cluster, at time.Time) error {
+	ref := cluster.Spec.ControlPlaneRef
+	if ref == nil {
+		return fmt.Errorf("cluster %s has no control plane", client.ObjectKeyFromObject(cluster))
+	}
+
+	controlPlane := new(unstructured.Unstructured)
+	controlPlane.SetAPIVersion(ref.APIVersion)
+	controlPlane.SetKind(ref.Kind)
+	controlPlane.SetNamespace(cluster.Namespace)
+	controlPlane.SetName(ref.Name)
+
+	patch := fmt.Appendf(nil, `{"spec":{"rolloutAfter":%q}}`, at.UTC().Format(time.RFC3339))
+	if err := cl.Patch(ctx, controlPlane, client.RawPatch(types.MergePatchType, patch), client.FieldValidation("Strict")); err != nil {
+		return fmt.Errorf("failed to trigger rollout of the %s %s: %w", ref.Kind, client.ObjectKeyFromObject(controlPlane), err)
+	}
+
+	return nil
+}
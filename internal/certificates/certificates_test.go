@@ -0,0 +1,132 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificates
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestServerCertificateExpiry(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	expiry, err := ServerCertificateExpiry(t.Context(), &rest.Config{
+		Host:            server.URL,
+		TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(expiry).To(BeTemporally("==", server.Certificate().NotAfter))
+}
+
+func TestMachinesExpiry(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	newMachine := func(status *time.Time, annotation string) clusterapiv1beta1.Machine {
+		machine := clusterapiv1beta1.Machine{}
+		if status != nil {
+			machine.Status.CertificatesExpiryDate = &metav1.Time{Time: *status}
+		}
+		if annotation != "" {
+			machine.Annotations = map[string]string{clusterapiv1beta1.MachineCertificatesExpiryDateAnnotation: annotation}
+		}
+		return machine
+	}
+
+	for _, tc := range []struct {
+		expected time.Time
+		name     string
+		machines []clusterapiv1beta1.Machine
+		err      bool
+	}{
+		{
+			name:     "no expiry reported",
+			machines: []clusterapiv1beta1.Machine{newMachine(nil, "")},
+		},
+		{
+			name: "earliest expiry",
+			machines: []clusterapiv1beta1.Machine{
+				newMachine(ptr.To(now.Add(48*time.Hour)), ""),
+				newMachine(ptr.To(now.Add(24*time.Hour)), ""),
+				newMachine(nil, ""),
+			},
+			expected: now.Add(24 * time.Hour),
+		},
+		{
+			name:     "annotation overrides status",
+			machines: []clusterapiv1beta1.Machine{newMachine(ptr.To(now.Add(48*time.Hour)), now.Add(time.Hour).Format(time.RFC3339))},
+			expected: now.Add(time.Hour),
+		},
+		{
+			name:     "invalid annotation",
+			machines: []clusterapiv1beta1.Machine{newMachine(nil, "tomorrow")},
+			err:      true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			expiry, err := MachinesExpiry(tc.machines)
+			if tc.err {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(expiry).To(BeTemporally("==", tc.expected))
+		})
+	}
+}
+
+func TestTriggerRollout(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := new(unstructured.Unstructured)
+	controlPlane.SetAPIVersion("controlplane.cluster.x-k8s.io/v1beta1")
+	controlPlane.SetKind("KubeadmControlPlane")
+	controlPlane.SetNamespace("test")
+	controlPlane.SetName("cluster-cp")
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(controlPlane).Build()
+
+	cluster := &clusterapiv1beta1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "cluster"},
+	}
+	g.Expect(TriggerRollout(t.Context(), cl, cluster, time.Now())).NotTo(Succeed())
+
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	cluster.Spec.ControlPlaneRef = &corev1.ObjectReference{APIVersion: controlPlane.GetAPIVersion(), Kind: controlPlane.GetKind(), Name: controlPlane.GetName()}
+	g.Expect(TriggerRollout(t.Context(), cl, cluster, at)).To(Succeed())
+
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(controlPlane), controlPlane)).To(Succeed())
+	rolloutAfter, _, err := unstructured.NestedString(controlPlane.Object, "spec", "rolloutAfter")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rolloutAfter).To(Equal("2025-01-02T03:04:05Z"))
+}
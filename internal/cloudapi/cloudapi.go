@@ -0,0 +1,195 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cloudapi limits the rate of the direct calls of the cloud APIs made
+// with each of the Credentials and suspends the calls after repeated
+// failures, so kcm never exhausts the API limits of the cloud accounts shared
+// with the CAPI providers managing the clusters.
+package cloudapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/K0rdent/kcm/internal/metrics"
+)
+
+const (
+	resultSuccess  = "success"
+	resultFailure  = "failure"
+	resultRejected = "rejected"
+)
+
+// ErrCircuitOpen is returned for the calls rejected while the calls with the
+// Credential are suspended.
+var ErrCircuitOpen = errors.New("cloud API calls are suspended after repeated failures")
+
+// DefaultOptions are the Options of the Limiter the flags of the manager
+// default to.
+var DefaultOptions = Options{
+	QPS:              1,
+	Burst:            5,
+	FailureThreshold: 3,
+	OpenDuration:     10 * time.Minute,
+}
+
+// Key identifies the account the calls are limited for.
+type Key struct {
+	// Credential is the namespaced name of the Credential.
+	Credential string
+	// Provider is the name of the cloud provider.
+	Provider string
+}
+
+// Options configure the Limiter.
+type Options struct {
+	// QPS is the maximum average rate of the calls per Key, the rate is
+	// unlimited if zero.
+	QPS float64
+	// Burst is the maximum number of the calls per Key made at once.
+	Burst int
+	// FailureThreshold is the number of the consecutive failures the calls
+	// are suspended after, the calls are never suspended if zero.
+	FailureThreshold int
+	// OpenDuration is how long the calls are suspended for, then a single
+	// trial call is let through, which either resumes the calls on success or
+	// suspends them again.
+	OpenDuration time.Duration
+}
+
+// Limiter limits the rate of the calls and breaks the circuit after the
+// repeated failures per Key. It is safe for concurrent use.
+type Limiter struct {
+	states map[Key]*state
+	now    func() time.Time
+	opts   Options
+	mu     sync.Mutex
+}
+
+type state struct {
+	limiter   *rate.Limiter
+	openUntil time.Time
+	failures  int
+	trial     bool
+}
+
+// NewLimiter returns the Limiter with the given Options.
+func NewLimiter(opts Options) *Limiter {
+	return &Limiter{
+		states: make(map[Key]*state),
+		now:    time.Now,
+		opts:   opts,
+	}
+}
+
+// Do calls fn once the rate limit of the Key allows it, or returns
+// [ErrCircuitOpen] without calling it while the calls of the Key are
+// suspended. The errors of fn other than the cancellation count as failures.
+func (l *Limiter) Do(ctx context.Context, key Key, fn func(context.Context) error) error {
+	s, err := l.acquire(key)
+	if err != nil {
+		metrics.TrackMetricCloudAPICall(key.Credential, key.Provider, resultRejected)
+		return err
+	}
+
+	if err := s.limiter.Wait(ctx); err != nil {
+		l.release(s)
+		return fmt.Errorf("failed to wait for the rate limit of the %s API: %w", key.Provider, err)
+	}
+
+	err = fn(ctx)
+	if errors.Is(err, context.Canceled) {
+		l.release(s)
+		return err
+	}
+
+	l.record(key, s, err)
+	return err
+}
+
+// Forget drops the state of the calls with the Credential, e.g. once it has
+// been removed.
+func (l *Limiter) Forget(credential string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key := range l.states {
+		if key.Credential == credential {
+			delete(l.states, key)
+		}
+	}
+	metrics.DeleteMetricsCloudAPI(credential)
+}
+
+func (l *Limiter) acquire(key Key) (*state, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, ok := l.states[key]
+	if !ok {
+		limit := rate.Inf
+		if l.opts.QPS > 0 {
+			limit = rate.Limit(l.opts.QPS)
+		}
+		s = &state{limiter: rate.NewLimiter(limit, l.opts.Burst)}
+		l.states[key] = s
+	}
+
+	if s.openUntil.IsZero() {
+		return s, nil
+	}
+	if now := l.now(); now.Before(s.openUntil) {
+		return nil, fmt.Errorf("%w, retrying after %s", ErrCircuitOpen, s.openUntil.Format(time.RFC3339))
+	}
+	// only a single trial call is let through once the circuit is half-open
+	if s.trial {
+		return nil, ErrCircuitOpen
+	}
+	s.trial = true
+	return s, nil
+}
+
+// release ends the trial call without its result.
+func (l *Limiter) release(s *state) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s.trial = false
+}
+
+func (l *Limiter) record(key Key, s *state, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s.trial = false
+	if err == nil {
+		s.failures = 0
+		s.openUntil = time.Time{}
+		metrics.TrackMetricCloudAPICall(key.Credential, key.Provider, resultSuccess)
+		metrics.TrackMetricCloudAPICircuitOpen(key.Credential, key.Provider, false)
+		return
+	}
+
+	s.failures++
+	metrics.TrackMetricCloudAPICall(key.Credential, key.Provider, resultFailure)
+	if l.opts.FailureThreshold > 0 && s.failures >= l.opts.FailureThreshold {
+		s.openUntil = l.now().Add(l.opts.OpenDuration)
+		metrics.TrackMetricCloudAPICircuitOpen(key.Credential, key.Provider, true)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLimiter_Do(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(Options{QPS: 0, Burst: 1, FailureThreshold: 2, OpenDuration: time.Minute})
+	l.now = func() time.Time { return now }
+
+	key := Key{Credential: "kcm-system/openstack-cred", Provider: "openstack"}
+	other := Key{Credential: "kcm-system/other-cred", Provider: "openstack"}
+
+	var calls int
+	failing := func(context.Context) error { calls++; return errors.New("too many requests") }
+	succeeding := func(context.Context) error { calls++; return nil }
+
+	g.Expect(l.Do(t.Context(), key, failing)).NotTo(MatchError(ErrCircuitOpen))
+	g.Expect(l.Do(t.Context(), key, failing)).NotTo(MatchError(ErrCircuitOpen))
+	g.Expect(calls).To(Equal(2))
+
+	// suspended after the threshold, the other Credentials are not affected
+	g.Expect(l.Do(t.Context(), key, succeeding)).To(MatchError(ErrCircuitOpen))
+	g.Expect(calls).To(Equal(2))
+	g.Expect(l.Do(t.Context(), other, succeeding)).To(Succeed())
+	g.Expect(calls).To(Equal(3))
+
+	// the failed trial call suspends the calls again
+	now = now.Add(time.Minute)
+	g.Expect(l.Do(t.Context(), key, failing)).NotTo(MatchError(ErrCircuitOpen))
+	g.Expect(l.Do(t.Context(), key, succeeding)).To(MatchError(ErrCircuitOpen))
+	g.Expect(calls).To(Equal(4))
+
+	// the succeeded trial call resumes the calls
+	now = now.Add(time.Minute)
+	g.Expect(l.Do(t.Context(), key, succeeding)).To(Succeed())
+	g.Expect(l.Do(t.Context(), key, succeeding)).To(Succeed())
+	g.Expect(calls).To(Equal(6))
+
+	// the cancellation is not a failure
+	canceled := func(context.Context) error { return context.Canceled }
+	for range 3 {
+		g.Expect(l.Do(t.Context(), key, canceled)).To(MatchError(context.Canceled))
+	}
+	g.Expect(l.Do(t.Context(), key, succeeding)).To(Succeed())
+
+	l.Forget(key.Credential)
+	g.Expect(l.states).To(HaveLen(1))
+}
+
+func TestLimiter_Do_rateLimit(t *testing.T) {
+	g := NewWithT(t)
+
+	l := NewLimiter(Options{QPS: 0.001, Burst: 1})
+	key := Key{Credential: "kcm-system/openstack-cred", Provider: "openstack"}
+
+	g.Expect(l.Do(t.Context(), key, func(context.Context) error { return nil })).To(Succeed())
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	err := l.Do(ctx, key, func(context.Context) error { return nil })
+	g.Expect(err).To(MatchError(ContainSubstring("rate limit")))
+}
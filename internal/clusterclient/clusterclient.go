@@ -0,0 +1,66 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clusterclient builds the clients of the managed clusters from the
+// kubeconfig Secrets of their ClusterDeployments.
+package clusterclient
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClientsetFunc returns the clientset of the managed cluster.
+type ClientsetFunc func(ctx context.Context, cluster client.ObjectKey) (kubernetes.Interface, error)
+
+// ClientFunc returns the controller-runtime client of the managed cluster.
+type ClientFunc func(ctx context.Context, cluster client.ObjectKey) (client.Client, error)
+
+// NewClientsetFunc returns the ClientsetFunc reading the kubeconfig Secrets
+// of the clusters with the given client. The name identifies the caller in the
+// user agent of the requests.
+func NewClientsetFunc(cl client.Client, name string) ClientsetFunc {
+	return func(ctx context.Context, cluster client.ObjectKey) (kubernetes.Interface, error) {
+		restConfig, err := remote.RESTConfig(ctx, name, cl, cluster)
+		if err != nil {
+			return nil, err
+		}
+		clientset, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create clientset of the cluster %s: %w", cluster, err)
+		}
+		return clientset, nil
+	}
+}
+
+// NewClientFunc returns the ClientFunc reading the kubeconfig Secrets of the
+// clusters with the given client, the clients share its scheme. The name
+// identifies the caller in the user agent of the requests.
+func NewClientFunc(cl client.Client, name string) ClientFunc {
+	return func(ctx context.Context, cluster client.ObjectKey) (client.Client, error) {
+		restConfig, err := remote.RESTConfig(ctx, name, cl, cluster)
+		if err != nil {
+			return nil, err
+		}
+		clusterClient, err := client.New(restConfig, client.Options{Scheme: cl.Scheme()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client of the cluster %s: %w", cluster, err)
+		}
+		return clusterClient, nil
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterclient
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: cluster
+  context:
+    cluster: cluster
+    user: admin
+current-context: cluster
+users:
+- name: admin
+  user:
+    token: token
+`
+
+func TestNewClientFuncs(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := client.ObjectKey{Namespace: "team-a", Name: "dev"}
+	missing := client.ObjectKey{Namespace: "team-a", Name: "missing"}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: cluster.Name + "-kubeconfig"},
+		Data:       map[string][]byte{"value": []byte(kubeconfig)},
+	}).Build()
+
+	clientset, err := NewClientsetFunc(cl, "kcm-test")(t.Context(), cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(clientset).NotTo(BeNil())
+	_, err = NewClientsetFunc(cl, "kcm-test")(t.Context(), missing)
+	g.Expect(err).To(HaveOccurred())
+
+	clusterClient, err := NewClientFunc(cl, "kcm-test")(t.Context(), cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(clusterClient.Scheme()).To(Equal(cl.Scheme()))
+	_, err = NewClientFunc(cl, "kcm-test")(t.Context(), missing)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,122 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+func TestParseReport(t *testing.T) {
+	const report = `{
+  "Controls": [
+    {
+      "id": "4",
+      "version": "cis-1.9",
+      "node_type": "node",
+      "tests": [
+        {"section": "4.1", "results": [
+          {"test_number": "4.1.1", "status": "PASS"},
+          {"test_number": "4.1.2", "status": "FAIL"}
+        ]},
+        {"section": "4.2", "results": [
+          {"test_number": "4.2.1", "status": "WARN"},
+          {"test_number": "4.2.2", "status": "FAIL"}
+        ]}
+      ]
+    }
+  ],
+  "Totals": {"total_pass": 1, "total_fail": 2, "total_warn": 1, "total_info": 0}
+}`
+
+	g := NewWithT(t)
+
+	summary, err := ParseReport([]byte(report))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(summary).To(Equal(&kcm.ComplianceStatus{
+		Benchmark:    "cis-1.9",
+		FailedChecks: []string{"4.1.2", "4.2.2"},
+		Passed:       1,
+		Failed:       2,
+		Warned:       1,
+	}))
+
+	_, err = ParseReport([]byte(`{"Controls": []}`))
+	g.Expect(err).To(MatchError("kube-bench report has no totals"))
+
+	_, err = ParseReport([]byte(`not a json`))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestJob(t *testing.T) {
+	g := NewWithT(t)
+
+	job := Job(&kcm.ComplianceSpec{Benchmark: "cis-1.9"})
+	g.Expect(job.Namespace).To(Equal(Namespace))
+	g.Expect(job.Spec.Template.Spec.HostPID).To(BeTrue())
+	g.Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+
+	container := job.Spec.Template.Spec.Containers[0]
+	g.Expect(container.Image).To(Equal(DefaultImage))
+	g.Expect(container.Args).To(Equal([]string{"--json", "--benchmark", "cis-1.9"}))
+	g.Expect(container.VolumeMounts).To(HaveLen(len(job.Spec.Template.Spec.Volumes)))
+	for _, mount := range container.VolumeMounts {
+		g.Expect(mount.ReadOnly).To(BeTrue())
+	}
+
+	g.Expect(Job(&kcm.ComplianceSpec{Image: "example.com/kube-bench:dev"}).Spec.Template.Spec.Containers[0].Image).To(Equal("example.com/kube-bench:dev"))
+}
+
+func TestScanner_Scan(t *testing.T) {
+	g := NewWithT(t)
+
+	cs := fake.NewClientset()
+	scanner := &Scanner{Client: cs}
+	spec := &kcm.ComplianceSpec{}
+
+	// starts the scan
+	summary, err := scanner.Scan(t.Context(), spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(summary).To(BeNil())
+
+	_, err = cs.CoreV1().Namespaces().Get(t.Context(), Namespace, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	job, err := cs.BatchV1().Jobs(Namespace).Get(t.Context(), JobName, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// waits for the job
+	summary, err = scanner.Scan(t.Context(), spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(summary).To(BeNil())
+
+	// reports the failed job and removes it
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	_, err = cs.BatchV1().Jobs(Namespace).UpdateStatus(t.Context(), job, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = scanner.Scan(t.Context(), spec)
+	g.Expect(err).To(MatchError(ErrScanFailed))
+
+	_, err = cs.BatchV1().Jobs(Namespace).Get(t.Context(), JobName, metav1.GetOptions{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
@@ -0,0 +1,85 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// maxFailedChecks limits the number of the failed checks reported in the status.
+const maxFailedChecks = 50
+
+// report is the subset of the kube-bench JSON output.
+type report struct {
+	Controls []struct {
+		Version string `json:"version"`
+		Tests   []struct {
+			Results []struct {
+				TestNumber string `json:"test_number"`
+				Status     string `json:"status"`
+			} `json:"results"`
+		} `json:"tests"`
+	} `json:"Controls"`
+	Totals *struct {
+		Pass int32 `json:"total_pass"`
+		Fail int32 `json:"total_fail"`
+		Warn int32 `json:"total_warn"`
+		Info int32 `json:"total_info"`
+	} `json:"Totals"`
+}
+
+// ParseReport returns the summary of the given kube-bench JSON report.
+func ParseReport(data []byte) (*kcm.ComplianceStatus, error) {
+	r := new(report)
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("failed to decode kube-bench report: %w", err)
+	}
+	if r.Totals == nil {
+		return nil, errors.New("kube-bench report has no totals")
+	}
+
+	summary := &kcm.ComplianceStatus{
+		Passed: r.Totals.Pass,
+		Failed: r.Totals.Fail,
+		Warned: r.Totals.Warn,
+		Info:   r.Totals.Info,
+	}
+
+	for _, control := range r.Controls {
+		if summary.Benchmark == "" {
+			summary.Benchmark = control.Version
+		}
+		for _, test := range control.Tests {
+			for _, result := range test.Results {
+				if result.Status == "FAIL" {
+					summary.FailedChecks = append(summary.FailedChecks, result.TestNumber)
+				}
+			}
+		}
+	}
+
+	slices.Sort(summary.FailedChecks)
+	summary.FailedChecks = slices.Compact(summary.FailedChecks)
+	if len(summary.FailedChecks) > maxFailedChecks {
+		summary.FailedChecks = summary.FailedChecks[:maxFailedChecks]
+	}
+
+	return summary, nil
+}
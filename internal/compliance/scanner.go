@@ -0,0 +1,227 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compliance runs the kube-bench CIS benchmark checks on the managed
+// clusters and summarizes their results.
+package compliance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+const (
+	// Namespace is the namespace of the managed cluster the scans are run in.
+	Namespace = "kcm-compliance"
+	// JobName is the name of the kube-bench Job.
+	JobName = "kube-bench"
+
+	// DefaultImage is the kube-bench image used unless overridden.
+	DefaultImage = "docker.io/aquasec/kube-bench:v0.10.4"
+)
+
+// ErrScanFailed is returned if the kube-bench Job has failed.
+var ErrScanFailed = errors.New("kube-bench scan failed")
+
+// hostPaths are the host directories kube-bench inspects.
+var hostPaths = map[string]string{
+	"var-lib-etcd":                "/var/lib/etcd",
+	"var-lib-kubelet":             "/var/lib/kubelet",
+	"var-lib-kube-scheduler":      "/var/lib/kube-scheduler",
+	"var-lib-kube-controller-mgr": "/var/lib/kube-controller-manager",
+	"var-lib-k0s":                 "/var/lib/k0s",
+	"etc-systemd":                 "/etc/systemd",
+	"lib-systemd":                 "/lib/systemd",
+	"srv-kubernetes":              "/srv/kubernetes",
+	"etc-kubernetes":              "/etc/kubernetes",
+	"etc-cni-netd":                "/etc/cni/net.d",
+	"opt-cni-bin":                 "/opt/cni/bin",
+}
+
+// Scanner runs the kube-bench scans on a managed cluster.
+type Scanner struct {
+	Client kubernetes.Interface
+}
+
+// Scan drives the scan with the given spec to completion. It starts the
+// kube-bench Job if none exists and returns nil summary while the Job is
+// running. Once the Job is completed, its report is summarized and the Job
+// is removed so that the next call starts a new scan.
+func (s *Scanner) Scan(ctx context.Context, spec *kcm.ComplianceSpec) (*kcm.ComplianceStatus, error) {
+	if err := s.ensureNamespace(ctx); err != nil {
+		return nil, err
+	}
+
+	job, err := s.Client.BatchV1().Jobs(Namespace).Get(ctx, JobName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := s.Client.BatchV1().Jobs(Namespace).Create(ctx, Job(spec), metav1.CreateOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to create %s/%s Job: %w", Namespace, JobName, err)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s/%s Job: %w", Namespace, JobName, err)
+	}
+
+	switch {
+	case jobCondition(job, batchv1.JobFailed):
+		if err := s.deleteJob(ctx); err != nil {
+			return nil, err
+		}
+		return nil, ErrScanFailed
+	case !jobCondition(job, batchv1.JobComplete):
+		return nil, nil
+	}
+
+	logs, err := s.jobLogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := ParseReport(logs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deleteJob(ctx); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// Job returns the kube-bench Job running the checks with the given spec.
+func Job(spec *kcm.ComplianceSpec) *batchv1.Job {
+	image := DefaultImage
+	if spec.Image != "" {
+		image = spec.Image
+	}
+
+	args := []string{"--json"}
+	if spec.Benchmark != "" {
+		args = append(args, "--benchmark", spec.Benchmark)
+	}
+
+	var (
+		volumes      []corev1.Volume
+		volumeMounts []corev1.VolumeMount
+	)
+	for _, name := range slices.Sorted(maps.Keys(hostPaths)) {
+		volumes = append(volumes, corev1.Volume{
+			Name:         name,
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: hostPaths[name]}},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: name, MountPath: hostPaths[name], ReadOnly: true})
+	}
+	volumes = append(volumes, corev1.Volume{
+		Name:         "usr-bin",
+		VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/usr/bin"}},
+	})
+	volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: "usr-bin", MountPath: "/usr/local/mount-from-host/bin", ReadOnly: true})
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      JobName,
+			Namespace: Namespace,
+			Labels:    map[string]string{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To[int32](0),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue},
+				},
+				Spec: corev1.PodSpec{
+					HostPID:       true,
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:         JobName,
+						Image:        image,
+						Command:      []string{"kube-bench"},
+						Args:         args,
+						VolumeMounts: volumeMounts,
+					}},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+func (s *Scanner) ensureNamespace(ctx context.Context) error {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: Namespace,
+			Labels: map[string]string{
+				kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue,
+				// kube-bench requires access to the host
+				"pod-security.kubernetes.io/enforce": "privileged",
+			},
+		},
+	}
+	if _, err := s.Client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create %s namespace: %w", Namespace, err)
+	}
+	return nil
+}
+
+func (s *Scanner) jobLogs(ctx context.Context) ([]byte, error) {
+	pods, err := s.Client.CoreV1().Pods(Namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + JobName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s/%s Job pods: %w", Namespace, JobName, err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+
+		logs, err := s.Client.CoreV1().Pods(Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: JobName}).DoRaw(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get logs of the %s/%s pod: %w", Namespace, pod.Name, err)
+		}
+		return logs, nil
+	}
+
+	return nil, fmt.Errorf("no succeeded pods of the %s/%s Job found", Namespace, JobName)
+}
+
+func (s *Scanner) deleteJob(ctx context.Context) error {
+	err := s.Client.BatchV1().Jobs(Namespace).Delete(ctx, JobName, metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationBackground)})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %s/%s Job: %w", Namespace, JobName, err)
+	}
+	return nil
+}
+
+func jobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The field managers the controllers apply the objects they own with, so
+// the fields set by each of them are tracked separately from the ones of the
+// other controllers and of the users.
+const (
+	clusterDeploymentFieldManager = "kcm-clusterdeployment-controller"
+	managementFieldManager        = "kcm-management-controller"
+)
+
+// conditionsObject is the object the conditions of which are kept in its
+// status.
+type conditionsObject interface {
+	client.Object
+	GetConditions() *[]metav1.Condition
+}
+
+// patchStatus writes the changes of the status of the object made since its
+// base copy has been read as a merge patch of the status subresource guarded
+// by the resource version. The patch carries only the changed fields, so the
+// rest of the status written by the other controllers in the meantime is kept.
+// The conditions are written as a whole, so upon the conflict the object is
+// read again and the changed conditions are set on top of the current ones.
+// The patch is not sent at all if the status is unchanged.
+func patchStatus[T conditionsObject](ctx context.Context, cl client.Client, obj, base T) error {
+	changed, err := statusChanged(obj, base)
+	if err != nil || !changed {
+		return err
+	}
+
+	// the object might have been updated since the base copy has been read,
+	// so the patch is guarded by the latest known resource version
+	from, ok := base.DeepCopyObject().(T)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", base)
+	}
+	from.SetResourceVersion(obj.GetResourceVersion())
+
+	baseConditions := *base.GetConditions()
+	desiredConditions := slices.Clone(*obj.GetConditions())
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := cl.Status().Patch(ctx, obj, client.MergeFromWithOptions(from, client.MergeFromWithOptimisticLock{}))
+		if !apierrors.IsConflict(err) {
+			return err
+		}
+
+		current, _ := obj.DeepCopyObject().(T)
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			return err
+		}
+		from.SetResourceVersion(current.GetResourceVersion())
+		*from.GetConditions() = slices.Clone(*current.GetConditions())
+		*obj.GetConditions() = rebaseConditions(*current.GetConditions(), baseConditions, desiredConditions)
+
+		return err
+	})
+}
+
+// statusChanged reports whether the status of the object differs from the
+// one of its base copy.
+func statusChanged(obj, base client.Object) (bool, error) {
+	data, err := client.MergeFrom(base).Data(obj)
+	if err != nil {
+		return false, fmt.Errorf("failed to calculate the status patch of %T: %w", obj, err)
+	}
+	patch := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return false, fmt.Errorf("failed to calculate the status patch of %T: %w", obj, err)
+	}
+	_, ok := patch["status"]
+	return ok, nil
+}
+
+// rebaseConditions sets the conditions changed and removes the conditions
+// removed between the base and the desired ones on top of the current ones,
+// so the conditions set by the other controllers are kept.
+func rebaseConditions(current, base, desired []metav1.Condition) []metav1.Condition {
+	conditions := slices.Clone(current)
+	for _, c := range desired {
+		if b := apimeta.FindStatusCondition(base, c.Type); b != nil && equality.Semantic.DeepEqual(*b, c) {
+			continue
+		}
+		if i := slices.IndexFunc(conditions, func(cc metav1.Condition) bool { return cc.Type == c.Type }); i >= 0 {
+			conditions[i] = c
+		} else {
+			conditions = append(conditions, c)
+		}
+	}
+	for _, c := range base {
+		if apimeta.FindStatusCondition(desired, c.Type) == nil {
+			apimeta.RemoveStatusCondition(&conditions, c.Type)
+		}
+	}
+
+	return conditions
+}
@@ -0,0 +1,107 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func Test_patchStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	mcs := &kcm.MultiClusterService{ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 2}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.MultiClusterService{}).
+		WithObjects(mcs).Build()
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(mcs), mcs)).To(Succeed())
+
+	// the unchanged status is not written
+	base := mcs.DeepCopy()
+	g.Expect(patchStatus(t.Context(), cl, mcs, base)).To(Succeed())
+	g.Expect(mcs.ResourceVersion).To(Equal(base.ResourceVersion))
+
+	// the status is written regardless of the concurrent changes of the object
+	stale := mcs.DeepCopy()
+	mcs.Labels = map[string]string{"foo": "bar"}
+	g.Expect(cl.Update(t.Context(), mcs)).To(Succeed())
+
+	stale.Status.ObservedGeneration = 2
+	g.Expect(patchStatus(t.Context(), cl, stale, base)).To(Succeed())
+
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(mcs), mcs)).To(Succeed())
+	g.Expect(mcs.Status.ObservedGeneration).To(Equal(int64(2)))
+	g.Expect(mcs.Labels).To(HaveKeyWithValue("foo", "bar"))
+	g.Expect(stale.ResourceVersion).To(Equal(mcs.ResourceVersion))
+
+	// the conditions written concurrently by the other controllers are kept
+	base = mcs.DeepCopy()
+	stale = mcs.DeepCopy()
+	concurrent := mcs.DeepCopy()
+	concurrent.Status.Conditions = []metav1.Condition{
+		{Type: "Other", Status: metav1.ConditionTrue, Reason: kcm.SucceededReason, LastTransitionTime: metav1.Now()},
+	}
+	g.Expect(cl.Status().Update(t.Context(), concurrent)).To(Succeed())
+
+	stale.Status.Conditions = []metav1.Condition{
+		{Type: kcm.ReadyCondition, Status: metav1.ConditionTrue, Reason: kcm.SucceededReason, LastTransitionTime: metav1.Now()},
+	}
+	stale.Status.ObservedGeneration = 3
+	g.Expect(patchStatus(t.Context(), cl, stale, base)).To(Succeed())
+
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(mcs), mcs)).To(Succeed())
+	g.Expect(mcs.Status.ObservedGeneration).To(Equal(int64(3)))
+	g.Expect(mcs.Status.Conditions).To(HaveLen(2))
+	g.Expect(mcs.Status.Conditions[0].Type).To(Equal("Other"))
+	g.Expect(mcs.Status.Conditions[1].Type).To(Equal(kcm.ReadyCondition))
+
+	// the rest of the status written concurrently is kept
+	base = mcs.DeepCopy()
+	stale = mcs.DeepCopy()
+	concurrent = mcs.DeepCopy()
+	concurrent.Status.Services = []kcm.ServiceStatus{{ClusterName: "cluster"}}
+	g.Expect(cl.Status().Update(t.Context(), concurrent)).To(Succeed())
+
+	stale.Status.ObservedGeneration = 4
+	g.Expect(patchStatus(t.Context(), cl, stale, base)).To(Succeed())
+
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(mcs), mcs)).To(Succeed())
+	g.Expect(mcs.Status.ObservedGeneration).To(Equal(int64(4)))
+	g.Expect(mcs.Status.Services).To(Equal([]kcm.ServiceStatus{{ClusterName: "cluster"}}))
+	g.Expect(mcs.Status.Conditions).To(HaveLen(2))
+}
+
+func Test_rebaseConditions(t *testing.T) {
+	g := NewWithT(t)
+
+	condition := func(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status}
+	}
+
+	current := []metav1.Condition{condition("Ready", metav1.ConditionFalse), condition("Reachable", metav1.ConditionTrue), condition("Stale", metav1.ConditionTrue)}
+	base := []metav1.Condition{condition("Ready", metav1.ConditionFalse), condition("Reachable", metav1.ConditionFalse), condition("Stale", metav1.ConditionTrue)}
+	desired := []metav1.Condition{condition("Ready", metav1.ConditionTrue), condition("Reachable", metav1.ConditionFalse), condition("New", metav1.ConditionTrue)}
+
+	g.Expect(rebaseConditions(current, base, desired)).To(Equal([]metav1.Condition{
+		condition("Ready", metav1.ConditionTrue), condition("Reachable", metav1.ConditionTrue), condition("New", metav1.ConditionTrue),
+	}))
+}
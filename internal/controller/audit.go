@@ -0,0 +1,63 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	sveltosv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/audit"
+)
+
+// recordAuditEvent records the action to the audit trail. Failures are logged
+// and do not fail the reconciliation, the action has been already persisted.
+func recordAuditEvent(ctx context.Context, recorder *audit.Recorder, action kcm.AuditAction, subject kcm.AuditSubject, message string, details map[string]string) {
+	if err := recorder.Record(ctx, action, subject, message, details); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to record audit event")
+	}
+}
+
+// recordServiceRollout records the rollout of the services to the audit trail
+// if the deployed services of the Sveltos profile have changed. The previous
+// spec is nil if the profile has not existed.
+func recordServiceRollout(ctx context.Context, recorder *audit.Recorder, subject kcm.AuditSubject, services []kcm.Service, previous, current *sveltosv1beta1.Spec) {
+	if previous == nil {
+		previous = new(sveltosv1beta1.Spec)
+	}
+	if equality.Semantic.DeepEqual(previous.HelmCharts, current.HelmCharts) &&
+		equality.Semantic.DeepEqual(previous.KustomizationRefs, current.KustomizationRefs) {
+		return
+	}
+
+	recordAuditEvent(ctx, recorder, kcm.AuditActionServiceRollout, subject,
+		"Services changed", map[string]string{"services": servicesSummary(services)})
+}
+
+// servicesSummary returns the comma-separated list of the enabled services and their templates.
+func servicesSummary(services []kcm.Service) string {
+	summary := make([]string, 0, len(services))
+	for _, svc := range services {
+		if svc.Disable {
+			continue
+		}
+		summary = append(summary, svc.Name+"="+svc.Template)
+	}
+	return strings.Join(summary, ",")
+}
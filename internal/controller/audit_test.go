@@ -0,0 +1,80 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	sveltosv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/audit"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func Test_recordServiceRollout(t *testing.T) {
+	subject := kcm.AuditSubject{Kind: kcm.MultiClusterServiceKind, Name: "mcs"}
+	services := []kcm.Service{{Name: "ingress", Template: "ingress-nginx-4-12-1"}, {Name: "disabled", Template: "t", Disable: true}}
+	charts := []sveltosv1beta1.HelmChart{{ReleaseName: "ingress", ChartName: "ingress-nginx", ChartVersion: "4.12.1"}}
+
+	tests := []struct {
+		name         string
+		previous     *sveltosv1beta1.Spec
+		current      *sveltosv1beta1.Spec
+		wantRecorded bool
+	}{
+		{
+			name:    "profile without services created",
+			current: &sveltosv1beta1.Spec{HelmCharts: []sveltosv1beta1.HelmChart{}},
+		},
+		{
+			name:         "profile with services created",
+			current:      &sveltosv1beta1.Spec{HelmCharts: charts},
+			wantRecorded: true,
+		},
+		{
+			name:     "services unchanged",
+			previous: &sveltosv1beta1.Spec{HelmCharts: charts, SyncMode: sveltosv1beta1.SyncModeContinuous},
+			current:  &sveltosv1beta1.Spec{HelmCharts: charts, SyncMode: sveltosv1beta1.SyncModeOneTime},
+		},
+		{
+			name:         "services changed",
+			previous:     &sveltosv1beta1.Spec{HelmCharts: charts},
+			current:      &sveltosv1beta1.Spec{HelmCharts: []sveltosv1beta1.HelmChart{{ReleaseName: "ingress", ChartName: "ingress-nginx", ChartVersion: "4.13.0"}}},
+			wantRecorded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			recordServiceRollout(t.Context(), &audit.Recorder{Client: cl, SystemNamespace: "kcm-system"}, subject, services, tt.previous, tt.current)
+
+			events := new(kcm.AuditEventList)
+			g.Expect(cl.List(t.Context(), events)).To(Succeed())
+			if !tt.wantRecorded {
+				g.Expect(events.Items).To(BeEmpty())
+				return
+			}
+			g.Expect(events.Items).To(HaveLen(1))
+			g.Expect(events.Items[0].Spec.Action).To(Equal(kcm.AuditActionServiceRollout))
+			g.Expect(events.Items[0].Spec.Details).To(HaveKeyWithValue("services", "ingress=ingress-nginx-4-12-1"))
+		})
+	}
+}
@@ -25,19 +25,31 @@ import (
 	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
 )
 
-func getBackupTemplateSpec(ctx context.Context, cl client.Client) (*velerov1.BackupSpec, error) {
+func getBackupTemplateSpec(ctx context.Context, cl client.Client, scope *kcmv1alpha1.ManagementBackupScope) (*velerov1.BackupSpec, error) {
 	bs := &velerov1.BackupSpec{
 		IncludedNamespaces: []string{"*"},
 		ExcludedResources:  []string{"clusters.cluster.x-k8s.io"},
 		TTL:                metav1.Duration{Duration: 30 * 24 * time.Hour}, // velero's default, set it for the sake of UX
 	}
 
+	cdFilter, err := newClusterDeploymentFilter(scope)
+	if err != nil {
+		return nil, err
+	}
+	if scope != nil {
+		if len(scope.IncludedNamespaces) > 0 {
+			bs.IncludedNamespaces = scope.IncludedNamespaces
+		}
+		bs.ExcludedNamespaces = scope.ExcludedNamespaces
+	}
+
 	orSelectors := []*metav1.LabelSelector{
 		// fixed ones
 		selector(kcmv1alpha1.GenericComponentNameLabel, kcmv1alpha1.GenericComponentLabelValueKCM),
@@ -51,7 +63,7 @@ func getBackupTemplateSpec(ctx context.Context, cl client.Client) (*velerov1.Bac
 	}
 
 	if len(clusterTemplates.Items) == 0 { // just collect child clusters names
-		cldSelectors, err := getClusterDeploymentsSelectors(ctx, cl, "")
+		cldSelectors, err := getClusterDeploymentsSelectors(ctx, cl, "", cdFilter)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get selectors for all clusterdeployments: %w", err)
 		}
@@ -62,7 +74,7 @@ func getBackupTemplateSpec(ctx context.Context, cl client.Client) (*velerov1.Bac
 	}
 
 	for _, cltpl := range clusterTemplates.Items {
-		cldSelectors, err := getClusterDeploymentsSelectors(ctx, cl, cltpl.Name)
+		cldSelectors, err := getClusterDeploymentsSelectors(ctx, cl, cltpl.Name, cdFilter)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get selectors for clusterdeployments referencing %s clustertemplate: %w", client.ObjectKeyFromObject(&cltpl), err)
 		}
@@ -70,6 +82,9 @@ func getBackupTemplateSpec(ctx context.Context, cl client.Client) (*velerov1.Bac
 		// add only enabled providers
 		if len(cldSelectors) > 0 {
 			for _, provider := range cltpl.Status.Providers {
+				if scope != nil && len(scope.Providers) > 0 && !slices.Contains(scope.Providers, provider) {
+					continue
+				}
 				orSelectors = append(orSelectors, selector(clusterapiv1beta1.ProviderNameLabel, provider))
 			}
 		}
@@ -82,6 +97,34 @@ func getBackupTemplateSpec(ctx context.Context, cl client.Client) (*velerov1.Bac
 	return bs, nil
 }
 
+// clusterDeploymentFilter reports whether the clusters of the given
+// [github.com/K0rdent/kcm/api/v1alpha1.ClusterDeployment] are backed up.
+type clusterDeploymentFilter func(cd *kcmv1alpha1.ClusterDeployment) bool
+
+// newClusterDeploymentFilter returns the filter of the ClusterDeployments
+// selected by the scope of the backup.
+func newClusterDeploymentFilter(scope *kcmv1alpha1.ManagementBackupScope) (clusterDeploymentFilter, error) {
+	if scope == nil {
+		return func(*kcmv1alpha1.ClusterDeployment) bool { return true }, nil
+	}
+
+	cdSelector := labels.Everything()
+	if scope.ClusterDeploymentSelector != nil {
+		var err error
+		cdSelector, err = metav1.LabelSelectorAsSelector(scope.ClusterDeploymentSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ClusterDeployment selector: %w", err)
+		}
+	}
+
+	return func(cd *kcmv1alpha1.ClusterDeployment) bool {
+		if len(scope.IncludedNamespaces) > 0 && !slices.Contains(scope.IncludedNamespaces, "*") && !slices.Contains(scope.IncludedNamespaces, cd.Namespace) {
+			return false
+		}
+		return !slices.Contains(scope.ExcludedNamespaces, cd.Namespace) && cdSelector.Matches(labels.Set(cd.Labels))
+	}, nil
+}
+
 func sortDedup(selectors []*metav1.LabelSelector) []*metav1.LabelSelector {
 	const nonKubeSep = "_"
 
@@ -110,7 +153,7 @@ func sortDedup(selectors []*metav1.LabelSelector) []*metav1.LabelSelector {
 	)
 }
 
-func getClusterDeploymentsSelectors(ctx context.Context, cl client.Client, clusterTemplateRef string) ([]*metav1.LabelSelector, error) {
+func getClusterDeploymentsSelectors(ctx context.Context, cl client.Client, clusterTemplateRef string, filter clusterDeploymentFilter) ([]*metav1.LabelSelector, error) {
 	cldeploys := new(kcmv1alpha1.ClusterDeploymentList)
 	opts := []client.ListOption{}
 	if clusterTemplateRef != "" {
@@ -121,15 +164,26 @@ func getClusterDeploymentsSelectors(ctx context.Context, cl client.Client, clust
 		return nil, fmt.Errorf("failed to list ClusterDeployments: %w", err)
 	}
 
-	selectors := make([]*metav1.LabelSelector, len(cldeploys.Items)*2)
-	for i, cldeploy := range cldeploys.Items {
-		selectors[i*2] = selector(kcmv1alpha1.FluxHelmChartNameKey, cldeploy.Name)
-		selectors[i*2+1] = selector(clusterapiv1beta1.ClusterNameLabel, cldeploy.Name)
+	selectors := make([]*metav1.LabelSelector, 0, len(cldeploys.Items)*2)
+	for _, cldeploy := range cldeploys.Items {
+		if !filter(&cldeploy) {
+			continue
+		}
+		selectors = append(selectors, clusterDeploymentSelectors(cldeploy.Name)...)
 	}
 
 	return selectors, nil
 }
 
+// clusterDeploymentSelectors returns the selectors of the objects of the
+// cluster of the [github.com/K0rdent/kcm/api/v1alpha1.ClusterDeployment].
+func clusterDeploymentSelectors(name string) []*metav1.LabelSelector {
+	return []*metav1.LabelSelector{
+		selector(kcmv1alpha1.FluxHelmChartNameKey, name),
+		selector(clusterapiv1beta1.ClusterNameLabel, name),
+	}
+}
+
 func selector(k, v string) *metav1.LabelSelector {
 	return &metav1.LabelSelector{
 		MatchLabels: map[string]string{k: v},
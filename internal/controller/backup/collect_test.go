@@ -18,9 +18,106 @@ import (
 	"reflect"
 	"testing"
 
+	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
 )
 
+func Test_getBackupTemplateSpec(t *testing.T) {
+	newClusterDeployment := func(namespace, name, template string, labels map[string]string) *kcmv1alpha1.ClusterDeployment {
+		return &kcmv1alpha1.ClusterDeployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+			Spec:       kcmv1alpha1.ClusterDeploymentSpec{Template: template},
+		}
+	}
+	newClusterTemplate := func(namespace, name string, providers ...string) *kcmv1alpha1.ClusterTemplate {
+		return &kcmv1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Status:     kcmv1alpha1.ClusterTemplateStatus{Providers: providers},
+		}
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithIndex(&kcmv1alpha1.ClusterDeployment{}, kcmv1alpha1.ClusterDeploymentTemplateIndexKey, kcmv1alpha1.ExtractTemplateNameFromClusterDeployment).
+		WithObjects(
+			newClusterTemplate("prod", "aws", "infrastructure-aws", "bootstrap-k0sproject-k0smotron"),
+			newClusterTemplate("dev", "azure", "infrastructure-azure"),
+			newClusterDeployment("prod", "prod-1", "aws", map[string]string{"env": "prod"}),
+			newClusterDeployment("prod", "prod-2", "aws", map[string]string{"env": "staging"}),
+			newClusterDeployment("dev", "dev-1", "azure", nil),
+		).Build()
+
+	const (
+		chartLabel    = kcmv1alpha1.FluxHelmChartNameKey
+		clusterLabel  = clusterapiv1beta1.ClusterNameLabel
+		providerLabel = clusterapiv1beta1.ProviderNameLabel
+	)
+
+	for _, tc := range []struct {
+		scope              *kcmv1alpha1.ManagementBackupScope
+		name               string
+		includedNamespaces []string
+		selected           []*metav1.LabelSelector
+		notSelected        []*metav1.LabelSelector
+	}{
+		{
+			name:               "whole state",
+			includedNamespaces: []string{"*"},
+			selected: []*metav1.LabelSelector{
+				selector(clusterLabel, "prod-1"), selector(clusterLabel, "prod-2"), selector(clusterLabel, "dev-1"),
+				selector(providerLabel, "infrastructure-aws"), selector(providerLabel, "infrastructure-azure"),
+			},
+		},
+		{
+			name:               "selected clusters",
+			scope:              &kcmv1alpha1.ManagementBackupScope{ClusterDeploymentSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+			includedNamespaces: []string{"*"},
+			selected:           []*metav1.LabelSelector{selector(chartLabel, "prod-1"), selector(clusterLabel, "prod-1"), selector(providerLabel, "infrastructure-aws")},
+			notSelected: []*metav1.LabelSelector{
+				selector(clusterLabel, "prod-2"), selector(clusterLabel, "dev-1"), selector(providerLabel, "infrastructure-azure"),
+			},
+		},
+		{
+			name:               "selected namespaces and providers",
+			scope:              &kcmv1alpha1.ManagementBackupScope{IncludedNamespaces: []string{"prod", "kcm-system"}, Providers: []string{"infrastructure-aws"}},
+			includedNamespaces: []string{"prod", "kcm-system"},
+			selected:           []*metav1.LabelSelector{selector(clusterLabel, "prod-1"), selector(clusterLabel, "prod-2"), selector(providerLabel, "infrastructure-aws")},
+			notSelected: []*metav1.LabelSelector{
+				selector(clusterLabel, "dev-1"), selector(providerLabel, "infrastructure-azure"), selector(providerLabel, "bootstrap-k0sproject-k0smotron"),
+			},
+		},
+		{
+			name:               "excluded namespaces",
+			scope:              &kcmv1alpha1.ManagementBackupScope{ExcludedNamespaces: []string{"prod"}},
+			includedNamespaces: []string{"*"},
+			selected:           []*metav1.LabelSelector{selector(clusterLabel, "dev-1")},
+			notSelected:        []*metav1.LabelSelector{selector(clusterLabel, "prod-1"), selector(providerLabel, "infrastructure-aws")},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			spec, err := getBackupTemplateSpec(t.Context(), cl, tc.scope)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(spec.IncludedNamespaces).To(Equal(tc.includedNamespaces))
+			g.Expect(spec.OrLabelSelectors).To(ContainElements(selector(kcmv1alpha1.GenericComponentNameLabel, kcmv1alpha1.GenericComponentLabelValueKCM), selector(providerLabel, "cluster-api")))
+			g.Expect(spec.OrLabelSelectors).To(ContainElements(tc.selected))
+			for _, s := range tc.notSelected {
+				g.Expect(spec.OrLabelSelectors).NotTo(ContainElement(s))
+			}
+		})
+	}
+
+	_, err := getBackupTemplateSpec(t.Context(), cl, &kcmv1alpha1.ManagementBackupScope{
+		ClusterDeploymentSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Unknown"}}},
+	})
+	NewWithT(t).Expect(err).To(HaveOccurred())
+}
+
 func Test_sortDedup(t *testing.T) {
 	type testInput struct {
 		name      string
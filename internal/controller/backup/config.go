@@ -16,19 +16,38 @@ package backup
 
 import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/K0rdent/kcm/internal/notifications"
 )
 
 // Reconciler has logic to create and reconcile [github.com/vmware-tanzu/velero/pkg/apis/velero/v1.Backup] objects.
 type Reconciler struct {
-	cl client.Client
+	cl       client.Client
+	notifier *notifications.Notifier
 
 	systemNamespace string
 }
 
-// NewReconciler creates instance of the [Reconciler].
-func NewReconciler(cl client.Client, systemNamespace string) *Reconciler {
-	return &Reconciler{
+// ReconcilerOpt is a function which configures the [Reconciler].
+type ReconcilerOpt func(r *Reconciler)
+
+// NewReconciler creates instance of the [Reconciler] and configures it using the provided [ReconcilerOpt].
+func NewReconciler(cl client.Client, systemNamespace string, opts ...ReconcilerOpt) *Reconciler {
+	r := &Reconciler{
 		cl:              cl,
 		systemNamespace: systemNamespace,
 	}
+
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// WithNotifier configures the [Reconciler] to notify about the failed backups with the given notifier.
+func WithNotifier(notifier *notifications.Notifier) ReconcilerOpt {
+	return func(r *Reconciler) {
+		r.notifier = notifier
+	}
 }
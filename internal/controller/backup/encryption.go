@@ -0,0 +1,158 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+const (
+	// repositoryCredentialsSecretName is the name of the Secret Velero reads
+	// the password of the kopia and restic repositories from.
+	repositoryCredentialsSecretName = "velero-repo-credentials"
+	// repositoryPasswordKey is the key of the password in the repository credentials Secret.
+	repositoryPasswordKey = "repository-password"
+)
+
+// applyEncryption applies the backup encryption of the [github.com/K0rdent/kcm/api/v1alpha1.Management]
+// to the given storage location, the default one if empty, and to the
+// repository credentials of Velero.
+func (r *Reconciler) applyEncryption(ctx context.Context, storageLocation string) error {
+	mgmt := new(kcmv1alpha1.Management)
+	if err := r.cl.Get(ctx, client.ObjectKey{Name: kcmv1alpha1.ManagementName}, mgmt); err != nil {
+		return fmt.Errorf("failed to get Management: %w", err)
+	}
+
+	encryption := mgmt.Spec.BackupEncryption
+	if encryption == nil {
+		return nil
+	}
+
+	if encryption.KMSKeyID != "" {
+		if err := r.encryptStorageLocation(ctx, storageLocation, encryption.KMSKeyID); err != nil {
+			return err
+		}
+	}
+
+	if encryption.RepositoryPasswordSecretRef != nil {
+		if err := r.setRepositoryPassword(ctx, encryption.RepositoryPasswordSecretRef); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encryptStorageLocation sets the server-side encryption with the KMS key to
+// the config of the storage location.
+func (r *Reconciler) encryptStorageLocation(ctx context.Context, storageLocation, kmsKeyID string) error {
+	locations := new(velerov1.BackupStorageLocationList)
+	if err := r.cl.List(ctx, locations, client.InNamespace(r.systemNamespace)); err != nil {
+		return fmt.Errorf("failed to list velero BackupStorageLocations: %w", err)
+	}
+
+	var location *velerov1.BackupStorageLocation
+	for i, l := range locations.Items {
+		if l.Name == storageLocation || storageLocation == "" && l.Spec.Default {
+			location = &locations.Items[i]
+			break
+		}
+	}
+	if location == nil {
+		if storageLocation == "" {
+			return fmt.Errorf("failed to encrypt backups: no default velero BackupStorageLocation found in %s", r.systemNamespace)
+		}
+		return fmt.Errorf("failed to encrypt backups: velero BackupStorageLocation %s is not found in %s", storageLocation, r.systemNamespace)
+	}
+
+	encryptionConfig, err := serverSideEncryptionConfig(location.Spec.Provider, kmsKeyID)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt backups in velero BackupStorageLocation %s: %w", location.Name, err)
+	}
+
+	config := maps.Clone(location.Spec.Config)
+	if config == nil {
+		config = make(map[string]string, len(encryptionConfig))
+	}
+	maps.Copy(config, encryptionConfig)
+	if maps.Equal(config, location.Spec.Config) {
+		return nil
+	}
+
+	patch := client.MergeFrom(location.DeepCopy())
+	location.Spec.Config = config
+	if err := r.cl.Patch(ctx, location, patch); err != nil {
+		return fmt.Errorf("failed to set encryption config of velero BackupStorageLocation %s: %w", location.Name, err)
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Set server-side encryption of velero BackupStorageLocation", "storage_location", location.Name)
+	return nil
+}
+
+// serverSideEncryptionConfig returns the config of the storage location of
+// the provider encrypting the objects with the KMS key.
+func serverSideEncryptionConfig(provider, kmsKeyID string) (map[string]string, error) {
+	switch strings.TrimPrefix(provider, "velero.io/") {
+	case "aws":
+		return map[string]string{"serverSideEncryption": "aws:kms", "kmsKeyId": kmsKeyID}, nil
+	case "gcp":
+		return map[string]string{"kmsKeyName": kmsKeyID}, nil
+	default:
+		return nil, fmt.Errorf("server-side encryption with the KMS key is not supported for the %s provider", provider)
+	}
+}
+
+// setRepositoryPassword copies the password from the referenced Secret to
+// the repository credentials of Velero.
+func (r *Reconciler) setRepositoryPassword(ctx context.Context, ref *corev1.SecretKeySelector) error {
+	source := new(corev1.Secret)
+	if err := r.cl.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: r.systemNamespace}, source); err != nil {
+		return fmt.Errorf("failed to get repository password Secret %s: %w", ref.Name, err)
+	}
+
+	password, ok := source.Data[ref.Key]
+	if !ok || len(password) == 0 {
+		return fmt.Errorf("repository password Secret %s has no %s key", ref.Name, ref.Key)
+	}
+
+	credentials := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: repositoryCredentialsSecretName, Namespace: r.systemNamespace}}
+	op, err := controllerutil.CreateOrPatch(ctx, r.cl, credentials, func() error {
+		if credentials.Data == nil {
+			credentials.Data = make(map[string][]byte, 1)
+		}
+		credentials.Data[repositoryPasswordKey] = password
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set velero repository password: %w", err)
+	}
+
+	if op != controllerutil.OperationResultNone {
+		ctrl.LoggerFrom(ctx).Info("Set velero repository password", "secret", ref.Name)
+	}
+	return nil
+}
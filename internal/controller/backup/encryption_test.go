@@ -0,0 +1,134 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestApplyEncryption(t *testing.T) {
+	const systemNamespace = "kcm-system"
+
+	newManagement := func(encryption *kcmv1alpha1.BackupEncryption) *kcmv1alpha1.Management {
+		return &kcmv1alpha1.Management{
+			ObjectMeta: metav1.ObjectMeta{Name: kcmv1alpha1.ManagementName},
+			Spec:       kcmv1alpha1.ManagementSpec{BackupEncryption: encryption},
+		}
+	}
+	newLocation := func(name, provider string, isDefault bool) *velerov1.BackupStorageLocation {
+		return &velerov1.BackupStorageLocation{
+			ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: name},
+			Spec: velerov1.BackupStorageLocationSpec{
+				Provider: provider,
+				Default:  isDefault,
+				Config:   map[string]string{"region": "us-east-1"},
+			},
+		}
+	}
+	password := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: "repo-password"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+
+	for _, tc := range []struct {
+		encryption      *kcmv1alpha1.BackupEncryption
+		name            string
+		storageLocation string
+		expectedConfig  map[string]string
+		expectedErr     string
+	}{
+		{
+			name:           "no encryption",
+			expectedConfig: map[string]string{"region": "us-east-1"},
+		},
+		{
+			name:       "kms key of the default location",
+			encryption: &kcmv1alpha1.BackupEncryption{KMSKeyID: "arn:aws:kms:us-east-1:0:key/1"},
+			expectedConfig: map[string]string{
+				"region":               "us-east-1",
+				"serverSideEncryption": "aws:kms",
+				"kmsKeyId":             "arn:aws:kms:us-east-1:0:key/1",
+			},
+		},
+		{
+			name:            "unsupported provider",
+			encryption:      &kcmv1alpha1.BackupEncryption{KMSKeyID: "key"},
+			storageLocation: "azure",
+			expectedErr:     "not supported for the velero.io/azure provider",
+		},
+		{
+			name:            "missing location",
+			encryption:      &kcmv1alpha1.BackupEncryption{KMSKeyID: "key"},
+			storageLocation: "missing",
+			expectedErr:     "velero BackupStorageLocation missing is not found",
+		},
+		{
+			name: "repository password",
+			encryption: &kcmv1alpha1.BackupEncryption{RepositoryPasswordSecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: password.Name}, Key: "password",
+			}},
+			expectedConfig: map[string]string{"region": "us-east-1"},
+		},
+		{
+			name: "missing repository password key",
+			encryption: &kcmv1alpha1.BackupEncryption{RepositoryPasswordSecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: password.Name}, Key: "missing",
+			}},
+			expectedErr: "has no missing key",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				newManagement(tc.encryption),
+				newLocation("default", "aws", true),
+				newLocation("azure", "velero.io/azure", false),
+				password.DeepCopy(),
+			).Build()
+			r := NewReconciler(cl, systemNamespace)
+
+			err := r.applyEncryption(t.Context(), tc.storageLocation)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			location := new(velerov1.BackupStorageLocation)
+			g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: "default"}, location)).To(Succeed())
+			g.Expect(location.Spec.Config).To(Equal(tc.expectedConfig))
+
+			credentials := new(corev1.Secret)
+			err = cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: repositoryCredentialsSecretName}, credentials)
+			if tc.encryption == nil || tc.encryption.RepositoryPasswordSecretRef == nil {
+				g.Expect(err).To(MatchError(ContainSubstring("not found")))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(credentials.Data).To(HaveKeyWithValue(repositoryPasswordKey, []byte("secret")))
+		})
+	}
+}
@@ -31,6 +31,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/internal/notifications"
+	"github.com/K0rdent/kcm/internal/record"
 	"github.com/K0rdent/kcm/internal/utils"
 )
 
@@ -101,14 +104,86 @@ func (r *Reconciler) ReconcileBackup(ctx context.Context, mgmtBackup *kcmv1alpha
 	}
 
 	l.V(1).Info("Updating backup status")
+	failed := isBackupFailed(&veleroBackup.Status) && (mgmtBackup.Status.LastBackup == nil || mgmtBackup.Status.LastBackup.Phase != veleroBackup.Status.Phase)
+	succeeded := veleroBackup.Status.Phase == velerov1.BackupPhaseCompleted && mgmtBackup.Status.LastSuccessfulBackupName != veleroBackup.Name
 	mgmtBackup.Status.LastBackup = &veleroBackup.Status
+	message := updateBackupHealth(mgmtBackup, veleroBackup, succeeded, failed)
 	if err := r.cl.Status().Update(ctx, mgmtBackup); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to update ManagementBackup %s status: %w", mgmtBackup.Name, err)
 	}
 
+	metrics.TrackMetricManagementBackupFailed(ctx, mgmtBackup.Name, isBackupFailed(&veleroBackup.Status))
+	var lastSuccess time.Time
+	if mgmtBackup.Status.LastSuccessfulBackupTime != nil {
+		lastSuccess = mgmtBackup.Status.LastSuccessfulBackupTime.Time
+	}
+	metrics.TrackMetricManagementBackupHealth(ctx, mgmtBackup.Name, lastSuccess, mgmtBackup.Status.LastSuccessfulBackupItems, mgmtBackup.Status.ConsecutiveFailures)
+
+	if succeeded {
+		record.Event(mgmtBackup, kcmv1alpha1.BackupSucceededReason, message)
+	}
+	if failed {
+		record.Warn(mgmtBackup, kcmv1alpha1.BackupFailedReason, message)
+		if err := r.notifier.Notify(ctx, notifications.ObjectNotification(kcmv1alpha1.NotificationEventBackupFailed, kcmv1alpha1.ManagementBackupKind, mgmtBackup, message)); err != nil {
+			l.Error(err, "failed to send notification")
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// updateBackupHealth records the succeeded or failed backup in the status
+// of the [github.com/K0rdent/kcm/api/v1alpha1.ManagementBackup] and returns
+// the message describing the outcome of the backup.
+func updateBackupHealth(mgmtBackup *kcmv1alpha1.ManagementBackup, veleroBackup *velerov1.Backup, succeeded, failed bool) string {
+	switch {
+	case succeeded:
+		completed := veleroBackup.Status.CompletionTimestamp
+		if completed == nil {
+			completed = &metav1.Time{Time: time.Now().UTC()}
+		}
+		mgmtBackup.Status.LastSuccessfulBackupName = veleroBackup.Name
+		mgmtBackup.Status.LastSuccessfulBackupTime = completed
+		mgmtBackup.Status.LastSuccessfulBackupItems = 0
+		if veleroBackup.Status.Progress != nil {
+			mgmtBackup.Status.LastSuccessfulBackupItems = int32(veleroBackup.Status.Progress.ItemsBackedUp) //nolint:gosec // the number of items fits
+		}
+		mgmtBackup.Status.ConsecutiveFailures = 0
+
+		message := fmt.Sprintf("Backup %s has succeeded with %d items backed up", veleroBackup.Name, mgmtBackup.Status.LastSuccessfulBackupItems)
+		apimeta.SetStatusCondition(&mgmtBackup.Status.Conditions, metav1.Condition{
+			Type:               kcmv1alpha1.LastBackupSucceededCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             kcmv1alpha1.BackupSucceededReason,
+			Message:            message,
+			ObservedGeneration: mgmtBackup.Generation,
+		})
+		return message
+	case failed:
+		mgmtBackup.Status.ConsecutiveFailures++
+
+		message := fmt.Sprintf("Backup %s has finished in the %s phase", veleroBackup.Name, veleroBackup.Status.Phase)
+		if veleroBackup.Status.FailureReason != "" {
+			message += ": " + veleroBackup.Status.FailureReason
+		}
+		apimeta.SetStatusCondition(&mgmtBackup.Status.Conditions, metav1.Condition{
+			Type:               kcmv1alpha1.LastBackupSucceededCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             kcmv1alpha1.BackupFailedReason,
+			Message:            fmt.Sprintf("%s, %d backups have failed in a row", message, mgmtBackup.Status.ConsecutiveFailures),
+			ObservedGeneration: mgmtBackup.Generation,
+		})
+		return message
+	}
+	return ""
+}
+
+func isBackupFailed(status *velerov1.BackupStatus) bool {
+	return status.Phase == velerov1.BackupPhaseFailed ||
+		status.Phase == velerov1.BackupPhasePartiallyFailed ||
+		status.Phase == velerov1.BackupPhaseFailedValidation
+}
+
 func (r *Reconciler) updateAfterRestoration(ctx context.Context, mgmtBackup *kcmv1alpha1.ManagementBackup) (ctrl.Result, error) {
 	removeVeleroLabels := func() {
 		delete(mgmtBackup.Labels, velerov1.BackupNameLabel)
@@ -181,7 +256,7 @@ func (r *Reconciler) createScheduleBackup(ctx context.Context, mgmtBackup *kcmv1
 	now := time.Now().UTC()
 	backupName := mgmtBackup.TimestampedBackupName(now)
 
-	if err := r.createNewVeleroBackup(ctx, backupName, withScheduleLabel(mgmtBackup.Name), withStorageLocation(mgmtBackup.Spec.StorageLocation)); err != nil {
+	if err := r.createNewVeleroBackup(ctx, backupName, mgmtBackup.Spec.Scope, withScheduleLabel(mgmtBackup.Name), withStorageLocation(mgmtBackup.Spec.StorageLocation), withRetention(mgmtBackup.Spec.Retention)); err != nil {
 		if isMetaError(err) {
 			return r.propagateMetaError(ctx, mgmtBackup, err.Error())
 		}
@@ -200,7 +275,7 @@ func (r *Reconciler) createScheduleBackup(ctx context.Context, mgmtBackup *kcmv1
 }
 
 func (r *Reconciler) createSingleBackup(ctx context.Context, mgmtBackup *kcmv1alpha1.ManagementBackup) (ctrl.Result, error) {
-	if err := r.createNewVeleroBackup(ctx, mgmtBackup.Name, withStorageLocation(mgmtBackup.Spec.StorageLocation)); err != nil {
+	if err := r.createNewVeleroBackup(ctx, mgmtBackup.Name, mgmtBackup.Spec.Scope, withStorageLocation(mgmtBackup.Spec.StorageLocation), withRetention(mgmtBackup.Spec.Retention)); err != nil {
 		if isMetaError(err) {
 			return r.propagateMetaError(ctx, mgmtBackup, err.Error())
 		}
@@ -234,10 +309,18 @@ func withStorageLocation(loc string) createOpt {
 	}
 }
 
-func (r *Reconciler) createNewVeleroBackup(ctx context.Context, backupName string, createOpts ...createOpt) error {
+func withRetention(retention *metav1.Duration) createOpt {
+	return func(b *velerov1.Backup) {
+		if retention != nil {
+			b.Spec.TTL = *retention
+		}
+	}
+}
+
+func (r *Reconciler) createNewVeleroBackup(ctx context.Context, backupName string, scope *kcmv1alpha1.ManagementBackupScope, createOpts ...createOpt) error {
 	l := ctrl.LoggerFrom(ctx)
 
-	veleroBackup, err := r.getNewVeleroBackup(ctx, backupName)
+	veleroBackup, err := r.getNewVeleroBackup(ctx, backupName, scope)
 	if err != nil {
 		return err
 	}
@@ -246,6 +329,11 @@ func (r *Reconciler) createNewVeleroBackup(ctx context.Context, backupName strin
 		o(veleroBackup)
 	}
 
+	// backups are never stored unencrypted once the encryption is configured
+	if err := r.applyEncryption(ctx, veleroBackup.Spec.StorageLocation); err != nil {
+		return err
+	}
+
 	if err := r.cl.Create(ctx, veleroBackup); client.IgnoreAlreadyExists(err) != nil { // avoid err-loop on status update error
 		return fmt.Errorf("failed to create velero Backup: %w", err)
 	}
@@ -254,8 +342,8 @@ func (r *Reconciler) createNewVeleroBackup(ctx context.Context, backupName strin
 	return nil
 }
 
-func (r *Reconciler) getNewVeleroBackup(ctx context.Context, backupName string) (*velerov1.Backup, error) {
-	templateSpec, err := getBackupTemplateSpec(ctx, r.cl)
+func (r *Reconciler) getNewVeleroBackup(ctx context.Context, backupName string, scope *kcmv1alpha1.ManagementBackupScope) (*velerov1.Backup, error) {
+	templateSpec, err := getBackupTemplateSpec(ctx, r.cl, scope)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct velero backup spec: %w", err)
 	}
@@ -20,7 +20,12 @@ import (
 	"testing"
 	"time"
 
+	. "github.com/onsi/gomega"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
 )
 
 const tsFormat = "20060102150405"
@@ -78,6 +83,49 @@ func Test_getMostRecentProducedBackup(t *testing.T) {
 	}
 }
 
+func Test_updateBackupHealth(t *testing.T) {
+	g := NewWithT(t)
+
+	completed := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	newBackup := func(name string, phase velerov1.BackupPhase) *velerov1.Backup {
+		return &velerov1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: velerov1.BackupStatus{
+				Phase:               phase,
+				FailureReason:       "storage is unavailable",
+				CompletionTimestamp: &completed,
+				Progress:            &velerov1.BackupProgress{ItemsBackedUp: 42},
+			},
+		}
+	}
+
+	mgmtBackup := new(kcmv1alpha1.ManagementBackup)
+
+	for i := range 2 {
+		message := updateBackupHealth(mgmtBackup, newBackup("failed", velerov1.BackupPhasePartiallyFailed), false, true)
+		g.Expect(message).To(Equal("Backup failed has finished in the PartiallyFailed phase: storage is unavailable"))
+		g.Expect(mgmtBackup.Status.ConsecutiveFailures).To(Equal(int32(i + 1)))
+	}
+	cond := apimeta.FindStatusCondition(mgmtBackup.Status.Conditions, kcmv1alpha1.LastBackupSucceededCondition)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(cond.Reason).To(Equal(kcmv1alpha1.BackupFailedReason))
+	g.Expect(cond.Message).To(ContainSubstring("2 backups have failed in a row"))
+	g.Expect(mgmtBackup.Status.LastSuccessfulBackupTime).To(BeNil())
+
+	message := updateBackupHealth(mgmtBackup, newBackup("completed", velerov1.BackupPhaseCompleted), true, false)
+	g.Expect(message).To(Equal("Backup completed has succeeded with 42 items backed up"))
+	g.Expect(mgmtBackup.Status.ConsecutiveFailures).To(BeZero())
+	g.Expect(mgmtBackup.Status.LastSuccessfulBackupName).To(Equal("completed"))
+	g.Expect(mgmtBackup.Status.LastSuccessfulBackupTime).To(Equal(&completed))
+	g.Expect(mgmtBackup.Status.LastSuccessfulBackupItems).To(Equal(int32(42)))
+	g.Expect(apimeta.IsStatusConditionTrue(mgmtBackup.Status.Conditions, kcmv1alpha1.LastBackupSucceededCondition)).To(BeTrue())
+
+	// the backup in progress does not change the health
+	g.Expect(updateBackupHealth(mgmtBackup, newBackup("running", velerov1.BackupPhaseInProgress), false, false)).To(BeEmpty())
+	g.Expect(mgmtBackup.Status.LastSuccessfulBackupName).To(Equal("completed"))
+}
+
 func futureName() createOpt {
 	return func(b *velerov1.Backup) {
 		rn := time.Duration((rand.Intn(100) + 1) * int(time.Minute))
@@ -0,0 +1,269 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// restoreRequeueInterval is the interval the download of the backup contents
+// and the progress of the Velero Restore are checked with.
+const restoreRequeueInterval = 10 * time.Second
+
+// ReconcileRestore builds the report of the changes the [github.com/K0rdent/kcm/api/v1alpha1.ManagementRestore]
+// makes and creates the Velero Restore unless it is a dry-run or the overwrite of the existing objects
+// is not confirmed.
+func (r *Reconciler) ReconcileRestore(ctx context.Context, mgmtRestore *kcmv1alpha1.ManagementRestore) (ctrl.Result, error) {
+	if mgmtRestore == nil {
+		return ctrl.Result{}, nil
+	}
+
+	switch {
+	case mgmtRestore.Status.Phase == kcmv1alpha1.ManagementRestorePhaseCompleted,
+		mgmtRestore.Status.Phase == kcmv1alpha1.ManagementRestorePhaseFailed:
+		return ctrl.Result{}, nil
+	case mgmtRestore.Status.RestoreName != "":
+		return r.updateRestoreStatus(ctx, mgmtRestore)
+	case mgmtRestore.Status.BackupName == "":
+		return r.resolveRestoreBackup(ctx, mgmtRestore)
+	case mgmtRestore.Status.Report == nil:
+		return r.analyzeRestore(ctx, mgmtRestore)
+	default:
+		return r.startRestore(ctx, mgmtRestore, nil)
+	}
+}
+
+// resolveRestoreBackup picks the restored backup, either the given one or
+// the last backup of the [github.com/K0rdent/kcm/api/v1alpha1.ManagementBackup]
+// started by the point in time.
+func (r *Reconciler) resolveRestoreBackup(ctx context.Context, mgmtRestore *kcmv1alpha1.ManagementRestore) (ctrl.Result, error) {
+	pointInTime := mgmtRestore.Spec.PointInTime
+	if pointInTime == nil {
+		mgmtRestore.Status.BackupName = mgmtRestore.Spec.BackupName
+		return r.analyzeRestore(ctx, mgmtRestore)
+	}
+
+	backups := new(velerov1.BackupList)
+	if err := r.cl.List(ctx, backups, client.InNamespace(r.systemNamespace)); err != nil {
+		if isMetaError(err) {
+			return r.failRestore(ctx, mgmtRestore, "Probably Velero is not installed: "+err.Error())
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to list velero Backups: %w", err)
+	}
+
+	var latest *velerov1.Backup
+	for i, b := range backups.Items {
+		if b.Name != pointInTime.ManagementBackup && b.Labels[scheduleMgmtNameLabel] != pointInTime.ManagementBackup {
+			continue
+		}
+		if b.Status.Phase != velerov1.BackupPhaseCompleted || b.Status.StartTimestamp == nil || b.Status.StartTimestamp.After(pointInTime.Time.Time) {
+			continue
+		}
+		if latest == nil || b.Status.StartTimestamp.After(latest.Status.StartTimestamp.Time) {
+			latest = &backups.Items[i]
+		}
+	}
+	if latest == nil {
+		return r.failRestore(ctx, mgmtRestore, fmt.Sprintf("No backup of ManagementBackup %s has been completed by %s",
+			pointInTime.ManagementBackup, pointInTime.Time.UTC().Format(time.RFC3339)))
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Restoring backup of the point in time", "backup_name", latest.Name, "backup_time", latest.Status.StartTimestamp)
+	mgmtRestore.Status.BackupName = latest.Name
+	return r.analyzeRestore(ctx, mgmtRestore)
+}
+
+// analyzeRestore requests the contents of the backup from Velero and compares
+// them with the objects of the management cluster.
+func (r *Reconciler) analyzeRestore(ctx context.Context, mgmtRestore *kcmv1alpha1.ManagementRestore) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+
+	downloadRequest := new(velerov1.DownloadRequest)
+	key := client.ObjectKey{Name: mgmtRestore.Name, Namespace: r.systemNamespace}
+	if err := r.cl.Get(ctx, key, downloadRequest); err != nil {
+		if isMetaError(err) && !apierrors.IsNotFound(err) {
+			return r.failRestore(ctx, mgmtRestore, "Probably Velero is not installed: "+err.Error())
+		}
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to get velero DownloadRequest %s: %w", key, err)
+		}
+
+		downloadRequest = &velerov1.DownloadRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: velerov1.DownloadRequestSpec{
+				Target: velerov1.DownloadTarget{
+					Kind: velerov1.DownloadTargetKindBackupContents,
+					Name: mgmtRestore.Status.BackupName,
+				},
+			},
+		}
+		if err := r.cl.Create(ctx, downloadRequest); err != nil {
+			if isMetaError(err) {
+				return r.failRestore(ctx, mgmtRestore, "Probably Velero is not installed: "+err.Error())
+			}
+			return ctrl.Result{}, fmt.Errorf("failed to create velero DownloadRequest %s: %w", key, err)
+		}
+		l.V(1).Info("Requested backup contents", "backup_name", mgmtRestore.Status.BackupName)
+
+		return r.setRestorePhase(ctx, mgmtRestore, kcmv1alpha1.ManagementRestorePhaseAnalyzing)
+	}
+
+	if downloadRequest.Status.Phase != velerov1.DownloadRequestPhaseProcessed || downloadRequest.Status.DownloadURL == "" {
+		return ctrl.Result{RequeueAfter: restoreRequeueInterval}, nil
+	}
+
+	// the objects of the whole backup are compared as they are downloaded, the
+	// ones of the single ClusterDeployment are selected once all of them are read
+	report := newRestoreReportBuilder(r.cl)
+	target := mgmtRestore.Spec.ClusterDeployment
+	var items []*unstructured.Unstructured
+	err := downloadBackupContents(ctx, downloadRequest.Status.DownloadURL, func(item *unstructured.Unstructured) error {
+		if target == nil {
+			return report.add(ctx, item)
+		}
+		items = append(items, item)
+		return nil
+	})
+	// the download URL expires, the contents are requested again on failure
+	if deleteErr := r.cl.Delete(ctx, downloadRequest); client.IgnoreNotFound(deleteErr) != nil {
+		l.Error(deleteErr, "failed to delete velero DownloadRequest", "download_request", key)
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to analyze contents of backup %s: %w", mgmtRestore.Status.BackupName, err)
+	}
+
+	if target != nil {
+		var found bool
+		if items, found = clusterDeploymentItems(items, target); !found {
+			return r.failRestore(ctx, mgmtRestore, fmt.Sprintf("ClusterDeployment %s/%s is not found in backup %s", target.Namespace, target.Name, mgmtRestore.Status.BackupName))
+		}
+		for _, item := range items {
+			if err := report.add(ctx, item); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to analyze contents of backup %s: %w", mgmtRestore.Status.BackupName, err)
+			}
+		}
+	}
+
+	mgmtRestore.Status.Report = report.build()
+
+	l.Info("Built restore report", "to_create", mgmtRestore.Status.Report.ToCreate, "differ", mgmtRestore.Status.Report.ToOverwrite,
+		"unchanged", mgmtRestore.Status.Report.Unchanged)
+	return r.startRestore(ctx, mgmtRestore, items)
+}
+
+// startRestore creates the Velero Restore once the restore is neither a
+// dry-run nor awaits the confirmation of the overwrite. The items are the
+// objects of the backup restored along with the ClusterDeployment if they
+// have been just analyzed.
+func (r *Reconciler) startRestore(ctx context.Context, mgmtRestore *kcmv1alpha1.ManagementRestore, items []*unstructured.Unstructured) (ctrl.Result, error) {
+	applyRestorePolicy(mgmtRestore.Status.Report, mgmtRestore.Spec.ExistingResourcePolicy)
+
+	switch {
+	case mgmtRestore.Spec.DryRun:
+		return r.setRestorePhase(ctx, mgmtRestore, kcmv1alpha1.ManagementRestorePhaseAnalyzed)
+	case mgmtRestore.Status.Report.ToOverwrite > 0 && !mgmtRestore.Spec.ConfirmOverwrite:
+		return r.setRestorePhase(ctx, mgmtRestore, kcmv1alpha1.ManagementRestorePhaseAwaitingConfirmation)
+	}
+
+	veleroRestore := &velerov1.Restore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mgmtRestore.Name,
+			Namespace: r.systemNamespace,
+		},
+		Spec: velerov1.RestoreSpec{
+			BackupName:             mgmtRestore.Status.BackupName,
+			ExistingResourcePolicy: mgmtRestore.Spec.ExistingResourcePolicy,
+		},
+	}
+
+	target := mgmtRestore.Spec.ClusterDeployment
+	if target != nil {
+		// the objects restored by kcm itself are taken from the contents of the backup,
+		// which are requested again once the overwrite has been confirmed
+		if items == nil {
+			mgmtRestore.Status.Report = nil
+			return r.analyzeRestore(ctx, mgmtRestore)
+		}
+		veleroRestore.Spec.IncludedNamespaces = []string{target.Namespace}
+		veleroRestore.Spec.OrLabelSelectors = clusterDeploymentSelectors(target.Name)
+	}
+
+	if err := r.cl.Create(ctx, veleroRestore); client.IgnoreAlreadyExists(err) != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create velero Restore: %w", err)
+	}
+	ctrl.LoggerFrom(ctx).Info("Velero Restore has been created", "restore_name", client.ObjectKeyFromObject(veleroRestore))
+
+	if target != nil {
+		// the Velero Restore already exists once retried
+		if err := r.restoreClusterDeploymentItems(ctx, veleroRestore, items, target); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	mgmtRestore.Status.RestoreName = veleroRestore.Name
+	return r.setRestorePhase(ctx, mgmtRestore, kcmv1alpha1.ManagementRestorePhaseRestoring)
+}
+
+// updateRestoreStatus reflects the phase of the Velero Restore.
+func (r *Reconciler) updateRestoreStatus(ctx context.Context, mgmtRestore *kcmv1alpha1.ManagementRestore) (ctrl.Result, error) {
+	veleroRestore := new(velerov1.Restore)
+	if err := r.cl.Get(ctx, client.ObjectKey{Name: mgmtRestore.Status.RestoreName, Namespace: r.systemNamespace}, veleroRestore); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.failRestore(ctx, mgmtRestore, fmt.Sprintf("Restore %s is not found", mgmtRestore.Status.RestoreName))
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get velero Restore: %w", err)
+	}
+
+	switch veleroRestore.Status.Phase {
+	case velerov1.RestorePhaseCompleted:
+		return r.setRestorePhase(ctx, mgmtRestore, kcmv1alpha1.ManagementRestorePhaseCompleted)
+	case velerov1.RestorePhasePartiallyFailed, velerov1.RestorePhaseFailed, velerov1.RestorePhaseFailedValidation:
+		message := fmt.Sprintf("Restore %s has finished in the %s phase", veleroRestore.Name, veleroRestore.Status.Phase)
+		if veleroRestore.Status.FailureReason != "" {
+			message += ": " + veleroRestore.Status.FailureReason
+		}
+		return r.failRestore(ctx, mgmtRestore, message)
+	default:
+		return ctrl.Result{RequeueAfter: restoreRequeueInterval}, nil
+	}
+}
+
+func (r *Reconciler) failRestore(ctx context.Context, mgmtRestore *kcmv1alpha1.ManagementRestore, message string) (ctrl.Result, error) {
+	mgmtRestore.Status.Error = message
+	return r.setRestorePhase(ctx, mgmtRestore, kcmv1alpha1.ManagementRestorePhaseFailed)
+}
+
+func (r *Reconciler) setRestorePhase(ctx context.Context, mgmtRestore *kcmv1alpha1.ManagementRestore, phase kcmv1alpha1.ManagementRestorePhase) (ctrl.Result, error) {
+	mgmtRestore.Status.Phase = phase
+	if err := r.cl.Status().Update(ctx, mgmtRestore); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update ManagementRestore %s status: %w", mgmtRestore.Name, err)
+	}
+
+	if phase == kcmv1alpha1.ManagementRestorePhaseAnalyzing || phase == kcmv1alpha1.ManagementRestorePhaseRestoring {
+		return ctrl.Result{RequeueAfter: restoreRequeueInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// clusterDeploymentItems returns the objects of the backup restored along
+// with the [github.com/K0rdent/kcm/api/v1alpha1.ClusterDeployment]: its
+// Credential with the identity, the ClusterDeployment itself and the objects
+// of its cluster. The ClusterDeployment not being in the backup is reported.
+func clusterDeploymentItems(items []*unstructured.Unstructured, target *kcmv1alpha1.ManagementRestoreClusterDeployment) ([]*unstructured.Unstructured, bool) {
+	find := func(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return nil
+		}
+		for _, item := range items {
+			if item.GroupVersionKind().Group == gv.Group && item.GetKind() == kind && item.GetNamespace() == namespace && item.GetName() == name {
+				return item
+			}
+		}
+		return nil
+	}
+
+	cd := find(kcmv1alpha1.GroupVersion.String(), kcmv1alpha1.ClusterDeploymentKind, target.Namespace, target.Name)
+	if cd == nil {
+		return nil, false
+	}
+
+	// the dependencies precede the ClusterDeployment, so they are restored first
+	var restored []*unstructured.Unstructured
+	seen := make(map[restoredItemKey]struct{})
+	add := func(item *unstructured.Unstructured) {
+		key := restoredItemKey{
+			GroupKind: item.GroupVersionKind().GroupKind(),
+			namespace: item.GetNamespace(),
+			name:      item.GetName(),
+		}
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		restored = append(restored, item)
+	}
+
+	if credentialName, _, _ := unstructured.NestedString(cd.Object, "spec", "credential"); credentialName != "" {
+		if cred := find(kcmv1alpha1.GroupVersion.String(), kcmv1alpha1.CredentialKind, target.Namespace, credentialName); cred != nil {
+			ref, _, _ := unstructured.NestedStringMap(cred.Object, "spec", "identityRef")
+			if identity := find(ref["apiVersion"], ref["kind"], ref["namespace"], ref["name"]); identity != nil {
+				add(identity)
+			}
+			add(cred)
+		}
+	}
+	add(cd)
+
+	for _, item := range items {
+		if item.GetNamespace() == target.Namespace && isClusterDeploymentObject(item, target.Name) {
+			add(item)
+		}
+	}
+
+	return restored, true
+}
+
+// restoredItemKey identifies the object of the backup regardless of the
+// version it has been read in.
+type restoredItemKey struct {
+	schema.GroupKind
+	namespace, name string
+}
+
+// isClusterDeploymentObject reports whether the object belongs to the
+// cluster of the [github.com/K0rdent/kcm/api/v1alpha1.ClusterDeployment],
+// such objects are selected by [clusterDeploymentSelectors].
+func isClusterDeploymentObject(obj client.Object, clusterDeploymentName string) bool {
+	labels := obj.GetLabels()
+	return labels[kcmv1alpha1.FluxHelmChartNameKey] == clusterDeploymentName || labels[clusterapiv1beta1.ClusterNameLabel] == clusterDeploymentName
+}
+
+// restoreClusterDeploymentItems restores the objects Velero cannot select by
+// labels, i.e. the ClusterDeployment and its Credential with the identity,
+// from the objects of the backup. The objects are labeled the same way Velero
+// labels the restored ones, so the ClusterDeployment is not reconciled until
+// the Velero Restore has completed.
+func (r *Reconciler) restoreClusterDeploymentItems(ctx context.Context, veleroRestore *velerov1.Restore, items []*unstructured.Unstructured, target *kcmv1alpha1.ManagementRestoreClusterDeployment) error {
+	l := ctrl.LoggerFrom(ctx)
+
+	for _, item := range items {
+		if isClusterDeploymentObject(item, target.Name) {
+			continue
+		}
+
+		obj := restoredObject(item, veleroRestore)
+		key := client.ObjectKeyFromObject(obj)
+		err := r.cl.Create(ctx, obj)
+		switch {
+		case err == nil:
+			l.V(1).Info("Restored object", "kind", obj.GetKind(), "object", key)
+			continue
+		case !apierrors.IsAlreadyExists(err):
+			return fmt.Errorf("failed to restore %s %s: %w", obj.GetKind(), key, err)
+		case veleroRestore.Spec.ExistingResourcePolicy != velerov1.PolicyTypeUpdate:
+			continue
+		}
+
+		existing := new(unstructured.Unstructured)
+		existing.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := r.cl.Get(ctx, key, existing); err != nil {
+			return fmt.Errorf("failed to get %s %s: %w", obj.GetKind(), key, err)
+		}
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		if err := r.cl.Update(ctx, obj); err != nil {
+			return fmt.Errorf("failed to overwrite %s %s: %w", obj.GetKind(), key, err)
+		}
+		l.V(1).Info("Overwritten object", "kind", obj.GetKind(), "object", key)
+	}
+
+	return nil
+}
+
+// restoredObject returns the copy of the backed up object ready to be
+// created, without the status and the metadata set by the API server.
+func restoredObject(item *unstructured.Unstructured, veleroRestore *velerov1.Restore) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: item.DeepCopy().Object}
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "deletionTimestamp", "deletionGracePeriodSeconds", "managedFields", "selfLink"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[velerov1.BackupNameLabel] = veleroRestore.Spec.BackupName
+	labels[velerov1.RestoreNameLabel] = veleroRestore.Name
+	obj.SetLabels(labels)
+
+	return obj
+}
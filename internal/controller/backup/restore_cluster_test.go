@@ -0,0 +1,208 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestReconcileClusterDeploymentRestore(t *testing.T) {
+	const (
+		systemNamespace = "kcm-system"
+		namespace       = "team"
+	)
+
+	newClusterDeployment := func(name string) *kcmv1alpha1.ClusterDeployment {
+		return &kcmv1alpha1.ClusterDeployment{
+			TypeMeta: metav1.TypeMeta{APIVersion: kcmv1alpha1.GroupVersion.String(), Kind: kcmv1alpha1.ClusterDeploymentKind},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: name, UID: "uid", ResourceVersion: "42",
+				Labels: map[string]string{kcmv1alpha1.GenericComponentNameLabel: kcmv1alpha1.GenericComponentLabelValueKCM},
+			},
+			Spec: kcmv1alpha1.ClusterDeploymentSpec{Template: "aws", Credential: "aws-cred"},
+		}
+	}
+	credential := &kcmv1alpha1.Credential{
+		TypeMeta:   metav1.TypeMeta{APIVersion: kcmv1alpha1.GroupVersion.String(), Kind: kcmv1alpha1.CredentialKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "aws-cred"},
+		Spec: kcmv1alpha1.CredentialSpec{
+			IdentityRef: &corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: systemNamespace, Name: "aws-identity"},
+		},
+	}
+	identity := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: "aws-identity"},
+		Data:       map[string][]byte{"credentials": []byte("secret")},
+	}
+	newConfigMap := func(name string, labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		}
+	}
+
+	contents := backupContents(t,
+		backupObject{resource: "clusterdeployments.k0rdent.mirantis.com", obj: newClusterDeployment("dev")},
+		backupObject{resource: "clusterdeployments.k0rdent.mirantis.com", obj: newClusterDeployment("prod")},
+		backupObject{resource: "credentials.k0rdent.mirantis.com", obj: credential},
+		backupObject{resource: "secrets", obj: identity},
+		backupObject{resource: "configmaps", obj: newConfigMap("dev-machine", map[string]string{clusterapiv1beta1.ClusterNameLabel: "dev"})},
+		backupObject{resource: "configmaps", obj: newConfigMap("prod-machine", map[string]string{clusterapiv1beta1.ClusterNameLabel: "prod"})},
+		backupObject{resource: "configmaps", obj: newConfigMap("unrelated", nil)},
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(contents)
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Now().Truncate(time.Second)
+	newBackup := func(name string, phase velerov1.BackupPhase, started time.Time) *velerov1.Backup {
+		return &velerov1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: name, Labels: map[string]string{scheduleMgmtNameLabel: "daily"}},
+			Status:     velerov1.BackupStatus{Phase: phase, StartTimestamp: &metav1.Time{Time: started}},
+		}
+	}
+	newRestore := func(name, clusterDeployment string, restoreTime time.Time) *kcmv1alpha1.ManagementRestore {
+		return &kcmv1alpha1.ManagementRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kcmv1alpha1.ManagementRestoreSpec{
+				PointInTime:            &kcmv1alpha1.ManagementRestorePointInTime{ManagementBackup: "daily", Time: metav1.NewTime(restoreTime)},
+				ClusterDeployment:      &kcmv1alpha1.ManagementRestoreClusterDeployment{Namespace: namespace, Name: clusterDeployment},
+				ExistingResourcePolicy: velerov1.PolicyTypeNone,
+			},
+		}
+	}
+	newClient := func(mgmtRestore *kcmv1alpha1.ManagementRestore) client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(
+				mgmtRestore,
+				&velerov1.DownloadRequest{
+					ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: mgmtRestore.Name},
+					Status:     velerov1.DownloadRequestStatus{Phase: velerov1.DownloadRequestPhaseProcessed, DownloadURL: server.URL},
+				},
+				newBackup("daily-1", velerov1.BackupPhaseCompleted, now.Add(-3*time.Hour)),
+				newBackup("daily-2", velerov1.BackupPhaseCompleted, now.Add(-2*time.Hour)),
+				newBackup("daily-3", velerov1.BackupPhaseFailed, now.Add(-90*time.Minute)),
+				newBackup("daily-4", velerov1.BackupPhaseCompleted, now.Add(-time.Hour)),
+				newClusterDeployment("prod"),
+			).
+			WithStatusSubresource(mgmtRestore).Build()
+	}
+
+	t.Run("restores the ClusterDeployment of the point in time", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mgmtRestore := newRestore("dev", "dev", now.Add(-80*time.Minute))
+		cl := newClient(mgmtRestore)
+		r := NewReconciler(cl, systemNamespace)
+
+		_, err := r.ReconcileRestore(t.Context(), mgmtRestore)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(mgmtRestore.Status.BackupName).To(Equal("daily-2"))
+		g.Expect(mgmtRestore.Status.Phase).To(Equal(kcmv1alpha1.ManagementRestorePhaseRestoring))
+		g.Expect(mgmtRestore.Status.Report.ToCreate).To(Equal(int32(4)))
+		g.Expect(mgmtRestore.Status.Report.Unchanged + mgmtRestore.Status.Report.Conflicts).To(BeZero())
+
+		veleroRestore := new(velerov1.Restore)
+		g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: mgmtRestore.Name}, veleroRestore)).To(Succeed())
+		g.Expect(veleroRestore.Spec.BackupName).To(Equal("daily-2"))
+		g.Expect(veleroRestore.Spec.IncludedNamespaces).To(ConsistOf(namespace))
+		g.Expect(veleroRestore.Spec.OrLabelSelectors).To(ConsistOf(clusterDeploymentSelectors("dev")))
+
+		cd := new(kcmv1alpha1.ClusterDeployment)
+		g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: namespace, Name: "dev"}, cd)).To(Succeed())
+		g.Expect(cd.Spec.Credential).To(Equal("aws-cred"))
+		g.Expect(cd.UID).NotTo(Equal(types.UID("uid")))
+		g.Expect(cd.Labels).To(HaveKeyWithValue(velerov1.RestoreNameLabel, veleroRestore.Name))
+		g.Expect(cd.Labels).To(HaveKeyWithValue(velerov1.BackupNameLabel, "daily-2"))
+		g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(credential), new(kcmv1alpha1.Credential))).To(Succeed())
+		g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(identity), new(corev1.Secret))).To(Succeed())
+
+		// the objects of the cluster are restored by Velero
+		g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: namespace, Name: "dev-machine"}, new(corev1.ConfigMap))).To(HaveOccurred())
+		g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: namespace, Name: "unrelated"}, new(corev1.ConfigMap))).To(HaveOccurred())
+	})
+
+	t.Run("ClusterDeployment is not in the backup", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mgmtRestore := newRestore("missing", "missing", now)
+		r := NewReconciler(newClient(mgmtRestore), systemNamespace)
+
+		_, err := r.ReconcileRestore(t.Context(), mgmtRestore)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(mgmtRestore.Status.BackupName).To(Equal("daily-4"))
+		g.Expect(mgmtRestore.Status.Phase).To(Equal(kcmv1alpha1.ManagementRestorePhaseFailed))
+		g.Expect(mgmtRestore.Status.Error).To(Equal("ClusterDeployment team/missing is not found in backup daily-4"))
+	})
+
+	t.Run("no backup by the point in time", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mgmtRestore := newRestore("early", "dev", now.Add(-4*time.Hour))
+		r := NewReconciler(newClient(mgmtRestore), systemNamespace)
+
+		_, err := r.ReconcileRestore(t.Context(), mgmtRestore)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(mgmtRestore.Status.Phase).To(Equal(kcmv1alpha1.ManagementRestorePhaseFailed))
+		g.Expect(mgmtRestore.Status.Error).To(HavePrefix("No backup of ManagementBackup daily has been completed by"))
+	})
+}
+
+func Test_clusterDeploymentItems(t *testing.T) {
+	g := NewWithT(t)
+
+	newItem := func(apiVersion, kind, name string, labels map[string]string) *unstructured.Unstructured {
+		item := new(unstructured.Unstructured)
+		item.SetAPIVersion(apiVersion)
+		item.SetKind(kind)
+		item.SetNamespace("team")
+		item.SetName(name)
+		item.SetLabels(labels)
+		return item
+	}
+
+	clusterLabels := map[string]string{clusterapiv1beta1.ClusterNameLabel: "dev"}
+	cd := newItem(kcmv1alpha1.GroupVersion.String(), kcmv1alpha1.ClusterDeploymentKind, "dev", clusterLabels)
+	configMap := newItem("v1", "ConfigMap", "dev", clusterLabels)
+
+	// the same objects read more than once, e.g. in the different versions
+	items := []*unstructured.Unstructured{
+		cd,
+		newItem("k0rdent.mirantis.com/v1beta1", kcmv1alpha1.ClusterDeploymentKind, "dev", clusterLabels),
+		configMap,
+		newItem("v1", "ConfigMap", "dev", clusterLabels),
+	}
+
+	restored, found := clusterDeploymentItems(items, &kcmv1alpha1.ManagementRestoreClusterDeployment{Namespace: "team", Name: "dev"})
+	g.Expect(found).To(BeTrue())
+	g.Expect(restored).To(Equal([]*unstructured.Unstructured{cd, configMap}))
+}
@@ -0,0 +1,225 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// maxRestoreReportItems limits the number of the objects listed in the report.
+const maxRestoreReportItems = 100
+
+// nonRestorableResources are the resources Velero never restores.
+var nonRestorableResources = []string{
+	"nodes",
+	"events",
+	"events.events.k8s.io",
+	"backups.velero.io",
+	"restores.velero.io",
+	"resticrepositories.velero.io",
+	"backuprepositories.velero.io",
+	"csinodes.storage.k8s.io",
+	"volumeattachments.storage.k8s.io",
+}
+
+// backupContentsClient downloads the backup contents, the timeout bounds both
+// the download and the comparison of the objects read along with it.
+var backupContentsClient = &http.Client{Timeout: 10 * time.Minute}
+
+// downloadBackupContents downloads the tarball of the backup contents and
+// calls fn for each of the restorable objects of the backup as they are read.
+func downloadBackupContents(ctx context.Context, url string, fn func(*unstructured.Unstructured) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to construct request: %w", err)
+	}
+
+	resp, err := backupContentsClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download backup contents: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download backup contents: unexpected status %s", resp.Status)
+	}
+
+	return readBackupContents(resp.Body, fn)
+}
+
+// readBackupContents reads the objects from the gzipped tarball of the backup
+// contents and calls fn for each of them. Velero stores each of the objects
+// both as resources/<resource>/namespaces/<namespace>/<name>.json (or
+// resources/<resource>/cluster/<name>.json) and under the directory of each of
+// the backed up versions of the resource, the copy of the preferred version of
+// which is read only.
+func readBackupContents(r io.Reader, fn func(*unstructured.Unstructured) error) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read gzipped backup contents: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read backup contents: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg || !isRestorableItem(hdr.Name) {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("failed to read %s from backup contents: %w", hdr.Name, err)
+		}
+
+		item := new(unstructured.Unstructured)
+		if err := item.UnmarshalJSON(data); err != nil {
+			return fmt.Errorf("failed to decode %s from backup contents: %w", hdr.Name, err)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+}
+
+func isRestorableItem(name string) bool {
+	parts := strings.Split(path.Clean(name), "/")
+	return len(parts) >= 5 && parts[0] == "resources" && path.Ext(name) == ".json" &&
+		!slices.Contains(nonRestorableResources, parts[1]) &&
+		strings.HasSuffix(parts[2], velerov1.PreferredVersionDir)
+}
+
+// restoreReportBuilder builds the report of the restore comparing the objects
+// of the backup with the existing ones one by one, so the objects of the
+// backup are not kept in memory.
+type restoreReportBuilder struct {
+	cl     client.Client
+	report kcmv1alpha1.ManagementRestoreReport
+	differ []kcmv1alpha1.ManagementRestoreItem
+}
+
+func newRestoreReportBuilder(cl client.Client) *restoreReportBuilder {
+	return &restoreReportBuilder{cl: cl}
+}
+
+// add compares the object of the backup with the existing one. The objects
+// the existing ones of which cannot be read are considered to differ from them.
+func (b *restoreReportBuilder) add(ctx context.Context, item *unstructured.Unstructured) error {
+	live := new(unstructured.Unstructured)
+	live.SetGroupVersionKind(item.GroupVersionKind())
+	err := b.cl.Get(ctx, client.ObjectKeyFromObject(item), live)
+	switch {
+	case apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err):
+		b.report.ToCreate++
+		return nil
+	case apierrors.IsForbidden(err):
+		// the existing object is not readable by the controller
+	case err != nil:
+		return fmt.Errorf("failed to get %s %s: %w", item.GetKind(), client.ObjectKeyFromObject(item), err)
+	case equality.Semantic.DeepEqual(restoredContent(item), restoredContent(live)):
+		b.report.Unchanged++
+		return nil
+	}
+
+	b.report.ToOverwrite++
+	b.differ = append(b.differ, kcmv1alpha1.ManagementRestoreItem{
+		APIVersion: item.GetAPIVersion(),
+		Kind:       item.GetKind(),
+		Namespace:  item.GetNamespace(),
+		Name:       item.GetName(),
+		Action:     kcmv1alpha1.ManagementRestoreActionOverwrite,
+	})
+	if len(b.differ) >= 2*maxRestoreReportItems {
+		b.truncate()
+	}
+	return nil
+}
+
+// build returns the report with the first of the differing objects.
+func (b *restoreReportBuilder) build() *kcmv1alpha1.ManagementRestoreReport {
+	b.truncate()
+	report := b.report
+	report.GeneratedAt = metav1.Now()
+	report.Items = b.differ
+	return &report
+}
+
+// truncate leaves the first of the differing objects in their order only.
+func (b *restoreReportBuilder) truncate() {
+	slices.SortFunc(b.differ, func(a, b kcmv1alpha1.ManagementRestoreItem) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	if len(b.differ) > maxRestoreReportItems {
+		b.differ = b.differ[:maxRestoreReportItems]
+	}
+}
+
+// restoredContent returns the content of the object compared upon restore,
+// the metadata and the status are set by the management cluster itself.
+func restoredContent(obj *unstructured.Unstructured) map[string]any {
+	content := make(map[string]any, len(obj.Object))
+	for k, v := range obj.Object {
+		if k == "metadata" || k == "status" {
+			continue
+		}
+		content[k] = v
+	}
+	return content
+}
+
+// applyRestorePolicy sets the action on the objects of the report differing
+// from the existing ones according to the existing resource policy, such
+// objects are overwritten with the update policy and are in conflict otherwise.
+func applyRestorePolicy(report *kcmv1alpha1.ManagementRestoreReport, policy velerov1.PolicyType) {
+	differ := report.ToOverwrite + report.Conflicts
+	action := kcmv1alpha1.ManagementRestoreActionConflict
+	report.ToOverwrite, report.Conflicts = 0, differ
+	if policy == velerov1.PolicyTypeUpdate {
+		action = kcmv1alpha1.ManagementRestoreActionOverwrite
+		report.ToOverwrite, report.Conflicts = differ, 0
+	}
+
+	for i := range report.Items {
+		report.Items[i].Action = action
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestReconcileRestore(t *testing.T) {
+	const systemNamespace = "kcm-system"
+
+	newConfigMap := func(name, value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: name},
+			Data:       map[string]string{"key": value},
+		}
+	}
+	template := &kcmv1alpha1.ClusterTemplate{
+		TypeMeta:   metav1.TypeMeta{APIVersion: kcmv1alpha1.GroupVersion.String(), Kind: kcmv1alpha1.ClusterTemplateKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: "aws"},
+	}
+
+	contents := backupContents(t,
+		backupObject{resource: "configmaps", obj: newConfigMap("unchanged", "a")},
+		backupObject{resource: "configmaps", obj: newConfigMap("changed", "b")},
+		backupObject{resource: "events", obj: newConfigMap("event", "c")},
+		backupObject{resource: "clustertemplates.k0rdent.mirantis.com", obj: template, versions: []string{"v1beta1"}},
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(contents)
+	}))
+	t.Cleanup(server.Close)
+
+	newDownloadRequest := func(name string) *velerov1.DownloadRequest {
+		return &velerov1.DownloadRequest{
+			ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: name},
+			Status:     velerov1.DownloadRequestStatus{Phase: velerov1.DownloadRequestPhaseProcessed, DownloadURL: server.URL},
+		}
+	}
+	newRestore := func(name string, spec kcmv1alpha1.ManagementRestoreSpec) *kcmv1alpha1.ManagementRestore {
+		spec.BackupName = "backup"
+		return &kcmv1alpha1.ManagementRestore{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec}
+	}
+
+	t.Run("dry-run", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mgmtRestore := newRestore("dry-run", kcmv1alpha1.ManagementRestoreSpec{DryRun: true, ExistingResourcePolicy: velerov1.PolicyTypeNone})
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(mgmtRestore, newDownloadRequest(mgmtRestore.Name), newConfigMap("unchanged", "a"), newConfigMap("changed", "a")).
+			WithStatusSubresource(mgmtRestore).Build()
+		r := NewReconciler(cl, systemNamespace)
+
+		_, err := r.ReconcileRestore(t.Context(), mgmtRestore)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Expect(mgmtRestore.Status.Phase).To(Equal(kcmv1alpha1.ManagementRestorePhaseAnalyzed))
+		report := mgmtRestore.Status.Report
+		g.Expect(report).NotTo(BeNil())
+		g.Expect(report.ToCreate).To(Equal(int32(1)))
+		g.Expect(report.Conflicts).To(Equal(int32(1)))
+		g.Expect(report.ToOverwrite).To(BeZero())
+		g.Expect(report.Unchanged).To(Equal(int32(1)))
+		g.Expect(report.Items).To(ConsistOf(kcmv1alpha1.ManagementRestoreItem{
+			APIVersion: "v1", Kind: "ConfigMap", Namespace: systemNamespace, Name: "changed", Action: kcmv1alpha1.ManagementRestoreActionConflict,
+		}))
+
+		g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: mgmtRestore.Name}, new(velerov1.DownloadRequest))).
+			To(MatchError(ContainSubstring("not found")))
+		g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: mgmtRestore.Name}, new(velerov1.Restore))).
+			To(HaveOccurred())
+	})
+
+	t.Run("overwrite requires confirmation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mgmtRestore := newRestore("overwrite", kcmv1alpha1.ManagementRestoreSpec{ExistingResourcePolicy: velerov1.PolicyTypeUpdate})
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(mgmtRestore, newDownloadRequest(mgmtRestore.Name), newConfigMap("unchanged", "a"), newConfigMap("changed", "a")).
+			WithStatusSubresource(mgmtRestore).Build()
+		r := NewReconciler(cl, systemNamespace)
+
+		_, err := r.ReconcileRestore(t.Context(), mgmtRestore)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(mgmtRestore.Status.Phase).To(Equal(kcmv1alpha1.ManagementRestorePhaseAwaitingConfirmation))
+		g.Expect(mgmtRestore.Status.Report.ToOverwrite).To(Equal(int32(1)))
+		g.Expect(mgmtRestore.Status.Report.Conflicts).To(BeZero())
+		g.Expect(mgmtRestore.Status.Report.Items).To(HaveLen(1))
+		g.Expect(mgmtRestore.Status.Report.Items[0].Action).To(Equal(kcmv1alpha1.ManagementRestoreActionOverwrite))
+
+		restoreKey := client.ObjectKey{Namespace: systemNamespace, Name: mgmtRestore.Name}
+		g.Expect(cl.Get(t.Context(), restoreKey, new(velerov1.Restore))).To(HaveOccurred())
+
+		mgmtRestore.Spec.ConfirmOverwrite = true
+		_, err = r.ReconcileRestore(t.Context(), mgmtRestore)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(mgmtRestore.Status.Phase).To(Equal(kcmv1alpha1.ManagementRestorePhaseRestoring))
+		g.Expect(mgmtRestore.Status.RestoreName).To(Equal(mgmtRestore.Name))
+
+		veleroRestore := new(velerov1.Restore)
+		g.Expect(cl.Get(t.Context(), restoreKey, veleroRestore)).To(Succeed())
+		g.Expect(veleroRestore.Spec.BackupName).To(Equal("backup"))
+		g.Expect(veleroRestore.Spec.ExistingResourcePolicy).To(Equal(velerov1.PolicyTypeUpdate))
+
+		veleroRestore.Status.Phase = velerov1.RestorePhaseCompleted
+		g.Expect(cl.Update(t.Context(), veleroRestore)).To(Succeed())
+
+		_, err = r.ReconcileRestore(t.Context(), mgmtRestore)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(mgmtRestore.Status.Phase).To(Equal(kcmv1alpha1.ManagementRestorePhaseCompleted))
+	})
+
+	t.Run("no overwrite restores right away", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mgmtRestore := newRestore("create", kcmv1alpha1.ManagementRestoreSpec{ExistingResourcePolicy: velerov1.PolicyTypeNone})
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(mgmtRestore, newDownloadRequest(mgmtRestore.Name), newConfigMap("changed", "a")).
+			WithStatusSubresource(mgmtRestore).Build()
+		r := NewReconciler(cl, systemNamespace)
+
+		_, err := r.ReconcileRestore(t.Context(), mgmtRestore)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(mgmtRestore.Status.Phase).To(Equal(kcmv1alpha1.ManagementRestorePhaseRestoring))
+		g.Expect(mgmtRestore.Status.Report.ToCreate).To(Equal(int32(2)))
+		g.Expect(mgmtRestore.Status.Report.Conflicts).To(Equal(int32(1)))
+	})
+}
+
+// backupObject is the object of the backed up resource.
+type backupObject struct {
+	obj      client.Object
+	resource string
+	// versions are the versions of the resource besides the preferred one the
+	// object is stored in, as with the EnableAPIGroupVersions feature of Velero
+	versions []string
+}
+
+// backupContents builds the gzipped tarball of the backup contents with the
+// given objects laid out the way Velero does.
+func backupContents(t *testing.T, objects ...backupObject) []byte {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+	write := func(name string, data []byte) {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("failed to write header of %s: %v", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	write("metadata/version", []byte("1.1.0"))
+	for _, o := range objects {
+		data, err := json.Marshal(o.obj)
+		if err != nil {
+			t.Fatalf("failed to marshal %s %s: %v", o.resource, client.ObjectKeyFromObject(o.obj), err)
+		}
+
+		scope := "cluster"
+		if o.obj.GetNamespace() != "" {
+			scope = path.Join("namespaces", o.obj.GetNamespace())
+		}
+		file := path.Join(scope, o.obj.GetName()+".json")
+
+		write(path.Join(velerov1.ResourcesDir, o.resource, file), data)
+		write(path.Join(velerov1.ResourcesDir, o.resource, o.obj.GetObjectKind().GroupVersionKind().Version+velerov1.PreferredVersionDir, file), data)
+		for _, version := range o.versions {
+			write(path.Join(velerov1.ResourcesDir, o.resource, version, file), data)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,319 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+const (
+	// standbyRequeueInterval is the interval the newer backups of the primary
+	// cluster are looked up with.
+	standbyRequeueInterval = time.Minute
+
+	// standbyPausedLabel marks the objects paused by the standby restores.
+	standbyPausedLabel = "k0rdent.mirantis.com/standby-paused"
+
+	// standbyResourceModifiersName is the name of the ConfigMap of the
+	// resource modifiers of the standby restores.
+	standbyResourceModifiersName = "kcm-standby-resource-modifiers"
+)
+
+// standbyPausedResource is the resource paused while restored on standby,
+// so the controllers of the standby cluster do not manage the clusters of the
+// primary one.
+type standbyPausedResource struct {
+	gvk           schema.GroupVersionKind
+	groupResource string
+	// field is the boolean field of the spec pausing the object.
+	field string
+}
+
+var standbyPausedResources = []standbyPausedResource{
+	{
+		gvk:           schema.GroupVersionKind{Group: "cluster.x-k8s.io", Version: "v1beta1", Kind: "Cluster"},
+		groupResource: "clusters.cluster.x-k8s.io",
+		field:         "paused",
+	},
+	{
+		gvk:           schema.GroupVersionKind{Group: "lib.projectsveltos.io", Version: "v1beta1", Kind: "SveltosCluster"},
+		groupResource: "sveltosclusters.lib.projectsveltos.io",
+		field:         "paused",
+	},
+	{
+		gvk:           schema.GroupVersionKind{Group: "helm.toolkit.fluxcd.io", Version: "v2", Kind: "HelmRelease"},
+		groupResource: "helmreleases.helm.toolkit.fluxcd.io",
+		field:         "suspend",
+	},
+}
+
+// standbyExcludedResources are the resources of the standby cluster itself
+// which are not restored from the backups of the primary cluster.
+var standbyExcludedResources = []string{
+	"managements.k0rdent.mirantis.com",
+	"managementbackups.k0rdent.mirantis.com",
+	"managementrestores.k0rdent.mirantis.com",
+}
+
+// ReconcileStandby continuously restores the backups of the primary cluster
+// onto the standby [github.com/K0rdent/kcm/api/v1alpha1.Management] and resumes the paused objects once
+// the standby cluster is promoted.
+func (r *Reconciler) ReconcileStandby(ctx context.Context, mgmt *kcmv1alpha1.Management) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+
+	patch := client.MergeFrom(mgmt.DeepCopy())
+	standby := mgmt.Spec.Standby
+	if standby == nil {
+		if mgmt.Status.Standby == nil {
+			return ctrl.Result{}, nil
+		}
+		mgmt.Status.Standby = nil
+		return ctrl.Result{}, r.patchStandbyStatus(ctx, mgmt, patch)
+	}
+
+	if mgmt.Status.Standby == nil {
+		mgmt.Status.Standby = new(kcmv1alpha1.StandbyStatus)
+	}
+	status := mgmt.Status.Standby
+	if status.PromotionTime != nil {
+		return ctrl.Result{}, nil
+	}
+
+	// the restore in progress pauses the objects again, so it has to be
+	// finished before the promotion
+	if status.LastRestoreName != "" && !isRestoreFinished(status.LastRestorePhase) {
+		veleroRestore := new(velerov1.Restore)
+		err := r.cl.Get(ctx, client.ObjectKey{Name: status.LastRestoreName, Namespace: r.systemNamespace}, veleroRestore)
+		switch {
+		case apierrors.IsNotFound(err):
+			status.LastRestorePhase = velerov1.RestorePhaseFailed
+			status.Error = fmt.Sprintf("Restore %s is not found", status.LastRestoreName)
+		case err != nil:
+			return ctrl.Result{}, fmt.Errorf("failed to get velero Restore %s: %w", status.LastRestoreName, err)
+		default:
+			status.LastRestorePhase = veleroRestore.Status.Phase
+			status.Error = ""
+			if veleroRestore.Status.Phase != velerov1.RestorePhaseCompleted && isRestoreFinished(veleroRestore.Status.Phase) {
+				status.Error = fmt.Sprintf("Restore %s has finished in the %s phase", veleroRestore.Name, veleroRestore.Status.Phase)
+				if veleroRestore.Status.FailureReason != "" {
+					status.Error += ": " + veleroRestore.Status.FailureReason
+				}
+			}
+		}
+
+		if err := r.patchStandbyStatus(ctx, mgmt, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+		if !isRestoreFinished(status.LastRestorePhase) {
+			return ctrl.Result{RequeueAfter: restoreRequeueInterval}, nil
+		}
+		patch = client.MergeFrom(mgmt.DeepCopy())
+		status = mgmt.Status.Standby
+	}
+
+	if standby.Promote {
+		if err := r.resumeStandbyObjects(ctx); err != nil {
+			status.Error = "Failed to resume the paused objects: " + err.Error()
+			if patchErr := r.patchStandbyStatus(ctx, mgmt, patch); patchErr != nil {
+				return ctrl.Result{}, errors.Join(err, patchErr)
+			}
+			return ctrl.Result{}, err
+		}
+
+		now := metav1.Now()
+		status.PromotionTime = &now
+		status.Error = ""
+		if err := r.patchStandbyStatus(ctx, mgmt, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		l.Info("Standby management cluster has been promoted", "last_restored_backup", status.LastRestoredBackup)
+		return ctrl.Result{}, nil
+	}
+
+	latest, err := r.latestScheduledBackup(ctx, standby.BackupSchedule)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if latest == nil || latest.Name == status.LastRestoredBackup {
+		return ctrl.Result{RequeueAfter: standbyRequeueInterval}, nil
+	}
+
+	if err := r.ensureStandbyResourceModifiers(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// only the restore of the last backup is kept
+	if status.LastRestoreName != "" {
+		previous := &velerov1.Restore{ObjectMeta: metav1.ObjectMeta{Name: status.LastRestoreName, Namespace: r.systemNamespace}}
+		if err := r.cl.Delete(ctx, previous); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete previous velero Restore %s: %w", previous.Name, err)
+		}
+	}
+
+	veleroRestore := &velerov1.Restore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "standby-" + latest.Name,
+			Namespace: r.systemNamespace,
+		},
+		Spec: velerov1.RestoreSpec{
+			BackupName:             latest.Name,
+			ExcludedResources:      standbyExcludedResources,
+			ExistingResourcePolicy: velerov1.PolicyTypeUpdate,
+			ResourceModifier: &corev1.TypedLocalObjectReference{
+				Kind: "ConfigMap",
+				Name: standbyResourceModifiersName,
+			},
+		},
+	}
+	if err := r.cl.Create(ctx, veleroRestore); client.IgnoreAlreadyExists(err) != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create velero Restore: %w", err)
+	}
+	l.Info("Restoring backup of the primary cluster", "backup_name", latest.Name, "restore_name", veleroRestore.Name)
+
+	now := metav1.Now()
+	status.LastRestoredBackup = latest.Name
+	status.LastRestoreName = veleroRestore.Name
+	status.LastRestoreTime = &now
+	status.LastRestorePhase = velerov1.RestorePhaseNew
+	if err := r.patchStandbyStatus(ctx, mgmt, patch); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: restoreRequeueInterval}, nil
+}
+
+func (r *Reconciler) patchStandbyStatus(ctx context.Context, mgmt *kcmv1alpha1.Management, patch client.Patch) error {
+	if err := r.cl.Status().Patch(ctx, mgmt, patch); err != nil {
+		return fmt.Errorf("failed to update Management %s standby status: %w", mgmt.Name, err)
+	}
+	return nil
+}
+
+func isRestoreFinished(phase velerov1.RestorePhase) bool {
+	return phase == velerov1.RestorePhaseCompleted ||
+		phase == velerov1.RestorePhasePartiallyFailed ||
+		phase == velerov1.RestorePhaseFailed ||
+		phase == velerov1.RestorePhaseFailedValidation
+}
+
+// latestScheduledBackup returns the most recent completed backup of the
+// scheduled ManagementBackup, nil if there is none.
+func (r *Reconciler) latestScheduledBackup(ctx context.Context, schedule string) (*velerov1.Backup, error) {
+	backups := new(velerov1.BackupList)
+	if err := r.cl.List(ctx, backups, client.InNamespace(r.systemNamespace), client.MatchingLabels{scheduleMgmtNameLabel: schedule}); err != nil {
+		return nil, fmt.Errorf("failed to list velero Backups: %w", err)
+	}
+
+	var latest *velerov1.Backup
+	for i, backup := range backups.Items {
+		if backup.Status.Phase != velerov1.BackupPhaseCompleted || backup.Status.StartTimestamp == nil {
+			continue
+		}
+		if latest == nil || backup.Status.StartTimestamp.After(latest.Status.StartTimestamp.Time) {
+			latest = &backups.Items[i]
+		}
+	}
+
+	return latest, nil
+}
+
+// ensureStandbyResourceModifiers ensures the ConfigMap of the resource
+// modifiers pausing and labeling the restored objects of the paused resources.
+func (r *Reconciler) ensureStandbyResourceModifiers(ctx context.Context) error {
+	rules := make([]any, 0, len(standbyPausedResources))
+	for _, res := range standbyPausedResources {
+		patchData, err := json.Marshal(map[string]any{
+			"metadata": map[string]any{"labels": map[string]string{standbyPausedLabel: "true"}},
+			"spec":     map[string]any{res.field: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal resource modifier of %s: %w", res.groupResource, err)
+		}
+
+		rules = append(rules, map[string]any{
+			"conditions":   map[string]any{"groupResource": res.groupResource},
+			"mergePatches": []any{map[string]any{"patchData": string(patchData)}},
+		})
+	}
+
+	modifiers, err := yaml.Marshal(map[string]any{"version": "v1", "resourceModifierRules": rules})
+	if err != nil {
+		return fmt.Errorf("failed to marshal resource modifiers: %w", err)
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: standbyResourceModifiersName, Namespace: r.systemNamespace}}
+	if _, err := controllerutil.CreateOrPatch(ctx, r.cl, cm, func() error {
+		cm.Data = map[string]string{"resource-modifiers.yaml": string(modifiers)}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile ConfigMap %s of resource modifiers: %w", standbyResourceModifiersName, err)
+	}
+
+	return nil
+}
+
+// resumeStandbyObjects unpauses the objects paused by the standby restores.
+func (r *Reconciler) resumeStandbyObjects(ctx context.Context) error {
+	var errs error
+	for _, res := range standbyPausedResources {
+		list := new(unstructured.UnstructuredList)
+		list.SetGroupVersionKind(res.gvk.GroupVersion().WithKind(res.gvk.Kind + "List"))
+		if err := r.cl.List(ctx, list, client.HasLabels{standbyPausedLabel}); err != nil {
+			if apimeta.IsNoMatchError(err) {
+				continue
+			}
+			errs = errors.Join(errs, fmt.Errorf("failed to list %s: %w", res.groupResource, err))
+			continue
+		}
+
+		patchData, err := json.Marshal(map[string]any{
+			"metadata": map[string]any{"labels": map[string]any{standbyPausedLabel: nil}},
+			"spec":     map[string]any{res.field: false},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal patch of %s: %w", res.groupResource, err)
+		}
+
+		for _, obj := range list.Items {
+			if err := r.cl.Patch(ctx, &obj, client.RawPatch(types.MergePatchType, patchData)); client.IgnoreNotFound(err) != nil {
+				errs = errors.Join(errs, fmt.Errorf("failed to resume %s %s: %w", res.gvk.Kind, client.ObjectKeyFromObject(&obj), err))
+				continue
+			}
+			ctrl.LoggerFrom(ctx).Info("Resumed object paused on standby", "kind", res.gvk.Kind, "object", client.ObjectKeyFromObject(&obj))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,132 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	hcv2 "github.com/fluxcd/helm-controller/api/v2"
+	. "github.com/onsi/gomega"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestReconcileStandby(t *testing.T) {
+	g := NewWithT(t)
+
+	const systemNamespace = "kcm-system"
+
+	now := time.Now()
+	newBackup := func(name string, phase velerov1.BackupPhase, started time.Time) *velerov1.Backup {
+		return &velerov1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: name, Labels: map[string]string{scheduleMgmtNameLabel: "daily"}},
+			Status:     velerov1.BackupStatus{Phase: phase, StartTimestamp: &metav1.Time{Time: started}},
+		}
+	}
+	pausedCluster := &clusterapiv1beta1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "restored", Labels: map[string]string{standbyPausedLabel: "true"}},
+		Spec:       clusterapiv1beta1.ClusterSpec{Paused: true},
+	}
+	otherCluster := &clusterapiv1beta1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "paused-by-user"},
+		Spec:       clusterapiv1beta1.ClusterSpec{Paused: true},
+	}
+	suspendedRelease := &hcv2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "restored", Labels: map[string]string{standbyPausedLabel: "true"}},
+		Spec:       hcv2.HelmReleaseSpec{Suspend: true},
+	}
+	mgmt := &kcmv1alpha1.Management{
+		ObjectMeta: metav1.ObjectMeta{Name: kcmv1alpha1.ManagementName},
+		Spec:       kcmv1alpha1.ManagementSpec{Standby: &kcmv1alpha1.StandbyManagement{BackupSchedule: "daily"}},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithObjects(
+			mgmt,
+			newBackup("daily-1", velerov1.BackupPhaseCompleted, now.Add(-2*time.Hour)),
+			newBackup("daily-2", velerov1.BackupPhaseCompleted, now.Add(-time.Hour)),
+			newBackup("daily-3", velerov1.BackupPhaseFailed, now),
+			pausedCluster, otherCluster, suspendedRelease,
+		).
+		WithStatusSubresource(mgmt).Build()
+	r := NewReconciler(cl, systemNamespace)
+
+	// the latest completed backup is restored
+	res, err := r.ReconcileStandby(t.Context(), mgmt)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(restoreRequeueInterval))
+	g.Expect(mgmt.Status.Standby).NotTo(BeNil())
+	g.Expect(mgmt.Status.Standby.LastRestoredBackup).To(Equal("daily-2"))
+	g.Expect(mgmt.Status.Standby.LastRestoreName).To(Equal("standby-daily-2"))
+
+	veleroRestore := new(velerov1.Restore)
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: "standby-daily-2"}, veleroRestore)).To(Succeed())
+	g.Expect(veleroRestore.Spec.BackupName).To(Equal("daily-2"))
+	g.Expect(veleroRestore.Spec.ExistingResourcePolicy).To(Equal(velerov1.PolicyTypeUpdate))
+	g.Expect(veleroRestore.Spec.ExcludedResources).To(ContainElement("managements.k0rdent.mirantis.com"))
+	g.Expect(veleroRestore.Spec.ResourceModifier.Name).To(Equal(standbyResourceModifiersName))
+
+	modifiers := new(corev1.ConfigMap)
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: standbyResourceModifiersName}, modifiers)).To(Succeed())
+	g.Expect(modifiers.Data["resource-modifiers.yaml"]).To(ContainSubstring("groupResource: clusters.cluster.x-k8s.io"))
+	g.Expect(modifiers.Data["resource-modifiers.yaml"]).To(ContainSubstring(`"spec":{"suspend":true}`))
+
+	// the restore in progress is waited for before the promotion
+	mgmt.Spec.Standby.Promote = true
+	g.Expect(cl.Update(t.Context(), mgmt)).To(Succeed())
+	res, err = r.ReconcileStandby(t.Context(), mgmt)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(restoreRequeueInterval))
+	g.Expect(mgmt.Status.Standby.PromotionTime).To(BeNil())
+
+	veleroRestore.Status.Phase = velerov1.RestorePhaseCompleted
+	g.Expect(cl.Update(t.Context(), veleroRestore)).To(Succeed())
+
+	res, err = r.ReconcileStandby(t.Context(), mgmt)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(BeZero())
+	g.Expect(mgmt.Status.Standby.LastRestorePhase).To(Equal(velerov1.RestorePhaseCompleted))
+	g.Expect(mgmt.Status.Standby.PromotionTime).NotTo(BeNil())
+
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(pausedCluster), pausedCluster)).To(Succeed())
+	g.Expect(pausedCluster.Spec.Paused).To(BeFalse())
+	g.Expect(pausedCluster.Labels).NotTo(HaveKey(standbyPausedLabel))
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(otherCluster), otherCluster)).To(Succeed())
+	g.Expect(otherCluster.Spec.Paused).To(BeTrue())
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(suspendedRelease), suspendedRelease)).To(Succeed())
+	g.Expect(suspendedRelease.Spec.Suspend).To(BeFalse())
+
+	// no restores after the promotion
+	g.Expect(cl.Create(t.Context(), newBackup("daily-4", velerov1.BackupPhaseCompleted, now.Add(time.Minute)))).To(Succeed())
+	_, err = r.ReconcileStandby(t.Context(), mgmt)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mgmt.Status.Standby.LastRestoredBackup).To(Equal("daily-2"))
+
+	// the status is removed along with the standby mode
+	mgmt.Spec.Standby = nil
+	g.Expect(cl.Update(t.Context(), mgmt)).To(Succeed())
+	_, err = r.ReconcileStandby(t.Context(), mgmt)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(mgmt), mgmt)).To(Succeed())
+	g.Expect(mgmt.Status.Standby).To(BeNil())
+}
@@ -0,0 +1,212 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/certificates"
+	"github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+const (
+	defaultCertificatesRotateBefore = 30 * 24 * time.Hour
+	certificatesCheckInterval       = 12 * time.Hour
+	certificatesCheckPollPeriod     = 5 * time.Minute
+	// certificatesRotationCooldown prevents triggering the rollout again
+	// while the previously triggered one is still in progress.
+	certificatesRotationCooldown = 24 * time.Hour
+)
+
+// CertificatesReconciler tracks the expiration of the certificates of the
+// clusters of the ClusterDeployments and triggers the rollout of their
+// control planes before the certificates expire if the rotation is enabled.
+type CertificatesReconciler struct {
+	client.Client
+
+	// serverCertificateExpiry returns the expiration time of the serving certificate of the cluster API server.
+	serverCertificateExpiry func(ctx context.Context, cluster client.ObjectKey) (time.Time, error)
+}
+
+func (r *CertificatesReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+	l.Info("Reconciling ClusterDeployment certificates")
+
+	cd := new(kcm.ClusterDeployment)
+	if err := r.Get(ctx, req.NamespacedName, cd); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			metrics.DeleteMetricClusterCertificatesDaysRemaining(req.Namespace, req.Name)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !cd.DeletionTimestamp.IsZero() || cd.Spec.DryRun {
+		metrics.DeleteMetricClusterCertificatesDaysRemaining(cd.Namespace, cd.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if !apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.ReadyCondition) {
+		l.V(1).Info("ClusterDeployment is not ready yet, postponing the certificates check")
+		return ctrl.Result{RequeueAfter: certificatesCheckPollPeriod}, nil
+	}
+
+	expiry, err := r.certificatesExpiry(ctx, cd)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if cd.Status.Certificates == nil {
+		cd.Status.Certificates = new(kcm.CertificatesStatus)
+	}
+	remaining := time.Until(expiry)
+	cd.Status.Certificates.ExpirationTime = &metav1.Time{Time: expiry}
+	cd.Status.Certificates.DaysRemaining = int32(remaining / (24 * time.Hour))
+	metrics.TrackMetricClusterCertificatesDaysRemaining(ctx, cd.Namespace, cd.Name, cd.Status.Certificates.DaysRemaining)
+
+	rotateBefore := defaultCertificatesRotateBefore
+	if cd.Spec.CertificateRotation != nil && cd.Spec.CertificateRotation.RotateBefore != nil {
+		rotateBefore = cd.Spec.CertificateRotation.RotateBefore.Duration
+	}
+
+	condition := metav1.Condition{
+		Type:               kcm.CertificatesValidCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             kcm.SucceededReason,
+		Message:            fmt.Sprintf("Certificates expire in %d days", cd.Status.Certificates.DaysRemaining),
+		ObservedGeneration: cd.Generation,
+	}
+	requeueAfter := min(certificatesCheckInterval, remaining-rotateBefore)
+
+	if remaining < rotateBefore {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = kcm.CertificatesExpiringReason
+		requeueAfter = certificatesCheckPollPeriod
+
+		if cd.Spec.CertificateRotation != nil {
+			if err := r.rotate(ctx, cd); err != nil {
+				condition.Reason = kcm.FailedReason
+				condition.Message = fmt.Sprintf("Failed to rotate the certificates expiring in %d days: %v", cd.Status.Certificates.DaysRemaining, err)
+			} else {
+				condition.Reason = kcm.CertificatesRotatingReason
+				condition.Message += ", rotation triggered at " + cd.Status.Certificates.LastRotationTime.UTC().Format(time.RFC3339)
+			}
+		}
+	}
+	apimeta.SetStatusCondition(cd.GetConditions(), condition)
+
+	if err := r.Status().Update(ctx, cd); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status for clusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// certificatesExpiry returns the earliest expiration time of the control
+// plane certificates of the cluster of the given ClusterDeployment.
+func (r *CertificatesReconciler) certificatesExpiry(ctx context.Context, cd *kcm.ClusterDeployment) (time.Time, error) {
+	list := new(unstructured.UnstructuredList)
+	list.SetGroupVersionKind(clusterapiv1beta1.GroupVersion.WithKind("MachineList"))
+	if err := r.List(ctx, list, client.InNamespace(cd.Namespace), client.MatchingLabels{kcm.ClusterNameLabelKey: cd.Name}, client.HasLabels{clusterapiv1beta1.MachineControlPlaneLabel}); err != nil {
+		return time.Time{}, fmt.Errorf("failed to list control plane Machines of the cluster %s: %w", client.ObjectKeyFromObject(cd), err)
+	}
+
+	machines := make([]clusterapiv1beta1.Machine, len(list.Items))
+	for i, item := range list.Items {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &machines[i]); err != nil {
+			return time.Time{}, fmt.Errorf("failed to convert Machine %s: %w", client.ObjectKeyFromObject(&item), err)
+		}
+	}
+
+	expiry, err := certificates.MachinesExpiry(machines)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	serverExpiry, err := r.serverCertificateExpiry(ctx, client.ObjectKeyFromObject(cd))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get API server certificate of the cluster %s: %w", client.ObjectKeyFromObject(cd), err)
+	}
+	if expiry.IsZero() || serverExpiry.Before(expiry) {
+		expiry = serverExpiry
+	}
+
+	return expiry, nil
+}
+
+// rotate triggers the rollout of the control plane of the cluster unless
+// it has been triggered recently.
+func (r *CertificatesReconciler) rotate(ctx context.Context, cd *kcm.ClusterDeployment) error {
+	if last := cd.Status.Certificates.LastRotationTime; last != nil && time.Since(last.Time) < certificatesRotationCooldown {
+		return nil
+	}
+
+	u := new(unstructured.Unstructured)
+	u.SetGroupVersionKind(clusterapiv1beta1.GroupVersion.WithKind("Cluster"))
+	if err := r.Get(ctx, client.ObjectKeyFromObject(cd), u); err != nil {
+		return fmt.Errorf("failed to get cluster %s: %w", client.ObjectKeyFromObject(cd), err)
+	}
+
+	cluster := new(clusterapiv1beta1.Cluster)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cluster); err != nil {
+		return fmt.Errorf("failed to convert cluster %s: %w", client.ObjectKeyFromObject(cd), err)
+	}
+
+	now := time.Now()
+	if err := certificates.TriggerRollout(ctx, r.Client, cluster, now); err != nil {
+		return err
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Triggered rotation of the cluster certificates", "expiration", cd.Status.Certificates.ExpirationTime)
+	cd.Status.Certificates.LastRotationTime = &metav1.Time{Time: now}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CertificatesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	if r.serverCertificateExpiry == nil {
+		r.serverCertificateExpiry = func(ctx context.Context, cluster client.ObjectKey) (time.Time, error) {
+			restConfig, err := remote.RESTConfig(ctx, "kcm-certificates", r.Client, cluster)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return certificates.ServerCertificateExpiry(ctx, restConfig)
+		}
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("certificates").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&kcm.ClusterDeployment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
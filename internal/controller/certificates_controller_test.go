@@ -0,0 +1,252 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+var _ = Describe("Certificates Controller", func() {
+	Context("When reconciling a resource", func() {
+		const clusterName = "test-cluster"
+
+		// the control plane kind supporting the rolloutAfter field of the
+		// Cluster API control plane contract
+		controlPlaneCRD := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "testcontrolplanes.controlplane.cluster.x-k8s.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "controlplane.cluster.x-k8s.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Kind:     "TestControlPlane",
+					ListKind: "TestControlPlaneList",
+					Plural:   "testcontrolplanes",
+					Singular: "testcontrolplane",
+				},
+				Scope: apiextensionsv1.NamespaceScoped,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+					Name:    "v1beta1",
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"spec": {
+									Type: "object",
+									Properties: map[string]apiextensionsv1.JSONSchemaProps{
+										"rolloutAfter": {Type: "string", Format: "date-time"},
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		}
+
+		var (
+			namespace         = corev1.Namespace{}
+			clusterDeployment = kcm.ClusterDeployment{}
+			machine           = clusterapiv1beta1.Machine{}
+			controlPlane      = unstructured.Unstructured{}
+		)
+
+		BeforeEach(func() {
+			By("ensure the control plane CRD", func() {
+				crdOptions := envtest.CRDInstallOptions{CRDs: []*apiextensionsv1.CustomResourceDefinition{controlPlaneCRD.DeepCopy()}}
+				_, err := envtest.InstallCRDs(cfg, crdOptions)
+				Expect(err).NotTo(HaveOccurred())
+				DeferCleanup(envtest.UninstallCRDs, cfg, crdOptions)
+			})
+
+			By("ensure namespace", func() {
+				namespace = corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						GenerateName: "test-namespace-",
+					},
+				}
+				Expect(k8sClient.Create(ctx, &namespace)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, &namespace)
+			})
+
+			By("ensure ClusterDeployment resource", func() {
+				clusterDeployment = kcm.ClusterDeployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      clusterName,
+						Namespace: namespace.Name,
+					},
+					Spec: kcm.ClusterDeploymentSpec{
+						Template:   "test-template",
+						Credential: "test-credential",
+						Config:     &apiextensionsv1.JSON{Raw: []byte(`{}`)},
+					},
+				}
+				Expect(k8sClient.Create(ctx, &clusterDeployment)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, &clusterDeployment)
+			})
+
+			By("ensure the control plane resources", func() {
+				controlPlane = unstructured.Unstructured{}
+				controlPlane.SetAPIVersion("controlplane.cluster.x-k8s.io/v1beta1")
+				controlPlane.SetKind("TestControlPlane")
+				controlPlane.SetNamespace(namespace.Name)
+				controlPlane.SetName(clusterName + "-cp")
+				Expect(k8sClient.Create(ctx, &controlPlane)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, &controlPlane)
+
+				cluster := clusterapiv1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      clusterName,
+						Namespace: namespace.Name,
+					},
+					Spec: clusterapiv1beta1.ClusterSpec{
+						ControlPlaneRef: &corev1.ObjectReference{
+							APIVersion: controlPlane.GetAPIVersion(),
+							Kind:       controlPlane.GetKind(),
+							Name:       controlPlane.GetName(),
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, &cluster)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, &cluster)
+
+				machine = clusterapiv1beta1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      clusterName + "-cp-0",
+						Namespace: namespace.Name,
+						Labels: map[string]string{
+							kcm.ClusterNameLabelKey:                    clusterName,
+							clusterapiv1beta1.MachineControlPlaneLabel: "",
+						},
+						Annotations: map[string]string{
+							clusterapiv1beta1.MachineCertificatesExpiryDateAnnotation: time.Now().Add(90*24*time.Hour + time.Hour).UTC().Format(time.RFC3339),
+						},
+					},
+					Spec: clusterapiv1beta1.MachineSpec{
+						ClusterName: clusterName,
+						Bootstrap: clusterapiv1beta1.Bootstrap{
+							DataSecretName: new(string),
+						},
+						InfrastructureRef: corev1.ObjectReference{
+							APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+							Kind:       "TestMachine",
+							Name:       clusterName + "-cp-0",
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, &machine)).To(Succeed())
+				DeferCleanup(k8sClient.Delete, &machine)
+			})
+		})
+
+		It("should track the expiration of the certificates and renew them before they expire", func() {
+			reconciler := &CertificatesReconciler{
+				Client: k8sClient,
+				serverCertificateExpiry: func(context.Context, client.ObjectKey) (time.Time, error) {
+					return time.Now().Add(365 * 24 * time.Hour), nil
+				},
+			}
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&clusterDeployment)}
+
+			certificatesValid := func() *metav1.Condition {
+				Expect(k8sClient.Get(ctx, req.NamespacedName, &clusterDeployment)).To(Succeed())
+				return apimeta.FindStatusCondition(clusterDeployment.Status.Conditions, kcm.CertificatesValidCondition)
+			}
+			rolloutAfter := func() string {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&controlPlane), &controlPlane)).To(Succeed())
+				v, _, err := unstructured.NestedString(controlPlane.Object, "spec", "rolloutAfter")
+				Expect(err).NotTo(HaveOccurred())
+				return v
+			}
+
+			By("postponing the check until the cluster is ready")
+			res, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(certificatesCheckPollPeriod))
+			Expect(certificatesValid()).To(BeNil())
+			Expect(clusterDeployment.Status.Certificates).To(BeNil())
+
+			apimeta.SetStatusCondition(&clusterDeployment.Status.Conditions, metav1.Condition{Type: kcm.ReadyCondition, Status: metav1.ConditionTrue, Reason: kcm.SucceededReason})
+			Expect(k8sClient.Status().Update(ctx, &clusterDeployment)).To(Succeed())
+
+			By("reporting the earliest expiration of the certificates")
+			res, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(certificatesCheckInterval))
+			condition := certificatesValid()
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(Equal("Certificates expire in 90 days"))
+			Expect(clusterDeployment.Status.Certificates).NotTo(BeNil())
+			Expect(clusterDeployment.Status.Certificates.DaysRemaining).To(Equal(int32(90)))
+			Expect(clusterDeployment.Status.Certificates.ExpirationTime.Format(time.RFC3339)).To(Equal(machine.Annotations[clusterapiv1beta1.MachineCertificatesExpiryDateAnnotation]))
+			Expect(rolloutAfter()).To(BeEmpty())
+
+			By("reporting the expiring certificates without the rotation enabled")
+			machine.Annotations[clusterapiv1beta1.MachineCertificatesExpiryDateAnnotation] = time.Now().Add(10*24*time.Hour + time.Hour).UTC().Format(time.RFC3339)
+			Expect(k8sClient.Update(ctx, &machine)).To(Succeed())
+
+			res, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(certificatesCheckPollPeriod))
+			condition = certificatesValid()
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(kcm.CertificatesExpiringReason))
+			Expect(clusterDeployment.Status.Certificates.DaysRemaining).To(Equal(int32(10)))
+			Expect(clusterDeployment.Status.Certificates.LastRotationTime).To(BeNil())
+			Expect(rolloutAfter()).To(BeEmpty())
+
+			By("triggering the rollout of the control plane with the rotation enabled")
+			clusterDeployment.Spec.CertificateRotation = &kcm.CertificateRotationSpec{RotateBefore: &metav1.Duration{Duration: 15 * 24 * time.Hour}}
+			Expect(k8sClient.Update(ctx, &clusterDeployment)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			condition = certificatesValid()
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(kcm.CertificatesRotatingReason))
+			Expect(clusterDeployment.Status.Certificates.LastRotationTime).NotTo(BeNil())
+			lastRotation := clusterDeployment.Status.Certificates.LastRotationTime.UTC().Format(time.RFC3339)
+			Expect(condition.Message).To(HaveSuffix("rotation triggered at " + lastRotation))
+			Expect(rolloutAfter()).To(Equal(lastRotation))
+
+			By("not triggering the rollout again while it is in progress")
+			controlPlane.Object["spec"] = map[string]any{}
+			Expect(k8sClient.Update(ctx, &controlPlane)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			condition = certificatesValid()
+			Expect(condition.Reason).To(Equal(kcm.CertificatesRotatingReason))
+			Expect(clusterDeployment.Status.Certificates.LastRotationTime.UTC().Format(time.RFC3339)).To(Equal(lastRotation))
+			Expect(rolloutAfter()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,172 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/cloudapi"
+	"github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/internal/quota"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+const defaultCloudQuotaInterval = time.Hour
+
+// CloudQuotaReconciler collects the cloud quotas of the accounts the
+// Credentials give access to into the status of the Management.
+type CloudQuotaReconciler struct {
+	client.Client
+
+	// Limiter limits the calls of the cloud APIs per Credential,
+	// [cloudapi.DefaultOptions] are used if unset.
+	Limiter *cloudapi.Limiter
+
+	collectors []quota.CloudCollector
+}
+
+func (r *CloudQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+
+	mgmt := new(kcm.Management)
+	if err := r.Get(ctx, req.NamespacedName, mgmt); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !mgmt.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if mgmt.Spec.CloudQuotaCollection == nil {
+		if len(mgmt.Status.CloudQuotas) == 0 {
+			return ctrl.Result{}, nil
+		}
+		for _, q := range mgmt.Status.CloudQuotas {
+			metrics.DeleteMetricsCloudQuota(q.Credential)
+			r.Limiter.Forget(q.Credential)
+		}
+		return ctrl.Result{}, r.updateStatus(ctx, mgmt, nil)
+	}
+
+	interval := defaultCloudQuotaInterval
+	if mgmt.Spec.CloudQuotaCollection.Interval != nil {
+		interval = mgmt.Spec.CloudQuotaCollection.Interval.Duration
+	}
+	if len(mgmt.Status.CloudQuotas) > 0 {
+		last := slices.MinFunc(mgmt.Status.CloudQuotas, func(a, b kcm.CredentialCloudQuotas) int {
+			return a.LastCollectionTime.Compare(b.LastCollectionTime.Time)
+		})
+		if next := last.LastCollectionTime.Add(interval); time.Now().Before(next) {
+			return ctrl.Result{RequeueAfter: time.Until(next)}, nil
+		}
+	}
+
+	l.Info("Collecting cloud quotas")
+
+	credentials := new(kcm.CredentialList)
+	if err := r.List(ctx, credentials); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list Credentials: %w", err)
+	}
+
+	var collected []kcm.CredentialCloudQuotas
+	for _, cred := range credentials.Items {
+		ref := cred.Spec.IdentityRef
+		if ref == nil {
+			continue
+		}
+
+		identity := new(unstructured.Unstructured)
+		identity.SetAPIVersion(ref.APIVersion)
+		identity.SetKind(ref.Kind)
+		if err := r.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, identity); err != nil {
+			l.V(1).Info("Failed to get the identity of the Credential, skipping", "credential", client.ObjectKeyFromObject(&cred), "err", err.Error())
+			continue
+		}
+
+		collector := quota.CloudCollectorFor(r.collectors, identity)
+		if collector == nil {
+			continue
+		}
+
+		entry := kcm.CredentialCloudQuotas{
+			LastCollectionTime: metav1.Now(),
+			Credential:         client.ObjectKeyFromObject(&cred).String(),
+			Provider:           collector.Provider(),
+		}
+		var quotas []kcm.CloudQuota
+		err := r.Limiter.Do(ctx, cloudapi.Key{Credential: entry.Credential, Provider: entry.Provider}, func(ctx context.Context) (err error) {
+			quotas, err = collector.Collect(ctx, r.Client, identity)
+			return err
+		})
+		if err != nil {
+			l.Error(err, "failed to collect cloud quotas", "credential", entry.Credential)
+			entry.Error = err.Error()
+		}
+		entry.Quotas = quotas
+		for _, q := range quotas {
+			metrics.TrackMetricCloudQuota(ctx, entry.Credential, entry.Provider, q)
+		}
+
+		collected = append(collected, entry)
+	}
+
+	for _, q := range mgmt.Status.CloudQuotas {
+		if !slices.ContainsFunc(collected, func(c kcm.CredentialCloudQuotas) bool { return c.Credential == q.Credential }) {
+			metrics.DeleteMetricsCloudQuota(q.Credential)
+			r.Limiter.Forget(q.Credential)
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: interval}, r.updateStatus(ctx, mgmt, collected)
+}
+
+func (r *CloudQuotaReconciler) updateStatus(ctx context.Context, mgmt *kcm.Management, quotas []kcm.CredentialCloudQuotas) error {
+	patch := client.MergeFrom(mgmt.DeepCopy())
+	mgmt.Status.CloudQuotas = quotas
+	if err := r.Status().Patch(ctx, mgmt, patch); err != nil {
+		return fmt.Errorf("failed to update cloud quotas in the Management %s status: %w", mgmt.Name, err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CloudQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	if r.collectors == nil {
+		r.collectors = quota.CloudCollectors
+	}
+	if r.Limiter == nil {
+		r.Limiter = cloudapi.NewLimiter(cloudapi.DefaultOptions)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("cloudquota").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&kcm.Management{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,123 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/cloudapi"
+	"github.com/K0rdent/kcm/internal/quota"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+type fakeCloudCollector struct {
+	quotas map[string][]kcm.CloudQuota
+}
+
+func (*fakeCloudCollector) Provider() string { return "fake" }
+
+func (*fakeCloudCollector) Supports(identity *unstructured.Unstructured) bool {
+	return identity.GetKind() == "Secret"
+}
+
+func (c *fakeCloudCollector) Collect(_ context.Context, _ client.Reader, identity *unstructured.Unstructured) ([]kcm.CloudQuota, error) {
+	quotas, ok := c.quotas[identity.GetName()]
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	return quotas, nil
+}
+
+func TestCloudQuotaReconciler_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	const systemNamespace = "kcm-system"
+
+	newCredential := func(name, identity string) *kcm.Credential {
+		return &kcm.Credential{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: systemNamespace},
+			Spec: kcm.CredentialSpec{IdentityRef: &corev1.ObjectReference{
+				APIVersion: "v1", Kind: "Secret", Name: identity, Namespace: systemNamespace,
+			}},
+		}
+	}
+	newSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: systemNamespace}}
+	}
+
+	mgmt := &kcm.Management{
+		ObjectMeta: metav1.ObjectMeta{Name: kcm.ManagementName},
+		Spec:       kcm.ManagementSpec{CloudQuotaCollection: &kcm.CloudQuotaCollection{}},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.Management{}).
+		WithObjects(
+			mgmt,
+			newCredential("openstack-cred", "openstack-cloud-config"),
+			newCredential("expired-cred", "expired-cloud-config"),
+			newCredential("missing-cred", "missing-cloud-config"),
+			newSecret("openstack-cloud-config"),
+			newSecret("expired-cloud-config"),
+		).Build()
+
+	quotas := []kcm.CloudQuota{quota.NewCloudQuota(kcm.CloudQuotaVCPUs, 20, 8)}
+	r := &CloudQuotaReconciler{
+		Client:     cl,
+		Limiter:    cloudapi.NewLimiter(cloudapi.DefaultOptions),
+		collectors: []quota.CloudCollector{&fakeCloudCollector{quotas: map[string][]kcm.CloudQuota{"openstack-cloud-config": quotas}}},
+	}
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mgmt)}
+
+	result, err := r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(defaultCloudQuotaInterval))
+
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, mgmt)).To(Succeed())
+	g.Expect(mgmt.Status.CloudQuotas).To(HaveLen(2))
+	g.Expect(mgmt.Status.CloudQuotas[0].Credential).To(Equal(systemNamespace + "/expired-cred"))
+	g.Expect(mgmt.Status.CloudQuotas[0].Error).To(Equal("unauthorized"))
+	g.Expect(mgmt.Status.CloudQuotas[1].Credential).To(Equal(systemNamespace + "/openstack-cred"))
+	g.Expect(mgmt.Status.CloudQuotas[1].Provider).To(Equal("fake"))
+	g.Expect(mgmt.Status.CloudQuotas[1].Quotas).To(Equal(quotas))
+
+	// not due yet
+	result, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+	g.Expect(result.RequeueAfter).To(BeNumerically("<=", defaultCloudQuotaInterval))
+
+	// collection disabled
+	mgmt.Spec.CloudQuotaCollection = nil
+	g.Expect(cl.Update(t.Context(), mgmt)).To(Succeed())
+
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, mgmt)).To(Succeed())
+	g.Expect(mgmt.Status.CloudQuotas).To(BeEmpty())
+}
@@ -0,0 +1,390 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/clusterclient"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+const (
+	// clusterBackupSyncPeriod is the period the state of the Velero
+	// Schedules is reported in the status of the ClusterDeployment with.
+	clusterBackupSyncPeriod = 10 * time.Minute
+	// defaultClusterBackupRetention is the default retention of Velero.
+	defaultClusterBackupRetention = 30 * 24 * time.Hour
+	// workloadBackupSchedulePrefix is the prefix of the names of the Velero
+	// Schedules created in the workload clusters.
+	workloadBackupSchedulePrefix = "kcm-"
+	// clusterBackupReadyPollPeriod is the period the readiness of the cluster
+	// the workload backups are postponed until is checked with.
+	clusterBackupReadyPollPeriod = time.Minute
+)
+
+var errBackupClusterNotReady = errors.New("waiting for the cluster to be ready")
+
+// ClusterBackupReconciler maintains the Velero Schedules of the scheduled
+// backups declared by the ClusterDeployments, either in the system namespace
+// of the management cluster or in the workload clusters depending on the
+// scope of the backup, and reports their state in the status.
+type ClusterBackupReconciler struct {
+	client.Client
+
+	newClusterClient clusterclient.ClientFunc
+
+	SystemNamespace string
+}
+
+func (r *ClusterBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+
+	cd := new(kcm.ClusterDeployment)
+	if err := r.Get(ctx, req.NamespacedName, cd); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		// the schedules of the workload cluster are removed along with the cluster
+		_, err := syncBackupSchedules(ctx, r.Client, backupScheduleLabels(req.NamespacedName), nil)
+		return ctrl.Result{}, err
+	}
+	if !cd.DeletionTimestamp.IsZero() {
+		_, err := syncBackupSchedules(ctx, r.Client, backupScheduleLabels(req.NamespacedName), nil)
+		return ctrl.Result{}, err
+	}
+	if cd.Spec.DryRun || len(cd.Spec.Backups) == 0 && len(cd.Status.Backups) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	if len(cd.Spec.Backups) > 0 && cd.Labels[kcm.ClusterDeploymentNameLabel] != cd.Name {
+		// the ClusterDeployment itself is selected into its backups by the label
+		patch := client.MergeFrom(cd.DeepCopy())
+		if cd.Labels == nil {
+			cd.Labels = make(map[string]string)
+		}
+		cd.Labels[kcm.ClusterDeploymentNameLabel] = cd.Name
+		if err := r.Patch(ctx, cd, patch); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to label ClusterDeployment %s: %w", req.NamespacedName, err)
+		}
+	}
+
+	base := cd.DeepCopy()
+
+	var managementSchedules, workloadSchedules []*velerov1.Schedule
+	for _, backup := range cd.Spec.Backups {
+		if backup.Scope == kcm.ClusterBackupScopeWorkload {
+			workloadSchedules = append(workloadSchedules, workloadBackupSchedule(cd, &backup))
+		} else {
+			managementSchedules = append(managementSchedules, r.managementBackupSchedule(cd, &backup))
+		}
+	}
+
+	schedules, managementErr := syncBackupSchedules(ctx, r.Client, backupScheduleLabels(req.NamespacedName), managementSchedules)
+	statuses := clusterBackupStatuses(cd, kcm.ClusterBackupScopeManagement, schedules, managementErr)
+	setBackupsSyncedCondition(cd, kcm.ManagementBackupsSyncedCondition, len(statuses) > 0, managementErr)
+
+	var workloadErr error
+	if len(workloadSchedules) > 0 || slices.ContainsFunc(cd.Status.Backups, func(s kcm.ClusterBackupStatus) bool {
+		return s.Scope == kcm.ClusterBackupScopeWorkload
+	}) {
+		schedules, workloadErr = r.syncWorkloadBackupSchedules(ctx, cd, workloadSchedules)
+		workloadStatuses := clusterBackupStatuses(cd, kcm.ClusterBackupScopeWorkload, schedules, workloadErr)
+		setBackupsSyncedCondition(cd, kcm.WorkloadBackupsSyncedCondition, len(workloadStatuses) > 0, workloadErr)
+		statuses = append(statuses, workloadStatuses...)
+	} else {
+		setBackupsSyncedCondition(cd, kcm.WorkloadBackupsSyncedCondition, false, nil)
+	}
+
+	cd.Status.Backups = statuses
+	if err := patchStatus(ctx, r.Client, cd, base); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status for clusterDeployment %s: %w", req.NamespacedName, err)
+	}
+
+	if errors.Is(workloadErr, errBackupClusterNotReady) {
+		l.V(1).Info("ClusterDeployment is not ready yet, postponing the workload backups")
+		if managementErr != nil {
+			return ctrl.Result{}, managementErr
+		}
+		return ctrl.Result{RequeueAfter: clusterBackupReadyPollPeriod}, nil
+	}
+	if err := errors.Join(managementErr, workloadErr); err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(cd.Status.Backups) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	l.V(1).Info("Synced backup schedules", "backups", len(cd.Status.Backups))
+	return ctrl.Result{RequeueAfter: clusterBackupSyncPeriod}, nil
+}
+
+// setBackupsSyncedCondition sets the condition of the backups of the scope
+// reporting the error of the sync of their schedules. The condition is removed
+// if the ClusterDeployment has no backups of the scope.
+func setBackupsSyncedCondition(cd *kcm.ClusterDeployment, conditionType string, hasBackups bool, syncErr error) {
+	if !hasBackups {
+		apimeta.RemoveStatusCondition(&cd.Status.Conditions, conditionType)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             kcm.SucceededReason,
+		Message:            "Velero Schedules are synced",
+		ObservedGeneration: cd.Generation,
+	}
+	switch {
+	case errors.Is(syncErr, errBackupClusterNotReady):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = kcm.ProgressingReason
+		condition.Message = syncErr.Error()
+	case syncErr != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = kcm.FailedReason
+		condition.Message = syncErr.Error()
+	}
+	apimeta.SetStatusCondition(&cd.Status.Conditions, condition)
+}
+
+// syncWorkloadBackupSchedules syncs the Velero Schedules of the workload
+// cluster once it is ready.
+func (r *ClusterBackupReconciler) syncWorkloadBackupSchedules(ctx context.Context, cd *kcm.ClusterDeployment, desired []*velerov1.Schedule) (map[string]*velerov1.Schedule, error) {
+	if !apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.ReadyCondition) {
+		return nil, errBackupClusterNotReady
+	}
+
+	clusterClient, err := r.newClusterClient(ctx, client.ObjectKeyFromObject(cd))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client of the cluster: %w", err)
+	}
+
+	schedules, err := syncBackupSchedules(ctx, clusterClient, backupScheduleLabels(client.ObjectKeyFromObject(cd)), desired)
+	if isVeleroMissingError(err) {
+		return nil, fmt.Errorf("probably Velero is not installed in the cluster: %w", err)
+	}
+	return schedules, err
+}
+
+// managementBackupSchedule returns the Velero Schedule of the system
+// namespace backing up the objects of the ClusterDeployment.
+func (r *ClusterBackupReconciler) managementBackupSchedule(cd *kcm.ClusterDeployment, backup *kcm.ClusterBackup) *velerov1.Schedule {
+	schedule := newBackupSchedule(cd, backup, r.SystemNamespace, backupScheduleName(cd.Namespace, cd.Name, backup.Name))
+	schedule.Spec.Template.IncludedNamespaces = []string{cd.Namespace}
+	// the cluster is recreated from the restored HelmRelease like with the
+	// backups of the whole management
+	schedule.Spec.Template.ExcludedResources = []string{"clusters.cluster.x-k8s.io"}
+	schedule.Spec.Template.OrLabelSelectors = []*metav1.LabelSelector{
+		{MatchLabels: map[string]string{kcm.ClusterDeploymentNameLabel: cd.Name}},
+		{MatchLabels: map[string]string{kcm.FluxHelmChartNameKey: cd.Name}},
+		{MatchLabels: map[string]string{kcm.ClusterNameLabelKey: cd.Name}},
+	}
+	return schedule
+}
+
+// workloadBackupSchedule returns the Velero Schedule of the workload cluster
+// backing up its resources.
+func workloadBackupSchedule(cd *kcm.ClusterDeployment, backup *kcm.ClusterBackup) *velerov1.Schedule {
+	namespace := backup.VeleroNamespace
+	if namespace == "" {
+		namespace = "velero"
+	}
+
+	schedule := newBackupSchedule(cd, backup, namespace, workloadBackupSchedulePrefix+backup.Name)
+	schedule.Spec.Template.IncludedNamespaces = backup.IncludedNamespaces
+	schedule.Spec.Template.ExcludedNamespaces = backup.ExcludedNamespaces
+	return schedule
+}
+
+func newBackupSchedule(cd *kcm.ClusterDeployment, backup *kcm.ClusterBackup, namespace, name string) *velerov1.Schedule {
+	retention := metav1.Duration{Duration: defaultClusterBackupRetention}
+	if backup.Retention != nil {
+		retention = *backup.Retention
+	}
+
+	labels := backupScheduleLabels(client.ObjectKeyFromObject(cd))
+	labels[kcm.ClusterBackupNameLabel] = backup.Name
+
+	return &velerov1.Schedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: velerov1.ScheduleSpec{
+			Schedule: backup.Schedule,
+			Template: velerov1.BackupSpec{
+				StorageLocation: backup.StorageLocation,
+				TTL:             retention,
+			},
+		},
+	}
+}
+
+// backupScheduleLabels returns the labels of the Velero Schedules of the
+// backups of the ClusterDeployment.
+func backupScheduleLabels(cd client.ObjectKey) map[string]string {
+	return map[string]string{
+		kcm.KCMManagedLabelKey:              kcm.KCMManagedLabelValue,
+		kcm.ClusterDeploymentNameLabel:      cd.Name,
+		kcm.ClusterDeploymentNamespaceLabel: cd.Namespace,
+	}
+}
+
+// backupScheduleName joins the parts into the name of the Velero Schedule,
+// which is shortened with the hash of the parts to fit the label value
+// Velero marks the backups of the schedule with. The parts are joined with
+// dots, which are not allowed in the namespaces and the names of the backups,
+// so the names of the different ClusterDeployments do not clash.
+func backupScheduleName(parts ...string) string {
+	const maxLength = 63
+
+	name := strings.Join(parts, ".")
+	if len(name) <= maxLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:8]
+	return strings.TrimRight(name[:maxLength-len(hash)-1], "-.") + "-" + hash
+}
+
+// syncBackupSchedules creates or updates the desired Velero Schedules and
+// deletes the ones of the given labels not desired anymore. It returns the
+// synced schedules by the names of the backups.
+func syncBackupSchedules(ctx context.Context, cl client.Client, labels map[string]string, desired []*velerov1.Schedule) (map[string]*velerov1.Schedule, error) {
+	existing := new(velerov1.ScheduleList)
+	if err := cl.List(ctx, existing, client.MatchingLabels(labels)); err != nil {
+		if isVeleroMissingError(err) && len(desired) == 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list Velero Schedules: %w", err)
+	}
+
+	var errs error
+	schedules := make(map[string]*velerov1.Schedule, len(desired))
+	for _, d := range desired {
+		schedule := &velerov1.Schedule{ObjectMeta: metav1.ObjectMeta{Name: d.Name, Namespace: d.Namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, cl, schedule, func() error {
+			if schedule.Labels == nil {
+				schedule.Labels = make(map[string]string)
+			}
+			for k, v := range d.Labels {
+				schedule.Labels[k] = v
+			}
+			schedule.Spec = d.Spec
+			return nil
+		}); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to reconcile Velero Schedule %s: %w", client.ObjectKeyFromObject(d), err))
+			continue
+		}
+		schedules[d.Labels[kcm.ClusterBackupNameLabel]] = schedule
+	}
+
+	for _, schedule := range existing.Items {
+		if slices.ContainsFunc(desired, func(d *velerov1.Schedule) bool {
+			return d.Name == schedule.Name && d.Namespace == schedule.Namespace
+		}) {
+			continue
+		}
+		if err := cl.Delete(ctx, &schedule); client.IgnoreNotFound(err) != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to delete Velero Schedule %s: %w", client.ObjectKeyFromObject(&schedule), err))
+		}
+	}
+
+	return schedules, errs
+}
+
+// clusterBackupStatuses returns the statuses of the backups of the scope
+// from the given synced schedules. If the schedules have failed to be synced,
+// the backups removed from the spec are kept in the status with the error,
+// so their schedules are deleted upon the next attempt.
+func clusterBackupStatuses(cd *kcm.ClusterDeployment, scope kcm.ClusterBackupScope, schedules map[string]*velerov1.Schedule, syncErr error) []kcm.ClusterBackupStatus {
+	var statuses []kcm.ClusterBackupStatus
+	for _, backup := range cd.Spec.Backups {
+		backupScope := backup.Scope
+		if backupScope == "" {
+			backupScope = kcm.ClusterBackupScopeManagement
+		}
+		if backupScope != scope {
+			continue
+		}
+
+		status := kcm.ClusterBackupStatus{Name: backup.Name, Scope: scope}
+		if schedule, ok := schedules[backup.Name]; ok {
+			status.Schedule = client.ObjectKeyFromObject(schedule).String()
+			status.Phase = string(schedule.Status.Phase)
+			status.LastBackupTime = schedule.Status.LastBackup
+			status.Error = strings.Join(schedule.Status.ValidationErrors, "; ")
+		}
+		if syncErr != nil {
+			status.Error = syncErr.Error()
+		}
+		statuses = append(statuses, status)
+	}
+
+	if syncErr == nil {
+		return statuses
+	}
+
+	for _, status := range cd.Status.Backups {
+		if status.Scope != scope || slices.ContainsFunc(cd.Spec.Backups, func(b kcm.ClusterBackup) bool { return b.Name == status.Name }) {
+			continue
+		}
+		status.Error = syncErr.Error()
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func isVeleroMissingError(err error) bool {
+	return apimeta.IsNoMatchError(err) || apierrors.IsNotFound(err)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	if r.newClusterClient == nil {
+		r.newClusterClient = clusterclient.NewClientFunc(r.Client, "kcm-backup")
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("clusterbackup").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&kcm.ClusterDeployment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,185 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestClusterBackupReconciler_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	const systemNamespace = "kcm-system"
+
+	cd := &kcm.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cd", Namespace: "team-a"},
+		Spec: kcm.ClusterDeploymentSpec{
+			Template: "aws-0-1-0",
+			Backups: []kcm.ClusterBackup{
+				{Name: "state", Schedule: "@daily", Scope: kcm.ClusterBackupScopeManagement},
+				{Name: "apps", Schedule: "0 * * * *", Scope: kcm.ClusterBackupScopeWorkload, VeleroNamespace: "velero", IncludedNamespaces: []string{"apps"}},
+			},
+		},
+		Status: kcm.ClusterDeploymentStatus{
+			Conditions: []metav1.Condition{{Type: kcm.ReadyCondition, Status: metav1.ConditionTrue}},
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.ClusterDeployment{}).
+		WithObjects(cd).Build()
+	clusterClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+	r := &ClusterBackupReconciler{
+		Client:          cl,
+		SystemNamespace: systemNamespace,
+		newClusterClient: func(context.Context, client.ObjectKey) (client.Client, error) {
+			return clusterClient, nil
+		},
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cd)}
+
+	res, err := r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(clusterBackupSyncPeriod))
+
+	managementSchedule := new(velerov1.Schedule)
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: "team-a.cd.state"}, managementSchedule)).To(Succeed())
+	g.Expect(managementSchedule.Spec.Schedule).To(Equal("@daily"))
+	g.Expect(managementSchedule.Spec.Template.IncludedNamespaces).To(Equal([]string{"team-a"}))
+	g.Expect(managementSchedule.Spec.Template.OrLabelSelectors).To(ContainElement(&metav1.LabelSelector{
+		MatchLabels: map[string]string{kcm.ClusterDeploymentNameLabel: "cd"},
+	}))
+	g.Expect(managementSchedule.Spec.Template.TTL.Duration).To(Equal(defaultClusterBackupRetention))
+
+	workloadSchedule := new(velerov1.Schedule)
+	g.Expect(clusterClient.Get(t.Context(), client.ObjectKey{Namespace: "velero", Name: "kcm-apps"}, workloadSchedule)).To(Succeed())
+	g.Expect(workloadSchedule.Spec.Template.IncludedNamespaces).To(Equal([]string{"apps"}))
+
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, cd)).To(Succeed())
+	g.Expect(cd.Labels).To(HaveKeyWithValue(kcm.ClusterDeploymentNameLabel, "cd"))
+	g.Expect(cd.Status.Backups).To(Equal([]kcm.ClusterBackupStatus{
+		{Name: "state", Scope: kcm.ClusterBackupScopeManagement, Schedule: systemNamespace + "/team-a.cd.state"},
+		{Name: "apps", Scope: kcm.ClusterBackupScopeWorkload, Schedule: "velero/kcm-apps"},
+	}))
+	g.Expect(apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.ManagementBackupsSyncedCondition)).To(BeTrue())
+	g.Expect(apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.WorkloadBackupsSyncedCondition)).To(BeTrue())
+
+	// the schedules of the removed backups are deleted
+	cd.Spec.Backups = nil
+	g.Expect(cl.Update(t.Context(), cd)).To(Succeed())
+
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	managementSchedules := new(velerov1.ScheduleList)
+	g.Expect(cl.List(t.Context(), managementSchedules)).To(Succeed())
+	g.Expect(managementSchedules.Items).To(BeEmpty())
+	workloadSchedules := new(velerov1.ScheduleList)
+	g.Expect(clusterClient.List(t.Context(), workloadSchedules)).To(Succeed())
+	g.Expect(workloadSchedules.Items).To(BeEmpty())
+
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, cd)).To(Succeed())
+	g.Expect(cd.Status.Backups).To(BeEmpty())
+	g.Expect(apimeta.FindStatusCondition(cd.Status.Conditions, kcm.ManagementBackupsSyncedCondition)).To(BeNil())
+	g.Expect(apimeta.FindStatusCondition(cd.Status.Conditions, kcm.WorkloadBackupsSyncedCondition)).To(BeNil())
+}
+
+func TestClusterBackupReconciler_ReconcileErrors(t *testing.T) {
+	g := NewWithT(t)
+
+	cd := &kcm.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cd", Namespace: "team-a"},
+		Spec: kcm.ClusterDeploymentSpec{
+			Template: "aws-0-1-0",
+			Backups: []kcm.ClusterBackup{
+				{Name: "state", Schedule: "@daily", Scope: kcm.ClusterBackupScopeManagement},
+				{Name: "apps", Schedule: "0 * * * *", Scope: kcm.ClusterBackupScopeWorkload},
+			},
+		},
+		Status: kcm.ClusterDeploymentStatus{
+			Conditions: []metav1.Condition{{Type: kcm.ReadyCondition, Status: metav1.ConditionTrue}},
+		},
+	}
+
+	managementErr := errors.New("management unavailable")
+	workloadErr := errors.New("cluster unreachable")
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.ClusterDeployment{}).
+		WithObjects(cd).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if _, ok := obj.(*velerov1.Schedule); ok {
+					return managementErr
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+
+	r := &ClusterBackupReconciler{
+		Client:          cl,
+		SystemNamespace: "kcm-system",
+		newClusterClient: func(context.Context, client.ObjectKey) (client.Client, error) {
+			return nil, workloadErr
+		},
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cd)}
+
+	_, err := r.Reconcile(t.Context(), req)
+	g.Expect(err).To(MatchError(managementErr))
+	g.Expect(err).To(MatchError(workloadErr))
+
+	// the errors of the scopes are reported separately
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, cd)).To(Succeed())
+	g.Expect(cd.Status.Backups).To(HaveLen(2))
+	g.Expect(cd.Status.Backups[0].Error).To(ContainSubstring(managementErr.Error()))
+	g.Expect(cd.Status.Backups[0].Error).NotTo(ContainSubstring(workloadErr.Error()))
+	g.Expect(cd.Status.Backups[1].Error).To(ContainSubstring(workloadErr.Error()))
+
+	managementCondition := apimeta.FindStatusCondition(cd.Status.Conditions, kcm.ManagementBackupsSyncedCondition)
+	g.Expect(managementCondition).NotTo(BeNil())
+	g.Expect(managementCondition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(managementCondition.Message).To(ContainSubstring(managementErr.Error()))
+	workloadCondition := apimeta.FindStatusCondition(cd.Status.Conditions, kcm.WorkloadBackupsSyncedCondition)
+	g.Expect(workloadCondition).NotTo(BeNil())
+	g.Expect(workloadCondition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(workloadCondition.Message).To(ContainSubstring(workloadErr.Error()))
+}
+
+func Test_backupScheduleName(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(backupScheduleName("team-a", "cd", "state")).To(Equal("team-a.cd.state"))
+
+	long := backupScheduleName("team-a", strings.Repeat("cluster", 10), "state")
+	g.Expect(long).To(HaveLen(63))
+	g.Expect(long).To(HavePrefix("team-a.cluster"))
+	g.Expect(long).NotTo(Equal(backupScheduleName("team-a", strings.Repeat("cluster", 10), "state2")))
+}
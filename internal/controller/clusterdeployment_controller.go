@@ -15,10 +15,13 @@
 package controller
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,6 +31,7 @@ import (
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	sveltosv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"go.opentelemetry.io/otel/trace"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	corev1 "k8s.io/api/core/v1"
@@ -38,6 +42,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,13 +51,23 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/audit"
+	"github.com/K0rdent/kcm/internal/clusterclient"
+	"github.com/K0rdent/kcm/internal/cost"
+	"github.com/K0rdent/kcm/internal/errclass"
 	"github.com/K0rdent/kcm/internal/helm"
 	"github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/internal/notifications"
 	providersloader "github.com/K0rdent/kcm/internal/providers"
+	"github.com/K0rdent/kcm/internal/quota"
+	"github.com/K0rdent/kcm/internal/record"
+	"github.com/K0rdent/kcm/internal/sharding"
 	"github.com/K0rdent/kcm/internal/sveltos"
 	"github.com/K0rdent/kcm/internal/telemetry"
+	"github.com/K0rdent/kcm/internal/tracing"
 	"github.com/K0rdent/kcm/internal/utils"
 	"github.com/K0rdent/kcm/internal/utils/ratelimit"
 	"github.com/K0rdent/kcm/internal/utils/status"
@@ -73,17 +88,48 @@ type ClusterDeploymentReconciler struct {
 	helmActor
 	Config          *rest.Config
 	DynamicClient   *dynamic.DynamicClient
+	Notifier        *notifications.Notifier
 	SystemNamespace string
 
+	// MaxConcurrentReconciles is the number of the ClusterDeployments
+	// reconciled concurrently, one if not set.
+	MaxConcurrentReconciles int
+	// RequeueInterval is the interval the ClusterDeployments are requeued at
+	// until they are ready, 10s if not set.
+	RequeueInterval time.Duration
+
+	// Sharder limits the reconciled ClusterDeployments to the shard of the
+	// replica, the controller is run on every replica if set and on the
+	// leader only otherwise.
+	Sharder *sharding.Sharder
+
+	// helmReleaseStatuses returns the state of the given Helm releases on the
+	// cluster, the state is not reported if nil.
+	helmReleaseStatuses func(ctx context.Context, cluster client.ObjectKey, releases []client.ObjectKey) ([]kcm.ServiceHelmReleaseStatus, error)
+
+	// revisionReader lists the ClusterDeploymentRevisions bypassing the cache
+	// not to record a revision again before the cache has observed it, the
+	// Client is used if not set.
+	revisionReader client.Reader
+	// auditRecorder records the material changes of the ClusterDeployments
+	// once they have been applied.
+	auditRecorder *audit.Recorder
+
 	defaultRequeueTime time.Duration
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *ClusterDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ClusterDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	l := ctrl.LoggerFrom(ctx)
 	l.Info("Reconciling ClusterDeployment")
 
+	ctx, span := tracing.Start(ctx, "ClusterDeployment.Reconcile", tracing.ObjectAttributes(kcm.ClusterDeploymentKind, req.Namespace, req.Name)...)
+	defer func() {
+		tracing.End(span, err)
+		trackReconcileError(ctx, kcm.ClusterDeploymentKind, err)
+	}()
+
 	clusterDeployment := &kcm.ClusterDeployment{}
 	if err := r.Client.Get(ctx, req.NamespacedName, clusterDeployment); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -95,6 +141,11 @@ func (r *ClusterDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	if r.Sharder != nil && !r.Sharder.Owns(clusterDeployment) {
+		l.V(1).Info("ClusterDeployment belongs to the shard of another replica, skipping")
+		return ctrl.Result{}, nil
+	}
+
 	if !clusterDeployment.DeletionTimestamp.IsZero() {
 		l.Info("Deleting ClusterDeployment")
 		return r.Delete(ctx, clusterDeployment)
@@ -116,7 +167,7 @@ func (r *ClusterDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			l.Error(err, "Failed to get Management object")
 			return ctrl.Result{}, err
 		}
-		if err := telemetry.TrackClusterDeploymentCreate(string(mgmt.UID), string(clusterDeployment.UID), clusterDeployment.Spec.Template, clusterDeployment.Spec.DryRun); err != nil {
+		if err := telemetry.TrackClusterDeploymentCreate(ctx, mgmt, string(clusterDeployment.UID), clusterDeployment.Spec.Template, clusterDeployment.Spec.DryRun); err != nil {
 			l.Error(err, "Failed to track ClusterDeployment creation")
 		}
 	}
@@ -173,6 +224,22 @@ func (r *ClusterDeploymentReconciler) reconcileUpdate(ctx context.Context, cd *k
 		return ctrl.Result{}, err
 	}
 
+	if res, restoring, err := r.waitForRestore(ctx, cd); restoring || err != nil {
+		return res, err
+	}
+
+	if rolledBack, err := r.rollback(ctx, cd); rolledBack || err != nil {
+		return ctrl.Result{}, err
+	}
+
+	statusBase := cd.DeepCopy()
+
+	cd.Status.CorrelationID = cd.GetCorrelationID()
+	l = l.WithValues("correlationID", cd.Status.CorrelationID)
+	ctx = ctrl.LoggerInto(ctx, l)
+	trace.SpanFromContext(ctx).SetAttributes(tracing.CorrelationIDAttribute(cd.Status.CorrelationID))
+
+	previousConditions := slices.Clone(cd.Status.Conditions)
 	if len(cd.Status.Conditions) == 0 {
 		cd.InitConditions()
 	}
@@ -180,26 +247,33 @@ func (r *ClusterDeploymentReconciler) reconcileUpdate(ctx context.Context, cd *k
 	clusterTpl := &kcm.ClusterTemplate{}
 
 	defer func() {
-		err = errors.Join(err, r.updateStatus(ctx, cd, clusterTpl))
+		err = errors.Join(err, r.updateStatus(ctx, cd, statusBase, clusterTpl, previousConditions))
 	}()
 
-	if err = r.Client.Get(ctx, client.ObjectKey{Name: cd.Spec.Template, Namespace: cd.Namespace}, clusterTpl); err != nil {
+	tctx, span := tracing.Start(ctx, "GetClusterTemplate")
+	err = r.Client.Get(tctx, client.ObjectKey{Name: cd.Spec.Template, Namespace: cd.Namespace}, clusterTpl)
+	tracing.End(span, err)
+	if err != nil {
 		l.Error(err, "Failed to get Template")
 		errMsg := fmt.Sprintf("failed to get provided template: %s", err)
 		if apierrors.IsNotFound(err) {
 			errMsg = "provided template is not found"
+			err = errclass.New(errclass.InvalidConfig, err)
 		}
 		apimeta.SetStatusCondition(cd.GetConditions(), metav1.Condition{
 			Type:    kcm.TemplateReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  kcm.FailedReason,
+			Reason:  errclass.Reason(err),
 			Message: errMsg,
 		})
 		return ctrl.Result{}, err
 	}
 
 	clusterRes, clusterErr := r.updateCluster(ctx, cd, clusterTpl)
-	servicesRes, servicesErr := r.updateServices(ctx, cd)
+
+	tctx, span = tracing.Start(ctx, "ReconcileSveltosProfile")
+	servicesRes, servicesErr := r.updateServices(tctx, cd)
+	tracing.End(span, servicesErr)
 
 	if err = errors.Join(clusterErr, servicesErr); err != nil {
 		return ctrl.Result{}, err
@@ -226,10 +300,10 @@ func (r *ClusterDeploymentReconciler) updateCluster(ctx context.Context, cd *kcm
 		apimeta.SetStatusCondition(cd.GetConditions(), metav1.Condition{
 			Type:    kcm.TemplateReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  kcm.FailedReason,
+			Reason:  kcm.InvalidConfigReason,
 			Message: errMsg,
 		})
-		return ctrl.Result{}, errors.New(errMsg)
+		return ctrl.Result{}, errclass.New(errclass.InvalidConfig, errors.New(errMsg))
 	}
 	// template is ok, propagate data from it
 	cd.Status.KubernetesVersion = clusterTpl.Status.KubernetesVersion
@@ -246,18 +320,20 @@ func (r *ClusterDeploymentReconciler) updateCluster(ctx context.Context, cd *kcm
 		apimeta.SetStatusCondition(cd.GetConditions(), metav1.Condition{
 			Type:    kcm.HelmChartReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  kcm.FailedReason,
+			Reason:  errclass.Reason(err),
 			Message: fmt.Sprintf("failed to get helm chart source: %s", err),
 		})
 		return ctrl.Result{}, err
 	}
 	l.Info("Downloading Helm chart")
-	hcChart, err := r.DownloadChartFromArtifact(ctx, source.GetArtifact())
+	tctx, span := tracing.Start(ctx, "DownloadHelmChart")
+	hcChart, err := r.DownloadChartFromArtifact(tctx, source.GetArtifact())
+	tracing.End(span, err)
 	if err != nil {
 		apimeta.SetStatusCondition(cd.GetConditions(), metav1.Condition{
 			Type:    kcm.HelmChartReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  kcm.FailedReason,
+			Reason:  errclass.Reason(err),
 			Message: fmt.Sprintf("failed to download helm chart: %s", err),
 		})
 		return ctrl.Result{}, err
@@ -270,11 +346,16 @@ func (r *ClusterDeploymentReconciler) updateCluster(ctx context.Context, cd *kcm
 	}
 
 	l.Info("Validating Helm chart with provided values")
-	if err = r.EnsureReleaseWithValues(ctx, actionConfig, hcChart, cd); err != nil {
+	tctx, span = tracing.Start(ctx, "RenderHelmChart")
+	err = r.EnsureReleaseWithValues(tctx, actionConfig, hcChart, cd)
+	tracing.End(span, err)
+	if err != nil {
+		// the chart is rendered locally, so it fails on the invalid configuration only
+		err = errclass.New(errclass.InvalidConfig, err)
 		apimeta.SetStatusCondition(cd.GetConditions(), metav1.Condition{
 			Type:    kcm.HelmChartReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  kcm.FailedReason,
+			Reason:  kcm.InvalidConfigReason,
 			Message: fmt.Sprintf("failed to validate template with provided configuration: %s", err),
 		})
 		return ctrl.Result{}, err
@@ -296,7 +377,7 @@ func (r *ClusterDeploymentReconciler) updateCluster(ctx context.Context, cd *kcm
 		apimeta.SetStatusCondition(cd.GetConditions(), metav1.Condition{
 			Type:    kcm.CredentialReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  kcm.FailedReason,
+			Reason:  errclass.Reason(err),
 			Message: fmt.Sprintf("Failed to get Credential: %s", err),
 		})
 		return ctrl.Result{}, err
@@ -322,6 +403,24 @@ func (r *ClusterDeploymentReconciler) updateCluster(ctx context.Context, cd *kcm
 		return ctrl.Result{}, nil
 	}
 
+	// the already deployed cluster exceeding the quota is still updated unless
+	// its usage increases, e.g. once the quota is lowered after the deployment
+	satisfied, err := r.checkClusterQuotas(ctx, cd)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !satisfied {
+		increases, err := r.quotaUsageIncreases(ctx, cd)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if increases {
+			l.Info("ClusterDeployment exceeds the ClusterQuota, skipping the deployment")
+			return ctrl.Result{}, nil
+		}
+		l.Info("ClusterDeployment exceeds the ClusterQuota, updating the already deployed cluster since its usage does not increase")
+	}
+
 	if err := cd.AddHelmValues(func(values map[string]any) error {
 		values["clusterIdentity"] = cred.Spec.IdentityRef
 
@@ -330,7 +429,7 @@ func (r *ClusterDeploymentReconciler) updateCluster(ctx context.Context, cd *kcm
 			values["clusterLabels"] = cd.GetObjectMeta().GetLabels()
 		}
 
-		return nil
+		return applyAuthenticationValues(values, cd.Spec.Authentication)
 	}); err != nil {
 		return ctrl.Result{}, err
 	}
@@ -343,25 +442,35 @@ func (r *ClusterDeploymentReconciler) updateCluster(ctx context.Context, cd *kcm
 			Name:       cd.Name,
 			UID:        cd.UID,
 		},
-		ChartRef: clusterTpl.Status.ChartRef,
+		ChartRef:      clusterTpl.Status.ChartRef,
+		CorrelationID: cd.Status.CorrelationID,
+		FieldManager:  clusterDeploymentFieldManager,
 	}
 	if clusterTpl.Spec.Helm.ChartSpec != nil {
 		hrReconcileOpts.ReconcileInterval = &clusterTpl.Spec.Helm.ChartSpec.Interval.Duration
 	}
 
-	hr, _, err := helm.ReconcileHelmRelease(ctx, r.Client, cd.Name, cd.Namespace, hrReconcileOpts)
+	tctx, span = tracing.Start(ctx, "ReconcileHelmRelease")
+	hr, operation, err := helm.ReconcileHelmRelease(tctx, r.Client, cd.Name, cd.Namespace, hrReconcileOpts)
+	tracing.End(span, err)
 	if err != nil {
+		trackHelmReleaseFailure(ctx, cd, metav1.ConditionFalse)
 		apimeta.SetStatusCondition(cd.GetConditions(), metav1.Condition{
 			Type:    kcm.HelmReleaseReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  kcm.FailedReason,
+			Reason:  errclass.Reason(err),
 			Message: err.Error(),
 		})
 		return ctrl.Result{}, err
 	}
 
+	if operation == controllerutil.OperationResultCreated {
+		record.Eventf(cd, eventReasonHelmInstallStarted, "HelmRelease %s/%s is created with the ClusterTemplate %s", hr.Namespace, hr.Name, cd.Spec.Template)
+	}
+
 	hrReadyCondition := fluxconditions.Get(hr, fluxmeta.ReadyCondition)
 	if hrReadyCondition != nil {
+		trackHelmReleaseFailure(ctx, cd, hrReadyCondition.Status)
 		apimeta.SetStatusCondition(cd.GetConditions(), metav1.Condition{
 			Type:    kcm.HelmReleaseReadyCondition,
 			Status:  hrReadyCondition.Status,
@@ -370,7 +479,9 @@ func (r *ClusterDeploymentReconciler) updateCluster(ctx context.Context, cd *kcm
 		})
 	}
 
-	requeue, err := r.aggregateCapoConditions(ctx, cd)
+	tctx, span = tracing.Start(ctx, "WaitCAPICluster")
+	requeue, err := r.aggregateCapoConditions(tctx, cd)
+	tracing.End(span, err)
 	if err != nil {
 		if requeue {
 			return ctrl.Result{RequeueAfter: r.defaultRequeueTime}, err
@@ -390,6 +501,80 @@ func (r *ClusterDeploymentReconciler) updateCluster(ctx context.Context, cd *kcm
 	return ctrl.Result{}, nil
 }
 
+// checkClusterQuotas sets the ClusterQuotaSatisfied condition and reports
+// whether the ClusterDeployment fits into all of the ClusterQuotas of its namespace.
+func (r *ClusterDeploymentReconciler) checkClusterQuotas(ctx context.Context, cd *kcm.ClusterDeployment) (satisfied bool, _ error) {
+	quotas := new(kcm.ClusterQuotaList)
+	if err := r.Client.List(ctx, quotas, client.InNamespace(cd.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list ClusterQuotas in namespace %s: %w", cd.Namespace, err)
+	}
+
+	if len(quotas.Items) == 0 {
+		apimeta.RemoveStatusCondition(cd.GetConditions(), kcm.ClusterQuotaSatisfiedCondition)
+		return true, nil
+	}
+
+	clusterDeployments := new(kcm.ClusterDeploymentList)
+	if err := r.Client.List(ctx, clusterDeployments, client.InNamespace(cd.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list ClusterDeployments in namespace %s: %w", cd.Namespace, err)
+	}
+
+	var errs error
+	for i := range quotas.Items {
+		errs = errors.Join(errs, quota.Check(&quotas.Items[i], cd, clusterDeployments.Items))
+	}
+
+	if errs != nil {
+		apimeta.SetStatusCondition(cd.GetConditions(), metav1.Condition{
+			Type:    kcm.ClusterQuotaSatisfiedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  kcm.ClusterQuotaExceededReason,
+			Message: errs.Error(),
+		})
+		return false, nil
+	}
+
+	apimeta.SetStatusCondition(cd.GetConditions(), metav1.Condition{
+		Type:    kcm.ClusterQuotaSatisfiedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  kcm.SucceededReason,
+		Message: "ClusterDeployment fits into the ClusterQuotas",
+	})
+
+	return true, nil
+}
+
+// quotaUsageIncreases reports whether the ClusterDeployment consumes more of
+// the quotas than its deployed HelmRelease. The usage of the not yet deployed
+// ClusterDeployment is always considered increased.
+func (r *ClusterDeploymentReconciler) quotaUsageIncreases(ctx context.Context, cd *kcm.ClusterDeployment) (bool, error) {
+	hr := new(hcv2.HelmRelease)
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(cd), hr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to get HelmRelease %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+
+	deployedValues := make(map[string]any)
+	if hr.Spec.Values != nil {
+		if err := json.Unmarshal(hr.Spec.Values.Raw, &deployedValues); err != nil {
+			return false, fmt.Errorf("failed to parse values of HelmRelease %s/%s: %w", cd.Namespace, cd.Name, err)
+		}
+	}
+	deployed, err := quota.UsageOfValues(deployedValues)
+	if err != nil {
+		return true, nil //nolint:nilerr // the deployed usage is unknown
+	}
+
+	usage, err := quota.UsageOf(cd)
+	if err != nil {
+		return false, fmt.Errorf("failed to compute the usage of the ClusterDeployment: %w", err)
+	}
+
+	return usage.Exceeds(deployed), nil
+}
+
 func (r *ClusterDeploymentReconciler) updateSveltosClusterCondition(ctx context.Context, clusterDeployment *kcm.ClusterDeployment) (bool, error) {
 	sveltosClusters := &libsveltosv1beta1.SveltosClusterList{}
 
@@ -523,7 +708,7 @@ func (r *ClusterDeploymentReconciler) updateServices(ctx context.Context, cd *kc
 		}
 		if err != nil {
 			condition.Message = err.Error()
-			condition.Reason = kcm.FailedReason
+			condition.Reason = errclass.Reason(err)
 			condition.Status = metav1.ConditionFalse
 		}
 		apimeta.SetStatusCondition(&cd.Status.Conditions, condition)
@@ -535,7 +720,7 @@ func (r *ClusterDeploymentReconciler) updateServices(ctx context.Context, cd *kc
 		}
 		if servicesErr != nil {
 			servicesCondition.Message = servicesErr.Error()
-			servicesCondition.Reason = kcm.FailedReason
+			servicesCondition.Reason = errclass.Reason(servicesErr)
 			servicesCondition.Status = metav1.ConditionFalse
 		}
 		apimeta.SetStatusCondition(&cd.Status.Conditions, servicesCondition)
@@ -559,6 +744,10 @@ func (r *ClusterDeploymentReconciler) updateServices(ctx context.Context, cd *kc
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	securityPatches, err := sveltos.GetSecurityPatches(ctx, r.Client, cd.Namespace, cd.Spec.ServiceSpec.Services)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
 	cred := &kcm.Credential{}
 	err = r.Client.Get(ctx, client.ObjectKey{
@@ -569,7 +758,15 @@ func (r *ClusterDeploymentReconciler) updateServices(ctx context.Context, cd *kc
 		return ctrl.Result{}, err
 	}
 
-	if _, err = sveltos.ReconcileProfile(ctx, r.Client, cd.Namespace, cd.Name,
+	var previousProfileSpec *sveltosv1beta1.Spec
+	previousProfile := new(sveltosv1beta1.Profile)
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(cd), previousProfile); err == nil {
+		previousProfileSpec = &previousProfile.Spec
+	} else if !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to get Profile %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+
+	reconciledProfile, err := sveltos.ReconcileProfile(ctx, r.Client, cd.Namespace, cd.Name,
 		sveltos.ReconcileProfileOpts{
 			OwnerReference: &metav1.OwnerReference{
 				APIVersion: kcm.GroupVersion.String(),
@@ -595,10 +792,16 @@ func (r *ClusterDeploymentReconciler) updateServices(ctx context.Context, cd *kc
 			SyncMode:        cd.Spec.ServiceSpec.SyncMode,
 			DriftIgnore:     cd.Spec.ServiceSpec.DriftIgnore,
 			DriftExclusions: cd.Spec.ServiceSpec.DriftExclusions,
+			Patches:         securityPatches,
 			ContinueOnError: cd.Spec.ServiceSpec.ContinueOnError,
-		}); err != nil {
+			CorrelationID:   cd.Status.CorrelationID,
+		})
+	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to reconcile Profile: %w", err)
 	}
+	recordServiceRollout(ctx, r.auditRecorder,
+		kcm.AuditSubject{Kind: kcm.ClusterDeploymentKind, Namespace: cd.Namespace, Name: cd.Name},
+		cd.Spec.ServiceSpec.Services, previousProfileSpec, &reconciledProfile.Spec)
 
 	metrics.TrackMetricTemplateUsage(ctx, kcm.ClusterTemplateKind, cd.Spec.Template, kcm.ClusterDeploymentKind, cd.ObjectMeta, true)
 
@@ -627,30 +830,176 @@ func (r *ClusterDeploymentReconciler) updateServices(ctx context.Context, cd *kc
 			return ctrl.Result{}, nil
 		}
 		cd.Status.Services = servicesStatus
+		r.updateHelmReleaseStatuses(ctx, cd, helmCharts)
 		l.Info("Successfully updated status of services")
 	}
 
 	return ctrl.Result{}, nil
 }
 
-// updateStatus updates the status for the ClusterDeployment object.
-func (r *ClusterDeploymentReconciler) updateStatus(ctx context.Context, cd *kcm.ClusterDeployment, template *kcm.ClusterTemplate) error {
+// updateHelmReleaseStatuses mirrors the state of the Helm releases of the
+// services on the ready cluster into the status of the services. The state
+// is kept as is if the cluster cannot be reached.
+func (r *ClusterDeploymentReconciler) updateHelmReleaseStatuses(ctx context.Context, cd *kcm.ClusterDeployment, helmCharts []sveltosv1beta1.HelmChart) {
+	if r.helmReleaseStatuses == nil || !apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.ReadyCondition) {
+		return
+	}
+
+	idx := slices.IndexFunc(cd.Status.Services, func(s kcm.ServiceStatus) bool {
+		return s.ClusterName == cd.Name && s.ClusterNamespace == cd.Namespace
+	})
+	if idx < 0 {
+		return
+	}
+
+	releases := make([]client.ObjectKey, 0, len(helmCharts))
+	for _, chart := range helmCharts {
+		releases = append(releases, client.ObjectKey{Namespace: chart.ReleaseNamespace, Name: chart.ReleaseName})
+	}
+
+	statuses, err := r.helmReleaseStatuses(ctx, client.ObjectKeyFromObject(cd), releases)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Info("Failed to get the state of the Helm releases of the services", "error", err.Error())
+		return
+	}
+	cd.Status.Services[idx].HelmReleases = statuses
+}
+
+// updateStatus patches the status of the ClusterDeployment object with the
+// changes made since the base copy of it has been read. The status is patched
+// even if some of it has failed to be updated, so the rest of it, e.g. the
+// lifecycle the events of which have been already emitted, is not lost. The
+// events of the conditions changed since the previous ones and the upgrade
+// notification are sent once the status has been patched.
+func (r *ClusterDeploymentReconciler) updateStatus(ctx context.Context, cd, base *kcm.ClusterDeployment, template *kcm.ClusterTemplate, previousConditions []metav1.Condition) error {
 	apimeta.SetStatusCondition(cd.GetConditions(), getServicesReadinessCondition(cd.Status.Services, len(cd.Spec.ServiceSpec.Services)))
 
 	cd.Status.ObservedGeneration = cd.Generation
 	cd.Status.Conditions = updateStatusConditions(cd.Status.Conditions)
 
+	var errs error
 	if err := r.setAvailableUpgrades(ctx, cd, template); err != nil {
-		return errors.New("failed to set available upgrades")
+		errs = errors.Join(errs, fmt.Errorf("failed to set available upgrades: %w", err))
+	}
+
+	applied, upgraded, err := r.updateLifecycle(ctx, cd)
+	errs = errors.Join(errs, err)
+	if err == nil {
+		errs = errors.Join(errs, r.reconcileRevision(ctx, cd, applied))
+	}
+
+	errs = errors.Join(errs, r.updateCost(ctx, cd, template))
+
+	if err := patchStatus(ctx, r.Client, cd, base); err != nil {
+		return errors.Join(errs, fmt.Errorf("failed to update status for clusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err))
+	}
+	recordConditionTransitions(cd, previousConditions, cd.Status.Conditions, clusterDeploymentConditionEvents)
+
+	if upgraded != nil {
+		if err := r.Notifier.Notify(ctx, *upgraded); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to send notification")
+		}
+	}
+
+	return errs
+}
+
+// updateLifecycle records the ClusterTemplate the cluster has been deployed or
+// upgraded with and tracks the lifecycle metrics of the ClusterDeployment.
+// It reports whether the current spec has been successfully applied and
+// returns the notification about the completed upgrade if any.
+func (r *ClusterDeploymentReconciler) updateLifecycle(ctx context.Context, cd *kcm.ClusterDeployment) (applied bool, upgraded *notifications.Notification, _ error) {
+	if !cd.Spec.DryRun && cd.Status.AppliedTemplate != "" && cd.Status.AppliedTemplate != cd.Spec.Template && cd.Status.UpgradeStartTime == nil {
+		now := metav1.Now()
+		cd.Status.UpgradeStartTime = &now
+		record.Eventf(cd, eventReasonUpgradeStarted, "Upgrading the cluster from the ClusterTemplate %s to %s", cd.Status.AppliedTemplate, cd.Spec.Template)
+	}
+
+	// the ready condition of the HelmRelease not yet reconciled after the change
+	// of the template refers to the previous template
+	hr := new(hcv2.HelmRelease)
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(cd), hr); client.IgnoreNotFound(err) != nil {
+		return false, nil, fmt.Errorf("failed to get HelmRelease %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+	applied = apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.ReadyCondition) &&
+		hr.Status.ObservedGeneration == hr.Generation && fluxconditions.IsReady(hr)
+
+	switch {
+	case applied && cd.Status.AppliedTemplate == "":
+		duration := time.Since(cd.CreationTimestamp.Time)
+		metrics.TrackMetricClusterDeploymentProvisioningDuration(ctx, cd.Namespace, cd.Spec.Template, duration)
+		record.Eventf(cd, eventReasonProvisioned, "Cluster is provisioned with the ClusterTemplate %s in %s", cd.Spec.Template, duration.Round(time.Second))
+		cd.Status.AppliedTemplate = cd.Spec.Template
+	case applied && cd.Status.AppliedTemplate != cd.Spec.Template:
+		if cd.Status.UpgradeStartTime != nil {
+			duration := time.Since(cd.Status.UpgradeStartTime.Time)
+			metrics.TrackMetricClusterDeploymentUpgradeDuration(ctx, cd.Namespace, cd.Spec.Template, duration)
+			message := fmt.Sprintf("Cluster is upgraded from the ClusterTemplate %s to %s in %s", cd.Status.AppliedTemplate, cd.Spec.Template, duration.Round(time.Second))
+			record.Event(cd, eventReasonUpgradeSucceeded, message)
+			notification := notifications.ObjectNotification(kcm.NotificationEventUpgradeSucceeded, kcm.ClusterDeploymentKind, cd, message)
+			upgraded = &notification
+		}
+		cd.Status.AppliedTemplate = cd.Spec.Template
+		cd.Status.UpgradeStartTime = nil
+	}
+
+	metrics.TrackMetricClusterDeploymentPhase(ctx, cd.Namespace, cd.Name, clusterDeploymentPhase(cd))
+	metrics.TrackMetricClusterDeploymentServicesReady(ctx, cd.Namespace, cd.Name, readyServicesCount(cd.Status.Services))
+	return applied, upgraded, nil
+}
+
+// updateCost estimates the cost of the cluster if the cost estimation is
+// enabled in the Management.
+func (r *ClusterDeploymentReconciler) updateCost(ctx context.Context, cd *kcm.ClusterDeployment, template *kcm.ClusterTemplate) error {
+	mgmt := new(kcm.Management)
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: kcm.ManagementName}, mgmt); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to get Management: %w", err)
 	}
 
-	if err := r.Client.Status().Update(ctx, cd); err != nil {
-		return fmt.Errorf("failed to update status for clusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err)
+	if mgmt.Spec.CostEstimation == nil || cd.Spec.DryRun {
+		cd.Status.Cost = nil
+		metrics.DeleteMetricClusterDeploymentEstimatedMonthlyCost(cd.Namespace, cd.Name)
+		return nil
+	}
+
+	estimate, err := cost.Estimate(mgmt.Spec.CostEstimation, cd, template.Status.Providers)
+	if err != nil {
+		return fmt.Errorf("failed to estimate cost of ClusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err)
 	}
+	cd.Status.Cost = estimate
 
+	monthly, _ := strconv.ParseFloat(estimate.MonthlyCost, 64)
+	metrics.TrackMetricClusterDeploymentEstimatedMonthlyCost(ctx, cd.Namespace, cd.Name, estimate.Currency, monthly)
 	return nil
 }
 
+// clusterDeploymentPhase returns the phase of the ClusterDeployment reported by the metrics.
+func clusterDeploymentPhase(cd *kcm.ClusterDeployment) string {
+	switch {
+	case !cd.DeletionTimestamp.IsZero():
+		return metrics.ClusterDeploymentPhaseDeleting
+	case apimeta.IsStatusConditionFalse(cd.Status.Conditions, kcm.ReadyCondition):
+		return metrics.ClusterDeploymentPhaseFailed
+	case cd.Status.AppliedTemplate == "":
+		return metrics.ClusterDeploymentPhaseProvisioning
+	case cd.Status.AppliedTemplate != cd.Spec.Template:
+		return metrics.ClusterDeploymentPhaseUpgrading
+	case apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.ReadyCondition):
+		return metrics.ClusterDeploymentPhaseReady
+	default:
+		// the deployed cluster is reconciled again, e.g. after the change of its configuration
+		return metrics.ClusterDeploymentPhaseProvisioning
+	}
+}
+
+// trackHelmReleaseFailure counts the failure of the HelmRelease of the
+// ClusterDeployment once its ready condition turns to false.
+func trackHelmReleaseFailure(ctx context.Context, cd *kcm.ClusterDeployment, status metav1.ConditionStatus) {
+	if status == metav1.ConditionFalse && !apimeta.IsStatusConditionFalse(cd.Status.Conditions, kcm.HelmReleaseReadyCondition) {
+		metrics.TrackMetricClusterDeploymentHelmReleaseFailure(ctx, cd.Namespace, cd.Name)
+	}
+}
+
 func (r *ClusterDeploymentReconciler) getSource(ctx context.Context, ref *hcv2.CrossNamespaceSourceReference) (sourcev1.Source, error) {
 	if ref == nil {
 		return nil, errors.New("helm chart source is not provided")
@@ -666,6 +1015,8 @@ func (r *ClusterDeploymentReconciler) getSource(ctx context.Context, ref *hcv2.C
 func (r *ClusterDeploymentReconciler) Delete(ctx context.Context, cd *kcm.ClusterDeployment) (result ctrl.Result, err error) {
 	l := ctrl.LoggerFrom(ctx)
 
+	metrics.TrackMetricClusterDeploymentPhase(ctx, cd.Namespace, cd.Name, metrics.ClusterDeploymentPhaseDeleting)
+
 	defer func() {
 		if err == nil {
 			metrics.TrackMetricTemplateUsage(ctx, kcm.ClusterTemplateKind, cd.Spec.Template, kcm.ClusterDeploymentKind, cd.ObjectMeta, false)
@@ -688,7 +1039,11 @@ func (r *ClusterDeploymentReconciler) Delete(ctx context.Context, cd *kcm.Cluste
 			if err := r.Client.Update(ctx, cd); err != nil {
 				return ctrl.Result{}, fmt.Errorf("failed to update clusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err)
 			}
+			recordAuditEvent(ctx, r.auditRecorder, kcm.AuditActionClusterDelete,
+				kcm.AuditSubject{Kind: kcm.ClusterDeploymentKind, Namespace: cd.Namespace, Name: cd.Name},
+				"ClusterDeployment deleted", map[string]string{"template": cd.Spec.Template})
 		}
+		metrics.DeleteMetricsClusterDeployment(cd.Namespace, cd.Name)
 		l.Info("ClusterDeployment deleted")
 		return ctrl.Result{}, nil
 	}
@@ -858,15 +1213,34 @@ func (r *ClusterDeploymentReconciler) setAvailableUpgrades(ctx context.Context,
 func (r *ClusterDeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.Client = mgr.GetClient()
 	r.Config = mgr.GetConfig()
+	r.revisionReader = mgr.GetAPIReader()
+	r.auditRecorder = &audit.Recorder{Client: r.Client, SystemNamespace: r.SystemNamespace, Controller: "clusterdeployment"}
 
 	r.helmActor = helm.NewActor(r.Config, r.Client.RESTMapper())
 
-	r.defaultRequeueTime = 10 * time.Second
+	if r.helmReleaseStatuses == nil {
+		newClientset := clusterclient.NewClientsetFunc(r.Client, "kcm-services")
+		r.helmReleaseStatuses = func(ctx context.Context, cluster client.ObjectKey, releases []client.ObjectKey) ([]kcm.ServiceHelmReleaseStatus, error) {
+			clientset, err := newClientset(ctx, cluster)
+			if err != nil {
+				return nil, err
+			}
+			return helm.GetReleaseStatuses(ctx, clientset, releases)
+		}
+	}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		WithOptions(controller.TypedOptions[ctrl.Request]{
-			RateLimiter: ratelimit.DefaultFastSlow(),
-		}).
+	r.defaultRequeueTime = cmp.Or(r.RequeueInterval, 10*time.Second)
+
+	opts := controller.TypedOptions[ctrl.Request]{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+		RateLimiter:             ratelimit.DefaultFastSlow(),
+	}
+	if r.Sharder != nil {
+		opts.NeedLeaderElection = ptr.To(false)
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(opts).
 		For(&kcm.ClusterDeployment{}).
 		Watches(&hcv2.HelmRelease{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []ctrl.Request {
@@ -940,5 +1314,103 @@ func (r *ClusterDeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				return req
 			}),
 		).
-		Complete(r)
+		Watches(&kcm.ClusterQuota{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []ctrl.Request {
+				clusterDeployments := &kcm.ClusterDeploymentList{}
+				if err := r.Client.List(ctx, clusterDeployments, client.InNamespace(o.GetNamespace())); err != nil {
+					return []ctrl.Request{}
+				}
+
+				req := make([]ctrl.Request, 0, len(clusterDeployments.Items))
+				for _, cluster := range clusterDeployments.Items {
+					req = append(req, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&cluster)})
+				}
+
+				return req
+			}),
+			builder.WithPredicates(predicate.Funcs{
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			}),
+		)
+
+	if r.Sharder != nil {
+		// the ClusterDeployments of the shard filtered out before it was
+		// acquired are enqueued once it is
+		b = b.WatchesRawSource(source.Channel(r.Sharder.Acquired(),
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []ctrl.Request {
+				clusterDeployments := &kcm.ClusterDeploymentList{}
+				if err := r.Client.List(ctx, clusterDeployments); err != nil {
+					return []ctrl.Request{}
+				}
+
+				var req []ctrl.Request
+				for _, cluster := range clusterDeployments.Items {
+					if r.Sharder.Owns(&cluster) {
+						req = append(req, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&cluster)})
+					}
+				}
+				return req
+			}),
+		))
+	}
+
+	return b.Complete(r)
+}
+
+// applyAuthenticationValues sets the k0s.auth values of the cluster
+// templates from the given authentication spec.
+func applyAuthenticationValues(values map[string]any, auth *kcm.ClusterAuthentication) error {
+	if auth == nil {
+		return nil
+	}
+
+	var config map[string]any
+	switch {
+	case auth.Config != nil:
+		if err := json.Unmarshal(auth.Config.Raw, &config); err != nil {
+			return fmt.Errorf("failed to decode authentication config: %w", err)
+		}
+	case auth.OIDC != nil:
+		issuer := map[string]any{
+			"url":       auth.OIDC.IssuerURL,
+			"audiences": []any{auth.OIDC.ClientID},
+		}
+		if auth.OIDC.CertificateAuthority != "" {
+			issuer["certificateAuthority"] = auth.OIDC.CertificateAuthority
+		}
+
+		usernameClaim := auth.OIDC.UsernameClaim
+		if usernameClaim == "" {
+			usernameClaim = "sub"
+		}
+		claimMappings := map[string]any{
+			"username": map[string]any{"claim": usernameClaim, "prefix": auth.OIDC.UsernamePrefix},
+		}
+		if auth.OIDC.GroupsClaim != "" {
+			claimMappings["groups"] = map[string]any{"claim": auth.OIDC.GroupsClaim, "prefix": auth.OIDC.GroupsPrefix}
+		}
+
+		config = map[string]any{
+			"apiVersion": "apiserver.config.k8s.io/v1beta1",
+			"kind":       "AuthenticationConfiguration",
+			"jwt": []any{map[string]any{
+				"issuer":        issuer,
+				"claimMappings": claimMappings,
+			}},
+		}
+	default:
+		return nil
+	}
+
+	k0s, ok := values["k0s"].(map[string]any)
+	if !ok {
+		k0s = make(map[string]any)
+		values["k0s"] = k0s
+	}
+	k0s["auth"] = map[string]any{
+		"enabled": true,
+		"config":  config,
+	}
+
+	return nil
 }
@@ -16,6 +16,9 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"testing"
 	"time"
 
 	hcv2 "github.com/fluxcd/helm-controller/api/v2"
@@ -23,6 +26,7 @@ import (
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	sveltosv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	corev1 "k8s.io/api/core/v1"
@@ -32,11 +36,15 @@ import (
 	"k8s.io/client-go/rest"
 	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/test/scheme"
 )
 
 type fakeHelmActor struct{}
@@ -588,3 +596,198 @@ var _ = Describe("ClusterDeployment Controller", func() {
 		})
 	})
 })
+
+func Test_applyAuthenticationValues(t *testing.T) {
+	for _, tc := range []struct {
+		auth     *kcm.ClusterAuthentication
+		values   map[string]any
+		name     string
+		expected string
+	}{
+		{
+			name:     "no authentication",
+			values:   map[string]any{"k0s": map[string]any{"version": "v1.32.1+k0s.0"}},
+			expected: `{"k0s":{"version":"v1.32.1+k0s.0"}}`,
+		},
+		{
+			name: "oidc",
+			auth: &kcm.ClusterAuthentication{OIDC: &kcm.OIDCAuthentication{
+				IssuerURL:      "https://issuer.example.com",
+				ClientID:       "kcm",
+				UsernameClaim:  "email",
+				UsernamePrefix: "oidc:",
+				GroupsClaim:    "groups",
+			}},
+			values: map[string]any{"k0s": map[string]any{"version": "v1.32.1+k0s.0"}},
+			expected: `{"k0s":{"version":"v1.32.1+k0s.0","auth":{"enabled":true,"config":{
+				"apiVersion":"apiserver.config.k8s.io/v1beta1",
+				"kind":"AuthenticationConfiguration",
+				"jwt":[{
+					"issuer":{"url":"https://issuer.example.com","audiences":["kcm"]},
+					"claimMappings":{"username":{"claim":"email","prefix":"oidc:"},"groups":{"claim":"groups","prefix":""}}
+				}]
+			}}}}`,
+		},
+		{
+			name:     "structured config overrides the values",
+			auth:     &kcm.ClusterAuthentication{Config: &apiextensionsv1.JSON{Raw: []byte(`{"kind":"AuthenticationConfiguration","jwt":[]}`)}},
+			values:   map[string]any{"k0s": map[string]any{"auth": map[string]any{"enabled": false}}},
+			expected: `{"k0s":{"auth":{"enabled":true,"config":{"kind":"AuthenticationConfiguration","jwt":[]}}}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(applyAuthenticationValues(tc.values, tc.auth)).To(Succeed())
+
+			actual, err := json.Marshal(tc.values)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(actual)).To(MatchJSON(tc.expected))
+		})
+	}
+}
+
+func Test_clusterDeploymentPhase(t *testing.T) {
+	readyCondition := func(status metav1.ConditionStatus) []metav1.Condition {
+		return []metav1.Condition{{Type: kcm.ReadyCondition, Status: status}}
+	}
+
+	for _, tc := range []struct {
+		cd       *kcm.ClusterDeployment
+		name     string
+		expected string
+	}{
+		{
+			name:     "not yet deployed",
+			cd:       &kcm.ClusterDeployment{Spec: kcm.ClusterDeploymentSpec{Template: "t1"}, Status: kcm.ClusterDeploymentStatus{Conditions: readyCondition(metav1.ConditionUnknown)}},
+			expected: metrics.ClusterDeploymentPhaseProvisioning,
+		},
+		{
+			name:     "ready",
+			cd:       &kcm.ClusterDeployment{Spec: kcm.ClusterDeploymentSpec{Template: "t1"}, Status: kcm.ClusterDeploymentStatus{AppliedTemplate: "t1", Conditions: readyCondition(metav1.ConditionTrue)}},
+			expected: metrics.ClusterDeploymentPhaseReady,
+		},
+		{
+			name:     "upgrading",
+			cd:       &kcm.ClusterDeployment{Spec: kcm.ClusterDeploymentSpec{Template: "t2"}, Status: kcm.ClusterDeploymentStatus{AppliedTemplate: "t1", Conditions: readyCondition(metav1.ConditionTrue)}},
+			expected: metrics.ClusterDeploymentPhaseUpgrading,
+		},
+		{
+			name:     "failed",
+			cd:       &kcm.ClusterDeployment{Spec: kcm.ClusterDeploymentSpec{Template: "t2"}, Status: kcm.ClusterDeploymentStatus{AppliedTemplate: "t1", Conditions: readyCondition(metav1.ConditionFalse)}},
+			expected: metrics.ClusterDeploymentPhaseFailed,
+		},
+		{
+			name: "deleting",
+			cd: &kcm.ClusterDeployment{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &metav1.Time{Time: time.Now()}},
+				Spec:       kcm.ClusterDeploymentSpec{Template: "t1"},
+				Status:     kcm.ClusterDeploymentStatus{AppliedTemplate: "t1", Conditions: readyCondition(metav1.ConditionTrue)},
+			},
+			expected: metrics.ClusterDeploymentPhaseDeleting,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(clusterDeploymentPhase(tc.cd)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func Test_updateHelmReleaseStatuses(t *testing.T) {
+	g := NewWithT(t)
+
+	statuses := []kcm.ServiceHelmReleaseStatus{{Namespace: "ingress", Name: "ingress-nginx", ChartVersion: "4.12.1", Revision: 2, Status: "deployed"}}
+	var requested []client.ObjectKey
+	r := &ClusterDeploymentReconciler{
+		helmReleaseStatuses: func(_ context.Context, cluster client.ObjectKey, releases []client.ObjectKey) ([]kcm.ServiceHelmReleaseStatus, error) {
+			g.Expect(cluster).To(Equal(client.ObjectKey{Namespace: "ns", Name: "cd"}))
+			requested = releases
+			return statuses, nil
+		},
+	}
+	helmCharts := []sveltosv1beta1.HelmChart{{ReleaseNamespace: "ingress", ReleaseName: "ingress-nginx"}}
+
+	cd := &kcm.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cd"},
+		Status: kcm.ClusterDeploymentStatus{
+			Conditions: []metav1.Condition{{Type: kcm.ReadyCondition, Status: metav1.ConditionFalse}},
+			Services:   []kcm.ServiceStatus{{ClusterNamespace: "ns", ClusterName: "cd"}},
+		},
+	}
+	r.updateHelmReleaseStatuses(t.Context(), cd, helmCharts)
+	g.Expect(requested).To(BeNil())
+	g.Expect(cd.Status.Services[0].HelmReleases).To(BeNil())
+
+	cd.Status.Conditions[0].Status = metav1.ConditionTrue
+	r.updateHelmReleaseStatuses(t.Context(), cd, helmCharts)
+	g.Expect(requested).To(Equal([]client.ObjectKey{{Namespace: "ingress", Name: "ingress-nginx"}}))
+	g.Expect(cd.Status.Services[0].HelmReleases).To(Equal(statuses))
+
+	r.helmReleaseStatuses = func(context.Context, client.ObjectKey, []client.ObjectKey) ([]kcm.ServiceHelmReleaseStatus, error) {
+		return nil, errors.New("cluster is unreachable")
+	}
+	r.updateHelmReleaseStatuses(t.Context(), cd, helmCharts)
+	g.Expect(cd.Status.Services[0].HelmReleases).To(Equal(statuses))
+}
+
+func TestClusterDeploymentReconciler_quotaUsageIncreases(t *testing.T) {
+	g := NewWithT(t)
+
+	cd := &kcm.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cd"},
+		Spec:       kcm.ClusterDeploymentSpec{Config: &apiextensionsv1.JSON{Raw: []byte(`{"workersNumber":2}`)}},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	r := &ClusterDeploymentReconciler{Client: cl}
+
+	increases, err := r.quotaUsageIncreases(t.Context(), cd)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(increases).To(BeTrue(), "the usage of the not deployed cluster should increase")
+
+	hr := &hcv2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cd"},
+		Spec:       hcv2.HelmReleaseSpec{Values: &apiextensionsv1.JSON{Raw: []byte(`{"workersNumber":3,"clusterIdentity":{"name":"cred"}}`)}},
+	}
+	g.Expect(cl.Create(t.Context(), hr)).To(Succeed())
+
+	increases, err = r.quotaUsageIncreases(t.Context(), cd)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(increases).To(BeFalse(), "the scale down of the deployed cluster should not increase the usage")
+
+	cd.Spec.Config = &apiextensionsv1.JSON{Raw: []byte(`{"workersNumber":4}`)}
+	increases, err = r.quotaUsageIncreases(t.Context(), cd)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(increases).To(BeTrue())
+}
+
+func TestClusterDeploymentReconciler_updateStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	cd := &kcm.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default", Generation: 2},
+		Spec:       kcm.ClusterDeploymentSpec{Template: "aws-standalone-cp-0-1-0", DryRun: true},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.ClusterDeployment{}).
+		WithObjects(cd).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*kcm.Management); ok {
+					return errors.New("management is unavailable")
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+	r := &ClusterDeploymentReconciler{Client: cl}
+
+	base := cd.DeepCopy()
+	err := r.updateStatus(t.Context(), cd, base, nil, nil)
+	g.Expect(err).To(MatchError(ContainSubstring("management is unavailable")))
+
+	// the status is patched regardless of the failed cost estimation
+	persisted := new(kcm.ClusterDeployment)
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(cd), persisted)).To(Succeed())
+	g.Expect(persisted.Status.ObservedGeneration).To(Equal(int64(2)))
+}
@@ -0,0 +1,81 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/record"
+)
+
+// restoreRequeueInterval is the interval the restored ClusterDeployments are
+// checked at until the Velero Restore completes.
+const restoreRequeueInterval = 15 * time.Second
+
+// waitForRestore holds the reconciliation of the ClusterDeployment restored by
+// Velero until its Restore has completed. The HelmRelease of the cluster is
+// not backed up, so holding it off ensures the cluster is recreated only once
+// all of the restored objects of the infrastructure and control plane
+// providers are in place, so the providers adopt the existing workload
+// cluster instead of provisioning a new one.
+//
+// Once the Restore has completed, the velero labels are removed from the
+// ClusterDeployment and the reconciliation proceeds. The ClusterDeployment is
+// held until the labels are removed manually if the Restore has failed.
+func (r *ClusterDeploymentReconciler) waitForRestore(ctx context.Context, cd *kcm.ClusterDeployment) (ctrl.Result, bool, error) {
+	restoreName := cd.Labels[velerov1.RestoreNameLabel]
+	if restoreName == "" {
+		return ctrl.Result{}, false, nil
+	}
+
+	l := ctrl.LoggerFrom(ctx).WithValues("restore", restoreName)
+
+	restore := new(velerov1.Restore)
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: r.SystemNamespace, Name: restoreName}, restore)
+	if err != nil && !isVeleroMissingError(err) {
+		return ctrl.Result{}, true, fmt.Errorf("failed to get velero Restore %s: %w", restoreName, err)
+	}
+
+	// the Restore might have been already deleted or velero uninstalled
+	if err == nil {
+		switch restore.Status.Phase {
+		case velerov1.RestorePhaseCompleted, velerov1.RestorePhasePartiallyFailed:
+		case velerov1.RestorePhaseFailed, velerov1.RestorePhaseFailedValidation:
+			l.Info("Restore has failed, ClusterDeployment is not reconciled until the velero labels are removed", "phase", restore.Status.Phase)
+			record.Warnf(cd, eventReasonRestoreFailed, "Restore %s has finished in the %s phase, remove the %s label to proceed", restoreName, restore.Status.Phase, velerov1.RestoreNameLabel)
+			return ctrl.Result{}, true, nil
+		default:
+			l.Info("Waiting for the restore to complete", "phase", restore.Status.Phase)
+			return ctrl.Result{RequeueAfter: restoreRequeueInterval}, true, nil
+		}
+	}
+
+	patch := client.MergeFrom(cd.DeepCopy())
+	delete(cd.Labels, velerov1.RestoreNameLabel)
+	delete(cd.Labels, velerov1.BackupNameLabel)
+	if err := r.Client.Patch(ctx, cd, patch); err != nil {
+		return ctrl.Result{}, true, fmt.Errorf("failed to remove velero labels of ClusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+
+	record.Eventf(cd, eventReasonRestored, "Restored by the restore %s", restoreName)
+	return ctrl.Result{}, true, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestClusterDeploymentReconciler_waitForRestore(t *testing.T) {
+	const systemNamespace = "kcm-system"
+
+	newClusterDeployment := func(restoreName string) *kcm.ClusterDeployment {
+		cd := &kcm.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+		if restoreName != "" {
+			cd.Labels = map[string]string{velerov1.RestoreNameLabel: restoreName, velerov1.BackupNameLabel: "backup"}
+		}
+		return cd
+	}
+	newRestore := func(phase velerov1.RestorePhase) *velerov1.Restore {
+		return &velerov1.Restore{
+			ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: systemNamespace},
+			Status:     velerov1.RestoreStatus{Phase: phase},
+		}
+	}
+
+	for _, tc := range []struct {
+		cd            *kcm.ClusterDeployment
+		restore       *velerov1.Restore
+		name          string
+		restoring     bool
+		requeue       bool
+		labelsRemoved bool
+	}{
+		{
+			name: "not restored",
+			cd:   newClusterDeployment(""),
+		},
+		{
+			name:      "restore in progress",
+			cd:        newClusterDeployment("restore"),
+			restore:   newRestore(velerov1.RestorePhaseInProgress),
+			restoring: true,
+			requeue:   true,
+		},
+		{
+			name:          "restore completed",
+			cd:            newClusterDeployment("restore"),
+			restore:       newRestore(velerov1.RestorePhaseCompleted),
+			restoring:     true,
+			labelsRemoved: true,
+		},
+		{
+			name:      "restore failed",
+			cd:        newClusterDeployment("restore"),
+			restore:   newRestore(velerov1.RestorePhaseFailed),
+			restoring: true,
+		},
+		{
+			name:          "restore deleted",
+			cd:            newClusterDeployment("restore"),
+			restoring:     true,
+			labelsRemoved: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs := []client.Object{tc.cd}
+			if tc.restore != nil {
+				objs = append(objs, tc.restore)
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build()
+			r := &ClusterDeploymentReconciler{Client: cl, SystemNamespace: systemNamespace}
+
+			res, restoring, err := r.waitForRestore(t.Context(), tc.cd)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(restoring).To(Equal(tc.restoring))
+			g.Expect(res.RequeueAfter > 0).To(Equal(tc.requeue))
+
+			cd := new(kcm.ClusterDeployment)
+			g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(tc.cd), cd)).To(Succeed())
+			if tc.labelsRemoved {
+				g.Expect(cd.Labels).NotTo(HaveKey(velerov1.RestoreNameLabel))
+				g.Expect(cd.Labels).NotTo(HaveKey(velerov1.BackupNameLabel))
+			} else if tc.restoring {
+				g.Expect(cd.Labels).To(HaveKey(velerov1.RestoreNameLabel))
+			}
+		})
+	}
+}
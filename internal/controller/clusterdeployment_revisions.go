@@ -0,0 +1,267 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/notifications"
+	"github.com/K0rdent/kcm/internal/record"
+)
+
+// defaultRevisionHistoryLimit is the number of the retained revisions if
+// the limit is not set in the spec.
+const defaultRevisionHistoryLimit = 10
+
+// reconcileRevision records the new revision once the applied part of the
+// spec of the ClusterDeployment is changed, updates the outcome of the
+// application of the current revision and prunes the revisions exceeding
+// the history limit.
+func (r *ClusterDeploymentReconciler) reconcileRevision(ctx context.Context, cd *kcm.ClusterDeployment, applied bool) error {
+	if cd.Spec.DryRun {
+		return nil
+	}
+
+	revisions, err := r.listRevisions(ctx, cd)
+	if err != nil {
+		return err
+	}
+
+	var current *kcm.ClusterDeploymentRevision
+	if len(revisions) > 0 {
+		current = &revisions[len(revisions)-1]
+	}
+
+	if current == nil || !revisionMatches(current, cd) {
+		if current != nil && current.Status.Outcome == kcm.RevisionOutcomeProgressing {
+			if err := r.setRevisionOutcome(ctx, current, kcm.RevisionOutcomeSuperseded, ""); err != nil {
+				return err
+			}
+		}
+
+		next, err := r.createRevision(ctx, cd, current)
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			r.recordRevisionChanges(ctx, cd, current, next)
+		}
+		revisions = append(revisions, *next)
+		current = next
+	}
+	cd.Status.Revision = current.Spec.Revision
+
+	if outcome, message := revisionOutcome(cd, applied); outcome != "" && current.Status.Outcome != kcm.RevisionOutcomeSucceeded && current.Status.Outcome != outcome {
+		if err := r.setRevisionOutcome(ctx, current, outcome, message); err != nil {
+			return err
+		}
+
+		if outcome == kcm.RevisionOutcomeFailed {
+			message = fmt.Sprintf("Revision %d with the ClusterTemplate %s has failed: %s", current.Spec.Revision, current.Spec.Template, message)
+			if err := r.Notifier.Notify(ctx, notifications.ObjectNotification(kcm.NotificationEventClusterDeploymentFailed, kcm.ClusterDeploymentKind, cd, message)); err != nil {
+				ctrl.LoggerFrom(ctx).Error(err, "failed to send notification")
+			}
+		}
+	}
+
+	return r.pruneRevisions(ctx, cd, revisions)
+}
+
+// recordRevisionChanges records the changes of the template and the
+// credential applied with the next revision to the audit trail.
+func (r *ClusterDeploymentReconciler) recordRevisionChanges(ctx context.Context, cd *kcm.ClusterDeployment, previous, next *kcm.ClusterDeploymentRevision) {
+	subject := kcm.AuditSubject{Kind: kcm.ClusterDeploymentKind, Namespace: cd.Namespace, Name: cd.Name}
+
+	if previous.Spec.Template != next.Spec.Template {
+		recordAuditEvent(ctx, r.auditRecorder, kcm.AuditActionTemplateUpgrade, subject,
+			fmt.Sprintf("ClusterTemplate changed from %s to %s", previous.Spec.Template, next.Spec.Template),
+			map[string]string{"oldTemplate": previous.Spec.Template, "newTemplate": next.Spec.Template})
+	}
+
+	if previous.Spec.Credential != next.Spec.Credential {
+		recordAuditEvent(ctx, r.auditRecorder, kcm.AuditActionCredentialChange, subject,
+			fmt.Sprintf("Credential changed from %s to %s", previous.Spec.Credential, next.Spec.Credential),
+			map[string]string{"oldCredential": previous.Spec.Credential, "newCredential": next.Spec.Credential})
+	}
+}
+
+// listRevisions returns the revisions of the ClusterDeployment in the ascending
+// order read from the API server.
+func (r *ClusterDeploymentReconciler) listRevisions(ctx context.Context, cd *kcm.ClusterDeployment) ([]kcm.ClusterDeploymentRevision, error) {
+	var reader client.Reader = r.Client
+	if r.revisionReader != nil {
+		reader = r.revisionReader
+	}
+
+	list := new(kcm.ClusterDeploymentRevisionList)
+	if err := reader.List(ctx, list, client.InNamespace(cd.Namespace), client.MatchingLabels{kcm.ClusterDeploymentNameLabel: cd.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterDeploymentRevisions of %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+
+	slices.SortFunc(list.Items, func(a, b kcm.ClusterDeploymentRevision) int {
+		return int(a.Spec.Revision - b.Spec.Revision)
+	})
+	return list.Items, nil
+}
+
+func (r *ClusterDeploymentReconciler) createRevision(ctx context.Context, cd *kcm.ClusterDeployment, previous *kcm.ClusterDeploymentRevision) (*kcm.ClusterDeploymentRevision, error) {
+	number := int64(1)
+	if previous != nil {
+		number = previous.Spec.Revision + 1
+	}
+
+	rev := &kcm.ClusterDeploymentRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      revisionName(cd.Name, number),
+			Namespace: cd.Namespace,
+			Labels:    map[string]string{kcm.ClusterDeploymentNameLabel: cd.Name},
+		},
+		Spec: kcm.ClusterDeploymentRevisionSpec{
+			ClusterDeploymentName: cd.Name,
+			Revision:              number,
+			Template:              cd.Spec.Template,
+			Credential:            cd.Spec.Credential,
+			Config:                cd.Spec.Config.DeepCopy(),
+		},
+	}
+	if err := controllerutil.SetOwnerReference(cd, rev, r.Client.Scheme()); err != nil {
+		return nil, fmt.Errorf("failed to set owner of ClusterDeploymentRevision %s/%s: %w", rev.Namespace, rev.Name, err)
+	}
+
+	if err := r.Client.Create(ctx, rev); err != nil {
+		return nil, fmt.Errorf("failed to create ClusterDeploymentRevision %s/%s: %w", rev.Namespace, rev.Name, err)
+	}
+
+	now := metav1.Now()
+	rev.Status = kcm.ClusterDeploymentRevisionStatus{AppliedTime: &now, Outcome: kcm.RevisionOutcomeProgressing}
+	if err := r.Client.Status().Update(ctx, rev); err != nil {
+		return nil, fmt.Errorf("failed to update ClusterDeploymentRevision %s/%s status: %w", rev.Namespace, rev.Name, err)
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Recorded ClusterDeploymentRevision", "revision", number, "template", cd.Spec.Template)
+	return rev, nil
+}
+
+func (r *ClusterDeploymentReconciler) setRevisionOutcome(ctx context.Context, rev *kcm.ClusterDeploymentRevision, outcome kcm.RevisionOutcome, message string) error {
+	patch := client.MergeFrom(rev.DeepCopy())
+	now := metav1.Now()
+	rev.Status.Outcome, rev.Status.Message, rev.Status.CompletionTime = outcome, message, &now
+	if err := r.Client.Status().Patch(ctx, rev, patch); err != nil {
+		return fmt.Errorf("failed to update ClusterDeploymentRevision %s/%s status: %w", rev.Namespace, rev.Name, err)
+	}
+	return nil
+}
+
+// pruneRevisions removes the oldest revisions exceeding the history limit.
+func (r *ClusterDeploymentReconciler) pruneRevisions(ctx context.Context, cd *kcm.ClusterDeployment, revisions []kcm.ClusterDeploymentRevision) error {
+	limit := defaultRevisionHistoryLimit
+	if cd.Spec.RevisionHistoryLimit != nil {
+		limit = int(*cd.Spec.RevisionHistoryLimit)
+	}
+
+	for i := 0; i < len(revisions)-limit; i++ {
+		if err := r.Client.Delete(ctx, &revisions[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete ClusterDeploymentRevision %s/%s: %w", revisions[i].Namespace, revisions[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// rollback applies the spec of the revision requested by the rollback
+// annotation to the ClusterDeployment and reports whether it has been updated.
+func (r *ClusterDeploymentReconciler) rollback(ctx context.Context, cd *kcm.ClusterDeployment) (bool, error) {
+	value, ok := cd.Annotations[kcm.ClusterDeploymentRollbackAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	rev := new(kcm.ClusterDeploymentRevision)
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		err = r.Client.Get(ctx, client.ObjectKey{Namespace: cd.Namespace, Name: revisionName(cd.Name, number)}, rev)
+	}
+	if err != nil {
+		if !apierrors.IsNotFound(err) && !errors.Is(err, strconv.ErrSyntax) && !errors.Is(err, strconv.ErrRange) {
+			return false, fmt.Errorf("failed to get ClusterDeploymentRevision %s of %s/%s: %w", value, cd.Namespace, cd.Name, err)
+		}
+		record.Warnf(cd, eventReasonRollbackFailed, "Revision %s is not found", value)
+		return true, r.removeRollbackAnnotation(ctx, cd)
+	}
+
+	updated := cd.DeepCopy()
+	delete(updated.Annotations, kcm.ClusterDeploymentRollbackAnnotation)
+	updated.Spec.Template = rev.Spec.Template
+	updated.Spec.Credential = rev.Spec.Credential
+	updated.Spec.Config = rev.Spec.Config.DeepCopy()
+	if err := r.Client.Update(ctx, updated); err != nil {
+		if apierrors.IsInvalid(err) || apierrors.IsForbidden(err) {
+			record.Warnf(cd, eventReasonRollbackFailed, "Rollback to the revision %d is rejected: %s", number, err)
+			return true, r.removeRollbackAnnotation(ctx, cd)
+		}
+		return false, fmt.Errorf("failed to roll back ClusterDeployment %s/%s to revision %d: %w", cd.Namespace, cd.Name, number, err)
+	}
+
+	record.Eventf(cd, eventReasonRollbackStarted, "Rolling back to the revision %d with the ClusterTemplate %s", number, rev.Spec.Template)
+	return true, nil
+}
+
+func (r *ClusterDeploymentReconciler) removeRollbackAnnotation(ctx context.Context, cd *kcm.ClusterDeployment) error {
+	patch := client.MergeFrom(cd.DeepCopy())
+	delete(cd.Annotations, kcm.ClusterDeploymentRollbackAnnotation)
+	if err := r.Client.Patch(ctx, cd, patch); err != nil {
+		return fmt.Errorf("failed to remove rollback annotation of ClusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+	return nil
+}
+
+func revisionName(clusterDeploymentName string, number int64) string {
+	return clusterDeploymentName + "-" + strconv.FormatInt(number, 10)
+}
+
+// revisionMatches reports whether the revision records the current spec of the ClusterDeployment.
+func revisionMatches(rev *kcm.ClusterDeploymentRevision, cd *kcm.ClusterDeployment) bool {
+	return rev.Spec.Template == cd.Spec.Template &&
+		rev.Spec.Credential == cd.Spec.Credential &&
+		apiequality.Semantic.DeepEqual(rev.Spec.Config, cd.Spec.Config)
+}
+
+// revisionOutcome returns the outcome of the application of the current spec
+// of the ClusterDeployment, empty while it is still being applied.
+func revisionOutcome(cd *kcm.ClusterDeployment, applied bool) (kcm.RevisionOutcome, string) {
+	if applied {
+		return kcm.RevisionOutcomeSucceeded, ""
+	}
+
+	for _, typ := range []string{kcm.TemplateReadyCondition, kcm.HelmChartReadyCondition, kcm.HelmReleaseReadyCondition} {
+		if c := apimeta.FindStatusCondition(cd.Status.Conditions, typ); c != nil && c.Status == metav1.ConditionFalse && c.Reason != kcm.ProgressingReason {
+			return kcm.RevisionOutcomeFailed, c.Message
+		}
+	}
+
+	return "", ""
+}
@@ -0,0 +1,176 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/audit"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestClusterDeploymentReconciler_reconcileRevision(t *testing.T) {
+	g := NewWithT(t)
+
+	cd := &kcm.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: kcm.ClusterDeploymentSpec{
+			Template:             "aws-standalone-cp-0-1-0",
+			Credential:           "aws-cred",
+			Config:               &apiextensionsv1.JSON{Raw: []byte(`{"region":"us-east-2"}`)},
+			RevisionHistoryLimit: ptr.To[int32](2),
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.ClusterDeploymentRevision{}).
+		WithObjects(cd).Build()
+	r := &ClusterDeploymentReconciler{Client: cl, auditRecorder: &audit.Recorder{Client: cl, Controller: "clusterdeployment"}}
+
+	revisions := func() []kcm.ClusterDeploymentRevision {
+		revs, err := r.listRevisions(t.Context(), cd)
+		g.Expect(err).NotTo(HaveOccurred())
+		return revs
+	}
+
+	g.Expect(r.reconcileRevision(t.Context(), cd, false)).To(Succeed())
+	g.Expect(cd.Status.Revision).To(Equal(int64(1)))
+	revs := revisions()
+	g.Expect(revs).To(HaveLen(1))
+	g.Expect(revs[0].Name).To(Equal("cluster-1"))
+	g.Expect(revs[0].Spec.Template).To(Equal(cd.Spec.Template))
+	g.Expect(revs[0].Status.Outcome).To(Equal(kcm.RevisionOutcomeProgressing))
+
+	g.Expect(r.reconcileRevision(t.Context(), cd, true)).To(Succeed())
+	revs = revisions()
+	g.Expect(revs).To(HaveLen(1))
+	g.Expect(revs[0].Status.Outcome).To(Equal(kcm.RevisionOutcomeSucceeded))
+	g.Expect(revs[0].Status.CompletionTime).NotTo(BeNil())
+
+	// the change of the applied spec records a new revision
+	cd.Spec.Template = "aws-standalone-cp-0-2-0"
+	g.Expect(r.reconcileRevision(t.Context(), cd, false)).To(Succeed())
+	g.Expect(cd.Status.Revision).To(Equal(int64(2)))
+
+	cd.Spec.Template = "aws-standalone-cp-0-3-0"
+	cd.Status.Conditions = []metav1.Condition{{
+		Type: kcm.HelmReleaseReadyCondition, Status: metav1.ConditionFalse, Reason: "UpgradeFailed", Message: "upgrade failed",
+	}}
+	g.Expect(r.reconcileRevision(t.Context(), cd, false)).To(Succeed())
+	g.Expect(cd.Status.Revision).To(Equal(int64(3)))
+
+	// the oldest revision is pruned beyond the limit
+	revs = revisions()
+	g.Expect(revs).To(HaveLen(2))
+	g.Expect(revs[0].Name).To(Equal("cluster-2"))
+	g.Expect(revs[0].Status.Outcome).To(Equal(kcm.RevisionOutcomeSuperseded))
+	g.Expect(revs[1].Name).To(Equal("cluster-3"))
+	g.Expect(revs[1].Status.Outcome).To(Equal(kcm.RevisionOutcomeFailed))
+	g.Expect(revs[1].Status.Message).To(Equal("upgrade failed"))
+
+	// the template changes applied with the new revisions are audited
+	events := new(kcm.AuditEventList)
+	g.Expect(cl.List(t.Context(), events)).To(Succeed())
+	g.Expect(events.Items).To(HaveLen(2))
+	for _, event := range events.Items {
+		g.Expect(event.Spec.Action).To(Equal(kcm.AuditActionTemplateUpgrade))
+		g.Expect(event.Spec.Actor.Controller).To(Equal("clusterdeployment"))
+	}
+}
+
+func TestClusterDeploymentReconciler_reconcileRevisionStaleCache(t *testing.T) {
+	g := NewWithT(t)
+
+	cd := &kcm.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec:       kcm.ClusterDeploymentSpec{Template: "aws-standalone-cp-0-1-0", Credential: "aws-cred"},
+	}
+
+	apiReader := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.ClusterDeploymentRevision{}).
+		WithObjects(cd).Build()
+	// the cache has observed none of the recorded revisions
+	cached := interceptor.NewClient(apiReader, interceptor.Funcs{
+		List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+			return nil
+		},
+	})
+	r := &ClusterDeploymentReconciler{Client: cached, revisionReader: apiReader}
+
+	g.Expect(r.reconcileRevision(t.Context(), cd, false)).To(Succeed())
+	g.Expect(r.reconcileRevision(t.Context(), cd, false)).To(Succeed())
+	g.Expect(cd.Status.Revision).To(Equal(int64(1)))
+
+	revs, err := r.listRevisions(t.Context(), cd)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(revs).To(HaveLen(1))
+}
+
+func TestClusterDeploymentReconciler_rollback(t *testing.T) {
+	g := NewWithT(t)
+
+	cd := &kcm.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cluster",
+			Namespace:   "default",
+			Annotations: map[string]string{kcm.ClusterDeploymentRollbackAnnotation: "1"},
+		},
+		Spec: kcm.ClusterDeploymentSpec{Template: "aws-standalone-cp-0-2-0", Credential: "aws-cred"},
+	}
+	rev := &kcm.ClusterDeploymentRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "default"},
+		Spec: kcm.ClusterDeploymentRevisionSpec{
+			ClusterDeploymentName: "cluster",
+			Revision:              1,
+			Template:              "aws-standalone-cp-0-1-0",
+			Credential:            "aws-cred-old",
+			Config:                &apiextensionsv1.JSON{Raw: []byte(`{"region":"us-east-2"}`)},
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cd, rev).Build()
+	r := &ClusterDeploymentReconciler{Client: cl}
+
+	rolledBack, err := r.rollback(t.Context(), cd)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rolledBack).To(BeTrue())
+
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(cd), cd)).To(Succeed())
+	g.Expect(cd.Annotations).NotTo(HaveKey(kcm.ClusterDeploymentRollbackAnnotation))
+	g.Expect(cd.Spec.Template).To(Equal(rev.Spec.Template))
+	g.Expect(cd.Spec.Credential).To(Equal(rev.Spec.Credential))
+	g.Expect(cd.Spec.Config).To(Equal(rev.Spec.Config))
+
+	// the missing revision only drops the annotation
+	cd.Annotations = map[string]string{kcm.ClusterDeploymentRollbackAnnotation: "5"}
+	g.Expect(cl.Update(t.Context(), cd)).To(Succeed())
+
+	rolledBack, err = r.rollback(t.Context(), cd)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rolledBack).To(BeTrue())
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(cd), cd)).To(Succeed())
+	g.Expect(cd.Annotations).NotTo(HaveKey(kcm.ClusterDeploymentRollbackAnnotation))
+	g.Expect(cd.Spec.Template).To(Equal(rev.Spec.Template))
+}
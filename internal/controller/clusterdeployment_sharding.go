@@ -0,0 +1,77 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+const shardedControllerStartInterval = 10 * time.Second
+
+// ShardedClusterDeploymentController sets up the ClusterDeployment controller
+// of the shard of the replica on every replica, once the Sveltos provider is
+// ready, instead of the Management controller setting it up on the leader
+// only.
+type ShardedClusterDeploymentController struct {
+	Manager    ctrl.Manager
+	Reconciler *ClusterDeploymentReconciler
+}
+
+// NeedLeaderElection implements the [sigs.k8s.io/controller-runtime/pkg/manager.LeaderElectionRunnable].
+func (*ShardedClusterDeploymentController) NeedLeaderElection() bool {
+	return false
+}
+
+// Start waits for the Sveltos provider to be ready and sets up the controller.
+func (c *ShardedClusterDeploymentController) Start(ctx context.Context) error {
+	l := ctrl.LoggerFrom(ctx).WithName("sharded-clusterdeployment-controller")
+
+	reader := c.Manager.GetAPIReader()
+	err := wait.PollUntilContextCancel(ctx, shardedControllerStartInterval, true, func(ctx context.Context) (bool, error) {
+		management := new(kcm.Management)
+		if err := reader.Get(ctx, client.ObjectKey{Name: kcm.ManagementName}, management); err != nil {
+			l.V(1).Info("Failed to get Management, retrying", "err", err.Error())
+			return false, nil
+		}
+		return management.Status.Components[kcm.ProviderSveltosName].Success, nil
+	})
+	if err != nil {
+		// the manager is stopping
+		return nil //nolint:nilerr // the context is canceled only
+	}
+
+	if c.Reconciler.DynamicClient == nil {
+		dc, err := dynamic.NewForConfig(c.Manager.GetConfig())
+		if err != nil {
+			return fmt.Errorf("failed to create dynamic client: %w", err)
+		}
+		c.Reconciler.DynamicClient = dc
+	}
+
+	l.Info("Provider has been successfully installed, so setting up controller for ClusterDeployment", "shards", c.Reconciler.Sharder.Shards())
+	if err := c.Reconciler.SetupWithManager(c.Manager); err != nil {
+		return fmt.Errorf("failed to setup controller for ClusterDeployment: %w", err)
+	}
+	return nil
+}
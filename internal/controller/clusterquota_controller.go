@@ -0,0 +1,119 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/quota"
+	"github.com/K0rdent/kcm/internal/utils"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+// ClusterQuotaReconciler reconciles a ClusterQuota object
+type ClusterQuotaReconciler struct {
+	client.Client
+}
+
+func (r *ClusterQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+	l.Info("Reconciling ClusterQuota")
+
+	clusterQuota := new(kcm.ClusterQuota)
+	if err := r.Get(ctx, req.NamespacedName, clusterQuota); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if updated, err := utils.AddKCMComponentLabel(ctx, r.Client, clusterQuota); updated || err != nil {
+		if err != nil {
+			l.Error(err, "adding component label")
+		}
+		return ctrl.Result{}, err
+	}
+
+	clusterDeployments := new(kcm.ClusterDeploymentList)
+	if err := r.List(ctx, clusterDeployments, client.InNamespace(clusterQuota.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list ClusterDeployments in namespace %s: %w", clusterQuota.Namespace, err)
+	}
+
+	used, violations := quota.Evaluate(clusterQuota, clusterDeployments.Items)
+
+	exceededBy := make([]string, 0, len(violations))
+	for name := range violations {
+		exceededBy = append(exceededBy, name)
+	}
+	slices.Sort(exceededBy)
+
+	clusterQuota.Status.Used = used
+	clusterQuota.Status.ExceededBy = exceededBy
+	clusterQuota.Status.ObservedGeneration = clusterQuota.Generation
+
+	readyCondition := metav1.Condition{
+		Type:    kcm.ReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  kcm.SucceededReason,
+		Message: "All ClusterDeployments fit into the ClusterQuota",
+	}
+	if len(exceededBy) > 0 {
+		readyCondition.Status = metav1.ConditionFalse
+		readyCondition.Reason = kcm.ClusterQuotaExceededReason
+		readyCondition.Message = "ClusterQuota is exceeded by the ClusterDeployments: " + strings.Join(exceededBy, ", ")
+	}
+	apimeta.SetStatusCondition(clusterQuota.GetConditions(), readyCondition)
+
+	if err := r.Status().Update(ctx, clusterQuota); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update ClusterQuota %s status: %w", req.NamespacedName, err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&kcm.ClusterQuota{}).
+		Watches(&kcm.ClusterDeployment{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []ctrl.Request {
+				quotas := new(kcm.ClusterQuotaList)
+				if err := r.List(ctx, quotas, client.InNamespace(o.GetNamespace())); err != nil {
+					return nil
+				}
+
+				req := make([]ctrl.Request, 0, len(quotas.Items))
+				for _, q := range quotas.Items {
+					req = append(req, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&q)})
+				}
+
+				return req
+			}),
+		).
+		Complete(r)
+}
@@ -0,0 +1,157 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/clusterclient"
+	"github.com/K0rdent/kcm/internal/compliance"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+const (
+	defaultComplianceInterval = 24 * time.Hour
+	complianceScanPollPeriod  = time.Minute
+)
+
+// ComplianceReconciler runs the CIS benchmark scans on the clusters of the
+// ClusterDeployments with the compliance scanning enabled.
+type ComplianceReconciler struct {
+	client.Client
+
+	newClusterClient clusterclient.ClientsetFunc
+}
+
+func (r *ComplianceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+	l.Info("Reconciling ClusterDeployment compliance")
+
+	cd := new(kcm.ClusterDeployment)
+	if err := r.Get(ctx, req.NamespacedName, cd); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !cd.DeletionTimestamp.IsZero() || cd.Spec.DryRun {
+		return ctrl.Result{}, nil
+	}
+
+	if cd.Spec.Compliance == nil {
+		removed := apimeta.RemoveStatusCondition(cd.GetConditions(), kcm.CompliancePassedCondition)
+		if cd.Status.Compliance == nil && !removed {
+			return ctrl.Result{}, nil
+		}
+		cd.Status.Compliance = nil
+		return ctrl.Result{}, r.updateStatus(ctx, cd)
+	}
+
+	if !apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.ReadyCondition) {
+		l.V(1).Info("ClusterDeployment is not ready yet, postponing the compliance scan")
+		return ctrl.Result{RequeueAfter: complianceScanPollPeriod}, nil
+	}
+
+	interval := defaultComplianceInterval
+	if cd.Spec.Compliance.Interval != nil {
+		interval = cd.Spec.Compliance.Interval.Duration
+	}
+	if cd.Status.Compliance != nil && cd.Status.Compliance.LastScanTime != nil {
+		if next := cd.Status.Compliance.LastScanTime.Add(interval); time.Now().Before(next) {
+			return ctrl.Result{RequeueAfter: time.Until(next)}, nil
+		}
+	}
+
+	clusterClient, err := r.newClusterClient(ctx, client.ObjectKeyFromObject(cd))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get client of the cluster %s: %w", req.NamespacedName, err)
+	}
+
+	summary, err := (&compliance.Scanner{Client: clusterClient}).Scan(ctx, cd.Spec.Compliance)
+	if err != nil {
+		if !errors.Is(err, compliance.ErrScanFailed) {
+			return ctrl.Result{}, err
+		}
+		apimeta.SetStatusCondition(cd.GetConditions(), metav1.Condition{
+			Type:               kcm.CompliancePassedCondition,
+			Status:             metav1.ConditionUnknown,
+			Reason:             kcm.FailedReason,
+			Message:            err.Error(),
+			ObservedGeneration: cd.Generation,
+		})
+		return ctrl.Result{RequeueAfter: complianceScanPollPeriod}, r.updateStatus(ctx, cd)
+	}
+	if summary == nil {
+		return ctrl.Result{RequeueAfter: complianceScanPollPeriod}, nil
+	}
+
+	summary.LastScanTime = &metav1.Time{Time: time.Now()}
+	cd.Status.Compliance = summary
+	apimeta.SetStatusCondition(cd.GetConditions(), complianceCondition(cd.Generation, summary))
+
+	l.Info("Compliance scan completed", "passed", summary.Passed, "failed", summary.Failed, "warned", summary.Warned)
+
+	return ctrl.Result{RequeueAfter: interval}, r.updateStatus(ctx, cd)
+}
+
+func complianceCondition(generation int64, summary *kcm.ComplianceStatus) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               kcm.CompliancePassedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             kcm.SucceededReason,
+		Message:            fmt.Sprintf("%d checks passed, %d require manual verification", summary.Passed, summary.Warned),
+		ObservedGeneration: generation,
+	}
+	if summary.Failed > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = kcm.ComplianceChecksFailedReason
+		condition.Message = fmt.Sprintf("%d checks failed: %s", summary.Failed, strings.Join(summary.FailedChecks, ", "))
+	}
+	return condition
+}
+
+func (r *ComplianceReconciler) updateStatus(ctx context.Context, cd *kcm.ClusterDeployment) error {
+	if err := r.Status().Update(ctx, cd); err != nil {
+		return fmt.Errorf("failed to update status for clusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ComplianceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	if r.newClusterClient == nil {
+		r.newClusterClient = clusterclient.NewClientsetFunc(r.Client, "kcm-compliance")
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("compliance").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&kcm.ClusterDeployment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +30,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 
 	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/errclass"
+	"github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/internal/notifications"
 	"github.com/K0rdent/kcm/internal/utils"
 	"github.com/K0rdent/kcm/internal/utils/ratelimit"
 )
@@ -36,6 +40,7 @@ import (
 // CredentialReconciler reconciles a Credential object
 type CredentialReconciler struct {
 	client.Client
+	Notifier        *notifications.Notifier
 	SystemNamespace string
 	syncPeriod      time.Duration
 }
@@ -43,6 +48,7 @@ type CredentialReconciler struct {
 func (r *CredentialReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	l := ctrl.LoggerFrom(ctx)
 	l.Info("Credential reconcile start")
+	defer func() { trackReconcileError(ctx, kcm.CredentialKind, err) }()
 
 	management := &kcm.Management{}
 	if err := r.Get(ctx, client.ObjectKey{Name: kcm.ManagementName}, management); err != nil {
@@ -55,6 +61,9 @@ func (r *CredentialReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	cred := &kcm.Credential{}
 	if err := r.Client.Get(ctx, req.NamespacedName, cred); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.DeleteMetricCredentialExpiration(req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -69,6 +78,8 @@ func (r *CredentialReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		err = errors.Join(err, r.updateStatus(ctx, cred))
 	}()
 
+	r.updateExpiry(ctx, management, cred)
+
 	clIdty := &unstructured.Unstructured{}
 	clIdty.SetAPIVersion(cred.Spec.IdentityRef.APIVersion)
 	clIdty.SetKind(cred.Spec.IdentityRef.Kind)
@@ -84,18 +95,23 @@ func (r *CredentialReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		if apierrors.IsNotFound(err) {
 			errMsg = fmt.Sprintf("ClusterIdentity object of Kind=%s %s/%s not found",
 				cred.Spec.IdentityRef.Kind, cred.Spec.IdentityRef.Namespace, cred.Spec.IdentityRef.Name)
+			err = errclass.New(errclass.InvalidConfig, err)
 		}
 
 		apimeta.SetStatusCondition(cred.GetConditions(), metav1.Condition{
 			Type:    kcm.CredentialReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  kcm.FailedReason,
+			Reason:  errclass.Reason(err),
 			Message: errMsg,
 		})
 
 		return ctrl.Result{}, err
 	}
 
+	if err := r.labelIdentity(ctx, clIdty); err != nil {
+		l.Error(err, "failed to label ClusterIdentity to be backed up")
+	}
+
 	apimeta.SetStatusCondition(cred.GetConditions(), metav1.Condition{
 		Type:    kcm.CredentialReadyCondition,
 		Status:  metav1.ConditionTrue,
@@ -106,6 +122,73 @@ func (r *CredentialReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
 }
 
+// updateExpiry reports whether the credentials of the identity are about to
+// expire and sends the notification once they start expiring or expire.
+func (r *CredentialReconciler) updateExpiry(ctx context.Context, management *kcm.Management, cred *kcm.Credential) {
+	if cred.Spec.ExpirationTime == nil {
+		apimeta.RemoveStatusCondition(cred.GetConditions(), kcm.CredentialValidCondition)
+		metrics.DeleteMetricCredentialExpiration(cred.Namespace, cred.Name)
+		return
+	}
+	metrics.TrackMetricCredentialExpiration(ctx, cred.Namespace, cred.Name, cred.Spec.ExpirationTime.Time)
+
+	expiration := cred.Spec.ExpirationTime.UTC().Format(time.RFC3339)
+	condition := metav1.Condition{
+		Type:    kcm.CredentialValidCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  kcm.SucceededReason,
+		Message: "Credential expires at " + expiration,
+	}
+	switch remaining := time.Until(cred.Spec.ExpirationTime.Time); {
+	case remaining <= 0:
+		condition.Status, condition.Reason = metav1.ConditionFalse, kcm.CredentialExpiredReason
+		condition.Message = "Credential has expired at " + expiration
+	case remaining <= notifications.CredentialExpiryThreshold(management):
+		condition.Status, condition.Reason = metav1.ConditionFalse, kcm.CredentialExpiringReason
+	}
+
+	if previous := apimeta.FindStatusCondition(cred.Status.Conditions, kcm.CredentialValidCondition); condition.Status == metav1.ConditionFalse &&
+		(previous == nil || previous.Reason != condition.Reason) {
+		if err := r.Notifier.Notify(ctx, notifications.ObjectNotification(kcm.NotificationEventCredentialExpiring, kcm.CredentialKind, cred, condition.Message)); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to send notification")
+		}
+	}
+
+	apimeta.SetStatusCondition(cred.GetConditions(), condition)
+}
+
+// labelIdentity adds the kcm component label to the ClusterIdentity and the
+// Secrets it references, so they are included in the ManagementBackups and
+// the Credential is ready right after the restore onto another management
+// cluster. Only the Secrets of the system namespace are labeled, the others
+// are expected to be labeled by their owners.
+func (r *CredentialReconciler) labelIdentity(ctx context.Context, clIdty *unstructured.Unstructured) error {
+	if clIdty.GetKind() != "Secret" || clIdty.GetNamespace() == r.SystemNamespace {
+		if _, err := utils.AddKCMComponentLabel(ctx, r.Client, clIdty); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range identitySecrets(clIdty, r.SystemNamespace) {
+		if key.Namespace != r.SystemNamespace {
+			continue
+		}
+
+		secret := new(corev1.Secret)
+		if err := r.Client.Get(ctx, key, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get Secret %s of ClusterIdentity %s: %w", key, clIdty.GetName(), err)
+		}
+		if _, err := utils.AddKCMComponentLabel(ctx, r.Client, secret); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *CredentialReconciler) updateStatus(ctx context.Context, cred *kcm.Credential) error {
 	cred.Status.Ready = false
 	for _, cond := range cred.Status.Conditions {
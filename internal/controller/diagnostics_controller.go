@@ -0,0 +1,116 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/diagnostics"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+// maxDiagnosticFindings limits the number of the findings reported in the status.
+const maxDiagnosticFindings = 100
+
+// DiagnosticsReconciler runs the checks of the Diagnostics upon its creation,
+// change of its spec or request of the run with the annotation.
+type DiagnosticsReconciler struct {
+	client.Client
+
+	engine *diagnostics.Engine
+}
+
+func (r *DiagnosticsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+
+	diag := new(kcm.Diagnostics)
+	if err := r.Get(ctx, req.NamespacedName, diag); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !diag.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	runRequest := diag.Annotations[kcm.DiagnosticsRunAnnotation]
+	if diag.Status.LastRunTime != nil && diag.Status.Error == "" &&
+		diag.Status.ObservedGeneration == diag.Generation && diag.Status.LastHandledRunRequest == runRequest {
+		return ctrl.Result{}, nil
+	}
+
+	l.Info("Running diagnostics")
+
+	findings, runErr := r.engine.Run(ctx, diag.Spec)
+
+	patch := client.MergeFrom(diag.DeepCopy())
+	now := metav1.Now()
+	diag.Status = kcm.DiagnosticsStatus{
+		LastRunTime:           &now,
+		LastHandledRunRequest: runRequest,
+		ObservedGeneration:    diag.Generation,
+	}
+	if runErr != nil {
+		diag.Status.Error = runErr.Error()
+	}
+	for _, f := range findings {
+		switch f.Severity {
+		case kcm.DiagnosticSeverityError:
+			diag.Status.Errors++
+		case kcm.DiagnosticSeverityWarning:
+			diag.Status.Warnings++
+		}
+	}
+	if len(findings) > maxDiagnosticFindings {
+		findings = findings[:maxDiagnosticFindings]
+	}
+	diag.Status.Findings = findings
+
+	if err := r.Status().Patch(ctx, diag, patch); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update Diagnostics %s status: %w", diag.Name, err)
+	}
+	if runErr != nil {
+		// the checks are run again with the backoff until all of them succeed
+		return ctrl.Result{}, fmt.Errorf("failed to run diagnostics %s: %w", diag.Name, runErr)
+	}
+
+	l.Info("Diagnostics completed", "errors", diag.Status.Errors, "warnings", diag.Status.Warnings)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DiagnosticsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	if r.engine == nil {
+		// the checks are run on demand, the objects are read directly to avoid
+		// caching all of them
+		r.engine = &diagnostics.Engine{Client: mgr.GetAPIReader(), ProbeWebhook: diagnostics.TCPProbe}
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("diagnostics").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&kcm.Diagnostics{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Complete(r)
+}
@@ -0,0 +1,97 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/diagnostics"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestDiagnosticsReconciler_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	diag := &kcm.Diagnostics{
+		ObjectMeta: metav1.ObjectMeta{Name: "kcm", Generation: 1},
+		Spec:       kcm.DiagnosticsSpec{Checks: []kcm.DiagnosticCheck{kcm.DiagnosticCheckProviders}},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.Diagnostics{}).
+		WithObjects(diag).Build()
+
+	r := &DiagnosticsReconciler{Client: cl, engine: &diagnostics.Engine{Client: cl}}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(diag)}
+
+	_, err := r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, diag)).To(Succeed())
+	g.Expect(diag.Status.LastRunTime).NotTo(BeNil())
+	g.Expect(diag.Status.Errors).To(Equal(int32(1)))
+	g.Expect(diag.Status.Warnings).To(BeZero())
+	g.Expect(diag.Status.Findings).To(Equal([]kcm.DiagnosticFinding{{
+		Check: kcm.DiagnosticCheckProviders, Severity: kcm.DiagnosticSeverityError, Message: "Management kcm is not found",
+	}}))
+
+	// the checks are not run again until requested
+	g.Expect(cl.Create(t.Context(), &kcm.Management{ObjectMeta: metav1.ObjectMeta{Name: kcm.ManagementName}})).To(Succeed())
+
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, diag)).To(Succeed())
+	g.Expect(diag.Status.Errors).To(Equal(int32(1)))
+
+	diag.Annotations = map[string]string{kcm.DiagnosticsRunAnnotation: "1"}
+	g.Expect(cl.Update(t.Context(), diag)).To(Succeed())
+
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, diag)).To(Succeed())
+	g.Expect(diag.Status.LastHandledRunRequest).To(Equal("1"))
+	g.Expect(diag.Status.Errors).To(BeZero())
+	g.Expect(diag.Status.Findings).To(BeEmpty())
+
+	// the checks failed to be run are reported and run again
+	r.engine = &diagnostics.Engine{Client: interceptor.NewClient(cl, interceptor.Funcs{
+		Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+			return errors.New("unavailable")
+		},
+	})}
+	diag.Annotations[kcm.DiagnosticsRunAnnotation] = "2"
+	g.Expect(cl.Update(t.Context(), diag)).To(Succeed())
+
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, diag)).To(Succeed())
+	g.Expect(diag.Status.Error).To(Equal("failed to run the Providers check: failed to get Management kcm: unavailable"))
+
+	r.engine = &diagnostics.Engine{Client: cl}
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, diag)).To(Succeed())
+	g.Expect(diag.Status.Error).To(BeEmpty())
+}
@@ -0,0 +1,181 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/clusterclient"
+	"github.com/K0rdent/kcm/internal/etcdbackup"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+const (
+	// etcdBackupSyncPeriod is the period the state of the CronJob of the
+	// snapshots is reported in the status of the ClusterDeployment with.
+	etcdBackupSyncPeriod = 10 * time.Minute
+	// etcdBackupReadyPollPeriod is the period the readiness of the cluster
+	// the snapshots are postponed until is checked with.
+	etcdBackupReadyPollPeriod = time.Minute
+)
+
+// EtcdBackupReconciler schedules the snapshots of etcd on the clusters of the
+// ClusterDeployments with the etcd backups enabled and reports their state in
+// the status.
+type EtcdBackupReconciler struct {
+	client.Client
+
+	newClusterClient clusterclient.ClientsetFunc
+}
+
+func (r *EtcdBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+
+	cd := new(kcm.ClusterDeployment)
+	if err := r.Get(ctx, req.NamespacedName, cd); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// the snapshots of the deleted clusters are removed along with the cluster
+	if !cd.DeletionTimestamp.IsZero() || cd.Spec.DryRun || cd.Spec.EtcdBackup == nil && cd.Status.EtcdBackup == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.ReadyCondition) {
+		l.V(1).Info("ClusterDeployment is not ready yet, postponing the etcd snapshots")
+		return ctrl.Result{RequeueAfter: etcdBackupReadyPollPeriod}, nil
+	}
+
+	clusterClient, err := r.newClusterClient(ctx, req.NamespacedName)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get client of the cluster %s: %w", req.NamespacedName, err)
+	}
+	scheduler := &etcdbackup.Scheduler{Client: clusterClient}
+
+	if cd.Spec.EtcdBackup == nil {
+		if err := scheduler.Remove(ctx); err != nil {
+			return ctrl.Result{}, err
+		}
+		l.Info("Removed etcd snapshots schedule")
+		return ctrl.Result{}, r.updateStatus(ctx, cd, nil)
+	}
+
+	status, err := r.schedule(ctx, cd, scheduler)
+	if err != nil {
+		status.Error = err.Error()
+	}
+	if statusErr := r.updateStatus(ctx, cd, status); statusErr != nil {
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+	if err != nil {
+		if errors.Is(err, etcdbackup.ErrUnsupportedControlPlane) || apierrors.IsNotFound(err) {
+			// fixed either by the change of the ClusterDeployment or by the
+			// creation of the missing objects not watched for
+			l.Info("Failed to schedule etcd snapshots", "error", err.Error())
+			return ctrl.Result{RequeueAfter: etcdBackupSyncPeriod}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	l.V(1).Info("Synced etcd snapshots schedule")
+	return ctrl.Result{RequeueAfter: etcdBackupSyncPeriod}, nil
+}
+
+// schedule syncs the CronJob of the snapshots in the cluster and returns the
+// status of the snapshots.
+func (r *EtcdBackupReconciler) schedule(ctx context.Context, cd *kcm.ClusterDeployment, scheduler *etcdbackup.Scheduler) (*kcm.EtcdBackupStatus, error) {
+	status := new(kcm.EtcdBackupStatus)
+	if cd.Status.EtcdBackup != nil {
+		status = cd.Status.EtcdBackup.DeepCopy()
+	}
+	status.Error = ""
+
+	controlPlaneKind, err := r.controlPlaneKind(ctx, client.ObjectKeyFromObject(cd))
+	if err != nil {
+		return status, err
+	}
+
+	cronJob, err := etcdbackup.CronJob(cd.Spec.EtcdBackup, controlPlaneKind, cd.Namespace+"/"+cd.Name)
+	if err != nil {
+		return status, err
+	}
+
+	secret := new(corev1.Secret)
+	secretKey := client.ObjectKey{Namespace: cd.Namespace, Name: cd.Spec.EtcdBackup.Storage.CredentialsSecret}
+	if err := r.Get(ctx, secretKey, secret); err != nil {
+		return status, fmt.Errorf("failed to get Secret %s with the credentials of the storage: %w", secretKey, err)
+	}
+
+	cronJob, err = scheduler.Schedule(ctx, cronJob, secret.Data)
+	if err != nil {
+		return status, err
+	}
+
+	status.LastScheduleTime = cronJob.Status.LastScheduleTime
+	status.LastSuccessfulTime = cronJob.Status.LastSuccessfulTime
+	return status, nil
+}
+
+// controlPlaneKind returns the kind of the control plane of the CAPI Cluster.
+func (r *EtcdBackupReconciler) controlPlaneKind(ctx context.Context, key client.ObjectKey) (string, error) {
+	cluster := new(unstructured.Unstructured)
+	cluster.SetGroupVersionKind(clusterapiv1beta1.GroupVersion.WithKind(clusterapiv1beta1.ClusterKind))
+	if err := r.Get(ctx, key, cluster); err != nil {
+		return "", fmt.Errorf("failed to get Cluster %s: %w", key, err)
+	}
+
+	kind, _, _ := unstructured.NestedString(cluster.Object, "spec", "controlPlaneRef", "kind")
+	return kind, nil
+}
+
+func (r *EtcdBackupReconciler) updateStatus(ctx context.Context, cd *kcm.ClusterDeployment, status *kcm.EtcdBackupStatus) error {
+	base := cd.DeepCopy()
+	cd.Status.EtcdBackup = status
+	if err := patchStatus(ctx, r.Client, cd, base); err != nil {
+		return fmt.Errorf("failed to update status for clusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EtcdBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	if r.newClusterClient == nil {
+		r.newClusterClient = clusterclient.NewClientsetFunc(r.Client, "kcm-etcd-backup")
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("etcdbackup").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&kcm.ClusterDeployment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
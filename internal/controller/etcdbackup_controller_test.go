@@ -0,0 +1,113 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/etcdbackup"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestEtcdBackupReconciler_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	cd := &kcm.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cd", Namespace: "team-a"},
+		Spec: kcm.ClusterDeploymentSpec{
+			Template: "aws-0-1-0",
+			EtcdBackup: &kcm.EtcdBackupSpec{
+				Schedule: "@daily",
+				Storage:  kcm.EtcdBackupStorage{Bucket: "snapshots", CredentialsSecret: "s3"},
+			},
+		},
+		Status: kcm.ClusterDeploymentStatus{
+			Conditions: []metav1.Condition{{Type: kcm.ReadyCondition, Status: metav1.ConditionTrue}},
+		},
+	}
+	cluster := &clusterapiv1beta1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cd", Namespace: "team-a"},
+		Spec: clusterapiv1beta1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{Kind: "K0sControlPlane", Name: "cd-cp"},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3", Namespace: "team-a"},
+		Data:       map[string][]byte{"AWS_ACCESS_KEY_ID": []byte("id"), "AWS_SECRET_ACCESS_KEY": []byte("secret")},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.ClusterDeployment{}).
+		WithObjects(cd, cluster).Build()
+	clientset := kubefake.NewClientset()
+
+	r := &EtcdBackupReconciler{
+		Client: cl,
+		newClusterClient: func(context.Context, client.ObjectKey) (kubernetes.Interface, error) {
+			return clientset, nil
+		},
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cd)}
+
+	// the missing secret is reported in the status
+	res, err := r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(etcdBackupSyncPeriod))
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, cd)).To(Succeed())
+	g.Expect(cd.Status.EtcdBackup).NotTo(BeNil())
+	g.Expect(cd.Status.EtcdBackup.Error).To(ContainSubstring("credentials of the storage"))
+
+	g.Expect(cl.Create(t.Context(), secret)).To(Succeed())
+
+	res, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(etcdBackupSyncPeriod))
+
+	cronJob, err := clientset.BatchV1().CronJobs(etcdbackup.Namespace).Get(t.Context(), etcdbackup.CronJobName, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cronJob.Spec.Schedule).To(Equal("@daily"))
+	g.Expect(cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "PREFIX", Value: "team-a/cd"}))
+	copied, err := clientset.CoreV1().Secrets(etcdbackup.Namespace).Get(t.Context(), etcdbackup.CredentialsSecretName, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(copied.Data).To(Equal(secret.Data))
+
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, cd)).To(Succeed())
+	g.Expect(cd.Status.EtcdBackup).To(Equal(&kcm.EtcdBackupStatus{}))
+
+	// the schedule is removed along with the spec
+	cd.Spec.EtcdBackup = nil
+	g.Expect(cl.Update(t.Context(), cd)).To(Succeed())
+
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = clientset.CoreV1().Namespaces().Get(t.Context(), etcdbackup.Namespace, metav1.GetOptions{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, cd)).To(Succeed())
+	g.Expect(cd.Status.EtcdBackup).To(BeNil())
+}
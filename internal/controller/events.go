@@ -0,0 +1,127 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/errclass"
+	"github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/internal/record"
+)
+
+// The reasons of the events of the lifecycle transitions.
+const (
+	eventReasonTemplateResolved   = "TemplateResolved"
+	eventReasonTemplateNotReady   = "TemplateNotReady"
+	eventReasonHelmInstallStarted = "HelmInstallStarted"
+	eventReasonHelmReleaseReady   = "HelmReleaseReady"
+	eventReasonHelmReleaseFailed  = "HelmReleaseFailed"
+	eventReasonInfrastructure     = "InfrastructureReady"
+	eventReasonControlPlaneReady  = "ControlPlaneReady"
+	eventReasonServicesDeployed   = "ServicesDeployed"
+	eventReasonServicesFailed     = "ServicesFailed"
+	eventReasonClustersReady      = "ClustersReady"
+	eventReasonProvisioned        = "Provisioned"
+	eventReasonUpgradeStarted     = "UpgradeStarted"
+	eventReasonUpgradeSucceeded   = "UpgradeSucceeded"
+	eventReasonRollbackStarted    = "RollbackStarted"
+	eventReasonRollbackFailed     = "RollbackFailed"
+	eventReasonRestored           = "Restored"
+	eventReasonRestoreFailed      = "RestoreFailed"
+	eventReasonComponentInstalled = "ComponentInstalled"
+	eventReasonComponentFailed    = "ComponentFailed"
+	eventReasonReady              = "Ready"
+	eventReasonNotReady           = "NotReady"
+	eventReasonReachable          = "Reachable"
+	eventReasonUnreachable        = "Unreachable"
+)
+
+// conditionEvents are the reasons of the events emitted once the condition
+// turns true or false, no warning is emitted if the failed reason is empty.
+type conditionEvents struct {
+	succeeded string
+	failed    string
+	// regressionOnly emits the warning only once the condition turns false
+	// after being true, e.g. for the conditions false until provisioned.
+	regressionOnly bool
+}
+
+var clusterDeploymentConditionEvents = map[string]conditionEvents{
+	kcm.TemplateReadyCondition:        {succeeded: eventReasonTemplateResolved, failed: eventReasonTemplateNotReady},
+	kcm.HelmReleaseReadyCondition:     {succeeded: eventReasonHelmReleaseReady, failed: eventReasonHelmReleaseFailed},
+	"InfrastructureReady":             {succeeded: eventReasonInfrastructure},
+	"ControlPlaneReady":               {succeeded: eventReasonControlPlaneReady},
+	kcm.SveltosProfileReadyCondition:  {failed: eventReasonServicesFailed},
+	kcm.ServicesInReadyStateCondition: {succeeded: eventReasonServicesDeployed},
+	kcm.ReadyCondition:                {succeeded: eventReasonReady, failed: eventReasonNotReady, regressionOnly: true},
+}
+
+var clusterHealthConditionEvents = map[string]conditionEvents{
+	kcm.ReachableCondition: {succeeded: eventReasonReachable, failed: eventReasonUnreachable, regressionOnly: true},
+}
+
+var multiClusterServiceConditionEvents = map[string]conditionEvents{
+	kcm.SveltosClusterProfileReadyCondition: {failed: eventReasonServicesFailed},
+	kcm.ClusterInReadyStateCondition:        {succeeded: eventReasonClustersReady},
+	kcm.ServicesInReadyStateCondition:       {succeeded: eventReasonServicesDeployed},
+	kcm.ReadyCondition:                      {succeeded: eventReasonReady, failed: eventReasonNotReady, regressionOnly: true},
+}
+
+var managementConditionEvents = map[string]conditionEvents{
+	kcm.ReadyCondition: {succeeded: eventReasonReady, failed: eventReasonNotReady, regressionOnly: true},
+}
+
+// recordConditionTransitions emits the events of the conditions of the object
+// changed their statuses since the previous ones. The condition turning false
+// is only reported once it has failed, and not while it is progressing.
+func recordConditionTransitions(obj runtime.Object, previous, current []metav1.Condition, events map[string]conditionEvents) {
+	for _, c := range current {
+		reasons, ok := events[c.Type]
+		if !ok {
+			continue
+		}
+
+		prev := apimeta.FindStatusCondition(previous, c.Type)
+		if prev != nil && prev.Status == c.Status {
+			continue
+		}
+
+		switch {
+		case c.Status == metav1.ConditionTrue && reasons.succeeded != "":
+			record.Event(obj, reasons.succeeded, c.Message)
+		case c.Status == metav1.ConditionFalse && reasons.failed != "" && prev != nil && c.Reason != kcm.ProgressingReason:
+			if reasons.regressionOnly && prev.Status != metav1.ConditionTrue {
+				continue
+			}
+			record.Warn(obj, reasons.failed, c.Message)
+		}
+	}
+}
+
+// trackReconcileError counts the failed reconciliation of the object of the
+// given kind by the class of the error.
+func trackReconcileError(ctx context.Context, kind string, err error) {
+	if err == nil {
+		return
+	}
+	class := errclass.Of(err)
+	metrics.TrackMetricReconcileError(ctx, kind, string(class), class.Retryable())
+}
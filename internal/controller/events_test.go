@@ -0,0 +1,89 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	kcmrecord "github.com/K0rdent/kcm/internal/record"
+)
+
+func Test_recordConditionTransitions(t *testing.T) {
+	condition := func(typ string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+		return metav1.Condition{Type: typ, Status: status, Reason: reason, Message: message}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		previous []metav1.Condition
+		current  []metav1.Condition
+		expected []string
+	}{
+		{
+			name:     "turned true",
+			previous: []metav1.Condition{condition(kcm.TemplateReadyCondition, metav1.ConditionUnknown, kcm.ProgressingReason, "")},
+			current:  []metav1.Condition{condition(kcm.TemplateReadyCondition, metav1.ConditionTrue, kcm.SucceededReason, "Template is valid")},
+			expected: []string{"Normal TemplateResolved Template is valid map[k0rdent.mirantis.com/correlation-id:test-1]"},
+		},
+		{
+			name:     "unchanged",
+			previous: []metav1.Condition{condition(kcm.TemplateReadyCondition, metav1.ConditionTrue, kcm.SucceededReason, "")},
+			current:  []metav1.Condition{condition(kcm.TemplateReadyCondition, metav1.ConditionTrue, kcm.SucceededReason, "Template is valid")},
+		},
+		{
+			name:     "failed",
+			previous: []metav1.Condition{condition(kcm.HelmReleaseReadyCondition, metav1.ConditionUnknown, kcm.ProgressingReason, "")},
+			current:  []metav1.Condition{condition(kcm.HelmReleaseReadyCondition, metav1.ConditionFalse, "InstallFailed", "install failed")},
+			expected: []string{"Warning HelmReleaseFailed install failed map[k0rdent.mirantis.com/correlation-id:test-1]"},
+		},
+		{
+			name:    "initially failed",
+			current: []metav1.Condition{condition(kcm.HelmReleaseReadyCondition, metav1.ConditionFalse, "InstallFailed", "install failed")},
+		},
+		{
+			name:     "regression",
+			previous: []metav1.Condition{condition(kcm.ReadyCondition, metav1.ConditionTrue, kcm.SucceededReason, "")},
+			current:  []metav1.Condition{condition(kcm.ReadyCondition, metav1.ConditionFalse, kcm.FailedReason, "cluster is broken")},
+			expected: []string{"Warning NotReady cluster is broken map[k0rdent.mirantis.com/correlation-id:test-1]"},
+		},
+		{
+			name:     "not ready while provisioning",
+			previous: []metav1.Condition{condition(kcm.ReadyCondition, metav1.ConditionUnknown, kcm.ProgressingReason, "")},
+			current:  []metav1.Condition{condition(kcm.ReadyCondition, metav1.ConditionFalse, kcm.FailedReason, "infrastructure is not ready")},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			recorder := record.NewFakeRecorder(10)
+			kcmrecord.InitFromRecorder(recorder)
+			t.Cleanup(func() { kcmrecord.InitFromRecorder(new(record.FakeRecorder)) })
+
+			recordConditionTransitions(&kcm.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{UID: "test", Generation: 1}}, tc.previous, tc.current, clusterDeploymentConditionEvents)
+			close(recorder.Events)
+
+			var actual []string
+			for e := range recorder.Events {
+				actual = append(actual, e)
+			}
+			g.Expect(actual).To(Equal(tc.expected))
+		})
+	}
+}
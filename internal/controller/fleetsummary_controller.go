@@ -0,0 +1,169 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/internal/providers"
+	"github.com/K0rdent/kcm/internal/sveltos"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+// FleetSummaryReconciler rolls up the state of the ClusterDeployments of
+// all of the namespaces into the status of the Management.
+type FleetSummaryReconciler struct {
+	client.Client
+}
+
+func (r *FleetSummaryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	mgmt := new(kcm.Management)
+	if err := r.Get(ctx, req.NamespacedName, mgmt); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !mgmt.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	clusterDeployments := new(kcm.ClusterDeploymentList)
+	if err := r.List(ctx, clusterDeployments); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list ClusterDeployments: %w", err)
+	}
+	templates := new(kcm.ClusterTemplateList)
+	if err := r.List(ctx, templates); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list ClusterTemplates: %w", err)
+	}
+
+	summary := fleetSummary(clusterDeployments.Items, templates.Items)
+	if equality.Semantic.DeepEqual(mgmt.Status.Fleet, summary) {
+		return ctrl.Result{}, nil
+	}
+
+	patch := client.MergeFrom(mgmt.DeepCopy())
+	mgmt.Status.Fleet = summary
+	if err := r.Status().Patch(ctx, mgmt, patch); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update fleet summary in the Management %s status: %w", mgmt.Name, err)
+	}
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Updated fleet summary", "clusters", summary.Clusters, "servicesPending", summary.ServicesPending)
+	return ctrl.Result{}, nil
+}
+
+// fleetSummary rolls up the state of the given ClusterDeployments, the
+// providers of the clusters are taken from the given ClusterTemplates.
+func fleetSummary(clusterDeployments []kcm.ClusterDeployment, templates []kcm.ClusterTemplate) *kcm.FleetSummary {
+	templateProviders := make(map[client.ObjectKey][]string, len(templates))
+	for _, t := range templates {
+		for _, p := range t.Status.Providers {
+			if strings.HasPrefix(p, providers.InfraPrefix) {
+				key := client.ObjectKeyFromObject(&t)
+				templateProviders[key] = append(templateProviders[key], strings.TrimPrefix(p, providers.InfraPrefix))
+			}
+		}
+	}
+
+	summary := &kcm.FleetSummary{
+		ClustersByProvider: make(map[string]int32),
+		ClustersByTemplate: make(map[string]int32),
+		ClustersByPhase:    make(map[string]int32),
+	}
+	for _, cd := range clusterDeployments {
+		summary.Clusters++
+
+		template := cd.Status.AppliedTemplate
+		if template == "" {
+			template = cd.Spec.Template
+		}
+		summary.ClustersByTemplate[template]++
+		for _, p := range templateProviders[client.ObjectKey{Namespace: cd.Namespace, Name: template}] {
+			summary.ClustersByProvider[p]++
+		}
+
+		phase := clusterDeploymentPhase(&cd)
+		summary.ClustersByPhase[phase]++
+		if phase == metrics.ClusterDeploymentPhaseUpgrading {
+			summary.UpgradesInProgress++
+		}
+		if apimeta.IsStatusConditionFalse(cd.Status.Conditions, kcm.ReachableCondition) {
+			summary.UnreachableClusters++
+		}
+
+		summary.Services += int32(len(cd.Spec.ServiceSpec.Services)) //nolint:gosec // the number of the services does not exceed int32
+		summary.ServicesPending += pendingServicesCount(&cd)
+	}
+
+	return summary
+}
+
+// pendingServicesCount returns the number of the services of the
+// ClusterDeployment the Helm releases of which are not ready yet.
+func pendingServicesCount(cd *kcm.ClusterDeployment) int32 {
+	if apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.ServicesInReadyStateCondition) {
+		return 0
+	}
+
+	var pending int32
+	for _, svc := range cd.Spec.ServiceSpec.Services {
+		namespace := svc.Namespace
+		if namespace == "" {
+			namespace = svc.Name
+		}
+		conditionType := sveltos.HelmReleaseReadyConditionType(namespace, svc.Name)
+
+		ready := false
+		for _, status := range cd.Status.Services {
+			if apimeta.IsStatusConditionTrue(status.Conditions, conditionType) {
+				ready = true
+				break
+			}
+		}
+		if !ready {
+			pending++
+		}
+	}
+	return pending
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *FleetSummaryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+
+	enqueueManagement := handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []ctrl.Request {
+		return []ctrl.Request{{NamespacedName: client.ObjectKey{Name: kcm.ManagementName}}}
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("fleetsummary").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&kcm.Management{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&kcm.ClusterDeployment{}, enqueueManagement).
+		Watches(&kcm.ClusterTemplate{}, enqueueManagement).
+		Complete(r)
+}
@@ -0,0 +1,103 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestFleetSummaryReconciler_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	const namespace = "kcm-system"
+
+	newTemplate := func(name string, providers ...string) *kcm.ClusterTemplate {
+		return &kcm.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     kcm.ClusterTemplateStatus{Providers: providers},
+		}
+	}
+	newClusterDeployment := func(name, template, appliedTemplate string, conditions ...metav1.Condition) *kcm.ClusterDeployment {
+		return &kcm.ClusterDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       kcm.ClusterDeploymentSpec{Template: template},
+			Status:     kcm.ClusterDeploymentStatus{AppliedTemplate: appliedTemplate, Conditions: conditions},
+		}
+	}
+	ready := metav1.Condition{Type: kcm.ReadyCondition, Status: metav1.ConditionTrue}
+	unreachable := metav1.Condition{Type: kcm.ReachableCondition, Status: metav1.ConditionFalse}
+
+	withServices := newClusterDeployment("with-services", "aws-0-1-0", "aws-0-1-0", ready)
+	withServices.Spec.ServiceSpec.Services = []kcm.Service{
+		{Name: "ingress-nginx", Template: "ingress-nginx-4-12-1"},
+		{Name: "cert-manager", Namespace: "certs", Template: "cert-manager-1-17-1"},
+	}
+	withServices.Status.Services = []kcm.ServiceStatus{{
+		ClusterName:      withServices.Name,
+		ClusterNamespace: namespace,
+		Conditions: []metav1.Condition{
+			{Type: "ingress-nginx.ingress-nginx/" + kcm.SveltosHelmReleaseReadyCondition, Status: metav1.ConditionTrue},
+			{Type: "certs.cert-manager/" + kcm.SveltosHelmReleaseReadyCondition, Status: metav1.ConditionFalse},
+		},
+	}}
+
+	mgmt := &kcm.Management{ObjectMeta: metav1.ObjectMeta{Name: kcm.ManagementName}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.Management{}).
+		WithObjects(
+			mgmt,
+			newTemplate("aws-0-1-0", "infrastructure-aws", "control-plane-k0sproject-k0smotron"),
+			newTemplate("aws-0-2-0", "infrastructure-aws", "control-plane-k0sproject-k0smotron"),
+			newTemplate("azure-0-1-0", "infrastructure-azure"),
+			withServices,
+			newClusterDeployment("upgrading", "aws-0-2-0", "aws-0-1-0", ready),
+			newClusterDeployment("unreachable", "azure-0-1-0", "azure-0-1-0", ready, unreachable),
+			newClusterDeployment("provisioning", "azure-0-1-0", ""),
+		).Build()
+
+	r := &FleetSummaryReconciler{Client: cl}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mgmt)}
+
+	_, err := r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, mgmt)).To(Succeed())
+	g.Expect(mgmt.Status.Fleet).To(Equal(&kcm.FleetSummary{
+		ClustersByProvider:  map[string]int32{"aws": 2, "azure": 2},
+		ClustersByTemplate:  map[string]int32{"aws-0-1-0": 2, "azure-0-1-0": 2},
+		ClustersByPhase:     map[string]int32{"Ready": 2, "Upgrading": 1, "Provisioning": 1},
+		Clusters:            4,
+		UnreachableClusters: 1,
+		UpgradesInProgress:  1,
+		Services:            2,
+		ServicesPending:     1,
+	}))
+
+	// the summary is not patched again if unchanged
+	resourceVersion := mgmt.ResourceVersion
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cl.Get(t.Context(), req.NamespacedName, mgmt)).To(Succeed())
+	g.Expect(mgmt.ResourceVersion).To(Equal(resourceVersion))
+}
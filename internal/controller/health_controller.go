@@ -0,0 +1,139 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/health"
+	"github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+// heartbeatRefreshPeriod is the period the last heartbeat of the reachable
+// cluster is refreshed with. The status is patched otherwise only once the
+// Reachable condition changes, not on every probe.
+const heartbeatRefreshPeriod = 10 * time.Minute
+
+// HealthReconciler periodically probes the API servers of the clusters of
+// the ClusterDeployments, recording the last heartbeat and the Reachable
+// condition, so the clusters that are gone are noticed.
+type HealthReconciler struct {
+	client.Client
+
+	// probe checks the API server of the cluster.
+	probe func(ctx context.Context, cluster client.ObjectKey) (health.Result, error)
+
+	// ProbeInterval is the interval between the probes of each cluster.
+	ProbeInterval time.Duration
+}
+
+func (r *HealthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+	l.V(1).Info("Probing ClusterDeployment health")
+
+	cd := new(kcm.ClusterDeployment)
+	if err := r.Get(ctx, req.NamespacedName, cd); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			metrics.DeleteMetricClusterDeploymentReachable(req.Namespace, req.Name)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !cd.DeletionTimestamp.IsZero() || cd.Spec.DryRun {
+		metrics.DeleteMetricClusterDeploymentReachable(cd.Namespace, cd.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// the cluster is not expected to be reachable until it is provisioned
+	if cd.Status.LastHeartbeat == nil && !apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.ReadyCondition) {
+		l.V(1).Info("ClusterDeployment is not ready yet, postponing the health probe")
+		return ctrl.Result{RequeueAfter: r.ProbeInterval}, nil
+	}
+
+	result, err := r.probe(ctx, client.ObjectKeyFromObject(cd))
+
+	base := cd.DeepCopy()
+	previousConditions := slices.Clone(cd.Status.Conditions)
+	condition := metav1.Condition{
+		Type:               kcm.ReachableCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             kcm.SucceededReason,
+		Message:            "API server is reachable and ready",
+		ObservedGeneration: cd.Generation,
+	}
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = kcm.UnreachableReason
+		condition.Message = err.Error()
+	case !result.Healthy():
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = kcm.ComponentsUnhealthyReason
+		condition.Message = "Readiness checks of the API server failed: " + strings.Join(result.FailedChecks, ", ")
+	}
+	if condition.Status == metav1.ConditionFalse && cd.Status.LastHeartbeat != nil {
+		condition.Message += ", last heartbeat at " + cd.Status.LastHeartbeat.UTC().Format(time.RFC3339)
+	}
+	changed := apimeta.SetStatusCondition(cd.GetConditions(), condition)
+	if condition.Status == metav1.ConditionTrue && (changed || cd.Status.LastHeartbeat == nil ||
+		time.Since(cd.Status.LastHeartbeat.Time) >= heartbeatRefreshPeriod) {
+		cd.Status.LastHeartbeat = &metav1.Time{Time: time.Now()}
+	}
+	metrics.TrackMetricClusterDeploymentReachable(ctx, cd.Namespace, cd.Name, condition.Status == metav1.ConditionTrue)
+
+	if err := patchStatus(ctx, r.Client, cd, base); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status for clusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+	recordConditionTransitions(cd, previousConditions, cd.Status.Conditions, clusterHealthConditionEvents)
+
+	return ctrl.Result{RequeueAfter: r.ProbeInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HealthReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	if r.probe == nil {
+		r.probe = func(ctx context.Context, cluster client.ObjectKey) (health.Result, error) {
+			restConfig, err := remote.RESTConfig(ctx, "kcm-health", r.Client, cluster)
+			if err != nil {
+				return health.Result{}, err
+			}
+			return health.Probe(ctx, restConfig)
+		}
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("health").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&kcm.ClusterDeployment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
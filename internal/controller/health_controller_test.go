@@ -0,0 +1,119 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/health"
+	"github.com/K0rdent/kcm/test/objects/clusterdeployment"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestHealthReconciler_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	cd := clusterdeployment.NewClusterDeployment()
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.ClusterDeployment{}).
+		WithObjects(cd).Build()
+
+	var (
+		result   health.Result
+		probeErr error
+		probed   bool
+	)
+	r := &HealthReconciler{
+		Client:        cl,
+		ProbeInterval: time.Minute,
+		probe: func(context.Context, client.ObjectKey) (health.Result, error) {
+			probed = true
+			return result, probeErr
+		},
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cd)}
+
+	reachable := func() *metav1.Condition {
+		g.Expect(cl.Get(t.Context(), req.NamespacedName, cd)).To(Succeed())
+		return apimeta.FindStatusCondition(cd.Status.Conditions, kcm.ReachableCondition)
+	}
+
+	// the cluster not provisioned yet is not probed
+	res, err := r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(time.Minute))
+	g.Expect(probed).To(BeFalse())
+	g.Expect(reachable()).To(BeNil())
+
+	apimeta.SetStatusCondition(&cd.Status.Conditions, metav1.Condition{Type: kcm.ReadyCondition, Status: metav1.ConditionTrue, Reason: kcm.SucceededReason})
+	g.Expect(cl.Status().Update(t.Context(), cd)).To(Succeed())
+
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(probed).To(BeTrue())
+	condition := reachable()
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(cd.Status.LastHeartbeat).NotTo(BeNil())
+	heartbeat := cd.Status.LastHeartbeat.DeepCopy()
+
+	// the status is not patched while the condition is unchanged
+	resourceVersion := cd.ResourceVersion
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reachable().Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(cd.ResourceVersion).To(Equal(resourceVersion))
+
+	// the stale heartbeat is refreshed
+	cd.Status.LastHeartbeat = &metav1.Time{Time: time.Now().Add(-heartbeatRefreshPeriod)}
+	g.Expect(cl.Status().Update(t.Context(), cd)).To(Succeed())
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reachable().Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(time.Since(cd.Status.LastHeartbeat.Time)).To(BeNumerically("<", heartbeatRefreshPeriod))
+	heartbeat = cd.Status.LastHeartbeat.DeepCopy()
+
+	result = health.Result{FailedChecks: []string{"etcd"}}
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	condition = reachable()
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal(kcm.ComponentsUnhealthyReason))
+	g.Expect(condition.Message).To(HavePrefix("Readiness checks of the API server failed: etcd, last heartbeat at "))
+	g.Expect(cd.Status.LastHeartbeat.Equal(heartbeat)).To(BeTrue())
+
+	// the cluster once seen is probed regardless of its readiness
+	apimeta.SetStatusCondition(&cd.Status.Conditions, metav1.Condition{Type: kcm.ReadyCondition, Status: metav1.ConditionFalse, Reason: kcm.FailedReason})
+	g.Expect(cl.Status().Update(t.Context(), cd)).To(Succeed())
+
+	probeErr = errors.New("connection refused")
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+	condition = reachable()
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal(kcm.UnreachableReason))
+	g.Expect(condition.Message).To(HavePrefix("connection refused, last heartbeat at "))
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -27,6 +28,8 @@ import (
 
 	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
 	"github.com/K0rdent/kcm/internal/controller/backup"
+	"github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/internal/notifications"
 	"github.com/K0rdent/kcm/internal/utils/ratelimit"
 )
 
@@ -36,6 +39,7 @@ type ManagementBackupReconciler struct {
 
 	internal *backup.Reconciler
 
+	Notifier        *notifications.Notifier
 	SystemNamespace string
 }
 
@@ -54,6 +58,9 @@ func (r *ManagementBackupReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 	mgmtBackup := new(kcmv1alpha1.ManagementBackup)
 	if err := r.Client.Get(ctx, req.NamespacedName, mgmtBackup); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.DeleteMetricsManagementBackup(req.Name)
+		}
 		l.Error(err, "unable to fetch ManagementBackup")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
@@ -76,7 +83,7 @@ func (r *ManagementBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return fmt.Errorf("unable to add periodic runner: %w", err)
 	}
 
-	r.internal = backup.NewReconciler(r.Client, r.SystemNamespace)
+	r.internal = backup.NewReconciler(r.Client, r.SystemNamespace, backup.WithNotifier(r.Notifier))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.TypedOptions[ctrl.Request]{
@@ -15,29 +15,47 @@
 package controller
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	fluxv2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/pkg/apis/kustomize"
 	fluxmeta "github.com/fluxcd/pkg/apis/meta"
 	fluxconditions "github.com/fluxcd/pkg/runtime/conditions"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	sveltosv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chartutil"
 	helmreleasepkg "helm.sh/helm/v3/pkg/release"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	capioperatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
@@ -49,12 +67,39 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/audit"
 	"github.com/K0rdent/kcm/internal/certmanager"
+	"github.com/K0rdent/kcm/internal/hardening"
 	"github.com/K0rdent/kcm/internal/helm"
+	"github.com/K0rdent/kcm/internal/imageverify"
+	"github.com/K0rdent/kcm/internal/notifications"
+	"github.com/K0rdent/kcm/internal/preflight"
+	"github.com/K0rdent/kcm/internal/record"
+	"github.com/K0rdent/kcm/internal/tracing"
 	"github.com/K0rdent/kcm/internal/utils"
 	"github.com/K0rdent/kcm/internal/utils/ratelimit"
 )
 
+// preflightRetryPeriod is the period the preflight checks of the postponed upgrade are rerun with.
+const preflightRetryPeriod = time.Minute
+
+// managementNetworkPolicyName is the name of the NetworkPolicies of the Management components.
+const managementNetworkPolicyName = "kcm-management-components"
+
+const (
+	// observabilityServiceName is the name of the MultiClusterService, of
+	// the release and of the namespace of the collector.
+	observabilityServiceName = "kcm-observability"
+	// defaultObservabilityTemplate is the collector ServiceTemplate shipped with the Release.
+	defaultObservabilityTemplate = "otel-collector-0-1-0"
+	// observabilityAuthorizationIdentifier identifies the authorization Secret
+	// in the templated values of the collector.
+	observabilityAuthorizationIdentifier = "ObservabilityAuthorization"
+)
+
+// defaultWebhookPorts are the admission webhooks ports of KCM, CAPI providers and cert-manager.
+var defaultWebhookPorts = []int32{9443, 10250}
+
 // ManagementReconciler reconciles a Management object
 type ManagementReconciler struct {
 	Client          client.Client
@@ -62,18 +107,82 @@ type ManagementReconciler struct {
 	Config          *rest.Config
 	DynamicClient   *dynamic.DynamicClient
 	SystemNamespace string
+	AuditRecorder   *audit.Recorder
+	Notifier        *notifications.Notifier
 
 	defaultRequeueTime time.Duration
 
+	DefaultRegistryConfig helm.DefaultRegistryConfig
+
 	CreateAccessManagement bool
 
+	// ClusterDeploymentConcurrency and MultiClusterServiceConcurrency are the
+	// numbers of the objects reconciled concurrently by the controllers
+	// started once the Sveltos provider is installed.
+	ClusterDeploymentConcurrency   int
+	MultiClusterServiceConcurrency int
+	// RequeueInterval is the interval the Management is requeued at until it
+	// is ready, 10s if not set. ClusterDeploymentRequeueInterval is passed to
+	// the ClusterDeployment controller.
+	RequeueInterval                  time.Duration
+	ClusterDeploymentRequeueInterval time.Duration
+	// ShardedClusterDeployments disables the set up of the ClusterDeployment
+	// controller, which is set up on every replica by the
+	// ShardedClusterDeploymentController instead.
+	ShardedClusterDeployments bool
+
+	imageVerifier     *imageverify.Verifier
+	imageVerifierKeys []byte
+
+	preflight *preflight.Checker
+
+	downloadHelmChartFunc func(context.Context, *sourcev1.Artifact) (*chart.Chart, error)
+
+	// renderedCharts are the last rendered manifests of the components keyed
+	// by their release names, so the charts are rendered again only once
+	// their artifacts or the configurations of the components change.
+	renderedCharts   map[string][]*renderedChart
+	renderedChartsMu sync.Mutex
+
 	sveltosDependentControllersStarted bool
 }
 
-func (r *ManagementReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+// maxRenderedCharts is the number of the rendered charts cached per component,
+// so both the installed and the upcoming charts are kept while the upgrade to
+// the next Release is reviewed.
+const maxRenderedCharts = 2
+
+// renderedChart is the manifests rendered from the chart of the component
+// along with the key of the inputs they are rendered from.
+type renderedChart struct {
+	manifests map[string]string
+	key       string
+
+	// images are extracted from the manifests once on the first request.
+	images     []string
+	imagesErr  error
+	imagesOnce sync.Once
+}
+
+// Images returns the container images referenced by the rendered manifests,
+// the returned slice is shared and must not be modified.
+func (c *renderedChart) Images() ([]string, error) {
+	c.imagesOnce.Do(func() {
+		c.images, c.imagesErr = imageverify.ImagesFromManifests(c.manifests)
+	})
+	return c.images, c.imagesErr
+}
+
+func (r *ManagementReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	l := ctrl.LoggerFrom(ctx)
 	l.Info("Reconciling Management")
 
+	ctx, span := tracing.Start(ctx, "Management.Reconcile", tracing.ObjectAttributes(kcm.ManagementKind, "", req.Name)...)
+	defer func() {
+		tracing.End(span, err)
+		trackReconcileError(ctx, kcm.ManagementKind, err)
+	}()
+
 	management := &kcm.Management{}
 	if err := r.Client.Get(ctx, req.NamespacedName, management); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -111,11 +220,41 @@ func (r *ManagementReconciler) Update(ctx context.Context, management *kcm.Manag
 		return ctrl.Result{}, err
 	}
 
+	// the traces are not essential, the components are reconciled regardless
+	if err := tracing.Configure(ctx, management.Spec.Tracing); err != nil {
+		l.Error(err, "failed to configure tracing")
+	}
+
 	if err := r.cleanupRemovedComponents(ctx, management); err != nil {
 		l.Error(err, "failed to cleanup removed components")
 		return ctrl.Result{}, err
 	}
 
+	previousConditions := slices.Clone(management.Status.Conditions)
+	statusBase := management.DeepCopy()
+
+	upgradeApproved, err := r.reviewUpgrade(ctx, management)
+	if err != nil {
+		l.Error(err, "failed to review the upgrade")
+		return ctrl.Result{}, err
+	}
+	if !upgradeApproved {
+		l.Info("Upgrade is pending the approval", "current_release", management.Status.Release, "new_release", management.Spec.Release)
+		// keep reconciling the components of the current Release until the upgrade is approved
+		management.Spec.Release = management.Status.Release
+	}
+
+	upgradeAllowed, err := r.runPreflightChecks(ctx, management)
+	if err != nil {
+		l.Error(err, "failed to run preflight checks")
+		return ctrl.Result{}, err
+	}
+	if !upgradeAllowed {
+		l.Info("Preflight checks failed, postponing the upgrade", "current_release", management.Status.Release, "new_release", management.Spec.Release)
+		// keep reconciling the components of the current Release until the checks pass
+		management.Spec.Release = management.Status.Release
+	}
+
 	requeueAutoUpgradeBackups, err := r.ensureUpgradeBackup(ctx, management)
 	if err != nil {
 		l.Error(err, "failed to ensure release backups before upgrades")
@@ -132,17 +271,42 @@ func (r *ManagementReconciler) Update(ctx context.Context, management *kcm.Manag
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileBackupSchedules(ctx, management); err != nil {
+		l.Error(err, "failed to reconcile backup schedules")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileDefaultHelmRepository(ctx, management); err != nil {
+		l.Error(err, "failed to reconcile default HelmRepository")
+		return ctrl.Result{}, err
+	}
+
 	if err := r.enableAdditionalComponents(ctx, management); err != nil { // TODO (zerospiel): i wonder, do we need to reflect these changes and changes from the `wrappedComponents` in the spec?
 		l.Error(err, "failed to enable additional KCM components")
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileBackupStorage(ctx, management); err != nil {
+		l.Error(err, "failed to reconcile backup storage")
+		return ctrl.Result{}, err
+	}
+
 	components, err := getWrappedComponents(ctx, r.Client, management)
 	if err != nil {
 		l.Error(err, "failed to wrap KCM components")
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileNetworkPolicies(ctx, management, components); err != nil {
+		l.Error(err, "failed to reconcile NetworkPolicies")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.cleanupOperatorProviders(ctx, components); err != nil {
+		l.Error(err, "failed to cleanup Cluster API Operator providers")
+		return ctrl.Result{}, err
+	}
+
 	var (
 		errs error
 
@@ -187,23 +351,85 @@ func (r *ManagementReconciler) Update(ctx context.Context, management *kcm.Manag
 			continue
 		}
 
-		hrReconcileOpts := helm.ReconcileHelmReleaseOpts{
-			Values:          component.Config,
-			ChartRef:        template.Status.ChartRef,
-			DependsOn:       component.dependsOn,
-			TargetNamespace: component.targetNamespace,
-			Install:         component.installSettings,
+		var (
+			rendered  *renderedChart
+			manifests map[string]string
+			renderErr error
+		)
+		if management.Spec.ImageVerification != nil || management.Spec.SecurityProfile != "" || component.operatorManaged {
+			tctx, span := tracing.Start(ctx, "RenderComponentChart", attribute.String("component", component.helmReleaseName))
+			rendered, renderErr = r.renderComponent(tctx, template, component)
+			tracing.End(span, renderErr)
+			if renderErr == nil {
+				manifests = rendered.manifests
+			}
 		}
-		if template.Spec.Helm.ChartSpec != nil {
-			hrReconcileOpts.ReconcileInterval = &template.Spec.Helm.ChartSpec.Interval.Duration
+
+		if policy := management.Spec.ImageVerification; policy != nil {
+			verifyErr := renderErr
+			if verifyErr == nil {
+				verifyErr = r.verifyComponentImages(ctx, policy, rendered, component.Images)
+			}
+			if verifyErr != nil {
+				if policy.Enforce {
+					errMsg := fmt.Sprintf("Failed to verify images of the %s component: %s", component.helmReleaseName, verifyErr)
+					updateComponentsStatus(statusAccumulator, component, nil, errMsg)
+					errs = errors.Join(errs, errors.New(errMsg))
+
+					continue
+				}
+				l.Info("Images verification failed, proceeding since the enforcement is disabled", "template", component.Template, "err", verifyErr.Error())
+			}
 		}
 
-		if _, _, err := helm.ReconcileHelmRelease(ctx, r.Client, component.helmReleaseName, r.SystemNamespace, hrReconcileOpts); err != nil {
-			errMsg := fmt.Sprintf("Failed to reconcile HelmRelease %s/%s: %s", r.SystemNamespace, component.helmReleaseName, err)
-			updateComponentsStatus(statusAccumulator, component, nil, errMsg)
-			errs = errors.Join(errs, errors.New(errMsg))
+		var postRenderers []fluxv2.PostRenderer
+		if management.Spec.SecurityProfile != "" {
+			patches, err := hardening.Patches(management.Spec.SecurityProfile, manifests)
+			if err = errors.Join(renderErr, err); err != nil {
+				errMsg := fmt.Sprintf("Failed to apply the %s security profile to the %s component: %s", management.Spec.SecurityProfile, component.helmReleaseName, err)
+				updateComponentsStatus(statusAccumulator, component, nil, errMsg)
+				errs = errors.Join(errs, errors.New(errMsg))
 
-			continue
+				continue
+			}
+			postRenderers = hardening.PostRenderers(patches)
+		}
+		if len(component.Images) > 0 {
+			postRenderers = append(postRenderers, imageOverridesPostRenderer(component.Images))
+		}
+
+		if component.operatorManaged {
+			if err := errors.Join(renderErr, r.reconcileOperatorProviders(ctx, management, component, manifests)); err != nil {
+				errMsg := fmt.Sprintf("Failed to reconcile the Cluster API Operator providers of the %s component: %s", component.helmReleaseName, err)
+				updateComponentsStatus(statusAccumulator, component, nil, errMsg)
+				errs = errors.Join(errs, errors.New(errMsg))
+
+				continue
+			}
+		} else {
+			hrReconcileOpts := helm.ReconcileHelmReleaseOpts{
+				Values:          component.Config,
+				ChartRef:        template.Status.ChartRef,
+				DependsOn:       component.dependsOn,
+				TargetNamespace: component.targetNamespace,
+				Install:         component.installSettings,
+				PostRenderers:   postRenderers,
+				FieldManager:    managementFieldManager,
+			}
+			if template.Spec.Helm.ChartSpec != nil {
+				hrReconcileOpts.ReconcileInterval = &template.Spec.Helm.ChartSpec.Interval.Duration
+			}
+
+			tctx, span := tracing.Start(ctx, "ReconcileHelmRelease", attribute.String("component", component.helmReleaseName))
+			_, _, err := helm.ReconcileHelmRelease(tctx, r.Client, component.helmReleaseName, r.SystemNamespace, hrReconcileOpts)
+			tracing.End(span, err)
+			if err != nil {
+				errMsg := fmt.Sprintf("Failed to reconcile HelmRelease %s/%s: %s", r.SystemNamespace, component.helmReleaseName, err)
+				updateComponentsStatus(statusAccumulator, component, nil, errMsg)
+				errs = errors.Join(errs, errors.New(errMsg))
+
+				continue
+			}
 		}
 
 		if err := r.checkProviderStatus(ctx, component); err != nil {
@@ -218,8 +444,11 @@ func (r *ManagementReconciler) Update(ctx context.Context, management *kcm.Manag
 
 	management.Status.AvailableProviders = statusAccumulator.providers
 	management.Status.CAPIContracts = statusAccumulator.compatibilityContracts
+	r.setComponentsHealth(ctx, management.Status.Components, statusAccumulator.components)
+	previousComponents := management.Status.Components
 	management.Status.Components = statusAccumulator.components
 	management.Status.ObservedGeneration = management.Generation
+	previousRelease := management.Status.Release
 	management.Status.Release = management.Spec.Release
 
 	shouldRequeue, err := r.startDependentControllers(ctx, management)
@@ -230,10 +459,29 @@ func (r *ManagementReconciler) Update(ctx context.Context, management *kcm.Manag
 		requeue = true
 	}
 
+	// the collector is deployed once the MultiClusterService controller is started
+	if err := r.reconcileObservability(ctx, management); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
 	setReadyCondition(management)
 
-	if err := r.Client.Status().Update(ctx, management); err != nil {
+	if err := patchStatus(ctx, r.Client, management, statusBase); err != nil {
 		errs = errors.Join(errs, fmt.Errorf("failed to update status for Management %s: %w", management.Name, err))
+	} else {
+		recordComponentTransitions(management, previousComponents, management.Status.Components)
+		recordConditionTransitions(management, previousConditions, management.Status.Conditions, managementConditionEvents)
+
+		if previousRelease != "" && previousRelease != management.Status.Release {
+			record.Eventf(management, eventReasonUpgradeStarted, "Upgrading the components from the Release %s to %s", previousRelease, management.Status.Release)
+			if err := r.AuditRecorder.Record(ctx, kcm.AuditActionReleaseUpgrade,
+				kcm.AuditSubject{Kind: kcm.ManagementKind, Name: management.Name},
+				fmt.Sprintf("Components upgraded from the Release %s to %s", previousRelease, management.Status.Release),
+				map[string]string{"oldRelease": previousRelease, "newRelease": management.Status.Release},
+			); err != nil {
+				l.Error(err, "failed to record audit event")
+			}
+		}
 	}
 
 	if errs != nil {
@@ -243,497 +491,2133 @@ func (r *ManagementReconciler) Update(ctx context.Context, management *kcm.Manag
 	if requeue {
 		return ctrl.Result{RequeueAfter: r.defaultRequeueTime}, nil
 	}
+	if !upgradeAllowed {
+		return ctrl.Result{RequeueAfter: preflightRetryPeriod}, nil
+	}
 
 	return ctrl.Result{}, nil
 }
 
-// startDependentControllers starts controllers that cannot be started
-// at process startup because of some dependency like CRDs being present.
-func (r *ManagementReconciler) startDependentControllers(ctx context.Context, management *kcm.Management) (requue bool, err error) {
-	if r.sveltosDependentControllersStarted {
-		// Only need to start controllers once.
-		return false, nil
+// runPreflightChecks validates the management cluster is ready for the upgrade
+// to the requested Release and reports the results in the PreflightPassed condition.
+// It returns false if the upgrade has to be postponed.
+func (r *ManagementReconciler) runPreflightChecks(ctx context.Context, mgmt *kcm.Management) (bool, error) {
+	if mgmt.Status.Release == "" || mgmt.Spec.Release == mgmt.Status.Release {
+		return true, nil
 	}
 
-	l := ctrl.LoggerFrom(ctx).WithValues("provider_name", kcm.ProviderSveltosName)
-	if !management.Status.Components[kcm.ProviderSveltosName].Success {
-		l.Info("Waiting for provider to be ready to setup contollers dependent on it")
+	condition := metav1.Condition{
+		Type:               kcm.PreflightPassedCondition,
+		ObservedGeneration: mgmt.Generation,
+		Status:             metav1.ConditionTrue,
+		Reason:             kcm.SucceededReason,
+		Message:            fmt.Sprintf("All preflight checks of the upgrade to the Release %s passed", mgmt.Spec.Release),
+	}
+
+	if mgmt.Annotations[kcm.SkipPreflightAnnotation] == "true" {
+		condition.Reason = kcm.PreflightChecksSkippedReason
+		condition.Message = fmt.Sprintf("Preflight checks of the upgrade to the Release %s are skipped", mgmt.Spec.Release)
+		meta.SetStatusCondition(&mgmt.Status.Conditions, condition)
 		return true, nil
 	}
 
-	currentNamespace := utils.CurrentNamespace()
+	release := new(kcm.Release)
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: mgmt.Spec.Release}, release); err != nil {
+		return false, fmt.Errorf("failed to get Release %s: %w", mgmt.Spec.Release, err)
+	}
 
-	l.Info("Provider has been successfully installed, so setting up controller for ClusterDeployment")
-	if err = (&ClusterDeploymentReconciler{
-		DynamicClient:   r.DynamicClient,
-		SystemNamespace: currentNamespace,
-	}).SetupWithManager(r.Manager); err != nil {
-		return false, fmt.Errorf("failed to setup controller for ClusterDeployment: %w", err)
+	checker := r.preflight
+	if checker == nil {
+		checker = &preflight.Checker{Client: r.Client}
 	}
-	l.Info("Setup for ClusterDeployment controller successful")
 
-	l.Info("Provider has been successfully installed, so setting up controller for MultiClusterService")
-	if err = (&MultiClusterServiceReconciler{
-		SystemNamespace: currentNamespace,
-	}).SetupWithManager(r.Manager); err != nil {
-		return false, fmt.Errorf("failed to setup controller for MultiClusterService: %w", err)
+	failures, err := checker.Run(ctx, mgmt, release)
+	if err != nil {
+		return false, err
 	}
-	l.Info("Setup for MultiClusterService controller successful")
 
-	r.sveltosDependentControllersStarted = true
-	return false, nil
+	if len(failures) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = kcm.PreflightChecksFailedReason
+		condition.Message = strings.Join(failures, "; ")
+	}
+	meta.SetStatusCondition(&mgmt.Status.Conditions, condition)
+
+	return len(failures) == 0, nil
 }
 
-func (r *ManagementReconciler) cleanupRemovedComponents(ctx context.Context, management *kcm.Management) error {
-	var (
-		errs error
-		l    = ctrl.LoggerFrom(ctx)
-	)
+// reviewUpgrade reports the changes of the upgrade to the requested Release if
+// the upgrade dry-run is enabled and reflects the approval of the upgrade in
+// the UpgradeApproved condition. It returns false if the upgrade is not approved yet.
+func (r *ManagementReconciler) reviewUpgrade(ctx context.Context, mgmt *kcm.Management) (bool, error) {
+	if !mgmt.Spec.UpgradeDryRun {
+		mgmt.Status.UpgradeReport = nil
+		meta.RemoveStatusCondition(&mgmt.Status.Conditions, kcm.UpgradeApprovedCondition)
+		return true, nil
+	}
+	if mgmt.Status.Release == "" || mgmt.Spec.Release == mgmt.Status.Release {
+		mgmt.Status.UpgradeReport = nil
+		return true, nil
+	}
 
-	managedHelmReleases := new(fluxv2.HelmReleaseList)
-	if err := r.Client.List(ctx, managedHelmReleases,
-		client.MatchingLabels{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue},
-		client.InNamespace(r.SystemNamespace), // all helmreleases are being installed only in the system namespace
-	); err != nil {
-		return fmt.Errorf("failed to list %s: %w", fluxv2.GroupVersion.WithKind(fluxv2.HelmReleaseKind), err)
+	condition := metav1.Condition{
+		Type:               kcm.UpgradeApprovedCondition,
+		ObservedGeneration: mgmt.Generation,
+		Status:             metav1.ConditionTrue,
+		Reason:             kcm.SucceededReason,
+		Message:            fmt.Sprintf("Upgrade to the Release %s is approved", mgmt.Spec.Release),
 	}
 
-	for _, hr := range managedHelmReleases.Items {
-		// do not remove non-management related components (#703)
-		if len(hr.OwnerReferences) > 0 {
-			continue
+	if mgmt.Annotations[kcm.ReleaseApprovalAnnotation] == mgmt.Spec.Release {
+		meta.SetStatusCondition(&mgmt.Status.Conditions, condition)
+		return true, nil
+	}
+
+	if mgmt.Status.UpgradeReport == nil || mgmt.Status.UpgradeReport.Release != mgmt.Spec.Release {
+		report, err := r.upgradeReport(ctx, mgmt)
+		if err != nil {
+			return false, fmt.Errorf("failed to report the upgrade to the Release %s: %w", mgmt.Spec.Release, err)
 		}
+		mgmt.Status.UpgradeReport = report
+	}
 
-		componentName := hr.Name // providers(components) names map 1-1 to the helmreleases names
+	condition.Status = metav1.ConditionFalse
+	condition.Reason = kcm.UpgradeApprovalPendingReason
+	condition.Message = fmt.Sprintf("Upgrade to the Release %s is pending the approval with the %s annotation", mgmt.Spec.Release, kcm.ReleaseApprovalAnnotation)
+	meta.SetStatusCondition(&mgmt.Status.Conditions, condition)
 
-		if componentName == kcm.CoreCAPIName ||
-			componentName == kcm.CoreKCMName ||
-			componentName == utils.TemplatesChartFromReleaseName(management.Spec.Release) ||
-			slices.ContainsFunc(management.Spec.Providers, func(newComp kcm.Provider) bool { return componentName == newComp.Name }) {
-			continue
-		}
+	return false, nil
+}
 
-		l.Info("Found component to remove", "component_name", componentName)
+// upgradeReport reports the components changed by the upgrade of the Management
+// to the requested Release along with the changes of their CRDs and the clusters
+// relying on their providers.
+func (r *ManagementReconciler) upgradeReport(ctx context.Context, mgmt *kcm.Management) (*kcm.UpgradeReport, error) {
+	components, err := getWrappedComponents(ctx, r.Client, mgmt)
+	if err != nil {
+		return nil, err
+	}
 
-		if err := r.Client.Delete(ctx, &hr); client.IgnoreNotFound(err) != nil {
-			errs = errors.Join(errs, fmt.Errorf("failed to delete %s: %w", client.ObjectKeyFromObject(&hr), err))
+	report := &kcm.UpgradeReport{Release: mgmt.Spec.Release}
+
+	var changedProviders []string
+	for _, comp := range components {
+		installed := mgmt.Status.Components[comp.helmReleaseName]
+		if installed.Template == comp.Template {
 			continue
 		}
-		l.Info("Removed HelmRelease", "reference", client.ObjectKeyFromObject(&hr).String())
-	}
 
-	return errs
-}
+		template := new(kcm.ProviderTemplate)
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: comp.Template}, template); err != nil {
+			return nil, fmt.Errorf("failed to get ProviderTemplate %s: %w", comp.Template, err)
+		}
 
-func (r *ManagementReconciler) ensureAccessManagement(ctx context.Context, mgmt *kcm.Management) error {
-	l := ctrl.LoggerFrom(ctx)
-	if !r.CreateAccessManagement {
-		return nil
-	}
-	l.Info("Ensuring AccessManagement is created")
-	amObj := &kcm.AccessManagement{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: kcm.AccessManagementName,
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: kcm.GroupVersion.String(),
-					Kind:       mgmt.Kind,
-					Name:       mgmt.Name,
-					UID:        mgmt.UID,
-				},
-			},
-		},
-	}
-	err := r.Client.Get(ctx, client.ObjectKey{
-		Name: kcm.AccessManagementName,
-	}, amObj)
-	if err == nil {
-		return nil
-	}
-	if !apierrors.IsNotFound(err) {
-		return fmt.Errorf("failed to get %s AccessManagement object: %w", kcm.AccessManagementName, err)
-	}
-	err = r.Client.Create(ctx, amObj)
-	if err != nil {
-		return fmt.Errorf("failed to create %s AccessManagement object: %w", kcm.AccessManagementName, err)
+		report.Components = append(report.Components, kcm.ComponentChange{
+			Name:             comp.helmReleaseName,
+			FromTemplate:     installed.Template,
+			ToTemplate:       comp.Template,
+			FromChartVersion: installed.Version,
+			ToChartVersion:   template.Status.ChartVersion,
+		})
+		changedProviders = append(changedProviders, template.Status.Providers...)
+
+		manifests, err := r.renderComponentChart(ctx, template, comp)
+		if err != nil {
+			return nil, err
+		}
+		crdChanges, err := r.crdChanges(ctx, comp.helmReleaseName, manifests)
+		if err != nil {
+			return nil, err
+		}
+		report.CRDs = append(report.CRDs, crdChanges...)
 	}
-	l.Info("Successfully created AccessManagement object")
 
-	return nil
-}
+	for _, name := range slices.Sorted(maps.Keys(mgmt.Status.Components)) {
+		if slices.ContainsFunc(components, func(c component) bool { return c.helmReleaseName == name }) {
+			continue
+		}
 
-// checkProviderStatus checks the status of a provider associated with a given
-// ProviderTemplate name. Since there's no way to determine resource Kind from
-// the given template iterate over all possible provider types.
-func (r *ManagementReconciler) checkProviderStatus(ctx context.Context, component component) error {
-	helmReleaseName := component.helmReleaseName
-	hr := &fluxv2.HelmRelease{}
-	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: r.SystemNamespace, Name: helmReleaseName}, hr); err != nil {
-		return fmt.Errorf("failed to check provider status: %w", err)
-	}
+		installed := mgmt.Status.Components[name]
+		report.Components = append(report.Components, kcm.ComponentChange{
+			Name:             name,
+			FromTemplate:     installed.Template,
+			FromChartVersion: installed.Version,
+		})
 
-	hrReadyCondition := fluxconditions.Get(hr, fluxmeta.ReadyCondition)
-	if hrReadyCondition == nil || hrReadyCondition.ObservedGeneration != hr.Generation {
-		return fmt.Errorf("HelmRelease %s/%s Ready condition is not updated yet", r.SystemNamespace, helmReleaseName)
-	}
-	if hr.Status.ObservedGeneration != hr.Generation {
-		return fmt.Errorf("HelmRelease %s/%s has not observed new values yet", r.SystemNamespace, helmReleaseName)
-	}
-	if !fluxconditions.IsReady(hr) {
-		return fmt.Errorf("HelmRelease %s/%s is not yet ready: %s", r.SystemNamespace, helmReleaseName, hrReadyCondition.Message)
+		template := new(kcm.ProviderTemplate)
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: installed.Template}, template); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to get ProviderTemplate %s: %w", installed.Template, err)
+		}
+		changedProviders = append(changedProviders, template.Status.Providers...)
 	}
 
-	// mostly for sanity check
-	latestSnapshot := hr.Status.History.Latest()
-	if latestSnapshot == nil {
-		return fmt.Errorf("HelmRelease %s/%s has empty deployment history in the status", r.SystemNamespace, helmReleaseName)
-	}
-	if latestSnapshot.Status != helmreleasepkg.StatusDeployed.String() {
-		return fmt.Errorf("HelmRelease %s/%s is not yet deployed, actual status is %s", r.SystemNamespace, helmReleaseName, latestSnapshot.Status)
-	}
-	if latestSnapshot.ConfigDigest != hr.Status.LastAttemptedConfigDigest {
-		return fmt.Errorf("HelmRelease %s/%s is not yet reconciled the latest values", r.SystemNamespace, helmReleaseName)
+	report.AffectedClusters, err = r.clustersUsingProviders(ctx, changedProviders)
+	if err != nil {
+		return nil, err
 	}
 
-	if !component.isCAPIProvider {
-		return nil
-	}
+	return report, nil
+}
 
-	type genericProviderList interface {
-		client.ObjectList
-		capioperatorv1.GenericProviderList
+// crdChanges compares the CustomResourceDefinitions from the given rendered
+// manifests of the component with the ones installed to the cluster.
+func (r *ManagementReconciler) crdChanges(ctx context.Context, componentName string, manifests map[string]string) ([]kcm.CRDChange, error) {
+	crds, err := crdsFromManifests(manifests)
+	if err != nil {
+		return nil, err
 	}
 
-	var (
-		errs          error
-		providerFound bool
+	var changes []kcm.CRDChange
+	for _, crd := range crds {
+		change := kcm.CRDChange{Name: crd.Name, Component: componentName}
 
-		ldebug = ctrl.LoggerFrom(ctx).V(1)
-	)
-	for _, gpl := range []genericProviderList{
-		&capioperatorv1.CoreProviderList{},
-		&capioperatorv1.InfrastructureProviderList{},
-		&capioperatorv1.BootstrapProviderList{},
-		&capioperatorv1.ControlPlaneProviderList{},
-	} {
-		if err := r.Client.List(ctx, gpl, client.MatchingLabels{kcm.FluxHelmChartNameKey: hr.Status.History.Latest().Name}); meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
-			ldebug.Info("capi operator providers are not found", "list_type", fmt.Sprintf("%T", gpl))
+		installed := new(apiextensionsv1.CustomResourceDefinition)
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: crd.Name}, installed); apierrors.IsNotFound(err) {
+			change.Added = true
+			changes = append(changes, change)
 			continue
 		} else if err != nil {
-			return fmt.Errorf("failed to list providers: %w", err)
+			return nil, fmt.Errorf("failed to get CustomResourceDefinition %s: %w", crd.Name, err)
 		}
 
-		items := gpl.GetItems()
-		if len(items) == 0 { // sanity
-			continue
+		versions, installedVersions := crdVersions(crd), crdVersions(installed)
+		for _, v := range versions {
+			if !slices.Contains(installedVersions, v) {
+				change.AddedVersions = append(change.AddedVersions, v)
+			}
 		}
+		for _, v := range installedVersions {
+			if !slices.Contains(versions, v) {
+				change.RemovedVersions = append(change.RemovedVersions, v)
+			}
+		}
+		if len(change.AddedVersions) > 0 || len(change.RemovedVersions) > 0 {
+			changes = append(changes, change)
+		}
+	}
 
-		providerFound = true
+	return changes, nil
+}
 
-		if err := checkProviderReadiness(items); err != nil {
-			errs = errors.Join(errs, err)
+// crdsFromManifests returns the CustomResourceDefinitions from the given
+// rendered manifests.
+func crdsFromManifests(manifests map[string]string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	for _, name := range slices.Sorted(maps.Keys(manifests)) {
+		decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(manifests[name]), 4096)
+		for {
+			crd := new(apiextensionsv1.CustomResourceDefinition)
+			if err := decoder.Decode(crd); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+			}
+			if crd.Kind == "CustomResourceDefinition" {
+				crds = append(crds, crd)
+			}
 		}
 	}
+	return crds, nil
+}
 
-	if !providerFound {
-		return errors.New("waiting for Cluster API Provider objects to be created")
+// releaseCRDVersions returns the versions served by the CRDs of the core
+// components of the given Release by the CRD names.
+func (r *ManagementReconciler) releaseCRDVersions(ctx context.Context, release *kcm.Release) (map[string][]string, error) {
+	kcmConfig, err := applyKCMDefaults(nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return errs
-}
+	versions := make(map[string][]string)
+	for _, comp := range []component{
+		{Component: kcm.Component{Template: release.Spec.KCM.Template, Config: kcmConfig}, helmReleaseName: kcm.CoreKCMName},
+		{Component: kcm.Component{Template: release.Spec.CAPI.Template}, helmReleaseName: kcm.CoreCAPIName},
+	} {
+		template := new(kcm.ProviderTemplate)
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: comp.Template}, template); err != nil {
+			return nil, fmt.Errorf("failed to get ProviderTemplate %s: %w", comp.Template, err)
+		}
 
-func checkProviderReadiness(items []capioperatorv1.GenericProvider) error {
-	var errMessages []string
-	for _, gp := range items {
-		if gp.GetGeneration() != gp.GetStatus().ObservedGeneration {
-			errMessages = append(errMessages, "status is not updated yet")
+		manifests, err := r.renderComponentChart(ctx, template, comp)
+		if err != nil {
+			return nil, err
+		}
+		crds, err := crdsFromManifests(manifests)
+		if err != nil {
+			return nil, err
+		}
+		for _, crd := range crds {
+			versions[crd.Name] = crdVersions(crd)
+		}
+	}
+
+	return versions, nil
+}
+
+func crdVersions(crd *apiextensionsv1.CustomResourceDefinition) []string {
+	versions := make([]string, 0, len(crd.Spec.Versions))
+	for _, v := range crd.Spec.Versions {
+		versions = append(versions, v.Name)
+	}
+	return versions
+}
+
+// clustersUsingProviders returns the ClusterDeployments in the namespace/name
+// format deployed from the ClusterTemplates relying on any of the given providers.
+func (r *ManagementReconciler) clustersUsingProviders(ctx context.Context, providers []string) ([]string, error) {
+	if len(providers) == 0 {
+		return nil, nil
+	}
+
+	clusterTemplates := new(kcm.ClusterTemplateList)
+	if err := r.Client.List(ctx, clusterTemplates); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterTemplates: %w", err)
+	}
+
+	templates := make(map[client.ObjectKey]struct{})
+	for _, tpl := range clusterTemplates.Items {
+		if slices.ContainsFunc(tpl.Status.Providers, func(p string) bool { return slices.Contains(providers, p) }) {
+			templates[client.ObjectKeyFromObject(&tpl)] = struct{}{}
+		}
+	}
+	if len(templates) == 0 {
+		return nil, nil
+	}
+
+	var clusters []string
+	for template := range templates {
+		clusterDeployments := new(kcm.ClusterDeploymentList)
+		if err := r.Client.List(ctx, clusterDeployments,
+			client.InNamespace(template.Namespace),
+			client.MatchingFields{kcm.ClusterDeploymentTemplateIndexKey: template.Name},
+		); err != nil {
+			return nil, fmt.Errorf("failed to list ClusterDeployments: %w", err)
+		}
+
+		for _, cd := range clusterDeployments.Items {
+			clusters = append(clusters, client.ObjectKeyFromObject(&cd).String())
+		}
+	}
+
+	slices.Sort(clusters)
+	return clusters, nil
+}
+
+// reconcileNetworkPolicies ensures the NetworkPolicies of the Management components exist
+// in the namespaces the components are installed to, or are removed if not configured.
+func (r *ManagementReconciler) reconcileNetworkPolicies(ctx context.Context, mgmt *kcm.Management, components []component) error {
+	l := ctrl.LoggerFrom(ctx)
+
+	namespaces := make(map[string]struct{})
+	if mgmt.Spec.NetworkPolicies != nil {
+		for _, c := range components {
+			namespace := c.targetNamespace
+			if namespace == "" {
+				namespace = r.SystemNamespace
+			}
+			namespaces[namespace] = struct{}{}
+		}
+	}
+
+	var errs error
+	for namespace := range namespaces {
+		networkPolicy := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      managementNetworkPolicyName,
+				Namespace: namespace,
+			},
+		}
+
+		operation, err := ctrl.CreateOrUpdate(ctx, r.Client, networkPolicy, func() error {
+			utils.AddLabel(networkPolicy, kcm.KCMManagedLabelKey, kcm.KCMManagedLabelValue)
+			networkPolicy.Spec = managementNetworkPolicySpec(mgmt.Spec.NetworkPolicies)
+			return controllerutil.SetOwnerReference(mgmt, networkPolicy, r.Client.Scheme())
+		})
+		if err != nil {
+			// the namespace of the component might not be created yet
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			errs = errors.Join(errs, fmt.Errorf("failed to reconcile NetworkPolicy %s: %w", client.ObjectKeyFromObject(networkPolicy), err))
 			continue
 		}
-		if gp.GetSpec().Version != "" && (gp.GetStatus().InstalledVersion != nil && gp.GetSpec().Version != *gp.GetStatus().InstalledVersion) {
-			errMessages = append(errMessages, fmt.Sprintf("expected version %s, actual %s", gp.GetSpec().Version, *gp.GetStatus().InstalledVersion))
+		if operation == controllerutil.OperationResultCreated || operation == controllerutil.OperationResultUpdated {
+			l.Info("Successfully mutated NetworkPolicy", "NetworkPolicy", client.ObjectKeyFromObject(networkPolicy), "operation_result", operation)
+		}
+	}
+
+	networkPolicies := new(networkingv1.NetworkPolicyList)
+	if err := r.Client.List(ctx, networkPolicies, client.MatchingLabels{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue}); err != nil {
+		return errors.Join(errs, fmt.Errorf("failed to list NetworkPolicies: %w", err))
+	}
+
+	for _, networkPolicy := range networkPolicies.Items {
+		if _, ok := namespaces[networkPolicy.Namespace]; ok || networkPolicy.Name != managementNetworkPolicyName {
 			continue
 		}
-		if !isProviderReady(gp) {
-			errMessages = append(errMessages, getFalseConditions(gp)...)
+
+		if err := r.Client.Delete(ctx, &networkPolicy); client.IgnoreNotFound(err) != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to delete NetworkPolicy %s: %w", client.ObjectKeyFromObject(&networkPolicy), err))
+			continue
 		}
+		l.Info("Removed NetworkPolicy", "NetworkPolicy", client.ObjectKeyFromObject(&networkPolicy))
 	}
-	if len(errMessages) == 0 {
-		return nil
+
+	return errs
+}
+
+// managementNetworkPolicySpec returns the spec of the NetworkPolicy selecting all of the pods
+// in the namespace and allowing the traffic within the namespace, the ingress on the
+// webhooks ports and from the selected namespaces and any egress.
+func managementNetworkPolicySpec(cfg *kcm.NetworkPolicies) networkingv1.NetworkPolicySpec {
+	webhookPorts := cfg.WebhookPorts
+	if len(webhookPorts) == 0 {
+		webhookPorts = defaultWebhookPorts
+	}
+
+	tcp := corev1.ProtocolTCP
+	ports := make([]networkingv1.NetworkPolicyPort, 0, len(webhookPorts))
+	for _, port := range webhookPorts {
+		ports = append(ports, networkingv1.NetworkPolicyPort{
+			Protocol: &tcp,
+			Port:     utils.PtrTo(intstr.FromInt32(port)),
+		})
+	}
+
+	peers := []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}
+	if cfg.IngressNamespaceSelector != nil {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{NamespaceSelector: cfg.IngressNamespaceSelector.DeepCopy()})
+	}
+
+	return networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{
+			{From: peers},
+			{Ports: ports},
+		},
+		Egress: []networkingv1.NetworkPolicyEgressRule{{}},
 	}
-	return fmt.Errorf("%s is not yet ready: %s", items[0].GetObjectKind().GroupVersionKind().Kind, strings.Join(errMessages, ", "))
 }
 
-func isProviderReady(gp capioperatorv1.GenericProvider) bool {
-	return slices.ContainsFunc(gp.GetStatus().Conditions, func(c clusterapiv1.Condition) bool {
-		return c.Type == clusterapiv1.ReadyCondition && c.Status == corev1.ConditionTrue
-	})
+// renderComponentChart renders the chart of the given component with its
+// configuration. The manifests are cached until the digest of the chart
+// artifact, the configuration or the target namespace of the component
+// change, the returned manifests are shared and must not be modified.
+func (r *ManagementReconciler) renderComponentChart(ctx context.Context, template *kcm.ProviderTemplate, comp component) (map[string]string, error) {
+	rendered, err := r.renderComponent(ctx, template, comp)
+	if err != nil {
+		return nil, err
+	}
+	return rendered.manifests, nil
 }
 
-func getFalseConditions(gp capioperatorv1.GenericProvider) []string {
-	var messages []string
-	for _, cond := range gp.GetStatus().Conditions {
-		if cond.Status != corev1.ConditionTrue && cond.Message != "" {
-			messages = append(messages, cond.Message)
+// renderComponent returns the chart of the given component rendered with its
+// configuration, see renderComponentChart.
+func (r *ManagementReconciler) renderComponent(ctx context.Context, template *kcm.ProviderTemplate, comp component) (*renderedChart, error) {
+	if template.Status.ChartRef == nil {
+		return nil, fmt.Errorf("ProviderTemplate %s has no chart reference", template.Name)
+	}
+
+	helmChart := new(sourcev1.HelmChart)
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: template.Status.ChartRef.Namespace, Name: template.Status.ChartRef.Name}, helmChart); err != nil {
+		return nil, fmt.Errorf("failed to get HelmChart %s: %w", template.Status.ChartRef, err)
+	}
+	if helmChart.GetArtifact() == nil {
+		return nil, fmt.Errorf("HelmChart %s has no artifact yet", client.ObjectKeyFromObject(helmChart))
+	}
+
+	values, err := comp.HelmValues()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the %s component configuration: %w", comp.helmReleaseName, err)
+	}
+
+	targetNamespace := comp.targetNamespace
+	if targetNamespace == "" {
+		targetNamespace = r.SystemNamespace
+	}
+
+	key, err := renderedChartKey(helmChart.GetArtifact(), values, targetNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute the key of the %s component chart: %w", comp.helmReleaseName, err)
+	}
+
+	if key != "" {
+		r.renderedChartsMu.Lock()
+		idx := slices.IndexFunc(r.renderedCharts[comp.helmReleaseName], func(rendered *renderedChart) bool { return rendered.key == key })
+		if idx >= 0 {
+			rendered := r.renderedCharts[comp.helmReleaseName][idx]
+			r.renderedChartsMu.Unlock()
+			return rendered, nil
 		}
+		r.renderedChartsMu.Unlock()
 	}
-	return messages
+
+	if r.downloadHelmChartFunc == nil {
+		r.downloadHelmChartFunc = helm.DownloadChartFromArtifact
+	}
+
+	hcChart, err := r.downloadHelmChartFunc(ctx, helmChart.GetArtifact())
+	if err != nil {
+		return nil, fmt.Errorf("failed to download chart of the ProviderTemplate %s: %w", template.Name, err)
+	}
+
+	manifests, err := helm.RenderChart(hcChart, values, comp.helmReleaseName, targetNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := &renderedChart{manifests: manifests, key: key}
+	if key != "" {
+		r.renderedChartsMu.Lock()
+		if r.renderedCharts == nil {
+			r.renderedCharts = make(map[string][]*renderedChart)
+		}
+		cached := append([]*renderedChart{rendered}, r.renderedCharts[comp.helmReleaseName]...)
+		r.renderedCharts[comp.helmReleaseName] = cached[:min(len(cached), maxRenderedCharts)]
+		r.renderedChartsMu.Unlock()
+	}
+
+	return rendered, nil
 }
 
-func (r *ManagementReconciler) Delete(ctx context.Context, management *kcm.Management) (ctrl.Result, error) {
-	l := ctrl.LoggerFrom(ctx)
-	listOpts := &client.ListOptions{
-		LabelSelector: labels.SelectorFromSet(map[string]string{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue}),
+// renderedChartKey returns the key of the manifests rendered from the chart of
+// the given artifact with the given values into the given namespace. The key
+// is empty if the artifact has no digest, so the chart is not cached.
+func renderedChartKey(artifact *sourcev1.Artifact, values map[string]any, namespace string) (string, error) {
+	if artifact.Digest == "" {
+		return "", nil
 	}
-	requeue, err := r.removeHelmReleases(ctx, kcm.CoreKCMName, listOpts)
-	if err != nil || requeue {
-		return ctrl.Result{RequeueAfter: r.defaultRequeueTime}, err
+
+	// the keys of the maps are sorted, so the equal values are marshaled equally
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return "", err
 	}
-	requeue, err = r.removeHelmCharts(ctx, listOpts)
-	if err != nil || requeue {
-		return ctrl.Result{RequeueAfter: r.defaultRequeueTime}, err
+	sum := sha256.Sum256(raw)
+
+	return artifact.Digest + "/" + hex.EncodeToString(sum[:]) + "/" + namespace, nil
+}
+
+// verifyComponentImages verifies the signatures of the container images
+// referenced by the given rendered chart of the component.
+func (r *ManagementReconciler) verifyComponentImages(ctx context.Context, policy *kcm.ImageVerification, rendered *renderedChart, overrides []kcm.ImageOverride) error {
+	verifier, err := r.getImageVerifier(ctx, policy)
+	if err != nil {
+		return err
 	}
-	requeue, err = r.removeHelmRepositories(ctx, listOpts)
-	if err != nil || requeue {
-		return ctrl.Result{RequeueAfter: r.defaultRequeueTime}, err
+
+	images, err := rendered.Images()
+	if err != nil {
+		return err
 	}
+	images = imageverify.OverrideImages(images, overrides)
 
-	// Removing finalizer in the end of cleanup
-	l.Info("Removing Management finalizer")
-	if controllerutil.RemoveFinalizer(management, kcm.ManagementFinalizer) {
-		return ctrl.Result{}, r.Client.Update(ctx, management)
+	var errs error
+	for _, image := range images {
+		if imageverify.ImageMatches(image, policy.IgnoredImages) {
+			continue
+		}
+
+		errs = errors.Join(errs, verifier.Verify(ctx, image))
 	}
-	return ctrl.Result{}, nil
+
+	return errs
 }
 
-func (r *ManagementReconciler) removeHelmReleases(ctx context.Context, kcmReleaseName string, opts *client.ListOptions) (requeue bool, err error) {
-	l := ctrl.LoggerFrom(ctx)
-	l.Info("Suspending KCM Helm Release reconciles")
-	kcmRelease := &fluxv2.HelmRelease{}
-	err = r.Client.Get(ctx, client.ObjectKey{Namespace: r.SystemNamespace, Name: kcmReleaseName}, kcmRelease)
-	if err != nil && !apierrors.IsNotFound(err) {
-		return false, err
+// getImageVerifier returns the verifier trusting the public keys from the Secret referenced by the policy.
+// The verifier is re-created only once the keys are changed to keep the cache of the verified images.
+func (r *ManagementReconciler) getImageVerifier(ctx context.Context, policy *kcm.ImageVerification) (*imageverify.Verifier, error) {
+	secret := new(corev1.Secret)
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: r.SystemNamespace, Name: policy.SecretRef}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get Secret %s/%s with the trusted keys: %w", r.SystemNamespace, policy.SecretRef, err)
 	}
-	if err == nil && !kcmRelease.Spec.Suspend {
-		kcmRelease.Spec.Suspend = true
-		if err := r.Client.Update(ctx, kcmRelease); err != nil {
-			return false, err
+
+	dataKeys := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		dataKeys = append(dataKeys, k)
+	}
+	slices.Sort(dataKeys)
+
+	var keys []byte
+	for _, k := range dataKeys {
+		keys = append(append(keys, secret.Data[k]...), '\n')
+	}
+
+	if r.imageVerifier != nil && bytes.Equal(r.imageVerifierKeys, keys) {
+		return r.imageVerifier, nil
+	}
+
+	verifier, err := imageverify.NewVerifier(keys, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted keys in the Secret %s/%s: %w", r.SystemNamespace, policy.SecretRef, err)
+	}
+
+	r.imageVerifier, r.imageVerifierKeys = verifier, keys
+	return verifier, nil
+}
+
+// startDependentControllers starts controllers that cannot be started
+// at process startup because of some dependency like CRDs being present.
+func (r *ManagementReconciler) startDependentControllers(ctx context.Context, management *kcm.Management) (requue bool, err error) {
+	if r.sveltosDependentControllersStarted {
+		// Only need to start controllers once.
+		return false, nil
+	}
+
+	l := ctrl.LoggerFrom(ctx).WithValues("provider_name", kcm.ProviderSveltosName)
+	if !management.Status.Components[kcm.ProviderSveltosName].Success {
+		l.Info("Waiting for provider to be ready to setup contollers dependent on it")
+		return true, nil
+	}
+
+	currentNamespace := utils.CurrentNamespace()
+
+	if !r.ShardedClusterDeployments {
+		l.Info("Provider has been successfully installed, so setting up controller for ClusterDeployment")
+		if err = (&ClusterDeploymentReconciler{
+			DynamicClient:           r.DynamicClient,
+			Notifier:                r.Notifier,
+			SystemNamespace:         currentNamespace,
+			MaxConcurrentReconciles: r.ClusterDeploymentConcurrency,
+			RequeueInterval:         r.ClusterDeploymentRequeueInterval,
+		}).SetupWithManager(r.Manager); err != nil {
+			return false, fmt.Errorf("failed to setup controller for ClusterDeployment: %w", err)
 		}
+		l.Info("Setup for ClusterDeployment controller successful")
 	}
-	l.Info("Ensuring all HelmReleases owned by KCM are removed")
-	gvk := fluxv2.GroupVersion.WithKind(fluxv2.HelmReleaseKind)
-	if err := utils.EnsureDeleteAllOf(ctx, r.Client, gvk, opts); err != nil {
-		l.Error(err, "Not all HelmReleases owned by KCM are removed")
-		return true, err
+
+	l.Info("Provider has been successfully installed, so setting up controller for MultiClusterService")
+	if err = (&MultiClusterServiceReconciler{
+		SystemNamespace:         currentNamespace,
+		MaxConcurrentReconciles: r.MultiClusterServiceConcurrency,
+	}).SetupWithManager(r.Manager); err != nil {
+		return false, fmt.Errorf("failed to setup controller for MultiClusterService: %w", err)
 	}
+	l.Info("Setup for MultiClusterService controller successful")
+
+	r.sveltosDependentControllersStarted = true
 	return false, nil
 }
 
-func (r *ManagementReconciler) removeHelmCharts(ctx context.Context, opts *client.ListOptions) (requeue bool, err error) {
-	l := ctrl.LoggerFrom(ctx)
-	l.Info("Ensuring all HelmCharts owned by KCM are removed")
-	gvk := sourcev1.GroupVersion.WithKind(sourcev1.HelmChartKind)
-	if err := utils.EnsureDeleteAllOf(ctx, r.Client, gvk, opts); err != nil {
-		l.Error(err, "Not all HelmCharts owned by KCM are removed")
-		return true, err
+func (r *ManagementReconciler) cleanupRemovedComponents(ctx context.Context, management *kcm.Management) error {
+	var (
+		errs error
+		l    = ctrl.LoggerFrom(ctx)
+	)
+
+	managedHelmReleases := new(fluxv2.HelmReleaseList)
+	if err := r.Client.List(ctx, managedHelmReleases,
+		client.MatchingLabels{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue},
+		client.InNamespace(r.SystemNamespace), // all helmreleases are being installed only in the system namespace
+	); err != nil {
+		return fmt.Errorf("failed to list %s: %w", fluxv2.GroupVersion.WithKind(fluxv2.HelmReleaseKind), err)
 	}
-	return false, nil
+
+	for _, hr := range managedHelmReleases.Items {
+		// do not remove non-management related components (#703)
+		if len(hr.OwnerReferences) > 0 {
+			continue
+		}
+
+		componentName := hr.Name // providers(components) names map 1-1 to the helmreleases names
+
+		if componentName == kcm.CoreCAPIName ||
+			componentName == kcm.CoreKCMName ||
+			componentName == utils.TemplatesChartFromReleaseName(management.Spec.Release) ||
+			slices.ContainsFunc(management.Spec.Providers, func(newComp kcm.Provider) bool { return componentName == newComp.Name && !newComp.Disabled }) {
+			continue
+		}
+
+		l.Info("Found component to remove", "component_name", componentName)
+
+		if err := r.Client.Delete(ctx, &hr); client.IgnoreNotFound(err) != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to delete %s: %w", client.ObjectKeyFromObject(&hr), err))
+			continue
+		}
+		l.Info("Removed HelmRelease", "reference", client.ObjectKeyFromObject(&hr).String())
+	}
+
+	return errs
 }
 
-func (r *ManagementReconciler) removeHelmRepositories(ctx context.Context, opts *client.ListOptions) (requeue bool, err error) {
+func (r *ManagementReconciler) ensureAccessManagement(ctx context.Context, mgmt *kcm.Management) error {
 	l := ctrl.LoggerFrom(ctx)
-	l.Info("Ensuring all HelmRepositories owned by KCM are removed")
-	gvk := sourcev1.GroupVersion.WithKind(sourcev1.HelmRepositoryKind)
-	if err := utils.EnsureDeleteAllOf(ctx, r.Client, gvk, opts); err != nil {
-		l.Error(err, "Not all HelmRepositories owned by KCM are removed")
-		return true, err
+	if !r.CreateAccessManagement {
+		return nil
+	}
+	l.Info("Ensuring AccessManagement is created")
+	amObj := &kcm.AccessManagement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: kcm.AccessManagementName,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: kcm.GroupVersion.String(),
+					Kind:       mgmt.Kind,
+					Name:       mgmt.Name,
+					UID:        mgmt.UID,
+				},
+			},
+		},
+	}
+	err := r.Client.Get(ctx, client.ObjectKey{
+		Name: kcm.AccessManagementName,
+	}, amObj)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get %s AccessManagement object: %w", kcm.AccessManagementName, err)
+	}
+	err = r.Client.Create(ctx, amObj)
+	if err != nil {
+		return fmt.Errorf("failed to create %s AccessManagement object: %w", kcm.AccessManagementName, err)
+	}
+	l.Info("Successfully created AccessManagement object")
+
+	return nil
+}
+
+// operatorProviderKinds are the kinds of the objects rendered from the provider
+// charts installed with the CAPIOperator lifecycle.
+var operatorProviderKinds = []schema.GroupVersionKind{
+	capioperatorv1.GroupVersion.WithKind("CoreProvider"),
+	capioperatorv1.GroupVersion.WithKind("InfrastructureProvider"),
+	capioperatorv1.GroupVersion.WithKind("BootstrapProvider"),
+	capioperatorv1.GroupVersion.WithKind("ControlPlaneProvider"),
+	corev1.SchemeGroupVersion.WithKind("Secret"),
+}
+
+// reconcileOperatorProviders creates or updates the objects rendered from the
+// chart of the given component, i.e. the Cluster API Operator providers and
+// their configuration Secrets, owned by the Management. The HelmRelease the
+// component was previously installed with is removed keeping the objects.
+func (r *ManagementReconciler) reconcileOperatorProviders(ctx context.Context, mgmt *kcm.Management, comp component, manifests map[string]string) error {
+	if err := r.releaseFromHelm(ctx, comp.helmReleaseName); err != nil {
+		return err
+	}
+
+	objects, err := objectsFromManifests(manifests)
+	if err != nil {
+		return err
+	}
+
+	namespace := comp.targetNamespace
+	if namespace == "" {
+		namespace = r.SystemNamespace
+	}
+
+	for _, desired := range objects {
+		if !slices.Contains(operatorProviderKinds, desired.GroupVersionKind()) {
+			return fmt.Errorf("%s %s is not supported by the %s provider lifecycle", desired.GetKind(), desired.GetName(), kcm.ProviderLifecycleCAPIOperator)
+		}
+		if desired.GetNamespace() == "" {
+			desired.SetNamespace(namespace)
+		}
+		if err := stringDataToData(desired); err != nil {
+			return fmt.Errorf("failed to convert the stringData of %s %s: %w", desired.GetKind(), client.ObjectKeyFromObject(desired), err)
+		}
+
+		obj := new(unstructured.Unstructured)
+		obj.SetGroupVersionKind(desired.GroupVersionKind())
+		obj.SetNamespace(desired.GetNamespace())
+		obj.SetName(desired.GetName())
+		if _, err := ctrl.CreateOrUpdate(ctx, r.Client, obj, func() error {
+			for k, v := range desired.Object {
+				if k == "apiVersion" || k == "kind" || k == "metadata" || k == "status" {
+					continue
+				}
+				obj.Object[k] = v
+			}
+
+			objLabels := obj.GetLabels()
+			if objLabels == nil {
+				objLabels = make(map[string]string)
+			}
+			maps.Copy(objLabels, desired.GetLabels())
+			objLabels[kcm.KCMManagedLabelKey] = kcm.KCMManagedLabelValue
+			objLabels[kcm.ProviderComponentLabelKey] = comp.helmReleaseName
+			obj.SetLabels(objLabels)
+
+			if annotations := desired.GetAnnotations(); len(annotations) > 0 {
+				merged := obj.GetAnnotations()
+				if merged == nil {
+					merged = make(map[string]string)
+				}
+				maps.Copy(merged, annotations)
+				obj.SetAnnotations(merged)
+			}
+
+			return controllerutil.SetOwnerReference(mgmt, obj, r.Client.Scheme())
+		}); err != nil {
+			return fmt.Errorf("failed to reconcile %s %s: %w", desired.GetKind(), client.ObjectKeyFromObject(desired), err)
+		}
+	}
+
+	return nil
+}
+
+// stringDataToData moves the stringData of the rendered Secret into its data.
+// The stringData is never returned by the API server, so the Secret would be
+// updated on every reconcile otherwise.
+func stringDataToData(obj *unstructured.Unstructured) error {
+	stringData, found, err := unstructured.NestedStringMap(obj.Object, "stringData")
+	if err != nil || !found {
+		return err
+	}
+
+	data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = make(map[string]string, len(stringData))
+	}
+	for k, v := range stringData {
+		data[k] = base64.StdEncoding.EncodeToString([]byte(v))
+	}
+
+	unstructured.RemoveNestedField(obj.Object, "stringData")
+	if len(data) == 0 {
+		return nil
+	}
+	return unstructured.SetNestedStringMap(obj.Object, data, "data")
+}
+
+// releaseFromHelm removes the HelmRelease of the given name without the
+// uninstallation of its objects: the suspended HelmReleases are deleted by
+// the helm-controller as is. The Helm storage of the release is removed
+// beforehand, since it is not owned by anything and would be left behind.
+func (r *ManagementReconciler) releaseFromHelm(ctx context.Context, name string) error {
+	hr := new(fluxv2.HelmRelease)
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: r.SystemNamespace, Name: name}, hr); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !hr.Spec.Suspend {
+		hr.Spec.Suspend = true
+		if err := r.Client.Update(ctx, hr); err != nil {
+			return fmt.Errorf("failed to suspend HelmRelease %s: %w", client.ObjectKeyFromObject(hr), err)
+		}
+	}
+
+	// the releases are stored in the Secrets named sh.helm.release.v1.<release>.v<revision>
+	storage := new(corev1.SecretList)
+	if err := r.Client.List(ctx, storage, client.InNamespace(hr.GetStorageNamespace()),
+		client.MatchingLabels{"owner": "helm", "name": hr.GetReleaseName()}); err != nil {
+		return fmt.Errorf("failed to list the Helm storage of HelmRelease %s: %w", client.ObjectKeyFromObject(hr), err)
+	}
+	for _, secret := range storage.Items {
+		if err := r.Client.Delete(ctx, &secret); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete the Helm storage Secret %s: %w", client.ObjectKeyFromObject(&secret), err)
+		}
+	}
+
+	if err := r.Client.Delete(ctx, hr); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete HelmRelease %s: %w", client.ObjectKeyFromObject(hr), err)
+	}
+	ctrl.LoggerFrom(ctx).Info("Handed over the component from the HelmRelease to the Cluster API Operator providers", "component", name)
+	return nil
+}
+
+// cleanupOperatorProviders removes the objects of the providers installed
+// with the CAPIOperator lifecycle which are not among the given components.
+// The objects of the components installed with the Helm lifecycle are
+// handed over to their HelmReleases instead.
+func (r *ManagementReconciler) cleanupOperatorProviders(ctx context.Context, components []component) error {
+	var errs error
+	for _, gvk := range operatorProviderKinds {
+		list := new(unstructured.UnstructuredList)
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.Client.List(ctx, list, client.HasLabels{kcm.ProviderComponentLabelKey}); meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+		}
+
+		for _, obj := range list.Items {
+			name := obj.GetLabels()[kcm.ProviderComponentLabelKey]
+
+			idx := slices.IndexFunc(components, func(c component) bool { return c.helmReleaseName == name })
+			if idx >= 0 && components[idx].operatorManaged {
+				continue
+			}
+
+			if idx < 0 {
+				if err := r.Client.Delete(ctx, &obj); client.IgnoreNotFound(err) != nil {
+					errs = errors.Join(errs, fmt.Errorf("failed to delete %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(&obj), err))
+				}
+				continue
+			}
+
+			objLabels := obj.GetLabels()
+			delete(objLabels, kcm.ProviderComponentLabelKey)
+			objLabels["app.kubernetes.io/managed-by"] = "Helm"
+			obj.SetLabels(objLabels)
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations["meta.helm.sh/release-name"] = name
+			annotations["meta.helm.sh/release-namespace"] = obj.GetNamespace()
+			obj.SetAnnotations(annotations)
+			obj.SetOwnerReferences(nil)
+			if err := r.Client.Update(ctx, &obj); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("failed to hand over %s %s to Helm: %w", gvk.Kind, client.ObjectKeyFromObject(&obj), err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// objectsFromManifests returns the objects from the given rendered manifests.
+func objectsFromManifests(manifests map[string]string) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	for _, name := range slices.Sorted(maps.Keys(manifests)) {
+		decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(manifests[name]), 4096)
+		for {
+			obj := new(unstructured.Unstructured)
+			if err := decoder.Decode(&obj.Object); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+			}
+			if len(obj.Object) > 0 {
+				objects = append(objects, obj)
+			}
+		}
+	}
+	return objects, nil
+}
+
+// checkProviderStatus checks the status of a provider associated with a given
+// ProviderTemplate name. Since there's no way to determine resource Kind from
+// the given template iterate over all possible provider types.
+func (r *ManagementReconciler) checkProviderStatus(ctx context.Context, component component) error {
+	if component.operatorManaged {
+		return r.checkOperatorProviders(ctx, client.MatchingLabels{kcm.ProviderComponentLabelKey: component.helmReleaseName})
+	}
+
+	helmReleaseName := component.helmReleaseName
+	hr := &fluxv2.HelmRelease{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: r.SystemNamespace, Name: helmReleaseName}, hr); err != nil {
+		return fmt.Errorf("failed to check provider status: %w", err)
+	}
+
+	hrReadyCondition := fluxconditions.Get(hr, fluxmeta.ReadyCondition)
+	if hrReadyCondition == nil || hrReadyCondition.ObservedGeneration != hr.Generation {
+		return fmt.Errorf("HelmRelease %s/%s Ready condition is not updated yet", r.SystemNamespace, helmReleaseName)
+	}
+	if hr.Status.ObservedGeneration != hr.Generation {
+		return fmt.Errorf("HelmRelease %s/%s has not observed new values yet", r.SystemNamespace, helmReleaseName)
+	}
+	if !fluxconditions.IsReady(hr) {
+		return fmt.Errorf("HelmRelease %s/%s is not yet ready: %s", r.SystemNamespace, helmReleaseName, hrReadyCondition.Message)
+	}
+
+	// mostly for sanity check
+	latestSnapshot := hr.Status.History.Latest()
+	if latestSnapshot == nil {
+		return fmt.Errorf("HelmRelease %s/%s has empty deployment history in the status", r.SystemNamespace, helmReleaseName)
+	}
+	if latestSnapshot.Status != helmreleasepkg.StatusDeployed.String() {
+		return fmt.Errorf("HelmRelease %s/%s is not yet deployed, actual status is %s", r.SystemNamespace, helmReleaseName, latestSnapshot.Status)
+	}
+	if latestSnapshot.ConfigDigest != hr.Status.LastAttemptedConfigDigest {
+		return fmt.Errorf("HelmRelease %s/%s is not yet reconciled the latest values", r.SystemNamespace, helmReleaseName)
+	}
+
+	if !component.isCAPIProvider {
+		return nil
+	}
+
+	return r.checkOperatorProviders(ctx, client.MatchingLabels{kcm.FluxHelmChartNameKey: latestSnapshot.Name})
+}
+
+// checkOperatorProviders checks the readiness of the Cluster API Operator
+// providers matching the given labels.
+func (r *ManagementReconciler) checkOperatorProviders(ctx context.Context, matchingLabels client.MatchingLabels) error {
+	type genericProviderList interface {
+		client.ObjectList
+		capioperatorv1.GenericProviderList
+	}
+
+	var (
+		errs          error
+		providerFound bool
+
+		ldebug = ctrl.LoggerFrom(ctx).V(1)
+	)
+	for _, gpl := range []genericProviderList{
+		&capioperatorv1.CoreProviderList{},
+		&capioperatorv1.InfrastructureProviderList{},
+		&capioperatorv1.BootstrapProviderList{},
+		&capioperatorv1.ControlPlaneProviderList{},
+	} {
+		if err := r.Client.List(ctx, gpl, matchingLabels); meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			ldebug.Info("capi operator providers are not found", "list_type", fmt.Sprintf("%T", gpl))
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to list providers: %w", err)
+		}
+
+		items := gpl.GetItems()
+		if len(items) == 0 { // sanity
+			continue
+		}
+
+		providerFound = true
+
+		if err := checkProviderReadiness(items); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	if !providerFound {
+		return errors.New("waiting for Cluster API Provider objects to be created")
+	}
+
+	return errs
+}
+
+func checkProviderReadiness(items []capioperatorv1.GenericProvider) error {
+	var errMessages []string
+	for _, gp := range items {
+		if gp.GetGeneration() != gp.GetStatus().ObservedGeneration {
+			errMessages = append(errMessages, "status is not updated yet")
+			continue
+		}
+		if gp.GetSpec().Version != "" && (gp.GetStatus().InstalledVersion != nil && gp.GetSpec().Version != *gp.GetStatus().InstalledVersion) {
+			errMessages = append(errMessages, fmt.Sprintf("expected version %s, actual %s", gp.GetSpec().Version, *gp.GetStatus().InstalledVersion))
+			continue
+		}
+		if !isProviderReady(gp) {
+			errMessages = append(errMessages, getFalseConditions(gp)...)
+		}
+	}
+	if len(errMessages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s is not yet ready: %s", items[0].GetObjectKind().GroupVersionKind().Kind, strings.Join(errMessages, ", "))
+}
+
+func isProviderReady(gp capioperatorv1.GenericProvider) bool {
+	return slices.ContainsFunc(gp.GetStatus().Conditions, func(c clusterapiv1.Condition) bool {
+		return c.Type == clusterapiv1.ReadyCondition && c.Status == corev1.ConditionTrue
+	})
+}
+
+func getFalseConditions(gp capioperatorv1.GenericProvider) []string {
+	var messages []string
+	for _, cond := range gp.GetStatus().Conditions {
+		if cond.Status != corev1.ConditionTrue && cond.Message != "" {
+			messages = append(messages, cond.Message)
+		}
+	}
+	return messages
+}
+
+func (r *ManagementReconciler) Delete(ctx context.Context, management *kcm.Management) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+	listOpts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue}),
+	}
+	requeue, err := r.removeOperatorProviders(ctx)
+	if err != nil || requeue {
+		return ctrl.Result{RequeueAfter: r.defaultRequeueTime}, err
+	}
+	requeue, err = r.removeHelmReleases(ctx, kcm.CoreKCMName, listOpts)
+	if err != nil || requeue {
+		return ctrl.Result{RequeueAfter: r.defaultRequeueTime}, err
+	}
+	requeue, err = r.removeHelmCharts(ctx, listOpts)
+	if err != nil || requeue {
+		return ctrl.Result{RequeueAfter: r.defaultRequeueTime}, err
+	}
+	requeue, err = r.removeHelmRepositories(ctx, listOpts)
+	if err != nil || requeue {
+		return ctrl.Result{RequeueAfter: r.defaultRequeueTime}, err
+	}
+
+	// Removing finalizer in the end of cleanup
+	l.Info("Removing Management finalizer")
+	if controllerutil.RemoveFinalizer(management, kcm.ManagementFinalizer) {
+		return ctrl.Result{}, r.Client.Update(ctx, management)
+	}
+	return ctrl.Result{}, nil
+}
+
+// removeOperatorProviders removes the objects of the providers installed with
+// the CAPIOperator lifecycle while the Cluster API Operator is still running.
+func (r *ManagementReconciler) removeOperatorProviders(ctx context.Context) (requeue bool, err error) {
+	l := ctrl.LoggerFrom(ctx)
+	l.Info("Ensuring all Cluster API Operator providers managed by KCM are removed")
+
+	selector, err := labels.Parse(kcm.ProviderComponentLabelKey)
+	if err != nil {
+		return false, err
+	}
+	for _, gvk := range operatorProviderKinds {
+		if err := utils.EnsureDeleteAllOf(ctx, r.Client, gvk, &client.ListOptions{LabelSelector: selector}); meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			l.Error(err, "Not all Cluster API Operator providers managed by KCM are removed")
+			return true, err
+		}
+	}
+	return false, nil
+}
+
+func (r *ManagementReconciler) removeHelmReleases(ctx context.Context, kcmReleaseName string, opts *client.ListOptions) (requeue bool, err error) {
+	l := ctrl.LoggerFrom(ctx)
+	l.Info("Suspending KCM Helm Release reconciles")
+	kcmRelease := &fluxv2.HelmRelease{}
+	err = r.Client.Get(ctx, client.ObjectKey{Namespace: r.SystemNamespace, Name: kcmReleaseName}, kcmRelease)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	if err == nil && !kcmRelease.Spec.Suspend {
+		kcmRelease.Spec.Suspend = true
+		if err := r.Client.Update(ctx, kcmRelease); err != nil {
+			return false, err
+		}
+	}
+	l.Info("Ensuring all HelmReleases owned by KCM are removed")
+	gvk := fluxv2.GroupVersion.WithKind(fluxv2.HelmReleaseKind)
+	if err := utils.EnsureDeleteAllOf(ctx, r.Client, gvk, opts); err != nil {
+		l.Error(err, "Not all HelmReleases owned by KCM are removed")
+		return true, err
+	}
+	return false, nil
+}
+
+func (r *ManagementReconciler) removeHelmCharts(ctx context.Context, opts *client.ListOptions) (requeue bool, err error) {
+	l := ctrl.LoggerFrom(ctx)
+	l.Info("Ensuring all HelmCharts owned by KCM are removed")
+	gvk := sourcev1.GroupVersion.WithKind(sourcev1.HelmChartKind)
+	if err := utils.EnsureDeleteAllOf(ctx, r.Client, gvk, opts); err != nil {
+		l.Error(err, "Not all HelmCharts owned by KCM are removed")
+		return true, err
+	}
+	return false, nil
+}
+
+func (r *ManagementReconciler) removeHelmRepositories(ctx context.Context, opts *client.ListOptions) (requeue bool, err error) {
+	l := ctrl.LoggerFrom(ctx)
+	l.Info("Ensuring all HelmRepositories owned by KCM are removed")
+	gvk := sourcev1.GroupVersion.WithKind(sourcev1.HelmRepositoryKind)
+	if err := utils.EnsureDeleteAllOf(ctx, r.Client, gvk, opts); err != nil {
+		l.Error(err, "Not all HelmRepositories owned by KCM are removed")
+		return true, err
+	}
+	return false, nil
+}
+
+type component struct {
+	kcm.Component
+
+	helmReleaseName string
+	targetNamespace string
+	installSettings *fluxv2.Install
+	// helm release dependencies
+	dependsOn      []fluxmeta.NamespacedObjectReference
+	isCAPIProvider bool
+	// the provider objects are created directly instead of the HelmRelease
+	operatorManaged bool
+}
+
+func applyKCMDefaults(config *apiextensionsv1.JSON) (*apiextensionsv1.JSON, error) {
+	values := chartutil.Values{}
+	if config != nil && config.Raw != nil {
+		err := json.Unmarshal(config.Raw, &values)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Those are only needed for the initial installation
+	enforcedValues := map[string]any{
+		"controller": map[string]any{
+			"createManagement":       false,
+			"createAccessManagement": false,
+			"createRelease":          false,
+		},
+	}
+
+	chartutil.CoalesceTables(values, enforcedValues)
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
+
+func getWrappedComponents(ctx context.Context, cl client.Client, mgmt *kcm.Management) ([]component, error) {
+	release := &kcm.Release{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: mgmt.Spec.Release}, release); err != nil {
+		return nil, fmt.Errorf("failed to get Release %s: %w", mgmt.Spec.Release, err)
+	}
+
+	components := make([]component, 0, len(mgmt.Spec.Providers)+2)
+
+	kcmComponent := kcm.Component{}
+	capiComponent := kcm.Component{}
+	if mgmt.Spec.Core != nil {
+		kcmComponent = mgmt.Spec.Core.KCM
+		capiComponent = mgmt.Spec.Core.CAPI
+	}
+
+	kcmComp := component{Component: kcmComponent, helmReleaseName: kcm.CoreKCMName}
+	if kcmComp.Template == "" {
+		kcmComp.Template = release.Spec.KCM.Template
+	}
+	kcmConfig, err := applyKCMDefaults(kcmComp.Config)
+	if err != nil {
+		return nil, err
+	}
+	kcmComp.Config = kcmConfig
+	components = append(components, kcmComp)
+
+	capiComp := component{
+		Component: capiComponent,
+		installSettings: &fluxv2.Install{
+			Remediation: &fluxv2.InstallRemediation{
+				Retries:              1,
+				RemediateLastFailure: utils.PtrTo(true),
+			},
+		},
+		helmReleaseName: kcm.CoreCAPIName,
+		dependsOn:       []fluxmeta.NamespacedObjectReference{{Name: kcm.CoreKCMName}},
+		isCAPIProvider:  true,
+	}
+	if capiComp.Template == "" {
+		capiComp.Template = release.Spec.CAPI.Template
+	}
+	components = append(components, capiComp)
+
+	const sveltosTargetNamespace = "projectsveltos"
+
+	for _, p := range mgmt.Spec.Providers {
+		if p.Disabled {
+			continue
+		}
+
+		c := component{
+			Component: p.Component, helmReleaseName: p.Name,
+			dependsOn: []fluxmeta.NamespacedObjectReference{{Name: kcm.CoreCAPIName}}, isCAPIProvider: true,
+		}
+		for _, dep := range p.DependsOn {
+			c.dependsOn = append(c.dependsOn, fluxmeta.NamespacedObjectReference{Name: dep})
+		}
+		// Try to find corresponding provider in the Release object
+		if c.Template == "" {
+			c.Template = release.ProviderTemplate(p.Name)
+		}
+
+		if p.Name == kcm.ProviderSveltosName {
+			c.isCAPIProvider = false
+			c.targetNamespace = sveltosTargetNamespace
+			c.installSettings = &fluxv2.Install{
+				CreateNamespace: true,
+			}
+		}
+
+		components = append(components, c)
+	}
+
+	components, err = sortComponents(components)
+	if err != nil {
+		return nil, err
+	}
+
+	if mgmt.Spec.ProviderLifecycle == kcm.ProviderLifecycleCAPIOperator {
+		for i := range components {
+			components[i].operatorManaged = components[i].isCAPIProvider
+		}
+	}
+
+	if mgmt.Spec.Global != nil {
+		for i := range components {
+			config, err := applyGlobalValues(components[i].Config, mgmt.Spec.Global)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set the global values for the %s component: %w", components[i].helmReleaseName, err)
+			}
+			components[i].Config = config
+		}
+	}
+
+	if mgmt.Spec.FIPS {
+		for i := range components {
+			config, err := applyFIPSValues(components[i].Config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to enable FIPS mode for the %s component: %w", components[i].helmReleaseName, err)
+			}
+			components[i].Config = config
+		}
+	}
+
+	if mgmt.Spec.RegistryMirror != nil {
+		for i := range components {
+			config, err := applyRegistryMirrorValues(components[i].Config, mgmt.Spec.RegistryMirror)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set the registry mirror for the %s component: %w", components[i].helmReleaseName, err)
+			}
+			components[i].Config = config
+		}
+	}
+
+	return components, nil
+}
+
+// sortComponents orders the given components so that each of them is
+// reconciled after the components it depends on.
+func sortComponents(components []component) ([]component, error) {
+	names := make([]string, 0, len(components))
+	dependencies := make(map[string][]string, len(components))
+	byName := make(map[string]component, len(components))
+	for _, c := range components {
+		names = append(names, c.helmReleaseName)
+		byName[c.helmReleaseName] = c
+		for _, dep := range c.dependsOn {
+			dependencies[c.helmReleaseName] = append(dependencies[c.helmReleaseName], dep.Name)
+		}
+	}
+
+	ordered, err := utils.DependencyOrder(names, dependencies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to order the components: %w", err)
+	}
+
+	sorted := make([]component, 0, len(components))
+	for _, name := range ordered {
+		sorted = append(sorted, byName[name])
+	}
+	return sorted, nil
+}
+
+// applyGlobalValues sets the given global values in the given component
+// configuration, the values of the configuration take precedence.
+func applyGlobalValues(config *apiextensionsv1.JSON, global *kcm.GlobalValues) (*apiextensionsv1.JSON, error) {
+	values := chartutil.Values{}
+	if config != nil && config.Raw != nil {
+		if err := json.Unmarshal(config.Raw, &values); err != nil {
+			return nil, err
+		}
+	}
+
+	raw, err := json.Marshal(global)
+	if err != nil {
+		return nil, err
+	}
+	globalValues := make(map[string]any)
+	if err := json.Unmarshal(raw, &globalValues); err != nil {
+		return nil, err
+	}
+	if len(globalValues) == 0 {
+		return config, nil
+	}
+
+	// values from the dst take precedence
+	chartutil.CoalesceTables(values, map[string]any{
+		"global": globalValues,
+	})
+	raw, err = json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
+
+// applyRegistryMirrorValues sets the image registry and the image pull secret
+// of the given registry mirror in the given component configuration.
+func applyRegistryMirrorValues(config *apiextensionsv1.JSON, mirror *kcm.RegistryMirror) (*apiextensionsv1.JSON, error) {
+	values := chartutil.Values{}
+	if config != nil && config.Raw != nil {
+		if err := json.Unmarshal(config.Raw, &values); err != nil {
+			return nil, err
+		}
+	}
+
+	globalValues := make(map[string]any)
+	if mirror.ImageRegistry != "" {
+		globalValues["imageRegistry"] = mirror.ImageRegistry
+	}
+	if mirror.CredentialsSecretRef != "" {
+		// keep the image pull secrets already set, e.g. the global ones
+		var pullSecrets []any
+		if global, ok := values["global"].(map[string]any); ok {
+			pullSecrets, _ = global["imagePullSecrets"].([]any)
+		}
+		if !slices.ContainsFunc(pullSecrets, func(s any) bool {
+			secret, ok := s.(map[string]any)
+			return ok && secret["name"] == mirror.CredentialsSecretRef
+		}) {
+			pullSecrets = append(pullSecrets, map[string]any{"name": mirror.CredentialsSecretRef})
+		}
+		globalValues["imagePullSecrets"] = pullSecrets
+	}
+	if len(globalValues) == 0 {
+		return config, nil
+	}
+
+	enforcedValues := map[string]any{
+		"global": globalValues,
+	}
+
+	// values from the dst take precedence
+	chartutil.CoalesceTables(enforcedValues, values)
+	raw, err := json.Marshal(enforcedValues)
+	if err != nil {
+		return nil, err
+	}
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
+
+// imageOverridesPostRenderer returns the Flux HelmRelease post-renderer
+// replacing the container images of the component with the given overrides.
+func imageOverridesPostRenderer(overrides []kcm.ImageOverride) fluxv2.PostRenderer {
+	images := make([]kustomize.Image, 0, len(overrides))
+	for _, o := range overrides {
+		images = append(images, kustomize.Image{Name: o.Name, NewName: o.NewName, NewTag: o.NewTag, Digest: o.Digest})
+	}
+	return fluxv2.PostRenderer{Kustomize: &fluxv2.Kustomize{Images: images}}
+}
+
+// applyFIPSValues enforces the FIPS-compliant mode in the given component configuration.
+func applyFIPSValues(config *apiextensionsv1.JSON) (*apiextensionsv1.JSON, error) {
+	values := chartutil.Values{}
+	if config != nil && config.Raw != nil {
+		if err := json.Unmarshal(config.Raw, &values); err != nil {
+			return nil, err
+		}
+	}
+
+	enforcedValues := map[string]any{
+		"global": map[string]any{
+			"fips": true,
+		},
+	}
+
+	// values from the dst take precedence
+	chartutil.CoalesceTables(enforcedValues, values)
+	raw, err := json.Marshal(enforcedValues)
+	if err != nil {
+		return nil, err
+	}
+	return &apiextensionsv1.JSON{Raw: raw}, nil
+}
+
+// enableAdditionalComponents enables the admission controller and cluster api operator
+// once the cert manager is ready and disables the optional components listed in the spec
+func (r *ManagementReconciler) enableAdditionalComponents(ctx context.Context, mgmt *kcm.Management) error {
+	l := ctrl.LoggerFrom(ctx)
+
+	config := make(map[string]any)
+
+	if mgmt.Spec.Core == nil {
+		mgmt.Spec.Core = new(kcm.Core)
+	}
+	if mgmt.Spec.Core.KCM.Config != nil {
+		if err := json.Unmarshal(mgmt.Spec.Core.KCM.Config.Raw, &config); err != nil {
+			return fmt.Errorf("failed to unmarshal KCM config into map[string]any: %w", err)
+		}
+	}
+
+	admissionWebhookValues := make(map[string]any)
+	if config["admissionWebhook"] != nil {
+		v, ok := config["admissionWebhook"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'admissionWebhook' (type %T) to map[string]any", config["admissionWebhook"])
+		}
+
+		admissionWebhookValues = v
+	}
+
+	capiOperatorValues := make(map[string]any)
+	if config["cluster-api-operator"] != nil {
+		v, ok := config["cluster-api-operator"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'cluster-api-operator' (type %T) to map[string]any", config["cluster-api-operator"])
+		}
+
+		capiOperatorValues = v
+	}
+
+	if config["velero"] != nil {
+		v, ok := config["velero"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'velero' (type %T) to map[string]any", config["velero"])
+		}
+
+		config["velero"] = v
+	}
+
+	if slices.Contains(mgmt.Spec.DisabledComponents, kcm.OptionalComponentVelero) {
+		l.Info("Velero is disabled, uninstalling it")
+		veleroValues, _ := config["velero"].(map[string]any)
+		if veleroValues == nil {
+			veleroValues = make(map[string]any)
+		}
+		veleroValues["enabled"] = false
+		config["velero"] = veleroValues
+	}
+
+	if r.Config != nil {
+		if err := certmanager.VerifyAPI(ctx, r.Config, r.SystemNamespace); err != nil {
+			return fmt.Errorf("failed to check in the cert-manager API is installed: %w", err)
+		}
+
+		l.Info("Cert manager is installed, enabling the KCM admission webhook")
+		admissionWebhookValues["enabled"] = true
+	}
+
+	config["admissionWebhook"] = admissionWebhookValues
+
+	if err := applyHighAvailabilityValues(config, mgmt.Spec.HighAvailability); err != nil {
+		return err
+	}
+
+	if err := applyDebugValues(config, mgmt.Spec.Debug); err != nil {
+		return err
+	}
+
+	if err := applyConcurrencyValues(config, mgmt.Spec.Concurrency); err != nil {
+		return err
+	}
+
+	if err := applyIntervalsValues(config, mgmt.Spec.Intervals); err != nil {
+		return err
+	}
+
+	if err := applyRBACValues(config, mgmt.Spec.Providers); err != nil {
+		return err
+	}
+
+	// Enable KCM capi operator only if it was not explicitly disabled in the config to
+	// support installation with existing cluster api operator
+	{
+		enabledV, enabledExists := capiOperatorValues["enabled"]
+		enabledValue, castedOk := enabledV.(bool)
+		if !enabledExists || !castedOk || enabledValue {
+			l.Info("Enabling cluster API operator")
+			capiOperatorValues["enabled"] = true
+		}
+	}
+	config["cluster-api-operator"] = capiOperatorValues
+
+	updatedConfig, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal KCM config: %w", err)
+	}
+
+	mgmt.Spec.Core.KCM.Config = &apiextensionsv1.JSON{Raw: updatedConfig}
+
+	return nil
+}
+
+// applyHighAvailabilityValues sets the replicas, the leader election timings and
+// the webhook disruption budget of the KCM controller manager in the given KCM config.
+func applyHighAvailabilityValues(config map[string]any, ha *kcm.HighAvailability) error {
+	if ha == nil {
+		return nil
+	}
+
+	if ha.Replicas != nil {
+		config["replicas"] = *ha.Replicas
+	}
+
+	if ha.WebhookMinAvailable != nil {
+		admissionWebhookValues, ok := config["admissionWebhook"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'admissionWebhook' (type %T) to map[string]any", config["admissionWebhook"])
+		}
+		admissionWebhookValues["minAvailable"] = *ha.WebhookMinAvailable
+	}
+
+	if ha.LeaderElection == nil {
+		return nil
+	}
+
+	controllerValues := make(map[string]any)
+	if config["controller"] != nil {
+		v, ok := config["controller"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'controller' (type %T) to map[string]any", config["controller"])
+		}
+
+		controllerValues = v
+	}
+
+	leaderElectionValues := make(map[string]any)
+	if controllerValues["leaderElection"] != nil {
+		v, ok := controllerValues["leaderElection"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'controller.leaderElection' (type %T) to map[string]any", controllerValues["leaderElection"])
+		}
+
+		leaderElectionValues = v
+	}
+
+	for key, d := range map[string]*metav1.Duration{
+		"leaseDuration": ha.LeaderElection.LeaseDuration,
+		"renewDeadline": ha.LeaderElection.RenewDeadline,
+		"retryPeriod":   ha.LeaderElection.RetryPeriod,
+	} {
+		if d != nil {
+			leaderElectionValues[key] = d.Duration.String()
+		}
+	}
+
+	controllerValues["leaderElection"] = leaderElectionValues
+	config["controller"] = controllerValues
+
+	return nil
+}
+
+// applyDebugValues enables the pprof endpoints on the loopback interface and
+// the block and mutex profiling of the KCM controller manager in the given KCM config.
+func applyDebugValues(config map[string]any, debug *kcm.Debug) error {
+	if debug == nil {
+		return nil
+	}
+
+	controllerValues := make(map[string]any)
+	if config["controller"] != nil {
+		v, ok := config["controller"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'controller' (type %T) to map[string]any", config["controller"])
+		}
+
+		controllerValues = v
+	}
+
+	debugValues := make(map[string]any)
+	if controllerValues["debug"] != nil {
+		v, ok := controllerValues["debug"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'controller.debug' (type %T) to map[string]any", controllerValues["debug"])
+		}
+
+		debugValues = v
+	}
+
+	// the endpoints are reachable only by forwarding the port of the pod,
+	// which is authorized by the RBAC of the pods/portforward subresource
+	const pprofBindAddress = "127.0.0.1:6060"
+
+	if debug.Pprof {
+		debugValues["pprofBindAddress"] = pprofBindAddress
+	}
+	debugValues["blockProfileRate"] = debug.BlockProfileRate
+	debugValues["mutexProfileFraction"] = debug.MutexProfileFraction
+
+	controllerValues["debug"] = debugValues
+	config["controller"] = controllerValues
+
+	return nil
+}
+
+// applyConcurrencyValues sets the numbers of the concurrent reconciliations of
+// the controllers of the KCM controller manager in the given KCM config.
+func applyConcurrencyValues(config map[string]any, concurrency *kcm.Concurrency) error {
+	if concurrency == nil {
+		return nil
+	}
+
+	controllerValues := make(map[string]any)
+	if config["controller"] != nil {
+		v, ok := config["controller"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'controller' (type %T) to map[string]any", config["controller"])
+		}
+
+		controllerValues = v
+	}
+
+	concurrencyValues := make(map[string]any)
+	if controllerValues["concurrency"] != nil {
+		v, ok := controllerValues["concurrency"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'controller.concurrency' (type %T) to map[string]any", controllerValues["concurrency"])
+		}
+
+		concurrencyValues = v
 	}
-	return false, nil
-}
 
-type component struct {
-	kcm.Component
+	for key, n := range map[string]*int32{
+		"clusterDeployment":   concurrency.ClusterDeployment,
+		"multiClusterService": concurrency.MultiClusterService,
+	} {
+		if n != nil {
+			concurrencyValues[key] = *n
+		}
+	}
 
-	helmReleaseName string
-	targetNamespace string
-	installSettings *fluxv2.Install
-	// helm release dependencies
-	dependsOn      []fluxmeta.NamespacedObjectReference
-	isCAPIProvider bool
+	controllerValues["concurrency"] = concurrencyValues
+	config["controller"] = controllerValues
+
+	return nil
 }
 
-func applyKCMDefaults(config *apiextensionsv1.JSON) (*apiextensionsv1.JSON, error) {
-	values := chartutil.Values{}
-	if config != nil && config.Raw != nil {
-		err := json.Unmarshal(config.Raw, &values)
-		if err != nil {
-			return nil, err
+// applyIntervalsValues sets the intervals of the periodic resyncs and
+// requeues of the controllers of the KCM controller manager in the given KCM
+// config.
+func applyIntervalsValues(config map[string]any, intervals *kcm.Intervals) error {
+	if intervals == nil {
+		return nil
+	}
+
+	controllerValues := make(map[string]any)
+	if config["controller"] != nil {
+		v, ok := config["controller"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'controller' (type %T) to map[string]any", config["controller"])
 		}
+
+		controllerValues = v
 	}
 
-	// Those are only needed for the initial installation
-	enforcedValues := map[string]any{
-		"controller": map[string]any{
-			"createManagement":       false,
-			"createAccessManagement": false,
-			"createRelease":          false,
-		},
+	intervalsValues := make(map[string]any)
+	if controllerValues["intervals"] != nil {
+		v, ok := controllerValues["intervals"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'controller.intervals' (type %T) to map[string]any", controllerValues["intervals"])
+		}
+
+		intervalsValues = v
 	}
 
-	chartutil.CoalesceTables(values, enforcedValues)
-	raw, err := json.Marshal(values)
-	if err != nil {
-		return nil, err
+	for key, d := range map[string]*metav1.Duration{
+		"resync":                   intervals.Resync,
+		"clusterDeploymentRequeue": intervals.ClusterDeploymentRequeue,
+		"managementRequeue":        intervals.ManagementRequeue,
+		"templateRequeue":          intervals.TemplateRequeue,
+	} {
+		if d != nil {
+			intervalsValues[key] = d.Duration.String()
+		}
 	}
-	return &apiextensionsv1.JSON{Raw: raw}, nil
+
+	controllerValues["intervals"] = intervalsValues
+	config["controller"] = controllerValues
+
+	return nil
 }
 
-func getWrappedComponents(ctx context.Context, cl client.Client, mgmt *kcm.Management) ([]component, error) {
-	release := &kcm.Release{}
-	if err := cl.Get(ctx, client.ObjectKey{Name: mgmt.Spec.Release}, release); err != nil {
-		return nil, fmt.Errorf("failed to get Release %s: %w", mgmt.Spec.Release, err)
+// applyRBACValues limits the permissions of the KCM controller on the objects
+// of the providers to the given enabled providers in the given KCM config, so
+// the permissions of the disabled providers are removed along with them.
+func applyRBACValues(config map[string]any, providers []kcm.Provider) error {
+	if len(providers) == 0 {
+		return nil
 	}
 
-	components := make([]component, 0, len(mgmt.Spec.Providers)+2)
+	rbacValues := make(map[string]any)
+	if config["rbac"] != nil {
+		v, ok := config["rbac"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'rbac' (type %T) to map[string]any", config["rbac"])
+		}
 
-	kcmComponent := kcm.Component{}
-	capiComponent := kcm.Component{}
-	if mgmt.Spec.Core != nil {
-		kcmComponent = mgmt.Spec.Core.KCM
-		capiComponent = mgmt.Spec.Core.CAPI
+		rbacValues = v
 	}
 
-	kcmComp := component{Component: kcmComponent, helmReleaseName: kcm.CoreKCMName}
-	if kcmComp.Template == "" {
-		kcmComp.Template = release.Spec.KCM.Template
-	}
-	kcmConfig, err := applyKCMDefaults(kcmComp.Config)
-	if err != nil {
-		return nil, err
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, p.Name)
 	}
-	kcmComp.Config = kcmConfig
-	components = append(components, kcmComp)
+	slices.Sort(names)
 
-	capiComp := component{
-		Component: capiComponent,
-		installSettings: &fluxv2.Install{
-			Remediation: &fluxv2.InstallRemediation{
-				Retries:              1,
-				RemediateLastFailure: utils.PtrTo(true),
-			},
-		},
-		helmReleaseName: kcm.CoreCAPIName,
-		dependsOn:       []fluxmeta.NamespacedObjectReference{{Name: kcm.CoreKCMName}},
-		isCAPIProvider:  true,
+	rbacValues["providers"] = names
+	config["rbac"] = rbacValues
+
+	return nil
+}
+
+// reconcileDefaultHelmRepository points the default HelmRepository of the system
+// namespace to the registry mirror of the Management or back to the default registry.
+func (r *ManagementReconciler) reconcileDefaultHelmRepository(ctx context.Context, mgmt *kcm.Management) error {
+	registryConfig, err := r.DefaultRegistryConfig.WithMirror(mgmt.Spec.RegistryMirror)
+	if err != nil {
+		return err
 	}
-	if capiComp.Template == "" {
-		capiComp.Template = release.Spec.CAPI.Template
+	if registryConfig.URL == "" {
+		return nil
 	}
-	components = append(components, capiComp)
+	return helm.ReconcileHelmRepository(ctx, r.Client, kcm.DefaultRepoName, r.SystemNamespace, registryConfig.HelmRepositorySpec())
+}
 
-	const sveltosTargetNamespace = "projectsveltos"
+// reconcileBackupSchedules ensures the scheduled ManagementBackups exist for each of
+// the backup schedules of the Management and removes the ones of the removed schedules.
+func (r *ManagementReconciler) reconcileBackupSchedules(ctx context.Context, mgmt *kcm.Management) error {
+	l := ctrl.LoggerFrom(ctx)
 
-	for _, p := range mgmt.Spec.Providers {
-		c := component{
-			Component: p.Component, helmReleaseName: p.Name,
-			dependsOn: []fluxmeta.NamespacedObjectReference{{Name: kcm.CoreCAPIName}}, isCAPIProvider: true,
+	for _, schedule := range mgmt.Spec.Backups {
+		mb := &kcm.ManagementBackup{ObjectMeta: metav1.ObjectMeta{Name: schedule.Name}}
+		operation, err := ctrl.CreateOrUpdate(ctx, r.Client, mb, func() error {
+			if mb.ResourceVersion != "" && mb.Labels[kcm.ManagementBackupScheduleLabelKey] != mgmt.Name {
+				return fmt.Errorf("ManagementBackup %s already exists and is not managed by the Management", mb.Name)
+			}
+			if mb.Labels == nil {
+				mb.Labels = make(map[string]string)
+			}
+			mb.Labels[kcm.ManagementBackupScheduleLabelKey] = mgmt.Name
+			mb.Spec = kcm.ManagementBackupSpec{
+				Schedule:                   schedule.Schedule,
+				StorageLocation:            schedule.StorageLocation,
+				Retention:                  schedule.Retention,
+				PerformOnManagementUpgrade: schedule.PerformOnManagementUpgrade,
+			}
+			return controllerutil.SetOwnerReference(mgmt, mb, r.Client.Scheme())
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reconcile ManagementBackup %s: %w", schedule.Name, err)
 		}
-		// Try to find corresponding provider in the Release object
-		if c.Template == "" {
-			c.Template = release.ProviderTemplate(p.Name)
+		if operation != controllerutil.OperationResultNone {
+			l.Info("Reconciled scheduled ManagementBackup", "name", mb.Name, "operation", operation)
 		}
+	}
 
-		if p.Name == kcm.ProviderSveltosName {
-			c.isCAPIProvider = false
-			c.targetNamespace = sveltosTargetNamespace
-			c.installSettings = &fluxv2.Install{
-				CreateNamespace: true,
-			}
-		}
+	scheduled := new(kcm.ManagementBackupList)
+	if err := r.Client.List(ctx, scheduled, client.MatchingLabels{kcm.ManagementBackupScheduleLabelKey: mgmt.Name}); err != nil {
+		return fmt.Errorf("failed to list scheduled ManagementBackups: %w", err)
+	}
 
-		components = append(components, c)
+	var errs error
+	for _, mb := range scheduled.Items {
+		if slices.ContainsFunc(mgmt.Spec.Backups, func(s kcm.ManagementBackupSchedule) bool { return s.Name == mb.Name }) {
+			continue
+		}
+		if err := r.Client.Delete(ctx, &mb); client.IgnoreNotFound(err) != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to delete ManagementBackup %s: %w", mb.Name, err))
+			continue
+		}
+		l.Info("Removed ManagementBackup of the removed backup schedule", "name", mb.Name)
 	}
 
-	return components, nil
+	return errs
 }
 
-// enableAdditionalComponents enables the admission controller and cluster api operator
-// once the cert manager is ready
-func (r *ManagementReconciler) enableAdditionalComponents(ctx context.Context, mgmt *kcm.Management) error {
+const (
+	// backupStorageLocationName is the name of the BackupStorageLocation
+	// configured from the backup storage of the Management.
+	backupStorageLocationName = "kcm-default"
+	// backupStorageCredentialsSecretName is the name of the Secret holding
+	// the credentials file of the Velero provider plugin.
+	backupStorageCredentialsSecretName = "kcm-backup-storage-credentials"
+	// backupStorageCredentialsKey is the key of the credentials file in the Secret.
+	backupStorageCredentialsKey = "cloud"
+)
+
+// veleroPlugin is the Velero provider plugin of the object storage.
+type veleroPlugin struct {
+	// provider is the name of the provider of the BackupStorageLocation.
+	provider string
+	// image is the default image of the plugin.
+	image string
+	// credentialsFile builds the credentials file of the plugin from the
+	// identity of the Credential and the Secret of the identity.
+	credentialsFile func(identity *unstructured.Unstructured, secret *corev1.Secret, config map[string]string) ([]byte, error)
+}
+
+// veleroPlugins are the Velero provider plugins by the kinds of the identities of the Credentials.
+var veleroPlugins = map[string]veleroPlugin{
+	"AWSClusterStaticIdentity": {
+		provider: "velero.io/aws",
+		image:    "velero/velero-plugin-for-aws:v1.11.0",
+		credentialsFile: func(_ *unstructured.Unstructured, secret *corev1.Secret, _ map[string]string) ([]byte, error) {
+			accessKeyID, secretAccessKey := secret.Data["AccessKeyID"], secret.Data["SecretAccessKey"]
+			if len(accessKeyID) == 0 || len(secretAccessKey) == 0 {
+				return nil, fmt.Errorf("Secret %s has no AccessKeyID or SecretAccessKey", secret.Name)
+			}
+			file := fmt.Sprintf("[default]\naws_access_key_id=%s\naws_secret_access_key=%s\n", accessKeyID, secretAccessKey)
+			if sessionToken := secret.Data["SessionToken"]; len(sessionToken) > 0 {
+				file += fmt.Sprintf("aws_session_token=%s\n", sessionToken)
+			}
+			return []byte(file), nil
+		},
+	},
+	"AzureClusterIdentity": {
+		provider: "velero.io/azure",
+		image:    "velero/velero-plugin-for-microsoft-azure:v1.11.0",
+		credentialsFile: func(identity *unstructured.Unstructured, secret *corev1.Secret, config map[string]string) ([]byte, error) {
+			if identityType, _, _ := unstructured.NestedString(identity.Object, "spec", "type"); identityType != "ServicePrincipal" {
+				return nil, fmt.Errorf("AzureClusterIdentity %s of the %s type is not supported", identity.GetName(), identityType)
+			}
+			tenantID, _, _ := unstructured.NestedString(identity.Object, "spec", "tenantID")
+			clientID, _, _ := unstructured.NestedString(identity.Object, "spec", "clientID")
+			clientSecret := secret.Data["clientSecret"]
+			if tenantID == "" || clientID == "" || len(clientSecret) == 0 {
+				return nil, fmt.Errorf("AzureClusterIdentity %s has no tenantID, clientID or clientSecret", identity.GetName())
+			}
+			file := fmt.Sprintf("AZURE_TENANT_ID=%s\nAZURE_CLIENT_ID=%s\nAZURE_CLIENT_SECRET=%s\nAZURE_CLOUD_NAME=AzurePublicCloud\n", tenantID, clientID, clientSecret)
+			if subscriptionID := config["subscriptionId"]; subscriptionID != "" {
+				file += fmt.Sprintf("AZURE_SUBSCRIPTION_ID=%s\n", subscriptionID)
+			}
+			return []byte(file), nil
+		},
+	},
+	"Secret": {
+		provider: "velero.io/gcp",
+		image:    "velero/velero-plugin-for-gcp:v1.11.0",
+		credentialsFile: func(_ *unstructured.Unstructured, secret *corev1.Secret, _ map[string]string) ([]byte, error) {
+			// the key of the service account of the GCP Credentials
+			credentials := secret.Data["credentials"]
+			if len(credentials) == 0 {
+				return nil, fmt.Errorf("Secret %s has no credentials of the GCP service account", secret.Name)
+			}
+			return credentials, nil
+		},
+	},
+}
+
+// reconcileBackupStorage configures the Velero provider plugin and the default
+// BackupStorageLocation from the Credential of the backup storage of the
+// Management and removes them once the backup storage is removed.
+func (r *ManagementReconciler) reconcileBackupStorage(ctx context.Context, mgmt *kcm.Management) error {
 	l := ctrl.LoggerFrom(ctx)
 
-	config := make(map[string]any)
+	storage := mgmt.Spec.BackupStorage
+	if storage == nil {
+		var errs error
+		for _, obj := range []client.Object{
+			&velerov1.BackupStorageLocation{ObjectMeta: metav1.ObjectMeta{Name: backupStorageLocationName, Namespace: r.SystemNamespace}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: backupStorageCredentialsSecretName, Namespace: r.SystemNamespace}},
+		} {
+			if err := r.Client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil && !meta.IsNoMatchError(err) {
+				errs = errors.Join(errs, fmt.Errorf("failed to delete %s: %w", client.ObjectKeyFromObject(obj), err))
+			}
+		}
+		return errs
+	}
 
-	if mgmt.Spec.Core == nil {
-		mgmt.Spec.Core = new(kcm.Core)
+	if slices.Contains(mgmt.Spec.DisabledComponents, kcm.OptionalComponentVelero) {
+		return errors.New("backup storage cannot be configured while Velero is disabled")
 	}
-	if mgmt.Spec.Core.KCM.Config != nil {
-		if err := json.Unmarshal(mgmt.Spec.Core.KCM.Config.Raw, &config); err != nil {
-			return fmt.Errorf("failed to unmarshal KCM config into map[string]any: %w", err)
-		}
+
+	cred := new(kcm.Credential)
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: storage.Credential, Namespace: r.SystemNamespace}, cred); err != nil {
+		return fmt.Errorf("failed to get Credential %s of the backup storage: %w", storage.Credential, err)
+	}
+	if cred.Spec.IdentityRef == nil {
+		return fmt.Errorf("Credential %s of the backup storage has no identity", cred.Name)
 	}
 
-	admissionWebhookValues := make(map[string]any)
-	if config["admissionWebhook"] != nil {
-		v, ok := config["admissionWebhook"].(map[string]any)
-		if !ok {
-			return fmt.Errorf("failed to cast 'admissionWebhook' (type %T) to map[string]any", config["admissionWebhook"])
+	plugin, ok := veleroPlugins[cred.Spec.IdentityRef.Kind]
+	if !ok {
+		return fmt.Errorf("identity %s of Credential %s is not supported by the backup storage", cred.Spec.IdentityRef.Kind, cred.Name)
+	}
+
+	identity := new(unstructured.Unstructured)
+	identity.SetAPIVersion(cred.Spec.IdentityRef.APIVersion)
+	identity.SetKind(cred.Spec.IdentityRef.Kind)
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: cred.Spec.IdentityRef.Name, Namespace: cred.Spec.IdentityRef.Namespace}, identity); err != nil {
+		return fmt.Errorf("failed to get identity %s %s of Credential %s: %w", identity.GetKind(), cred.Spec.IdentityRef.Name, cred.Name, err)
+	}
+
+	secretKey := client.ObjectKeyFromObject(identity)
+	if identity.GetKind() != "Secret" {
+		secrets := identitySecrets(identity, r.SystemNamespace)
+		if len(secrets) == 0 {
+			return fmt.Errorf("identity %s %s of Credential %s references no Secret", identity.GetKind(), identity.GetName(), cred.Name)
 		}
+		secretKey = secrets[0]
+	}
+	identitySecret := new(corev1.Secret)
+	if err := r.Client.Get(ctx, secretKey, identitySecret); err != nil {
+		return fmt.Errorf("failed to get Secret %s of Credential %s: %w", secretKey, cred.Name, err)
+	}
 
-		admissionWebhookValues = v
+	credentials, err := plugin.credentialsFile(identity, identitySecret, storage.Config)
+	if err != nil {
+		return fmt.Errorf("failed to build credentials of the backup storage from Credential %s: %w", cred.Name, err)
 	}
 
-	capiOperatorValues := make(map[string]any)
-	if config["cluster-api-operator"] != nil {
-		v, ok := config["cluster-api-operator"].(map[string]any)
-		if !ok {
-			return fmt.Errorf("failed to cast 'cluster-api-operator' (type %T) to map[string]any", config["cluster-api-operator"])
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: backupStorageCredentialsSecretName, Namespace: r.SystemNamespace}}
+	if _, err := controllerutil.CreateOrPatch(ctx, r.Client, secret, func() error {
+		secret.Data = map[string][]byte{backupStorageCredentialsKey: credentials}
+		return controllerutil.SetOwnerReference(mgmt, secret, r.Client.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile backup storage credentials: %w", err)
+	}
+
+	location := &velerov1.BackupStorageLocation{ObjectMeta: metav1.ObjectMeta{Name: backupStorageLocationName, Namespace: r.SystemNamespace}}
+	operation, err := controllerutil.CreateOrPatch(ctx, r.Client, location, func() error {
+		location.Spec.Provider = plugin.provider
+		location.Spec.ObjectStorage = &velerov1.ObjectStorageLocation{Bucket: storage.Bucket, Prefix: storage.Prefix}
+		location.Spec.Credential = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: backupStorageCredentialsSecretName},
+			Key:                  backupStorageCredentialsKey,
+		}
+		location.Spec.Default = true
+		// the rest of the config, e.g. of the encryption, is kept as is
+		if location.Spec.Config == nil {
+			location.Spec.Config = make(map[string]string, len(storage.Config))
 		}
+		maps.Copy(location.Spec.Config, storage.Config)
+		return controllerutil.SetOwnerReference(mgmt, location, r.Client.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile BackupStorageLocation %s: %w", backupStorageLocationName, err)
+	}
+	if operation != controllerutil.OperationResultNone {
+		l.Info("Reconciled BackupStorageLocation of the backup storage", "provider", plugin.provider, "operation", operation)
+	}
 
-		capiOperatorValues = v
+	image := plugin.image
+	if storage.PluginImage != "" {
+		image = storage.PluginImage
+	}
+	return applyVeleroPluginValues(mgmt, image)
+}
+
+// applyVeleroPluginValues adds the init container of the Velero plugin to the
+// Velero values of the KCM config unless the container of the plugin is
+// already configured.
+func applyVeleroPluginValues(mgmt *kcm.Management, image string) error {
+	config := make(map[string]any)
+	if mgmt.Spec.Core != nil && mgmt.Spec.Core.KCM.Config != nil {
+		if err := json.Unmarshal(mgmt.Spec.Core.KCM.Config.Raw, &config); err != nil {
+			return fmt.Errorf("failed to unmarshal KCM config into map[string]any: %w", err)
+		}
 	}
 
+	veleroValues := make(map[string]any)
 	if config["velero"] != nil {
 		v, ok := config["velero"].(map[string]any)
 		if !ok {
 			return fmt.Errorf("failed to cast 'velero' (type %T) to map[string]any", config["velero"])
 		}
 
-		config["velero"] = v
+		veleroValues = v
 	}
 
-	if r.Config != nil {
-		if err := certmanager.VerifyAPI(ctx, r.Config, r.SystemNamespace); err != nil {
-			return fmt.Errorf("failed to check in the cert-manager API is installed: %w", err)
+	var initContainers []any
+	if veleroValues["initContainers"] != nil {
+		v, ok := veleroValues["initContainers"].([]any)
+		if !ok {
+			return fmt.Errorf("failed to cast 'velero.initContainers' (type %T) to []any", veleroValues["initContainers"])
 		}
 
-		l.Info("Cert manager is installed, enabling the KCM admission webhook")
-		admissionWebhookValues["enabled"] = true
+		initContainers = v
 	}
 
-	config["admissionWebhook"] = admissionWebhookValues
-
-	// Enable KCM capi operator only if it was not explicitly disabled in the config to
-	// support installation with existing cluster api operator
-	{
-		enabledV, enabledExists := capiOperatorValues["enabled"]
-		enabledValue, castedOk := enabledV.(bool)
-		if !enabledExists || !castedOk || enabledValue {
-			l.Info("Enabling cluster API operator")
-			capiOperatorValues["enabled"] = true
-		}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.IndexAny(name, ":@"); i >= 0 {
+		name = name[:i]
 	}
-	config["cluster-api-operator"] = capiOperatorValues
+	if slices.ContainsFunc(initContainers, func(c any) bool {
+		container, ok := c.(map[string]any)
+		return ok && container["name"] == name
+	}) {
+		return nil
+	}
+
+	veleroValues["initContainers"] = append(initContainers, map[string]any{
+		"name":            name,
+		"image":           image,
+		"imagePullPolicy": string(corev1.PullIfNotPresent),
+		"volumeMounts":    []any{map[string]any{"mountPath": "/target", "name": "plugins"}},
+	})
+	config["velero"] = veleroValues
 
 	updatedConfig, err := json.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal KCM config: %w", err)
 	}
 
+	if mgmt.Spec.Core == nil {
+		mgmt.Spec.Core = new(kcm.Core)
+	}
 	mgmt.Spec.Core.KCM.Config = &apiextensionsv1.JSON{Raw: updatedConfig}
 
 	return nil
 }
 
+// reconcileObservability ensures the MultiClusterService deploying the collector
+// pointed at the observability backend to the selected clusters exists while
+// the observability is configured and removes it otherwise.
+func (r *ManagementReconciler) reconcileObservability(ctx context.Context, mgmt *kcm.Management) error {
+	l := ctrl.LoggerFrom(ctx)
+
+	mcs := &kcm.MultiClusterService{ObjectMeta: metav1.ObjectMeta{Name: observabilityServiceName}}
+	cfg := mgmt.Spec.Observability
+	if cfg == nil {
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(mcs), mcs); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if mcs.Labels[kcm.KCMManagedLabelKey] != kcm.KCMManagedLabelValue {
+			return nil
+		}
+		if err := r.Client.Delete(ctx, mcs); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete MultiClusterService %s: %w", mcs.Name, err)
+		}
+		l.Info("Removed observability MultiClusterService", "name", mcs.Name)
+		return nil
+	}
+
+	template := cfg.Template
+	if template == "" {
+		template = defaultObservabilityTemplate
+	}
+
+	operation, err := ctrl.CreateOrUpdate(ctx, r.Client, mcs, func() error {
+		if mcs.ResourceVersion != "" && mcs.Labels[kcm.KCMManagedLabelKey] != kcm.KCMManagedLabelValue {
+			return fmt.Errorf("MultiClusterService %s already exists and is not managed by the Management", mcs.Name)
+		}
+		utils.AddLabel(mcs, kcm.KCMManagedLabelKey, kcm.KCMManagedLabelValue)
+		mcs.Spec.ClusterSelector = cfg.ClusterSelector
+		mcs.Spec.ServiceSpec.Services = []kcm.Service{{
+			Name:      observabilityServiceName,
+			Namespace: observabilityServiceName,
+			Template:  template,
+			Values:    observabilityValues(cfg),
+		}}
+		mcs.Spec.ServiceSpec.TemplateResourceRefs = nil
+		if cfg.Backend.AuthorizationSecretRef != "" {
+			mcs.Spec.ServiceSpec.TemplateResourceRefs = []sveltosv1beta1.TemplateResourceRef{{
+				Resource: corev1.ObjectReference{
+					APIVersion: "v1",
+					Kind:       "Secret",
+					Namespace:  r.SystemNamespace,
+					Name:       cfg.Backend.AuthorizationSecretRef,
+				},
+				Identifier: observabilityAuthorizationIdentifier,
+			}}
+		}
+		return controllerutil.SetOwnerReference(mgmt, mcs, r.Client.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile MultiClusterService %s: %w", mcs.Name, err)
+	}
+	if operation != controllerutil.OperationResultNone {
+		l.Info("Reconciled observability MultiClusterService", "name", mcs.Name, "operation", operation)
+	}
+
+	return nil
+}
+
+// observabilityValues returns the values of the collector templated with the
+// name of the cluster and the authorization of the backend upon deployment.
+func observabilityValues(cfg *kcm.Observability) string {
+	values := fmt.Sprintf(`clusterName: '{{ .Cluster.metadata.name }}'
+logs:
+  enabled: %t
+metrics:
+  enabled: %t
+backend:
+  endpoint: %q
+  insecureSkipVerify: %t
+`, !cfg.DisableLogs, !cfg.DisableMetrics, cfg.Backend.Endpoint, cfg.Backend.InsecureSkipVerify)
+	if cfg.Backend.AuthorizationSecretRef != "" {
+		values += fmt.Sprintf("  authorization: '{{ (getResource %q).data.authorization | b64dec }}'\n", observabilityAuthorizationIdentifier)
+	}
+	return values
+}
+
 func (r *ManagementReconciler) ensureUpgradeBackup(ctx context.Context, mgmt *kcm.Management) (requeue bool, _ error) {
 	if mgmt.Status.Release == "" {
 		return false, nil
@@ -845,6 +2729,65 @@ func updateComponentsStatus(
 	}
 }
 
+// setComponentsHealth fills the details of the current components statuses
+// from their HelmReleases and the previous statuses.
+func (r *ManagementReconciler) setComponentsHealth(ctx context.Context, previous, current map[string]kcm.ComponentStatus) {
+	now := metav1.Now()
+	for name, status := range current {
+		hr := new(fluxv2.HelmRelease)
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: r.SystemNamespace, Name: name}, hr); err != nil {
+			if !apierrors.IsNotFound(err) {
+				ctrl.LoggerFrom(ctx).Error(err, "failed to get component HelmRelease", "component", name)
+			}
+			hr = nil
+		}
+		current[name] = componentHealth(previous[name], status, hr, now)
+	}
+}
+
+// componentHealth returns the current status of the component with the
+// conditions and the chart version of its HelmRelease, and the readiness
+// and error timestamps carried over from the previous status.
+func componentHealth(previous, current kcm.ComponentStatus, hr *fluxv2.HelmRelease, now metav1.Time) kcm.ComponentStatus {
+	if hr != nil {
+		current.Conditions = nil
+		for _, c := range hr.Status.Conditions {
+			current.Conditions = append(current.Conditions, *c.DeepCopy())
+		}
+		if latest := hr.Status.History.Latest(); latest != nil {
+			current.Version = latest.ChartVersion
+		}
+	}
+
+	if current.Success {
+		current.ReadySince = &now
+		if previous.Success && previous.ReadySince != nil {
+			current.ReadySince = previous.ReadySince
+		}
+	}
+
+	current.LastError, current.LastErrorTime = previous.LastError, previous.LastErrorTime
+	if current.Error != "" && (current.Error != previous.LastError || previous.LastErrorTime == nil) {
+		current.LastError, current.LastErrorTime = current.Error, &now
+	}
+
+	return current
+}
+
+// recordComponentTransitions emits the events of the components installed or
+// failed since the previous statuses.
+func recordComponentTransitions(mgmt *kcm.Management, previous, current map[string]kcm.ComponentStatus) {
+	for _, name := range slices.Sorted(maps.Keys(current)) {
+		status, prev := current[name], previous[name]
+		switch {
+		case status.Success && !prev.Success:
+			record.Eventf(mgmt, eventReasonComponentInstalled, "Component %s is installed with the template %s", name, status.Template)
+		case !status.Success && prev.Success:
+			record.Warnf(mgmt, eventReasonComponentFailed, "Component %s has failed: %s", name, status.Error)
+		}
+	}
+}
+
 // setReadyCondition updates the Management resource's "Ready" condition based on whether
 // all components are healthy.
 func setReadyCondition(management *kcm.Management) {
@@ -884,7 +2827,13 @@ func (r *ManagementReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.Config = mgr.GetConfig()
 	r.DynamicClient = dc
 
-	r.defaultRequeueTime = 10 * time.Second
+	r.defaultRequeueTime = cmp.Or(r.RequeueInterval, 10*time.Second)
+
+	etcdHealth, err := preflight.EtcdHealth(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	r.preflight = &preflight.Checker{Client: mgr.GetAPIReader(), StorageHealth: etcdHealth, ReleaseCRDVersions: r.releaseCRDVersions}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.TypedOptions[ctrl.Request]{
@@ -15,7 +15,9 @@
 package controller
 
 import (
+	"context"
 	"fmt"
+	"testing"
 	"time"
 
 	helmcontrollerv2 "github.com/fluxcd/helm-controller/api/v2"
@@ -24,18 +26,28 @@ import (
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"helm.sh/helm/v3/pkg/chart"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	capioperator "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/helm"
 	"github.com/K0rdent/kcm/internal/utils"
+	"github.com/K0rdent/kcm/test/scheme"
 )
 
 var _ = Describe("Management Controller", func() {
@@ -300,7 +312,7 @@ var _ = Describe("Management Controller", func() {
 
 			By("Checking the Management components status is populated")
 			Expect(mgmt.Status.Components).To(HaveLen(2)) // required: capi, kcm
-			Expect(mgmt.Status.Components).To(BeEquivalentTo(map[string]kcmv1.ComponentStatus{
+			Expect(basicComponentsStatus(mgmt.Status.Components)).To(BeEquivalentTo(map[string]kcmv1.ComponentStatus{
 				kcmv1.CoreKCMName: {
 					Success:  false,
 					Template: providerTemplateRequiredComponent,
@@ -350,7 +362,7 @@ var _ = Describe("Management Controller", func() {
 
 			By("Checking the Management components status is populated")
 			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mgmt), mgmt)).To(Succeed())
-			Expect(mgmt.Status.Components).To(BeEquivalentTo(map[string]kcmv1.ComponentStatus{
+			Expect(basicComponentsStatus(mgmt.Status.Components)).To(BeEquivalentTo(map[string]kcmv1.ComponentStatus{
 				kcmv1.CoreKCMName: {
 					Success:  true,
 					Template: providerTemplateRequiredComponent,
@@ -361,6 +373,11 @@ var _ = Describe("Management Controller", func() {
 					Error:    fmt.Sprintf("HelmRelease %s/%s Ready condition is not updated yet", helmReleaseNamespace, coreComponents[kcmv1.CoreCAPIName].helmReleaseName),
 				},
 			}))
+			Expect(mgmt.Status.Components[kcmv1.CoreKCMName].ReadySince).NotTo(BeNil())
+			Expect(mgmt.Status.Components[kcmv1.CoreKCMName].LastError).To(Equal(fmt.Sprintf("HelmRelease %s/%s Ready condition is not updated yet", helmReleaseNamespace, coreComponents[kcmv1.CoreKCMName].helmReleaseName)))
+			Expect(mgmt.Status.Components[kcmv1.CoreKCMName].Conditions).To(ContainElement(HaveField("Type", fluxmeta.ReadyCondition)))
+			Expect(mgmt.Status.Components[kcmv1.CoreCAPIName].ReadySince).To(BeNil())
+			Expect(mgmt.Status.Components[kcmv1.CoreCAPIName].LastErrorTime).NotTo(BeNil())
 
 			By("Expecting condition Ready=False Management status")
 			cond := meta.FindStatusCondition(mgmt.Status.Conditions, kcmv1.ReadyCondition)
@@ -442,7 +459,7 @@ var _ = Describe("Management Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mgmt), mgmt)).To(Succeed())
-			Expect(mgmt.Status.Components).To(BeEquivalentTo(map[string]kcmv1.ComponentStatus{
+			Expect(basicComponentsStatus(mgmt.Status.Components)).To(BeEquivalentTo(map[string]kcmv1.ComponentStatus{
 				kcmv1.CoreKCMName:  {Success: true, Template: providerTemplateRequiredComponent},
 				kcmv1.CoreCAPIName: {Success: true, Template: providerTemplateRequiredComponent},
 			}))
@@ -483,5 +500,822 @@ var _ = Describe("Management Controller", func() {
 				return apierrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(coreProvider), &capioperator.CoreProvider{}))
 			}).WithTimeout(timeout).WithPolling(interval).Should(BeTrue())
 		})
+
+		It("should remove the HelmRelease fields set client-side once it is applied server-side", func() {
+			const helmReleaseName = "test-component-csa-upgrade"
+
+			chartRef := &helmcontrollerv2.CrossNamespaceSourceReference{Kind: sourcev1.HelmChartKind, Name: "test-chart", Namespace: metav1.NamespaceDefault}
+
+			By("Creating the HelmRelease client-side")
+			_, _, err := helm.ReconcileHelmRelease(ctx, k8sClient, helmReleaseName, metav1.NamespaceDefault, helm.ReconcileHelmReleaseOpts{
+				ChartRef:  chartRef,
+				Values:    &apiextensionsv1.JSON{Raw: []byte(`{"foo":"bar"}`)},
+				DependsOn: []fluxmeta.NamespacedObjectReference{{Name: kcmv1.CoreCAPIName}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Applying the HelmRelease server-side without the dependencies and the values")
+			hr, _, err := helm.ReconcileHelmRelease(ctx, k8sClient, helmReleaseName, metav1.NamespaceDefault, helm.ReconcileHelmReleaseOpts{
+				ChartRef:     chartRef,
+				FieldManager: managementFieldManager,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(hr), hr)).To(Succeed())
+			Expect(hr.Spec.DependsOn).To(BeEmpty())
+			Expect(hr.Spec.Values).To(BeNil())
+			Expect(hr.Spec.ChartRef).To(Equal(chartRef))
+			for _, f := range hr.ManagedFields {
+				Expect(f.Manager).To(Equal(managementFieldManager))
+			}
+
+			Expect(k8sClient.Delete(ctx, hr)).To(Succeed())
+		})
 	})
 })
+
+func Test_applyFIPSValues(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   *apiextensionsv1.JSON
+		expected string
+	}{
+		{
+			name:     "no config",
+			expected: `{"global":{"fips":true}}`,
+		},
+		{
+			name:     "config is preserved",
+			config:   &apiextensionsv1.JSON{Raw: []byte(`{"foo":"bar","global":{"registry":"example.com"}}`)},
+			expected: `{"foo":"bar","global":{"fips":true,"registry":"example.com"}}`,
+		},
+		{
+			name:     "fips is enforced",
+			config:   &apiextensionsv1.JSON{Raw: []byte(`{"global":{"fips":false}}`)},
+			expected: `{"global":{"fips":true}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := applyFIPSValues(tc.config)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(actual.Raw)).To(MatchJSON(tc.expected))
+		})
+	}
+}
+
+func Test_applyRegistryMirrorValues(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   *apiextensionsv1.JSON
+		mirror   *kcmv1.RegistryMirror
+		expected string
+	}{
+		{
+			name:     "no image settings",
+			config:   &apiextensionsv1.JSON{Raw: []byte(`{"foo":"bar"}`)},
+			mirror:   &kcmv1.RegistryMirror{ChartsURL: "oci://registry.local/charts"},
+			expected: `{"foo":"bar"}`,
+		},
+		{
+			name:     "image registry and pull secret are set",
+			mirror:   &kcmv1.RegistryMirror{ChartsURL: "oci://registry.local/charts", ImageRegistry: "registry.local", CredentialsSecretRef: "mirror-creds"},
+			expected: `{"global":{"imageRegistry":"registry.local","imagePullSecrets":[{"name":"mirror-creds"}]}}`,
+		},
+		{
+			name:     "config is preserved",
+			config:   &apiextensionsv1.JSON{Raw: []byte(`{"foo":"bar","global":{"fips":true,"imageRegistry":"example.com"}}`)},
+			mirror:   &kcmv1.RegistryMirror{ChartsURL: "oci://registry.local/charts", ImageRegistry: "registry.local"},
+			expected: `{"foo":"bar","global":{"fips":true,"imageRegistry":"registry.local"}}`,
+		},
+		{
+			name:     "pull secret is appended",
+			config:   &apiextensionsv1.JSON{Raw: []byte(`{"global":{"imagePullSecrets":[{"name":"global-creds"}]}}`)},
+			mirror:   &kcmv1.RegistryMirror{ChartsURL: "oci://registry.local/charts", CredentialsSecretRef: "mirror-creds"},
+			expected: `{"global":{"imagePullSecrets":[{"name":"global-creds"},{"name":"mirror-creds"}]}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := applyRegistryMirrorValues(tc.config, tc.mirror)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(actual.Raw)).To(MatchJSON(tc.expected))
+		})
+	}
+}
+
+func Test_reviewUpgrade(t *testing.T) {
+	g := NewWithT(t)
+
+	const systemNamespace = "kcm-system"
+
+	kcmTemplate := &kcmv1.ProviderTemplate{ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-2-0"}}
+	kcmTemplate.Status.ChartRef = &helmcontrollerv2.CrossNamespaceSourceReference{Kind: sourcev1.HelmChartKind, Name: "kcm-0-2-0", Namespace: systemNamespace}
+	kcmTemplate.Status.ChartVersion = "0.2.0"
+
+	kcmHelmChart := &sourcev1.HelmChart{ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-2-0", Namespace: systemNamespace}}
+	kcmHelmChart.Status.Artifact = &sourcev1.Artifact{Path: "kcm-0.2.0.tgz"}
+
+	azureTemplate := &kcmv1.ProviderTemplate{ObjectMeta: metav1.ObjectMeta{Name: "cluster-api-provider-azure-0-1-0"}}
+	azureTemplate.Status.Providers = kcmv1.Providers{"infrastructure-azure"}
+
+	azureClusterTemplate := &kcmv1.ClusterTemplate{ObjectMeta: metav1.ObjectMeta{Name: "azure-standalone-cp-0-1-0", Namespace: "team"}}
+	azureClusterTemplate.Status.Providers = kcmv1.Providers{"infrastructure-azure"}
+
+	crd := func(name string, versions ...string) *apiextensionsv1.CustomResourceDefinition {
+		crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, v := range versions {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: v})
+		}
+		return crd
+	}
+
+	mgmt := &kcmv1.Management{
+		ObjectMeta: metav1.ObjectMeta{Name: kcmv1.ManagementName, Generation: 2},
+		Spec:       kcmv1.ManagementSpec{Release: "kcm-0-2-0", UpgradeDryRun: true},
+		Status: kcmv1.ManagementStatus{
+			Release: "kcm-0-1-0",
+			Components: map[string]kcmv1.ComponentStatus{
+				kcmv1.CoreKCMName:            {Template: "kcm-0-1-0", Version: "0.1.0"},
+				kcmv1.CoreCAPIName:           {Template: "cluster-api-0-1-0", Version: "0.1.0"},
+				"cluster-api-provider-azure": {Template: azureTemplate.Name, Version: "0.1.0"},
+			},
+		},
+	}
+
+	r := &ManagementReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			&kcmv1.Release{
+				ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-2-0"},
+				Spec: kcmv1.ReleaseSpec{
+					KCM:  kcmv1.CoreProviderTemplate{Template: kcmTemplate.Name},
+					CAPI: kcmv1.CoreProviderTemplate{Template: "cluster-api-0-1-0"},
+				},
+			},
+			kcmTemplate, kcmHelmChart, azureTemplate, azureClusterTemplate,
+			&kcmv1.ClusterDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: "team"},
+				Spec:       kcmv1.ClusterDeploymentSpec{Template: azureClusterTemplate.Name},
+			},
+			crd("managements.k0rdent.mirantis.com", "v1alpha1"),
+		).WithIndex(&kcmv1.ClusterDeployment{}, kcmv1.ClusterDeploymentTemplateIndexKey, kcmv1.ExtractTemplateNameFromClusterDeployment).Build(),
+		SystemNamespace: systemNamespace,
+		downloadHelmChartFunc: func(context.Context, *sourcev1.Artifact) (*chart.Chart, error) {
+			return &chart.Chart{
+				Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "kcm", Version: "0.2.0"},
+				Templates: []*chart.File{{Name: "templates/crds.yaml", Data: []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: managements.k0rdent.mirantis.com
+spec:
+  versions:
+  - name: v1alpha1
+  - name: v1beta1
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: regions.k0rdent.mirantis.com
+spec:
+  versions:
+  - name: v1alpha1
+`)}},
+			}, nil
+		},
+	}
+
+	approved, err := r.reviewUpgrade(t.Context(), mgmt)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(approved).To(BeFalse())
+	g.Expect(meta.IsStatusConditionFalse(mgmt.Status.Conditions, kcmv1.UpgradeApprovedCondition)).To(BeTrue())
+	g.Expect(mgmt.Status.UpgradeReport).To(Equal(&kcmv1.UpgradeReport{
+		Release: "kcm-0-2-0",
+		Components: []kcmv1.ComponentChange{
+			{Name: kcmv1.CoreKCMName, FromTemplate: "kcm-0-1-0", ToTemplate: "kcm-0-2-0", FromChartVersion: "0.1.0", ToChartVersion: "0.2.0"},
+			{Name: "cluster-api-provider-azure", FromTemplate: azureTemplate.Name, FromChartVersion: "0.1.0"},
+		},
+		CRDs: []kcmv1.CRDChange{
+			{Name: "managements.k0rdent.mirantis.com", Component: kcmv1.CoreKCMName, AddedVersions: []string{"v1beta1"}},
+			{Name: "regions.k0rdent.mirantis.com", Component: kcmv1.CoreKCMName, Added: true},
+		},
+		AffectedClusters: []string{"team/dev"},
+	}))
+
+	mgmt.Annotations = map[string]string{kcmv1.ReleaseApprovalAnnotation: "kcm-0-2-0"}
+	approved, err = r.reviewUpgrade(t.Context(), mgmt)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(approved).To(BeTrue())
+	g.Expect(meta.IsStatusConditionTrue(mgmt.Status.Conditions, kcmv1.UpgradeApprovedCondition)).To(BeTrue())
+}
+
+func Test_renderComponentChart(t *testing.T) {
+	g := NewWithT(t)
+
+	const systemNamespace = "kcm-system"
+
+	template := &kcmv1.ProviderTemplate{ObjectMeta: metav1.ObjectMeta{Name: "cluster-api-provider-aws-0-1-0"}}
+	template.Status.ChartRef = &helmcontrollerv2.CrossNamespaceSourceReference{Kind: sourcev1.HelmChartKind, Name: template.Name, Namespace: systemNamespace}
+
+	helmChart := &sourcev1.HelmChart{ObjectMeta: metav1.ObjectMeta{Name: template.Name, Namespace: systemNamespace}}
+	helmChart.Status.Artifact = &sourcev1.Artifact{Path: "aws-0.1.0.tgz", Digest: "sha256:1"}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(template, helmChart).WithStatusSubresource(helmChart).Build()
+
+	downloads := 0
+	r := &ManagementReconciler{
+		Client:          cl,
+		SystemNamespace: systemNamespace,
+		downloadHelmChartFunc: func(context.Context, *sourcev1.Artifact) (*chart.Chart, error) {
+			downloads++
+			return &chart.Chart{
+				Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "aws", Version: "0.1.0"},
+				Templates: []*chart.File{{Name: "templates/cm.yaml", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Values.name }}
+data:
+  image: registry.local/{{ .Values.name }}:1
+`)}},
+			}, nil
+		},
+	}
+
+	comp := component{
+		Component:       kcmv1.Component{Config: &apiextensionsv1.JSON{Raw: []byte(`{"name":"a"}`)}},
+		helmReleaseName: "cluster-api-provider-aws",
+	}
+
+	render := func() map[string]string {
+		manifests, err := r.renderComponentChart(t.Context(), template, comp)
+		g.Expect(err).NotTo(HaveOccurred())
+		return manifests
+	}
+
+	g.Expect(render()).To(HaveKeyWithValue("aws/templates/cm.yaml", ContainSubstring("name: a")))
+	g.Expect(render()).To(HaveKeyWithValue("aws/templates/cm.yaml", ContainSubstring("name: a")))
+	g.Expect(downloads).To(Equal(1), "the unchanged chart should not be rendered again")
+
+	comp.Config = &apiextensionsv1.JSON{Raw: []byte(`{"name":"b"}`)}
+	g.Expect(render()).To(HaveKeyWithValue("aws/templates/cm.yaml", ContainSubstring("name: b")))
+	g.Expect(downloads).To(Equal(2), "the chart should be rendered again once the configuration changes")
+
+	helmChart.Status.Artifact.Digest = "sha256:2"
+	g.Expect(cl.Status().Update(t.Context(), helmChart)).To(Succeed())
+	render()
+	g.Expect(downloads).To(Equal(3), "the chart should be rendered again once the artifact changes")
+
+	comp.targetNamespace = "capa-system"
+	render()
+	g.Expect(downloads).To(Equal(4), "the chart should be rendered again once the target namespace changes")
+
+	comp.targetNamespace = ""
+	render()
+	g.Expect(downloads).To(Equal(4), "the previous chart of the component should be kept")
+
+	rendered, err := r.renderComponent(t.Context(), template, comp)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(r.renderComponent(t.Context(), template, comp)).To(BeIdenticalTo(rendered), "the images of the unchanged chart should be extracted once")
+	g.Expect(rendered.Images()).To(Equal([]string{"registry.local/b:1"}))
+}
+
+func Test_sortComponents(t *testing.T) {
+	g := NewWithT(t)
+
+	dependsOn := func(names ...string) []fluxmeta.NamespacedObjectReference {
+		refs := make([]fluxmeta.NamespacedObjectReference, 0, len(names))
+		for _, name := range names {
+			refs = append(refs, fluxmeta.NamespacedObjectReference{Name: name})
+		}
+		return refs
+	}
+
+	sorted, err := sortComponents([]component{
+		{helmReleaseName: kcmv1.CoreKCMName},
+		{helmReleaseName: kcmv1.CoreCAPIName, dependsOn: dependsOn(kcmv1.CoreKCMName)},
+		{helmReleaseName: "cluster-api-provider-openstack", dependsOn: dependsOn(kcmv1.CoreCAPIName, "cluster-api-provider-k0sproject-k0smotron")},
+		{helmReleaseName: "cluster-api-provider-aws", dependsOn: dependsOn(kcmv1.CoreCAPIName)},
+		{helmReleaseName: "cluster-api-provider-k0sproject-k0smotron", dependsOn: dependsOn(kcmv1.CoreCAPIName)},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	names := make([]string, 0, len(sorted))
+	for _, c := range sorted {
+		names = append(names, c.helmReleaseName)
+	}
+	g.Expect(names).To(Equal([]string{
+		kcmv1.CoreKCMName, kcmv1.CoreCAPIName, "cluster-api-provider-aws",
+		"cluster-api-provider-k0sproject-k0smotron", "cluster-api-provider-openstack",
+	}))
+
+	_, err = sortComponents([]component{
+		{helmReleaseName: kcmv1.CoreKCMName},
+		{helmReleaseName: "cluster-api-provider-aws", dependsOn: dependsOn(kcmv1.CoreCAPIName)},
+	})
+	g.Expect(err).To(MatchError("failed to order the components: cluster-api-provider-aws depends on capi which is not installed"))
+}
+
+func Test_operatorProviders(t *testing.T) {
+	g := NewWithT(t)
+
+	const (
+		systemNamespace = "kcm-system"
+		awsName         = "cluster-api-provider-aws"
+	)
+
+	mgmt := &kcmv1.Management{ObjectMeta: metav1.ObjectMeta{Name: kcmv1.ManagementName, UID: "mgmt-uid"}}
+	hr := &helmcontrollerv2.HelmRelease{ObjectMeta: metav1.ObjectMeta{Name: awsName, Namespace: systemNamespace}}
+	helmStorage := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name: "sh.helm.release.v1." + awsName + ".v1", Namespace: systemNamespace,
+		Labels: map[string]string{"owner": "helm", "name": awsName},
+	}}
+	otherHelmStorage := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name: "sh.helm.release.v1.capi.v1", Namespace: systemNamespace,
+		Labels: map[string]string{"owner": "helm", "name": "capi"},
+	}}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(mgmt, hr, helmStorage, otherHelmStorage).Build()
+	r := &ManagementReconciler{Client: cl, SystemNamespace: systemNamespace}
+
+	manifests := map[string]string{
+		"provider.yaml": `apiVersion: operator.cluster.x-k8s.io/v1alpha2
+kind: InfrastructureProvider
+metadata:
+  name: aws
+spec:
+  version: v2.7.1
+  configSecret:
+    name: aws-variables
+`,
+		"secret.yaml": `apiVersion: v1
+kind: Secret
+metadata:
+  name: aws-variables
+stringData:
+  AWS_B64ENCODED_CREDENTIALS: ""
+`,
+	}
+
+	comp := component{helmReleaseName: awsName, isCAPIProvider: true, operatorManaged: true}
+	g.Expect(r.reconcileOperatorProviders(t.Context(), mgmt, comp, manifests)).To(Succeed())
+
+	err := cl.Get(t.Context(), client.ObjectKeyFromObject(hr), hr)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue(), "the HelmRelease should be removed")
+	err = cl.Get(t.Context(), client.ObjectKeyFromObject(helmStorage), helmStorage)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue(), "the Helm storage of the release should be removed")
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(otherHelmStorage), otherHelmStorage)).To(Succeed())
+
+	provider := new(capioperator.InfrastructureProvider)
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: "aws"}, provider)).To(Succeed())
+	g.Expect(provider.Spec.Version).To(Equal("v2.7.1"))
+	g.Expect(provider.Labels).To(HaveKeyWithValue(kcmv1.ProviderComponentLabelKey, awsName))
+	g.Expect(provider.OwnerReferences).To(HaveLen(1))
+	g.Expect(provider.OwnerReferences[0].UID).To(Equal(mgmt.UID))
+
+	secret := new(corev1.Secret)
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: "aws-variables"}, secret)).To(Succeed())
+	g.Expect(secret.Labels).To(HaveKeyWithValue(kcmv1.ProviderComponentLabelKey, awsName))
+	g.Expect(secret.StringData).To(BeEmpty())
+	g.Expect(secret.Data).To(HaveKeyWithValue("AWS_B64ENCODED_CREDENTIALS", BeEmpty()))
+
+	// the unchanged objects are not updated
+	g.Expect(r.reconcileOperatorProviders(t.Context(), mgmt, comp, manifests)).To(Succeed())
+	unchanged := new(corev1.Secret)
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(secret), unchanged)).To(Succeed())
+	g.Expect(unchanged.ResourceVersion).To(Equal(secret.ResourceVersion))
+
+	// the objects of the active components are kept
+	g.Expect(r.cleanupOperatorProviders(t.Context(), []component{comp})).To(Succeed())
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(provider), provider)).To(Succeed())
+	g.Expect(provider.Labels).To(HaveKey(kcmv1.ProviderComponentLabelKey))
+
+	// the objects of the components switched to Helm are handed over to their HelmReleases
+	g.Expect(r.cleanupOperatorProviders(t.Context(), []component{{helmReleaseName: awsName, isCAPIProvider: true}})).To(Succeed())
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(provider), provider)).To(Succeed())
+	g.Expect(provider.Labels).NotTo(HaveKey(kcmv1.ProviderComponentLabelKey))
+	g.Expect(provider.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "Helm"))
+	g.Expect(provider.Annotations).To(HaveKeyWithValue("meta.helm.sh/release-name", awsName))
+	g.Expect(provider.Annotations).To(HaveKeyWithValue("meta.helm.sh/release-namespace", systemNamespace))
+	g.Expect(provider.OwnerReferences).To(BeEmpty())
+
+	// the objects of the removed components are deleted
+	g.Expect(r.reconcileOperatorProviders(t.Context(), mgmt, comp, manifests)).To(Succeed())
+	g.Expect(r.cleanupOperatorProviders(t.Context(), nil)).To(Succeed())
+	err = cl.Get(t.Context(), client.ObjectKeyFromObject(provider), provider)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue(), "the provider should be removed")
+	err = cl.Get(t.Context(), client.ObjectKeyFromObject(secret), secret)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue(), "the secret should be removed")
+}
+
+func Test_applyGlobalValues(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   *apiextensionsv1.JSON
+		global   *kcmv1.GlobalValues
+		expected string
+	}{
+		{
+			name:     "no global values",
+			config:   &apiextensionsv1.JSON{Raw: []byte(`{"foo":"bar"}`)},
+			global:   &kcmv1.GlobalValues{},
+			expected: `{"foo":"bar"}`,
+		},
+		{
+			name: "global values are set",
+			global: &kcmv1.GlobalValues{
+				NodeSelector:      map[string]string{"node-role.kubernetes.io/infra": ""},
+				PriorityClassName: "system-cluster-critical",
+				Tolerations:       []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+				ImagePullSecrets:  []corev1.LocalObjectReference{{Name: "creds"}},
+			},
+			expected: `{"global":{"nodeSelector":{"node-role.kubernetes.io/infra":""},"priorityClassName":"system-cluster-critical",` +
+				`"tolerations":[{"key":"infra","operator":"Exists","effect":"NoSchedule"}],"imagePullSecrets":[{"name":"creds"}]}}`,
+		},
+		{
+			name:   "config takes precedence",
+			config: &apiextensionsv1.JSON{Raw: []byte(`{"foo":"bar","global":{"fips":true,"priorityClassName":"custom"}}`)},
+			global: &kcmv1.GlobalValues{
+				PriorityClassName: "system-cluster-critical",
+				Resources:         &corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")}},
+			},
+			expected: `{"foo":"bar","global":{"fips":true,"priorityClassName":"custom","resources":{"limits":{"memory":"256Mi"}}}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := applyGlobalValues(tc.config, tc.global)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(actual.Raw)).To(MatchJSON(tc.expected))
+		})
+	}
+}
+
+func Test_managementNetworkPolicySpec(t *testing.T) {
+	g := NewWithT(t)
+
+	tcp := corev1.ProtocolTCP
+
+	spec := managementNetworkPolicySpec(&kcmv1.NetworkPolicies{})
+	g.Expect(spec.PodSelector).To(Equal(metav1.LabelSelector{}))
+	g.Expect(spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+	g.Expect(spec.Egress).To(Equal([]networkingv1.NetworkPolicyEgressRule{{}}))
+	g.Expect(spec.Ingress).To(Equal([]networkingv1.NetworkPolicyIngressRule{
+		{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}},
+		{Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: &tcp, Port: utils.PtrTo(intstr.FromInt32(9443))},
+			{Protocol: &tcp, Port: utils.PtrTo(intstr.FromInt32(10250))},
+		}},
+	}))
+
+	monitoring := &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "monitoring"}}
+	spec = managementNetworkPolicySpec(&kcmv1.NetworkPolicies{IngressNamespaceSelector: monitoring, WebhookPorts: []int32{8443}})
+	g.Expect(spec.Ingress).To(Equal([]networkingv1.NetworkPolicyIngressRule{
+		{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}, {NamespaceSelector: monitoring}}},
+		{Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: utils.PtrTo(intstr.FromInt32(8443))}}},
+	}))
+}
+
+// basicComponentsStatus strips the health details off the given components statuses.
+func basicComponentsStatus(components map[string]kcmv1.ComponentStatus) map[string]kcmv1.ComponentStatus {
+	basic := make(map[string]kcmv1.ComponentStatus, len(components))
+	for name, c := range components {
+		basic[name] = kcmv1.ComponentStatus{Template: c.Template, Error: c.Error, Success: c.Success}
+	}
+	return basic
+}
+
+func Test_componentHealth(t *testing.T) {
+	g := NewWithT(t)
+
+	before := metav1.NewTime(time.Now().Add(-time.Hour))
+	now := metav1.Now()
+
+	hr := &helmcontrollerv2.HelmRelease{Status: helmcontrollerv2.HelmReleaseStatus{
+		Conditions: []metav1.Condition{{Type: fluxmeta.ReadyCondition, Status: metav1.ConditionTrue}},
+		History:    helmcontrollerv2.Snapshots{{Version: 1, ChartVersion: "0.1.0"}, {Version: 2, ChartVersion: "0.2.0"}},
+	}}
+
+	status := componentHealth(kcmv1.ComponentStatus{}, kcmv1.ComponentStatus{Error: "not ready"}, nil, now)
+	g.Expect(status.ReadySince).To(BeNil())
+	g.Expect(status.LastError).To(Equal("not ready"))
+	g.Expect(status.LastErrorTime).To(Equal(&now))
+
+	previous := kcmv1.ComponentStatus{Error: "not ready", LastError: "not ready", LastErrorTime: &before}
+	status = componentHealth(previous, kcmv1.ComponentStatus{Error: "not ready"}, nil, now)
+	g.Expect(status.LastErrorTime).To(Equal(&before))
+
+	status = componentHealth(previous, kcmv1.ComponentStatus{Success: true}, hr, now)
+	g.Expect(status.ReadySince).To(Equal(&now))
+	g.Expect(status.LastError).To(Equal("not ready"))
+	g.Expect(status.LastErrorTime).To(Equal(&before))
+	g.Expect(status.Version).To(Equal("0.2.0"))
+	g.Expect(status.Conditions).To(Equal(hr.Status.Conditions))
+
+	previous = kcmv1.ComponentStatus{Success: true, ReadySince: &before}
+	status = componentHealth(previous, kcmv1.ComponentStatus{Success: true}, hr, now)
+	g.Expect(status.ReadySince).To(Equal(&before))
+}
+
+func Test_reconcileBackupSchedules(t *testing.T) {
+	g := NewWithT(t)
+
+	mgmt := &kcmv1.Management{
+		ObjectMeta: metav1.ObjectMeta{Name: kcmv1.ManagementName},
+		Spec: kcmv1.ManagementSpec{Backups: []kcmv1.ManagementBackupSchedule{
+			{Name: "daily", Schedule: "@daily", Retention: &metav1.Duration{Duration: 72 * time.Hour}},
+		}},
+	}
+
+	scheduled := func(name string) *kcmv1.ManagementBackup {
+		return &kcmv1.ManagementBackup{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{kcmv1.ManagementBackupScheduleLabelKey: mgmt.Name},
+		}}
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		mgmt,
+		scheduled("hourly"),
+		&kcmv1.ManagementBackup{ObjectMeta: metav1.ObjectMeta{Name: "manual"}},
+	).Build()
+	r := &ManagementReconciler{Client: cl}
+
+	g.Expect(r.reconcileBackupSchedules(t.Context(), mgmt)).To(Succeed())
+
+	backups := new(kcmv1.ManagementBackupList)
+	g.Expect(cl.List(t.Context(), backups)).To(Succeed())
+	g.Expect(backups.Items).To(HaveLen(2))
+
+	daily := new(kcmv1.ManagementBackup)
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Name: "daily"}, daily)).To(Succeed())
+	g.Expect(daily.Labels).To(HaveKeyWithValue(kcmv1.ManagementBackupScheduleLabelKey, mgmt.Name))
+	g.Expect(daily.OwnerReferences).To(HaveLen(1))
+	g.Expect(daily.Spec.Schedule).To(Equal("@daily"))
+	g.Expect(daily.Spec.Retention).To(Equal(&metav1.Duration{Duration: 72 * time.Hour}))
+
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Name: "manual"}, new(kcmv1.ManagementBackup))).To(Succeed())
+
+	// updated schedule
+	mgmt.Spec.Backups[0].Schedule = "0 3 * * *"
+	mgmt.Spec.Backups[0].StorageLocation = "s3"
+	g.Expect(r.reconcileBackupSchedules(t.Context(), mgmt)).To(Succeed())
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Name: "daily"}, daily)).To(Succeed())
+	g.Expect(daily.Spec.Schedule).To(Equal("0 3 * * *"))
+	g.Expect(daily.Spec.StorageLocation).To(Equal("s3"))
+
+	// existing unmanaged ManagementBackup
+	mgmt.Spec.Backups = append(mgmt.Spec.Backups, kcmv1.ManagementBackupSchedule{Name: "manual", Schedule: "@weekly"})
+	g.Expect(r.reconcileBackupSchedules(t.Context(), mgmt)).To(MatchError(ContainSubstring("ManagementBackup manual already exists and is not managed by the Management")))
+}
+
+func Test_reconcileBackupStorage(t *testing.T) {
+	g := NewWithT(t)
+
+	const systemNamespace = "kcm-system"
+
+	mgmt := &kcmv1.Management{
+		ObjectMeta: metav1.ObjectMeta{Name: kcmv1.ManagementName},
+		Spec: kcmv1.ManagementSpec{BackupStorage: &kcmv1.BackupStorage{
+			Credential: "aws-credential",
+			Bucket:     "backups",
+			Prefix:     "mgmt",
+			Config:     map[string]string{"region": "us-east-1"},
+		}},
+	}
+	identity := &unstructured.Unstructured{}
+	identity.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta2")
+	identity.SetKind("AWSClusterStaticIdentity")
+	identity.SetName("aws-identity")
+	g.Expect(unstructured.SetNestedField(identity.Object, "aws-identity-secret", "spec", "secretRef")).To(Succeed())
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		mgmt,
+		identity,
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: "aws-identity-secret"},
+			Data:       map[string][]byte{"AccessKeyID": []byte("id"), "SecretAccessKey": []byte("key")},
+		},
+		&kcmv1.Credential{
+			ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: "aws-credential"},
+			Spec: kcmv1.CredentialSpec{IdentityRef: &corev1.ObjectReference{
+				APIVersion: identity.GetAPIVersion(), Kind: identity.GetKind(), Name: identity.GetName(),
+			}},
+		},
+		&velerov1.BackupStorageLocation{
+			ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: backupStorageLocationName},
+			Spec:       velerov1.BackupStorageLocationSpec{Config: map[string]string{"kmsKeyId": "key"}},
+		},
+	).Build()
+	r := &ManagementReconciler{Client: cl, SystemNamespace: systemNamespace}
+
+	g.Expect(r.reconcileBackupStorage(t.Context(), mgmt)).To(Succeed())
+
+	secret := new(corev1.Secret)
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: backupStorageCredentialsSecretName}, secret)).To(Succeed())
+	g.Expect(string(secret.Data[backupStorageCredentialsKey])).To(Equal("[default]\naws_access_key_id=id\naws_secret_access_key=key\n"))
+
+	location := new(velerov1.BackupStorageLocation)
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: backupStorageLocationName}, location)).To(Succeed())
+	g.Expect(location.Spec.Provider).To(Equal("velero.io/aws"))
+	g.Expect(location.Spec.Default).To(BeTrue())
+	g.Expect(location.Spec.ObjectStorage).To(Equal(&velerov1.ObjectStorageLocation{Bucket: "backups", Prefix: "mgmt"}))
+	g.Expect(location.Spec.Config).To(Equal(map[string]string{"region": "us-east-1", "kmsKeyId": "key"}))
+	g.Expect(location.Spec.Credential.Name).To(Equal(backupStorageCredentialsSecretName))
+	g.Expect(location.OwnerReferences).To(HaveLen(1))
+
+	g.Expect(mgmt.Spec.Core).NotTo(BeNil())
+	g.Expect(string(mgmt.Spec.Core.KCM.Config.Raw)).To(MatchJSON(`{"velero":{"initContainers":[{
+		"name":"velero-plugin-for-aws","image":"velero/velero-plugin-for-aws:v1.11.0","imagePullPolicy":"IfNotPresent",
+		"volumeMounts":[{"mountPath":"/target","name":"plugins"}]}]}}`))
+
+	// the configured plugin is kept as is
+	mgmt.Spec.Core.KCM.Config = &apiextensionsv1.JSON{Raw: []byte(`{"velero":{"initContainers":[{"name":"velero-plugin-for-aws","image":"mirror/velero-plugin-for-aws:v1.11.0"}]}}`)}
+	g.Expect(r.reconcileBackupStorage(t.Context(), mgmt)).To(Succeed())
+	g.Expect(string(mgmt.Spec.Core.KCM.Config.Raw)).To(MatchJSON(`{"velero":{"initContainers":[{"name":"velero-plugin-for-aws","image":"mirror/velero-plugin-for-aws:v1.11.0"}]}}`))
+
+	// unsupported identity
+	mgmt.Spec.BackupStorage.Credential = "vsphere-credential"
+	g.Expect(cl.Create(t.Context(), &kcmv1.Credential{
+		ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: "vsphere-credential"},
+		Spec:       kcmv1.CredentialSpec{IdentityRef: &corev1.ObjectReference{Kind: "VSphereClusterIdentity", Name: "vsphere"}},
+	})).To(Succeed())
+	g.Expect(r.reconcileBackupStorage(t.Context(), mgmt)).To(MatchError(ContainSubstring("is not supported by the backup storage")))
+
+	// removed backup storage
+	mgmt.Spec.BackupStorage = nil
+	g.Expect(r.reconcileBackupStorage(t.Context(), mgmt)).To(Succeed())
+	g.Expect(apierrors.IsNotFound(cl.Get(t.Context(), client.ObjectKeyFromObject(location), location))).To(BeTrue())
+	g.Expect(apierrors.IsNotFound(cl.Get(t.Context(), client.ObjectKeyFromObject(secret), secret))).To(BeTrue())
+}
+
+func Test_reconcileObservability(t *testing.T) {
+	g := NewWithT(t)
+
+	mgmt := &kcmv1.Management{
+		ObjectMeta: metav1.ObjectMeta{Name: kcmv1.ManagementName},
+		Spec: kcmv1.ManagementSpec{Observability: &kcmv1.Observability{
+			ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			Backend:         kcmv1.ObservabilityBackend{Endpoint: "https://otlp.example.com"},
+			DisableLogs:     true,
+		}},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(mgmt).Build()
+	r := &ManagementReconciler{Client: cl, SystemNamespace: "kcm-system"}
+
+	g.Expect(r.reconcileObservability(t.Context(), mgmt)).To(Succeed())
+
+	mcs := new(kcmv1.MultiClusterService)
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Name: observabilityServiceName}, mcs)).To(Succeed())
+	g.Expect(mcs.Labels).To(HaveKeyWithValue(kcmv1.KCMManagedLabelKey, kcmv1.KCMManagedLabelValue))
+	g.Expect(mcs.OwnerReferences).To(HaveLen(1))
+	g.Expect(mcs.Spec.ClusterSelector.MatchLabels).To(Equal(map[string]string{"env": "prod"}))
+	g.Expect(mcs.Spec.ServiceSpec.TemplateResourceRefs).To(BeEmpty())
+	g.Expect(mcs.Spec.ServiceSpec.Services).To(HaveLen(1))
+	service := mcs.Spec.ServiceSpec.Services[0]
+	g.Expect(service.Template).To(Equal(defaultObservabilityTemplate))
+	g.Expect(service.Namespace).To(Equal(observabilityServiceName))
+	g.Expect(service.Values).To(Equal(`clusterName: '{{ .Cluster.metadata.name }}'
+logs:
+  enabled: false
+metrics:
+  enabled: true
+backend:
+  endpoint: "https://otlp.example.com"
+  insecureSkipVerify: false
+`))
+
+	// authorization of the backend
+	mgmt.Spec.Observability.Backend.AuthorizationSecretRef = "otlp-auth"
+	g.Expect(r.reconcileObservability(t.Context(), mgmt)).To(Succeed())
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Name: observabilityServiceName}, mcs)).To(Succeed())
+	g.Expect(mcs.Spec.ServiceSpec.Services[0].Values).To(HaveSuffix(`  authorization: '{{ (getResource "ObservabilityAuthorization").data.authorization | b64dec }}'` + "\n"))
+	g.Expect(mcs.Spec.ServiceSpec.TemplateResourceRefs).To(HaveLen(1))
+	g.Expect(mcs.Spec.ServiceSpec.TemplateResourceRefs[0].Resource.Namespace).To(Equal("kcm-system"))
+	g.Expect(mcs.Spec.ServiceSpec.TemplateResourceRefs[0].Resource.Name).To(Equal("otlp-auth"))
+
+	// removed configuration
+	mgmt.Spec.Observability = nil
+	g.Expect(r.reconcileObservability(t.Context(), mgmt)).To(Succeed())
+	g.Expect(apierrors.IsNotFound(cl.Get(t.Context(), client.ObjectKey{Name: observabilityServiceName}, mcs))).To(BeTrue())
+
+	// existing unmanaged MultiClusterService
+	g.Expect(cl.Create(t.Context(), &kcmv1.MultiClusterService{ObjectMeta: metav1.ObjectMeta{Name: observabilityServiceName}})).To(Succeed())
+	g.Expect(r.reconcileObservability(t.Context(), mgmt)).To(Succeed())
+	g.Expect(cl.Get(t.Context(), client.ObjectKey{Name: observabilityServiceName}, mcs)).To(Succeed())
+
+	mgmt.Spec.Observability = &kcmv1.Observability{Backend: kcmv1.ObservabilityBackend{Endpoint: "https://otlp.example.com"}}
+	g.Expect(r.reconcileObservability(t.Context(), mgmt)).To(MatchError(ContainSubstring("MultiClusterService kcm-observability already exists and is not managed by the Management")))
+}
+
+func Test_applyDebugValues(t *testing.T) {
+	g := NewWithT(t)
+
+	config := map[string]any{
+		"controller": map[string]any{"createManagement": true},
+	}
+	g.Expect(applyDebugValues(config, nil)).To(Succeed())
+	g.Expect(config["controller"]).NotTo(HaveKey("debug"))
+
+	g.Expect(applyDebugValues(config, &kcmv1.Debug{Pprof: true, BlockProfileRate: 1000})).To(Succeed())
+	g.Expect(config).To(Equal(map[string]any{
+		"controller": map[string]any{
+			"createManagement": true,
+			"debug":            map[string]any{"pprofBindAddress": "127.0.0.1:6060", "blockProfileRate": int32(1000), "mutexProfileFraction": int32(0)},
+		},
+	}))
+
+	g.Expect(applyDebugValues(map[string]any{"controller": "invalid"}, &kcmv1.Debug{})).To(MatchError(ContainSubstring("failed to cast 'controller'")))
+}
+
+func Test_applyConcurrencyValues(t *testing.T) {
+	g := NewWithT(t)
+
+	config := map[string]any{
+		"controller": map[string]any{"createManagement": true, "concurrency": map[string]any{"clusterDeployment": 1}},
+	}
+	g.Expect(applyConcurrencyValues(config, nil)).To(Succeed())
+	g.Expect(config["controller"]).To(HaveKeyWithValue("concurrency", map[string]any{"clusterDeployment": 1}))
+
+	g.Expect(applyConcurrencyValues(config, &kcmv1.Concurrency{
+		ClusterDeployment:   utils.PtrTo[int32](10),
+		MultiClusterService: utils.PtrTo[int32](4),
+	})).To(Succeed())
+	g.Expect(config).To(Equal(map[string]any{
+		"controller": map[string]any{
+			"createManagement": true,
+			"concurrency":      map[string]any{"clusterDeployment": int32(10), "multiClusterService": int32(4)},
+		},
+	}))
+
+	g.Expect(applyConcurrencyValues(map[string]any{"controller": "invalid"}, &kcmv1.Concurrency{})).To(MatchError(ContainSubstring("failed to cast 'controller'")))
+}
+
+func Test_applyIntervalsValues(t *testing.T) {
+	g := NewWithT(t)
+
+	config := map[string]any{
+		"controller": map[string]any{"createManagement": true, "intervals": map[string]any{"resync": "10h0m0s"}},
+	}
+	g.Expect(applyIntervalsValues(config, nil)).To(Succeed())
+	g.Expect(config["controller"]).To(HaveKeyWithValue("intervals", map[string]any{"resync": "10h0m0s"}))
+
+	g.Expect(applyIntervalsValues(config, &kcmv1.Intervals{
+		ClusterDeploymentRequeue: &metav1.Duration{Duration: time.Minute},
+		TemplateRequeue:          &metav1.Duration{Duration: 10 * time.Minute},
+	})).To(Succeed())
+	g.Expect(config).To(Equal(map[string]any{
+		"controller": map[string]any{
+			"createManagement": true,
+			"intervals":        map[string]any{"clusterDeploymentRequeue": "1m0s", "resync": "10h0m0s", "templateRequeue": "10m0s"},
+		},
+	}))
+
+	g.Expect(applyIntervalsValues(map[string]any{"controller": "invalid"}, &kcmv1.Intervals{})).To(MatchError(ContainSubstring("failed to cast 'controller'")))
+}
+
+func Test_applyRBACValues(t *testing.T) {
+	g := NewWithT(t)
+
+	config := map[string]any{"rbac": map[string]any{"providers": []string{"cluster-api-provider-aws"}}}
+	g.Expect(applyRBACValues(config, nil)).To(Succeed())
+	g.Expect(config).To(Equal(map[string]any{"rbac": map[string]any{"providers": []string{"cluster-api-provider-aws"}}}))
+
+	g.Expect(applyRBACValues(config, []kcmv1.Provider{
+		{Name: "projectsveltos"},
+		{Name: "cluster-api-provider-vsphere"},
+	})).To(Succeed())
+	g.Expect(config).To(Equal(map[string]any{"rbac": map[string]any{"providers": []string{"cluster-api-provider-vsphere", "projectsveltos"}}}))
+
+	g.Expect(applyRBACValues(map[string]any{"rbac": "invalid"}, []kcmv1.Provider{{Name: "projectsveltos"}})).To(MatchError(ContainSubstring("failed to cast 'rbac'")))
+}
+
+func Test_applyHighAvailabilityValues(t *testing.T) {
+	g := NewWithT(t)
+
+	config := map[string]any{
+		"admissionWebhook": map[string]any{"enabled": true},
+		"controller":       map[string]any{"createManagement": true, "leaderElection": map[string]any{"retryPeriod": "5s"}},
+	}
+	g.Expect(applyHighAvailabilityValues(config, nil)).To(Succeed())
+	g.Expect(config).NotTo(HaveKey("replicas"))
+
+	g.Expect(applyHighAvailabilityValues(config, &kcmv1.HighAvailability{
+		Replicas:            utils.PtrTo[int32](3),
+		WebhookMinAvailable: utils.PtrTo[int32](2),
+		LeaderElection: &kcmv1.LeaderElection{
+			LeaseDuration: &metav1.Duration{Duration: 30 * time.Second},
+			RenewDeadline: &metav1.Duration{Duration: 20 * time.Second},
+		},
+	})).To(Succeed())
+	g.Expect(config).To(Equal(map[string]any{
+		"replicas":         int32(3),
+		"admissionWebhook": map[string]any{"enabled": true, "minAvailable": int32(2)},
+		"controller": map[string]any{
+			"createManagement": true,
+			"leaderElection":   map[string]any{"leaseDuration": "30s", "renewDeadline": "20s", "retryPeriod": "5s"},
+		},
+	}))
+}
@@ -0,0 +1,67 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	kcmv1alpha1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/controller/backup"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+// ManagementRestoreReconciler reconciles a ManagementRestore object
+type ManagementRestoreReconciler struct {
+	client.Client
+
+	internal *backup.Reconciler
+
+	SystemNamespace string
+}
+
+func (r *ManagementRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+
+	mgmtRestore := new(kcmv1alpha1.ManagementRestore)
+	if err := r.Get(ctx, req.NamespacedName, mgmtRestore); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !mgmtRestore.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	res, err := r.internal.ReconcileRestore(ctx, mgmtRestore)
+	if err != nil {
+		l.Error(err, "failed to reconcile managementrestores")
+	}
+	return res, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ManagementRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.internal = backup.NewReconciler(r.Client, r.SystemNamespace)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		Named("mgmtrestore_controller").
+		For(&kcmv1alpha1.ManagementRestore{}).
+		Complete(r)
+}
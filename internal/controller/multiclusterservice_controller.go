@@ -39,8 +39,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/audit"
+	"github.com/K0rdent/kcm/internal/errclass"
 	"github.com/K0rdent/kcm/internal/metrics"
 	"github.com/K0rdent/kcm/internal/sveltos"
+	"github.com/K0rdent/kcm/internal/tracing"
 	"github.com/K0rdent/kcm/internal/utils"
 	"github.com/K0rdent/kcm/internal/utils/ratelimit"
 )
@@ -49,15 +52,29 @@ import (
 type MultiClusterServiceReconciler struct {
 	Client          client.Client
 	SystemNamespace string
+
+	// MaxConcurrentReconciles is the number of the MultiClusterServices
+	// reconciled concurrently, one if not set.
+	MaxConcurrentReconciles int
+
+	// auditRecorder records the rollouts of the services once they have been
+	// applied.
+	auditRecorder *audit.Recorder
 }
 
 // Reconcile reconciles a MultiClusterService object.
-func (r *MultiClusterServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *MultiClusterServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	l := ctrl.LoggerFrom(ctx)
 	l.Info("Reconciling MultiClusterService")
 
+	ctx, span := tracing.Start(ctx, "MultiClusterService.Reconcile", tracing.ObjectAttributes(kcm.MultiClusterServiceKind, "", req.Name)...)
+	defer func() {
+		tracing.End(span, err)
+		trackReconcileError(ctx, kcm.MultiClusterServiceKind, err)
+	}()
+
 	mcs := &kcm.MultiClusterService{}
-	err := r.Client.Get(ctx, req.NamespacedName, mcs)
+	err = r.Client.Get(ctx, req.NamespacedName, mcs)
 	if apierrors.IsNotFound(err) {
 		l.Info("MultiClusterService not found, ignoring since object must be deleted")
 		return ctrl.Result{}, nil
@@ -93,6 +110,9 @@ func (r *MultiClusterServiceReconciler) reconcileUpdate(ctx context.Context, mcs
 	// if there is an error while retrieving status for the services.
 	var servicesErr error
 
+	previousConditions := slices.Clone(mcs.Status.Conditions)
+	statusBase := mcs.DeepCopy()
+
 	defer func() {
 		condition := metav1.Condition{
 			Reason: kcm.SucceededReason,
@@ -101,7 +121,7 @@ func (r *MultiClusterServiceReconciler) reconcileUpdate(ctx context.Context, mcs
 		}
 		if err != nil {
 			condition.Message = err.Error()
-			condition.Reason = kcm.FailedReason
+			condition.Reason = errclass.Reason(err)
 			condition.Status = metav1.ConditionFalse
 		}
 		apimeta.SetStatusCondition(&mcs.Status.Conditions, condition)
@@ -113,12 +133,16 @@ func (r *MultiClusterServiceReconciler) reconcileUpdate(ctx context.Context, mcs
 		}
 		if servicesErr != nil {
 			servicesCondition.Message = servicesErr.Error()
-			servicesCondition.Reason = kcm.FailedReason
+			servicesCondition.Reason = errclass.Reason(servicesErr)
 			servicesCondition.Status = metav1.ConditionFalse
 		}
 		apimeta.SetStatusCondition(&mcs.Status.Conditions, servicesCondition)
 
-		err = errors.Join(err, servicesErr, r.updateStatus(ctx, mcs))
+		statusErr := r.updateStatus(ctx, mcs, statusBase)
+		if statusErr == nil {
+			recordConditionTransitions(mcs, previousConditions, mcs.Status.Conditions, multiClusterServiceConditionEvents)
+		}
+		err = errors.Join(err, servicesErr, statusErr)
 	}()
 
 	if controllerutil.AddFinalizer(mcs, kcm.MultiClusterServiceFinalizer) {
@@ -145,8 +169,21 @@ func (r *MultiClusterServiceReconciler) reconcileUpdate(ctx context.Context, mcs
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	securityPatches, err := sveltos.GetSecurityPatches(ctx, r.Client, r.SystemNamespace, mcs.Spec.ServiceSpec.Services)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
-	if _, err = sveltos.ReconcileClusterProfile(ctx, r.Client, mcs.Name,
+	var previousProfileSpec *sveltosv1beta1.Spec
+	previousProfile := new(sveltosv1beta1.ClusterProfile)
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: mcs.Name}, previousProfile); err == nil {
+		previousProfileSpec = &previousProfile.Spec
+	} else if !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to get ClusterProfile %s: %w", mcs.Name, err)
+	}
+
+	tctx, span := tracing.Start(ctx, "ReconcileSveltosProfile")
+	clusterProfile, err := sveltos.ReconcileClusterProfile(tctx, r.Client, mcs.Name,
 		sveltos.ReconcileProfileOpts{
 			OwnerReference: &metav1.OwnerReference{
 				APIVersion: kcm.GroupVersion.String(),
@@ -166,9 +203,15 @@ func (r *MultiClusterServiceReconciler) reconcileUpdate(ctx context.Context, mcs
 			DriftIgnore:          mcs.Spec.ServiceSpec.DriftIgnore,
 			DriftExclusions:      mcs.Spec.ServiceSpec.DriftExclusions,
 			ContinueOnError:      mcs.Spec.ServiceSpec.ContinueOnError,
-		}); err != nil {
+			Patches:              securityPatches,
+		})
+	tracing.End(span, err)
+	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to reconcile ClusterProfile: %w", err)
 	}
+	recordServiceRollout(ctx, r.auditRecorder,
+		kcm.AuditSubject{Kind: kcm.MultiClusterServiceKind, Name: mcs.Name},
+		mcs.Spec.ServiceSpec.Services, previousProfileSpec, &clusterProfile.Spec)
 
 	for _, svc := range mcs.Spec.ServiceSpec.Services {
 		metrics.TrackMetricTemplateUsage(ctx, kcm.ServiceTemplateKind, svc.Template, kcm.MultiClusterServiceKind, mcs.ObjectMeta, true)
@@ -190,7 +233,9 @@ func (r *MultiClusterServiceReconciler) reconcileUpdate(ctx context.Context, mcs
 		mcs.Status.Services = nil
 	} else {
 		var servicesStatus []kcm.ServiceStatus
-		servicesStatus, servicesErr = updateServicesStatus(ctx, r.Client, profileRef, profile.Status.MatchingClusterRefs, mcs.Status.Services)
+		tctx, span := tracing.Start(ctx, "FetchServicesStatus")
+		servicesStatus, servicesErr = updateServicesStatus(tctx, r.Client, profileRef, profile.Status.MatchingClusterRefs, mcs.Status.Services)
+		tracing.End(span, servicesErr)
 		if servicesErr != nil {
 			return ctrl.Result{}, nil
 		}
@@ -199,8 +244,9 @@ func (r *MultiClusterServiceReconciler) reconcileUpdate(ctx context.Context, mcs
 	return ctrl.Result{}, nil
 }
 
-// updateStatus updates the status for the MultiClusterService object.
-func (r *MultiClusterServiceReconciler) updateStatus(ctx context.Context, mcs *kcm.MultiClusterService) error {
+// updateStatus patches the status of the MultiClusterService object with the
+// changes made since the base copy of it has been read.
+func (r *MultiClusterServiceReconciler) updateStatus(ctx context.Context, mcs, base *kcm.MultiClusterService) error {
 	if err := r.setClustersServicesReadinessConditions(ctx, mcs); err != nil {
 		return fmt.Errorf("failed to set clusters and services readiness conditions: %w", err)
 	}
@@ -208,7 +254,7 @@ func (r *MultiClusterServiceReconciler) updateStatus(ctx context.Context, mcs *k
 	mcs.Status.ObservedGeneration = mcs.Generation
 	mcs.Status.Conditions = updateStatusConditions(mcs.Status.Conditions)
 
-	if err := r.Client.Status().Update(ctx, mcs); err != nil {
+	if err := patchStatus(ctx, r.Client, mcs, base); err != nil {
 		return fmt.Errorf("failed to update status for MultiClusterService %s/%s: %w", mcs.Namespace, mcs.Name, err)
 	}
 
@@ -268,12 +314,7 @@ func (r *MultiClusterServiceReconciler) setClustersServicesReadinessConditions(c
 }
 
 func getServicesReadinessCondition(serviceStatuses []kcm.ServiceStatus, desiredServices int) metav1.Condition {
-	ready := 0
-	for _, svcstatus := range serviceStatuses {
-		if !slices.ContainsFunc(svcstatus.Conditions, func(e metav1.Condition) bool { return e.Status != metav1.ConditionTrue }) {
-			ready++
-		}
-	}
+	ready := readyServicesCount(serviceStatuses)
 
 	// NOTE: if desired < ready we still want to show this, because some of services might be in removal process
 	// WARN: at the moment complete service removal is not being handled at all
@@ -297,19 +338,38 @@ func getServicesReadinessCondition(serviceStatuses []kcm.ServiceStatus, desiredS
 	return c
 }
 
+// readyServicesCount returns the number of the services all of the conditions of which are true.
+func readyServicesCount(serviceStatuses []kcm.ServiceStatus) int {
+	ready := 0
+	for _, svcstatus := range serviceStatuses {
+		if !slices.ContainsFunc(svcstatus.Conditions, func(e metav1.Condition) bool { return e.Status != metav1.ConditionTrue }) {
+			ready++
+		}
+	}
+	return ready
+}
+
 // updateStatusConditions evaluates all provided conditions and returns them
 // after setting a new condition based on the status of the provided ones.
 func updateStatusConditions(conditions []metav1.Condition) []metav1.Condition {
 	var warnings, errs strings.Builder
+	// failedReason is the class of the first of the classified failures
+	failedReason := kcm.FailedReason
 
 	for _, condition := range conditions {
-		if condition.Type == kcm.ReadyCondition {
+		// the compliance scan results, the certificates expiration and the
+		// reachability of the running cluster do not affect the readiness
+		if condition.Type == kcm.ReadyCondition || condition.Type == kcm.CompliancePassedCondition || condition.Type == kcm.CertificatesValidCondition ||
+			condition.Type == kcm.ReachableCondition {
 			continue
 		}
 		if condition.Status == metav1.ConditionUnknown {
 			_, _ = warnings.WriteString(condition.Message + ". ")
 		}
 		if condition.Status == metav1.ConditionFalse {
+			if failedReason == kcm.FailedReason {
+				failedReason = conditionFailureClass(condition)
+			}
 			switch condition.Type {
 			case kcm.ClusterInReadyStateCondition:
 				_, _ = errs.WriteString(condition.Message + " Clusters are ready. ")
@@ -335,7 +395,7 @@ func updateStatusConditions(conditions []metav1.Condition) []metav1.Condition {
 	}
 	if errs.Len() > 0 {
 		condition.Status = metav1.ConditionFalse
-		condition.Reason = kcm.FailedReason
+		condition.Reason = failedReason
 		condition.Message = strings.TrimSuffix(errs.String(), ". ")
 	}
 
@@ -343,13 +403,25 @@ func updateStatusConditions(conditions []metav1.Condition) []metav1.Condition {
 	return conditions
 }
 
-// updateServicesStatus updates the services deployment status.
+// conditionFailureClass returns the reason of the class of the failure the false
+// condition reports, e.g. the exhausted cloud quota of the infrastructure provider.
+func conditionFailureClass(condition metav1.Condition) string {
+	if errclass.IsReason(condition.Reason) {
+		return condition.Reason
+	}
+	return string(errclass.OfMessage(condition.Message))
+}
+
+// updateServicesStatus updates the services deployment status. The clusters
+// no longer matching the profile, e.g. relabeled out of the selector, are
+// dropped from the status.
 func updateServicesStatus(ctx context.Context, c client.Client, profileRef client.ObjectKey, profileStatusMatchingClusterRefs []corev1.ObjectReference, servicesStatus []kcm.ServiceStatus) ([]kcm.ServiceStatus, error) {
 	profileKind := sveltosv1beta1.ProfileKind
 	if profileRef.Namespace == "" {
 		profileKind = sveltosv1beta1.ClusterProfileKind
 	}
 
+	updatedStatus := make([]kcm.ServiceStatus, 0, len(profileStatusMatchingClusterRefs))
 	for _, obj := range profileStatusMatchingClusterRefs {
 		isSveltosCluster := obj.APIVersion == libsveltosv1beta1.GroupVersion.String()
 		summaryName := sveltoscontrollers.GetClusterSummaryName(profileKind, profileRef.Name, obj.Name, isSveltosCluster)
@@ -360,16 +432,14 @@ func updateServicesStatus(ctx context.Context, c client.Client, profileRef clien
 			return nil, fmt.Errorf("failed to get ClusterSummary %s to fetch status: %w", summaryRef.String(), err)
 		}
 
-		idx := slices.IndexFunc(servicesStatus, func(o kcm.ServiceStatus) bool {
+		status := kcm.ServiceStatus{
+			ClusterName:      obj.Name,
+			ClusterNamespace: obj.Namespace,
+		}
+		if idx := slices.IndexFunc(servicesStatus, func(o kcm.ServiceStatus) bool {
 			return obj.Name == o.ClusterName && obj.Namespace == o.ClusterNamespace
-		})
-
-		if idx < 0 {
-			servicesStatus = append(servicesStatus, kcm.ServiceStatus{
-				ClusterName:      obj.Name,
-				ClusterNamespace: obj.Namespace,
-			})
-			idx = len(servicesStatus) - 1
+		}); idx >= 0 {
+			status = servicesStatus[idx]
 		}
 
 		conditions, err := sveltos.GetStatusConditions(&summary)
@@ -381,10 +451,11 @@ func updateServicesStatus(ctx context.Context, c client.Client, profileRef clien
 		// implemented by Sveltos ClusterSummary object. E.g. If a service has been
 		// removed, the ClusterSummary status will not show that service, therefore
 		// we also want the entry for that service to be removed from conditions.
-		servicesStatus[idx].Conditions = conditions
+		status.Conditions = conditions
+		updatedStatus = append(updatedStatus, status)
 	}
 
-	return servicesStatus, nil
+	return updatedStatus, nil
 }
 
 func (r *MultiClusterServiceReconciler) reconcileDelete(ctx context.Context, mcs *kcm.MultiClusterService) (result ctrl.Result, err error) {
@@ -445,10 +516,12 @@ func requeueSveltosProfileForClusterSummary(ctx context.Context, obj client.Obje
 // SetupWithManager sets up the controller with the Manager.
 func (r *MultiClusterServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.Client = mgr.GetClient()
+	r.auditRecorder = &audit.Recorder{Client: r.Client, SystemNamespace: r.SystemNamespace, Controller: "multiclusterservice"}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.TypedOptions[ctrl.Request]{
-			RateLimiter: ratelimit.DefaultFastSlow(),
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+			RateLimiter:             ratelimit.DefaultFastSlow(),
 		}).
 		For(&kcm.MultiClusterService{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Watches(&sveltosv1beta1.ClusterSummary{},
@@ -458,5 +531,27 @@ func (r *MultiClusterServiceReconciler) SetupWithManager(mgr ctrl.Manager) error
 				GenericFunc: func(event.GenericEvent) bool { return false },
 			}),
 		).
+		// the services of the removed clusters are dropped from the status of
+		// the MultiClusterServices matching them
+		Watches(&kcm.ClusterDeployment{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []ctrl.Request {
+				multiClusterServices := new(kcm.MultiClusterServiceList)
+				if err := r.Client.List(ctx, multiClusterServices,
+					client.MatchingFields{kcm.MultiClusterServiceClustersIndexKey: client.ObjectKeyFromObject(o).String()}); err != nil {
+					return []ctrl.Request{}
+				}
+
+				req := make([]ctrl.Request, 0, len(multiClusterServices.Items))
+				for _, mcs := range multiClusterServices.Items {
+					req = append(req, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&mcs)})
+				}
+				return req
+			}),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				UpdateFunc:  func(event.UpdateEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			}),
+		).
 		Complete(r)
 }
@@ -16,6 +16,7 @@ package controller
 
 import (
 	"context"
+	"testing"
 	"time"
 
 	helmcontrollerv2 "github.com/fluxcd/helm-controller/api/v2"
@@ -28,9 +29,13 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
 )
 
 var _ = Describe("MultiClusterService Controller", func() {
@@ -275,3 +280,99 @@ var _ = Describe("MultiClusterService Controller", func() {
 		})
 	})
 })
+
+func Test_updateStatusConditions(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		conditions []metav1.Condition
+		reason     string
+	}{
+		{
+			name: "ready",
+			conditions: []metav1.Condition{
+				{Type: kcm.HelmReleaseReadyCondition, Status: metav1.ConditionTrue, Reason: kcm.SucceededReason},
+			},
+			reason: kcm.SucceededReason,
+		},
+		{
+			name: "unclassified failure",
+			conditions: []metav1.Condition{
+				{Type: kcm.HelmReleaseReadyCondition, Status: metav1.ConditionFalse, Reason: "InstallFailed", Message: "install retries exhausted"},
+			},
+			reason: kcm.FailedReason,
+		},
+		{
+			name: "classified reason",
+			conditions: []metav1.Condition{
+				{Type: kcm.HelmChartReadyCondition, Status: metav1.ConditionFalse, Reason: kcm.InvalidConfigReason, Message: "failed to validate template"},
+				{Type: kcm.HelmReleaseReadyCondition, Status: metav1.ConditionFalse, Reason: "InstallFailed", Message: "install retries exhausted"},
+			},
+			reason: kcm.InvalidConfigReason,
+		},
+		{
+			name: "classified message",
+			conditions: []metav1.Condition{
+				{Type: "InfrastructureReady", Status: metav1.ConditionFalse, Reason: "InstanceProvisionFailed", Message: "VcpuLimitExceeded: You have requested more vCPU capacity"},
+			},
+			reason: kcm.QuotaExceededReason,
+		},
+		{
+			name: "certificates do not affect readiness",
+			conditions: []metav1.Condition{
+				{Type: kcm.CertificatesValidCondition, Status: metav1.ConditionFalse, Reason: kcm.AuthFailureReason},
+			},
+			reason: kcm.SucceededReason,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			conditions := updateStatusConditions(tc.conditions)
+			var ready *metav1.Condition
+			for i := range conditions {
+				if conditions[i].Type == kcm.ReadyCondition {
+					ready = &conditions[i]
+				}
+			}
+			g.Expect(ready).NotTo(BeNil())
+			g.Expect(ready.Reason).To(Equal(tc.reason))
+		})
+	}
+}
+
+func Test_updateServicesStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	const namespace = "test"
+	summary := func(cluster string) *sveltosv1beta1.ClusterSummary {
+		return &sveltosv1beta1.ClusterSummary{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-mcs-capi-" + cluster, Namespace: namespace},
+			Status: sveltosv1beta1.ClusterSummaryStatus{
+				HelmReleaseSummaries: []sveltosv1beta1.HelmChartSummary{
+					{ReleaseName: "ingress", ReleaseNamespace: "default", Status: sveltosv1beta1.HelmChartStatusManaging},
+				},
+			},
+		}
+	}
+	clusterRef := func(cluster string) corev1.ObjectReference {
+		return corev1.ObjectReference{APIVersion: clusterapiv1beta1.GroupVersion.String(), Kind: "Cluster", Namespace: namespace, Name: cluster}
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(summary("first"), summary("second")).Build()
+
+	// the first cluster has been relabeled out of the selector, the second
+	// one has been relabeled into it
+	current := []kcm.ServiceStatus{
+		{ClusterName: "first", ClusterNamespace: namespace},
+	}
+	statuses, err := updateServicesStatus(context.Background(), cl, client.ObjectKey{Name: "test-mcs"}, []corev1.ObjectReference{clusterRef("second")}, current)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(statuses).To(HaveLen(1))
+	g.Expect(statuses[0].ClusterName).To(Equal("second"))
+	g.Expect(statuses[0].Conditions).To(HaveLen(1))
+	g.Expect(statuses[0].Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+
+	statuses, err = updateServicesStatus(context.Background(), cl, client.ObjectKey{Name: "test-mcs"}, nil, statuses)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(statuses).To(BeEmpty())
+}
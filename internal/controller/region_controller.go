@@ -0,0 +1,450 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	fluxv2 "github.com/fluxcd/helm-controller/api/v2"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	fluxconditions "github.com/fluxcd/pkg/runtime/conditions"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/helm"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+const (
+	regionHelmReleasePrefix = "kcm-region-"
+	regionKubeConfigKey     = "value"
+	regionSyncPeriod        = time.Minute
+)
+
+// RegionReconciler installs the KCM instances to the regional management
+// clusters, distributes the templates and the credentials to them and
+// aggregates the inventory of their ClusterDeployments.
+type RegionReconciler struct {
+	client.Client
+
+	// newRegionalClient returns the client of the regional cluster with the given kubeconfig.
+	newRegionalClient func(kubeconfig []byte) (client.Client, error)
+
+	SystemNamespace string
+}
+
+func (r *RegionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+	l.Info("Reconciling Region")
+
+	region := new(kcm.Region)
+	if err := r.Get(ctx, req.NamespacedName, region); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !region.DeletionTimestamp.IsZero() {
+		return r.delete(ctx, region)
+	}
+
+	if controllerutil.AddFinalizer(region, kcm.RegionFinalizer) {
+		if err := r.Update(ctx, region); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer to Region %s: %w", region.Name, err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	region.Status.ObservedGeneration = region.Generation
+
+	installed, err := r.installKCM(ctx, region)
+	if err != nil {
+		apimeta.SetStatusCondition(region.GetConditions(), metav1.Condition{
+			Type:               kcm.RegionKCMInstalledCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             kcm.FailedReason,
+			Message:            err.Error(),
+			ObservedGeneration: region.Generation,
+		})
+		return ctrl.Result{}, errors.Join(err, r.updateStatus(ctx, region))
+	}
+	if !installed {
+		return ctrl.Result{RequeueAfter: regionSyncPeriod}, r.updateStatus(ctx, region)
+	}
+
+	regionalClient, err := r.getRegionalClient(ctx, region)
+	if err != nil {
+		return ctrl.Result{}, errors.Join(err, r.updateStatus(ctx, region))
+	}
+
+	distributedCondition := metav1.Condition{
+		Type:               kcm.RegionObjectsDistributedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             kcm.SucceededReason,
+		Message:            "All of the objects are distributed to the regional cluster",
+		ObservedGeneration: region.Generation,
+	}
+	if err := r.distribute(ctx, regionalClient, region); err != nil {
+		distributedCondition.Status = metav1.ConditionFalse
+		distributedCondition.Reason = kcm.FailedReason
+		distributedCondition.Message = err.Error()
+	}
+	apimeta.SetStatusCondition(region.GetConditions(), distributedCondition)
+
+	inventory, err := regionInventory(ctx, regionalClient)
+	if err != nil {
+		l.Error(err, "failed to collect the inventory of the regional cluster")
+	} else {
+		region.Status.ClusterDeployments = inventory
+	}
+
+	return ctrl.Result{RequeueAfter: regionSyncPeriod}, r.updateStatus(ctx, region)
+}
+
+// installKCM ensures the HelmRelease of the KCM chart targeting the regional
+// cluster exists and returns whether it is ready.
+func (r *RegionReconciler) installKCM(ctx context.Context, region *kcm.Region) (bool, error) {
+	kubeconfigSecret := new(corev1.Secret)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: r.SystemNamespace, Name: regionKubeConfigSecretName(region)}, kubeconfigSecret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get kubeconfig Secret %s: %w", regionKubeConfigSecretName(region), err)
+		}
+		apimeta.SetStatusCondition(region.GetConditions(), metav1.Condition{
+			Type:               kcm.RegionKCMInstalledCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             kcm.ProgressingReason,
+			Message:            fmt.Sprintf("Waiting for the kubeconfig Secret %s of the regional cluster", regionKubeConfigSecretName(region)),
+			ObservedGeneration: region.Generation,
+		})
+		return false, nil
+	}
+
+	kcmTemplate, err := r.getKCMTemplate(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	hr, _, err := helm.ReconcileHelmRelease(ctx, r.Client, regionHelmReleasePrefix+region.Name, r.SystemNamespace, helm.ReconcileHelmReleaseOpts{
+		Values:          region.Spec.Config,
+		ChartRef:        kcmTemplate.Status.ChartRef,
+		ReleaseName:     kcm.CoreKCMName,
+		TargetNamespace: r.SystemNamespace,
+		KubeConfig: &fluxmeta.KubeConfigReference{
+			SecretRef: fluxmeta.SecretKeyReference{Name: kubeconfigSecret.Name, Key: regionKubeConfigKey},
+		},
+		Install: &fluxv2.Install{CreateNamespace: true},
+		OwnerReference: &metav1.OwnerReference{
+			APIVersion: kcm.GroupVersion.String(),
+			Kind:       kcm.RegionKind,
+			Name:       region.Name,
+			UID:        region.UID,
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to reconcile KCM HelmRelease of the Region %s: %w", region.Name, err)
+	}
+
+	condition := metav1.Condition{
+		Type:               kcm.RegionKCMInstalledCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             kcm.ProgressingReason,
+		Message:            "Waiting for the KCM HelmRelease to be ready",
+		ObservedGeneration: region.Generation,
+	}
+	if hr.Generation == hr.Status.ObservedGeneration {
+		if ready := fluxconditions.Get(hr, fluxmeta.ReadyCondition); ready != nil {
+			condition.Status = ready.Status
+			condition.Message = ready.Message
+			if ready.Status == metav1.ConditionTrue {
+				condition.Reason = kcm.SucceededReason
+			} else if ready.Status == metav1.ConditionFalse {
+				condition.Reason = kcm.FailedReason
+			}
+		}
+	}
+	apimeta.SetStatusCondition(region.GetConditions(), condition)
+
+	return condition.Status == metav1.ConditionTrue, nil
+}
+
+// getKCMTemplate returns the KCM ProviderTemplate installed by the Management.
+func (r *RegionReconciler) getKCMTemplate(ctx context.Context) (*kcm.ProviderTemplate, error) {
+	mgmt := new(kcm.Management)
+	if err := r.Get(ctx, client.ObjectKey{Name: kcm.ManagementName}, mgmt); err != nil {
+		return nil, fmt.Errorf("failed to get Management: %w", err)
+	}
+
+	templateName := ""
+	if mgmt.Spec.Core != nil {
+		templateName = mgmt.Spec.Core.KCM.Template
+	}
+	if templateName == "" {
+		release := new(kcm.Release)
+		if err := r.Get(ctx, client.ObjectKey{Name: mgmt.Spec.Release}, release); err != nil {
+			return nil, fmt.Errorf("failed to get Release %s: %w", mgmt.Spec.Release, err)
+		}
+		templateName = release.Spec.KCM.Template
+	}
+
+	template := new(kcm.ProviderTemplate)
+	if err := r.Get(ctx, client.ObjectKey{Name: templateName}, template); err != nil {
+		return nil, fmt.Errorf("failed to get ProviderTemplate %s: %w", templateName, err)
+	}
+	if !template.Status.Valid || template.Status.ChartRef == nil {
+		return nil, fmt.Errorf("ProviderTemplate %s is not ready yet", templateName)
+	}
+
+	return template, nil
+}
+
+func (r *RegionReconciler) getRegionalClient(ctx context.Context, region *kcm.Region) (client.Client, error) {
+	secret := new(corev1.Secret)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: r.SystemNamespace, Name: regionKubeConfigSecretName(region)}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig Secret %s: %w", regionKubeConfigSecretName(region), err)
+	}
+
+	cl, err := r.newRegionalClient(secret.Data[regionKubeConfigKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client of the regional cluster of the Region %s: %w", region.Name, err)
+	}
+	return cl, nil
+}
+
+// distribute copies the templates and the credentials listed in the Region
+// to the system namespace of the regional cluster.
+func (r *RegionReconciler) distribute(ctx context.Context, regionalClient client.Client, region *kcm.Region) error {
+	var errs error
+
+	for _, name := range region.Spec.ClusterTemplates {
+		errs = errors.Join(errs, r.distributeObject(ctx, regionalClient, &kcm.ClusterTemplate{}, client.ObjectKey{Namespace: r.SystemNamespace, Name: name}))
+	}
+	for _, name := range region.Spec.ServiceTemplates {
+		errs = errors.Join(errs, r.distributeObject(ctx, regionalClient, &kcm.ServiceTemplate{}, client.ObjectKey{Namespace: r.SystemNamespace, Name: name}))
+	}
+	for _, name := range region.Spec.Credentials {
+		errs = errors.Join(errs, r.distributeCredential(ctx, regionalClient, name))
+	}
+
+	return errs
+}
+
+// distributeCredential copies the Credential along with its identity object
+// and the Secrets referenced by the identity.
+func (r *RegionReconciler) distributeCredential(ctx context.Context, regionalClient client.Client, name string) error {
+	cred := new(kcm.Credential)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: r.SystemNamespace, Name: name}, cred); err != nil {
+		return fmt.Errorf("failed to get Credential %s: %w", name, err)
+	}
+
+	if ref := cred.Spec.IdentityRef; ref != nil {
+		identity := new(unstructured.Unstructured)
+		identity.SetAPIVersion(ref.APIVersion)
+		identity.SetKind(ref.Kind)
+		if err := r.distributeObject(ctx, regionalClient, identity, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}); err != nil {
+			return err
+		}
+
+		for _, key := range identitySecrets(identity, r.SystemNamespace) {
+			if err := r.distributeObject(ctx, regionalClient, &corev1.Secret{}, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.distributeObject(ctx, regionalClient, cred, client.ObjectKeyFromObject(cred))
+}
+
+// distributeObject reads the object with the given key into obj and
+// creates or updates its copy in the regional cluster.
+func (r *RegionReconciler) distributeObject(ctx context.Context, regionalClient client.Client, obj client.Object, key client.ObjectKey) error {
+	if err := r.Get(ctx, key, obj); err != nil {
+		return fmt.Errorf("failed to get %T %s: %w", obj, key, err)
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme())
+	if err != nil {
+		return err
+	}
+	src, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s %s: %w", gvk.Kind, key, err)
+	}
+
+	if key.Namespace != "" {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: key.Namespace}}
+		if err := regionalClient.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create namespace %s in the regional cluster: %w", key.Namespace, err)
+		}
+	}
+
+	dst := new(unstructured.Unstructured)
+	dst.SetGroupVersionKind(gvk)
+	dst.SetNamespace(key.Namespace)
+	dst.SetName(key.Name)
+	if _, err := ctrl.CreateOrUpdate(ctx, regionalClient, dst, func() error {
+		for k, v := range src {
+			if k == "apiVersion" || k == "kind" || k == "metadata" || k == "status" {
+				continue
+			}
+			dst.Object[k] = runtime.DeepCopyJSONValue(v)
+		}
+		labels := dst.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[kcm.KCMManagedLabelKey] = kcm.KCMManagedLabelValue
+		dst.SetLabels(labels)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to distribute %s %s to the regional cluster: %w", gvk.Kind, key, err)
+	}
+
+	return nil
+}
+
+// identitySecrets returns the keys of the Secrets referenced by the credential
+// identity. The Secrets without the namespace are looked up in the namespace of
+// the identity, or in the system namespace if the identity is cluster-scoped.
+func identitySecrets(identity *unstructured.Unstructured, systemNamespace string) []client.ObjectKey {
+	namespace := identity.GetNamespace()
+	if namespace == "" {
+		namespace = systemNamespace
+	}
+
+	var keys []client.ObjectKey
+	for _, field := range []string{"secretRef", "clientSecret"} {
+		if name, ok, _ := unstructured.NestedString(identity.Object, "spec", field); ok && name != "" {
+			keys = append(keys, client.ObjectKey{Namespace: namespace, Name: name})
+			continue
+		}
+		ref, ok, _ := unstructured.NestedStringMap(identity.Object, "spec", field)
+		if !ok || ref["name"] == "" {
+			continue
+		}
+		key := client.ObjectKey{Namespace: ref["namespace"], Name: ref["name"]}
+		if key.Namespace == "" {
+			key.Namespace = namespace
+		}
+		keys = append(keys, key)
+	}
+	if name, ok, _ := unstructured.NestedString(identity.Object, "spec", "secretName"); ok && name != "" {
+		keys = append(keys, client.ObjectKey{Namespace: namespace, Name: name})
+	}
+
+	return keys
+}
+
+// regionInventory returns the ClusterDeployments of the regional cluster.
+func regionInventory(ctx context.Context, regionalClient client.Client) ([]kcm.RegionClusterDeployment, error) {
+	cds := new(kcm.ClusterDeploymentList)
+	if err := regionalClient.List(ctx, cds); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterDeployments: %w", err)
+	}
+
+	inventory := make([]kcm.RegionClusterDeployment, 0, len(cds.Items))
+	for _, cd := range cds.Items {
+		inventory = append(inventory, kcm.RegionClusterDeployment{
+			Namespace: cd.Namespace,
+			Name:      cd.Name,
+			Template:  cd.Spec.Template,
+			Ready:     apimeta.IsStatusConditionTrue(cd.Status.Conditions, kcm.ReadyCondition),
+		})
+	}
+	slices.SortFunc(inventory, func(a, b kcm.RegionClusterDeployment) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+
+	return inventory, nil
+}
+
+func (r *RegionReconciler) delete(ctx context.Context, region *kcm.Region) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+
+	hr := &fluxv2.HelmRelease{ObjectMeta: metav1.ObjectMeta{Namespace: r.SystemNamespace, Name: regionHelmReleasePrefix + region.Name}}
+	err := r.Get(ctx, client.ObjectKeyFromObject(hr), hr)
+	if client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get HelmRelease %s: %w", client.ObjectKeyFromObject(hr), err)
+	}
+	if err == nil {
+		if hr.DeletionTimestamp.IsZero() {
+			if err := r.Client.Delete(ctx, hr); client.IgnoreNotFound(err) != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to delete HelmRelease %s: %w", client.ObjectKeyFromObject(hr), err)
+			}
+		}
+		l.Info("Waiting for the KCM instance of the regional cluster to be uninstalled")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	if controllerutil.RemoveFinalizer(region, kcm.RegionFinalizer) {
+		if err := r.Update(ctx, region); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to remove finalizer from Region %s: %w", region.Name, err)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *RegionReconciler) updateStatus(ctx context.Context, region *kcm.Region) error {
+	if err := r.Status().Update(ctx, region); err != nil {
+		return fmt.Errorf("failed to update status for Region %s: %w", region.Name, err)
+	}
+	return nil
+}
+
+// regionKubeConfigSecretName returns the name of the Secret with the kubeconfig of the regional cluster.
+func regionKubeConfigSecretName(region *kcm.Region) string {
+	if region.Spec.KubeConfigSecretRef != "" {
+		return region.Spec.KubeConfigSecretRef
+	}
+	// follows the naming of the CAPI kubeconfig Secrets
+	return region.Spec.ClusterDeployment + "-kubeconfig"
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RegionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	if r.newRegionalClient == nil {
+		r.newRegionalClient = func(kubeconfig []byte) (client.Client, error) {
+			restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+			if err != nil {
+				return nil, err
+			}
+			return client.New(restConfig, client.Options{Scheme: mgr.GetScheme()})
+		}
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("region").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&kcm.Region{}).
+		Owns(&fluxv2.HelmRelease{}).
+		Complete(r)
+}
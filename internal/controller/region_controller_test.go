@@ -0,0 +1,190 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	fluxv2 "github.com/fluxcd/helm-controller/api/v2"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestRegionReconciler_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	const systemNamespace = "kcm-system"
+
+	kcmTemplate := &kcm.ProviderTemplate{ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-1-0"}}
+	kcmTemplate.Status.Valid = true
+	kcmTemplate.Status.ChartRef = &fluxv2.CrossNamespaceSourceReference{Kind: sourcev1.HelmChartKind, Name: "kcm-0-1-0", Namespace: systemNamespace}
+
+	region := &kcm.Region{
+		ObjectMeta: metav1.ObjectMeta{Name: "eu"},
+		Spec: kcm.RegionSpec{
+			KubeConfigSecretRef: "eu-kubeconfig",
+			ClusterTemplates:    []string{"aws-standalone-cp-0-1-0"},
+			Credentials:         []string{"openstack-cred"},
+		},
+	}
+
+	mgmtClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.Region{}, &fluxv2.HelmRelease{}).
+		WithObjects(
+			region,
+			&kcm.Management{
+				ObjectMeta: metav1.ObjectMeta{Name: kcm.ManagementName},
+				Spec:       kcm.ManagementSpec{Release: "kcm-0-1-0"},
+			},
+			&kcm.Release{
+				ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-1-0"},
+				Spec:       kcm.ReleaseSpec{KCM: kcm.CoreProviderTemplate{Template: kcmTemplate.Name}},
+			},
+			kcmTemplate,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "eu-kubeconfig", Namespace: systemNamespace},
+				Data:       map[string][]byte{"value": []byte("kubeconfig")},
+			},
+			&kcm.ClusterTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "aws-standalone-cp-0-1-0", Namespace: systemNamespace},
+				Spec:       kcm.ClusterTemplateSpec{Helm: kcm.HelmSpec{ChartRef: &fluxv2.CrossNamespaceSourceReference{Kind: sourcev1.HelmChartKind, Name: "aws"}}},
+			},
+			&kcm.Credential{
+				ObjectMeta: metav1.ObjectMeta{Name: "openstack-cred", Namespace: systemNamespace},
+				Spec: kcm.CredentialSpec{IdentityRef: &corev1.ObjectReference{
+					APIVersion: "v1", Kind: "Secret", Name: "openstack-cloud-config", Namespace: systemNamespace,
+				}},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "openstack-cloud-config", Namespace: systemNamespace},
+				Data:       map[string][]byte{"clouds.yaml": []byte("clouds")},
+			},
+		).Build()
+
+	regionalClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&kcm.ClusterDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "team"},
+			Spec:       kcm.ClusterDeploymentSpec{Template: "aws-standalone-cp-0-1-0"},
+		},
+	).Build()
+
+	r := &RegionReconciler{
+		Client:          mgmtClient,
+		SystemNamespace: systemNamespace,
+		newRegionalClient: func(kubeconfig []byte) (client.Client, error) {
+			g.Expect(string(kubeconfig)).To(Equal("kubeconfig"))
+			return regionalClient, nil
+		},
+	}
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(region)}
+
+	// finalizer
+	_, err := r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// KCM HelmRelease
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	hr := new(fluxv2.HelmRelease)
+	g.Expect(mgmtClient.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: "kcm-region-eu"}, hr)).To(Succeed())
+	g.Expect(hr.Spec.ReleaseName).To(Equal(kcm.CoreKCMName))
+	g.Expect(hr.Spec.ChartRef).To(Equal(kcmTemplate.Status.ChartRef))
+	g.Expect(hr.Spec.KubeConfig).NotTo(BeNil())
+	g.Expect(hr.Spec.KubeConfig.SecretRef).To(Equal(fluxmeta.SecretKeyReference{Name: "eu-kubeconfig", Key: "value"}))
+	g.Expect(hr.OwnerReferences).To(HaveLen(1))
+
+	g.Expect(mgmtClient.Get(t.Context(), req.NamespacedName, region)).To(Succeed())
+	g.Expect(apimeta.IsStatusConditionTrue(region.Status.Conditions, kcm.RegionKCMInstalledCondition)).To(BeFalse())
+
+	hr.Status.Conditions = []metav1.Condition{{Type: fluxmeta.ReadyCondition, Status: metav1.ConditionTrue, Reason: "InstallSucceeded", LastTransitionTime: metav1.Now()}}
+	g.Expect(mgmtClient.Status().Update(t.Context(), hr)).To(Succeed())
+
+	// distribution and inventory
+	_, err = r.Reconcile(t.Context(), req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(mgmtClient.Get(t.Context(), req.NamespacedName, region)).To(Succeed())
+	g.Expect(apimeta.IsStatusConditionTrue(region.Status.Conditions, kcm.RegionKCMInstalledCondition)).To(BeTrue())
+	g.Expect(apimeta.IsStatusConditionTrue(region.Status.Conditions, kcm.RegionObjectsDistributedCondition)).To(BeTrue())
+	g.Expect(region.Status.ClusterDeployments).To(Equal([]kcm.RegionClusterDeployment{
+		{Namespace: "team", Name: "cluster", Template: "aws-standalone-cp-0-1-0"},
+	}))
+
+	template := new(kcm.ClusterTemplate)
+	g.Expect(regionalClient.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: "aws-standalone-cp-0-1-0"}, template)).To(Succeed())
+	g.Expect(template.Spec.Helm.ChartRef.Name).To(Equal("aws"))
+	g.Expect(template.Labels).To(HaveKeyWithValue(kcm.KCMManagedLabelKey, kcm.KCMManagedLabelValue))
+
+	cred := new(kcm.Credential)
+	g.Expect(regionalClient.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: "openstack-cred"}, cred)).To(Succeed())
+	g.Expect(cred.Spec.IdentityRef.Name).To(Equal("openstack-cloud-config"))
+
+	secret := new(corev1.Secret)
+	g.Expect(regionalClient.Get(t.Context(), client.ObjectKey{Namespace: systemNamespace, Name: "openstack-cloud-config"}, secret)).To(Succeed())
+	g.Expect(secret.Data).To(HaveKeyWithValue("clouds.yaml", []byte("clouds")))
+}
+
+func Test_identitySecrets(t *testing.T) {
+	const systemNamespace = "kcm-system"
+
+	newIdentity := func(namespace string, spec map[string]any) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+		u.SetNamespace(namespace)
+		return u
+	}
+
+	for _, tc := range []struct {
+		identity *unstructured.Unstructured
+		name     string
+		expected []client.ObjectKey
+	}{
+		{
+			name:     "cluster-scoped identity with secret name",
+			identity: newIdentity("", map[string]any{"secretRef": "aws-credentials"}),
+			expected: []client.ObjectKey{{Namespace: systemNamespace, Name: "aws-credentials"}},
+		},
+		{
+			name:     "namespaced identity with secret reference",
+			identity: newIdentity("default", map[string]any{"clientSecret": map[string]any{"name": "azure-secret", "namespace": "azure"}}),
+			expected: []client.ObjectKey{{Namespace: "azure", Name: "azure-secret"}},
+		},
+		{
+			name:     "identity with secret name in the same namespace",
+			identity: newIdentity("vsphere", map[string]any{"secretName": "vsphere-secret"}),
+			expected: []client.ObjectKey{{Namespace: "vsphere", Name: "vsphere-secret"}},
+		},
+		{
+			name:     "identity without secrets",
+			identity: newIdentity("", map[string]any{"allowedNamespaces": map[string]any{}}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			NewWithT(t).Expect(identitySecrets(tc.identity, systemNamespace)).To(Equal(tc.expected))
+		})
+	}
+}
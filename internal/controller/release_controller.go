@@ -27,6 +27,7 @@ import (
 	fluxconditions "github.com/fluxcd/pkg/runtime/conditions"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/storage/driver"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -35,6 +36,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -65,6 +67,8 @@ type ReleaseReconciler struct {
 
 	DefaultRegistryConfig helm.DefaultRegistryConfig
 
+	downloadHelmChartFunc func(context.Context, *sourcev1.Artifact) (*chart.Chart, error)
+
 	CreateManagement bool
 	CreateRelease    bool
 	CreateTemplates  bool
@@ -139,9 +143,82 @@ func (r *ReleaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		return ctrl.Result{}, err
 	}
 	release.Status.Ready = true
+
+	if err := r.reconcileReleaseNotes(ctx, release); err != nil {
+		l.Error(err, "failed to fetch release notes")
+		return ctrl.Result{}, err
+	}
 	return ctrl.Result{}, nil
 }
 
+// reconcileReleaseNotes fetches the release notes from the annotations of the
+// KCM chart of the Release once per chart version.
+func (r *ReleaseReconciler) reconcileReleaseNotes(ctx context.Context, release *kcm.Release) error {
+	tpl := new(kcm.ProviderTemplate)
+	if err := r.Get(ctx, client.ObjectKey{Name: release.Spec.KCM.Template}, tpl); err != nil {
+		return fmt.Errorf("failed to get ProviderTemplate %s: %w", release.Spec.KCM.Template, err)
+	}
+	if tpl.Status.ChartRef == nil {
+		return nil
+	}
+	if release.Status.Notes != nil && release.Status.Notes.ChartVersion == tpl.Status.ChartVersion {
+		return nil
+	}
+
+	helmChart := new(sourcev1.HelmChart)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: tpl.Status.ChartRef.Namespace, Name: tpl.Status.ChartRef.Name}, helmChart); err != nil {
+		return fmt.Errorf("failed to get HelmChart %s/%s: %w", tpl.Status.ChartRef.Namespace, tpl.Status.ChartRef.Name, err)
+	}
+	if helmChart.Status.Artifact == nil {
+		return fmt.Errorf("HelmChart %s/%s has no artifact", helmChart.Namespace, helmChart.Name)
+	}
+
+	if r.downloadHelmChartFunc == nil {
+		r.downloadHelmChartFunc = helm.DownloadChartFromArtifact
+	}
+
+	kcmChart, err := r.downloadHelmChartFunc(ctx, helmChart.Status.Artifact)
+	if err != nil {
+		return fmt.Errorf("failed to download chart: %w", err)
+	}
+	if kcmChart.Metadata == nil {
+		return errors.New("chart metadata is empty")
+	}
+
+	notes, err := parseReleaseNotes(kcmChart.Metadata.Annotations)
+	if err != nil {
+		return fmt.Errorf("failed to parse release notes of the chart %s: %w", kcmChart.Name(), err)
+	}
+	notes.ChartVersion = tpl.Status.ChartVersion
+	release.Status.Notes = notes
+
+	return nil
+}
+
+// parseReleaseNotes parses the release notes from the given chart annotations.
+func parseReleaseNotes(annotations map[string]string) (*kcm.ReleaseNotes, error) {
+	notes := new(kcm.ReleaseNotes)
+	if v := annotations[kcm.ReleaseChangesAnnotation]; v != "" {
+		if err := yaml.Unmarshal([]byte(v), &notes.Changes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s annotation: %w", kcm.ReleaseChangesAnnotation, err)
+		}
+	}
+	if v := annotations[kcm.ReleaseUpgradeStepsAnnotation]; v != "" {
+		if err := yaml.Unmarshal([]byte(v), &notes.UpgradeSteps); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s annotation: %w", kcm.ReleaseUpgradeStepsAnnotation, err)
+		}
+	}
+
+	for _, c := range notes.Changes {
+		if c.Breaking {
+			notes.Breaking = true
+			break
+		}
+	}
+
+	return notes, nil
+}
+
 func (r *ReleaseReconciler) validateProviderTemplates(ctx context.Context, releaseName string, expectedTemplates []string) error {
 	providerTemplates := &kcm.ProviderTemplateList{}
 	if err := r.List(ctx, providerTemplates, client.MatchingFields{kcm.OwnerRefIndexKey: releaseName}); err != nil {
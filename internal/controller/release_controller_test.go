@@ -0,0 +1,95 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	hcv2 "github.com/fluxcd/helm-controller/api/v2"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestReleaseReconciler_reconcileReleaseNotes(t *testing.T) {
+	g := NewWithT(t)
+
+	const systemNamespace = "kcm-system"
+
+	tpl := &kcm.ProviderTemplate{ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-2-0"}}
+	tpl.Status.ChartRef = &hcv2.CrossNamespaceSourceReference{Kind: sourcev1.HelmChartKind, Name: "kcm-0-2-0", Namespace: systemNamespace}
+	tpl.Status.ChartVersion = "0.2.0"
+
+	helmChart := &sourcev1.HelmChart{ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-2-0", Namespace: systemNamespace}}
+	helmChart.Status.Artifact = &sourcev1.Artifact{Path: "kcm-0.2.0.tgz"}
+
+	downloads := 0
+	r := &ReleaseReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tpl, helmChart).Build(),
+		downloadHelmChartFunc: func(context.Context, *sourcev1.Artifact) (*chart.Chart, error) {
+			downloads++
+			return &chart.Chart{Metadata: &chart.Metadata{
+				Name:    "kcm",
+				Version: "0.2.0",
+				Annotations: map[string]string{
+					kcm.ReleaseChangesAnnotation: `- kind: added
+  description: Region objects
+- kind: removed
+  description: The v1alpha0 API
+  breaking: true
+`,
+					kcm.ReleaseUpgradeStepsAnnotation: `- Remove the v1alpha0 objects before the upgrade`,
+				},
+			}}, nil
+		},
+	}
+
+	release := &kcm.Release{
+		ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-2-0"},
+		Spec:       kcm.ReleaseSpec{KCM: kcm.CoreProviderTemplate{Template: tpl.Name}},
+	}
+
+	g.Expect(r.reconcileReleaseNotes(t.Context(), release)).To(Succeed())
+	g.Expect(release.Status.Notes).To(Equal(&kcm.ReleaseNotes{
+		ChartVersion: "0.2.0",
+		Changes: []kcm.ReleaseChange{
+			{Kind: kcm.ReleaseChangeAdded, Description: "Region objects"},
+			{Kind: kcm.ReleaseChangeRemoved, Description: "The v1alpha0 API", Breaking: true},
+		},
+		UpgradeSteps: []string{"Remove the v1alpha0 objects before the upgrade"},
+		Breaking:     true,
+	}))
+
+	// the notes are fetched once per chart version
+	g.Expect(r.reconcileReleaseNotes(t.Context(), release)).To(Succeed())
+	g.Expect(downloads).To(Equal(1))
+}
+
+func Test_parseReleaseNotes(t *testing.T) {
+	g := NewWithT(t)
+
+	notes, err := parseReleaseNotes(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(notes).To(Equal(&kcm.ReleaseNotes{}))
+
+	_, err = parseReleaseNotes(map[string]string{kcm.ReleaseUpgradeStepsAnnotation: "steps: {}"})
+	g.Expect(err).To(MatchError(ContainSubstring("failed to unmarshal " + kcm.ReleaseUpgradeStepsAnnotation + " annotation")))
+}
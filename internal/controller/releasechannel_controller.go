@@ -0,0 +1,155 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+const releaseUpgradePollPeriod = time.Minute
+
+// ReleaseChannelReconciler upgrades the Management subscribed to a release
+// channel to the newer ready Releases of the channel.
+type ReleaseChannelReconciler struct {
+	client.Client
+}
+
+func (r *ReleaseChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+	l.Info("Reconciling Management release channel")
+
+	mgmt := new(kcm.Management)
+	if err := r.Get(ctx, req.NamespacedName, mgmt); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !mgmt.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if mgmt.Spec.ReleaseChannel == nil {
+		return ctrl.Result{}, r.setAvailableRelease(ctx, mgmt, "")
+	}
+
+	if mgmt.Status.Release != mgmt.Spec.Release {
+		l.V(1).Info("Management upgrade is in progress, postponing the release channel check", "release", mgmt.Spec.Release)
+		return ctrl.Result{RequeueAfter: releaseUpgradePollPeriod}, nil
+	}
+
+	current := new(kcm.Release)
+	if err := r.Get(ctx, client.ObjectKey{Name: mgmt.Spec.Release}, current); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get Release %s: %w", mgmt.Spec.Release, err)
+	}
+
+	releases := new(kcm.ReleaseList)
+	if err := r.List(ctx, releases); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list Releases: %w", err)
+	}
+
+	candidate, err := latestChannelRelease(current, releases.Items, mgmt.Spec.ReleaseChannel.Channel)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if candidate == nil {
+		return ctrl.Result{}, r.setAvailableRelease(ctx, mgmt, "")
+	}
+
+	if mgmt.Spec.ReleaseChannel.Approval == kcm.ReleaseApprovalManual && mgmt.Annotations[kcm.ReleaseApprovalAnnotation] != candidate.Name {
+		l.Info("Newer Release is available in the subscribed channel, waiting for the approval", "release", candidate.Name, "channel", mgmt.Spec.ReleaseChannel.Channel)
+		return ctrl.Result{}, r.setAvailableRelease(ctx, mgmt, candidate.Name)
+	}
+
+	l.Info("Upgrading Management to the Release of the subscribed channel", "from", mgmt.Spec.Release, "to", candidate.Name, "channel", mgmt.Spec.ReleaseChannel.Channel)
+	original := mgmt.DeepCopy()
+	mgmt.Spec.Release = candidate.Name
+	if err := r.Patch(ctx, mgmt, client.MergeFrom(original)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to upgrade Management to the Release %s: %w", candidate.Name, err)
+	}
+
+	return ctrl.Result{RequeueAfter: releaseUpgradePollPeriod}, nil
+}
+
+func (r *ReleaseChannelReconciler) setAvailableRelease(ctx context.Context, mgmt *kcm.Management, release string) error {
+	if mgmt.Status.AvailableRelease == release {
+		return nil
+	}
+
+	original := mgmt.DeepCopy()
+	mgmt.Status.AvailableRelease = release
+	if err := r.Status().Patch(ctx, mgmt, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to update status of Management %s: %w", mgmt.Name, err)
+	}
+	return nil
+}
+
+// latestChannelRelease returns the ready Release of the given channel with
+// the highest version newer than the current one, nil if there is none.
+func latestChannelRelease(current *kcm.Release, releases []kcm.Release, channel kcm.ReleaseChannel) (*kcm.Release, error) {
+	currentVersion, err := semver.NewVersion(current.Spec.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse version %s of the Release %s: %w", current.Spec.Version, current.Name, err)
+	}
+
+	var (
+		latest        *kcm.Release
+		latestVersion = currentVersion
+	)
+	for i := range releases {
+		release := &releases[i]
+		if !release.Status.Ready || !channel.Includes(release.Spec.Channel) {
+			continue
+		}
+
+		version, err := semver.NewVersion(release.Spec.Version)
+		if err != nil {
+			// the Releases of the unrelated versioning are never picked up
+			continue
+		}
+		if version.GreaterThan(latestVersion) {
+			latest, latestVersion = release, version
+		}
+	}
+
+	return latest, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ReleaseChannelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("release-channel").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&kcm.Management{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Watches(&kcm.Release{}, handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []ctrl.Request {
+			return []ctrl.Request{{NamespacedName: client.ObjectKey{Name: kcm.ManagementName}}}
+		})).
+		Complete(r)
+}
@@ -0,0 +1,71 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+func Test_latestChannelRelease(t *testing.T) {
+	newRelease := func(name, version string, channel kcm.ReleaseChannel, ready bool) kcm.Release {
+		return kcm.Release{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       kcm.ReleaseSpec{Version: version, Channel: channel},
+			Status:     kcm.ReleaseStatus{Ready: ready},
+		}
+	}
+
+	current := newRelease("kcm-0-2-0", "0.2.0", kcm.ReleaseChannelStable, true)
+	releases := []kcm.Release{
+		current,
+		newRelease("kcm-0-1-0", "0.1.0", kcm.ReleaseChannelStable, true),
+		newRelease("kcm-0-3-0", "0.3.0", "", true),
+		newRelease("kcm-0-4-0", "0.4.0", kcm.ReleaseChannelStable, false),
+		newRelease("kcm-0-4-0-rc-1", "0.4.0-rc.1", kcm.ReleaseChannelRC, true),
+		newRelease("kcm-0-5-0-nightly", "0.5.0-nightly.20250101", kcm.ReleaseChannelNightly, true),
+		newRelease("kcm-invalid", "latest", kcm.ReleaseChannelNightly, true),
+	}
+
+	for _, tc := range []struct {
+		channel  kcm.ReleaseChannel
+		expected string
+	}{
+		{channel: kcm.ReleaseChannelStable, expected: "kcm-0-3-0"},
+		{channel: kcm.ReleaseChannelRC, expected: "kcm-0-4-0-rc-1"},
+		{channel: kcm.ReleaseChannelNightly, expected: "kcm-0-5-0-nightly"},
+	} {
+		t.Run(string(tc.channel), func(t *testing.T) {
+			g := NewWithT(t)
+
+			latest, err := latestChannelRelease(&current, releases, tc.channel)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(latest).NotTo(BeNil())
+			g.Expect(latest.Name).To(Equal(tc.expected))
+		})
+	}
+
+	t.Run("no newer releases", func(t *testing.T) {
+		g := NewWithT(t)
+
+		latest, err := latestChannelRelease(&current, releases[:2], kcm.ReleaseChannelNightly)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(latest).To(BeNil())
+	})
+}
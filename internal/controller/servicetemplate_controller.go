@@ -15,6 +15,7 @@
 package controller
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -424,7 +425,7 @@ func (r *ServiceTemplateReconciler) reconcileOCIRepository(
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ServiceTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.defaultRequeueTime = 1 * time.Minute
+	r.defaultRequeueTime = cmp.Or(r.RequeueInterval, time.Minute)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.TypedOptions[ctrl.Request]{
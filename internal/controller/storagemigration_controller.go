@@ -0,0 +1,182 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+// storageMigrationPageSize is the number of the objects listed at once upon migration.
+const storageMigrationPageSize = 500
+
+// migratedResources are the resources of the CRDs, by their API group, the
+// objects of which are migrated to the storage version. The controller is
+// granted to rewrite these resources only, the list must be kept in sync with
+// the storage-migration ClusterRole of the chart.
+var migratedResources = map[string][]string{
+	kcm.GroupVersion.Group: {
+		"accessmanagements", "auditevents", "clusterdeploymentrevisions", "clusterdeployments",
+		"clusterquotas", "clustertemplatechains", "clustertemplates", "credentials", "diagnostics",
+		"managementbackups", "managementrestores", "managements", "multiclusterservices",
+		"providertemplates", "regions", "releases", "servicetemplatechains", "servicetemplates",
+	},
+	clusterapiv1beta1.GroupVersion.Group: {
+		"clusterclasses", "clusters", "machinedeployments", "machinehealthchecks",
+		"machinepools", "machines", "machinesets",
+	},
+	"addons.cluster.x-k8s.io":   {"clusterresourcesetbindings", "clusterresourcesets"},
+	"ipam.cluster.x-k8s.io":     {"ipaddressclaims", "ipaddresses"},
+	"runtime.cluster.x-k8s.io":  {"extensionconfigs"},
+	"operator.cluster.x-k8s.io": {"addonproviders", "bootstrapproviders", "controlplaneproviders", "coreproviders", "infrastructureproviders", "ipamproviders", "runtimeextensionproviders"},
+}
+
+// StorageMigrationReconciler rewrites the objects of the CRDs stored in
+// several versions, e.g. after the upgrade of the Management, to the current
+// storage version and prunes the previous versions from the stored versions
+// of the CRD, so they can be safely removed by the next upgrades.
+type StorageMigrationReconciler struct {
+	client.Client
+
+	// reader lists the migrated objects bypassing the cache.
+	reader client.Reader
+}
+
+func (r *StorageMigrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := ctrl.LoggerFrom(ctx)
+
+	crd := new(apiextensionsv1.CustomResourceDefinition)
+	if err := r.Get(ctx, req.NamespacedName, crd); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	storageVersion := crdStorageVersion(crd)
+	if !needsStorageMigration(crd) || storageVersion == "" || !isCRDEstablished(crd) {
+		return ctrl.Result{}, nil
+	}
+
+	l.Info("Migrating objects to the storage version", "storedVersions", crd.Status.StoredVersions, "storageVersion", storageVersion)
+
+	migrated, conflicts, err := r.migrateObjects(ctx, crd, storageVersion)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if conflicts > 0 {
+		// the objects changed since they were listed are rewritten again
+		// before the previous versions are pruned
+		l.Info("Objects have been changed during the migration, requeueing", "objects", migrated, "conflicts", conflicts)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	crd.Status.StoredVersions = []string{storageVersion}
+	if err := r.Status().Update(ctx, crd); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update stored versions of CRD %s: %w", crd.Name, err)
+	}
+
+	l.Info("Migrated objects to the storage version", "storageVersion", storageVersion, "objects", migrated)
+	return ctrl.Result{}, nil
+}
+
+// migrateObjects rewrites all of the objects of the CRD unchanged, which
+// makes the API server to store them in the storage version, and returns the
+// number of the objects rewritten and of the ones failed to be rewritten due
+// to a conflict.
+func (r *StorageMigrationReconciler) migrateObjects(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, storageVersion string) (migrated, conflicts int, _ error) {
+	continueToken := ""
+	for {
+		list := new(unstructured.UnstructuredList)
+		list.SetAPIVersion(crd.Spec.Group + "/" + storageVersion)
+		list.SetKind(crd.Spec.Names.ListKind)
+		if err := r.reader.List(ctx, list, client.Limit(storageMigrationPageSize), client.Continue(continueToken)); err != nil {
+			return migrated, conflicts, fmt.Errorf("failed to list %s: %w", crd.Name, err)
+		}
+
+		for _, obj := range list.Items {
+			err := r.Update(ctx, &obj)
+			switch {
+			case err == nil:
+				migrated++
+			case apierrors.IsConflict(err):
+				conflicts++
+			case apierrors.IsNotFound(err):
+				// the deleted objects are not stored anymore
+			default:
+				return migrated, conflicts, fmt.Errorf("failed to migrate %s %s: %w", crd.Spec.Names.Kind, client.ObjectKeyFromObject(&obj), err)
+			}
+		}
+
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return migrated, conflicts, nil
+		}
+	}
+}
+
+func crdStorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+func isCRDEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	return slices.ContainsFunc(crd.Status.Conditions, func(c apiextensionsv1.CustomResourceDefinitionCondition) bool {
+		return c.Type == apiextensionsv1.Established && c.Status == apiextensionsv1.ConditionTrue
+	})
+}
+
+// needsStorageMigration reports whether the CRD of the migrated resources has
+// objects stored in other versions than the storage one.
+func needsStorageMigration(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	if !slices.Contains(migratedResources[crd.Spec.Group], crd.Spec.Names.Plural) {
+		return false
+	}
+
+	storageVersion := crdStorageVersion(crd)
+	return slices.ContainsFunc(crd.Status.StoredVersions, func(v string) bool { return v != storageVersion })
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StorageMigrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	r.reader = mgr.GetAPIReader()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("storagemigration").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		For(&apiextensionsv1.CustomResourceDefinition{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			crd, ok := o.(*apiextensionsv1.CustomResourceDefinition)
+			return ok && needsStorageMigration(crd)
+		}))).
+		Complete(r)
+}
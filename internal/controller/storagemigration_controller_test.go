@@ -0,0 +1,139 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestStorageMigrationReconciler_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	newCRD := func(plural, group, kind string, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: plural + "." + group},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: group,
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: plural, Kind: kind, ListKind: kind + "List"},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha0", Served: true},
+					{Name: "v1alpha1", Served: true, Storage: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				StoredVersions: storedVersions,
+				Conditions:     []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue}},
+			},
+		}
+	}
+
+	crd := newCRD("clusterdeployments", kcm.GroupVersion.Group, kcm.ClusterDeploymentKind, "v1alpha0", "v1alpha1")
+	foreignCRD := newCRD("foos", "example.com", "Foo", "v1alpha0", "v1alpha1")
+	// the resources of the migrated groups the controller is not granted to rewrite
+	providerCRD := newCRD("awsclusters", "infrastructure.cluster.x-k8s.io", "AWSCluster", "v1alpha0", "v1alpha1")
+
+	cds := []*kcm.ClusterDeployment{
+		{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "team"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "team"}},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&apiextensionsv1.CustomResourceDefinition{}).
+		WithObjects(crd, foreignCRD, cds[0], cds[1]).Build()
+
+	r := &StorageMigrationReconciler{Client: cl, reader: cl}
+
+	g.Expect(needsStorageMigration(crd)).To(BeTrue())
+	g.Expect(needsStorageMigration(foreignCRD)).To(BeFalse())
+	g.Expect(needsStorageMigration(providerCRD)).To(BeFalse())
+
+	resourceVersions := make(map[string]string)
+	for _, cd := range cds {
+		g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(cd), cd)).To(Succeed())
+		resourceVersions[cd.Name] = cd.ResourceVersion
+	}
+
+	_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(crd)})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(crd), crd)).To(Succeed())
+	g.Expect(crd.Status.StoredVersions).To(Equal([]string{"v1alpha1"}))
+	g.Expect(needsStorageMigration(crd)).To(BeFalse())
+
+	for _, cd := range cds {
+		migrated := new(kcm.ClusterDeployment)
+		g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(cd), migrated)).To(Succeed())
+		g.Expect(migrated.ResourceVersion).NotTo(Equal(resourceVersions[cd.Name]), "%s is expected to be rewritten", cd.Name)
+	}
+
+	// the stored versions are not pruned until the conflicting objects are rewritten
+	crd.Status.StoredVersions = []string{"v1alpha0", "v1alpha1"}
+	g.Expect(cl.Status().Update(t.Context(), crd)).To(Succeed())
+
+	conflicted := false
+	r.Client = interceptor.NewClient(cl, interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if !conflicted && obj.GetName() == "first" {
+				conflicted = true
+				return apierrors.NewConflict(schema.GroupResource{Group: kcm.GroupVersion.Group, Resource: "clusterdeployments"}, obj.GetName(), errors.New("the object has been modified"))
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	})
+
+	res, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(crd)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.Requeue).To(BeTrue())
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(crd), crd)).To(Succeed())
+	g.Expect(crd.Status.StoredVersions).To(Equal([]string{"v1alpha0", "v1alpha1"}))
+
+	res, err = r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(crd)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.Requeue).To(BeFalse())
+	g.Expect(cl.Get(t.Context(), client.ObjectKeyFromObject(crd), crd)).To(Succeed())
+	g.Expect(crd.Status.StoredVersions).To(Equal([]string{"v1alpha1"}))
+}
+
+func Test_migratedResources(t *testing.T) {
+	g := NewWithT(t)
+
+	// all of the kcm CRDs are migrated
+	crds, err := filepath.Glob(filepath.Join("..", "..", "templates", "provider", "kcm", "templates", "crds", kcm.GroupVersion.Group+"_*.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(crds).NotTo(BeEmpty())
+
+	resources := make([]string, 0, len(crds))
+	for _, crd := range crds {
+		resources = append(resources, strings.TrimSuffix(strings.TrimPrefix(filepath.Base(crd), kcm.GroupVersion.Group+"_"), ".yaml"))
+	}
+	g.Expect(migratedResources[kcm.GroupVersion.Group]).To(ConsistOf(resources))
+}
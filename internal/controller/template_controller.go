@@ -15,8 +15,8 @@
 package controller
 
 import (
+	"cmp"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
@@ -54,6 +54,9 @@ type TemplateReconciler struct {
 	SystemNamespace       string
 	DefaultRegistryConfig helm.DefaultRegistryConfig
 	CreateManagement      bool
+	// RequeueInterval is the interval the templates are requeued at to be
+	// revalidated, 1m if not set.
+	RequeueInterval time.Duration
 
 	defaultRequeueTime time.Duration
 }
@@ -215,7 +218,12 @@ func (r *TemplateReconciler) ReconcileTemplate(ctx context.Context, template tem
 			if namespace == "" {
 				namespace = r.SystemNamespace
 			}
-			err := helm.ReconcileHelmRepository(ctx, r.Client, kcm.DefaultRepoName, namespace, r.DefaultRegistryConfig.HelmRepositorySpec())
+			registryConfig, err := r.registryConfig(ctx)
+			if err != nil {
+				l.Error(err, "Failed to get registry config")
+				return ctrl.Result{}, err
+			}
+			err = helm.ReconcileHelmRepository(ctx, r.Client, kcm.DefaultRepoName, namespace, registryConfig.HelmRepositorySpec())
 			if err != nil {
 				l.Error(err, "Failed to reconcile default HelmRepository")
 				return ctrl.Result{}, err
@@ -255,55 +263,56 @@ func (r *TemplateReconciler) ReconcileTemplate(ctx context.Context, template tem
 		r.downloadHelmChartFunc = helm.DownloadChartFromArtifact
 	}
 
-	l.Info("Downloading Helm chart")
-	helmChart, err := r.downloadHelmChartFunc(ctx, artifact)
+	validation, err := helm.ValidateChart(ctx, artifact.Digest, func() (*helm.ChartValidation, error) {
+		l.Info("Downloading Helm chart")
+		helmChart, err := r.downloadHelmChartFunc(ctx, artifact)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download chart: %w", err)
+		}
+
+		l.Info("Validating Helm chart")
+		return helm.NewChartValidation(helmChart)
+	})
 	if err != nil {
-		l.Error(err, "Failed to download Helm chart")
-		err = fmt.Errorf("failed to download chart: %w", err)
+		l.Error(err, "Failed to validate Helm chart")
 		_ = r.updateStatus(ctx, template, err.Error())
 		return ctrl.Result{}, err
 	}
 
-	l.Info("Validating Helm chart")
-	if err := helmChart.Validate(); err != nil {
+	if validation.Error != "" {
+		err := errors.New(validation.Error)
 		l.Error(err, "Helm chart validation failed")
 		_ = r.updateStatus(ctx, template, err.Error())
 		return ctrl.Result{}, err
 	}
 
 	l.Info("Parsing Helm chart metadata")
-	if err := fillStatusWithProviders(template, helmChart); err != nil {
+	if err := template.FillStatusWithProviders(validation.Annotations); err != nil {
 		l.Error(err, "Failed to fill status with providers")
 		_ = r.updateStatus(ctx, template, err.Error())
 		return ctrl.Result{}, err
 	}
 
-	status.Description = helmChart.Metadata.Description
-
-	rawValues, err := json.Marshal(helmChart.Values)
-	if err != nil {
-		l.Error(err, "Failed to parse Helm chart values")
-		err = fmt.Errorf("failed to parse Helm chart values: %w", err)
-		_ = r.updateStatus(ctx, template, err.Error())
-		return ctrl.Result{}, err
-	}
-	status.Config = &apiextensionsv1.JSON{Raw: rawValues}
+	status.Description = validation.Description
+	status.Config = &apiextensionsv1.JSON{Raw: validation.Values}
 
 	l.Info("Chart validation completed successfully")
 
 	return ctrl.Result{}, r.updateStatus(ctx, template, "")
 }
 
-func templateManagedByKCM(template templateCommon) bool {
-	return template.GetLabels()[kcm.KCMManagedLabelKey] == kcm.KCMManagedLabelValue
-}
-
-func fillStatusWithProviders(template templateCommon, helmChart *chart.Chart) error {
-	if helmChart.Metadata == nil {
-		return errors.New("chart metadata is empty")
+// registryConfig returns the config of the default registry taking into
+// account the registry mirror of the Management.
+func (r *TemplateReconciler) registryConfig(ctx context.Context) (*helm.DefaultRegistryConfig, error) {
+	mgmt := new(kcm.Management)
+	if err := r.Get(ctx, client.ObjectKey{Name: kcm.ManagementName}, mgmt); client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to get Management: %w", err)
 	}
+	return r.DefaultRegistryConfig.WithMirror(mgmt.Spec.RegistryMirror)
+}
 
-	return template.FillStatusWithProviders(helmChart.Metadata.Annotations)
+func templateManagedByKCM(template templateCommon) bool {
+	return template.GetLabels()[kcm.KCMManagedLabelKey] == kcm.KCMManagedLabelValue
 }
 
 func (r *TemplateReconciler) updateStatus(ctx context.Context, template templateCommon, validationError string) error {
@@ -437,7 +446,7 @@ func (r *ClusterTemplateReconciler) validateCompatibilityAttrs(ctx context.Conte
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.defaultRequeueTime = 1 * time.Minute
+	r.defaultRequeueTime = cmp.Or(r.RequeueInterval, time.Minute)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.TypedOptions[ctrl.Request]{
@@ -503,7 +512,7 @@ func (r *ClusterTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ProviderTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.defaultRequeueTime = 1 * time.Minute
+	r.defaultRequeueTime = cmp.Or(r.RequeueInterval, time.Minute)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.TypedOptions[ctrl.Request]{
@@ -0,0 +1,218 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/metrics"
+	"github.com/K0rdent/kcm/internal/utils/ratelimit"
+)
+
+// TemplateUsageReconciler counts the ClusterDeployments and the
+// MultiClusterServices referencing each of the ClusterTemplates and
+// ServiceTemplates and reports the numbers in the status of the templates.
+// The names of the reconciled requests are the namespaces of the templates.
+type TemplateUsageReconciler struct {
+	client.Client
+
+	SystemNamespace string
+}
+
+func (r *TemplateUsageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	namespace := req.Name
+
+	clusterTemplates := new(kcm.ClusterTemplateList)
+	if err := r.List(ctx, clusterTemplates, client.InNamespace(namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list ClusterTemplates: %w", err)
+	}
+	serviceTemplates := new(kcm.ServiceTemplateList)
+	if err := r.List(ctx, serviceTemplates, client.InNamespace(namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list ServiceTemplates: %w", err)
+	}
+	clusterDeployments := new(kcm.ClusterDeploymentList)
+	if err := r.List(ctx, clusterDeployments, client.InNamespace(namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list ClusterDeployments: %w", err)
+	}
+	// the services of the MultiClusterServices are deployed from the
+	// templates of the system namespace
+	multiClusterServices := new(kcm.MultiClusterServiceList)
+	if namespace == r.SystemNamespace {
+		if err := r.List(ctx, multiClusterServices); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to list MultiClusterServices: %w", err)
+		}
+	}
+
+	clusterUsage, serviceUsage := templateUsage(clusterDeployments.Items, multiClusterServices.Items)
+
+	var errs error
+
+	metrics.DeleteMetricsTemplateReferences(kcm.ClusterTemplateKind, namespace)
+	for i := range clusterTemplates.Items {
+		template := &clusterTemplates.Items[i]
+		usage := &kcm.TemplateUsage{ClusterDeployments: clusterUsage[template.Name]}
+		metrics.TrackMetricTemplateReferences(ctx, kcm.ClusterTemplateKind, namespace, template.Name, kcm.ClusterDeploymentKind, usage.ClusterDeployments)
+		errs = errors.Join(errs, r.updateUsage(ctx, template, &template.Status.Usage, usage))
+	}
+
+	metrics.DeleteMetricsTemplateReferences(kcm.ServiceTemplateKind, namespace)
+	for i := range serviceTemplates.Items {
+		template := &serviceTemplates.Items[i]
+		usage := &kcm.TemplateUsage{}
+		if u, ok := serviceUsage[template.Name]; ok {
+			usage = u
+		}
+		metrics.TrackMetricTemplateReferences(ctx, kcm.ServiceTemplateKind, namespace, template.Name, kcm.ClusterDeploymentKind, usage.ClusterDeployments)
+		metrics.TrackMetricTemplateReferences(ctx, kcm.ServiceTemplateKind, namespace, template.Name, kcm.MultiClusterServiceKind, usage.MultiClusterServices)
+		errs = errors.Join(errs, r.updateUsage(ctx, template, &template.Status.Usage, usage))
+	}
+
+	return ctrl.Result{}, errs
+}
+
+// updateUsage patches the usage in the status of the template if it has changed.
+func (r *TemplateUsageReconciler) updateUsage(ctx context.Context, template client.Object, current **kcm.TemplateUsage, usage *kcm.TemplateUsage) error {
+	if equality.Semantic.DeepEqual(*current, usage) {
+		return nil
+	}
+
+	patch := client.MergeFrom(template.DeepCopyObject().(client.Object))
+	*current = usage
+	if err := r.Status().Patch(ctx, template, patch); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to update usage in the template %s status: %w", client.ObjectKeyFromObject(template), err)
+	}
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Updated template usage", "template", client.ObjectKeyFromObject(template),
+		"clusterDeployments", usage.ClusterDeployments, "multiClusterServices", usage.MultiClusterServices)
+	return nil
+}
+
+// templateUsage returns the number of the given ClusterDeployments
+// referencing each of the ClusterTemplates either in the spec or as the
+// applied one, so the templates the clusters are being upgraded from are
+// still counted, and the number of the ClusterDeployments and the
+// MultiClusterServices referencing each of the ServiceTemplates.
+func templateUsage(clusterDeployments []kcm.ClusterDeployment, multiClusterServices []kcm.MultiClusterService) (map[string]int32, map[string]*kcm.TemplateUsage) {
+	clusterUsage := make(map[string]int32)
+	serviceUsage := make(map[string]*kcm.TemplateUsage)
+	serviceTemplateUsage := func(name string) *kcm.TemplateUsage {
+		if _, ok := serviceUsage[name]; !ok {
+			serviceUsage[name] = new(kcm.TemplateUsage)
+		}
+		return serviceUsage[name]
+	}
+
+	for _, cd := range clusterDeployments {
+		clusterUsage[cd.Spec.Template]++
+		if cd.Status.AppliedTemplate != "" && cd.Status.AppliedTemplate != cd.Spec.Template {
+			clusterUsage[cd.Status.AppliedTemplate]++
+		}
+
+		for _, name := range uniqueServiceTemplates(cd.Spec.ServiceSpec.Services) {
+			serviceTemplateUsage(name).ClusterDeployments++
+		}
+	}
+
+	for _, mcs := range multiClusterServices {
+		for _, name := range uniqueServiceTemplates(mcs.Spec.ServiceSpec.Services) {
+			serviceTemplateUsage(name).MultiClusterServices++
+		}
+	}
+
+	return clusterUsage, serviceUsage
+}
+
+// uniqueServiceTemplates returns the names of the ServiceTemplates of the
+// services, each of them once.
+func uniqueServiceTemplates(services []kcm.Service) []string {
+	seen := make(map[string]struct{}, len(services))
+	names := make([]string, 0, len(services))
+	for _, svc := range services {
+		if _, ok := seen[svc.Template]; ok {
+			continue
+		}
+		seen[svc.Template] = struct{}{}
+		names = append(names, svc.Template)
+	}
+	return names
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TemplateUsageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+
+	enqueueNamespace := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []ctrl.Request {
+		return []ctrl.Request{{NamespacedName: client.ObjectKey{Name: o.GetNamespace()}}}
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("templateusage").
+		WithOptions(controller.TypedOptions[ctrl.Request]{
+			RateLimiter: ratelimit.DefaultFastSlow(),
+		}).
+		Watches(&kcm.ClusterTemplate{}, enqueueNamespace).
+		Watches(&kcm.ServiceTemplate{}, enqueueNamespace).
+		Watches(&kcm.ClusterDeployment{}, enqueueNamespace, builder.WithPredicates(predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldCD, ok := e.ObjectOld.(*kcm.ClusterDeployment)
+				if !ok {
+					return false
+				}
+				newCD, ok := e.ObjectNew.(*kcm.ClusterDeployment)
+				if !ok {
+					return false
+				}
+				return clusterDeploymentTemplatesChanged(oldCD, newCD)
+			},
+		})).
+		Watches(&kcm.MultiClusterService{}, handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []ctrl.Request {
+			return []ctrl.Request{{NamespacedName: client.ObjectKey{Name: r.SystemNamespace}}}
+		}), builder.WithPredicates(predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldMCS, ok := e.ObjectOld.(*kcm.MultiClusterService)
+				if !ok {
+					return false
+				}
+				newMCS, ok := e.ObjectNew.(*kcm.MultiClusterService)
+				if !ok {
+					return false
+				}
+				return !slices.Equal(uniqueServiceTemplates(oldMCS.Spec.ServiceSpec.Services), uniqueServiceTemplates(newMCS.Spec.ServiceSpec.Services))
+			},
+		})).
+		Complete(r)
+}
+
+// clusterDeploymentTemplatesChanged reports whether the templates referenced
+// by the ClusterDeployment, either in the spec, as the applied one or by the
+// services, have changed.
+func clusterDeploymentTemplatesChanged(oldCD, newCD *kcm.ClusterDeployment) bool {
+	return oldCD.Spec.Template != newCD.Spec.Template ||
+		oldCD.Status.AppliedTemplate != newCD.Status.AppliedTemplate ||
+		!slices.Equal(uniqueServiceTemplates(oldCD.Spec.ServiceSpec.Services), uniqueServiceTemplates(newCD.Spec.ServiceSpec.Services))
+}
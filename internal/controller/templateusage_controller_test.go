@@ -0,0 +1,127 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestTemplateUsageReconciler_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	const namespace = "kcm-system"
+
+	newClusterTemplate := func(name string) *kcm.ClusterTemplate {
+		return &kcm.ClusterTemplate{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+	newServiceTemplate := func(name string) *kcm.ServiceTemplate {
+		return &kcm.ServiceTemplate{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+	newClusterDeployment := func(name, template, appliedTemplate string, serviceTemplates ...string) *kcm.ClusterDeployment {
+		cd := &kcm.ClusterDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       kcm.ClusterDeploymentSpec{Template: template},
+			Status:     kcm.ClusterDeploymentStatus{AppliedTemplate: appliedTemplate},
+		}
+		for _, tmpl := range serviceTemplates {
+			cd.Spec.ServiceSpec.Services = append(cd.Spec.ServiceSpec.Services, kcm.Service{Name: tmpl, Template: tmpl})
+		}
+		return cd
+	}
+
+	mcs := &kcm.MultiClusterService{
+		ObjectMeta: metav1.ObjectMeta{Name: "mcs"},
+		Spec: kcm.MultiClusterServiceSpec{ServiceSpec: kcm.ServiceSpec{Services: []kcm.Service{
+			{Name: "ingress-nginx", Template: "ingress-nginx-4-12-1"},
+		}}},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithStatusSubresource(&kcm.ClusterTemplate{}, &kcm.ServiceTemplate{}).
+		WithObjects(
+			newClusterTemplate("aws-0-1-0"),
+			newClusterTemplate("aws-0-2-0"),
+			newClusterTemplate("aws-0-3-0"),
+			newServiceTemplate("ingress-nginx-4-12-1"),
+			newServiceTemplate("cert-manager-1-17-1"),
+			newClusterDeployment("ready", "aws-0-1-0", "aws-0-1-0", "ingress-nginx-4-12-1", "ingress-nginx-4-12-1"),
+			newClusterDeployment("upgrading", "aws-0-2-0", "aws-0-1-0"),
+			mcs,
+		).Build()
+
+	r := &TemplateUsageReconciler{Client: cl, SystemNamespace: namespace}
+	_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKey{Name: namespace}})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for name, expected := range map[string]int32{"aws-0-1-0": 2, "aws-0-2-0": 1, "aws-0-3-0": 0} {
+		template := new(kcm.ClusterTemplate)
+		g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: namespace, Name: name}, template)).To(Succeed())
+		g.Expect(template.Status.Usage).To(Equal(&kcm.TemplateUsage{ClusterDeployments: expected}), name)
+	}
+	for name, expected := range map[string]*kcm.TemplateUsage{
+		"ingress-nginx-4-12-1": {ClusterDeployments: 1, MultiClusterServices: 1},
+		"cert-manager-1-17-1":  {},
+	} {
+		template := new(kcm.ServiceTemplate)
+		g.Expect(cl.Get(t.Context(), client.ObjectKey{Namespace: namespace, Name: name}, template)).To(Succeed())
+		g.Expect(template.Status.Usage).To(Equal(expected), name)
+	}
+}
+
+func Test_clusterDeploymentTemplatesChanged(t *testing.T) {
+	cd := &kcm.ClusterDeployment{
+		Spec: kcm.ClusterDeploymentSpec{
+			Template:    "aws-0-2-0",
+			ServiceSpec: kcm.ServiceSpec{Services: []kcm.Service{{Name: "ingress", Template: "ingress-nginx-4-12-1"}}},
+		},
+		Status: kcm.ClusterDeploymentStatus{AppliedTemplate: "aws-0-1-0"},
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(cd *kcm.ClusterDeployment)
+		expected bool
+	}{
+		{name: "unrelated change", modify: func(cd *kcm.ClusterDeployment) {
+			cd.Spec.DryRun = true
+			cd.Spec.ServiceSpec.Services[0].Namespace = "ingress"
+		}},
+		{name: "template", modify: func(cd *kcm.ClusterDeployment) { cd.Spec.Template = "aws-0-3-0" }, expected: true},
+		{name: "applied template", modify: func(cd *kcm.ClusterDeployment) { cd.Status.AppliedTemplate = "aws-0-2-0" }, expected: true},
+		{name: "service template", modify: func(cd *kcm.ClusterDeployment) {
+			cd.Spec.ServiceSpec.Services[0].Template = "ingress-nginx-4-12-2"
+		}, expected: true},
+		{name: "removed service", modify: func(cd *kcm.ClusterDeployment) { cd.Spec.ServiceSpec.Services = nil }, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			newCD := cd.DeepCopy()
+			tt.modify(newCD)
+			g.Expect(clusterDeploymentTemplatesChanged(cd, newCD)).To(Equal(tt.expected))
+		})
+	}
+}
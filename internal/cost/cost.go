@@ -0,0 +1,106 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cost estimates the monthly cost of the ClusterDeployments from the
+// prices of the instance types of their node pools.
+package cost
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/providers"
+	"github.com/K0rdent/kcm/internal/quota"
+)
+
+// hoursPerMonth is the average number of hours in a month.
+const hoursPerMonth = 730
+
+// PriceListFor returns the hourly prices of the instance types of the first
+// of the given providers with the price list, nil if none of them has it.
+func PriceListFor(cfg *kcm.CostEstimation, templateProviders []string) map[string]string {
+	for _, p := range templateProviders {
+		if !strings.HasPrefix(p, providers.InfraPrefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(p, providers.InfraPrefix)
+		if idx := slices.IndexFunc(cfg.PriceLists, func(l kcm.PriceList) bool { return l.Provider == name }); idx >= 0 {
+			return cfg.PriceLists[idx].HourlyPrices
+		}
+	}
+
+	return nil
+}
+
+// Estimate returns the estimated cost of the ClusterDeployment deployed from
+// the ClusterTemplate with the given providers. The instance types missing
+// from the price list are reported as unpriced.
+func Estimate(cfg *kcm.CostEstimation, cd *kcm.ClusterDeployment, templateProviders []string) (*kcm.CostEstimate, error) {
+	pools, err := quota.NodePoolsOf(cd)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := PriceListFor(cfg, templateProviders)
+	estimate := &kcm.CostEstimate{Currency: cfg.Currency}
+
+	var monthly float64
+	for _, pool := range pools {
+		if pool.InstanceType == "" {
+			continue
+		}
+
+		price, ok := prices[pool.InstanceType]
+		if !ok {
+			estimate.UnpricedInstanceTypes = append(estimate.UnpricedInstanceTypes, pool.InstanceType)
+			continue
+		}
+
+		hourly, err := strconv.ParseFloat(price, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %q of the instance type %s: %w", price, pool.InstanceType, err)
+		}
+		monthly += hourly * float64(pool.Nodes) * hoursPerMonth
+	}
+
+	slices.Sort(estimate.UnpricedInstanceTypes)
+	estimate.UnpricedInstanceTypes = slices.Compact(estimate.UnpricedInstanceTypes)
+	estimate.MonthlyCost = strconv.FormatFloat(monthly, 'f', 2, 64)
+
+	return estimate, nil
+}
+
+// BudgetWarning returns the warning about the estimate exceeding the monthly
+// budget, empty if it does not or the budget is not set.
+func BudgetWarning(cfg *kcm.CostEstimation, estimate *kcm.CostEstimate) string {
+	if cfg.MonthlyBudget == "" {
+		return ""
+	}
+
+	budget, err := strconv.ParseFloat(cfg.MonthlyBudget, 64)
+	if err != nil {
+		return ""
+	}
+	monthly, err := strconv.ParseFloat(estimate.MonthlyCost, 64)
+	if err != nil || monthly <= budget {
+		return ""
+	}
+
+	return fmt.Sprintf("The estimated monthly cost of the ClusterDeployment %s %s exceeds the budget of %s %s",
+		estimate.MonthlyCost, cfg.Currency, cfg.MonthlyBudget, cfg.Currency)
+}
@@ -0,0 +1,84 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cost
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/objects/clusterdeployment"
+)
+
+func TestEstimate(t *testing.T) {
+	cfg := &kcm.CostEstimation{
+		Currency: "USD",
+		PriceLists: []kcm.PriceList{
+			{Provider: "azure", HourlyPrices: map[string]string{"Standard_A4_v2": "0.2"}},
+			{Provider: "aws", HourlyPrices: map[string]string{"t3.large": "0.0832", "t3.small": "0.0208"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		config    string
+		providers []string
+		want      *kcm.CostEstimate
+	}{
+		{
+			name:      "priced node pools",
+			config:    `{"controlPlaneNumber":3,"workersNumber":2,"controlPlane":{"instanceType":"t3.large"},"worker":{"instanceType":"t3.small"}}`,
+			providers: []string{"bootstrap-k0sproject-k0smotron", "infrastructure-aws"},
+			want:      &kcm.CostEstimate{MonthlyCost: "212.58", Currency: "USD"},
+		},
+		{
+			name:      "unpriced instance type",
+			config:    `{"controlPlaneNumber":1,"workersNumber":2,"controlPlane":{"instanceType":"t3.large"},"worker":{"instanceType":"m5.large"}}`,
+			providers: []string{"infrastructure-aws"},
+			want:      &kcm.CostEstimate{MonthlyCost: "60.74", Currency: "USD", UnpricedInstanceTypes: []string{"m5.large"}},
+		},
+		{
+			name:      "no price list of the provider",
+			config:    `{"workersNumber":2,"worker":{"flavor":"m1.large"}}`,
+			providers: []string{"infrastructure-openstack"},
+			want:      &kcm.CostEstimate{MonthlyCost: "0.00", Currency: "USD", UnpricedInstanceTypes: []string{"m1.large"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := Estimate(cfg, clusterdeployment.NewClusterDeployment(clusterdeployment.WithConfig(tt.config)), tt.providers)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestBudgetWarning(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := &kcm.CostEstimation{Currency: "EUR"}
+	estimate := &kcm.CostEstimate{MonthlyCost: "212.58", Currency: "EUR"}
+	g.Expect(BudgetWarning(cfg, estimate)).To(BeEmpty())
+
+	cfg.MonthlyBudget = "500"
+	g.Expect(BudgetWarning(cfg, estimate)).To(BeEmpty())
+
+	cfg.MonthlyBudget = "200"
+	g.Expect(BudgetWarning(cfg, estimate)).To(Equal("The estimated monthly cost of the ClusterDeployment 212.58 EUR exceeds the budget of 200 EUR"))
+}
@@ -0,0 +1,386 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diagnostics runs the checks of the health of the management
+// cluster and reports the problems found.
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	fluxv2 "github.com/fluxcd/helm-controller/api/v2"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+const (
+	defaultStuckFinalizerThreshold = 30 * time.Minute
+	webhookProbeTimeout            = 5 * time.Second
+)
+
+// checkedCRDGroups are the API groups of the CRDs the versions of which are checked.
+var checkedCRDGroups = []string{
+	kcm.GroupVersion.Group,
+	clusterapiv1beta1.GroupVersion.Group,
+	"toolkit.fluxcd.io",
+	"projectsveltos.io",
+}
+
+// finalizedKinds are the kinds of the objects checked for the stuck finalizers.
+var finalizedKinds = []schema.GroupVersionKind{
+	kcm.GroupVersion.WithKind(kcm.ManagementKind),
+	kcm.GroupVersion.WithKind(kcm.ClusterDeploymentKind),
+	kcm.GroupVersion.WithKind(kcm.MultiClusterServiceKind),
+	kcm.GroupVersion.WithKind(kcm.CredentialKind),
+	kcm.GroupVersion.WithKind(kcm.RegionKind),
+	kcm.GroupVersion.WithKind(kcm.ClusterTemplateKind),
+	kcm.GroupVersion.WithKind(kcm.ServiceTemplateKind),
+	kcm.GroupVersion.WithKind(kcm.ProviderTemplateKind),
+	clusterapiv1beta1.GroupVersion.WithKind(clusterapiv1beta1.ClusterKind),
+	fluxv2.GroupVersion.WithKind(fluxv2.HelmReleaseKind),
+}
+
+// Engine runs the diagnostic checks.
+type Engine struct {
+	Client client.Reader
+
+	// ProbeWebhook returns an error if the webhook service is not reachable
+	// at the given address. The reachability is not probed if not set.
+	ProbeWebhook func(ctx context.Context, address string) error
+}
+
+type check func(context.Context, kcm.DiagnosticsSpec) ([]kcm.DiagnosticFinding, error)
+
+// Run runs the checks of the given spec and returns the findings. The checks
+// failed to be run do not prevent the rest of them from being run and are
+// reported by the returned error.
+func (e *Engine) Run(ctx context.Context, spec kcm.DiagnosticsSpec) ([]kcm.DiagnosticFinding, error) {
+	checks := []struct {
+		run  check
+		name kcm.DiagnosticCheck
+	}{
+		{name: kcm.DiagnosticCheckCRDVersions, run: e.checkCRDVersions},
+		{name: kcm.DiagnosticCheckWebhooks, run: e.checkWebhooks},
+		{name: kcm.DiagnosticCheckProviders, run: e.checkProviders},
+		{name: kcm.DiagnosticCheckStuckFinalizers, run: e.checkStuckFinalizers},
+		{name: kcm.DiagnosticCheckOrphanedHelmReleases, run: e.checkOrphanedHelmReleases},
+	}
+
+	var (
+		findings []kcm.DiagnosticFinding
+		errs     error
+	)
+	for _, c := range checks {
+		if len(spec.Checks) > 0 && !slices.Contains(spec.Checks, c.name) {
+			continue
+		}
+
+		f, err := c.run(ctx, spec)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to run the %s check: %w", c.name, err))
+		}
+		for i := range f {
+			f[i].Check = c.name
+		}
+		findings = append(findings, f...)
+	}
+
+	return findings, errs
+}
+
+// TCPProbe returns an error if the TCP connection to the given address
+// cannot be established.
+func TCPProbe(ctx context.Context, address string) error {
+	dialer := &net.Dialer{Timeout: webhookProbeTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkCRDVersions reports the CRDs which are not established or have
+// objects stored in the versions not served or in several versions.
+func (e *Engine) checkCRDVersions(ctx context.Context, _ kcm.DiagnosticsSpec) ([]kcm.DiagnosticFinding, error) {
+	crds := new(apiextensionsv1.CustomResourceDefinitionList)
+	if err := e.Client.List(ctx, crds); err != nil {
+		return nil, fmt.Errorf("failed to list CustomResourceDefinitions: %w", err)
+	}
+
+	var findings []kcm.DiagnosticFinding
+	for _, crd := range crds.Items {
+		if !slices.ContainsFunc(checkedCRDGroups, func(group string) bool {
+			return crd.Spec.Group == group || strings.HasSuffix(crd.Spec.Group, "."+group)
+		}) {
+			continue
+		}
+
+		object := &kcm.DiagnosticObject{Kind: "CustomResourceDefinition", Name: crd.Name}
+		for _, cond := range crd.Status.Conditions {
+			if cond.Type == apiextensionsv1.Established && cond.Status != apiextensionsv1.ConditionTrue {
+				findings = append(findings, kcm.DiagnosticFinding{
+					Object:   object,
+					Severity: kcm.DiagnosticSeverityError,
+					Message:  "CRD is not established: " + cond.Message,
+				})
+			}
+		}
+
+		for _, stored := range crd.Status.StoredVersions {
+			if !slices.ContainsFunc(crd.Spec.Versions, func(v apiextensionsv1.CustomResourceDefinitionVersion) bool {
+				return v.Name == stored && v.Served
+			}) {
+				findings = append(findings, kcm.DiagnosticFinding{
+					Object:   object,
+					Severity: kcm.DiagnosticSeverityError,
+					Message:  fmt.Sprintf("objects are stored in the version %s not served by the CRD", stored),
+				})
+			}
+		}
+
+		if len(crd.Status.StoredVersions) > 1 {
+			findings = append(findings, kcm.DiagnosticFinding{
+				Object:   object,
+				Severity: kcm.DiagnosticSeverityWarning,
+				Message:  fmt.Sprintf("objects are stored in several versions %s, the storage migration is pending", strings.Join(crd.Status.StoredVersions, ", ")),
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// checkWebhooks reports the admission webhooks the services of which are
+// missing, have no ready endpoints or are not reachable. The findings of the
+// webhooks failing the requests are reported with the Error severity.
+func (e *Engine) checkWebhooks(ctx context.Context, _ kcm.DiagnosticsSpec) ([]kcm.DiagnosticFinding, error) {
+	validating := new(admissionregistrationv1.ValidatingWebhookConfigurationList)
+	if err := e.Client.List(ctx, validating); err != nil {
+		return nil, fmt.Errorf("failed to list ValidatingWebhookConfigurations: %w", err)
+	}
+	mutating := new(admissionregistrationv1.MutatingWebhookConfigurationList)
+	if err := e.Client.List(ctx, mutating); err != nil {
+		return nil, fmt.Errorf("failed to list MutatingWebhookConfigurations: %w", err)
+	}
+
+	var findings []kcm.DiagnosticFinding
+	for _, cfg := range validating.Items {
+		object := &kcm.DiagnosticObject{Kind: "ValidatingWebhookConfiguration", Name: cfg.Name}
+		for _, wh := range cfg.Webhooks {
+			f, err := e.checkWebhook(ctx, object, wh.Name, wh.ClientConfig, wh.FailurePolicy)
+			if err != nil {
+				return nil, err
+			}
+			findings = append(findings, f...)
+		}
+	}
+	for _, cfg := range mutating.Items {
+		object := &kcm.DiagnosticObject{Kind: "MutatingWebhookConfiguration", Name: cfg.Name}
+		for _, wh := range cfg.Webhooks {
+			f, err := e.checkWebhook(ctx, object, wh.Name, wh.ClientConfig, wh.FailurePolicy)
+			if err != nil {
+				return nil, err
+			}
+			findings = append(findings, f...)
+		}
+	}
+
+	return findings, nil
+}
+
+func (e *Engine) checkWebhook(ctx context.Context, object *kcm.DiagnosticObject, name string, clientConfig admissionregistrationv1.WebhookClientConfig, failurePolicy *admissionregistrationv1.FailurePolicyType) ([]kcm.DiagnosticFinding, error) {
+	ref := clientConfig.Service
+	if ref == nil {
+		return nil, nil
+	}
+
+	severity := kcm.DiagnosticSeverityError
+	if failurePolicy != nil && *failurePolicy == admissionregistrationv1.Ignore {
+		severity = kcm.DiagnosticSeverityWarning
+	}
+	finding := func(severity kcm.DiagnosticSeverity, format string, args ...any) []kcm.DiagnosticFinding {
+		return []kcm.DiagnosticFinding{{
+			Object:   object,
+			Severity: severity,
+			Message:  fmt.Sprintf("webhook %s: ", name) + fmt.Sprintf(format, args...),
+		}}
+	}
+
+	svcKey := client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+	if err := e.Client.Get(ctx, svcKey, new(corev1.Service)); err != nil {
+		if apierrors.IsNotFound(err) {
+			return finding(severity, "service %s is not found", svcKey), nil
+		}
+		return nil, fmt.Errorf("failed to get Service %s: %w", svcKey, err)
+	}
+
+	endpointSlices := new(discoveryv1.EndpointSliceList)
+	if err := e.Client.List(ctx, endpointSlices, client.InNamespace(ref.Namespace), client.MatchingLabels{discoveryv1.LabelServiceName: ref.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list EndpointSlices of the Service %s: %w", svcKey, err)
+	}
+	if !hasReadyEndpoints(endpointSlices.Items) {
+		return finding(severity, "service %s has no ready endpoints", svcKey), nil
+	}
+
+	if e.ProbeWebhook != nil {
+		port := int32(443)
+		if ref.Port != nil {
+			port = *ref.Port
+		}
+		address := net.JoinHostPort(fmt.Sprintf("%s.%s.svc", ref.Name, ref.Namespace), fmt.Sprint(port))
+		if err := e.ProbeWebhook(ctx, address); err != nil {
+			return finding(severity, "service %s is not reachable: %s", svcKey, err), nil
+		}
+	}
+
+	if len(clientConfig.CABundle) == 0 {
+		return finding(kcm.DiagnosticSeverityWarning, "CA bundle is not set"), nil
+	}
+
+	return nil, nil
+}
+
+func hasReadyEndpoints(endpointSlices []discoveryv1.EndpointSlice) bool {
+	for _, s := range endpointSlices {
+		for _, ep := range s.Endpoints {
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkProviders reports the components of the Management failed to be installed.
+func (e *Engine) checkProviders(ctx context.Context, _ kcm.DiagnosticsSpec) ([]kcm.DiagnosticFinding, error) {
+	mgmt := new(kcm.Management)
+	if err := e.Client.Get(ctx, client.ObjectKey{Name: kcm.ManagementName}, mgmt); err != nil {
+		if apierrors.IsNotFound(err) {
+			return []kcm.DiagnosticFinding{{
+				Severity: kcm.DiagnosticSeverityError,
+				Message:  fmt.Sprintf("Management %s is not found", kcm.ManagementName),
+			}}, nil
+		}
+		return nil, fmt.Errorf("failed to get Management %s: %w", kcm.ManagementName, err)
+	}
+
+	object := &kcm.DiagnosticObject{Kind: kcm.ManagementKind, Name: mgmt.Name}
+
+	var findings []kcm.DiagnosticFinding
+	for _, name := range slices.Sorted(maps.Keys(mgmt.Status.Components)) {
+		component := mgmt.Status.Components[name]
+		if component.Success {
+			continue
+		}
+
+		msg := fmt.Sprintf("component %s is not installed", name)
+		if component.Error != "" {
+			msg += ": " + component.Error
+		}
+		findings = append(findings, kcm.DiagnosticFinding{Object: object, Severity: kcm.DiagnosticSeverityError, Message: msg})
+	}
+
+	return findings, nil
+}
+
+// checkStuckFinalizers reports the objects being deleted for longer than the
+// threshold. The kinds not installed to the cluster are skipped.
+func (e *Engine) checkStuckFinalizers(ctx context.Context, spec kcm.DiagnosticsSpec) ([]kcm.DiagnosticFinding, error) {
+	threshold := defaultStuckFinalizerThreshold
+	if spec.StuckFinalizerThreshold != nil {
+		threshold = spec.StuckFinalizerThreshold.Duration
+	}
+
+	var findings []kcm.DiagnosticFinding
+	for _, gvk := range finalizedKinds {
+		list := new(metav1.PartialObjectMetadataList)
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := e.Client.List(ctx, list); err != nil {
+			if apimeta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+		}
+
+		for _, obj := range list.Items {
+			if obj.DeletionTimestamp.IsZero() || len(obj.Finalizers) == 0 {
+				continue
+			}
+			if deleting := time.Since(obj.DeletionTimestamp.Time); deleting > threshold {
+				findings = append(findings, kcm.DiagnosticFinding{
+					Object:   &kcm.DiagnosticObject{Kind: gvk.Kind, Namespace: obj.Namespace, Name: obj.Name},
+					Severity: kcm.DiagnosticSeverityWarning,
+					Message: fmt.Sprintf("object is being deleted for %s, the pending finalizers: %s",
+						deleting.Round(time.Minute), strings.Join(obj.Finalizers, ", ")),
+				})
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// checkOrphanedHelmReleases reports the HelmReleases managed by kcm the
+// owners of which no longer exist.
+func (e *Engine) checkOrphanedHelmReleases(ctx context.Context, _ kcm.DiagnosticsSpec) ([]kcm.DiagnosticFinding, error) {
+	hrs := new(fluxv2.HelmReleaseList)
+	if err := e.Client.List(ctx, hrs, client.MatchingLabels{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue}); err != nil {
+		return nil, fmt.Errorf("failed to list HelmReleases: %w", err)
+	}
+
+	var findings []kcm.DiagnosticFinding
+	for _, hr := range hrs.Items {
+		for _, ref := range hr.OwnerReferences {
+			gv, err := schema.ParseGroupVersion(ref.APIVersion)
+			if err != nil {
+				continue
+			}
+
+			owner := new(metav1.PartialObjectMetadata)
+			owner.SetGroupVersionKind(gv.WithKind(ref.Kind))
+			err = e.Client.Get(ctx, client.ObjectKey{Namespace: hr.Namespace, Name: ref.Name}, owner)
+			if err != nil && !apierrors.IsNotFound(err) && !apimeta.IsNoMatchError(err) {
+				return nil, fmt.Errorf("failed to get %s %s owning HelmRelease %s: %w", ref.Kind, ref.Name, client.ObjectKeyFromObject(&hr), err)
+			}
+			if err == nil && owner.UID == ref.UID {
+				continue
+			}
+
+			findings = append(findings, kcm.DiagnosticFinding{
+				Object:   &kcm.DiagnosticObject{Kind: fluxv2.HelmReleaseKind, Namespace: hr.Namespace, Name: hr.Name},
+				Severity: kcm.DiagnosticSeverityWarning,
+				Message:  fmt.Sprintf("owner %s %s no longer exists", ref.Kind, ref.Name),
+			})
+		}
+	}
+
+	return findings, nil
+}
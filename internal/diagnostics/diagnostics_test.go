@@ -0,0 +1,219 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	fluxv2 "github.com/fluxcd/helm-controller/api/v2"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestEngine_checkCRDVersions(t *testing.T) {
+	g := NewWithT(t)
+
+	newCRD := func(name, group string, served []string, stored ...string) *apiextensionsv1.CustomResourceDefinition {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: group},
+			Status:     apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: stored},
+		}
+		for _, v := range served {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: v, Served: true})
+		}
+		return crd
+	}
+
+	e := &Engine{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		newCRD("clusters.cluster.x-k8s.io", "cluster.x-k8s.io", []string{"v1beta1"}, "v1beta1"),
+		newCRD("managements.k0rdent.mirantis.com", "k0rdent.mirantis.com", []string{"v1alpha1", "v1beta1"}, "v1alpha1", "v1beta1"),
+		newCRD("helmreleases.helm.toolkit.fluxcd.io", "helm.toolkit.fluxcd.io", []string{"v2"}, "v2beta1"),
+		newCRD("foos.example.com", "example.com", []string{"v1"}, "v1alpha1"),
+	).Build()}
+
+	findings, err := e.checkCRDVersions(t.Context(), kcm.DiagnosticsSpec{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(findings).To(Equal([]kcm.DiagnosticFinding{
+		{
+			Object:   &kcm.DiagnosticObject{Kind: "CustomResourceDefinition", Name: "helmreleases.helm.toolkit.fluxcd.io"},
+			Severity: kcm.DiagnosticSeverityError,
+			Message:  "objects are stored in the version v2beta1 not served by the CRD",
+		},
+		{
+			Object:   &kcm.DiagnosticObject{Kind: "CustomResourceDefinition", Name: "managements.k0rdent.mirantis.com"},
+			Severity: kcm.DiagnosticSeverityWarning,
+			Message:  "objects are stored in several versions v1alpha1, v1beta1, the storage migration is pending",
+		},
+	}))
+}
+
+func TestEngine_checkWebhooks(t *testing.T) {
+	g := NewWithT(t)
+
+	const namespace = "kcm-system"
+
+	newWebhook := func(name, service string, policy admissionregistrationv1.FailurePolicyType) admissionregistrationv1.ValidatingWebhook {
+		return admissionregistrationv1.ValidatingWebhook{
+			Name:          name,
+			FailurePolicy: &policy,
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service:  &admissionregistrationv1.ServiceReference{Namespace: namespace, Name: service, Port: ptr.To[int32](9443)},
+				CABundle: []byte("ca"),
+			},
+		}
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "kcm-validating-webhook-configuration"},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{
+				newWebhook("reachable.k0rdent.mirantis.com", "kcm-webhook", admissionregistrationv1.Fail),
+				newWebhook("missing.k0rdent.mirantis.com", "missing-webhook", admissionregistrationv1.Fail),
+				newWebhook("not-ready.k0rdent.mirantis.com", "not-ready-webhook", admissionregistrationv1.Ignore),
+				newWebhook("unreachable.k0rdent.mirantis.com", "unreachable-webhook", admissionregistrationv1.Fail),
+			},
+		},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "kcm-webhook", Namespace: namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "not-ready-webhook", Namespace: namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "unreachable-webhook", Namespace: namespace}},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: "kcm-webhook-1", Namespace: namespace, Labels: map[string]string{discoveryv1.LabelServiceName: "kcm-webhook"}},
+			Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)}}},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: "not-ready-webhook-1", Namespace: namespace, Labels: map[string]string{discoveryv1.LabelServiceName: "not-ready-webhook"}},
+			Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.2"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)}}},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: "unreachable-webhook-1", Namespace: namespace, Labels: map[string]string{discoveryv1.LabelServiceName: "unreachable-webhook"}},
+			Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.3"}}},
+		},
+	).Build()
+
+	e := &Engine{
+		Client: cl,
+		ProbeWebhook: func(_ context.Context, address string) error {
+			if address == "unreachable-webhook.kcm-system.svc:9443" {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+	}
+
+	object := &kcm.DiagnosticObject{Kind: "ValidatingWebhookConfiguration", Name: "kcm-validating-webhook-configuration"}
+	findings, err := e.checkWebhooks(t.Context(), kcm.DiagnosticsSpec{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(findings).To(Equal([]kcm.DiagnosticFinding{
+		{Object: object, Severity: kcm.DiagnosticSeverityError, Message: "webhook missing.k0rdent.mirantis.com: service kcm-system/missing-webhook is not found"},
+		{Object: object, Severity: kcm.DiagnosticSeverityWarning, Message: "webhook not-ready.k0rdent.mirantis.com: service kcm-system/not-ready-webhook has no ready endpoints"},
+		{Object: object, Severity: kcm.DiagnosticSeverityError, Message: "webhook unreachable.k0rdent.mirantis.com: service kcm-system/unreachable-webhook is not reachable: connection refused"},
+	}))
+}
+
+func TestEngine_Run(t *testing.T) {
+	g := NewWithT(t)
+
+	deleted := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	recentlyDeleted := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	mgmt := &kcm.Management{
+		ObjectMeta: metav1.ObjectMeta{Name: kcm.ManagementName},
+		Status: kcm.ManagementStatus{Components: map[string]kcm.ComponentStatus{
+			"cluster-api":                  {Success: true},
+			"cluster-api-provider-aws":     {Error: "install retries exhausted"},
+			"cluster-api-provider-vsphere": {},
+		}},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		mgmt,
+		&kcm.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "stuck", Namespace: "team", DeletionTimestamp: &deleted, Finalizers: []string{kcm.ClusterDeploymentFinalizer},
+		}},
+		&kcm.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "deleting", Namespace: "team", DeletionTimestamp: &recentlyDeleted, Finalizers: []string{kcm.ClusterDeploymentFinalizer},
+		}},
+		&kcm.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "team", UID: "cluster-uid"}},
+		&fluxv2.HelmRelease{ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster", Namespace: "team",
+			Labels:          map[string]string{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: kcm.GroupVersion.String(), Kind: kcm.ClusterDeploymentKind, Name: "cluster", UID: "cluster-uid"}},
+		}},
+		&fluxv2.HelmRelease{ObjectMeta: metav1.ObjectMeta{
+			Name: "gone", Namespace: "team",
+			Labels:          map[string]string{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: kcm.GroupVersion.String(), Kind: kcm.ClusterDeploymentKind, Name: "gone", UID: types.UID("gone-uid")}},
+		}},
+	).Build()
+
+	e := &Engine{Client: cl}
+	findings, err := e.Run(t.Context(), kcm.DiagnosticsSpec{
+		Checks: []kcm.DiagnosticCheck{kcm.DiagnosticCheckProviders, kcm.DiagnosticCheckStuckFinalizers, kcm.DiagnosticCheckOrphanedHelmReleases},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	mgmtObject := &kcm.DiagnosticObject{Kind: kcm.ManagementKind, Name: kcm.ManagementName}
+	g.Expect(findings).To(HaveLen(4))
+	g.Expect(findings[0]).To(Equal(kcm.DiagnosticFinding{
+		Object: mgmtObject, Check: kcm.DiagnosticCheckProviders, Severity: kcm.DiagnosticSeverityError,
+		Message: "component cluster-api-provider-aws is not installed: install retries exhausted",
+	}))
+	g.Expect(findings[1]).To(Equal(kcm.DiagnosticFinding{
+		Object: mgmtObject, Check: kcm.DiagnosticCheckProviders, Severity: kcm.DiagnosticSeverityError,
+		Message: "component cluster-api-provider-vsphere is not installed",
+	}))
+	g.Expect(findings[2].Check).To(Equal(kcm.DiagnosticCheckStuckFinalizers))
+	g.Expect(findings[2].Object).To(Equal(&kcm.DiagnosticObject{Kind: kcm.ClusterDeploymentKind, Namespace: "team", Name: "stuck"}))
+	g.Expect(findings[2].Message).To(Equal("object is being deleted for 2h0m0s, the pending finalizers: " + kcm.ClusterDeploymentFinalizer))
+	g.Expect(findings[3]).To(Equal(kcm.DiagnosticFinding{
+		Object: &kcm.DiagnosticObject{Kind: fluxv2.HelmReleaseKind, Namespace: "team", Name: "gone"}, Check: kcm.DiagnosticCheckOrphanedHelmReleases,
+		Severity: kcm.DiagnosticSeverityWarning, Message: "owner ClusterDeployment gone no longer exists",
+	}))
+
+	g.Expect(cl.Delete(t.Context(), mgmt)).To(Succeed())
+	findings, err = e.Run(t.Context(), kcm.DiagnosticsSpec{Checks: []kcm.DiagnosticCheck{kcm.DiagnosticCheckProviders}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(findings).To(Equal([]kcm.DiagnosticFinding{{
+		Check: kcm.DiagnosticCheckProviders, Severity: kcm.DiagnosticSeverityError, Message: "Management kcm is not found",
+	}}))
+
+	e.Client = interceptor.NewClient(cl, interceptor.Funcs{
+		Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+			return errors.New("unavailable")
+		},
+	})
+	findings, err = e.Run(t.Context(), kcm.DiagnosticsSpec{
+		Checks: []kcm.DiagnosticCheck{kcm.DiagnosticCheckProviders, kcm.DiagnosticCheckStuckFinalizers},
+	})
+	g.Expect(err).To(MatchError("failed to run the Providers check: failed to get Management kcm: unavailable"))
+	g.Expect(findings).To(HaveLen(1))
+	g.Expect(findings[0].Check).To(Equal(kcm.DiagnosticCheckStuckFinalizers))
+}
@@ -0,0 +1,134 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errclass classifies the failures of the reconciliations by their
+// causes telling the ones resolved by retrying later from the ones requiring
+// the spec to be fixed.
+package errclass
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// Class is the class of the failure, the string representation of which is
+// the reason of the conditions reporting it.
+type Class string
+
+const (
+	QuotaExceeded Class = Class(kcm.QuotaExceededReason)
+	AuthFailure   Class = Class(kcm.AuthFailureReason)
+	Timeout       Class = Class(kcm.TimeoutReason)
+	InvalidConfig Class = Class(kcm.InvalidConfigReason)
+	// Unknown is the class of the failures of the other causes.
+	Unknown Class = Class(kcm.FailedReason)
+)
+
+// Retryable reports whether the failures of the class are expected to be
+// resolved by retrying later without changes of the spec or credentials.
+func (c Class) Retryable() bool {
+	return c != AuthFailure && c != InvalidConfig
+}
+
+// messagePatterns are the substrings of the errors of the cloud providers and
+// of the Kubernetes API reported in the messages of the conditions, in the
+// order of the precedence.
+var messagePatterns = []struct {
+	class    Class
+	patterns []string
+}{
+	{QuotaExceeded, []string{"exceeded quota", "quotaexceeded", "quota exceeded", "limitexceeded", "insufficientquota", "insufficient quota"}},
+	{AuthFailure, []string{"unauthorized", "authfailure", "authorizationfailed", "invalidclienttokenid", "expiredtoken", "invalid_client", "access denied"}},
+	{Timeout, []string{"context deadline exceeded", "i/o timeout", "timed out"}},
+}
+
+type classifiedError struct {
+	err   error
+	class Class
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+
+func (e *classifiedError) Unwrap() error { return e.err }
+
+// New returns the error of the given class wrapping err, nil if err is nil.
+func New(class Class, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+// Of returns the class of the error, the explicitly classified errors take
+// precedence over the errors of the Kubernetes API, the rest are classified
+// by their messages.
+func Of(err error) Class {
+	if err == nil {
+		return Unknown
+	}
+
+	if ce := new(classifiedError); errors.As(err, &ce) {
+		return ce.class
+	}
+
+	var netErr net.Error
+	switch {
+	case apierrors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota"):
+		// the ResourceQuota admission rejects the objects as forbidden
+		return QuotaExceeded
+	case apierrors.IsUnauthorized(err), apierrors.IsForbidden(err):
+		return AuthFailure
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return Timeout
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return InvalidConfig
+	}
+
+	return OfMessage(err.Error())
+}
+
+// OfMessage returns the class of the failure reported with the message,
+// e.g. the one of the condition of the object managed by the other controller.
+func OfMessage(message string) Class {
+	message = strings.ToLower(message)
+	for _, p := range messagePatterns {
+		for _, pattern := range p.patterns {
+			if strings.Contains(message, pattern) {
+				return p.class
+			}
+		}
+	}
+	return Unknown
+}
+
+// Reason returns the reason of the condition reporting the error.
+func Reason(err error) string {
+	return string(Of(err))
+}
+
+// IsReason reports whether the reason of the condition is the one of the classified failures.
+func IsReason(reason string) bool {
+	switch Class(reason) {
+	case QuotaExceeded, AuthFailure, Timeout, InvalidConfig:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errclass
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestOf(t *testing.T) {
+	secrets := schema.GroupResource{Resource: "secrets"}
+
+	for _, tc := range []struct {
+		err       error
+		name      string
+		expected  Class
+		retryable bool
+	}{
+		{
+			name:      "unclassified",
+			err:       errors.New("something went wrong"),
+			expected:  Unknown,
+			retryable: true,
+		},
+		{
+			name:      "explicitly classified",
+			err:       fmt.Errorf("failed to render chart: %w", New(InvalidConfig, errors.New("values don't meet the specifications of the schema"))),
+			expected:  InvalidConfig,
+			retryable: false,
+		},
+		{
+			name:      "resource quota",
+			err:       apierrors.NewForbidden(secrets, "creds", errors.New("exceeded quota: compute-resources")),
+			expected:  QuotaExceeded,
+			retryable: true,
+		},
+		{
+			name:     "forbidden",
+			err:      apierrors.NewForbidden(secrets, "creds", errors.New("not allowed")),
+			expected: AuthFailure,
+		},
+		{
+			name:     "unauthorized",
+			err:      apierrors.NewUnauthorized("token expired"),
+			expected: AuthFailure,
+		},
+		{
+			name:      "deadline exceeded",
+			err:       fmt.Errorf("failed to get ClusterTemplate: %w", context.DeadlineExceeded),
+			expected:  Timeout,
+			retryable: true,
+		},
+		{
+			name:     "invalid",
+			err:      apierrors.NewInvalid(schema.GroupKind{Kind: "HelmRelease"}, "cd", nil),
+			expected: InvalidConfig,
+		},
+		{
+			name:      "cloud quota in message",
+			err:       errors.New("failed to create instance: VcpuLimitExceeded: You have requested more vCPU capacity than your current vCPU limit"),
+			expected:  QuotaExceeded,
+			retryable: true,
+		},
+		{
+			name:     "cloud auth in message",
+			err:      errors.New("operation error EC2: DescribeInstances, api error AuthFailure: AWS was not able to validate the provided access credentials"),
+			expected: AuthFailure,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(Of(tc.err)).To(Equal(tc.expected))
+			g.Expect(Of(tc.err).Retryable()).To(Equal(tc.retryable))
+			g.Expect(Reason(tc.err)).To(Equal(string(tc.expected)))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(New(Timeout, nil)).To(Succeed())
+
+	err := errors.New("timeout")
+	g.Expect(New(Timeout, err)).To(MatchError(err))
+}
@@ -0,0 +1,295 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package etcdbackup schedules the snapshots of etcd of the managed clusters
+// and their upload to the S3-compatible object storage.
+package etcdbackup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+const (
+	// Namespace is the namespace of the managed cluster the snapshots are taken in.
+	Namespace = "kcm-etcd-backup"
+	// CronJobName is the name of the CronJob taking the snapshots.
+	CronJobName = "etcd-snapshot"
+	// CredentialsSecretName is the name of the Secret holding the access
+	// keys of the storage in the managed cluster.
+	CredentialsSecretName = "etcd-backup-credentials"
+
+	// DefaultImage is the etcd image used unless overridden.
+	DefaultImage = "registry.k8s.io/etcd:3.5.21-0"
+	// DefaultUploadImage is the AWS CLI image used unless overridden.
+	DefaultUploadImage = "docker.io/amazon/aws-cli:2.27.0"
+
+	snapshotsVolume = "snapshots"
+	pkiVolume       = "etcd-pki"
+	pkiMountPath    = "/etcd-pki"
+)
+
+// ErrUnsupportedControlPlane is returned for the control planes etcd of which
+// is not running on the nodes of the cluster.
+var ErrUnsupportedControlPlane = errors.New("etcd snapshots are not supported for the control plane")
+
+// etcdClient is the location of the client certificates of etcd on the
+// control plane nodes.
+type etcdClient struct {
+	// pkiDir is the host directory holding the certificates.
+	pkiDir string
+	// caFile, certFile and keyFile are relative to the pkiDir.
+	caFile, certFile, keyFile string
+}
+
+// etcdClients are the client certificates of etcd by the kinds of the
+// control planes.
+var etcdClients = map[string]etcdClient{
+	"KubeadmControlPlane": {
+		pkiDir:   "/etc/kubernetes/pki",
+		caFile:   "etcd/ca.crt",
+		certFile: "apiserver-etcd-client.crt",
+		keyFile:  "apiserver-etcd-client.key",
+	},
+	"K0sControlPlane": {
+		pkiDir:   "/var/lib/k0s/pki",
+		caFile:   "etcd/ca.crt",
+		certFile: "apiserver-etcd-client.crt",
+		keyFile:  "apiserver-etcd-client.key",
+	},
+}
+
+// uploadScript uploads the snapshot to the bucket under the key of the time
+// the snapshot has been taken at.
+const uploadScript = `set -e
+key="${PREFIX%/}/etcd-snapshot-$(date -u +%Y%m%d%H%M%S).db"
+aws s3 cp "$SNAPSHOT" "s3://${BUCKET}/${key#/}" ${ENDPOINT:+--endpoint-url "$ENDPOINT"}`
+
+// Scheduler maintains the snapshots of etcd on a managed cluster.
+type Scheduler struct {
+	Client kubernetes.Interface
+}
+
+// Schedule creates or updates the CronJob taking the snapshots with the given
+// spec and the Secret with the given access keys of the storage. It returns
+// the synced CronJob.
+func (s *Scheduler) Schedule(ctx context.Context, cronJob *batchv1.CronJob, credentials map[string][]byte) (*batchv1.CronJob, error) {
+	if err := s.ensureNamespace(ctx); err != nil {
+		return nil, err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CredentialsSecretName,
+			Namespace: Namespace,
+			Labels:    map[string]string{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue},
+		},
+		Data: credentials,
+	}
+	existingSecret, err := s.Client.CoreV1().Secrets(Namespace).Get(ctx, CredentialsSecretName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = s.Client.CoreV1().Secrets(Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	case err == nil:
+		existingSecret.Data = credentials
+		_, err = s.Client.CoreV1().Secrets(Namespace).Update(ctx, existingSecret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile %s/%s Secret: %w", Namespace, CredentialsSecretName, err)
+	}
+
+	existing, err := s.Client.BatchV1().CronJobs(Namespace).Get(ctx, CronJobName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		created, err := s.Client.BatchV1().CronJobs(Namespace).Create(ctx, cronJob, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s/%s CronJob: %w", Namespace, CronJobName, err)
+		}
+		return created, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s/%s CronJob: %w", Namespace, CronJobName, err)
+	}
+
+	existing.Labels = cronJob.Labels
+	existing.Spec = cronJob.Spec
+	updated, err := s.Client.BatchV1().CronJobs(Namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update %s/%s CronJob: %w", Namespace, CronJobName, err)
+	}
+	return updated, nil
+}
+
+// Remove removes the namespace of the snapshots along with the CronJob and
+// the Secret, the uploaded snapshots are kept.
+func (s *Scheduler) Remove(ctx context.Context) error {
+	if err := s.Client.CoreV1().Namespaces().Delete(ctx, Namespace, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %s namespace: %w", Namespace, err)
+	}
+	return nil
+}
+
+// CronJob returns the CronJob taking the snapshots of etcd of the control
+// plane of the given kind with the given spec, the snapshots are uploaded
+// under the given prefix of the bucket, e.g. the namespaced name of the
+// ClusterDeployment.
+func CronJob(spec *kcm.EtcdBackupSpec, controlPlaneKind, defaultPrefix string) (*batchv1.CronJob, error) {
+	etcd, ok := etcdClients[controlPlaneKind]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnsupportedControlPlane, controlPlaneKind)
+	}
+
+	image := DefaultImage
+	if spec.Image != "" {
+		image = spec.Image
+	}
+	uploadImage := DefaultUploadImage
+	if spec.UploadImage != "" {
+		uploadImage = spec.UploadImage
+	}
+	prefix := defaultPrefix
+	if spec.Storage.Prefix != "" {
+		prefix = spec.Storage.Prefix
+	}
+
+	snapshot := path.Join("/", snapshotsVolume, "snapshot.db")
+	uploadEnv := []corev1.EnvVar{
+		{Name: "SNAPSHOT", Value: snapshot},
+		{Name: "BUCKET", Value: spec.Storage.Bucket},
+		{Name: "PREFIX", Value: prefix},
+		{Name: "ENDPOINT", Value: spec.Storage.Endpoint},
+	}
+	if spec.Storage.Region != "" {
+		uploadEnv = append(uploadEnv, corev1.EnvVar{Name: "AWS_DEFAULT_REGION", Value: spec.Storage.Region})
+	}
+
+	labels := map[string]string{kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue}
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CronJobName,
+			Namespace: Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   spec.Schedule,
+			Suspend:                    ptr.To(spec.Suspend),
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To[int32](3),
+			FailedJobsHistoryLimit:     ptr.To[int32](3),
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To[int32](1),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: labels},
+						Spec: corev1.PodSpec{
+							// etcd listens on the loopback interface of the control plane nodes
+							HostNetwork:   true,
+							RestartPolicy: corev1.RestartPolicyNever,
+							Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+								RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+									NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+										MatchExpressions: []corev1.NodeSelectorRequirement{{
+											Key:      "node-role.kubernetes.io/control-plane",
+											Operator: corev1.NodeSelectorOpExists,
+										}},
+									}},
+								},
+							}},
+							Tolerations: []corev1.Toleration{{
+								Key:      "node-role.kubernetes.io/control-plane",
+								Operator: corev1.TolerationOpExists,
+								Effect:   corev1.TaintEffectNoSchedule,
+							}, {
+								Key:      "node-role.kubernetes.io/master",
+								Operator: corev1.TolerationOpExists,
+								Effect:   corev1.TaintEffectNoSchedule,
+							}},
+							InitContainers: []corev1.Container{{
+								Name:  "snapshot",
+								Image: image,
+								Command: []string{
+									"etcdctl", "snapshot", "save", snapshot,
+									"--endpoints=https://127.0.0.1:2379",
+									"--cacert=" + path.Join(pkiMountPath, etcd.caFile),
+									"--cert=" + path.Join(pkiMountPath, etcd.certFile),
+									"--key=" + path.Join(pkiMountPath, etcd.keyFile),
+								},
+								Env: []corev1.EnvVar{{Name: "ETCDCTL_API", Value: "3"}},
+								VolumeMounts: []corev1.VolumeMount{
+									{Name: snapshotsVolume, MountPath: path.Dir(snapshot)},
+									{Name: pkiVolume, MountPath: pkiMountPath, ReadOnly: true},
+								},
+							}},
+							Containers: []corev1.Container{{
+								Name:    "upload",
+								Image:   uploadImage,
+								Command: []string{"/bin/sh", "-c", uploadScript},
+								Env:     uploadEnv,
+								EnvFrom: []corev1.EnvFromSource{{
+									SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: CredentialsSecretName}},
+								}},
+								VolumeMounts: []corev1.VolumeMount{
+									{Name: snapshotsVolume, MountPath: path.Dir(snapshot), ReadOnly: true},
+								},
+							}},
+							Volumes: []corev1.Volume{
+								{Name: snapshotsVolume, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+								{Name: pkiVolume, VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: etcd.pkiDir}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (s *Scheduler) ensureNamespace(ctx context.Context) error {
+	labels := map[string]string{
+		kcm.KCMManagedLabelKey: kcm.KCMManagedLabelValue,
+		// the snapshots require access to the host network and certificates
+		"pod-security.kubernetes.io/enforce": "privileged",
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: Namespace, Labels: labels}}
+	_, err := s.Client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create %s namespace: %w", Namespace, err)
+	}
+
+	// the labels might have been changed or removed since the creation
+	patch, err := json.Marshal(map[string]any{"metadata": map[string]any{"labels": labels}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the labels of %s namespace: %w", Namespace, err)
+	}
+	if _, err := s.Client.CoreV1().Namespaces().Patch(ctx, Namespace, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to label %s namespace: %w", Namespace, err)
+	}
+	return nil
+}
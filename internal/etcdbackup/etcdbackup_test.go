@@ -0,0 +1,112 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdbackup
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+func TestCronJob(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := &kcm.EtcdBackupSpec{
+		Schedule: "@daily",
+		Storage:  kcm.EtcdBackupStorage{Bucket: "snapshots", Endpoint: "https://minio.local", Region: "us-east-1", CredentialsSecret: "s3"},
+	}
+
+	cronJob, err := CronJob(spec, "K0sControlPlane", "default/cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cronJob.Spec.Schedule).To(Equal("@daily"))
+	g.Expect(*cronJob.Spec.Suspend).To(BeFalse())
+
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	g.Expect(podSpec.HostNetwork).To(BeTrue())
+	g.Expect(podSpec.Volumes[1].HostPath.Path).To(Equal("/var/lib/k0s/pki"))
+	g.Expect(podSpec.InitContainers[0].Image).To(Equal(DefaultImage))
+	g.Expect(podSpec.InitContainers[0].Command).To(ContainElement("--cacert=/etcd-pki/etcd/ca.crt"))
+	g.Expect(podSpec.Containers[0].Image).To(Equal(DefaultUploadImage))
+	g.Expect(podSpec.Containers[0].Env).To(ContainElements(
+		HaveField("Value", "default/cluster"),
+		HaveField("Value", "https://minio.local"),
+		HaveField("Name", "AWS_DEFAULT_REGION"),
+	))
+	g.Expect(podSpec.Containers[0].EnvFrom[0].SecretRef.Name).To(Equal(CredentialsSecretName))
+
+	spec.Storage.Prefix = "etcd"
+	spec.Image = "registry.local/etcd:3.5"
+	spec.Suspend = true
+	cronJob, err = CronJob(spec, "KubeadmControlPlane", "default/cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*cronJob.Spec.Suspend).To(BeTrue())
+	podSpec = cronJob.Spec.JobTemplate.Spec.Template.Spec
+	g.Expect(podSpec.Volumes[1].HostPath.Path).To(Equal("/etc/kubernetes/pki"))
+	g.Expect(podSpec.InitContainers[0].Image).To(Equal("registry.local/etcd:3.5"))
+	g.Expect(podSpec.Containers[0].Env).To(ContainElement(HaveField("Value", "etcd")))
+
+	_, err = CronJob(spec, "K0smotronControlPlane", "default/cluster")
+	g.Expect(err).To(MatchError(ErrUnsupportedControlPlane))
+}
+
+func TestScheduler(t *testing.T) {
+	g := NewWithT(t)
+
+	clientset := fake.NewClientset()
+	s := &Scheduler{Client: clientset}
+
+	spec := &kcm.EtcdBackupSpec{Schedule: "@daily", Storage: kcm.EtcdBackupStorage{Bucket: "snapshots", CredentialsSecret: "s3"}}
+	cronJob, err := CronJob(spec, "K0sControlPlane", "default/cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = s.Schedule(t.Context(), cronJob, map[string][]byte{"AWS_ACCESS_KEY_ID": []byte("id")})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ns, err := clientset.CoreV1().Namespaces().Get(t.Context(), Namespace, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ns.Labels).To(HaveKeyWithValue("pod-security.kubernetes.io/enforce", "privileged"))
+
+	// the labels of the namespace are restored
+	ns.Labels = map[string]string{"pod-security.kubernetes.io/enforce": "baseline"}
+	_, err = clientset.CoreV1().Namespaces().Update(t.Context(), ns, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the changes of the spec and the credentials are applied
+	spec.Schedule = "@hourly"
+	cronJob, err = CronJob(spec, "K0sControlPlane", "default/cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	synced, err := s.Schedule(t.Context(), cronJob, map[string][]byte{"AWS_ACCESS_KEY_ID": []byte("rotated")})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(synced.Spec.Schedule).To(Equal("@hourly"))
+
+	secret, err := clientset.CoreV1().Secrets(Namespace).Get(t.Context(), CredentialsSecretName, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(secret.Data).To(HaveKeyWithValue("AWS_ACCESS_KEY_ID", []byte("rotated")))
+
+	ns, err = clientset.CoreV1().Namespaces().Get(t.Context(), Namespace, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ns.Labels).To(HaveKeyWithValue("pod-security.kubernetes.io/enforce", "privileged"))
+	g.Expect(ns.Labels).To(HaveKeyWithValue(kcm.KCMManagedLabelKey, kcm.KCMManagedLabelValue))
+
+	g.Expect(s.Remove(t.Context())).To(Succeed())
+	_, err = clientset.CoreV1().Namespaces().Get(t.Context(), Namespace, metav1.GetOptions{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(s.Remove(t.Context())).To(Succeed())
+}
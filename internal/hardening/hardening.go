@@ -0,0 +1,222 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hardening generates the patches applying the security profiles
+// to the workloads rendered from the Helm charts.
+package hardening
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	hcv2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/fluxcd/pkg/apis/kustomize"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// Patch is a strategic merge patch targeting a single workload.
+type Patch struct {
+	Patch     string
+	Group     string
+	Version   string
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// podTemplatePaths maps the kinds of the workloads to the paths of their pod templates.
+var podTemplatePaths = map[string][]string{
+	"Deployment":  {"spec", "template"},
+	"StatefulSet": {"spec", "template"},
+	"DaemonSet":   {"spec", "template"},
+	"ReplicaSet":  {"spec", "template"},
+	"Job":         {"spec", "template"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template"},
+}
+
+// PodSecurityContext returns the pod-level security context fields enforced by the profile.
+func PodSecurityContext(profile kcm.SecurityProfile) map[string]any {
+	sc := map[string]any{
+		"seccompProfile": map[string]any{"type": "RuntimeDefault"},
+	}
+	if profile == kcm.SecurityProfileRestricted {
+		sc["runAsNonRoot"] = true
+	}
+	return sc
+}
+
+// ContainerSecurityContext returns the container-level security context fields enforced by the profile.
+func ContainerSecurityContext(profile kcm.SecurityProfile) map[string]any {
+	sc := map[string]any{
+		"privileged":               false,
+		"allowPrivilegeEscalation": false,
+	}
+	if profile == kcm.SecurityProfileRestricted {
+		sc["capabilities"] = map[string]any{"drop": []any{"ALL"}}
+		sc["readOnlyRootFilesystem"] = true
+		sc["runAsNonRoot"] = true
+		sc["seccompProfile"] = map[string]any{"type": "RuntimeDefault"}
+	}
+	return sc
+}
+
+// Patches returns the patches applying the given security profile to all of the
+// workloads and their containers found in the rendered chart manifests keyed by
+// the template name. The patches are sorted by the target kind, namespace and name.
+func Patches(profile kcm.SecurityProfile, manifests map[string]string) ([]Patch, error) {
+	if profile == "" {
+		return nil, nil
+	}
+	if profile != kcm.SecurityProfileBaseline && profile != kcm.SecurityProfileRestricted {
+		return nil, fmt.Errorf("unknown security profile %q", profile)
+	}
+
+	var patches []Patch
+	for name, manifest := range manifests {
+		if path.Ext(name) != ".yaml" && path.Ext(name) != ".yml" {
+			continue
+		}
+
+		decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+		for {
+			var obj map[string]any
+			if err := decoder.Decode(&obj); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+			}
+
+			patch, ok, err := workloadPatch(profile, obj)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate patch for %s: %w", name, err)
+			}
+			if ok {
+				patches = append(patches, patch)
+			}
+		}
+	}
+
+	slices.SortFunc(patches, func(a, b Patch) int {
+		return strings.Compare(a.Kind+"/"+a.Namespace+"/"+a.Name, b.Kind+"/"+b.Namespace+"/"+b.Name)
+	})
+
+	return patches, nil
+}
+
+func workloadPatch(profile kcm.SecurityProfile, obj map[string]any) (Patch, bool, error) {
+	kind, _ := obj["kind"].(string)
+	templatePath, ok := podTemplatePaths[kind]
+	if !ok {
+		return Patch{}, false, nil
+	}
+
+	apiVersion, _ := obj["apiVersion"].(string)
+	metadata, _ := obj["metadata"].(map[string]any)
+	name, _ := metadata["name"].(string)
+	namespace, _ := metadata["namespace"].(string)
+	if name == "" {
+		return Patch{}, false, nil
+	}
+
+	podTemplate := obj
+	for _, key := range templatePath {
+		podTemplate, _ = podTemplate[key].(map[string]any)
+	}
+	podSpec, _ := podTemplate["spec"].(map[string]any)
+
+	patchedPodSpec := map[string]any{
+		"securityContext": PodSecurityContext(profile),
+	}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _ := podSpec[field].([]any)
+
+		patchedContainers := make([]any, 0, len(containers))
+		for _, c := range containers {
+			container, _ := c.(map[string]any)
+			if containerName, _ := container["name"].(string); containerName != "" {
+				patchedContainers = append(patchedContainers, map[string]any{
+					"name":            containerName,
+					"securityContext": ContainerSecurityContext(profile),
+				})
+			}
+		}
+		if len(patchedContainers) > 0 {
+			patchedPodSpec[field] = patchedContainers
+		}
+	}
+
+	patchObj := map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]any{"name": name},
+	}
+	parent := patchObj
+	for _, key := range templatePath {
+		child := make(map[string]any)
+		parent[key] = child
+		parent = child
+	}
+	parent["spec"] = patchedPodSpec
+
+	raw, err := json.Marshal(patchObj)
+	if err != nil {
+		return Patch{}, false, err
+	}
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return Patch{}, false, err
+	}
+
+	return Patch{
+		Patch:     string(raw),
+		Group:     gv.Group,
+		Version:   gv.Version,
+		Kind:      kind,
+		Name:      name,
+		Namespace: namespace,
+	}, true, nil
+}
+
+// PostRenderers returns the Flux HelmRelease post-renderers applying the given patches.
+func PostRenderers(patches []Patch) []hcv2.PostRenderer {
+	if len(patches) == 0 {
+		return nil
+	}
+
+	kustomizePatches := make([]kustomize.Patch, 0, len(patches))
+	for _, p := range patches {
+		kustomizePatches = append(kustomizePatches, kustomize.Patch{
+			Patch: p.Patch,
+			Target: &kustomize.Selector{
+				Group:     p.Group,
+				Version:   p.Version,
+				Kind:      p.Kind,
+				Name:      p.Name,
+				Namespace: p.Namespace,
+			},
+		})
+	}
+
+	return []hcv2.PostRenderer{{Kustomize: &hcv2.Kustomize{Patches: kustomizePatches}}}
+}
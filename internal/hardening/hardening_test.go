@@ -0,0 +1,152 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hardening
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+const testManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller
+  namespace: kcm-system
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox
+      containers:
+      - name: manager
+        image: controller
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: controller
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: cleanup
+            image: cleanup
+`
+
+func TestPatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile kcm.SecurityProfile
+		want    []Patch
+		wantErr bool
+	}{
+		{
+			name: "no profile",
+		},
+		{
+			name:    "unknown profile",
+			profile: "privileged",
+			wantErr: true,
+		},
+		{
+			name:    "baseline",
+			profile: kcm.SecurityProfileBaseline,
+			want: []Patch{
+				{
+					Patch:   `{"apiVersion":"batch/v1","kind":"CronJob","metadata":{"name":"cleanup"},"spec":{"jobTemplate":{"spec":{"template":{"spec":{"containers":[{"name":"cleanup","securityContext":{"allowPrivilegeEscalation":false,"privileged":false}}],"securityContext":{"seccompProfile":{"type":"RuntimeDefault"}}}}}}}}`,
+					Group:   "batch",
+					Version: "v1",
+					Kind:    "CronJob",
+					Name:    "cleanup",
+				},
+				{
+					Patch:     `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"controller"},"spec":{"template":{"spec":{"containers":[{"name":"manager","securityContext":{"allowPrivilegeEscalation":false,"privileged":false}}],"initContainers":[{"name":"init","securityContext":{"allowPrivilegeEscalation":false,"privileged":false}}],"securityContext":{"seccompProfile":{"type":"RuntimeDefault"}}}}}}`,
+					Group:     "apps",
+					Version:   "v1",
+					Kind:      "Deployment",
+					Name:      "controller",
+					Namespace: "kcm-system",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := Patches(tt.profile, map[string]string{
+				"templates/manifest.yaml": testManifest,
+				"templates/NOTES.txt":     "kind: Deployment",
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPatches_Restricted(t *testing.T) {
+	g := NewWithT(t)
+
+	got, err := Patches(kcm.SecurityProfileRestricted, map[string]string{"templates/manifest.yaml": testManifest})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(HaveLen(2))
+	g.Expect(got[1].Patch).To(MatchJSON(`{
+		"apiVersion": "apps/v1",
+		"kind": "Deployment",
+		"metadata": {"name": "controller"},
+		"spec": {"template": {"spec": {
+			"securityContext": {"runAsNonRoot": true, "seccompProfile": {"type": "RuntimeDefault"}},
+			"initContainers": [{"name": "init", "securityContext": {
+				"allowPrivilegeEscalation": false,
+				"capabilities": {"drop": ["ALL"]},
+				"privileged": false,
+				"readOnlyRootFilesystem": true,
+				"runAsNonRoot": true,
+				"seccompProfile": {"type": "RuntimeDefault"}
+			}}],
+			"containers": [{"name": "manager", "securityContext": {
+				"allowPrivilegeEscalation": false,
+				"capabilities": {"drop": ["ALL"]},
+				"privileged": false,
+				"readOnlyRootFilesystem": true,
+				"runAsNonRoot": true,
+				"seccompProfile": {"type": "RuntimeDefault"}
+			}}]
+		}}}
+	}`))
+
+	postRenderers := PostRenderers(got)
+	g.Expect(postRenderers).To(HaveLen(1))
+	g.Expect(postRenderers[0].Kustomize.Patches).To(HaveLen(2))
+	g.Expect(postRenderers[0].Kustomize.Patches[1].Target.Kind).To(Equal("Deployment"))
+	g.Expect(postRenderers[0].Kustomize.Patches[1].Target.Name).To(Equal("controller"))
+	g.Expect(PostRenderers(nil)).To(BeNil())
+}
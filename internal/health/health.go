@@ -0,0 +1,99 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health probes the API servers of the managed clusters.
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+const probeTimeout = 10 * time.Second
+
+// Result is the outcome of the probe of the reachable API server.
+type Result struct {
+	// FailedChecks lists the names of the failed readiness checks of the
+	// API server, e.g. etcd, empty if the API server is ready.
+	FailedChecks []string
+}
+
+// Healthy reports whether all of the readiness checks have passed.
+func (r Result) Healthy() bool {
+	return len(r.FailedChecks) == 0
+}
+
+// Probe requests the verbose readiness report of the API server the given
+// config points to. The error is returned only if the API server is not
+// reachable or responds with an unexpected status.
+func Probe(ctx context.Context, config *rest.Config) (Result, error) {
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create HTTP client for the API server: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(config.Host, "/")+"/readyz?verbose", nil)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create request to the API server %s: %w", config.Host, err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to connect to the API server %s: %w", config.Host, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read the response of the API server %s: %w", config.Host, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return Result{}, nil
+	case http.StatusInternalServerError:
+		// the verbose report lists the failed checks as "[-]name failed: reason withheld"
+		return Result{FailedChecks: failedChecks(string(body))}, nil
+	default:
+		return Result{}, fmt.Errorf("API server %s responded with unexpected status %s", config.Host, resp.Status)
+	}
+}
+
+func failedChecks(report string) []string {
+	var failed []string
+	for line := range strings.Lines(report) {
+		check, ok := strings.CutPrefix(strings.TrimSpace(line), "[-]")
+		if !ok {
+			continue
+		}
+		if name, _, ok := strings.Cut(check, " "); ok {
+			check = name
+		}
+		failed = append(failed, check)
+	}
+	if len(failed) == 0 {
+		// the report is unexpected, still the API server is not ready
+		failed = []string{"readyz"}
+	}
+	return failed
+}
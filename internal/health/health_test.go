@@ -0,0 +1,90 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+func TestProbe(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		body     string
+		expected []string
+		status   int
+		err      bool
+	}{
+		{
+			name:   "ready",
+			status: http.StatusOK,
+			body:   "[+]ping ok\n[+]etcd ok\nreadyz check passed\n",
+		},
+		{
+			name:     "failed checks",
+			status:   http.StatusInternalServerError,
+			body:     "[+]ping ok\n[-]etcd failed: reason withheld\n[-]informer-sync failed: reason withheld\nreadyz check failed\n",
+			expected: []string{"etcd", "informer-sync"},
+		},
+		{
+			name:     "unexpected report",
+			status:   http.StatusInternalServerError,
+			body:     "internal error",
+			expected: []string{"readyz"},
+		},
+		{
+			name:   "unauthorized",
+			status: http.StatusUnauthorized,
+			err:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.URL.Path).To(Equal("/readyz"))
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			result, err := Probe(t.Context(), &rest.Config{
+				Host:            server.URL,
+				TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+			})
+			if tc.err {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.FailedChecks).To(Equal(tc.expected))
+			g.Expect(result.Healthy()).To(Equal(len(tc.expected) == 0))
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewTLSServer(http.NotFoundHandler())
+		server.Close()
+
+		_, err := Probe(t.Context(), &rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{Insecure: true}})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
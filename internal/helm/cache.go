@@ -0,0 +1,342 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	"golang.org/x/sync/singleflight"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/K0rdent/kcm/internal/metrics"
+)
+
+const (
+	// chartArchiveExt is the extension of the chart archives stored in the cache.
+	chartArchiveExt = ".tgz"
+	// chartValidationExt is the extension of the validations of the charts
+	// stored in the cache next to their archives.
+	chartValidationExt = ".json"
+)
+
+// The caches of the chart cache lookups are counted for.
+const (
+	chartCacheArchive    = "archive"
+	chartCacheValidation = "validation"
+)
+
+// chartCache is the cache of the charts downloaded by DownloadChart, the
+// charts are downloaded on every call if nil.
+var chartCache *ChartCache
+
+// SetChartCache makes DownloadChart fetch the charts with the digests through
+// the given cache, nil disables the caching.
+func SetChartCache(c *ChartCache) {
+	chartCache = c
+}
+
+// ChartCache is the on-disk cache of the chart archives keyed by their
+// digests, so the same chart version is downloaded once regardless of the
+// number of the templates and the clusters it is validated or installed for.
+// The validations of the charts are cached along with the archives, so the
+// charts are neither downloaded nor loaded again to revalidate the templates,
+// e.g. after the restart of the controller. The least recently used archives
+// are evicted along with their validations once the total size of the cache
+// exceeds the limit.
+type ChartCache struct {
+	// validations are the validations read from or stored in the cache keyed
+	// by their paths.
+	validations map[string]*ChartValidation
+
+	dir     string
+	maxSize int64
+
+	// downloads deduplicates the concurrent downloads of the same archive.
+	downloads singleflight.Group
+	// validateCalls deduplicates the concurrent validations of the same chart.
+	validateCalls singleflight.Group
+	// evictMu serializes the evictions of the archives and keeps the archives
+	// being read from being evicted meanwhile.
+	evictMu sync.RWMutex
+	// validationsMu guards the validations.
+	validationsMu sync.Mutex
+}
+
+// NewChartCache returns the cache storing the chart archives in the given
+// directory, which is created if missing. The maxSize limits the total size
+// of the archives in bytes, 0 disables the limit.
+func NewChartCache(dir string, maxSize int64) (*ChartCache, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create chart cache directory %s: %w", dir, err)
+	}
+	return &ChartCache{dir: dir, maxSize: maxSize, validations: make(map[string]*ChartValidation)}, nil
+}
+
+// path returns the path of the file of the chart with the given digest and
+// extension in the cache.
+func (c *ChartCache) path(digest, ext string) (string, error) {
+	dig, err := godigest.Parse(digest)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse digest %s: %w", digest, err)
+	}
+	return filepath.Join(c.dir, dig.Algorithm().String()+"-"+dig.Encoded()+ext), nil
+}
+
+// Chart returns the chart with the given digest from the cache, downloading
+// it from the URL if it is not cached yet. Each of the calls returns its own
+// copy of the chart, so the callers are free to modify it.
+func (c *ChartCache) Chart(ctx context.Context, chartURL, digest string) (*chart.Chart, error) {
+	path, err := c.path(digest, chartArchiveExt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err, _ := c.downloads.Do(path, func() (any, error) {
+		if _, err := os.Stat(path); err == nil {
+			metrics.TrackMetricChartCacheLookup(chartCacheArchive, true)
+			return nil, nil
+		}
+		metrics.TrackMetricChartCacheLookup(chartCacheArchive, false)
+		if err := c.download(ctx, chartURL, digest, path); err != nil {
+			return nil, err
+		}
+		c.evict(ctx, path)
+		return nil, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	data, err := c.readArchive(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// evicted by a concurrent download before being read, the chart is
+		// downloaded bypassing the cache
+		buf := new(bytes.Buffer)
+		if err := downloadChartArchive(ctx, chartURL, digest, buf); err != nil {
+			return nil, err
+		}
+		data = buf.Bytes()
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read cached chart %s: %w", chartURL, err)
+	}
+
+	helmChart, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		// the archive is downloaded again on the next call
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("failed to load archive for chart %s, %w", chartURL, err)
+	}
+	return helmChart, nil
+}
+
+// readArchive reads the archive at the given path holding off the evictions
+// and marks it as recently used.
+func (c *ChartCache) readArchive(path string) ([]byte, error) {
+	c.evictMu.RLock()
+	defer c.evictMu.RUnlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// the modification time orders the archives by their last use for eviction
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, nil
+}
+
+// Validation returns the validation of the chart with the given digest from
+// the cache, validating the chart with the given func and storing its result
+// only if it is not cached yet. The errors of the func are not cached. The
+// returned validation is shared by the callers and must not be modified.
+func (c *ChartCache) Validation(ctx context.Context, digest string, validate func() (*ChartValidation, error)) (*ChartValidation, error) {
+	path, err := c.path(digest, chartValidationExt)
+	if err != nil {
+		return nil, err
+	}
+
+	c.validationsMu.Lock()
+	validation, ok := c.validations[path]
+	c.validationsMu.Unlock()
+	if ok {
+		metrics.TrackMetricChartCacheLookup(chartCacheValidation, true)
+		return validation, nil
+	}
+
+	v, err, _ := c.validateCalls.Do(path, func() (any, error) {
+		validation, ok := c.readValidation(path)
+		metrics.TrackMetricChartCacheLookup(chartCacheValidation, ok)
+		if !ok {
+			var err error
+			if validation, err = validate(); err != nil {
+				return nil, err
+			}
+			if err := c.writeValidation(validation, path); err != nil {
+				// the chart is validated again on the next call
+				log.FromContext(ctx).Error(err, "failed to store chart validation in the cache", "path", path)
+			}
+		}
+
+		c.validationsMu.Lock()
+		c.validations[path] = validation
+		c.validationsMu.Unlock()
+		return validation, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ChartValidation), nil
+}
+
+// readValidation reads the validation stored at the given path, the
+// unreadable validations are removed.
+func (*ChartCache) readValidation(path string) (*ChartValidation, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	validation := new(ChartValidation)
+	if err := json.Unmarshal(data, validation); err != nil {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	return validation, true
+}
+
+// writeValidation stores the validation at the given path, the validation
+// appears in the cache only once it is completely written.
+func (c *ChartCache) writeValidation(validation *ChartValidation, path string) error {
+	data, err := json.Marshal(validation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal chart validation: %w", err)
+	}
+
+	f, err := os.CreateTemp(c.dir, ".validation-*")
+	if err != nil {
+		return fmt.Errorf("failed to create chart cache file: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write chart cache file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write chart cache file: %w", err)
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// download stores the verified chart archive at the given path, the archive
+// appears in the cache only once it is completely written.
+func (c *ChartCache) download(ctx context.Context, chartURL, digest, path string) error {
+	f, err := os.CreateTemp(c.dir, ".download-*")
+	if err != nil {
+		return fmt.Errorf("failed to create chart cache file: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if err := downloadChartArchive(ctx, chartURL, digest, f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write chart cache file: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to store chart %s in the cache: %w", chartURL, err)
+	}
+	return nil
+}
+
+// evict removes the least recently used archives except the given one along
+// with the validations of their charts until the total size of the cache fits
+// the limit.
+func (c *ChartCache) evict(ctx context.Context, keep string) {
+	if c.maxSize <= 0 {
+		return
+	}
+
+	c.evictMu.Lock()
+	defer c.evictMu.Unlock()
+
+	l := log.FromContext(ctx)
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		l.Error(err, "failed to list chart cache", "dir", c.dir)
+		return
+	}
+
+	var (
+		archives []fs.FileInfo
+		size     int64
+	)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), chartArchiveExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// removed concurrently
+			continue
+		}
+		archives = append(archives, info)
+		size += info.Size()
+	}
+
+	slices.SortFunc(archives, func(a, b fs.FileInfo) int {
+		return a.ModTime().Compare(b.ModTime())
+	})
+
+	for _, info := range archives {
+		if size <= c.maxSize {
+			return
+		}
+		path := filepath.Join(c.dir, info.Name())
+		if path == keep {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			l.Error(err, "failed to evict chart from the cache", "path", path)
+			continue
+		}
+		size -= info.Size()
+
+		validationPath := strings.TrimSuffix(path, chartArchiveExt) + chartValidationExt
+		if err := os.Remove(validationPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			l.Error(err, "failed to evict chart validation from the cache", "path", validationPath)
+		}
+		c.validationsMu.Lock()
+		delete(c.validations, validationPath)
+		c.validationsMu.Unlock()
+	}
+}
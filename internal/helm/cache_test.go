@@ -0,0 +1,120 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	godigest "github.com/opencontainers/go-digest"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestChartCache(t *testing.T) {
+	g := NewWithT(t)
+
+	archives := make(map[string][]byte)
+	for _, version := range []string{"0.1.0", "0.2.0"} {
+		path, err := chartutil.Save(&chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "test", Version: version}}, t.TempDir())
+		g.Expect(err).NotTo(HaveOccurred())
+		archives["/"+version], err = os.ReadFile(path)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write(archives[r.URL.Path])
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "charts")
+	// fits a single archive only
+	cache, err := NewChartCache(dir, int64(len(archives["/0.1.0"])+1))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for range 2 {
+		c, err := cache.Chart(t.Context(), server.URL+"/0.1.0", godigest.FromBytes(archives["/0.1.0"]).String())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(c.Metadata.Version).To(Equal("0.1.0"))
+	}
+	g.Expect(requests.Load()).To(BeEquivalentTo(1))
+
+	_, err = cache.Chart(t.Context(), server.URL+"/0.1.0", godigest.FromBytes(archives["/0.2.0"]).String())
+	g.Expect(err).To(MatchError(ContainSubstring("verification for digest")))
+
+	c, err := cache.Chart(t.Context(), server.URL+"/0.2.0", godigest.FromBytes(archives["/0.2.0"]).String())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Metadata.Version).To(Equal("0.2.0"))
+
+	entries, err := os.ReadDir(dir)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(entries).To(HaveLen(1), "the least recently used archive is expected to be evicted")
+	g.Expect(entries[0].Name()).To(Equal("sha256-" + godigest.FromBytes(archives["/0.2.0"]).Encoded() + chartArchiveExt))
+}
+
+func TestChartCacheValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := filepath.Join(t.TempDir(), "charts")
+	cache, err := NewChartCache(dir, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	digest := godigest.FromString("chart").String()
+	var validations int
+	validate := func() (*ChartValidation, error) {
+		validations++
+		return NewChartValidation(&chart.Chart{Metadata: &chart.Metadata{
+			APIVersion:  chart.APIVersionV2,
+			Name:        "test",
+			Version:     "0.1.0",
+			Description: "test chart",
+			Annotations: map[string]string{"cluster.x-k8s.io/provider": "infrastructure-aws"},
+		}})
+	}
+
+	_, err = cache.Validation(t.Context(), digest, func() (*ChartValidation, error) { return nil, errors.New("download failed") })
+	g.Expect(err).To(MatchError("download failed"))
+
+	for range 2 {
+		v, err := cache.Validation(t.Context(), digest, validate)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(v.Error).To(BeEmpty())
+		g.Expect(v.Description).To(Equal("test chart"))
+	}
+	g.Expect(validations).To(Equal(1), "the failed validations are not expected to be cached")
+
+	// the validations stored on disk survive the restart of the controller
+	restarted, err := NewChartCache(dir, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	v, err := restarted.Validation(t.Context(), digest, validate)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(v.Annotations).To(HaveKeyWithValue("cluster.x-k8s.io/provider", "infrastructure-aws"))
+	g.Expect(v.Values).To(MatchJSON("null"))
+	g.Expect(validations).To(Equal(1))
+
+	v, err = cache.Validation(t.Context(), godigest.FromString("invalid").String(), func() (*ChartValidation, error) {
+		return NewChartValidation(&chart.Chart{})
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(v.Error).To(ContainSubstring("metadata is required"))
+}
@@ -16,12 +16,19 @@ package helm
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	hcv2 "github.com/fluxcd/helm-controller/api/v2"
 	"github.com/fluxcd/pkg/apis/meta"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/csaupgrade"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -33,6 +40,11 @@ const (
 	DefaultReconcileInterval = 10 * time.Minute
 )
 
+// csaFieldManager is the field manager the HelmReleases have been created and
+// updated client-side with, the API server derives it from the default user
+// agent of the client, i.e. the name of the binary.
+var csaFieldManager = strings.Split(rest.DefaultKubernetesUserAgent(), "/")[0]
+
 type ReconcileHelmReleaseOpts struct {
 	Values            *apiextensionsv1.JSON
 	OwnerReference    *metav1.OwnerReference
@@ -40,7 +52,16 @@ type ReconcileHelmReleaseOpts struct {
 	ReconcileInterval *time.Duration
 	Install           *hcv2.Install
 	TargetNamespace   string
+	KubeConfig        *meta.KubeConfigReference
 	DependsOn         []meta.NamespacedObjectReference
+	PostRenderers     []hcv2.PostRenderer
+	// ReleaseName overrides the name of the Helm release, defaults to the name of the HelmRelease.
+	ReleaseName string
+	// CorrelationID is set as the annotation of the HelmRelease if not empty.
+	CorrelationID string
+	// FieldManager is the field manager the HelmRelease is applied with
+	// server-side, it is created or updated client-side if empty.
+	FieldManager string
 }
 
 func ReconcileHelmRelease(ctx context.Context,
@@ -56,37 +77,16 @@ func ReconcileHelmRelease(ctx context.Context,
 		},
 	}
 
-	operation, err := ctrl.CreateOrUpdate(ctx, cl, hr, func() error {
-		if hr.Labels == nil {
-			hr.Labels = make(map[string]string)
-		}
-		hr.Labels[kcm.KCMManagedLabelKey] = kcm.KCMManagedLabelValue
-
-		if opts.OwnerReference != nil {
-			hr.OwnerReferences = []metav1.OwnerReference{*opts.OwnerReference}
+	if opts.FieldManager != "" {
+		operation, err := applyHelmRelease(ctx, cl, hr, opts)
+		if err != nil {
+			return nil, operation, err
 		}
+		return hr, operation, nil
+	}
 
-		hr.Spec.ChartRef = opts.ChartRef
-		hr.Spec.Interval = metav1.Duration{Duration: func() time.Duration {
-			if opts.ReconcileInterval != nil {
-				return *opts.ReconcileInterval
-			}
-			return DefaultReconcileInterval
-		}()}
-		hr.Spec.ReleaseName = name
-
-		if opts.Values != nil {
-			hr.Spec.Values = opts.Values
-		}
-		if opts.DependsOn != nil {
-			hr.Spec.DependsOn = opts.DependsOn
-		}
-		if opts.TargetNamespace != "" {
-			hr.Spec.TargetNamespace = opts.TargetNamespace
-		}
-		if opts.Install != nil {
-			hr.Spec.Install = opts.Install
-		}
+	operation, err := ctrl.CreateOrUpdate(ctx, cl, hr, func() error {
+		setHelmReleaseSpec(hr, opts)
 		return nil
 	})
 	if err != nil {
@@ -96,6 +96,105 @@ func ReconcileHelmRelease(ctx context.Context,
 	return hr, operation, nil
 }
 
+// applyHelmRelease applies the desired state of the HelmRelease server-side
+// with the field manager of the options, taking over the fields set by the
+// previous client-side updates, and reports whether it has been changed.
+func applyHelmRelease(ctx context.Context, cl client.Client, hr *hcv2.HelmRelease, opts ReconcileHelmReleaseOpts) (controllerutil.OperationResult, error) {
+	existing := new(hcv2.HelmRelease)
+	err := cl.Get(ctx, client.ObjectKeyFromObject(hr), existing)
+	if client.IgnoreNotFound(err) != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	created := apierrors.IsNotFound(err)
+
+	if !created {
+		if err := upgradeManagedFields(ctx, cl, existing, opts.FieldManager); err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+	}
+
+	hr.SetGroupVersionKind(hcv2.GroupVersion.WithKind(hcv2.HelmReleaseKind))
+	setHelmReleaseSpec(hr, opts)
+	if err := cl.Patch(ctx, hr, client.Apply, client.FieldOwner(opts.FieldManager), client.ForceOwnership); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	switch {
+	case created:
+		return controllerutil.OperationResultCreated, nil
+	case hr.ResourceVersion != existing.ResourceVersion:
+		return controllerutil.OperationResultUpdated, nil
+	default:
+		return controllerutil.OperationResultNone, nil
+	}
+}
+
+// upgradeManagedFields moves the fields owned by the client-side field manager
+// of the HelmRelease to the given server-side one, so the fields no longer set
+// by the latter are removed upon the apply. The HelmRelease is patched only
+// once, while it still has the fields of the client-side field manager.
+func upgradeManagedFields(ctx context.Context, cl client.Client, hr *hcv2.HelmRelease, fieldManager string) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(hr, sets.New(csaFieldManager), fieldManager)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade the managed fields of the HelmRelease %s: %w", client.ObjectKeyFromObject(hr), err)
+	}
+	if patch == nil {
+		return nil
+	}
+
+	if err := cl.Patch(ctx, hr, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		return fmt.Errorf("failed to upgrade the managed fields of the HelmRelease %s: %w", client.ObjectKeyFromObject(hr), err)
+	}
+	return nil
+}
+
+// setHelmReleaseSpec sets the labels, the annotations, the owner and the spec
+// of the HelmRelease from the options.
+func setHelmReleaseSpec(hr *hcv2.HelmRelease, opts ReconcileHelmReleaseOpts) {
+	if hr.Labels == nil {
+		hr.Labels = make(map[string]string)
+	}
+	hr.Labels[kcm.KCMManagedLabelKey] = kcm.KCMManagedLabelValue
+
+	if opts.CorrelationID != "" {
+		if hr.Annotations == nil {
+			hr.Annotations = make(map[string]string)
+		}
+		hr.Annotations[kcm.CorrelationIDAnnotation] = opts.CorrelationID
+	}
+
+	if opts.OwnerReference != nil {
+		hr.OwnerReferences = []metav1.OwnerReference{*opts.OwnerReference}
+	}
+
+	hr.Spec.ChartRef = opts.ChartRef
+	hr.Spec.Interval = metav1.Duration{Duration: func() time.Duration {
+		if opts.ReconcileInterval != nil {
+			return *opts.ReconcileInterval
+		}
+		return DefaultReconcileInterval
+	}()}
+	hr.Spec.ReleaseName = hr.Name
+	if opts.ReleaseName != "" {
+		hr.Spec.ReleaseName = opts.ReleaseName
+	}
+	hr.Spec.KubeConfig = opts.KubeConfig
+
+	if opts.Values != nil {
+		hr.Spec.Values = opts.Values
+	}
+	if opts.DependsOn != nil {
+		hr.Spec.DependsOn = opts.DependsOn
+	}
+	if opts.TargetNamespace != "" {
+		hr.Spec.TargetNamespace = opts.TargetNamespace
+	}
+	if opts.Install != nil {
+		hr.Spec.Install = opts.Install
+	}
+	hr.Spec.PostRenderers = opts.PostRenderers
+}
+
 func DeleteHelmRelease(ctx context.Context, cl client.Client, name, namespace string) error {
 	err := cl.Delete(ctx, &hcv2.HelmRelease{
 		ObjectMeta: metav1.ObjectMeta{
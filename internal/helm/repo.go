@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/utils"
 )
 
 type DefaultRegistryConfig struct {
@@ -36,9 +37,31 @@ type DefaultRegistryConfig struct {
 	RepoType          string
 	URL               string
 	CredentialsSecret string
+	CertSecret        string
 	Insecure          bool
 }
 
+// WithMirror returns the config of the given registry mirror, the config
+// itself is returned if the mirror is not set.
+func (r *DefaultRegistryConfig) WithMirror(mirror *kcm.RegistryMirror) (*DefaultRegistryConfig, error) {
+	if mirror == nil {
+		return r, nil
+	}
+
+	repoType, err := utils.DetermineDefaultRepositoryType(mirror.ChartsURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine the registry mirror repository type: %w", err)
+	}
+
+	return &DefaultRegistryConfig{
+		RepoType:          repoType,
+		URL:               mirror.ChartsURL,
+		CredentialsSecret: mirror.CredentialsSecretRef,
+		CertSecret:        mirror.CASecretRef,
+		Insecure:          mirror.Insecure,
+	}, nil
+}
+
 func (r *DefaultRegistryConfig) HelmRepositorySpec() sourcev1.HelmRepositorySpec {
 	return sourcev1.HelmRepositorySpec{
 		Type:     r.RepoType,
@@ -53,6 +76,14 @@ func (r *DefaultRegistryConfig) HelmRepositorySpec() sourcev1.HelmRepositorySpec
 			}
 			return nil
 		}(),
+		CertSecretRef: func() *meta.LocalObjectReference {
+			if r.CertSecret != "" {
+				return &meta.LocalObjectReference{
+					Name: r.CertSecret,
+				}
+			}
+			return nil
+		}(),
 	}
 }
 
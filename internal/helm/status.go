@@ -0,0 +1,103 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// GetReleaseStatuses returns the state of the last revisions of the given
+// Helm releases stored in the cluster of the given clientset. The releases
+// not installed yet are omitted.
+func GetReleaseStatuses(ctx context.Context, clientset kubernetes.Interface, releases []client.ObjectKey) ([]kcm.ServiceHelmReleaseStatus, error) {
+	statuses := make([]kcm.ServiceHelmReleaseStatus, 0, len(releases))
+	for _, key := range releases {
+		last, err := lastRevision(ctx, clientset, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Helm release %s: %w", key, err)
+		}
+		if last == nil {
+			continue
+		}
+		statuses = append(statuses, releaseStatus(key, last))
+	}
+
+	return statuses, nil
+}
+
+// lastRevision returns the last revision of the Helm release, nil if it has
+// not been installed yet. The revisions are listed by the labels of their
+// storage Secrets without the superseded ones, and the last of them is decoded
+// only.
+func lastRevision(ctx context.Context, clientset kubernetes.Interface, key client.ObjectKey) (*release.Release, error) {
+	secrets, err := clientset.CoreV1().Secrets(key.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{"owner": "helm", "name": key.Name}.String() + ",status!=" + release.StatusSuperseded.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var last int
+	for _, secret := range secrets.Items {
+		if version, err := strconv.Atoi(secret.Labels["version"]); err == nil {
+			last = max(last, version)
+		}
+	}
+	if last == 0 {
+		return nil, nil
+	}
+
+	rel, err := driver.NewSecrets(clientset.CoreV1().Secrets(key.Namespace)).Get(fmt.Sprintf("sh.helm.release.v1.%s.v%d", key.Name, last))
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		// the revision has been deleted in the meantime
+		return nil, nil
+	}
+	return rel, err
+}
+
+func releaseStatus(key client.ObjectKey, rel *release.Release) kcm.ServiceHelmReleaseStatus {
+	status := kcm.ServiceHelmReleaseStatus{
+		Name:      key.Name,
+		Namespace: key.Namespace,
+		Revision:  int32(rel.Version), //nolint:gosec // the revisions do not exceed int32
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		status.ChartVersion = rel.Chart.Metadata.Version
+		status.AppVersion = rel.Chart.Metadata.AppVersion
+	}
+	if rel.Info != nil {
+		status.Status = rel.Info.Status.String()
+		if rel.Info.Status == release.StatusFailed {
+			status.FailureMessage = rel.Info.Description
+		}
+		if !rel.Info.LastDeployed.IsZero() {
+			status.LastDeployed = &metav1.Time{Time: rel.Info.LastDeployed.Time}
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,83 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	helmtime "helm.sh/helm/v3/pkg/time"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+func TestGetReleaseStatuses(t *testing.T) {
+	g := NewWithT(t)
+
+	lastDeployed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	newRelease := func(version int, chartVersion string, status release.Status, description string) *release.Release {
+		return &release.Release{
+			Name:      "ingress-nginx",
+			Namespace: "ingress",
+			Version:   version,
+			Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "ingress-nginx", Version: chartVersion, AppVersion: "1.12.0"}},
+			Info:      &release.Info{Status: status, Description: description, LastDeployed: helmtime.Time{Time: lastDeployed}},
+		}
+	}
+
+	clientset := fake.NewClientset()
+	secrets := driver.NewSecrets(clientset.CoreV1().Secrets("ingress"))
+	for _, rel := range []*release.Release{
+		newRelease(1, "4.12.0", release.StatusSuperseded, "Install complete"),
+		newRelease(2, "4.12.1", release.StatusFailed, "Upgrade \"ingress-nginx\" failed: timed out waiting for the condition"),
+	} {
+		g.Expect(secrets.Create(fmt.Sprintf("sh.helm.release.v1.%s.v%d", rel.Name, rel.Version), rel)).To(Succeed())
+	}
+
+	// the superseded revisions are not decoded
+	_, err := clientset.CoreV1().Secrets("ingress").Create(t.Context(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "sh.helm.release.v1.ingress-nginx.v0",
+			Labels: map[string]string{"owner": "helm", "name": "ingress-nginx", "status": release.StatusSuperseded.String(), "version": "0"},
+		},
+		Data: map[string][]byte{"release": []byte("corrupted")},
+	}, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	statuses, err := GetReleaseStatuses(t.Context(), clientset, []client.ObjectKey{
+		{Namespace: "ingress", Name: "ingress-nginx"},
+		{Namespace: "monitoring", Name: "not-installed"},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(statuses).To(Equal([]kcm.ServiceHelmReleaseStatus{{
+		LastDeployed:   &metav1.Time{Time: lastDeployed},
+		Name:           "ingress-nginx",
+		Namespace:      "ingress",
+		ChartVersion:   "4.12.1",
+		AppVersion:     "1.12.0",
+		Status:         "failed",
+		FailureMessage: "Upgrade \"ingress-nginx\" failed: timed out waiting for the condition",
+		Revision:       2,
+	}}))
+}
@@ -26,6 +26,8 @@ import (
 	godigest "github.com/opencontainers/go-digest"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -34,16 +36,35 @@ func DownloadChartFromArtifact(ctx context.Context, artifact *sourcev1.Artifact)
 }
 
 func DownloadChart(ctx context.Context, chartURL, digest string) (*chart.Chart, error) {
+	if chartCache != nil && digest != "" {
+		return chartCache.Chart(ctx, chartURL, digest)
+	}
+
+	var buf bytes.Buffer
+	if err := downloadChartArchive(ctx, chartURL, digest, &buf); err != nil {
+		return nil, err
+	}
+
+	helmChart, err := loader.LoadArchive(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archive for chart %s, %w", chartURL, err)
+	}
+	return helmChart, nil
+}
+
+// downloadChartArchive writes the chart archive downloaded from the given URL
+// to the writer and verifies it against the digest unless it is empty.
+func downloadChartArchive(ctx context.Context, chartURL, digest string, w io.Writer) error {
 	l := log.FromContext(ctx, "chart", chartURL)
 
 	client := retryablehttp.NewClient()
 	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, chartURL, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -51,19 +72,31 @@ func DownloadChart(ctx context.Context, chartURL, digest string) (*chart.Chart,
 		}
 	}()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("chart download request failed: %s", resp.Status)
+		return fmt.Errorf("chart download request failed: %s", resp.Status)
 	}
 
-	var buf bytes.Buffer
-	if err := copyChart(resp.Body, &buf, digest); err != nil {
-		return nil, err
+	return copyChart(resp.Body, w, digest)
+}
+
+// RenderChart renders the templates of the given chart with the values
+// as if the chart is installed, and returns the rendered manifests keyed by the template name.
+// The lookup function is not available, so the charts relying on it are rendered as for a fresh installation.
+func RenderChart(c *chart.Chart, values map[string]any, releaseName, namespace string) (map[string]string, error) {
+	renderValues, err := chartutil.ToRenderValues(c, values, chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: namespace,
+		IsInstall: true,
+	}, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare values of the chart %s: %w", c.Name(), err)
 	}
 
-	helmChart, err := loader.LoadArchive(&buf)
+	manifests, err := engine.Render(c, renderValues)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load archive for chart %s, %w", chartURL, err)
+		return nil, fmt.Errorf("failed to render the chart %s: %w", c.Name(), err)
 	}
-	return helmChart, nil
+
+	return manifests, nil
 }
 
 func copyChart(reader io.Reader, writer io.Writer, digest string) error {
@@ -0,0 +1,66 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// ChartValidation is the result of the validation of the chart reported in
+// the statuses of the templates.
+type ChartValidation struct {
+	// Annotations are the annotations of the chart.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Description is the description of the chart.
+	Description string `json:"description,omitempty"`
+	// Error is the error the chart has failed the validation with, empty if
+	// the chart is valid.
+	Error string `json:"error,omitempty"`
+	// Values are the default values of the chart in JSON.
+	Values json.RawMessage `json:"values,omitempty"`
+}
+
+// NewChartValidation validates the given chart and returns the result.
+func NewChartValidation(helmChart *chart.Chart) (*ChartValidation, error) {
+	if err := helmChart.Validate(); err != nil {
+		return &ChartValidation{Error: err.Error()}, nil
+	}
+
+	values, err := json.Marshal(helmChart.Values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Helm chart values: %w", err)
+	}
+
+	return &ChartValidation{
+		Annotations: helmChart.Metadata.Annotations,
+		Description: helmChart.Metadata.Description,
+		Values:      values,
+	}, nil
+}
+
+// ValidateChart returns the validation of the chart with the given digest
+// through the chart cache, calling the given func to validate the chart only
+// if the validation is not cached yet. The chart is validated on every call
+// if the cache is disabled or the digest is empty.
+func ValidateChart(ctx context.Context, digest string, validate func() (*ChartValidation, error)) (*ChartValidation, error) {
+	if chartCache != nil && digest != "" {
+		return chartCache.Validation(ctx, digest, validate)
+	}
+	return validate()
+}
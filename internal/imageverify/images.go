@@ -0,0 +1,155 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// imageKeys are the keys of the manifests holding the container images,
+// the latter is used by the CAPI operator providers.
+var imageKeys = []string{"image", "imageUrl"}
+
+// ImagesFromManifests returns the sorted list of the container images
+// referenced by the given rendered chart manifests keyed by the template name.
+func ImagesFromManifests(manifests map[string]string) ([]string, error) {
+	var images []string
+	for name, manifest := range manifests {
+		if path.Ext(name) != ".yaml" && path.Ext(name) != ".yml" {
+			continue
+		}
+
+		found, err := ImagesFromManifest(manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		images = append(images, found...)
+	}
+
+	slices.Sort(images)
+	return slices.Compact(images), nil
+}
+
+// ImagesFromManifest returns the container images referenced by the given
+// multi-document YAML manifest.
+func ImagesFromManifest(manifest string) ([]string, error) {
+	var images []string
+
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	for {
+		var obj map[string]any
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		collectImages(obj, &images)
+	}
+
+	return images, nil
+}
+
+// ImageMatches reports whether the image matches any of the given
+// patterns. The patterns are matched against the image reference
+// without the tag or digest; the reference itself is matched too.
+func ImageMatches(image string, patterns []string) bool {
+	name, _ := splitImage(image)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, image); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OverrideImages returns the given images with the overrides applied the
+// same way as the Kustomize images transformer does.
+func OverrideImages(images []string, overrides []kcm.ImageOverride) []string {
+	if len(overrides) == 0 {
+		return images
+	}
+
+	result := make([]string, 0, len(images))
+	for _, image := range images {
+		name, suffix := splitImage(image)
+		idx := slices.IndexFunc(overrides, func(o kcm.ImageOverride) bool { return o.Name == name })
+		if idx < 0 {
+			result = append(result, image)
+			continue
+		}
+
+		o := overrides[idx]
+		if o.NewName != "" {
+			name = o.NewName
+		}
+		switch {
+		case o.Digest != "":
+			suffix = "@" + o.Digest
+		case o.NewTag != "":
+			suffix = ":" + o.NewTag
+		}
+		result = append(result, name+suffix)
+	}
+
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// splitImage splits the image reference into the name and the tag or digest
+// suffix along with its separator.
+func splitImage(image string) (name, suffix string) {
+	name = image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	return name, image[len(name):]
+}
+
+func collectImages(v any, dst *[]string) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, nested := range val {
+			if s, ok := nested.(string); ok && slices.Contains(imageKeys, k) {
+				if s = strings.TrimSpace(s); s != "" && !strings.ContainsAny(s, " \t{}") {
+					*dst = append(*dst, s)
+				}
+				continue
+			}
+			collectImages(nested, dst)
+		}
+	case []any:
+		for _, nested := range val {
+			collectImages(nested, dst)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/helm"
+)
+
+func TestImagesFromManifests(t *testing.T) {
+	g := NewWithT(t)
+
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "test", Version: "0.1.0", APIVersion: chart.APIVersionV2},
+		Values: map[string]any{
+			"image":    map[string]any{"repository": "ghcr.io/k0rdent/kcm/controller", "tag": "1.0.0"},
+			"provider": map[string]any{"imageUrl": "registry.k8s.io/cluster-api/cluster-api-controller:v1.9.0"},
+		},
+		Templates: []*chart.File{
+			{
+				Name: "templates/deployment.yaml",
+				Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox
+      containers:
+      - name: manager
+        image: {{ .Values.image.repository }}:{{ .Values.image.tag }}
+      - name: sidecar
+        image: {{ .Values.image.repository }}:{{ .Values.image.tag }}
+`),
+			},
+			{
+				Name: "templates/provider.yaml",
+				Data: []byte(`apiVersion: operator.cluster.x-k8s.io/v1alpha2
+kind: CoreProvider
+metadata:
+  name: cluster-api
+spec:
+  deployment:
+    containers:
+    - name: manager
+      imageUrl: {{ .Values.provider.imageUrl }}
+`),
+			},
+			{
+				Name: "templates/NOTES.txt",
+				Data: []byte(`image: not-an-image`),
+			},
+		},
+	}
+
+	manifests, err := helm.RenderChart(c, map[string]any{"image": map[string]any{"tag": "1.0.1"}}, "kcm", "kcm-system")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	images, err := ImagesFromManifests(manifests)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(images).To(Equal([]string{
+		"busybox",
+		"ghcr.io/k0rdent/kcm/controller:1.0.1",
+		"registry.k8s.io/cluster-api/cluster-api-controller:v1.9.0",
+	}))
+}
+
+func TestImageMatches(t *testing.T) {
+	tests := []struct {
+		image    string
+		patterns []string
+		want     bool
+	}{
+		{image: "docker.io/library/busybox:1.36", patterns: []string{"docker.io/library/*"}, want: true},
+		{image: "ghcr.io/k0rdent/kcm/controller@sha256:abc", patterns: []string{"ghcr.io/k0rdent/kcm/controller"}, want: true},
+		{image: "ghcr.io/k0rdent/kcm/controller:1.0.0", patterns: []string{"ghcr.io/k0rdent/kcm/controller:1.0.0"}, want: true},
+		{image: "ghcr.io/k0rdent/kcm/controller:1.0.0", patterns: []string{"ghcr.io/k0rdent/*"}},
+		{image: "busybox", patterns: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			NewWithT(t).Expect(ImageMatches(tt.image, tt.patterns)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestOverrideImages(t *testing.T) {
+	g := NewWithT(t)
+
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	images := []string{
+		"registry.k8s.io/cluster-api/cluster-api-controller:v1.9.0",
+		"quay.io/jetstack/cert-manager-controller:v1.16.0",
+		"ghcr.io/k0rdent/kcm/controller@sha256:abc",
+		"docker.io/library/busybox",
+	}
+
+	g.Expect(OverrideImages(images, nil)).To(Equal(images))
+	g.Expect(OverrideImages(images, []kcm.ImageOverride{
+		{Name: "registry.k8s.io/cluster-api/cluster-api-controller", NewName: "registry.local/capi/cluster-api-controller"},
+		{Name: "quay.io/jetstack/cert-manager-controller", NewTag: "v1.16.1"},
+		{Name: "ghcr.io/k0rdent/kcm/controller", NewTag: "v1.0.0"},
+		{Name: "docker.io/library/busybox", NewName: "registry.local/busybox", NewTag: "1.36", Digest: digest},
+		{Name: "docker.io/library/nginx", NewTag: "1.27"},
+	})).To(Equal([]string{
+		"ghcr.io/k0rdent/kcm/controller:v1.0.0",
+		"quay.io/jetstack/cert-manager-controller:v1.16.1",
+		"registry.local/busybox@" + digest,
+		"registry.local/capi/cluster-api-controller:v1.9.0",
+	}))
+}
@@ -0,0 +1,92 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	dockerHubRegistry     = "docker.io"
+	dockerHubRegistryHost = "registry-1.docker.io"
+)
+
+// reference is a parsed container image reference.
+type reference struct {
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+// parseReference parses the given image reference applying the same
+// defaults as the container runtimes do: images without a registry are
+// pulled from the Docker Hub, images without a tag are tagged as latest.
+func parseReference(image string) (reference, error) {
+	if image == "" {
+		return reference{}, errors.New("empty image reference")
+	}
+
+	var ref reference
+
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.digest = name[:i], name[i+1:]
+		if !strings.HasPrefix(ref.digest, "sha256:") {
+			return reference{}, fmt.Errorf("unsupported digest in the image reference %q", image)
+		}
+	}
+
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.tag = name[:i], name[i+1:]
+	}
+	if ref.tag == "" && ref.digest == "" {
+		ref.tag = "latest"
+	}
+
+	ref.registry, ref.repository = dockerHubRegistry, name
+	if i := strings.Index(name, "/"); i >= 0 {
+		if host := name[:i]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.registry, ref.repository = host, name[i+1:]
+		}
+	}
+	if ref.registry == dockerHubRegistry && !strings.Contains(ref.repository, "/") {
+		ref.repository = "library/" + ref.repository
+	}
+
+	if ref.repository == "" || strings.ToLower(ref.repository) != ref.repository {
+		return reference{}, fmt.Errorf("invalid repository in the image reference %q", image)
+	}
+
+	return ref, nil
+}
+
+// host returns the registry host to talk to.
+func (r reference) host() string {
+	if r.registry == dockerHubRegistry {
+		return dockerHubRegistryHost
+	}
+	return r.registry
+}
+
+// identifier returns the digest of the reference if given, otherwise the tag.
+func (r reference) identifier() string {
+	if r.digest != "" {
+		return r.digest
+	}
+	return r.tag
+}
@@ -0,0 +1,213 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	mediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex       = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerList     = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	// maxResponseSize limits the size of the manifests and signature payloads.
+	maxResponseSize = 4 << 20
+)
+
+var errNotFound = errors.New("not found")
+
+type manifest struct {
+	Layers []descriptor `json:"layers"`
+}
+
+type descriptor struct {
+	Annotations map[string]string `json:"annotations,omitempty"`
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+}
+
+// registryClient is a minimal read-only client of the OCI distribution API
+// supporting anonymous access and the bearer token authentication flow.
+type registryClient struct {
+	httpClient *http.Client
+}
+
+// resolve returns the digest of the manifest the given reference points to.
+func (c *registryClient) resolve(ctx context.Context, ref reference) (string, error) {
+	_, digest, err := c.get(ctx, ref, "manifests/"+ref.identifier(),
+		mediaTypeOCIIndex, mediaTypeOCIManifest, mediaTypeDockerList, mediaTypeDockerManifest)
+	if err != nil {
+		return "", err
+	}
+
+	if ref.digest != "" && digest != ref.digest {
+		return "", fmt.Errorf("manifest digest %s does not match the referenced one %s", digest, ref.digest)
+	}
+
+	return digest, nil
+}
+
+// manifest fetches the manifest with the given tag or digest from the repository of the reference.
+func (c *registryClient) manifest(ctx context.Context, ref reference, identifier string) (*manifest, error) {
+	body, _, err := c.get(ctx, ref, "manifests/"+identifier, mediaTypeOCIManifest, mediaTypeDockerManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(manifest)
+	if err := json.Unmarshal(body, m); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest %s: %w", identifier, err)
+	}
+
+	return m, nil
+}
+
+// blob fetches the blob with the given digest from the repository of the reference.
+func (c *registryClient) blob(ctx context.Context, ref reference, digest string) ([]byte, error) {
+	body, actual, err := c.get(ctx, ref, "blobs/"+digest)
+	if err != nil {
+		return nil, err
+	}
+
+	if actual != digest {
+		return nil, fmt.Errorf("blob digest %s does not match the expected one %s", actual, digest)
+	}
+
+	return body, nil
+}
+
+// get fetches the given path of the repository API and returns the body
+// along with its digest.
+func (c *registryClient) get(ctx context.Context, ref reference, path string, accept ...string) ([]byte, string, error) {
+	endpoint := fmt.Sprintf("https://%s/v2/%s/%s", ref.host(), ref.repository, path)
+
+	resp, err := c.do(ctx, endpoint, "", accept)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+
+		token, err := c.token(ctx, challenge, ref)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to authenticate to %s: %w", ref.registry, err)
+		}
+
+		if resp, err = c.do(ctx, endpoint, token, accept); err != nil {
+			return nil, "", err
+		}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, "", fmt.Errorf("%s %s: %w", ref.repository, path, errNotFound)
+	default:
+		return nil, "", fmt.Errorf("unexpected status code %d while fetching %s %s", resp.StatusCode, ref.repository, path)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s %s: %w", ref.repository, path, err)
+	}
+
+	sum := sha256.Sum256(body)
+	return body, "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+func (c *registryClient) do(ctx context.Context, endpoint, token string, accept []string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(accept) > 0 {
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// token obtains an anonymous pull token according to the given bearer challenge.
+func (c *registryClient) token(ctx context.Context, challenge string, ref reference) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported authentication scheme %q", scheme)
+	}
+
+	values := make(map[string]string)
+	for _, param := range strings.Split(params, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if ok {
+			values[strings.ToLower(k)] = strings.Trim(v, `"`)
+		}
+	}
+
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid realm in the bearer challenge %q", challenge)
+	}
+
+	query := realm.Query()
+	if service := values["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", "repository:"+ref.repository+":pull")
+	realm.RawQuery = query.Encode()
+
+	resp, err := c.do(ctx, realm.String(), "", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d from the token endpoint", resp.StatusCode)
+	}
+
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	if tokenResponse.AccessToken != "" {
+		return tokenResponse.AccessToken, nil
+	}
+
+	return "", errors.New("empty token in the token response")
+}
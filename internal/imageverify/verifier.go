@@ -0,0 +1,218 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package imageverify verifies the cosign signatures of container images
+// against a set of trusted public keys.
+package imageverify
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// signatureAnnotation is the annotation of the signature layer holding
+	// the base64-encoded signature of the layer payload.
+	signatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	// cacheTTL is the period the successful verification result of an image is cached for.
+	cacheTTL = time.Hour
+)
+
+// Verifier verifies the cosign signatures of the container images.
+type Verifier struct {
+	registry *registryClient
+	verified map[string]time.Time
+	keys     []crypto.PublicKey
+	mu       sync.Mutex
+}
+
+// NewVerifier returns a Verifier trusting the given PEM-encoded public keys.
+// If the httpClient is nil, the default one is used.
+func NewVerifier(pemKeys []byte, httpClient *http.Client) (*Verifier, error) {
+	keys, err := parsePublicKeys(pemKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Verifier{
+		registry: &registryClient{httpClient: httpClient},
+		verified: make(map[string]time.Time),
+		keys:     keys,
+	}, nil
+}
+
+// Verify checks that the given image is signed with at least one of the trusted keys.
+// Successful results are cached for an hour.
+func (v *Verifier) Verify(ctx context.Context, image string) error {
+	v.mu.Lock()
+	verifiedAt, ok := v.verified[image]
+	v.mu.Unlock()
+	if ok && time.Since(verifiedAt) < cacheTTL {
+		return nil
+	}
+
+	if err := v.verify(ctx, image); err != nil {
+		return fmt.Errorf("failed to verify signature of the image %s: %w", image, err)
+	}
+
+	v.mu.Lock()
+	v.verified[image] = time.Now()
+	v.mu.Unlock()
+
+	return nil
+}
+
+func (v *Verifier) verify(ctx context.Context, image string) error {
+	ref, err := parseReference(image)
+	if err != nil {
+		return err
+	}
+
+	digest, err := v.registry.resolve(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to resolve image digest: %w", err)
+	}
+
+	signatures, err := v.registry.manifest(ctx, ref, signatureTag(digest))
+	if err != nil {
+		if errors.Is(err, errNotFound) {
+			return errors.New("no signatures found")
+		}
+		return fmt.Errorf("failed to get signatures: %w", err)
+	}
+
+	var errs error
+	for _, layer := range signatures.Layers {
+		signature, ok := layer.Annotations[signatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		payload, err := v.registry.blob(ctx, ref, layer.Digest)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to get signature payload: %w", err))
+			continue
+		}
+
+		if err := v.verifySignature(payload, signature, digest); err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+
+		return nil
+	}
+
+	if errs == nil {
+		return errors.New("no signatures found")
+	}
+
+	return errs
+}
+
+// verifySignature checks the signature of the given payload with the trusted keys and
+// that the payload refers to the image with the given digest.
+func (v *Verifier) verifySignature(payload []byte, signature, digest string) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	if !slices.ContainsFunc(v.keys, func(key crypto.PublicKey) bool { return verifyWithKey(key, payload, sig) }) {
+		return errors.New("signature does not match any of the trusted keys")
+	}
+
+	var simpleSigning struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+		return fmt.Errorf("failed to decode signature payload: %w", err)
+	}
+
+	if signed := simpleSigning.Critical.Image.DockerManifestDigest; signed != digest {
+		return fmt.Errorf("signature is issued for the digest %s instead of %s", signed, digest)
+	}
+
+	return nil
+}
+
+// signatureTag returns the tag cosign stores the signatures of the image with the given digest under.
+func signatureTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + ".sig"
+}
+
+func verifyWithKey(key crypto.PublicKey, payload, sig []byte) bool {
+	hash := sha256.Sum256(payload)
+
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, hash[:], sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, payload, sig)
+	}
+
+	return false
+}
+
+func parsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM-encoded public keys found")
+	}
+
+	return keys, nil
+}
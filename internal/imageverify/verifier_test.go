@@ -0,0 +1,244 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const testToken = "test-token"
+
+type testRegistry struct {
+	*httptest.Server
+	objects map[string][]byte
+}
+
+func newTestRegistry(t *testing.T) *testRegistry {
+	t.Helper()
+
+	r := &testRegistry{objects: make(map[string][]byte)}
+	r.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": testToken})
+			return
+		}
+
+		if req.Header.Get("Authorization") != "Bearer "+testToken {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, r.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, ok := r.objects[req.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func (r *testRegistry) host() string {
+	return strings.TrimPrefix(r.URL, "https://")
+}
+
+// push stores an image manifest under the given tag and returns its digest.
+func (r *testRegistry) push(repository, tag string) string {
+	body := []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"layers":[],"annotations":{"repository":%q,"tag":%q}}`, mediaTypeOCIManifest, repository, tag))
+	digest := digestOf(body)
+	r.objects["/v2/"+repository+"/manifests/"+tag] = body
+	r.objects["/v2/"+repository+"/manifests/"+digest] = body
+	return digest
+}
+
+// sign stores the cosign signature of the given digest signed with the key.
+func (r *testRegistry) sign(t *testing.T, repository, digest string, key *ecdsa.PrivateKey) {
+	t.Helper()
+
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":%q},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, r.host()+"/"+repository, digest))
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatalf("failed to sign payload: %v", err)
+	}
+
+	payloadDigest := digestOf(payload)
+	r.objects["/v2/"+repository+"/blobs/"+payloadDigest] = payload
+
+	m, err := json.Marshal(manifest{Layers: []descriptor{{
+		MediaType:   "application/vnd.dev.cosign.simplesigning.v1+json",
+		Digest:      payloadDigest,
+		Annotations: map[string]string{signatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+	}}})
+	if err != nil {
+		t.Fatalf("failed to marshal signature manifest: %v", err)
+	}
+	r.objects["/v2/"+repository+"/manifests/"+signatureTag(digest)] = m
+}
+
+func digestOf(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func newKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	trustedKey, trustedPEM := newKey(t)
+	untrustedKey, _ := newKey(t)
+
+	signedDigest := registry.push("k0rdent/signed", "v1")
+	registry.sign(t, "k0rdent/signed", signedDigest, trustedKey)
+
+	registry.push("k0rdent/unsigned", "v1")
+
+	untrustedDigest := registry.push("k0rdent/untrusted", "v1")
+	registry.sign(t, "k0rdent/untrusted", untrustedDigest, untrustedKey)
+
+	mismatchedDigest := registry.push("k0rdent/mismatched", "v1")
+	registry.sign(t, "k0rdent/mismatched", signedDigest, trustedKey)
+	const mismatchedManifests = "/v2/k0rdent/mismatched/manifests/"
+	registry.objects[mismatchedManifests+signatureTag(mismatchedDigest)] = registry.objects[mismatchedManifests+signatureTag(signedDigest)]
+
+	tests := []struct {
+		name    string
+		image   string
+		wantErr string
+	}{
+		{
+			name:  "signed image",
+			image: registry.host() + "/k0rdent/signed:v1",
+		},
+		{
+			name:  "signed image by digest",
+			image: registry.host() + "/k0rdent/signed@" + signedDigest,
+		},
+		{
+			name:    "unsigned image",
+			image:   registry.host() + "/k0rdent/unsigned:v1",
+			wantErr: "no signatures found",
+		},
+		{
+			name:    "image signed with an untrusted key",
+			image:   registry.host() + "/k0rdent/untrusted:v1",
+			wantErr: "signature does not match any of the trusted keys",
+		},
+		{
+			name:    "signature of another image",
+			image:   registry.host() + "/k0rdent/mismatched:v1",
+			wantErr: "signature is issued for the digest " + signedDigest,
+		},
+		{
+			name:    "missing image",
+			image:   registry.host() + "/k0rdent/missing:v1",
+			wantErr: "failed to resolve image digest",
+		},
+	}
+
+	verifier, err := NewVerifier(trustedPEM, registry.Client())
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := verifier.Verify(context.Background(), tt.image)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestNewVerifier(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewVerifier([]byte("not a key"), nil)
+	g.Expect(err).To(MatchError("no PEM-encoded public keys found"))
+
+	_, first := newKey(t)
+	_, second := newKey(t)
+	verifier, err := NewVerifier(append(first, second...), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(verifier.keys).To(HaveLen(2))
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image   string
+		want    reference
+		wantErr bool
+	}{
+		{image: "nginx", want: reference{registry: "docker.io", repository: "library/nginx", tag: "latest"}},
+		{image: "k0rdent/kcm:1.0.0", want: reference{registry: "docker.io", repository: "k0rdent/kcm", tag: "1.0.0"}},
+		{image: "ghcr.io/k0rdent/kcm/controller:1.0.0", want: reference{registry: "ghcr.io", repository: "k0rdent/kcm/controller", tag: "1.0.0"}},
+		{image: "localhost:5000/kcm@sha256:abc", want: reference{registry: "localhost:5000", repository: "kcm", digest: "sha256:abc"}},
+		{image: "registry.k8s.io/pause:3.9@sha256:abc", want: reference{registry: "registry.k8s.io", repository: "pause", tag: "3.9", digest: "sha256:abc"}},
+		{image: "kcm@md5:abc", wantErr: true},
+		{image: "Invalid/Repo", wantErr: true},
+		{image: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := parseReference(tt.image)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -16,6 +16,8 @@ package metrics
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,8 +34,47 @@ const (
 	metricLabelParentKind        = "parent_kind"
 	metricLabelParentNamespace   = "parent_namespace"
 	metricLabelParentName        = "parent_name"
+	metricLabelClusterNamespace  = "cluster_namespace"
+	metricLabelClusterName       = "cluster_name"
+	metricLabelCredential        = "credential"
+	metricLabelProvider          = "provider"
+	metricLabelResource          = "resource"
+	metricLabelPhase             = "phase"
+	metricLabelCurrency          = "currency"
+	metricLabelController        = "controller"
+	metricLabelClass             = "class"
+	metricLabelRetryable         = "retryable"
+	metricLabelBackupName        = "backup_name"
+	metricLabelNamespace         = "namespace"
+	metricLabelName              = "name"
+	metricLabelWebhook           = "webhook"
+	metricLabelOperation         = "operation"
+	metricLabelAllowed           = "allowed"
+	metricLabelReason            = "reason"
+	metricLabelResult            = "result"
+	metricLabelCache             = "cache"
 )
 
+// The phases of the ClusterDeployment reported by the phase metric.
+const (
+	ClusterDeploymentPhaseProvisioning = "Provisioning"
+	ClusterDeploymentPhaseUpgrading    = "Upgrading"
+	ClusterDeploymentPhaseReady        = "Ready"
+	ClusterDeploymentPhaseFailed       = "Failed"
+	ClusterDeploymentPhaseDeleting     = "Deleting"
+)
+
+var clusterDeploymentPhases = []string{
+	ClusterDeploymentPhaseProvisioning,
+	ClusterDeploymentPhaseUpgrading,
+	ClusterDeploymentPhaseReady,
+	ClusterDeploymentPhaseFailed,
+	ClusterDeploymentPhaseDeleting,
+}
+
+// clusterLifecycleBuckets range from 1 minute to about 4 hours.
+var clusterLifecycleBuckets = prometheus.ExponentialBuckets(60, 2, 9)
+
 var metricTemplateUsage = prometheus.NewGaugeVec(
 	prometheus.GaugeOpts{
 		Namespace: kcm.CoreKCMName,
@@ -52,10 +93,238 @@ var metricTemplateInvalidity = prometheus.NewGaugeVec(
 	[]string{metricLabelTemplateKind, metricLabelTemplateNamespace, metricLabelTemplateName},
 )
 
+// metricTemplateReferences is the number of the objects referencing each of
+// the templates as reported in its status. Unlike metricTemplateUsage, which
+// is set per parent object by the reconcilers of the parents, it is reported
+// for all of the templates, including the unused ones, and counts the
+// ClusterTemplates the clusters are being upgraded from.
+var metricTemplateReferences = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "template_references",
+		Help:      "Number of the objects referencing the template",
+	},
+	[]string{metricLabelTemplateKind, metricLabelTemplateNamespace, metricLabelTemplateName, metricLabelParentKind},
+)
+
+var metricClusterCertificatesDaysRemaining = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "cluster_certificates_days_remaining",
+		Help:      "Number of days remaining until the cluster certificates expire",
+	},
+	[]string{metricLabelClusterNamespace, metricLabelClusterName},
+)
+
+var metricCloudQuotaLimit = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "cloud_quota_limit",
+		Help:      "Cloud quota limit of the resource of the Credential account, -1 if unlimited",
+	},
+	[]string{metricLabelCredential, metricLabelProvider, metricLabelResource},
+)
+
+var metricCloudQuotaRemaining = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "cloud_quota_remaining",
+		Help:      "Remaining cloud quota of the resource of the Credential account, -1 if unlimited",
+	},
+	[]string{metricLabelCredential, metricLabelProvider, metricLabelResource},
+)
+
+var metricCloudAPICalls = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "cloud_api_calls_total",
+		Help:      "Number of the direct calls of the cloud API with the Credential by result, rejected if the circuit was open",
+	},
+	[]string{metricLabelCredential, metricLabelProvider, metricLabelResult},
+)
+
+var metricCloudAPICircuitOpen = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "cloud_api_circuit_open",
+		Help:      "Whether the calls of the cloud API with the Credential are suspended after repeated failures, 1 if suspended",
+	},
+	[]string{metricLabelCredential, metricLabelProvider},
+)
+
+var metricClusterDeploymentPhase = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "clusterdeployment_phase",
+		Help:      "Phase of the ClusterDeployment, 1 for the current phase and 0 for the others",
+	},
+	[]string{metricLabelClusterNamespace, metricLabelClusterName, metricLabelPhase},
+)
+
+var metricClusterDeploymentProvisioningDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "clusterdeployment_provisioning_duration_seconds",
+		Help:      "Time elapsed from the creation of the ClusterDeployment until it has been ready for the first time",
+		Buckets:   clusterLifecycleBuckets,
+	},
+	[]string{metricLabelClusterNamespace, metricLabelTemplateName},
+)
+
+var metricClusterDeploymentUpgradeDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "clusterdeployment_upgrade_duration_seconds",
+		Help:      "Time elapsed from the change of the ClusterTemplate of the ClusterDeployment until it has been ready again",
+		Buckets:   clusterLifecycleBuckets,
+	},
+	[]string{metricLabelClusterNamespace, metricLabelTemplateName},
+)
+
+var metricClusterDeploymentHelmReleaseFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "clusterdeployment_helmrelease_failures_total",
+		Help:      "Number of times the HelmRelease of the ClusterDeployment has failed",
+	},
+	[]string{metricLabelClusterNamespace, metricLabelClusterName},
+)
+
+var metricClusterDeploymentServicesReady = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "clusterdeployment_services_ready",
+		Help:      "Number of ready services of the ClusterDeployment",
+	},
+	[]string{metricLabelClusterNamespace, metricLabelClusterName},
+)
+
+var metricReconcileErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "reconcile_errors_total",
+		Help:      "Number of the failed reconciliations by the class of the failure",
+	},
+	[]string{metricLabelController, metricLabelClass, metricLabelRetryable},
+)
+
+var metricWebhookAdmissionDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "webhook_admission_duration_seconds",
+		Help:      "Time taken to handle the admission requests",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{metricLabelWebhook, metricLabelOperation, metricLabelAllowed},
+)
+
+var metricWebhookAdmissionRejections = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "webhook_admission_rejections_total",
+		Help:      "Number of the rejected admission requests by the reason of the rejection",
+	},
+	[]string{metricLabelWebhook, metricLabelOperation, metricLabelReason},
+)
+
+var metricClusterDeploymentReachable = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "clusterdeployment_reachable",
+		Help:      "Whether the API server of the cluster of the ClusterDeployment is reachable and ready",
+	},
+	[]string{metricLabelClusterNamespace, metricLabelClusterName},
+)
+
+var metricClusterDeploymentEstimatedMonthlyCost = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "clusterdeployment_estimated_monthly_cost",
+		Help:      "Estimated monthly cost of the ClusterDeployment",
+	},
+	[]string{metricLabelClusterNamespace, metricLabelClusterName, metricLabelCurrency},
+)
+
+var metricManagementBackupFailed = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "managementbackup_last_backup_failed",
+		Help:      "Whether the last backup of the ManagementBackup has failed",
+	},
+	[]string{metricLabelBackupName},
+)
+
+var metricManagementBackupLastSuccess = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "managementbackup_last_success_timestamp_seconds",
+		Help:      "Unix time the last succeeded backup of the ManagementBackup has been completed at",
+	},
+	[]string{metricLabelBackupName},
+)
+
+var metricManagementBackupLastSuccessItems = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "managementbackup_last_success_items",
+		Help:      "Number of the items backed up by the last succeeded backup of the ManagementBackup",
+	},
+	[]string{metricLabelBackupName},
+)
+
+var metricManagementBackupConsecutiveFailures = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "managementbackup_consecutive_failures",
+		Help:      "Number of the backups of the ManagementBackup failed in a row since the last succeeded one",
+	},
+	[]string{metricLabelBackupName},
+)
+
+var metricCredentialExpiration = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "credential_expiration_timestamp_seconds",
+		Help:      "Unix time the credentials of the identity of the Credential expire at",
+	},
+	[]string{metricLabelNamespace, metricLabelName},
+)
+
+var metricChartCacheLookups = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: kcm.CoreKCMName,
+		Name:      "chart_cache_lookups_total",
+		Help:      "Number of the lookups of the Helm chart archives and validations in the chart cache",
+	},
+	[]string{metricLabelCache, metricLabelResult},
+)
+
 func init() {
 	metrics.Registry.MustRegister(
 		metricTemplateUsage,
 		metricTemplateInvalidity,
+		metricTemplateReferences,
+		metricClusterCertificatesDaysRemaining,
+		metricCloudQuotaLimit,
+		metricCloudQuotaRemaining,
+		metricCloudAPICalls,
+		metricCloudAPICircuitOpen,
+		metricClusterDeploymentPhase,
+		metricClusterDeploymentProvisioningDuration,
+		metricClusterDeploymentUpgradeDuration,
+		metricClusterDeploymentHelmReleaseFailures,
+		metricClusterDeploymentServicesReady,
+		metricClusterDeploymentEstimatedMonthlyCost,
+		metricClusterDeploymentReachable,
+		metricReconcileErrors,
+		metricWebhookAdmissionDuration,
+		metricWebhookAdmissionRejections,
+		metricManagementBackupFailed,
+		metricManagementBackupLastSuccess,
+		metricManagementBackupLastSuccessItems,
+		metricManagementBackupConsecutiveFailures,
+		metricCredentialExpiration,
+		metricChartCacheLookups,
 	)
 }
 
@@ -104,3 +373,345 @@ func TrackMetricTemplateInvalidity(ctx context.Context, templateKind, templateNa
 		"value", value,
 	)
 }
+
+func TrackMetricTemplateReferences(ctx context.Context, templateKind, templateNamespace, templateName, parentKind string, references int32) {
+	metricTemplateReferences.With(prometheus.Labels{
+		metricLabelTemplateKind:      templateKind,
+		metricLabelTemplateNamespace: templateNamespace,
+		metricLabelTemplateName:      templateName,
+		metricLabelParentKind:        parentKind,
+	}).Set(float64(references))
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking template references metric",
+		metricLabelTemplateKind, templateKind,
+		metricLabelTemplateNamespace, templateNamespace,
+		metricLabelTemplateName, templateName,
+		metricLabelParentKind, parentKind,
+		"value", references,
+	)
+}
+
+// DeleteMetricsTemplateReferences deletes the references metrics of all of
+// the templates of the given kind in the namespace.
+func DeleteMetricsTemplateReferences(templateKind, templateNamespace string) {
+	metricTemplateReferences.DeletePartialMatch(prometheus.Labels{
+		metricLabelTemplateKind:      templateKind,
+		metricLabelTemplateNamespace: templateNamespace,
+	})
+}
+
+func TrackMetricClusterCertificatesDaysRemaining(ctx context.Context, clusterNamespace, clusterName string, daysRemaining int32) {
+	metricClusterCertificatesDaysRemaining.With(prometheus.Labels{
+		metricLabelClusterNamespace: clusterNamespace,
+		metricLabelClusterName:      clusterName,
+	}).Set(float64(daysRemaining))
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking cluster certificates days remaining metric",
+		metricLabelClusterNamespace, clusterNamespace,
+		metricLabelClusterName, clusterName,
+		"value", daysRemaining,
+	)
+}
+
+func DeleteMetricClusterCertificatesDaysRemaining(clusterNamespace, clusterName string) {
+	metricClusterCertificatesDaysRemaining.Delete(prometheus.Labels{
+		metricLabelClusterNamespace: clusterNamespace,
+		metricLabelClusterName:      clusterName,
+	})
+}
+
+func TrackMetricCloudQuota(ctx context.Context, credential, provider string, quota kcm.CloudQuota) {
+	labels := prometheus.Labels{
+		metricLabelCredential: credential,
+		metricLabelProvider:   provider,
+		metricLabelResource:   string(quota.Resource),
+	}
+	metricCloudQuotaLimit.With(labels).Set(float64(quota.Limit))
+	metricCloudQuotaRemaining.With(labels).Set(float64(quota.Remaining))
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking cloud quota metrics",
+		metricLabelCredential, credential,
+		metricLabelProvider, provider,
+		metricLabelResource, quota.Resource,
+		"limit", quota.Limit,
+		"remaining", quota.Remaining,
+	)
+}
+
+func DeleteMetricsCloudQuota(credential string) {
+	metricCloudQuotaLimit.DeletePartialMatch(prometheus.Labels{metricLabelCredential: credential})
+	metricCloudQuotaRemaining.DeletePartialMatch(prometheus.Labels{metricLabelCredential: credential})
+}
+
+func TrackMetricCloudAPICall(credential, provider, result string) {
+	metricCloudAPICalls.With(prometheus.Labels{
+		metricLabelCredential: credential,
+		metricLabelProvider:   provider,
+		metricLabelResult:     result,
+	}).Inc()
+}
+
+func TrackMetricCloudAPICircuitOpen(credential, provider string, open bool) {
+	var value float64
+	if open {
+		value = 1
+	}
+
+	metricCloudAPICircuitOpen.With(prometheus.Labels{
+		metricLabelCredential: credential,
+		metricLabelProvider:   provider,
+	}).Set(value)
+}
+
+func DeleteMetricsCloudAPI(credential string) {
+	metricCloudAPICalls.DeletePartialMatch(prometheus.Labels{metricLabelCredential: credential})
+	metricCloudAPICircuitOpen.DeletePartialMatch(prometheus.Labels{metricLabelCredential: credential})
+}
+
+func TrackMetricChartCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	metricChartCacheLookups.With(prometheus.Labels{
+		metricLabelCache:  cache,
+		metricLabelResult: result,
+	}).Inc()
+}
+
+func TrackMetricClusterDeploymentPhase(ctx context.Context, clusterNamespace, clusterName, phase string) {
+	for _, p := range clusterDeploymentPhases {
+		var value float64
+		if p == phase {
+			value = 1
+		}
+
+		metricClusterDeploymentPhase.With(prometheus.Labels{
+			metricLabelClusterNamespace: clusterNamespace,
+			metricLabelClusterName:      clusterName,
+			metricLabelPhase:            p,
+		}).Set(value)
+	}
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking ClusterDeployment phase metric",
+		metricLabelClusterNamespace, clusterNamespace,
+		metricLabelClusterName, clusterName,
+		metricLabelPhase, phase,
+	)
+}
+
+func TrackMetricClusterDeploymentProvisioningDuration(ctx context.Context, clusterNamespace, templateName string, duration time.Duration) {
+	metricClusterDeploymentProvisioningDuration.With(prometheus.Labels{
+		metricLabelClusterNamespace: clusterNamespace,
+		metricLabelTemplateName:     templateName,
+	}).Observe(duration.Seconds())
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking ClusterDeployment provisioning duration metric",
+		metricLabelClusterNamespace, clusterNamespace,
+		metricLabelTemplateName, templateName,
+		"value", duration.Seconds(),
+	)
+}
+
+func TrackMetricClusterDeploymentUpgradeDuration(ctx context.Context, clusterNamespace, templateName string, duration time.Duration) {
+	metricClusterDeploymentUpgradeDuration.With(prometheus.Labels{
+		metricLabelClusterNamespace: clusterNamespace,
+		metricLabelTemplateName:     templateName,
+	}).Observe(duration.Seconds())
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking ClusterDeployment upgrade duration metric",
+		metricLabelClusterNamespace, clusterNamespace,
+		metricLabelTemplateName, templateName,
+		"value", duration.Seconds(),
+	)
+}
+
+func TrackMetricClusterDeploymentHelmReleaseFailure(ctx context.Context, clusterNamespace, clusterName string) {
+	metricClusterDeploymentHelmReleaseFailures.With(prometheus.Labels{
+		metricLabelClusterNamespace: clusterNamespace,
+		metricLabelClusterName:      clusterName,
+	}).Inc()
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking ClusterDeployment HelmRelease failure metric",
+		metricLabelClusterNamespace, clusterNamespace,
+		metricLabelClusterName, clusterName,
+	)
+}
+
+func TrackMetricClusterDeploymentServicesReady(ctx context.Context, clusterNamespace, clusterName string, ready int) {
+	metricClusterDeploymentServicesReady.With(prometheus.Labels{
+		metricLabelClusterNamespace: clusterNamespace,
+		metricLabelClusterName:      clusterName,
+	}).Set(float64(ready))
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking ClusterDeployment services ready metric",
+		metricLabelClusterNamespace, clusterNamespace,
+		metricLabelClusterName, clusterName,
+		"value", ready,
+	)
+}
+
+func TrackMetricClusterDeploymentEstimatedMonthlyCost(ctx context.Context, clusterNamespace, clusterName, currency string, cost float64) {
+	// the currency of the previous estimate may differ
+	DeleteMetricClusterDeploymentEstimatedMonthlyCost(clusterNamespace, clusterName)
+	metricClusterDeploymentEstimatedMonthlyCost.With(prometheus.Labels{
+		metricLabelClusterNamespace: clusterNamespace,
+		metricLabelClusterName:      clusterName,
+		metricLabelCurrency:         currency,
+	}).Set(cost)
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking ClusterDeployment estimated monthly cost metric",
+		metricLabelClusterNamespace, clusterNamespace,
+		metricLabelClusterName, clusterName,
+		metricLabelCurrency, currency,
+		"value", cost,
+	)
+}
+
+func DeleteMetricClusterDeploymentEstimatedMonthlyCost(clusterNamespace, clusterName string) {
+	metricClusterDeploymentEstimatedMonthlyCost.DeletePartialMatch(prometheus.Labels{
+		metricLabelClusterNamespace: clusterNamespace,
+		metricLabelClusterName:      clusterName,
+	})
+}
+
+func TrackMetricClusterDeploymentReachable(ctx context.Context, clusterNamespace, clusterName string, reachable bool) {
+	var value float64
+	if reachable {
+		value = 1
+	}
+
+	metricClusterDeploymentReachable.With(prometheus.Labels{
+		metricLabelClusterNamespace: clusterNamespace,
+		metricLabelClusterName:      clusterName,
+	}).Set(value)
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking ClusterDeployment reachable metric",
+		metricLabelClusterNamespace, clusterNamespace,
+		metricLabelClusterName, clusterName,
+		"value", value,
+	)
+}
+
+func DeleteMetricClusterDeploymentReachable(clusterNamespace, clusterName string) {
+	metricClusterDeploymentReachable.Delete(prometheus.Labels{
+		metricLabelClusterNamespace: clusterNamespace,
+		metricLabelClusterName:      clusterName,
+	})
+}
+
+func TrackMetricReconcileError(ctx context.Context, controller, class string, retryable bool) {
+	labels := prometheus.Labels{
+		metricLabelController: controller,
+		metricLabelClass:      class,
+		metricLabelRetryable:  strconv.FormatBool(retryable),
+	}
+	metricReconcileErrors.With(labels).Inc()
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking reconcile error metric",
+		metricLabelController, controller,
+		metricLabelClass, class,
+		metricLabelRetryable, retryable,
+	)
+}
+
+// TrackMetricWebhookAdmission observes the duration of the admission request
+// and counts it as rejected with the given reason unless it is allowed.
+func TrackMetricWebhookAdmission(ctx context.Context, webhook, operation string, allowed bool, reason string, duration time.Duration) {
+	metricWebhookAdmissionDuration.With(prometheus.Labels{
+		metricLabelWebhook:   webhook,
+		metricLabelOperation: operation,
+		metricLabelAllowed:   strconv.FormatBool(allowed),
+	}).Observe(duration.Seconds())
+	if !allowed {
+		metricWebhookAdmissionRejections.With(prometheus.Labels{
+			metricLabelWebhook:   webhook,
+			metricLabelOperation: operation,
+			metricLabelReason:    reason,
+		}).Inc()
+	}
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking webhook admission metric",
+		metricLabelWebhook, webhook,
+		metricLabelOperation, operation,
+		metricLabelAllowed, allowed,
+		metricLabelReason, reason,
+		"duration", duration,
+	)
+}
+
+func TrackMetricManagementBackupFailed(ctx context.Context, backupName string, failed bool) {
+	var value float64
+	if failed {
+		value = 1
+	}
+
+	metricManagementBackupFailed.With(prometheus.Labels{
+		metricLabelBackupName: backupName,
+	}).Set(value)
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking ManagementBackup failed metric",
+		metricLabelBackupName, backupName,
+		"value", value,
+	)
+}
+
+// TrackMetricManagementBackupHealth tracks the last succeeded backup of the
+// ManagementBackup, if any, and the number of the backups failed since then.
+func TrackMetricManagementBackupHealth(ctx context.Context, backupName string, lastSuccess time.Time, lastSuccessItems, consecutiveFailures int32) {
+	labels := prometheus.Labels{metricLabelBackupName: backupName}
+	if !lastSuccess.IsZero() {
+		metricManagementBackupLastSuccess.With(labels).Set(float64(lastSuccess.Unix()))
+		metricManagementBackupLastSuccessItems.With(labels).Set(float64(lastSuccessItems))
+	}
+	metricManagementBackupConsecutiveFailures.With(labels).Set(float64(consecutiveFailures))
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking ManagementBackup health metrics",
+		metricLabelBackupName, backupName,
+		"last_success", lastSuccess,
+		"last_success_items", lastSuccessItems,
+		"consecutive_failures", consecutiveFailures,
+	)
+}
+
+// DeleteMetricsManagementBackup deletes all of the metrics of the ManagementBackup.
+func DeleteMetricsManagementBackup(backupName string) {
+	labels := prometheus.Labels{metricLabelBackupName: backupName}
+	metricManagementBackupFailed.Delete(labels)
+	metricManagementBackupLastSuccess.Delete(labels)
+	metricManagementBackupLastSuccessItems.Delete(labels)
+	metricManagementBackupConsecutiveFailures.Delete(labels)
+}
+
+func TrackMetricCredentialExpiration(ctx context.Context, namespace, name string, expiration time.Time) {
+	metricCredentialExpiration.With(prometheus.Labels{
+		metricLabelNamespace: namespace,
+		metricLabelName:      name,
+	}).Set(float64(expiration.Unix()))
+
+	ctrl.LoggerFrom(ctx).V(1).Info("Tracking Credential expiration metric",
+		metricLabelNamespace, namespace,
+		metricLabelName, name,
+		"value", expiration.Unix(),
+	)
+}
+
+func DeleteMetricCredentialExpiration(namespace, name string) {
+	metricCredentialExpiration.Delete(prometheus.Labels{
+		metricLabelNamespace: namespace,
+		metricLabelName:      name,
+	})
+}
+
+func DeleteMetricsClusterDeployment(clusterNamespace, clusterName string) {
+	labels := prometheus.Labels{
+		metricLabelClusterNamespace: clusterNamespace,
+		metricLabelClusterName:      clusterName,
+	}
+	metricClusterDeploymentPhase.DeletePartialMatch(labels)
+	metricClusterDeploymentHelmReleaseFailures.Delete(labels)
+	metricClusterDeploymentServicesReady.Delete(labels)
+	metricClusterDeploymentEstimatedMonthlyCost.DeletePartialMatch(labels)
+	metricClusterDeploymentReachable.Delete(labels)
+}
@@ -0,0 +1,194 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// stateMetricsTimeout limits the time the objects are listed for upon a scrape.
+const stateMetricsTimeout = 10 * time.Second
+
+const (
+	metricLabelTemplate          = "template"
+	metricLabelVersion           = "version"
+	metricLabelType              = "type"
+	metricLabelStatus            = "status"
+	metricLabelIdentityKind      = "identity_kind"
+	metricLabelIdentityNamespace = "identity_namespace"
+	metricLabelIdentityName      = "identity_name"
+	metricLabelKCMTemplate       = "kcm_template"
+	metricLabelCAPITemplate      = "capi_template"
+)
+
+// conditionStatuses are the values of the status label of the condition
+// metrics, one series is exported per each of them.
+var conditionStatuses = []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown}
+
+func newStateDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(kcm.CoreKCMName, "", name), help, labels, nil)
+}
+
+var (
+	descClusterDeploymentInfo = newStateDesc("clusterdeployment_info", "Information about the ClusterDeployment",
+		metricLabelNamespace, metricLabelName, metricLabelTemplate, metricLabelCredential)
+	descClusterDeploymentCreated = newStateDesc("clusterdeployment_created", "Unix creation timestamp of the ClusterDeployment",
+		metricLabelNamespace, metricLabelName)
+	descClusterDeploymentCondition = newStateDesc("clusterdeployment_status_condition", "The condition of the ClusterDeployment",
+		metricLabelNamespace, metricLabelName, metricLabelType, metricLabelStatus)
+	descClusterDeploymentServices = newStateDesc("clusterdeployment_spec_services", "Number of the services of the ClusterDeployment",
+		metricLabelNamespace, metricLabelName)
+
+	descCredentialInfo = newStateDesc("credential_info", "Information about the Credential",
+		metricLabelNamespace, metricLabelName, metricLabelIdentityKind, metricLabelIdentityNamespace, metricLabelIdentityName)
+	descCredentialCreated = newStateDesc("credential_created", "Unix creation timestamp of the Credential",
+		metricLabelNamespace, metricLabelName)
+	descCredentialReady = newStateDesc("credential_status_ready", "Whether the Credential is ready",
+		metricLabelNamespace, metricLabelName)
+	descCredentialCondition = newStateDesc("credential_status_condition", "The condition of the Credential",
+		metricLabelNamespace, metricLabelName, metricLabelType, metricLabelStatus)
+
+	descReleaseInfo = newStateDesc("release_info", "Information about the Release",
+		metricLabelName, metricLabelVersion, metricLabelKCMTemplate, metricLabelCAPITemplate)
+	descReleaseCreated = newStateDesc("release_created", "Unix creation timestamp of the Release",
+		metricLabelName)
+	descReleaseReady = newStateDesc("release_status_ready", "Whether the Release is ready to be upgraded to",
+		metricLabelName)
+	descReleaseCondition = newStateDesc("release_status_condition", "The condition of the Release",
+		metricLabelName, metricLabelType, metricLabelStatus)
+
+	descMultiClusterServiceInfo = newStateDesc("multiclusterservice_info", "Information about the MultiClusterService",
+		metricLabelName)
+	descMultiClusterServiceCreated = newStateDesc("multiclusterservice_created", "Unix creation timestamp of the MultiClusterService",
+		metricLabelName)
+	descMultiClusterServiceCondition = newStateDesc("multiclusterservice_status_condition", "The condition of the MultiClusterService",
+		metricLabelName, metricLabelType, metricLabelStatus)
+	descMultiClusterServiceServices = newStateDesc("multiclusterservice_spec_services", "Number of the services of the MultiClusterService",
+		metricLabelName)
+)
+
+// StateCollector exports the fields and the conditions of the
+// ClusterDeployments, Credentials, Releases and MultiClusterServices as
+// metrics in the manner of kube-state-metrics. The objects are listed upon
+// each scrape, so the metrics of the removed objects disappear with them.
+type StateCollector struct {
+	reader client.Reader
+}
+
+// NewStateCollector returns the collector listing the objects with the given
+// reader, which is expected to be backed by the cache.
+func NewStateCollector(reader client.Reader) *StateCollector {
+	return &StateCollector{reader: reader}
+}
+
+// Describe implements [prometheus.Collector].
+func (*StateCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		descClusterDeploymentInfo, descClusterDeploymentCreated, descClusterDeploymentCondition, descClusterDeploymentServices,
+		descCredentialInfo, descCredentialCreated, descCredentialReady, descCredentialCondition,
+		descReleaseInfo, descReleaseCreated, descReleaseReady, descReleaseCondition,
+		descMultiClusterServiceInfo, descMultiClusterServiceCreated, descMultiClusterServiceCondition, descMultiClusterServiceServices,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements [prometheus.Collector].
+func (c *StateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), stateMetricsTimeout)
+	defer cancel()
+
+	l := ctrl.Log.WithName("state-metrics")
+
+	clusterDeployments := new(kcm.ClusterDeploymentList)
+	if err := c.reader.List(ctx, clusterDeployments); err != nil {
+		l.Error(err, "failed to list ClusterDeployments")
+	}
+	for _, cd := range clusterDeployments.Items {
+		ch <- prometheus.MustNewConstMetric(descClusterDeploymentInfo, prometheus.GaugeValue, 1, cd.Namespace, cd.Name, cd.Spec.Template, cd.Spec.Credential)
+		ch <- createdMetric(descClusterDeploymentCreated, &cd, cd.Namespace, cd.Name)
+		ch <- prometheus.MustNewConstMetric(descClusterDeploymentServices, prometheus.GaugeValue, float64(len(cd.Spec.ServiceSpec.Services)), cd.Namespace, cd.Name)
+		collectConditions(ch, descClusterDeploymentCondition, cd.Status.Conditions, cd.Namespace, cd.Name)
+	}
+
+	credentials := new(kcm.CredentialList)
+	if err := c.reader.List(ctx, credentials); err != nil {
+		l.Error(err, "failed to list Credentials")
+	}
+	for _, cred := range credentials.Items {
+		var identityKind, identityNamespace, identityName string
+		if ref := cred.Spec.IdentityRef; ref != nil {
+			identityKind, identityNamespace, identityName = ref.Kind, ref.Namespace, ref.Name
+		}
+		ch <- prometheus.MustNewConstMetric(descCredentialInfo, prometheus.GaugeValue, 1, cred.Namespace, cred.Name, identityKind, identityNamespace, identityName)
+		ch <- createdMetric(descCredentialCreated, &cred, cred.Namespace, cred.Name)
+		ch <- prometheus.MustNewConstMetric(descCredentialReady, prometheus.GaugeValue, boolValue(cred.Status.Ready), cred.Namespace, cred.Name)
+		collectConditions(ch, descCredentialCondition, cred.Status.Conditions, cred.Namespace, cred.Name)
+	}
+
+	releases := new(kcm.ReleaseList)
+	if err := c.reader.List(ctx, releases); err != nil {
+		l.Error(err, "failed to list Releases")
+	}
+	for _, release := range releases.Items {
+		ch <- prometheus.MustNewConstMetric(descReleaseInfo, prometheus.GaugeValue, 1, release.Name, release.Spec.Version, release.Spec.KCM.Template, release.Spec.CAPI.Template)
+		ch <- createdMetric(descReleaseCreated, &release, release.Name)
+		ch <- prometheus.MustNewConstMetric(descReleaseReady, prometheus.GaugeValue, boolValue(release.Status.Ready), release.Name)
+		collectConditions(ch, descReleaseCondition, release.Status.Conditions, release.Name)
+	}
+
+	multiClusterServices := new(kcm.MultiClusterServiceList)
+	if err := c.reader.List(ctx, multiClusterServices); err != nil {
+		l.Error(err, "failed to list MultiClusterServices")
+	}
+	for _, mcs := range multiClusterServices.Items {
+		ch <- prometheus.MustNewConstMetric(descMultiClusterServiceInfo, prometheus.GaugeValue, 1, mcs.Name)
+		ch <- createdMetric(descMultiClusterServiceCreated, &mcs, mcs.Name)
+		ch <- prometheus.MustNewConstMetric(descMultiClusterServiceServices, prometheus.GaugeValue, float64(len(mcs.Spec.ServiceSpec.Services)), mcs.Name)
+		collectConditions(ch, descMultiClusterServiceCondition, mcs.Status.Conditions, mcs.Name)
+	}
+}
+
+func createdMetric(desc *prometheus.Desc, obj client.Object, labelValues ...string) prometheus.Metric {
+	return prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(obj.GetCreationTimestamp().Unix()), labelValues...)
+}
+
+// collectConditions exports a series per each of the statuses of each of the
+// given conditions, the one of the current status of the condition is 1.
+func collectConditions(ch chan<- prometheus.Metric, desc *prometheus.Desc, conditions []metav1.Condition, labelValues ...string) {
+	for _, condition := range conditions {
+		for _, status := range conditionStatuses {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, boolValue(condition.Status == status),
+				slices.Concat(labelValues, []string{condition.Type, strings.ToLower(string(status))})...)
+		}
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
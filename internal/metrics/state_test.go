@@ -0,0 +1,89 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestStateCollector(t *testing.T) {
+	g := NewWithT(t)
+
+	created := metav1.NewTime(time.Unix(1735689600, 0))
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&kcm.ClusterDeployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cd", CreationTimestamp: created},
+			Spec: kcm.ClusterDeploymentSpec{
+				Template:    "aws-0-1-0",
+				Credential:  "aws-cred",
+				ServiceSpec: kcm.ServiceSpec{Services: []kcm.Service{{Name: "ingress-nginx", Template: "ingress-nginx-4-12-1"}}},
+			},
+			Status: kcm.ClusterDeploymentStatus{Conditions: []metav1.Condition{{Type: kcm.ReadyCondition, Status: metav1.ConditionFalse}}},
+		},
+		&kcm.Credential{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "aws-cred", CreationTimestamp: created},
+			Spec:       kcm.CredentialSpec{IdentityRef: &corev1.ObjectReference{Kind: "AWSClusterStaticIdentity", Name: "aws-identity"}},
+			Status:     kcm.CredentialStatus{Ready: true},
+		},
+		&kcm.Release{
+			ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-2-0", CreationTimestamp: created},
+			Spec:       kcm.ReleaseSpec{Version: "0.2.0", KCM: kcm.CoreProviderTemplate{Template: "kcm-0-2-0"}, CAPI: kcm.CoreProviderTemplate{Template: "cluster-api-0-2-0"}},
+		},
+	).Build()
+
+	collector := NewStateCollector(cl)
+	g.Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP kcm_clusterdeployment_info Information about the ClusterDeployment
+# TYPE kcm_clusterdeployment_info gauge
+kcm_clusterdeployment_info{credential="aws-cred",name="cd",namespace="ns",template="aws-0-1-0"} 1
+# HELP kcm_clusterdeployment_created Unix creation timestamp of the ClusterDeployment
+# TYPE kcm_clusterdeployment_created gauge
+kcm_clusterdeployment_created{name="cd",namespace="ns"} 1.7356896e+09
+# HELP kcm_clusterdeployment_spec_services Number of the services of the ClusterDeployment
+# TYPE kcm_clusterdeployment_spec_services gauge
+kcm_clusterdeployment_spec_services{name="cd",namespace="ns"} 1
+# HELP kcm_clusterdeployment_status_condition The condition of the ClusterDeployment
+# TYPE kcm_clusterdeployment_status_condition gauge
+kcm_clusterdeployment_status_condition{name="cd",namespace="ns",status="false",type="Ready"} 1
+kcm_clusterdeployment_status_condition{name="cd",namespace="ns",status="true",type="Ready"} 0
+kcm_clusterdeployment_status_condition{name="cd",namespace="ns",status="unknown",type="Ready"} 0
+# HELP kcm_credential_info Information about the Credential
+# TYPE kcm_credential_info gauge
+kcm_credential_info{identity_kind="AWSClusterStaticIdentity",identity_name="aws-identity",identity_namespace="",name="aws-cred",namespace="ns"} 1
+# HELP kcm_credential_status_ready Whether the Credential is ready
+# TYPE kcm_credential_status_ready gauge
+kcm_credential_status_ready{name="aws-cred",namespace="ns"} 1
+# HELP kcm_release_info Information about the Release
+# TYPE kcm_release_info gauge
+kcm_release_info{capi_template="cluster-api-0-2-0",kcm_template="kcm-0-2-0",name="kcm-0-2-0",version="0.2.0"} 1
+# HELP kcm_release_status_ready Whether the Release is ready to be upgraded to
+# TYPE kcm_release_status_ready gauge
+kcm_release_status_ready{name="kcm-0-2-0"} 0
+`),
+		"kcm_clusterdeployment_info", "kcm_clusterdeployment_created", "kcm_clusterdeployment_spec_services", "kcm_clusterdeployment_status_condition",
+		"kcm_credential_info", "kcm_credential_status_ready", "kcm_release_info", "kcm_release_status_ready", "kcm_multiclusterservice_info",
+	)).To(Succeed())
+}
@@ -0,0 +1,294 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notifications sends the notifications about the failures and the
+// completions of the operations to the receivers configured in the Management.
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// DefaultCredentialExpiryThreshold is the period before the expiration of the
+// Credential it is reported as expiring at if not set in the Management.
+const DefaultCredentialExpiryThreshold = 7 * 24 * time.Hour
+
+// sendTimeout is the timeout of sending the notification to a receiver.
+const sendTimeout = 10 * time.Second
+
+var httpClient = &http.Client{Timeout: sendTimeout}
+
+// Notification is the notification about the event of the object.
+type Notification struct {
+	Time      time.Time             `json:"time"`
+	Event     kcm.NotificationEvent `json:"event"`
+	Kind      string                `json:"kind"`
+	Namespace string                `json:"namespace,omitempty"`
+	Name      string                `json:"name"`
+	Message   string                `json:"message"`
+}
+
+// String returns the text of the notification sent to Slack and by email.
+func (n Notification) String() string {
+	name := n.Name
+	if n.Namespace != "" {
+		name = n.Namespace + "/" + n.Name
+	}
+	return fmt.Sprintf("[%s] %s %s: %s", n.Event, n.Kind, name, n.Message)
+}
+
+// ObjectNotification returns the notification about the event of the object.
+func ObjectNotification(event kcm.NotificationEvent, kind string, obj metav1.Object, message string) Notification {
+	return Notification{Event: event, Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Message: message}
+}
+
+// Notifier sends the notifications to the receivers configured in the
+// Management. A nil Notifier sends nothing.
+type Notifier struct {
+	client.Client
+
+	// SystemNamespace is the namespace the Secrets of the receivers are read from.
+	SystemNamespace string
+
+	sendMail func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// CredentialExpiryThreshold returns the period before the expiration of the
+// Credential it is reported as expiring at.
+func CredentialExpiryThreshold(mgmt *kcm.Management) time.Duration {
+	if mgmt.Spec.Notifications == nil || mgmt.Spec.Notifications.CredentialExpiryThreshold == nil {
+		return DefaultCredentialExpiryThreshold
+	}
+	return mgmt.Spec.Notifications.CredentialExpiryThreshold.Duration
+}
+
+// Notify sends the notification to each of the receivers subscribed to its
+// event. The failures of the receivers do not prevent the notification from
+// being sent to the rest of them.
+func (n *Notifier) Notify(ctx context.Context, notification Notification) error {
+	if n == nil {
+		return nil
+	}
+
+	mgmt := new(kcm.Management)
+	if err := n.Get(ctx, client.ObjectKey{Name: kcm.ManagementName}, mgmt); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if mgmt.Spec.Notifications == nil {
+		return nil
+	}
+
+	if notification.Time.IsZero() {
+		notification.Time = time.Now()
+	}
+
+	var errs error
+	for _, receiver := range mgmt.Spec.Notifications.Receivers {
+		if len(receiver.Events) > 0 && !slices.Contains(receiver.Events, notification.Event) {
+			continue
+		}
+
+		if err := n.send(ctx, receiver, notification); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to send %s notification to the receiver %s: %w", notification.Event, receiver.Name, err))
+		}
+	}
+
+	return errs
+}
+
+func (n *Notifier) send(ctx context.Context, receiver kcm.NotificationReceiver, notification Notification) error {
+	switch {
+	case receiver.Slack != nil:
+		url, err := n.secretValue(ctx, receiver.Slack.URLSecretRef, "url")
+		if err != nil {
+			return err
+		}
+		return post(ctx, url, "", map[string]string{"text": notification.String()})
+	case receiver.Webhook != nil:
+		var authorization string
+		if receiver.Webhook.AuthorizationSecretRef != "" {
+			var err error
+			if authorization, err = n.secretValue(ctx, receiver.Webhook.AuthorizationSecretRef, "authorization"); err != nil {
+				return err
+			}
+		}
+		return post(ctx, receiver.Webhook.URL, authorization, notification)
+	case receiver.Email != nil:
+		return n.sendEmail(ctx, receiver.Email, notification)
+	default:
+		return errors.New("no receiver is specified")
+	}
+}
+
+func (n *Notifier) sendEmail(ctx context.Context, receiver *kcm.EmailReceiver, notification Notification) error {
+	var auth smtp.Auth
+	if receiver.CredentialsSecretRef != "" {
+		secret, err := n.secret(ctx, receiver.CredentialsSecretRef)
+		if err != nil {
+			return err
+		}
+
+		host, _, err := net.SplitHostPort(receiver.Address)
+		if err != nil {
+			return fmt.Errorf("failed to parse SMTP server address %s: %w", receiver.Address, err)
+		}
+		auth = smtp.PlainAuth("", string(secret.Data["username"]), string(secret.Data["password"]), host)
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: [kcm] %s %s\r\nDate: %s\r\n\r\n%s\r\n",
+		receiver.From, strings.Join(receiver.To, ", "), notification.Event, notification.Name,
+		notification.Time.Format(time.RFC1123Z), notification.String())
+
+	send := n.sendMail
+	if send == nil {
+		send = sendMail
+	}
+	if err := send(ctx, receiver.Address, auth, receiver.From, receiver.To, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email via %s: %w", receiver.Address, err)
+	}
+	return nil
+}
+
+// sendMail sends the message like smtp.SendMail does, the connection to the
+// SMTP server is bound to the deadline of the context, the sendTimeout if
+// the deadline is later or not set.
+func sendMail(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	for _, line := range append([]string{from}, to...) {
+		if strings.ContainsAny(line, "\r\n") {
+			return errors.New("smtp: a line must not contain CR or LF")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	conn, err := new(net.Dialer).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(a); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (n *Notifier) secret(ctx context.Context, name string) (*corev1.Secret, error) {
+	secret := new(corev1.Secret)
+	if err := n.Get(ctx, client.ObjectKey{Namespace: n.SystemNamespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get Secret %s/%s: %w", n.SystemNamespace, name, err)
+	}
+	return secret, nil
+}
+
+func (n *Notifier) secretValue(ctx context.Context, name, key string) (string, error) {
+	secret, err := n.secret(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s has no %s key", n.SystemNamespace, name, key)
+	}
+	return string(value), nil
+}
+
+func post(ctx context.Context, url, authorization string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to post notification: unexpected status %s", resp.Status)
+	}
+	return nil
+}
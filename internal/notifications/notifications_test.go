@@ -0,0 +1,164 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+func TestNotifier_Notify(t *testing.T) {
+	g := NewWithT(t)
+
+	const systemNamespace = "kcm-system"
+
+	type request struct {
+		body          map[string]any
+		path          string
+		authorization string
+	}
+	requests := make(chan request, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := request{path: r.URL.Path, authorization: r.Header.Get("Authorization")}
+		_ = json.Unmarshal(body, &req.body)
+		requests <- req
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	var mails []string
+	mgmt := &kcm.Management{
+		ObjectMeta: metav1.ObjectMeta{Name: kcm.ManagementName},
+		Spec: kcm.ManagementSpec{Notifications: &kcm.Notifications{Receivers: []kcm.NotificationReceiver{
+			{Name: "slack", Slack: &kcm.SlackReceiver{URLSecretRef: "slack"}},
+			{Name: "webhook", Webhook: &kcm.WebhookReceiver{URL: server.URL + "/hook", AuthorizationSecretRef: "webhook"}},
+			{
+				Name:   "email",
+				Events: []kcm.NotificationEvent{kcm.NotificationEventBackupFailed},
+				Email:  &kcm.EmailReceiver{Address: "smtp.example.com:587", From: "kcm@example.com", To: []string{"ops@example.com"}},
+			},
+			{
+				Name:    "broken",
+				Events:  []kcm.NotificationEvent{kcm.NotificationEventUpgradeSucceeded},
+				Webhook: &kcm.WebhookReceiver{URL: server.URL + "/broken"},
+			},
+		}}},
+	}
+	secrets := []*corev1.Secret{
+		{ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: systemNamespace}, Data: map[string][]byte{"url": []byte(server.URL + "/slack")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "webhook", Namespace: systemNamespace}, Data: map[string][]byte{"authorization": []byte("Bearer token")}},
+	}
+
+	n := &Notifier{
+		Client:          fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(mgmt, secrets[0], secrets[1]).Build(),
+		SystemNamespace: systemNamespace,
+		sendMail: func(_ context.Context, addr string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+			g.Expect(addr).To(Equal("smtp.example.com:587"))
+			g.Expect(to).To(Equal([]string{"ops@example.com"}))
+			mails = append(mails, string(msg))
+			return nil
+		},
+	}
+
+	notification := Notification{Event: kcm.NotificationEventClusterDeploymentFailed, Kind: kcm.ClusterDeploymentKind, Namespace: "default", Name: "cluster", Message: "install failed"}
+	g.Expect(n.Notify(t.Context(), notification)).To(Succeed())
+
+	slack, webhook := <-requests, <-requests
+	g.Expect(slack.path).To(Equal("/slack"))
+	g.Expect(slack.body).To(HaveKeyWithValue("text", "[ClusterDeploymentFailed] ClusterDeployment default/cluster: install failed"))
+	g.Expect(webhook.path).To(Equal("/hook"))
+	g.Expect(webhook.authorization).To(Equal("Bearer token"))
+	g.Expect(webhook.body).To(HaveKeyWithValue("event", "ClusterDeploymentFailed"))
+	g.Expect(webhook.body).To(HaveKeyWithValue("name", "cluster"))
+	g.Expect(mails).To(BeEmpty())
+
+	// the receivers subscribed to the event only are notified
+	notification.Event = kcm.NotificationEventBackupFailed
+	g.Expect(n.Notify(t.Context(), notification)).To(Succeed())
+	<-requests
+	<-requests
+	g.Expect(mails).To(HaveLen(1))
+	g.Expect(mails[0]).To(ContainSubstring("Subject: [kcm] BackupFailed cluster"))
+
+	// the failure of the receiver is reported after notifying the rest of them
+	notification.Event = kcm.NotificationEventUpgradeSucceeded
+	g.Expect(n.Notify(t.Context(), notification)).To(MatchError(ContainSubstring("receiver broken")))
+	g.Expect(requests).To(HaveLen(3))
+
+	// nothing is sent without the configured notifications
+	var nilNotifier *Notifier
+	g.Expect(nilNotifier.Notify(t.Context(), notification)).To(Succeed())
+}
+
+func Test_sendMail(t *testing.T) {
+	g := NewWithT(t)
+
+	// the server accepts the connections and never greets the clients
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() { _ = lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(io.Discard, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = sendMail(ctx, lis.Addr().String(), nil, "kcm@example.com", []string{"ops@example.com"}, []byte("test"))
+	g.Expect(err).To(MatchError(os.ErrDeadlineExceeded))
+	g.Expect(time.Since(start)).To(BeNumerically("<", sendTimeout))
+
+	g.Expect(sendMail(t.Context(), lis.Addr().String(), nil, "kcm@example.com", []string{"ops@example.com\r\nBcc: all@example.com"}, nil)).
+		To(MatchError(ContainSubstring("must not contain CR or LF")))
+}
+
+func TestCredentialExpiryThreshold(t *testing.T) {
+	g := NewWithT(t)
+
+	mgmt := new(kcm.Management)
+	g.Expect(CredentialExpiryThreshold(mgmt)).To(Equal(DefaultCredentialExpiryThreshold))
+
+	mgmt.Spec.Notifications = &kcm.Notifications{CredentialExpiryThreshold: &metav1.Duration{Duration: 0}}
+	g.Expect(CredentialExpiryThreshold(mgmt)).To(BeZero())
+}
@@ -0,0 +1,255 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package preflight validates the management cluster is ready for the
+// upgrade of the Management to another Release.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// checkedCRDGroups are the API groups of the CRDs installed by the Management components.
+var checkedCRDGroups = []string{kcm.GroupVersion.Group, clusterapiv1beta1.GroupVersion.Group}
+
+// pendingClusterPhases are the phases of the CAPI Clusters being provisioned or deleted.
+var pendingClusterPhases = []clusterapiv1beta1.ClusterPhase{
+	clusterapiv1beta1.ClusterPhasePending,
+	clusterapiv1beta1.ClusterPhaseProvisioning,
+	clusterapiv1beta1.ClusterPhaseDeleting,
+}
+
+// Checker runs the preflight checks.
+type Checker struct {
+	Client client.Reader
+
+	// StorageHealth returns an error if the storage of the management
+	// cluster is not healthy. The check is skipped if not set.
+	StorageHealth func(ctx context.Context) error
+	// ReleaseCRDVersions returns the versions served by the CRDs of the given
+	// Release by the CRD names. The storage of the downgrades is not checked
+	// if not set.
+	ReleaseCRDVersions func(ctx context.Context, release *kcm.Release) (map[string][]string, error)
+}
+
+// Run runs all of the checks of the upgrade of the Management to the given
+// Release and returns the failures found. The returned error is set only if
+// the checks could not be performed.
+func (c *Checker) Run(ctx context.Context, mgmt *kcm.Management, release *kcm.Release) ([]string, error) {
+	checks := []func(context.Context, *kcm.Management, *kcm.Release) ([]string, error){
+		c.checkCRDs,
+		c.checkProviderContracts,
+		c.checkPendingClusterOperations,
+		c.checkStorage,
+		c.checkDowngradeStorage,
+	}
+
+	var failures []string
+	for _, check := range checks {
+		f, err := check(ctx, mgmt, release)
+		if err != nil {
+			return nil, err
+		}
+		failures = append(failures, f...)
+	}
+
+	return failures, nil
+}
+
+// checkCRDs reports the CRDs with objects stored in several versions, the
+// upgrade may drop one of them before the objects are migrated.
+func (c *Checker) checkCRDs(ctx context.Context, _ *kcm.Management, _ *kcm.Release) ([]string, error) {
+	crds := new(apiextensionsv1.CustomResourceDefinitionList)
+	if err := c.Client.List(ctx, crds); err != nil {
+		return nil, fmt.Errorf("failed to list CustomResourceDefinitions: %w", err)
+	}
+
+	var failures []string
+	for _, crd := range crds.Items {
+		if !slices.ContainsFunc(checkedCRDGroups, func(group string) bool {
+			return crd.Spec.Group == group || strings.HasSuffix(crd.Spec.Group, "."+group)
+		}) {
+			continue
+		}
+
+		if len(crd.Status.StoredVersions) > 1 {
+			failures = append(failures, fmt.Sprintf("CRD %s has objects stored in several versions %s, the storage migration is pending",
+				crd.Name, strings.Join(crd.Status.StoredVersions, ", ")))
+		}
+	}
+
+	return failures, nil
+}
+
+// checkProviderContracts reports the ProviderTemplates of the Release which
+// are not valid or expose the contracts unsupported by its core CAPI template.
+func (c *Checker) checkProviderContracts(ctx context.Context, mgmt *kcm.Management, release *kcm.Release) ([]string, error) {
+	capiTplName := release.Spec.CAPI.Template
+	if mgmt.Spec.Core != nil && mgmt.Spec.Core.CAPI.Template != "" {
+		capiTplName = mgmt.Spec.Core.CAPI.Template
+	}
+
+	capiTpl := new(kcm.ProviderTemplate)
+	if err := c.Client.Get(ctx, client.ObjectKey{Name: capiTplName}, capiTpl); err != nil {
+		return []string{fmt.Sprintf("failed to get ProviderTemplate %s: %s", capiTplName, err)}, nil
+	}
+	if !capiTpl.Status.Valid {
+		return []string{fmt.Sprintf("ProviderTemplate %s is not valid", capiTplName)}, nil
+	}
+
+	var failures []string
+	for _, p := range mgmt.Spec.Providers {
+		if p.Disabled {
+			continue
+		}
+
+		tplName := p.Template
+		if tplName == "" {
+			tplName = release.ProviderTemplate(p.Name)
+		}
+		if tplName == "" || tplName == capiTplName {
+			continue
+		}
+
+		tpl := new(kcm.ProviderTemplate)
+		if err := c.Client.Get(ctx, client.ObjectKey{Name: tplName}, tpl); err != nil {
+			failures = append(failures, fmt.Sprintf("failed to get ProviderTemplate %s: %s", tplName, err))
+			continue
+		}
+		if !tpl.Status.Valid {
+			failures = append(failures, fmt.Sprintf("ProviderTemplate %s is not valid", tplName))
+			continue
+		}
+
+		if len(capiTpl.Status.CAPIContracts) == 0 {
+			continue
+		}
+		for capiVersion := range tpl.Status.CAPIContracts {
+			if _, ok := capiTpl.Status.CAPIContracts[capiVersion]; !ok {
+				failures = append(failures, fmt.Sprintf("ProviderTemplate %s requires the %s CAPI contract version unsupported by the ProviderTemplate %s", tplName, capiVersion, capiTplName))
+			}
+		}
+	}
+
+	slices.Sort(failures)
+	return failures, nil
+}
+
+// checkPendingClusterOperations reports the clusters being provisioned or deleted.
+func (c *Checker) checkPendingClusterOperations(ctx context.Context, _ *kcm.Management, _ *kcm.Release) ([]string, error) {
+	clusters := new(unstructured.UnstructuredList)
+	clusters.SetGroupVersionKind(clusterapiv1beta1.GroupVersion.WithKind("ClusterList"))
+	if err := c.Client.List(ctx, clusters); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", clusterapiv1beta1.GroupVersion.WithKind("Cluster"), err)
+	}
+
+	var pending []string
+	for _, cluster := range clusters.Items {
+		phase, _, _ := unstructured.NestedString(cluster.Object, "status", "phase")
+		if slices.Contains(pendingClusterPhases, clusterapiv1beta1.ClusterPhase(phase)) {
+			pending = append(pending, fmt.Sprintf("%s (%s)", client.ObjectKeyFromObject(&cluster), phase))
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	slices.Sort(pending)
+	return []string{"clusters have pending operations: " + strings.Join(pending, ", ")}, nil
+}
+
+func (c *Checker) checkStorage(ctx context.Context, _ *kcm.Management, _ *kcm.Release) ([]string, error) {
+	if c.StorageHealth == nil {
+		return nil, nil
+	}
+	if err := c.StorageHealth(ctx); err != nil {
+		return []string{"storage is not healthy: " + err.Error()}, nil
+	}
+	return nil, nil
+}
+
+// checkDowngradeStorage reports the CRDs with objects stored in the versions
+// not served by the Release the Management is downgraded to.
+func (c *Checker) checkDowngradeStorage(ctx context.Context, mgmt *kcm.Management, release *kcm.Release) ([]string, error) {
+	if c.ReleaseCRDVersions == nil || mgmt.Status.Release == "" || mgmt.Status.Release == release.Name {
+		return nil, nil
+	}
+
+	currentRelease := new(kcm.Release)
+	if err := c.Client.Get(ctx, client.ObjectKey{Name: mgmt.Status.Release}, currentRelease); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get Release %s: %w", mgmt.Status.Release, err)
+	}
+	if !release.IsDowngradeFrom(currentRelease) {
+		return nil, nil
+	}
+
+	servedVersions, err := c.ReleaseCRDVersions(ctx, release)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to get the CRDs of the Release %s: %s", release.Name, err)}, nil
+	}
+
+	crds := new(apiextensionsv1.CustomResourceDefinitionList)
+	if err := c.Client.List(ctx, crds); err != nil {
+		return nil, fmt.Errorf("failed to list CustomResourceDefinitions: %w", err)
+	}
+
+	var failures []string
+	for _, crd := range crds.Items {
+		versions, ok := servedVersions[crd.Name]
+		if !ok {
+			continue
+		}
+
+		for _, v := range crd.Status.StoredVersions {
+			if !slices.Contains(versions, v) {
+				failures = append(failures, fmt.Sprintf("CRD %s has objects stored in the version %s not served by the Release %s", crd.Name, v, release.Name))
+			}
+		}
+	}
+
+	slices.Sort(failures)
+	return failures, nil
+}
+
+// EtcdHealth returns the StorageHealth function checking the etcd readiness
+// reported by the API server of the given config.
+func EtcdHealth(config *rest.Config) (func(ctx context.Context) error, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+
+	return func(ctx context.Context) error {
+		if _, err := clientset.Discovery().RESTClient().Get().AbsPath("/readyz/etcd").DoRaw(ctx); err != nil {
+			return fmt.Errorf("etcd is not ready: %w", err)
+		}
+		return nil
+	}, nil
+}
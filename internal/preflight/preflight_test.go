@@ -0,0 +1,194 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+func TestChecker_Run(t *testing.T) {
+	s := runtime.NewScheme()
+	utilruntime.Must(kcm.AddToScheme(s))
+	utilruntime.Must(apiextensionsv1.AddToScheme(s))
+	utilruntime.Must(clusterapiv1beta1.AddToScheme(s))
+
+	const (
+		capiTplName = "cluster-api-0-1-0"
+		awsTplName  = "cluster-api-provider-aws-0-1-0"
+	)
+
+	release := &kcm.Release{
+		ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-1-0"},
+		Spec: kcm.ReleaseSpec{
+			CAPI:      kcm.CoreProviderTemplate{Template: capiTplName},
+			Providers: []kcm.NamedProviderTemplate{{Name: "cluster-api-provider-aws", CoreProviderTemplate: kcm.CoreProviderTemplate{Template: awsTplName}}},
+		},
+	}
+	mgmt := &kcm.Management{
+		ObjectMeta: metav1.ObjectMeta{Name: kcm.ManagementName},
+		Spec: kcm.ManagementSpec{
+			Release:   release.Name,
+			Providers: []kcm.Provider{{Name: "cluster-api-provider-aws"}},
+		},
+	}
+
+	providerTemplate := func(name string, valid bool, contracts ...string) *kcm.ProviderTemplate {
+		tpl := &kcm.ProviderTemplate{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		tpl.Status.Valid = valid
+		if len(contracts) > 0 {
+			tpl.Status.CAPIContracts = make(kcm.CompatibilityContracts)
+			for _, c := range contracts {
+				tpl.Status.CAPIContracts[c] = ""
+			}
+		}
+		return tpl
+	}
+	crd := func(name, group string, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: group},
+			Status:     apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+		}
+	}
+	cluster := func(name string, phase clusterapiv1beta1.ClusterPhase) *clusterapiv1beta1.Cluster {
+		return &clusterapiv1beta1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Status:     clusterapiv1beta1.ClusterStatus{Phase: string(phase)},
+		}
+	}
+
+	tests := []struct {
+		storageHealth func(context.Context) error
+		name          string
+		objects       []client.Object
+		wantFailures  []string
+	}{
+		{
+			name: "all checks passed",
+			objects: []client.Object{
+				providerTemplate(capiTplName, true, "v1beta1"),
+				providerTemplate(awsTplName, true, "v1beta1"),
+				crd("clusters.cluster.x-k8s.io", "cluster.x-k8s.io", "v1beta1"),
+				crd("widgets.example.com", "example.com", "v1alpha1", "v1"),
+				cluster("provisioned", clusterapiv1beta1.ClusterPhaseProvisioned),
+			},
+			storageHealth: func(context.Context) error { return nil },
+		},
+		{
+			name: "all checks failed",
+			objects: []client.Object{
+				providerTemplate(capiTplName, true, "v1beta1"),
+				providerTemplate(awsTplName, true, "v1beta2"),
+				crd("awsclusters.infrastructure.cluster.x-k8s.io", "infrastructure.cluster.x-k8s.io", "v1beta1", "v1beta2"),
+				cluster("provisioning", clusterapiv1beta1.ClusterPhaseProvisioning),
+				cluster("deleting", clusterapiv1beta1.ClusterPhaseDeleting),
+			},
+			storageHealth: func(context.Context) error { return errors.New("etcd is not ready") },
+			wantFailures: []string{
+				"CRD awsclusters.infrastructure.cluster.x-k8s.io has objects stored in several versions v1beta1, v1beta2, the storage migration is pending",
+				"ProviderTemplate " + awsTplName + " requires the v1beta2 CAPI contract version unsupported by the ProviderTemplate " + capiTplName,
+				"clusters have pending operations: test/deleting (Deleting), test/provisioning (Provisioning)",
+				"storage is not healthy: etcd is not ready",
+			},
+		},
+		{
+			name: "provider template is not valid",
+			objects: []client.Object{
+				providerTemplate(capiTplName, true),
+				providerTemplate(awsTplName, false),
+			},
+			wantFailures: []string{"ProviderTemplate " + awsTplName + " is not valid"},
+		},
+		{
+			name:         "core capi template does not exist",
+			wantFailures: []string{`failed to get ProviderTemplate ` + capiTplName + `: providertemplates.k0rdent.mirantis.com "` + capiTplName + `" not found`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			checker := &Checker{
+				Client:        fake.NewClientBuilder().WithScheme(s).WithObjects(tt.objects...).Build(),
+				StorageHealth: tt.storageHealth,
+			}
+
+			failures, err := checker.Run(t.Context(), mgmt, release)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(failures).To(Equal(tt.wantFailures))
+		})
+	}
+}
+
+func TestChecker_checkDowngradeStorage(t *testing.T) {
+	g := NewWithT(t)
+
+	s := runtime.NewScheme()
+	utilruntime.Must(kcm.AddToScheme(s))
+	utilruntime.Must(apiextensionsv1.AddToScheme(s))
+
+	currentRelease := &kcm.Release{ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-2-0"}, Spec: kcm.ReleaseSpec{Version: "0.2.0"}}
+	release := &kcm.Release{ObjectMeta: metav1.ObjectMeta{Name: "kcm-0-1-0"}, Spec: kcm.ReleaseSpec{Version: "0.1.0"}}
+
+	mgmt := &kcm.Management{ObjectMeta: metav1.ObjectMeta{Name: kcm.ManagementName}}
+	mgmt.Status.Release = currentRelease.Name
+
+	checker := &Checker{
+		Client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+			currentRelease,
+			release,
+			&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "widgets.k0rdent.mirantis.com"},
+				Status:     apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1beta1"}},
+			},
+			&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "gadgets.k0rdent.mirantis.com"},
+				Status:     apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1alpha1"}},
+			},
+		).Build(),
+		ReleaseCRDVersions: func(_ context.Context, r *kcm.Release) (map[string][]string, error) {
+			g.Expect(r.Name).To(Equal(release.Name))
+			return map[string][]string{
+				"widgets.k0rdent.mirantis.com": {"v1alpha1"},
+				"gadgets.k0rdent.mirantis.com": {"v1alpha1"},
+			}, nil
+		},
+	}
+
+	failures, err := checker.checkDowngradeStorage(t.Context(), mgmt, release)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(failures).To(Equal([]string{
+		"CRD widgets.k0rdent.mirantis.com has objects stored in the version v1beta1 not served by the Release kcm-0-1-0",
+	}))
+
+	// upgrades are not checked
+	failures, err = checker.checkDowngradeStorage(t.Context(), &kcm.Management{Status: kcm.ManagementStatus{Release: release.Name}}, currentRelease)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(failures).To(BeEmpty())
+}
@@ -0,0 +1,88 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// CloudCollector collects the quotas of the cloud account the identity of
+// a Credential gives access to.
+type CloudCollector interface {
+	// Provider returns the name of the cloud provider.
+	Provider() string
+	// Supports reports whether the quotas can be collected with the given identity.
+	Supports(identity *unstructured.Unstructured) bool
+	// Collect returns the quotas of the cloud resources.
+	Collect(ctx context.Context, cl client.Reader, identity *unstructured.Unstructured) ([]kcm.CloudQuota, error)
+}
+
+// CloudCollectors are the collectors of the supported cloud providers.
+var CloudCollectors = []CloudCollector{
+	&OpenStackCollector{},
+}
+
+// CloudCollectorFor returns the collector supporting the given identity, nil if none.
+func CloudCollectorFor(collectors []CloudCollector, identity *unstructured.Unstructured) CloudCollector {
+	for _, c := range collectors {
+		if c.Supports(identity) {
+			return c
+		}
+	}
+	return nil
+}
+
+// NewCloudQuota returns the quota of the resource with the given limit and usage,
+// the negative limit stands for the unlimited resource.
+func NewCloudQuota(resource kcm.CloudQuotaResource, limit, used int64) kcm.CloudQuota {
+	if limit < 0 {
+		return kcm.CloudQuota{Resource: resource, Limit: -1, Used: used, Remaining: -1}
+	}
+	return kcm.CloudQuota{Resource: resource, Limit: limit, Used: used, Remaining: max(limit-used, 0)}
+}
+
+// CloudQuotaWarnings returns the warnings about the cloud quotas the
+// ClusterDeployment of the given usage is likely to exceed. Each of the
+// nodes is expected to take an instance, and each of the clusters a load
+// balancer and a public IP of the API server. The vCPUs quota is not checked
+// since the vCPUs of the instance types are not known.
+func CloudQuotaWarnings(credential string, usage Usage, quotas []kcm.CloudQuota) []string {
+	var warnings []string
+	for _, q := range quotas {
+		if q.Remaining < 0 {
+			continue
+		}
+
+		switch q.Resource {
+		case kcm.CloudQuotaInstances:
+			if int64(usage.Nodes) > q.Remaining {
+				warnings = append(warnings, fmt.Sprintf("the cluster of %d nodes likely exceeds the %s quota of the Credential %s: %d of %d left",
+					usage.Nodes, q.Resource, credential, q.Remaining, q.Limit))
+			}
+		case kcm.CloudQuotaPublicIPs, kcm.CloudQuotaLoadBalancers:
+			if q.Remaining == 0 {
+				warnings = append(warnings, fmt.Sprintf("the cluster likely exceeds the %s quota of the Credential %s: 0 of %d left",
+					q.Resource, credential, q.Limit))
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,73 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+func TestNewCloudQuota(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(NewCloudQuota(kcm.CloudQuotaVCPUs, 20, 8)).To(Equal(kcm.CloudQuota{Resource: kcm.CloudQuotaVCPUs, Limit: 20, Used: 8, Remaining: 12}))
+	g.Expect(NewCloudQuota(kcm.CloudQuotaVCPUs, 4, 8)).To(Equal(kcm.CloudQuota{Resource: kcm.CloudQuotaVCPUs, Limit: 4, Used: 8, Remaining: 0}))
+	g.Expect(NewCloudQuota(kcm.CloudQuotaVCPUs, -1, 8)).To(Equal(kcm.CloudQuota{Resource: kcm.CloudQuotaVCPUs, Limit: -1, Used: 8, Remaining: -1}))
+}
+
+func TestCloudQuotaWarnings(t *testing.T) {
+	const credential = "kcm-system/openstack-cred"
+
+	tests := []struct {
+		name   string
+		usage  Usage
+		quotas []kcm.CloudQuota
+		want   []string
+	}{
+		{
+			name:   "enough headroom",
+			usage:  Usage{Nodes: 3},
+			quotas: []kcm.CloudQuota{NewCloudQuota(kcm.CloudQuotaInstances, 10, 2), NewCloudQuota(kcm.CloudQuotaLoadBalancers, 2, 1)},
+		},
+		{
+			name:   "unlimited resources",
+			usage:  Usage{Nodes: 30},
+			quotas: []kcm.CloudQuota{NewCloudQuota(kcm.CloudQuotaVCPUs, -1, 200), NewCloudQuota(kcm.CloudQuotaPublicIPs, -1, 5)},
+		},
+		{
+			name:   "exhausted quotas",
+			usage:  Usage{Nodes: 5},
+			quotas: []kcm.CloudQuota{NewCloudQuota(kcm.CloudQuotaInstances, 10, 7), NewCloudQuota(kcm.CloudQuotaPublicIPs, 4, 4)},
+			want: []string{
+				"the cluster of 5 nodes likely exceeds the instances quota of the Credential kcm-system/openstack-cred: 3 of 10 left",
+				"the cluster likely exceeds the publicIPs quota of the Credential kcm-system/openstack-cred: 0 of 4 left",
+			},
+		},
+		{
+			name:   "vcpus are not compared with nodes",
+			usage:  Usage{Nodes: 5},
+			quotas: []kcm.CloudQuota{NewCloudQuota(kcm.CloudQuotaInstances, 10, 2), NewCloudQuota(kcm.CloudQuotaVCPUs, 10, 7)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			NewWithT(t).Expect(CloudQuotaWarnings(credential, tt.usage, tt.quotas)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,381 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+const (
+	openStackCloudsKey = "clouds.yaml"
+	openStackCACertKey = "cacert"
+	// openStackDefaultCloud is the name of the cloud used if clouds.yaml
+	// defines several of them.
+	openStackDefaultCloud = "openstack"
+)
+
+// OpenStackCollector collects the compute, floating IP and load balancer
+// quotas of the project the clouds.yaml of the Secret identity gives access to.
+type OpenStackCollector struct {
+	// HTTPClient is the client the OpenStack APIs are queried with,
+	// the client with the CA certificate of the Secret is used if not set.
+	HTTPClient *http.Client
+}
+
+type openStackCloud struct {
+	Auth struct {
+		AuthURL                     string `json:"auth_url"`
+		Username                    string `json:"username"`
+		Password                    string `json:"password"`
+		UserDomainName              string `json:"user_domain_name"`
+		ProjectID                   string `json:"project_id"`
+		ProjectName                 string `json:"project_name"`
+		ProjectDomainName           string `json:"project_domain_name"`
+		ApplicationCredentialID     string `json:"application_credential_id"`
+		ApplicationCredentialSecret string `json:"application_credential_secret"`
+	} `json:"auth"`
+	RegionName string `json:"region_name"`
+	Interface  string `json:"interface"`
+	Verify     *bool  `json:"verify"`
+}
+
+type openStackCatalogEntry struct {
+	Type      string              `json:"type"`
+	Endpoints []openStackEndpoint `json:"endpoints"`
+}
+
+type openStackEndpoint struct {
+	Interface string `json:"interface"`
+	Region    string `json:"region"`
+	URL       string `json:"url"`
+}
+
+type openStackSession struct {
+	client    *http.Client
+	token     string
+	projectID string
+	endpoints map[string]string
+}
+
+func (*OpenStackCollector) Provider() string { return "openstack" }
+
+func (*OpenStackCollector) Supports(identity *unstructured.Unstructured) bool {
+	if identity.GetAPIVersion() != "v1" || identity.GetKind() != "Secret" {
+		return false
+	}
+	_, ok, _ := unstructured.NestedString(identity.Object, "data", openStackCloudsKey)
+	return ok
+}
+
+func (c *OpenStackCollector) Collect(ctx context.Context, _ client.Reader, identity *unstructured.Unstructured) ([]kcm.CloudQuota, error) {
+	secret := new(corev1.Secret)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(identity.Object, secret); err != nil {
+		return nil, fmt.Errorf("failed to convert Secret %s: %w", client.ObjectKeyFromObject(identity), err)
+	}
+
+	cloud, err := openStackCloudFromClouds(secret.Data[openStackCloudsKey])
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := c.httpClient(cloud, secret.Data[openStackCACertKey])
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := authenticateOpenStack(ctx, httpClient, cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas, err := session.computeQuotas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := session.endpoints["network"]; ok {
+		q, err := session.floatingIPQuota(ctx)
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, q)
+	}
+
+	if _, ok := session.endpoints["load-balancer"]; ok {
+		q, err := session.loadBalancerQuota(ctx)
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, q)
+	}
+
+	return quotas, nil
+}
+
+func (c *OpenStackCollector) httpClient(cloud *openStackCloud, caCert []byte) (*http.Client, error) {
+	if c.HTTPClient != nil {
+		return c.HTTPClient, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cloud.Verify != nil && !*cloud.Verify {
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // explicitly requested by the clouds.yaml
+	}
+	if len(caCert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("failed to parse the OpenStack CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+	}, nil
+}
+
+func openStackCloudFromClouds(data []byte) (*openStackCloud, error) {
+	var clouds struct {
+		Clouds map[string]*openStackCloud `json:"clouds"`
+	}
+	if err := yaml.Unmarshal(data, &clouds); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", openStackCloudsKey, err)
+	}
+
+	cloud := clouds.Clouds[openStackDefaultCloud]
+	if cloud == nil && len(clouds.Clouds) == 1 {
+		for _, c := range clouds.Clouds {
+			cloud = c
+		}
+	}
+	if cloud == nil {
+		return nil, fmt.Errorf("%s has no %s cloud", openStackCloudsKey, openStackDefaultCloud)
+	}
+	if cloud.Auth.AuthURL == "" {
+		return nil, fmt.Errorf("%s has no auth_url", openStackCloudsKey)
+	}
+
+	return cloud, nil
+}
+
+// authenticateOpenStack issues the Keystone v3 token of the given cloud.
+func authenticateOpenStack(ctx context.Context, httpClient *http.Client, cloud *openStackCloud) (*openStackSession, error) {
+	type domain struct {
+		Name string `json:"name,omitempty"`
+	}
+
+	identity := map[string]any{}
+	auth := map[string]any{"identity": identity}
+	if cloud.Auth.ApplicationCredentialID != "" {
+		identity["methods"] = []string{"application_credential"}
+		identity["application_credential"] = map[string]string{
+			"id":     cloud.Auth.ApplicationCredentialID,
+			"secret": cloud.Auth.ApplicationCredentialSecret,
+		}
+	} else {
+		identity["methods"] = []string{"password"}
+		identity["password"] = map[string]any{"user": map[string]any{
+			"name":     cloud.Auth.Username,
+			"password": cloud.Auth.Password,
+			"domain":   domain{Name: cloud.Auth.UserDomainName},
+		}}
+
+		project := map[string]any{}
+		if cloud.Auth.ProjectID != "" {
+			project["id"] = cloud.Auth.ProjectID
+		} else {
+			project["name"] = cloud.Auth.ProjectName
+			project["domain"] = domain{Name: cloud.Auth.ProjectDomainName}
+		}
+		auth["scope"] = map[string]any{"project": project}
+	}
+
+	body, err := json.Marshal(map[string]any{"auth": auth})
+	if err != nil {
+		return nil, err
+	}
+
+	authURL := strings.TrimSuffix(cloud.Auth.AuthURL, "/")
+	if !strings.HasSuffix(authURL, "/v3") {
+		authURL += "/v3"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authURL+"/auth/tokens", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate to OpenStack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to authenticate to OpenStack: unexpected status %s", resp.Status)
+	}
+
+	var token struct {
+		Token struct {
+			Project struct {
+				ID string `json:"id"`
+			} `json:"project"`
+			Catalog []openStackCatalogEntry `json:"catalog"`
+		} `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode the OpenStack token: %w", err)
+	}
+
+	iface := cloud.Interface
+	if iface == "" {
+		iface = "public"
+	}
+
+	session := &openStackSession{
+		client:    httpClient,
+		token:     resp.Header.Get("X-Subject-Token"),
+		projectID: token.Token.Project.ID,
+		endpoints: make(map[string]string),
+	}
+	for _, entry := range token.Token.Catalog {
+		idx := slices.IndexFunc(entry.Endpoints, func(e openStackEndpoint) bool {
+			return e.Interface == iface && (cloud.RegionName == "" || e.Region == cloud.RegionName)
+		})
+		if idx < 0 {
+			continue
+		}
+		endpoint := strings.TrimSuffix(entry.Endpoints[idx].URL, "/")
+		if entry.Type == "network" {
+			// the network endpoint may be registered with or without the API version
+			endpoint = strings.TrimSuffix(endpoint, "/v2.0")
+		}
+		session.endpoints[entry.Type] = endpoint
+	}
+
+	return session, nil
+}
+
+func (s *openStackSession) get(ctx context.Context, service, path string, into any) error {
+	endpoint, ok := s.endpoints[service]
+	if !ok {
+		return fmt.Errorf("OpenStack %s endpoint is not found in the catalog", service)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Auth-Token", s.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query OpenStack %s API: %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to query OpenStack %s API: unexpected status %s: %s", service, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(into); err != nil {
+		return fmt.Errorf("failed to decode the OpenStack %s API response: %w", service, err)
+	}
+	return nil
+}
+
+func (s *openStackSession) computeQuotas(ctx context.Context) ([]kcm.CloudQuota, error) {
+	var limits struct {
+		Limits struct {
+			Absolute struct {
+				MaxTotalInstances  int64 `json:"maxTotalInstances"`
+				TotalInstancesUsed int64 `json:"totalInstancesUsed"`
+				MaxTotalCores      int64 `json:"maxTotalCores"`
+				TotalCoresUsed     int64 `json:"totalCoresUsed"`
+			} `json:"absolute"`
+		} `json:"limits"`
+	}
+	if err := s.get(ctx, "compute", "/limits", &limits); err != nil {
+		return nil, err
+	}
+
+	abs := limits.Limits.Absolute
+	return []kcm.CloudQuota{
+		NewCloudQuota(kcm.CloudQuotaInstances, abs.MaxTotalInstances, abs.TotalInstancesUsed),
+		NewCloudQuota(kcm.CloudQuotaVCPUs, abs.MaxTotalCores, abs.TotalCoresUsed),
+	}, nil
+}
+
+func (s *openStackSession) floatingIPQuota(ctx context.Context) (kcm.CloudQuota, error) {
+	var details struct {
+		Quota struct {
+			FloatingIP struct {
+				Limit    int64 `json:"limit"`
+				Used     int64 `json:"used"`
+				Reserved int64 `json:"reserved"`
+			} `json:"floatingip"`
+		} `json:"quota"`
+	}
+	if err := s.get(ctx, "network", "/v2.0/quotas/"+url.PathEscape(s.projectID)+"/details.json", &details); err != nil {
+		return kcm.CloudQuota{}, err
+	}
+
+	fip := details.Quota.FloatingIP
+	return NewCloudQuota(kcm.CloudQuotaPublicIPs, fip.Limit, fip.Used+fip.Reserved), nil
+}
+
+func (s *openStackSession) loadBalancerQuota(ctx context.Context) (kcm.CloudQuota, error) {
+	var quota struct {
+		Quota struct {
+			LoadBalancer int64 `json:"load_balancer"`
+		} `json:"quota"`
+	}
+	if err := s.get(ctx, "load-balancer", "/v2/lbaas/quotas/"+url.PathEscape(s.projectID), &quota); err != nil {
+		return kcm.CloudQuota{}, err
+	}
+
+	var lbs struct {
+		LoadBalancers []json.RawMessage `json:"loadbalancers"`
+	}
+	if err := s.get(ctx, "load-balancer", "/v2/lbaas/loadbalancers?fields=id&project_id="+url.QueryEscape(s.projectID), &lbs); err != nil {
+		return kcm.CloudQuota{}, err
+	}
+
+	return NewCloudQuota(kcm.CloudQuotaLoadBalancers, quota.Quota.LoadBalancer, int64(len(lbs.LoadBalancers))), nil
+}
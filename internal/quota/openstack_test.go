@@ -0,0 +1,132 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+func TestOpenStackCollector_Collect(t *testing.T) {
+	g := NewWithT(t)
+
+	const (
+		token     = "token"
+		projectID = "project"
+	)
+
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	authorized := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Auth-Token") != token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			h(w, r)
+		}
+	}
+
+	mux.HandleFunc("POST /identity/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Auth struct {
+				Identity struct {
+					Methods []string `json:"methods"`
+				} `json:"identity"`
+			} `json:"auth"`
+		}
+		g.Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+		g.Expect(body.Auth.Identity.Methods).To(Equal([]string{"application_credential"}))
+
+		endpoint := func(path string) []map[string]string {
+			return []map[string]string{
+				{"interface": "internal", "region": "RegionOne", "url": "http://internal.invalid"},
+				{"interface": "public", "region": "RegionOne", "url": srv.URL + path},
+			}
+		}
+		w.Header().Set("X-Subject-Token", token)
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, map[string]any{"token": map[string]any{
+			"project": map[string]string{"id": projectID},
+			"catalog": []map[string]any{
+				{"type": "compute", "endpoints": endpoint("/compute/v2.1")},
+				{"type": "network", "endpoints": endpoint("/network/v2.0")},
+				{"type": "load-balancer", "endpoints": endpoint("/load-balancer")},
+			},
+		}})
+	})
+	mux.HandleFunc("GET /compute/v2.1/limits", authorized(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"limits": map[string]any{"absolute": map[string]int{
+			"maxTotalInstances": 10, "totalInstancesUsed": 4, "maxTotalCores": -1, "totalCoresUsed": 16,
+		}}})
+	}))
+	mux.HandleFunc("GET /network/v2.0/quotas/"+projectID+"/details.json", authorized(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"quota": map[string]any{"floatingip": map[string]int{"limit": 5, "used": 3, "reserved": 1}}})
+	}))
+	mux.HandleFunc("GET /load-balancer/v2/lbaas/quotas/"+projectID, authorized(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"quota": map[string]int{"load_balancer": 2}})
+	}))
+	mux.HandleFunc("GET /load-balancer/v2/lbaas/loadbalancers", authorized(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.Query().Get("project_id")).To(Equal(projectID))
+		writeJSON(w, map[string]any{"loadbalancers": []map[string]string{{"id": "a"}, {"id": "b"}}})
+	}))
+
+	srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "openstack-cloud-config", Namespace: "kcm-system"},
+		Data: map[string][]byte{openStackCloudsKey: []byte(`clouds:
+  openstack:
+    auth:
+      auth_url: ` + srv.URL + `/identity
+      application_credential_id: id
+      application_credential_secret: secret
+    region_name: RegionOne
+    interface: public
+`)},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(secret)
+	g.Expect(err).NotTo(HaveOccurred())
+	identity := &unstructured.Unstructured{Object: obj}
+
+	c := &OpenStackCollector{HTTPClient: srv.Client()}
+	g.Expect(c.Supports(identity)).To(BeTrue())
+	g.Expect(c.Supports(&unstructured.Unstructured{Object: map[string]any{"apiVersion": "v1", "kind": "Secret"}})).To(BeFalse())
+
+	quotas, err := c.Collect(t.Context(), nil, identity)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(quotas).To(Equal([]kcm.CloudQuota{
+		{Resource: kcm.CloudQuotaInstances, Limit: 10, Used: 4, Remaining: 6},
+		{Resource: kcm.CloudQuotaVCPUs, Limit: -1, Used: 16, Remaining: -1},
+		{Resource: kcm.CloudQuotaPublicIPs, Limit: 5, Used: 4, Remaining: 1},
+		{Resource: kcm.CloudQuotaLoadBalancers, Limit: 2, Used: 2, Remaining: 0},
+	}))
+}
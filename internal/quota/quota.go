@@ -0,0 +1,293 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"strings"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// instanceTypeKeys are the keys of the ClusterDeployment configuration
+// holding the instance types of the machines across the supported providers.
+var instanceTypeKeys = []string{"instanceType", "vmSize", "machineType", "flavor"}
+
+// Usage describes the resources consumed by a single ClusterDeployment.
+type Usage struct {
+	InstanceTypes []string
+	Nodes         int32
+}
+
+// UsageOf returns the resources consumed by the given ClusterDeployment
+// based on its configuration.
+func UsageOf(cd *kcm.ClusterDeployment) (Usage, error) {
+	values, err := cd.HelmValues()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	return UsageOfValues(values)
+}
+
+// UsageOfValues returns the resources consumed by the ClusterDeployment
+// with the given Helm values, e.g. the ones of its deployed HelmRelease.
+func UsageOfValues(values map[string]any) (Usage, error) {
+	var usage Usage
+	for _, key := range []string{"controlPlaneNumber", "workersNumber"} {
+		n, err := toInt32(values[key])
+		if err != nil {
+			return Usage{}, fmt.Errorf("invalid %s value: %w", key, err)
+		}
+		usage.Nodes += n
+	}
+
+	collectInstanceTypes(values, &usage.InstanceTypes)
+	slices.Sort(usage.InstanceTypes)
+	usage.InstanceTypes = slices.Compact(usage.InstanceTypes)
+
+	return usage, nil
+}
+
+// Exceeds reports whether the usage consumes more of the quotas than the
+// given previous one, i.e. it has more nodes or uses the instance types not
+// used before. The ClusterDeployments are not allowed to exceed their
+// previous usage only, so the ones deployed before the quotas are lowered
+// can still be updated.
+func (u Usage) Exceeds(prev Usage) bool {
+	if u.Nodes > prev.Nodes {
+		return true
+	}
+
+	for _, instanceType := range u.InstanceTypes {
+		if !slices.Contains(prev.InstanceTypes, instanceType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NodePool is the group of the machines of the ClusterDeployment of the same role.
+type NodePool struct {
+	Name         string
+	InstanceType string
+	Nodes        int32
+}
+
+// NodePoolsOf returns the control plane and the worker node pools of the
+// given ClusterDeployment based on its configuration. The instance type is
+// empty if it is not set, e.g. for the hosted control planes.
+func NodePoolsOf(cd *kcm.ClusterDeployment) ([]NodePool, error) {
+	values, err := cd.HelmValues()
+	if err != nil {
+		return nil, err
+	}
+
+	var pools []NodePool
+	for _, pool := range []struct{ name, numberKey string }{{"controlPlane", "controlPlaneNumber"}, {"worker", "workersNumber"}} {
+		n, err := toInt32(values[pool.numberKey])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %w", pool.numberKey, err)
+		}
+		if n == 0 {
+			continue
+		}
+
+		section, _ := values[pool.name].(map[string]any)
+		instanceType := instanceTypeOf(section)
+		if instanceType == "" && pool.name == "worker" {
+			// the hosted control plane templates configure the workers at the top level
+			instanceType = instanceTypeOf(values)
+		}
+		pools = append(pools, NodePool{Name: pool.name, InstanceType: instanceType, Nodes: n})
+	}
+
+	return pools, nil
+}
+
+// Evaluate computes the consumption of the given ClusterQuota by the ClusterDeployments
+// from the same namespace. The ClusterDeployments are admitted in the order of their
+// creation, those that do not fit into the remaining quota are returned
+// in the map of violations keyed by the ClusterDeployment name.
+// ClusterDeployments in the dry-run mode do not consume the quota.
+func Evaluate(quota *kcm.ClusterQuota, cds []kcm.ClusterDeployment) (kcm.ClusterQuotaUsage, map[string]error) {
+	return evaluate(quota, sortByCreation(cds))
+}
+
+func evaluate(quota *kcm.ClusterQuota, sorted []kcm.ClusterDeployment) (kcm.ClusterQuotaUsage, map[string]error) {
+	var (
+		used       kcm.ClusterQuotaUsage
+		admitted   kcm.ClusterQuotaUsage
+		violations = make(map[string]error)
+	)
+
+	for _, cd := range sorted {
+		if cd.Namespace != quota.Namespace || cd.Spec.DryRun {
+			continue
+		}
+
+		usage, err := UsageOf(&cd)
+		if err != nil {
+			violations[cd.Name] = err
+			continue
+		}
+
+		used.ClusterDeployments++
+		used.Nodes += usage.Nodes
+
+		if err := checkUsage(quota, admitted, usage); err != nil {
+			violations[cd.Name] = fmt.Errorf("ClusterQuota %s/%s is exceeded: %w", quota.Namespace, quota.Name, err)
+			continue
+		}
+
+		admitted.ClusterDeployments++
+		admitted.Nodes += usage.Nodes
+	}
+
+	return used, violations
+}
+
+// Check validates that the given ClusterDeployment fits into the ClusterQuota
+// after all of the ClusterDeployments created before it. The given ClusterDeployment
+// replaces its previous version in the list, if any. A ClusterDeployment that
+// is not yet created is checked after all of the existing ones.
+func Check(quota *kcm.ClusterQuota, cd *kcm.ClusterDeployment, cds []kcm.ClusterDeployment) error {
+	all := make([]kcm.ClusterDeployment, 0, len(cds)+1)
+	for _, existing := range cds {
+		if existing.Name != cd.Name {
+			all = append(all, existing)
+		}
+	}
+
+	if cd.CreationTimestamp.IsZero() {
+		all = append(sortByCreation(all), *cd)
+	} else {
+		all = sortByCreation(append(all, *cd))
+	}
+
+	_, violations := evaluate(quota, all)
+	return violations[cd.Name]
+}
+
+func checkUsage(quota *kcm.ClusterQuota, admitted kcm.ClusterQuotaUsage, usage Usage) error {
+	var errs error
+
+	if quota.Spec.MaxClusterDeployments != nil && admitted.ClusterDeployments+1 > *quota.Spec.MaxClusterDeployments {
+		errs = errors.Join(errs, fmt.Errorf("the number of ClusterDeployments exceeds the limit of %d", *quota.Spec.MaxClusterDeployments))
+	}
+
+	if quota.Spec.MaxNodes != nil && admitted.Nodes+usage.Nodes > *quota.Spec.MaxNodes {
+		errs = errors.Join(errs, fmt.Errorf("the total number of nodes %d exceeds the limit of %d", admitted.Nodes+usage.Nodes, *quota.Spec.MaxNodes))
+	}
+
+	if len(quota.Spec.AllowedInstanceTypes) > 0 {
+		for _, instanceType := range usage.InstanceTypes {
+			if !isInstanceTypeAllowed(quota.Spec.AllowedInstanceTypes, instanceType) {
+				errs = errors.Join(errs, fmt.Errorf("the instance type %q is not allowed, allowed types: %s", instanceType, strings.Join(quota.Spec.AllowedInstanceTypes, ", ")))
+			}
+		}
+	}
+
+	return errs
+}
+
+func isInstanceTypeAllowed(allowed []string, instanceType string) bool {
+	for _, pattern := range allowed {
+		if ok, _ := path.Match(pattern, instanceType); ok || pattern == instanceType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortByCreation returns a copy of the given ClusterDeployments sorted by the creation
+// timestamp. Objects not yet created (thus without the timestamp) are placed last.
+func sortByCreation(cds []kcm.ClusterDeployment) []kcm.ClusterDeployment {
+	sorted := slices.Clone(cds)
+	slices.SortStableFunc(sorted, func(a, b kcm.ClusterDeployment) int {
+		aZero, bZero := a.CreationTimestamp.IsZero(), b.CreationTimestamp.IsZero()
+		switch {
+		case aZero && bZero:
+			return strings.Compare(a.Name, b.Name)
+		case aZero:
+			return 1
+		case bZero:
+			return -1
+		}
+
+		if c := a.CreationTimestamp.Compare(b.CreationTimestamp.Time); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return sorted
+}
+
+func collectInstanceTypes(v any, dst *[]string) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, nested := range val {
+			if s, ok := nested.(string); ok && s != "" && slices.Contains(instanceTypeKeys, k) {
+				*dst = append(*dst, s)
+				continue
+			}
+			collectInstanceTypes(nested, dst)
+		}
+	case []any:
+		for _, nested := range val {
+			collectInstanceTypes(nested, dst)
+		}
+	}
+}
+
+func instanceTypeOf(values map[string]any) string {
+	for _, key := range instanceTypeKeys {
+		if s, ok := values[key].(string); ok && s != "" {
+			return s
+		}
+	}
+
+	return ""
+}
+
+func toInt32(v any) (int32, error) {
+	var n float64
+	switch val := v.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		n = float64(val)
+	case int:
+		n = float64(val)
+	case float64:
+		n = val
+	default:
+		return 0, fmt.Errorf("unexpected type %T", v)
+	}
+
+	if n < 0 || n > math.MaxInt32 || n != math.Trunc(n) {
+		return 0, fmt.Errorf("%v is not a valid number of nodes", v)
+	}
+
+	return int32(n), nil
+}
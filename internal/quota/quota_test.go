@@ -0,0 +1,234 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/objects/clusterdeployment"
+	"github.com/K0rdent/kcm/test/objects/clusterquota"
+)
+
+func TestUsageOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    Usage
+		wantErr bool
+	}{
+		{
+			name: "no config",
+		},
+		{
+			name:   "nodes and instance types",
+			config: `{"controlPlaneNumber":3,"workersNumber":2,"controlPlane":{"instanceType":"t3.large"},"worker":{"instanceType":"t3.small"}}`,
+			want:   Usage{Nodes: 5, InstanceTypes: []string{"t3.large", "t3.small"}},
+		},
+		{
+			name:   "nested machine pools",
+			config: `{"machinePools":{"system":{"count":1,"vmSize":"Standard_A4_v2"},"user":{"count":1,"vmSize":"Standard_A4_v2"}}}`,
+			want:   Usage{InstanceTypes: []string{"Standard_A4_v2"}},
+		},
+		{
+			name:    "invalid number of nodes",
+			config:  `{"workersNumber":"two"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var opts []clusterdeployment.Opt
+			if tt.config != "" {
+				opts = append(opts, clusterdeployment.WithConfig(tt.config))
+			}
+
+			got, err := UsageOf(clusterdeployment.NewClusterDeployment(opts...))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.Nodes).To(Equal(tt.want.Nodes))
+			g.Expect(got.InstanceTypes).To(Equal(tt.want.InstanceTypes))
+		})
+	}
+}
+
+func TestUsageExceeds(t *testing.T) {
+	prev := Usage{Nodes: 3, InstanceTypes: []string{"t3.large", "t3.small"}}
+
+	tests := []struct {
+		name  string
+		usage Usage
+		want  bool
+	}{
+		{name: "same usage", usage: prev},
+		{name: "fewer nodes", usage: Usage{Nodes: 2, InstanceTypes: []string{"t3.small"}}},
+		{name: "more nodes", usage: Usage{Nodes: 4, InstanceTypes: []string{"t3.small"}}, want: true},
+		{name: "new instance type", usage: Usage{Nodes: 3, InstanceTypes: []string{"t3.xlarge"}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tt.usage.Exceeds(prev)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNodePoolsOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   []NodePool
+	}{
+		{
+			name: "no config",
+		},
+		{
+			name:   "standalone control plane",
+			config: `{"controlPlaneNumber":3,"workersNumber":2,"controlPlane":{"vmSize":"Standard_A4_v2"},"worker":{"vmSize":"Standard_A2_v2"}}`,
+			want: []NodePool{
+				{Name: "controlPlane", InstanceType: "Standard_A4_v2", Nodes: 3},
+				{Name: "worker", InstanceType: "Standard_A2_v2", Nodes: 2},
+			},
+		},
+		{
+			name:   "hosted control plane",
+			config: `{"controlPlaneNumber":3,"workersNumber":2,"instanceType":"t3.small"}`,
+			want: []NodePool{
+				{Name: "controlPlane", Nodes: 3},
+				{Name: "worker", InstanceType: "t3.small", Nodes: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var opts []clusterdeployment.Opt
+			if tt.config != "" {
+				opts = append(opts, clusterdeployment.WithConfig(tt.config))
+			}
+
+			got, err := NodePoolsOf(clusterdeployment.NewClusterDeployment(opts...))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+	older := metav1.NewTime(now.Add(-time.Hour))
+	newer := metav1.NewTime(now)
+
+	tests := []struct {
+		name           string
+		quota          *kcm.ClusterQuota
+		cds            []kcm.ClusterDeployment
+		wantUsed       kcm.ClusterQuotaUsage
+		wantViolations []string
+	}{
+		{
+			name:  "no limits",
+			quota: clusterquota.NewClusterQuota(),
+			cds: []kcm.ClusterDeployment{
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("a"), clusterdeployment.WithConfig(`{"workersNumber":2}`)),
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("b"), clusterdeployment.WithConfig(`{"workersNumber":3}`)),
+			},
+			wantUsed: kcm.ClusterQuotaUsage{ClusterDeployments: 2, Nodes: 5},
+		},
+		{
+			name:  "newer cluster deployment exceeds the limit of clusters",
+			quota: clusterquota.NewClusterQuota(clusterquota.WithMaxClusterDeployments(1)),
+			cds: []kcm.ClusterDeployment{
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("a"), clusterdeployment.WithCreationTimestamp(newer)),
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("b"), clusterdeployment.WithCreationTimestamp(older)),
+			},
+			wantUsed:       kcm.ClusterQuotaUsage{ClusterDeployments: 2},
+			wantViolations: []string{"a"},
+		},
+		{
+			name:  "not yet created cluster deployment goes last",
+			quota: clusterquota.NewClusterQuota(clusterquota.WithMaxNodes(4)),
+			cds: []kcm.ClusterDeployment{
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("a"), clusterdeployment.WithConfig(`{"workersNumber":2}`)),
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("b"), clusterdeployment.WithConfig(`{"workersNumber":3}`), clusterdeployment.WithCreationTimestamp(older)),
+			},
+			wantUsed:       kcm.ClusterQuotaUsage{ClusterDeployments: 2, Nodes: 5},
+			wantViolations: []string{"a"},
+		},
+		{
+			name:  "dry-run and foreign namespace cluster deployments are ignored",
+			quota: clusterquota.NewClusterQuota(clusterquota.WithMaxClusterDeployments(1)),
+			cds: []kcm.ClusterDeployment{
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("a"), clusterdeployment.WithDryRun(true)),
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("b"), clusterdeployment.WithNamespace("other")),
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("c")),
+			},
+			wantUsed: kcm.ClusterQuotaUsage{ClusterDeployments: 1},
+		},
+		{
+			name:  "instance type is not allowed",
+			quota: clusterquota.NewClusterQuota(clusterquota.WithAllowedInstanceTypes("t3.*", "m5.large")),
+			cds: []kcm.ClusterDeployment{
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("a"), clusterdeployment.WithConfig(`{"worker":{"instanceType":"t3.small"}}`)),
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("b"), clusterdeployment.WithConfig(`{"worker":{"instanceType":"m5.large"}}`)),
+				*clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("c"), clusterdeployment.WithConfig(`{"worker":{"instanceType":"p4d.24xlarge"}}`)),
+			},
+			wantUsed:       kcm.ClusterQuotaUsage{ClusterDeployments: 3},
+			wantViolations: []string{"c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			used, violations := Evaluate(tt.quota, tt.cds)
+			g.Expect(used).To(Equal(tt.wantUsed))
+
+			names := make([]string, 0, len(violations))
+			for name := range violations {
+				names = append(names, name)
+			}
+			g.Expect(names).To(ConsistOf(tt.wantViolations))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	g := NewWithT(t)
+
+	quota := clusterquota.NewClusterQuota(clusterquota.WithMaxClusterDeployments(1))
+	existing := clusterdeployment.NewClusterDeployment(
+		clusterdeployment.WithName("existing"),
+		clusterdeployment.WithCreationTimestamp(metav1.Now()),
+	)
+
+	g.Expect(Check(quota, existing, []kcm.ClusterDeployment{*existing})).To(Succeed())
+	g.Expect(Check(quota, clusterdeployment.NewClusterDeployment(), []kcm.ClusterDeployment{*existing})).
+		To(MatchError(ContainSubstring("the number of ClusterDeployments exceeds the limit of 1")))
+}
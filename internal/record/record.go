@@ -0,0 +1,71 @@
+// Copyright 2025
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package record emits the Kubernetes Events of the objects reconciled by
+// the controllers.
+package record
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// defaultRecorder drops the events until the recorder of the manager is set.
+var defaultRecorder record.EventRecorder = new(record.FakeRecorder)
+
+// correlatedObject is the object the events of which are annotated with the
+// ID correlating them with the current attempt of its reconciliation.
+type correlatedObject interface {
+	GetCorrelationID() string
+}
+
+// InitFromRecorder sets the recorder the events are emitted with.
+func InitFromRecorder(recorder record.EventRecorder) {
+	defaultRecorder = recorder
+}
+
+// Event emits the event of the normal type.
+func Event(object runtime.Object, reason, message string) {
+	emit(object, corev1.EventTypeNormal, reason, message)
+}
+
+// Eventf emits the event of the normal type with the formatted message.
+func Eventf(object runtime.Object, reason, messageFmt string, args ...any) {
+	Event(object, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// Warn emits the event of the warning type.
+func Warn(object runtime.Object, reason, message string) {
+	emit(object, corev1.EventTypeWarning, reason, message)
+}
+
+// Warnf emits the event of the warning type with the formatted message.
+func Warnf(object runtime.Object, reason, messageFmt string, args ...any) {
+	Warn(object, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func emit(object runtime.Object, eventType, reason, message string) {
+	if o, ok := object.(correlatedObject); ok {
+		if id := o.GetCorrelationID(); id != "" {
+			defaultRecorder.AnnotatedEventf(object, map[string]string{kcm.CorrelationIDAnnotation: id}, eventType, reason, "%s", message)
+			return
+		}
+	}
+	defaultRecorder.Event(object, eventType, reason, message)
+}
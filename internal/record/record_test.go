@@ -0,0 +1,45 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package record
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+func TestEventCorrelationID(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := record.NewFakeRecorder(10)
+	InitFromRecorder(recorder)
+	t.Cleanup(func() { InitFromRecorder(new(record.FakeRecorder)) })
+
+	cd := &kcm.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Name: "cd", Namespace: "ns", UID: "uid", Generation: 2}}
+	Event(cd, "Reason", "message")
+	g.Expect(<-recorder.Events).To(Equal("Normal Reason message map[" + kcm.CorrelationIDAnnotation + ":uid-2]"))
+
+	cd.Annotations = map[string]string{kcm.CorrelationIDAnnotation: "attempt-1"}
+	Warnf(cd, "Reason", "message %d", 1)
+	g.Expect(<-recorder.Events).To(Equal("Warning Reason message 1 map[" + kcm.CorrelationIDAnnotation + ":attempt-1]"))
+
+	Event(&corev1.ConfigMap{}, "Reason", "message")
+	g.Expect(<-recorder.Events).To(Equal("Normal Reason message"))
+}
@@ -0,0 +1,212 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sharding splits the objects reconciled by a controller into shards
+// reconciled by the replicas of the controller manager holding the leases of
+// the shards, each replica holds at most one shard.
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// ShardLabel pins the object to the shard with the given index instead of the
+// one its name hashes to.
+const ShardLabel = "k0rdent.mirantis.com/shard"
+
+// Of returns the index of the shard of the object among the given number of
+// shards.
+func Of(obj client.Object, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+	if v, ok := obj.GetLabels()[ShardLabel]; ok {
+		if i, err := strconv.Atoi(v); err == nil && i >= 0 && i < shards {
+			return i
+		}
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(obj.GetNamespace() + "/" + obj.GetName()))
+	return int(h.Sum32() % uint32(shards)) //nolint:gosec // the number of shards is positive
+}
+
+// Options configure the Sharder.
+type Options struct {
+	// Namespace is the namespace of the leases of the shards.
+	Namespace string
+	// LeaseName is the prefix of the names of the leases, suffixed with the
+	// index of the shard.
+	LeaseName string
+	// Shards is the number of the shards.
+	Shards int
+	// LeaseDuration, RenewDeadline and RetryPeriod are the same as the ones
+	// of the leader election of the manager.
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// Sharder acquires the lease of one of the shards not held by the other
+// replicas and reports whether the objects belong to it. It is run by the
+// manager on every replica regardless of the leader election.
+type Sharder struct {
+	config   *rest.Config
+	acquired chan event.GenericEvent
+	identity string
+	opts     Options
+	shard    atomic.Int32
+}
+
+// New returns the Sharder holding no shard yet.
+func New(config *rest.Config, opts Options) (*Sharder, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	s := &Sharder{
+		config:   config,
+		acquired: make(chan event.GenericEvent, 1),
+		identity: hostname + "_" + string(uuid.NewUUID()),
+		opts:     opts,
+	}
+	s.shard.Store(-1)
+	return s, nil
+}
+
+// Shards returns the number of the shards.
+func (s *Sharder) Shards() int {
+	return s.opts.Shards
+}
+
+// Owns reports whether the object belongs to the shard held by the replica.
+func (s *Sharder) Owns(obj client.Object) bool {
+	shard := s.shard.Load()
+	return shard >= 0 && Of(obj, s.opts.Shards) == int(shard)
+}
+
+// Acquired returns the channel receiving an event once the shard is
+// acquired, so the controller can enqueue the objects of the shard filtered
+// out before.
+func (s *Sharder) Acquired() <-chan event.GenericEvent {
+	return s.acquired
+}
+
+// NeedLeaderElection implements the [sigs.k8s.io/controller-runtime/pkg/manager.LeaderElectionRunnable].
+func (*Sharder) NeedLeaderElection() bool {
+	return false
+}
+
+// Start tries to acquire the leases of all of the shards until one of them is
+// acquired, and then keeps renewing it. It returns an error once the lease is
+// lost, which stops the manager the same way the loss of the leadership does.
+func (s *Sharder) Start(ctx context.Context) error {
+	l := ctrl.LoggerFrom(ctx).WithName("sharder")
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		// wait for the held lease to be released
+		cancel()
+		wg.Wait()
+	}()
+
+	acquired := make(chan int, s.opts.Shards)
+	lost := make(chan int, s.opts.Shards)
+	cancels := make([]context.CancelFunc, s.opts.Shards)
+	for i := range s.opts.Shards {
+		name := fmt.Sprintf("%s-%d", s.opts.LeaseName, i)
+		lock, err := resourcelock.NewFromKubeconfig(resourcelock.LeasesResourceLock, s.opts.Namespace, name,
+			resourcelock.ResourceLockConfig{Identity: s.identity}, s.config, s.opts.RenewDeadline)
+		if err != nil {
+			return fmt.Errorf("failed to create the lock of the shard %d: %w", i, err)
+		}
+
+		elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			LeaseDuration:   s.opts.LeaseDuration,
+			RenewDeadline:   s.opts.RenewDeadline,
+			RetryPeriod:     s.opts.RetryPeriod,
+			ReleaseOnCancel: true,
+			Name:            name,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(context.Context) { acquired <- i },
+				OnStoppedLeading: func() {
+					if s.shard.Load() == int32(i) { //nolint:gosec // the number of shards fits
+						lost <- i
+					}
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create the elector of the shard %d: %w", i, err)
+		}
+
+		var electorCtx context.Context
+		electorCtx, cancels[i] = context.WithCancel(ctx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			elector.Run(electorCtx)
+		}()
+	}
+
+	l.Info("Waiting for a shard to be acquired", "shards", s.opts.Shards, "identity", s.identity)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case i := <-acquired:
+			// several leases might be acquired at once, the extra ones are
+			// released right away
+			if !s.shard.CompareAndSwap(-1, int32(i)) { //nolint:gosec // the number of shards fits
+				cancels[i]()
+				continue
+			}
+			for j, cancel := range cancels {
+				if j != i {
+					cancel()
+				}
+			}
+
+			l.Info("Acquired shard", "shard", i)
+			select {
+			case s.acquired <- event.GenericEvent{Object: &metav1.PartialObjectMetadata{}}:
+			default:
+			}
+		case i := <-lost:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("lost the lease of the shard %d", i)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharding
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestOf(t *testing.T) {
+	g := NewWithT(t)
+
+	newObject := func(name string, labels map[string]string) client.Object {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: labels}}
+	}
+
+	const shards = 4
+	counts := make([]int, shards)
+	for i := range 1000 {
+		obj := newObject(fmt.Sprintf("cluster-%d", i), nil)
+		shard := Of(obj, shards)
+		g.Expect(shard).To(Equal(Of(obj, shards)), "the shard is expected to be stable")
+		counts[shard]++
+	}
+	for _, count := range counts {
+		g.Expect(count).To(BeNumerically("~", 1000/shards, 50), "the objects are expected to be spread evenly")
+	}
+
+	g.Expect(Of(newObject("cluster", map[string]string{ShardLabel: "3"}), shards)).To(Equal(3))
+	// the invalid pins are ignored
+	g.Expect(Of(newObject("cluster", map[string]string{ShardLabel: "4"}), shards)).To(Equal(Of(newObject("cluster", nil), shards)))
+	g.Expect(Of(newObject("cluster", map[string]string{ShardLabel: "3"}), 1)).To(Equal(0))
+}
+
+func TestSharder_Owns(t *testing.T) {
+	g := NewWithT(t)
+
+	s, err := New(nil, Options{Shards: 2})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	obj := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Labels: map[string]string{ShardLabel: "1"}}}
+	g.Expect(s.Owns(obj)).To(BeFalse(), "no objects are expected to be owned until the shard is acquired")
+
+	s.shard.Store(1)
+	g.Expect(s.Owns(obj)).To(BeTrue())
+	obj.Labels[ShardLabel] = "0"
+	g.Expect(s.Owns(obj)).To(BeFalse())
+}
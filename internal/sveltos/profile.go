@@ -24,11 +24,14 @@ import (
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/hardening"
+	"github.com/K0rdent/kcm/internal/helm"
 	"github.com/K0rdent/kcm/internal/utils"
 )
 
@@ -46,10 +49,13 @@ type ReconcileProfileOpts struct {
 	PolicyRefs           []sveltosv1beta1.PolicyRef
 	DriftIgnore          []libsveltosv1beta1.PatchSelector
 	DriftExclusions      []sveltosv1beta1.DriftExclusion
+	Patches              []libsveltosv1beta1.Patch
 	Priority             int32
 	StopOnConflict       bool
 	Reload               bool
 	ContinueOnError      bool
+	// CorrelationID is set as the annotation of the Profile if not empty.
+	CorrelationID string
 }
 
 // ReconcileClusterProfile reconciles a Sveltos ClusterProfile object.
@@ -111,6 +117,13 @@ func ReconcileProfile(
 		}
 		p.Spec = *spec
 
+		if opts.CorrelationID != "" {
+			if p.Annotations == nil {
+				p.Annotations = make(map[string]string)
+			}
+			p.Annotations[kcm.CorrelationIDAnnotation] = opts.CorrelationID
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -235,6 +248,99 @@ func GetHelmCharts(ctx context.Context, c client.Client, namespace string, servi
 	return helmCharts, nil
 }
 
+// GetSecurityPatches returns the patches applying the security profile of the Management
+// to the workloads of the helm charts of the given services. The charts are rendered with
+// the services values, the values from the references are not taken into account.
+// Namespace is the namespace of the referred templates in services slice.
+func GetSecurityPatches(ctx context.Context, c client.Client, namespace string, services []kcm.Service) ([]libsveltosv1beta1.Patch, error) {
+	management := &kcm.Management{}
+	if err := c.Get(ctx, client.ObjectKey{Name: kcm.ManagementName}, management); err != nil {
+		return nil, fmt.Errorf("failed to get Management: %w", err)
+	}
+
+	profile := management.Spec.SecurityProfile
+	if profile == "" {
+		return nil, nil
+	}
+
+	var patches []libsveltosv1beta1.Patch
+	for _, svc := range services {
+		if svc.Disable {
+			continue
+		}
+
+		tmpl := &kcm.ServiceTemplate{}
+		tmplRef := client.ObjectKey{Name: svc.Template, Namespace: namespace}
+		if err := c.Get(ctx, tmplRef, tmpl); err != nil {
+			return nil, fmt.Errorf("failed to get ServiceTemplate %s: %w", tmplRef.String(), err)
+		}
+
+		if tmpl.Spec.Helm == nil || !tmpl.Status.Valid {
+			continue
+		}
+
+		if tmpl.GetCommonStatus() == nil || tmpl.GetCommonStatus().ChartRef == nil {
+			return nil, fmt.Errorf("status for ServiceTemplate %s/%s has not been updated yet", tmpl.Namespace, tmpl.Name)
+		}
+
+		chart := &sourcev1.HelmChart{}
+		chartRef := client.ObjectKey{
+			Namespace: tmpl.GetCommonStatus().ChartRef.Namespace,
+			Name:      tmpl.GetCommonStatus().ChartRef.Name,
+		}
+		if err := c.Get(ctx, chartRef, chart); err != nil {
+			return nil, fmt.Errorf("failed to get HelmChart %s referenced by ServiceTemplate %s: %w", chartRef.String(), tmplRef.String(), err)
+		}
+		if chart.GetArtifact() == nil {
+			return nil, fmt.Errorf("HelmChart %s referenced by ServiceTemplate %s has no artifact yet", chartRef.String(), tmplRef.String())
+		}
+
+		hcChart, err := helm.DownloadChartFromArtifact(ctx, chart.GetArtifact())
+		if err != nil {
+			return nil, fmt.Errorf("failed to download HelmChart %s: %w", chartRef.String(), err)
+		}
+
+		values := make(map[string]any)
+		if err := yaml.Unmarshal([]byte(svc.Values), &values); err != nil {
+			return nil, fmt.Errorf("failed to parse values of the service %s: %w", svc.Name, err)
+		}
+
+		releaseNamespace := svc.Namespace
+		if releaseNamespace == "" {
+			releaseNamespace = svc.Name
+		}
+
+		manifests, err := helm.RenderChart(hcChart, values, svc.Name, releaseNamespace)
+		if err != nil {
+			return nil, err
+		}
+
+		svcPatches, err := hardening.Patches(profile, manifests)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range svcPatches {
+			namespace := p.Namespace
+			if namespace == "" {
+				namespace = releaseNamespace
+			}
+			patches = append(patches, libsveltosv1beta1.Patch{
+				Patch: p.Patch,
+				Target: &libsveltosv1beta1.PatchSelector{
+					Group:     p.Group,
+					Version:   p.Version,
+					Kind:      p.Kind,
+					Name:      p.Name,
+					Namespace: namespace,
+				},
+			})
+		}
+	}
+
+	return patches, nil
+}
+
 func GetKustomizationRefs(ctx context.Context, c client.Client, namespace string, services []kcm.Service) ([]sveltosv1beta1.KustomizationRef, error) {
 	l := ctrl.LoggerFrom(ctx)
 	kustomizationRefs := []sveltosv1beta1.KustomizationRef{}
@@ -342,6 +448,7 @@ func GetSpec(opts *ReconcileProfileOpts) (*sveltosv1beta1.Spec, error) {
 		PolicyRefs:           opts.PolicyRefs,
 		DriftExclusions:      opts.DriftExclusions,
 		ContinueOnError:      opts.ContinueOnError,
+		Patches:              opts.Patches,
 	}
 
 	for _, target := range opts.DriftIgnore {
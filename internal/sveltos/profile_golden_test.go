@@ -0,0 +1,175 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sveltos
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	helmcontrollerv2 "github.com/fluxcd/helm-controller/api/v2"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	sveltosv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/utils"
+	"github.com/K0rdent/kcm/test/golden"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+const goldenNamespace = "test-namespace"
+
+// goldenServices are the services of the profiles rendered by the golden
+// tests: an OCI chart, a chart of the HTTP repository with the credentials
+// and a disabled service.
+var goldenServices = []kcm.Service{
+	{
+		Template:  "ingress-nginx-4-11-0",
+		Name:      "ingress-nginx",
+		Namespace: "ingress",
+		Values:    "controller:\n  replicaCount: 2\n",
+	},
+	{
+		Template: "cert-manager-1-16-2",
+		Name:     "cert-manager",
+		ValuesFrom: []sveltosv1beta1.ValueFrom{
+			{Kind: "ConfigMap", Name: "cert-manager-values"},
+		},
+	},
+	{
+		Template: "kyverno-3-2-6",
+		Name:     "kyverno",
+		Disable:  true,
+	},
+}
+
+func goldenObjects() []client.Object {
+	serviceTemplate := func(name, chartName string) *kcm.ServiceTemplate {
+		return &kcm.ServiceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: goldenNamespace},
+			Spec:       kcm.ServiceTemplateSpec{Helm: &kcm.HelmSpec{ChartRef: &helmcontrollerv2.CrossNamespaceSourceReference{}}},
+			Status: kcm.ServiceTemplateStatus{TemplateStatusCommon: kcm.TemplateStatusCommon{
+				TemplateValidationStatus: kcm.TemplateValidationStatus{Valid: true},
+				ChartRef:                 &helmcontrollerv2.CrossNamespaceSourceReference{Kind: sourcev1.HelmChartKind, Name: chartName, Namespace: goldenNamespace},
+			}},
+		}
+	}
+	helmChart := func(name, chart, version, repository string) *sourcev1.HelmChart {
+		return &sourcev1.HelmChart{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: goldenNamespace},
+			Spec: sourcev1.HelmChartSpec{
+				Chart:     chart,
+				Version:   version,
+				SourceRef: sourcev1.LocalHelmChartSourceReference{Kind: sourcev1.HelmRepositoryKind, Name: repository},
+			},
+		}
+	}
+
+	return []client.Object{
+		serviceTemplate("ingress-nginx-4-11-0", "ingress-nginx-4-11-0"),
+		serviceTemplate("cert-manager-1-16-2", "cert-manager-1-16-2"),
+		serviceTemplate("kyverno-3-2-6", "kyverno-3-2-6"),
+		helmChart("ingress-nginx-4-11-0", "ingress-nginx", "4.11.0", "kcm-templates"),
+		helmChart("cert-manager-1-16-2", "cert-manager", "v1.16.2", "jetstack"),
+		&sourcev1.HelmRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "kcm-templates", Namespace: goldenNamespace},
+			Spec:       sourcev1.HelmRepositorySpec{URL: "oci://ghcr.io/k0rdent/kcm/charts", Type: utils.RegistryTypeOCI},
+		},
+		&sourcev1.HelmRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "jetstack", Namespace: goldenNamespace},
+			Spec: sourcev1.HelmRepositorySpec{
+				URL:       "http://charts.example.com",
+				Insecure:  true,
+				SecretRef: &fluxmeta.LocalObjectReference{Name: "jetstack-credentials"},
+			},
+		},
+	}
+}
+
+// TestProfilesGolden compares the Profile of the ClusterDeployment and the
+// ClusterProfile of the MultiClusterService reconciled for the fixed
+// services and options with the golden files, run the test with the -update
+// flag to rewrite them.
+func TestProfilesGolden(t *testing.T) {
+	ctx := context.Background()
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(goldenObjects()...).Build()
+
+	helmCharts, err := GetHelmCharts(ctx, cl, goldenNamespace, goldenServices)
+	require.NoError(t, err)
+
+	opts := ReconcileProfileOpts{
+		HelmCharts: helmCharts,
+		TemplateResourceRefs: []sveltosv1beta1.TemplateResourceRef{
+			{Resource: corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Name: "identity"}, Identifier: "InfrastructureProviderIdentity"},
+		},
+		PolicyRefs: []sveltosv1beta1.PolicyRef{
+			{Kind: "ConfigMap", Namespace: goldenNamespace, Name: "identity-resource-template", DeploymentType: sveltosv1beta1.DeploymentTypeRemote},
+		},
+		DriftIgnore: []libsveltosv1beta1.PatchSelector{
+			{Kind: "Deployment", Name: "ingress-nginx-controller", Namespace: "ingress"},
+		},
+		DriftExclusions: []sveltosv1beta1.DriftExclusion{
+			{Paths: []string{"/spec/replicas"}},
+		},
+		SyncMode:      string(sveltosv1beta1.SyncModeContinuousWithDriftDetection),
+		Priority:      100,
+		Reload:        true,
+		CorrelationID: "00000000-0000-0000-0000-000000000000",
+	}
+
+	t.Run("profile", func(t *testing.T) {
+		opts := opts
+		opts.OwnerReference = &metav1.OwnerReference{APIVersion: kcm.GroupVersion.String(), Kind: kcm.ClusterDeploymentKind, Name: "test-cluster", UID: "test-cluster-uid"}
+		opts.LabelSelector = metav1.LabelSelector{MatchLabels: map[string]string{kcm.FluxHelmChartNamespaceKey: goldenNamespace, kcm.FluxHelmChartNameKey: "test-cluster"}}
+
+		profile, err := ReconcileProfile(ctx, cl, goldenNamespace, "test-cluster", opts)
+		require.NoError(t, err)
+		profile.TypeMeta = metav1.TypeMeta{APIVersion: sveltosv1beta1.GroupVersion.String(), Kind: sveltosv1beta1.ProfileKind}
+		assertGolden(t, profile)
+	})
+
+	t.Run("clusterprofile", func(t *testing.T) {
+		opts := opts
+		opts.OwnerReference = &metav1.OwnerReference{APIVersion: kcm.GroupVersion.String(), Kind: kcm.MultiClusterServiceKind, Name: "test-mcs", UID: "test-mcs-uid"}
+		opts.LabelSelector = metav1.LabelSelector{MatchLabels: map[string]string{"env": "test"}}
+		opts.StopOnConflict = true
+		opts.ContinueOnError = true
+
+		clusterProfile, err := ReconcileClusterProfile(ctx, cl, "test-mcs", opts)
+		require.NoError(t, err)
+		clusterProfile.TypeMeta = metav1.TypeMeta{APIVersion: sveltosv1beta1.GroupVersion.String(), Kind: sveltosv1beta1.ClusterProfileKind}
+		assertGolden(t, clusterProfile)
+	})
+}
+
+// assertGolden compares the object without the fields set by the API server
+// with the golden file of the test.
+func assertGolden(t *testing.T, obj client.Object) {
+	t.Helper()
+
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	data, err := yaml.Marshal(obj)
+	require.NoError(t, err)
+	golden.Assert(t, filepath.Join("testdata", t.Name()+".golden.yaml"), data)
+}
@@ -15,8 +15,12 @@
 package telemetry
 
 import (
+	"context"
+	"strconv"
+
 	"github.com/segmentio/analytics-go"
 
+	"github.com/K0rdent/kcm/api/v1alpha1"
 	"github.com/K0rdent/kcm/internal/build"
 )
 
@@ -25,17 +29,25 @@ const (
 	clusterDeploymentHeartbeatEvent = "cluster-deployment-heartbeat"
 )
 
-func TrackClusterDeploymentCreate(id, clusterDeploymentID, template string, dryRun bool) error {
+func TrackClusterDeploymentCreate(ctx context.Context, mgmt *v1alpha1.Management, clusterDeploymentID, template string, dryRun bool) error {
+	policy := PolicyFor(mgmt)
+	if !policy.Collects(v1alpha1.TelemetryCategoryLifecycle) {
+		return nil
+	}
+	if policy.Prometheus() {
+		metricClusterDeploymentCreations.WithLabelValues(template, strconv.FormatBool(dryRun)).Inc()
+	}
+
 	props := map[string]any{
 		"kcmVersion":          build.Version,
 		"clusterDeploymentID": clusterDeploymentID,
 		"template":            template,
 		"dryRun":              dryRun,
 	}
-	return TrackEvent(clusterDeploymentCreateEvent, id, props)
+	return policy.export(ctx, clusterDeploymentCreateEvent, string(mgmt.UID), props)
 }
 
-func TrackClusterDeploymentHeartbeat(id, clusterDeploymentID, clusterID, template, templateHelmChartVersion string, providers []string) error {
+func TrackClusterDeploymentHeartbeat(ctx context.Context, mgmt *v1alpha1.Management, clusterDeploymentID, clusterID, template, templateHelmChartVersion string, providers []string) error {
 	props := map[string]any{
 		"kcmVersion":               build.Version,
 		"clusterDeploymentID":      clusterDeploymentID,
@@ -44,7 +56,7 @@ func TrackClusterDeploymentHeartbeat(id, clusterDeploymentID, clusterID, templat
 		"templateHelmChartVersion": templateHelmChartVersion,
 		"providers":                providers,
 	}
-	return TrackEvent(clusterDeploymentHeartbeatEvent, id, props)
+	return PolicyFor(mgmt).export(ctx, clusterDeploymentHeartbeatEvent, string(mgmt.UID), props)
 }
 
 func TrackEvent(name, id string, properties map[string]any) error {
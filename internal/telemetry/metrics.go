@@ -0,0 +1,55 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+const (
+	metricLabelTemplate             = "template"
+	metricLabelTemplateChartVersion = "template_chart_version"
+	metricLabelDryRun               = "dry_run"
+)
+
+var metricClusterDeploymentCreations = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: v1alpha1.CoreKCMName,
+		Subsystem: "telemetry",
+		Name:      "cluster_deployment_creations_total",
+		Help:      "Number of the created ClusterDeployments",
+	},
+	[]string{metricLabelTemplate, metricLabelDryRun},
+)
+
+var metricClusterDeployments = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: v1alpha1.CoreKCMName,
+		Subsystem: "telemetry",
+		Name:      "cluster_deployments",
+		Help:      "Number of the ClusterDeployments as of the last heartbeat",
+	},
+	[]string{metricLabelTemplate, metricLabelTemplateChartVersion},
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		metricClusterDeploymentCreations,
+		metricClusterDeployments,
+	)
+}
@@ -0,0 +1,132 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/K0rdent/kcm/internal/build"
+)
+
+const otlpLogsPath = "/v1/logs"
+
+var otlpClient = &http.Client{Timeout: 10 * time.Second}
+
+type (
+	otlpLogsRequest struct {
+		ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+	}
+	otlpResourceLogs struct {
+		Resource  otlpResource    `json:"resource"`
+		ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeLogs struct {
+		Scope      otlpScope       `json:"scope"`
+		LogRecords []otlpLogRecord `json:"logRecords"`
+	}
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+	otlpLogRecord struct {
+		Body         otlpAnyValue   `json:"body"`
+		TimeUnixNano string         `json:"timeUnixNano"`
+		Attributes   []otlpKeyValue `json:"attributes"`
+	}
+	otlpKeyValue struct {
+		Value otlpAnyValue `json:"value"`
+		Key   string       `json:"key"`
+	}
+	otlpAnyValue struct {
+		StringValue *string         `json:"stringValue,omitempty"`
+		BoolValue   *bool           `json:"boolValue,omitempty"`
+		ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+	}
+	otlpArrayValue struct {
+		Values []otlpAnyValue `json:"values"`
+	}
+)
+
+// exportOTLP exports the event as the OpenTelemetry log record to the
+// OTLP/HTTP receiver at the given endpoint in the JSON encoding.
+func exportOTLP(ctx context.Context, endpoint, name, id string, properties map[string]any) error {
+	attributes := []otlpKeyValue{{Key: "event.name", Value: otlpString(name)}, {Key: "kcm.management.id", Value: otlpString(id)}}
+	for k, v := range properties {
+		attributes = append(attributes, otlpKeyValue{Key: k, Value: otlpValue(v)})
+	}
+
+	body, err := json.Marshal(otlpLogsRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{{Key: "service.name", Value: otlpString("kcm")}}},
+		ScopeLogs: []otlpScopeLogs{{
+			Scope: otlpScope{Name: "kcm/telemetry", Version: build.Version},
+			LogRecords: []otlpLogRecord{{
+				TimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+				Body:         otlpString(name),
+				Attributes:   attributes,
+			}},
+		}},
+	}}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal OTLP logs request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+otlpLogsPath, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP logs request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := otlpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export the event %s to %s: %w", name, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to export the event %s to %s: unexpected status %s", name, endpoint, resp.Status)
+	}
+	return nil
+}
+
+func otlpString(s string) otlpAnyValue {
+	return otlpAnyValue{StringValue: &s}
+}
+
+func otlpValue(v any) otlpAnyValue {
+	switch v := v.(type) {
+	case string:
+		return otlpString(v)
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case []string:
+		values := make([]otlpAnyValue, 0, len(v))
+		for _, s := range v {
+			values = append(values, otlpString(s))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	default:
+		return otlpString(fmt.Sprint(v))
+	}
+}
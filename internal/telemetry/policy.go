@@ -0,0 +1,79 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+// Policy is the telemetry policy of the Management.
+type Policy struct {
+	spec v1alpha1.Telemetry
+}
+
+// PolicyFor returns the telemetry policy of the given Management. All of the
+// data is collected and sent online if the Management has no policy set.
+func PolicyFor(mgmt *v1alpha1.Management) Policy {
+	p := Policy{spec: v1alpha1.Telemetry{Mode: v1alpha1.TelemetryModeOnline}}
+	if mgmt.Spec.Telemetry != nil {
+		p.spec = *mgmt.Spec.Telemetry
+	}
+	if p.spec.Mode == "" {
+		p.spec.Mode = v1alpha1.TelemetryModeOnline
+	}
+	return p
+}
+
+// Collects reports whether the data of the given category is collected.
+func (p Policy) Collects(category v1alpha1.TelemetryCategory) bool {
+	if p.spec.Mode == v1alpha1.TelemetryModeDisabled {
+		return false
+	}
+	return len(p.spec.Categories) == 0 || slices.Contains(p.spec.Categories, category)
+}
+
+// Online reports whether the data is sent online.
+func (p Policy) Online() bool {
+	return p.spec.Mode == v1alpha1.TelemetryModeOnline
+}
+
+// Prometheus reports whether the aggregated data is exposed as the metrics.
+func (p Policy) Prometheus() bool {
+	return p.spec.Exporters != nil && p.spec.Exporters.Prometheus
+}
+
+// otlpEndpoint returns the endpoint of the OTLP exporter if configured.
+func (p Policy) otlpEndpoint() string {
+	if p.spec.Exporters == nil || p.spec.Exporters.OTLP == nil {
+		return ""
+	}
+	return p.spec.Exporters.OTLP.Endpoint
+}
+
+// export sends the event online and to the OTLP exporter according to the policy.
+func (p Policy) export(ctx context.Context, name, id string, properties map[string]any) error {
+	var errs error
+	if p.Online() {
+		errs = errors.Join(errs, TrackEvent(name, id, properties))
+	}
+	if endpoint := p.otlpEndpoint(); endpoint != "" {
+		errs = errors.Join(errs, exportOTLP(ctx, endpoint, name, id, properties))
+	}
+	return errs
+}
@@ -0,0 +1,89 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+func TestPolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	policy := PolicyFor(&v1alpha1.Management{})
+	g.Expect(policy.Online()).To(BeTrue())
+	g.Expect(policy.Prometheus()).To(BeFalse())
+	g.Expect(policy.Collects(v1alpha1.TelemetryCategoryLifecycle)).To(BeTrue())
+	g.Expect(policy.Collects(v1alpha1.TelemetryCategoryInventory)).To(BeTrue())
+
+	policy = PolicyFor(&v1alpha1.Management{Spec: v1alpha1.ManagementSpec{Telemetry: &v1alpha1.Telemetry{
+		Mode:       v1alpha1.TelemetryModeLocal,
+		Categories: []v1alpha1.TelemetryCategory{v1alpha1.TelemetryCategoryInventory},
+		Exporters:  &v1alpha1.TelemetryExporters{Prometheus: true},
+	}}})
+	g.Expect(policy.Online()).To(BeFalse())
+	g.Expect(policy.Prometheus()).To(BeTrue())
+	g.Expect(policy.Collects(v1alpha1.TelemetryCategoryLifecycle)).To(BeFalse())
+	g.Expect(policy.Collects(v1alpha1.TelemetryCategoryInventory)).To(BeTrue())
+
+	policy = PolicyFor(&v1alpha1.Management{Spec: v1alpha1.ManagementSpec{Telemetry: &v1alpha1.Telemetry{Mode: v1alpha1.TelemetryModeDisabled}}})
+	g.Expect(policy.Collects(v1alpha1.TelemetryCategoryLifecycle)).To(BeFalse())
+	g.Expect(policy.Collects(v1alpha1.TelemetryCategoryInventory)).To(BeFalse())
+}
+
+func TestTrackClusterDeploymentCreate_OTLP(t *testing.T) {
+	g := NewWithT(t)
+
+	var received otlpLogsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.Path).To(Equal(otlpLogsPath))
+		g.Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+		body, err := io.ReadAll(r.Body)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(json.Unmarshal(body, &received)).To(Succeed())
+	}))
+	defer server.Close()
+
+	mgmt := &v1alpha1.Management{
+		ObjectMeta: metav1.ObjectMeta{UID: "mgmt-uid"},
+		Spec: v1alpha1.ManagementSpec{Telemetry: &v1alpha1.Telemetry{
+			Mode:      v1alpha1.TelemetryModeLocal,
+			Exporters: &v1alpha1.TelemetryExporters{OTLP: &v1alpha1.OTLPExporter{Endpoint: server.URL + "/"}},
+		}},
+	}
+	g.Expect(TrackClusterDeploymentCreate(t.Context(), mgmt, "cd-uid", "aws-standalone-cp-0-1-0", false)).To(Succeed())
+
+	g.Expect(received.ResourceLogs).To(HaveLen(1))
+	g.Expect(received.ResourceLogs[0].ScopeLogs).To(HaveLen(1))
+	records := received.ResourceLogs[0].ScopeLogs[0].LogRecords
+	g.Expect(records).To(HaveLen(1))
+	g.Expect(*records[0].Body.StringValue).To(Equal(clusterDeploymentCreateEvent))
+
+	attributes := make(map[string]otlpAnyValue)
+	for _, kv := range records[0].Attributes {
+		attributes[kv.Key] = kv.Value
+	}
+	g.Expect(*attributes["kcm.management.id"].StringValue).To(Equal("mgmt-uid"))
+	g.Expect(*attributes["template"].StringValue).To(Equal("aws-standalone-cp-0-1-0"))
+	g.Expect(*attributes["dryRun"].BoolValue).To(BeFalse())
+}
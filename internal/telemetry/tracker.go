@@ -70,6 +70,12 @@ func (t *Tracker) trackClusterDeploymentHeartbeat(ctx context.Context) error {
 		return err
 	}
 
+	policy := PolicyFor(mgmt)
+	metricClusterDeployments.Reset()
+	if !policy.Collects(v1alpha1.TelemetryCategoryInventory) {
+		return nil
+	}
+
 	templates := make(map[string]v1alpha1.ClusterTemplate)
 	for _, template := range templatesList.Items {
 		templates[template.Name] = template
@@ -86,8 +92,13 @@ func (t *Tracker) trackClusterDeploymentHeartbeat(ctx context.Context) error {
 		// TODO: get k0s cluster ID once it's exposed in k0smotron API
 		clusterID := ""
 
+		if policy.Prometheus() {
+			metricClusterDeployments.WithLabelValues(clusterDeployment.Spec.Template, template.Status.ChartVersion).Inc()
+		}
+
 		err := TrackClusterDeploymentHeartbeat(
-			string(mgmt.UID),
+			ctx,
+			mgmt,
 			string(clusterDeployment.UID),
 			clusterID,
 			clusterDeployment.Spec.Template,
@@ -0,0 +1,132 @@
+// Copyright 2025
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/build"
+)
+
+const tracerName = "github.com/K0rdent/kcm"
+
+var (
+	mu       sync.Mutex
+	current  *kcm.Tracing
+	provider *sdktrace.TracerProvider
+)
+
+// Configure sets up the export of the traces to the OTLP collector of the
+// given configuration, replacing the previously configured one. The traces
+// are not exported if the configuration is nil.
+func Configure(ctx context.Context, cfg *kcm.Tracing) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if reflect.DeepEqual(current, cfg) {
+		return nil
+	}
+
+	var next *sdktrace.TracerProvider
+	if cfg != nil {
+		var err error
+		if next, err = newTracerProvider(ctx, cfg); err != nil {
+			return err
+		}
+		otel.SetTracerProvider(next)
+	} else {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	}
+
+	if provider != nil {
+		if err := provider.Shutdown(ctx); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to shut down the previous tracer provider")
+		}
+	}
+
+	current, provider = cfg.DeepCopy(), next
+	return nil
+}
+
+func newTracerProvider(ctx context.Context, cfg *kcm.Tracing) (*sdktrace.TracerProvider, error) {
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	// the exporter connects lazily, so the unavailable collector does not fail it
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	ratio := 1.
+	if cfg.SamplingPercentage != nil {
+		ratio = float64(*cfg.SamplingPercentage) / 100
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(kcm.CoreKCMName),
+			semconv.ServiceVersion(build.Version),
+		)),
+	), nil
+}
+
+// Start starts the span of the given name with the object attributes.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// CorrelationIDAttribute returns the attribute of the ID correlating the span
+// with the logs and events of the same reconciliation attempt.
+func CorrelationIDAttribute(id string) attribute.KeyValue {
+	return attribute.String("k0rdent.correlation_id", id)
+}
+
+// ObjectAttributes returns the attributes of the reconciled object.
+func ObjectAttributes(kind, namespace, name string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("k8s.object.kind", kind), attribute.String("k8s.object.name", name)}
+	if namespace != "" {
+		attrs = append(attrs, attribute.String("k8s.namespace.name", namespace))
+	}
+	return attrs
+}
@@ -0,0 +1,64 @@
+// Copyright 2025
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	"k8s.io/utils/ptr"
+
+	kcm "github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+func TestConfigure(t *testing.T) {
+	g := NewWithT(t)
+	ctx := t.Context()
+
+	cfg := &kcm.Tracing{Endpoint: "localhost:4317", Insecure: true, SamplingPercentage: ptr.To[int32](50)}
+	g.Expect(Configure(ctx, cfg)).To(Succeed())
+	g.Expect(otel.GetTracerProvider()).To(BeAssignableToTypeOf(&sdktrace.TracerProvider{}))
+	configured := otel.GetTracerProvider()
+
+	g.Expect(Configure(ctx, cfg.DeepCopy())).To(Succeed())
+	g.Expect(otel.GetTracerProvider()).To(BeIdenticalTo(configured), "unchanged configuration is expected to keep the provider")
+
+	g.Expect(Configure(ctx, nil)).To(Succeed())
+	g.Expect(otel.GetTracerProvider()).To(BeAssignableToTypeOf(noop.NewTracerProvider()))
+}
+
+func TestEnd(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(tracerName)
+
+	_, span := tracer.Start(t.Context(), "succeeded")
+	End(span, nil)
+	_, span = tracer.Start(t.Context(), "failed")
+	End(span, errors.New("boom"))
+
+	spans := recorder.Ended()
+	g.Expect(spans).To(HaveLen(2))
+	g.Expect(spans[0].Status().Code).To(Equal(codes.Unset))
+	g.Expect(spans[1].Status().Code).To(Equal(codes.Error))
+	g.Expect(spans[1].Status().Description).To(Equal("boom"))
+}
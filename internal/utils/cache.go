@@ -0,0 +1,48 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// helmReleaseSecretType is the type of the Secrets the Helm releases are stored in.
+const helmReleaseSecretType corev1.SecretType = "helm.sh/release.v1"
+
+// CacheByObject returns the selectors of the high-cardinality objects cached
+// by the manager. The Secrets storing the Helm releases, which are the most
+// numerous and the largest ones in the management cluster, are read by Helm
+// directly and are never cached.
+func CacheByObject() map[client.Object]cache.ByObject {
+	return map[client.Object]cache.ByObject{
+		&corev1.Secret{}: {
+			Field: fields.OneTermNotEqualSelector("type", string(helmReleaseSecretType)),
+		},
+	}
+}
+
+// UncachedObjects returns the objects read by the manager client directly
+// from the API server instead of the cache. They are neither watched nor
+// listed by the controllers and are read only occasionally, so caching all of
+// them in the cluster is not worth the memory.
+//
+// The CAPI Machines are listed as the unstructured objects, which are not
+// cached either.
+func UncachedObjects() []client.Object {
+	return []client.Object{&corev1.ConfigMap{}}
+}
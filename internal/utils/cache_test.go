@@ -0,0 +1,48 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/K0rdent/kcm/internal/utils"
+)
+
+func TestCacheByObject(t *testing.T) {
+	var secretSelector fields.Selector
+	for obj, byObject := range utils.CacheByObject() {
+		if _, ok := obj.(*corev1.Secret); ok {
+			secretSelector = byObject.Field
+		}
+	}
+	if secretSelector == nil {
+		t.Fatal("expected the field selector of the Secrets")
+	}
+
+	for secretType, cached := range map[corev1.SecretType]bool{
+		corev1.SecretTypeOpaque:           true,
+		corev1.SecretTypeTLS:              true,
+		"cluster.x-k8s.io/secret":         true,
+		"helm.sh/release.v1":              false,
+		corev1.SecretTypeDockerConfigJson: true,
+	} {
+		if got := secretSelector.Matches(fields.Set{"type": string(secretType)}); got != cached {
+			t.Errorf("expected the Secret of the %s type to be cached: %t, got %t", secretType, cached, got)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DependencyOrder returns the given names ordered so that each of them
+// follows its dependencies. The names with no dependencies between them keep
+// their relative order. An error is returned if any of the dependencies is not
+// in the names or the dependencies are cyclic.
+func DependencyOrder(names []string, dependencies map[string][]string) ([]string, error) {
+	for _, name := range names {
+		for _, dep := range dependencies[name] {
+			if !slices.Contains(names, dep) {
+				return nil, fmt.Errorf("%s depends on %s which is not installed", name, dep)
+			}
+		}
+	}
+
+	ordered := make([]string, 0, len(names))
+	remaining := slices.Clone(names)
+	for len(remaining) > 0 {
+		i := slices.IndexFunc(remaining, func(name string) bool {
+			return !slices.ContainsFunc(dependencies[name], func(dep string) bool {
+				return !slices.Contains(ordered, dep)
+			})
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("dependency cycle between %s", strings.Join(remaining, ", "))
+		}
+		ordered = append(ordered, remaining[i])
+		remaining = slices.Delete(remaining, i, i+1)
+	}
+
+	return ordered, nil
+}
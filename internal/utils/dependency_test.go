@@ -0,0 +1,70 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/K0rdent/kcm/internal/utils"
+)
+
+func TestDependencyOrder(t *testing.T) {
+	tests := []struct {
+		dependencies map[string][]string
+		name         string
+		wantErr      string
+		names        []string
+		want         []string
+	}{
+		{
+			name:  "no dependencies",
+			names: []string{"kcm", "capi", "aws"},
+			want:  []string{"kcm", "capi", "aws"},
+		},
+		{
+			name:         "dependencies go first",
+			names:        []string{"kcm", "openstack", "k0smotron", "capi"},
+			dependencies: map[string][]string{"openstack": {"capi", "k0smotron"}, "k0smotron": {"capi"}, "capi": {"kcm"}},
+			want:         []string{"kcm", "capi", "k0smotron", "openstack"},
+		},
+		{
+			name:         "unknown dependency",
+			names:        []string{"kcm", "aws"},
+			dependencies: map[string][]string{"aws": {"capi"}},
+			wantErr:      "aws depends on capi which is not installed",
+		},
+		{
+			name:         "dependency cycle",
+			names:        []string{"kcm", "aws", "azure"},
+			dependencies: map[string][]string{"aws": {"azure"}, "azure": {"aws"}},
+			wantErr:      "dependency cycle between aws, azure",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := utils.DependencyOrder(tt.names, tt.dependencies)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"slices"
 	"strings"
 
@@ -31,7 +32,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/cost"
 	providersloader "github.com/K0rdent/kcm/internal/providers"
+	"github.com/K0rdent/kcm/internal/quota"
 )
 
 type ClusterDeploymentValidator struct {
@@ -90,7 +93,11 @@ func (v *ClusterDeploymentValidator) ValidateCreate(ctx context.Context, obj run
 		return nil, fmt.Errorf("%s: %w", invalidClusterDeploymentMsg, err)
 	}
 
-	return nil, nil
+	if err := v.validateClusterQuotas(ctx, clusterDeployment); err != nil {
+		return nil, fmt.Errorf("%s: %w", invalidClusterDeploymentMsg, err)
+	}
+
+	return append(v.cloudQuotaWarnings(ctx, clusterDeployment), v.costWarnings(ctx, clusterDeployment, template)...), nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -138,7 +145,20 @@ func (v *ClusterDeploymentValidator) ValidateUpdate(ctx context.Context, oldObj,
 		return nil, fmt.Errorf("%s: %w", invalidClusterDeploymentMsg, err)
 	}
 
-	return nil, nil
+	var warnings admission.Warnings
+	// do not block unrelated updates (e.g. of metadata) of the already admitted objects
+	if oldClusterDeployment.Spec.DryRun || !reflect.DeepEqual(oldClusterDeployment.Spec.Config, newClusterDeployment.Spec.Config) {
+		// the already deployed objects are allowed to exceed the quotas lowered
+		// after their admission unless they consume more than before
+		if oldClusterDeployment.Spec.DryRun || usageIncreases(oldClusterDeployment, newClusterDeployment) {
+			if err := v.validateClusterQuotas(ctx, newClusterDeployment); err != nil {
+				return nil, fmt.Errorf("%s: %w", invalidClusterDeploymentMsg, err)
+			}
+		}
+		warnings = v.costWarnings(ctx, newClusterDeployment, template)
+	}
+
+	return warnings, nil
 }
 
 func validateK8sCompatibility(ctx context.Context, cl client.Client, template *kcmv1.ClusterTemplate, mc *kcmv1.ClusterDeployment) error {
@@ -306,6 +326,106 @@ func isCredMatchTemplate(cred *kcmv1.Credential, template *kcmv1.ClusterTemplate
 	return nil
 }
 
+// validateClusterQuotas checks that the given ClusterDeployment fits into
+// all of the ClusterQuotas defined in its namespace.
+func (v *ClusterDeploymentValidator) validateClusterQuotas(ctx context.Context, clusterDeployment *kcmv1.ClusterDeployment) error {
+	if clusterDeployment.Spec.DryRun {
+		return nil // dry-run objects do not consume quotas
+	}
+
+	quotas := new(kcmv1.ClusterQuotaList)
+	if err := v.List(ctx, quotas, client.InNamespace(clusterDeployment.Namespace)); err != nil {
+		return fmt.Errorf("failed to list ClusterQuotas: %w", err)
+	}
+
+	if len(quotas.Items) == 0 {
+		return nil
+	}
+
+	clusterDeployments := new(kcmv1.ClusterDeploymentList)
+	if err := v.List(ctx, clusterDeployments, client.InNamespace(clusterDeployment.Namespace)); err != nil {
+		return fmt.Errorf("failed to list ClusterDeployments: %w", err)
+	}
+
+	var errs error
+	for i := range quotas.Items {
+		errs = errors.Join(errs, quota.Check(&quotas.Items[i], clusterDeployment, clusterDeployments.Items))
+	}
+
+	return errs
+}
+
+// usageIncreases reports whether the updated ClusterDeployment consumes more
+// of the quotas than its previous version. The usage is considered increased
+// if it cannot be computed, so the invalid configuration is reported by the
+// quotas check.
+func usageIncreases(oldClusterDeployment, newClusterDeployment *kcmv1.ClusterDeployment) bool {
+	oldUsage, err := quota.UsageOf(oldClusterDeployment)
+	if err != nil {
+		return true
+	}
+	newUsage, err := quota.UsageOf(newClusterDeployment)
+	if err != nil {
+		return true
+	}
+	return newUsage.Exceeds(oldUsage)
+}
+
+// cloudQuotaWarnings returns the warnings about the cloud quotas of the
+// Credential the ClusterDeployment is likely to exceed. The quotas are
+// taken from the Management status and are not checked if not collected.
+func (v *ClusterDeploymentValidator) cloudQuotaWarnings(ctx context.Context, clusterDeployment *kcmv1.ClusterDeployment) admission.Warnings {
+	if clusterDeployment.Spec.DryRun || clusterDeployment.Spec.Credential == "" {
+		return nil
+	}
+
+	mgmt := new(kcmv1.Management)
+	if err := v.Get(ctx, client.ObjectKey{Name: kcmv1.ManagementName}, mgmt); err != nil {
+		ctrl.LoggerFrom(ctx).V(1).Info("Failed to get Management, skipping the cloud quotas check", "err", err.Error())
+		return nil
+	}
+
+	credential := client.ObjectKey{Namespace: clusterDeployment.Namespace, Name: clusterDeployment.Spec.Credential}.String()
+	idx := slices.IndexFunc(mgmt.Status.CloudQuotas, func(q kcmv1.CredentialCloudQuotas) bool { return q.Credential == credential })
+	if idx < 0 {
+		return nil
+	}
+
+	usage, err := quota.UsageOf(clusterDeployment)
+	if err != nil {
+		return nil
+	}
+
+	return quota.CloudQuotaWarnings(credential, usage, mgmt.Status.CloudQuotas[idx].Quotas)
+}
+
+// costWarnings returns the warning about the estimated monthly cost of the
+// ClusterDeployment exceeding the budget defined in the Management.
+func (v *ClusterDeploymentValidator) costWarnings(ctx context.Context, clusterDeployment *kcmv1.ClusterDeployment, template *kcmv1.ClusterTemplate) admission.Warnings {
+	if clusterDeployment.Spec.DryRun {
+		return nil
+	}
+
+	mgmt := new(kcmv1.Management)
+	if err := v.Get(ctx, client.ObjectKey{Name: kcmv1.ManagementName}, mgmt); err != nil {
+		ctrl.LoggerFrom(ctx).V(1).Info("Failed to get Management, skipping the cost estimation", "err", err.Error())
+		return nil
+	}
+	if mgmt.Spec.CostEstimation == nil {
+		return nil
+	}
+
+	estimate, err := cost.Estimate(mgmt.Spec.CostEstimation, clusterDeployment, template.Status.Providers)
+	if err != nil {
+		return nil
+	}
+
+	if warning := cost.BudgetWarning(mgmt.Spec.CostEstimation, estimate); warning != "" {
+		return admission.Warnings{warning}
+	}
+	return nil
+}
+
 func ValidateCrossNamespaceRefs(ctx context.Context, namespace string, serviceSpec *kcmv1.ServiceSpec) (errs error) {
 	l := ctrl.LoggerFrom(ctx)
 
@@ -28,6 +28,7 @@ import (
 
 	"github.com/K0rdent/kcm/api/v1alpha1"
 	"github.com/K0rdent/kcm/test/objects/clusterdeployment"
+	"github.com/K0rdent/kcm/test/objects/clusterquota"
 	"github.com/K0rdent/kcm/test/objects/credential"
 	"github.com/K0rdent/kcm/test/objects/management"
 	"github.com/K0rdent/kcm/test/objects/template"
@@ -283,6 +284,127 @@ func TestClusterDeploymentValidateCreate(t *testing.T) {
 				),
 			},
 		},
+		{
+			name: "should fail if the ClusterQuota is exceeded",
+			ClusterDeployment: clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithClusterTemplate(testTemplateName),
+				clusterdeployment.WithCredential(testCredentialName),
+				clusterdeployment.WithConfig(`{"workersNumber":2}`),
+			),
+			existingObjects: []runtime.Object{
+				mgmt,
+				cred,
+				template.NewClusterTemplate(
+					template.WithName(testTemplateName),
+					template.WithProvidersStatus(
+						"infrastructure-aws",
+						"control-plane-k0smotron",
+						"bootstrap-k0smotron",
+					),
+					template.WithValidationStatus(v1alpha1.TemplateValidationStatus{Valid: true}),
+				),
+				clusterdeployment.NewClusterDeployment(
+					clusterdeployment.WithName("existing"),
+					clusterdeployment.WithConfig(`{"workersNumber":2}`),
+				),
+				clusterquota.NewClusterQuota(
+					clusterquota.WithMaxClusterDeployments(2),
+					clusterquota.WithMaxNodes(3),
+				),
+			},
+			err: "the ClusterDeployment is invalid: ClusterQuota default/clusterquota is exceeded: the total number of nodes 4 exceeds the limit of 3",
+		},
+		{
+			name: "should succeed if the ClusterQuota is not exceeded",
+			ClusterDeployment: clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithClusterTemplate(testTemplateName),
+				clusterdeployment.WithCredential(testCredentialName),
+				clusterdeployment.WithConfig(`{"workersNumber":1,"worker":{"instanceType":"t3.small"}}`),
+			),
+			existingObjects: []runtime.Object{
+				mgmt,
+				cred,
+				template.NewClusterTemplate(
+					template.WithName(testTemplateName),
+					template.WithProvidersStatus(
+						"infrastructure-aws",
+						"control-plane-k0smotron",
+						"bootstrap-k0smotron",
+					),
+					template.WithValidationStatus(v1alpha1.TemplateValidationStatus{Valid: true}),
+				),
+				clusterdeployment.NewClusterDeployment(
+					clusterdeployment.WithName("existing"),
+					clusterdeployment.WithConfig(`{"workersNumber":2}`),
+				),
+				clusterquota.NewClusterQuota(
+					clusterquota.WithMaxClusterDeployments(2),
+					clusterquota.WithMaxNodes(3),
+					clusterquota.WithAllowedInstanceTypes("t3.*"),
+				),
+			},
+		},
+		{
+			name: "should warn if the cloud quota of the credential is likely exceeded",
+			ClusterDeployment: clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithClusterTemplate(testTemplateName),
+				clusterdeployment.WithCredential(testCredentialName),
+				clusterdeployment.WithConfig(`{"controlPlaneNumber":1,"workersNumber":3}`),
+			),
+			existingObjects: []runtime.Object{
+				management.NewManagement(
+					management.WithAvailableProviders(mgmt.Status.AvailableProviders),
+					management.WithCloudQuotas(v1alpha1.CredentialCloudQuotas{
+						Credential: clusterdeployment.DefaultNamespace + "/" + testCredentialName,
+						Provider:   "openstack",
+						Quotas: []v1alpha1.CloudQuota{
+							{Resource: v1alpha1.CloudQuotaInstances, Limit: 10, Used: 7, Remaining: 3},
+							{Resource: v1alpha1.CloudQuotaLoadBalancers, Limit: 5, Used: 1, Remaining: 4},
+						},
+					}),
+				),
+				cred,
+				template.NewClusterTemplate(
+					template.WithName(testTemplateName),
+					template.WithProvidersStatus(
+						"infrastructure-aws",
+						"control-plane-k0smotron",
+						"bootstrap-k0smotron",
+					),
+					template.WithValidationStatus(v1alpha1.TemplateValidationStatus{Valid: true}),
+				),
+			},
+			warnings: admission.Warnings{"the cluster of 4 nodes likely exceeds the instances quota of the Credential default/cred-test: 3 of 10 left"},
+		},
+		{
+			name: "should warn if the estimated cost exceeds the budget",
+			ClusterDeployment: clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithClusterTemplate(testTemplateName),
+				clusterdeployment.WithCredential(testCredentialName),
+				clusterdeployment.WithConfig(`{"controlPlaneNumber":1,"workersNumber":3,"controlPlane":{"instanceType":"t3.large"},"worker":{"instanceType":"t3.large"}}`),
+			),
+			existingObjects: []runtime.Object{
+				management.NewManagement(
+					management.WithAvailableProviders(mgmt.Status.AvailableProviders),
+					management.WithCostEstimation(&v1alpha1.CostEstimation{
+						MonthlyBudget: "200",
+						Currency:      "USD",
+						PriceLists:    []v1alpha1.PriceList{{Provider: "aws", HourlyPrices: map[string]string{"t3.large": "0.0832"}}},
+					}),
+				),
+				cred,
+				template.NewClusterTemplate(
+					template.WithName(testTemplateName),
+					template.WithProvidersStatus(
+						"infrastructure-aws",
+						"control-plane-k0smotron",
+						"bootstrap-k0smotron",
+					),
+					template.WithValidationStatus(v1alpha1.TemplateValidationStatus{Valid: true}),
+				),
+			},
+			warnings: admission.Warnings{"The estimated monthly cost of the ClusterDeployment 242.94 USD exceeds the budget of 200 USD"},
+		},
 		{
 			name: "cluster template k8s version does not satisfy service template constraints",
 			ClusterDeployment: clusterdeployment.NewClusterDeployment(
@@ -583,6 +705,90 @@ func TestClusterDeploymentValidateUpdate(t *testing.T) {
 				),
 			},
 		},
+		{
+			name: "should succeed if the ClusterQuota is exceeded but the config is not changed",
+			oldClusterDeployment: clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithClusterTemplate(testTemplateName),
+				clusterdeployment.WithConfig(`{"workersNumber":2}`),
+				clusterdeployment.WithCredential(testCredentialName),
+			),
+			newClusterDeployment: clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithClusterTemplate(testTemplateName),
+				clusterdeployment.WithConfig(`{"workersNumber":2}`),
+				clusterdeployment.WithCredential(testCredentialName),
+				clusterdeployment.WithServiceTemplate(testSvcTemplate1Name),
+			),
+			existingObjects: []runtime.Object{
+				mgmt,
+				cred,
+				template.NewClusterTemplate(
+					template.WithName(testTemplateName),
+					template.WithProvidersStatus(
+						"infrastructure-aws",
+						"control-plane-k0smotron",
+						"bootstrap-k0smotron",
+					),
+				),
+				template.NewServiceTemplate(
+					template.WithName(testSvcTemplate1Name),
+					template.WithValidationStatus(v1alpha1.TemplateValidationStatus{Valid: true}),
+				),
+				clusterquota.NewClusterQuota(clusterquota.WithMaxNodes(1)),
+			},
+		},
+		{
+			name: "should succeed if the ClusterQuota is exceeded but the new config does not increase the usage",
+			oldClusterDeployment: clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithClusterTemplate(testTemplateName),
+				clusterdeployment.WithConfig(`{"workersNumber":3}`),
+				clusterdeployment.WithCredential(testCredentialName),
+			),
+			newClusterDeployment: clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithClusterTemplate(testTemplateName),
+				clusterdeployment.WithConfig(`{"workersNumber":2,"foo":"bar"}`),
+				clusterdeployment.WithCredential(testCredentialName),
+			),
+			existingObjects: []runtime.Object{
+				mgmt,
+				cred,
+				template.NewClusterTemplate(
+					template.WithName(testTemplateName),
+					template.WithProvidersStatus(
+						"infrastructure-aws",
+						"control-plane-k0smotron",
+						"bootstrap-k0smotron",
+					),
+				),
+				clusterquota.NewClusterQuota(clusterquota.WithMaxNodes(1)),
+			},
+		},
+		{
+			name: "should fail if the ClusterQuota is exceeded by the new config",
+			oldClusterDeployment: clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithClusterTemplate(testTemplateName),
+				clusterdeployment.WithConfig(`{"workersNumber":1}`),
+				clusterdeployment.WithCredential(testCredentialName),
+			),
+			newClusterDeployment: clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithClusterTemplate(testTemplateName),
+				clusterdeployment.WithConfig(`{"workersNumber":2}`),
+				clusterdeployment.WithCredential(testCredentialName),
+			),
+			existingObjects: []runtime.Object{
+				mgmt,
+				cred,
+				template.NewClusterTemplate(
+					template.WithName(testTemplateName),
+					template.WithProvidersStatus(
+						"infrastructure-aws",
+						"control-plane-k0smotron",
+						"bootstrap-k0smotron",
+					),
+				),
+				clusterquota.NewClusterQuota(clusterquota.WithMaxNodes(1)),
+			},
+			err: "the ClusterDeployment is invalid: ClusterQuota default/clusterquota is exceeded: the total number of nodes 2 exceeds the limit of 1",
+		},
 		{
 			name: "should succeed if serviceTemplates are added",
 			oldClusterDeployment: clusterdeployment.NewClusterDeployment(
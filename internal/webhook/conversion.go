@@ -0,0 +1,32 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+// ConversionPath is the path the conversion webhook of the kcm CRDs is
+// served at.
+const ConversionPath = "/convert"
+
+// SetupConversionWebhookWithManager registers the webhook converting the
+// objects between the versions of the kcm API registered in the scheme of
+// the manager. It is registered explicitly, so the kinds without the
+// admission webhooks are converted as well.
+func SetupConversionWebhookWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(ConversionPath, conversion.NewWebhookHandler(mgr.GetScheme()))
+}
@@ -0,0 +1,111 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/K0rdent/kcm/internal/metrics"
+)
+
+// slowAdmissionThreshold is the duration of the admission request after
+// which the request is logged as slow.
+const slowAdmissionThreshold = time.Second
+
+// instrumentedServer instruments the admission webhooks registered in the
+// wrapped server.
+type instrumentedServer struct {
+	webhook.Server
+}
+
+// NewInstrumentedServer wraps the server, so the admission webhooks
+// registered in it export the metrics of the handled requests and log the
+// rejected and the slow ones.
+func NewInstrumentedServer(server webhook.Server) webhook.Server {
+	return &instrumentedServer{Server: server}
+}
+
+// Register registers the webhook in the wrapped server, the handler of the
+// admission webhook is instrumented.
+func (s *instrumentedServer) Register(path string, hook http.Handler) {
+	if wh, ok := hook.(*admission.Webhook); ok && wh.Handler != nil {
+		wh.Handler = &instrumentedHandler{Handler: wh.Handler, path: path}
+	}
+	s.Server.Register(path, hook)
+}
+
+// instrumentedHandler handles the admission requests with the wrapped handler
+// and tracks the outcome of them.
+type instrumentedHandler struct {
+	admission.Handler
+
+	path string
+}
+
+func (h *instrumentedHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	start := time.Now()
+	resp := h.Handler.Handle(ctx, req)
+	duration := time.Since(start)
+
+	reason := admissionRejectionReason(resp)
+	metrics.TrackMetricWebhookAdmission(ctx, h.path, string(req.Operation), resp.Allowed, reason, duration)
+
+	l := ctrl.LoggerFrom(ctx).WithValues(
+		"kind", req.Kind.Kind,
+		"namespace", req.Namespace,
+		"name", req.Name,
+		"operation", req.Operation,
+		"user", req.UserInfo.Username,
+		"duration", duration,
+	)
+	if !resp.Allowed {
+		var message string
+		if resp.Result != nil {
+			message = resp.Result.Message
+		}
+		l.Info("Admission request rejected", "reason", reason, "message", message)
+	}
+	if duration > slowAdmissionThreshold {
+		l.Info("Admission request handled slowly", "threshold", slowAdmissionThreshold)
+	}
+
+	return resp
+}
+
+// admissionRejectionReason returns the reason of the rejection of the
+// admission request, empty if the request is allowed.
+func admissionRejectionReason(resp admission.Response) string {
+	switch {
+	case resp.Allowed:
+		return ""
+	case resp.Result == nil:
+		return string(metav1.StatusReasonUnknown)
+	case resp.Result.Reason != "":
+		return string(resp.Result.Reason)
+	case resp.Result.Code >= http.StatusInternalServerError:
+		return string(metav1.StatusReasonInternalError)
+	case resp.Result.Code == http.StatusBadRequest:
+		return string(metav1.StatusReasonBadRequest)
+	default:
+		return string(metav1.StatusReasonUnknown)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestInstrumentedServer(t *testing.T) {
+	g := NewWithT(t)
+
+	denied := apierrors.NewInvalid(schema.GroupKind{Group: "k0rdent.mirantis.com", Kind: "ClusterDeployment"}, "cd", nil)
+	wh := &admission.Webhook{Handler: admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+		return admission.ValidationResponse(false, denied.Error())
+	})}
+
+	server := NewInstrumentedServer(webhook.NewServer(webhook.Options{}))
+	server.Register("/validate", wh)
+	// the handlers other than the admission webhooks are registered as is
+	server.Register("/other", http.NotFoundHandler())
+
+	g.Expect(wh.Handler).To(BeAssignableToTypeOf(&instrumentedHandler{}))
+
+	resp := wh.Handle(t.Context(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create}})
+	g.Expect(resp.Allowed).To(BeFalse())
+	g.Expect(resp.Result.Message).To(Equal(denied.Error()))
+}
+
+func Test_admissionRejectionReason(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		resp     admission.Response
+		expected string
+	}{
+		{name: "allowed", resp: admission.Allowed(""), expected: ""},
+		{name: "denied", resp: admission.Denied("denied"), expected: string(metav1.StatusReasonForbidden)},
+		{
+			name:     "other code",
+			resp:     admission.Errored(http.StatusUnprocessableEntity, apierrors.NewInvalid(schema.GroupKind{Kind: "Management"}, "kcm", nil)),
+			expected: string(metav1.StatusReasonUnknown),
+		},
+		{
+			name: "status reason",
+			resp: admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{
+				Result: &metav1.Status{Code: http.StatusUnprocessableEntity, Reason: metav1.StatusReasonInvalid},
+			}},
+			expected: string(metav1.StatusReasonInvalid),
+		},
+		{name: "bad request", resp: admission.Errored(http.StatusBadRequest, errors.New("failed to decode")), expected: string(metav1.StatusReasonBadRequest)},
+		{name: "internal error", resp: admission.Errored(http.StatusInternalServerError, errors.New("failed")), expected: string(metav1.StatusReasonInternalError)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			NewWithT(t).Expect(admissionRejectionReason(tc.resp)).To(Equal(tc.expected))
+		})
+	}
+}
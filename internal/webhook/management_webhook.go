@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/utils"
 )
 
 type ManagementValidator struct {
@@ -65,6 +66,12 @@ func (v *ManagementValidator) ValidateCreate(ctx context.Context, obj runtime.Ob
 				field.Forbidden(field.NewPath("spec", "release"), err.Error()),
 			})
 	}
+	if err := checkProvidersDependencies(mgmt); err != nil {
+		return nil,
+			apierrors.NewInvalid(mgmt.GroupVersionKind().GroupKind(), mgmt.Name, field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "providers"), err.Error()),
+			})
+	}
 	return nil, nil
 }
 
@@ -99,6 +106,15 @@ func (v *ManagementValidator) ValidateUpdate(ctx context.Context, oldObj, newObj
 		return nil, fmt.Errorf("failed to get Release %s: %w", newMgmt.Spec.Release, err)
 	}
 
+	if oldMgmt.Spec.Release != newMgmt.Spec.Release {
+		if err := checkDowngrade(ctx, v.Client, oldMgmt.Spec.Release, release, newMgmt); err != nil {
+			return nil,
+				apierrors.NewInvalid(newMgmt.GroupVersionKind().GroupKind(), newMgmt.Name, field.ErrorList{
+					field.Forbidden(field.NewPath("spec", "release"), err.Error()),
+				})
+		}
+	}
+
 	if err := checkComponentsRemoval(ctx, v.Client, release, oldMgmt, newMgmt); err != nil {
 		return admission.Warnings{"Some of the providers cannot be removed"},
 			apierrors.NewInvalid(newMgmt.GroupVersionKind().GroupKind(), newMgmt.Name, field.ErrorList{
@@ -106,6 +122,28 @@ func (v *ManagementValidator) ValidateUpdate(ctx context.Context, oldObj, newObj
 			})
 	}
 
+	if err := checkProvidersDependencies(newMgmt); err != nil {
+		return nil,
+			apierrors.NewInvalid(newMgmt.GroupVersionKind().GroupKind(), newMgmt.Name, field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "providers"), err.Error()),
+			})
+	}
+
+	pinnedWarnings, err := checkPinnedProviders(ctx, v.Client, release, newMgmt)
+	if err != nil {
+		return nil,
+			apierrors.NewInvalid(newMgmt.GroupVersionKind().GroupKind(), newMgmt.Name, field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "providers"), err.Error()),
+			})
+	}
+
+	if err := checkOptionalComponentsDisabling(ctx, v.Client, oldMgmt, newMgmt); err != nil {
+		return nil,
+			apierrors.NewInvalid(newMgmt.GroupVersionKind().GroupKind(), newMgmt.Name, field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "disabledComponents"), err.Error()),
+			})
+	}
+
 	incompatibleContracts, err := getIncompatibleContracts(ctx, v, release, newMgmt)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", invalidMgmtMsg, err)
@@ -115,13 +153,160 @@ func (v *ManagementValidator) ValidateUpdate(ctx context.Context, oldObj, newObj
 		return admission.Warnings{"The Management object has incompatible CAPI contract versions in ProviderTemplates"}, fmt.Errorf("%s: %s", invalidMgmtMsg, incompatibleContracts)
 	}
 
+	if oldMgmt.Spec.Release != newMgmt.Spec.Release {
+		// the pinned providers are kept through the Release upgrades
+		return pinnedWarnings, nil
+	}
+
 	return nil, nil
 }
 
+// checkDowngrade validates the change of the Release to the one of a lower
+// version is explicitly allowed and none of the ClusterDeployments use the
+// templates of the current Release missing in the requested one.
+func checkDowngrade(ctx context.Context, cl client.Client, currentReleaseName string, release *kcmv1.Release, mgmt *kcmv1.Management) error {
+	currentRelease := new(kcmv1.Release)
+	if err := cl.Get(ctx, client.ObjectKey{Name: currentReleaseName}, currentRelease); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get Release %s: %w", currentReleaseName, err)
+	}
+
+	if !release.IsDowngradeFrom(currentRelease) {
+		return nil
+	}
+	if mgmt.Annotations[kcmv1.AllowDowngradeAnnotation] != "true" {
+		return fmt.Errorf("the Release %s is older than the current Release %s, the downgrade must be allowed with the %s annotation",
+			release.Name, currentRelease.Name, kcmv1.AllowDowngradeAnnotation)
+	}
+
+	currentTemplates, err := releaseTemplates(ctx, cl, currentRelease.Name)
+	if err != nil {
+		return err
+	}
+	templates, err := releaseTemplates(ctx, cl, release.Name)
+	if err != nil {
+		return err
+	}
+	for name := range templates {
+		delete(currentTemplates, name)
+	}
+	if len(currentTemplates) == 0 {
+		return nil
+	}
+
+	var inUse []string
+	for name := range currentTemplates {
+		for _, indexKey := range []string{kcmv1.ClusterDeploymentTemplateIndexKey, kcmv1.ClusterDeploymentServiceTemplatesIndexKey} {
+			clusterDeployments := new(kcmv1.ClusterDeploymentList)
+			if err := cl.List(ctx, clusterDeployments, client.MatchingFields{indexKey: name}); err != nil {
+				return fmt.Errorf("failed to list ClusterDeployments: %w", err)
+			}
+
+			for _, cd := range clusterDeployments.Items {
+				inUse = append(inUse, fmt.Sprintf("%s (%s)", client.ObjectKeyFromObject(&cd), name))
+			}
+		}
+	}
+	if len(inUse) == 0 {
+		return nil
+	}
+
+	slices.Sort(inUse)
+	inUse = slices.Compact(inUse)
+	return fmt.Errorf("the ClusterDeployments use the templates missing in the Release %s: %s", release.Name, strings.Join(inUse, ", "))
+}
+
+// releaseTemplates returns the names of the ClusterTemplates and of the
+// ServiceTemplates installed with the Release of the given name.
+func releaseTemplates(ctx context.Context, cl client.Client, releaseName string) (map[string]struct{}, error) {
+	selector := client.MatchingLabels{kcmv1.FluxHelmChartNameKey: utils.TemplatesChartFromReleaseName(releaseName)}
+
+	clusterTemplates := new(kcmv1.ClusterTemplateList)
+	if err := cl.List(ctx, clusterTemplates, selector); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterTemplates: %w", err)
+	}
+	serviceTemplates := new(kcmv1.ServiceTemplateList)
+	if err := cl.List(ctx, serviceTemplates, selector); err != nil {
+		return nil, fmt.Errorf("failed to list ServiceTemplates: %w", err)
+	}
+
+	templates := make(map[string]struct{}, len(clusterTemplates.Items)+len(serviceTemplates.Items))
+	for _, tpl := range clusterTemplates.Items {
+		templates[tpl.Name] = struct{}{}
+	}
+	for _, tpl := range serviceTemplates.Items {
+		templates[tpl.Name] = struct{}{}
+	}
+	return templates, nil
+}
+
+// checkProvidersDependencies validates the dependencies of the enabled providers
+// reference the other enabled providers and are not cyclic.
+func checkProvidersDependencies(mgmt *kcmv1.Management) error {
+	names := []string{kcmv1.CoreKCMName, kcmv1.CoreCAPIName}
+	dependencies := make(map[string][]string)
+	for _, p := range mgmt.Spec.Providers {
+		if p.Disabled {
+			continue
+		}
+		names = append(names, p.Name)
+		dependencies[p.Name] = p.DependsOn
+	}
+
+	_, err := utils.DependencyOrder(names, dependencies)
+	return err
+}
+
+// checkPinnedProviders validates the providers pinned to the ProviderTemplates other
+// than the defaults of the Release are valid and provide the same providers as
+// the defaults. The warnings list the pinned providers.
+func checkPinnedProviders(ctx context.Context, cl client.Client, release *kcmv1.Release, mgmt *kcmv1.Management) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	for _, p := range mgmt.Spec.Providers {
+		defaultTplName := release.ProviderTemplate(p.Name)
+		if p.Disabled || p.Template == "" || defaultTplName == "" || p.Template == defaultTplName {
+			continue
+		}
+
+		pinnedTpl := new(kcmv1.ProviderTemplate)
+		if err := cl.Get(ctx, client.ObjectKey{Name: p.Template}, pinnedTpl); err != nil {
+			return nil, fmt.Errorf("failed to get ProviderTemplate %s pinned for the provider %s: %w", p.Template, p.Name, err)
+		}
+		if !pinnedTpl.Status.Valid {
+			return nil, fmt.Errorf("ProviderTemplate %s pinned for the provider %s is not valid", p.Template, p.Name)
+		}
+
+		defaultTpl := new(kcmv1.ProviderTemplate)
+		if err := cl.Get(ctx, client.ObjectKey{Name: defaultTplName}, defaultTpl); err != nil {
+			return nil, fmt.Errorf("failed to get ProviderTemplate %s: %w", defaultTplName, err)
+		}
+
+		var missingProviders []string
+		for _, provider := range defaultTpl.Status.Providers {
+			if !slices.Contains(pinnedTpl.Status.Providers, provider) {
+				missingProviders = append(missingProviders, provider)
+			}
+		}
+		if len(missingProviders) > 0 {
+			return nil, fmt.Errorf("ProviderTemplate %s pinned for the provider %s does not provide %s", p.Template, p.Name, strings.Join(missingProviders, ", "))
+		}
+
+		warnings = append(warnings, fmt.Sprintf("The provider %s is pinned to the ProviderTemplate %s instead of %s of the Release %s", p.Name, p.Template, defaultTplName, release.Name))
+	}
+
+	return warnings, nil
+}
+
 func checkComponentsRemoval(ctx context.Context, cl client.Client, release *kcmv1.Release, oldMgmt, newMgmt *kcmv1.Management) error {
 	removedComponents := []kcmv1.Provider{}
 	for _, oldComp := range oldMgmt.Spec.Providers {
-		if !slices.ContainsFunc(newMgmt.Spec.Providers, func(newComp kcmv1.Provider) bool { return oldComp.Name == newComp.Name }) {
+		if oldComp.Disabled {
+			continue
+		}
+		// disabled providers are uninstalled, hence are treated as the removed ones
+		if !slices.ContainsFunc(newMgmt.Spec.Providers, func(newComp kcmv1.Provider) bool { return oldComp.Name == newComp.Name && !newComp.Disabled }) {
 			removedComponents = append(removedComponents, oldComp)
 		}
 	}
@@ -174,6 +359,31 @@ func checkComponentsRemoval(ctx context.Context, cl client.Client, release *kcmv
 	}
 }
 
+func checkOptionalComponentsDisabling(ctx context.Context, cl client.Client, oldMgmt, newMgmt *kcmv1.Management) error {
+	if len(newMgmt.Spec.Backups) > 0 && slices.Contains(newMgmt.Spec.DisabledComponents, kcmv1.OptionalComponentVelero) {
+		return fmt.Errorf("component %s is required by the backup schedules and cannot be disabled while spec.backups is set", kcmv1.OptionalComponentVelero)
+	}
+
+	for _, c := range newMgmt.Spec.DisabledComponents {
+		if slices.Contains(oldMgmt.Spec.DisabledComponents, c) {
+			continue
+		}
+
+		switch c {
+		case kcmv1.OptionalComponentVelero:
+			backups := new(kcmv1.ManagementBackupList)
+			if err := cl.List(ctx, backups, client.Limit(1)); err != nil {
+				return fmt.Errorf("failed to list ManagementBackups: %w", err)
+			}
+			if len(backups.Items) > 0 {
+				return fmt.Errorf("component %s is required by the ManagementBackups and cannot be disabled until all of them are removed", c)
+			}
+		}
+	}
+
+	return nil
+}
+
 func getIncompatibleContracts(ctx context.Context, cl client.Client, release *kcmv1.Release, mgmt *kcmv1.Management) (string, error) {
 	capiTplName := release.Spec.CAPI.Template
 	if mgmt.Spec.Core != nil && mgmt.Spec.Core.CAPI.Template != "" {
@@ -191,6 +401,10 @@ func getIncompatibleContracts(ctx context.Context, cl client.Client, release *kc
 
 	incompatibleContracts := strings.Builder{}
 	for _, p := range mgmt.Spec.Providers {
+		if p.Disabled {
+			continue
+		}
+
 		tplName := p.Template
 		if tplName == "" {
 			tplName = release.ProviderTemplate(p.Name)
@@ -20,11 +20,13 @@ import (
 
 	. "github.com/onsi/gomega"
 	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/utils"
 	"github.com/K0rdent/kcm/test/objects/clusterdeployment"
 	"github.com/K0rdent/kcm/test/objects/management"
 	"github.com/K0rdent/kcm/test/objects/release"
@@ -104,8 +106,9 @@ func TestManagementValidateUpdate(t *testing.T) {
 		bootstrapK0smotronProvider = "bootstrap-k0sproject-k0smotron"
 		k0smotronTemplateName      = "k0smotron-0-0-7"
 
-		awsProviderTemplateName = "cluster-api-provider-aws-0-0-4"
-		awsClusterTemplateName  = "aws-standalone-cp-0-0-5"
+		awsProviderTemplateName       = "cluster-api-provider-aws-0-0-4"
+		awsPinnedProviderTemplateName = "cluster-api-provider-aws-0-0-5"
+		awsClusterTemplateName        = "aws-standalone-cp-0-0-5"
 	)
 
 	validStatus := v1alpha1.TemplateValidationStatus{Valid: true}
@@ -117,6 +120,21 @@ func TestManagementValidateUpdate(t *testing.T) {
 		},
 	}
 
+	componentAwsDisabled := *componentAwsDefaultTpl.DeepCopy()
+	componentAwsDisabled.Disabled = true
+
+	componentAwsPinnedTpl := v1alpha1.Provider{
+		Name: "cluster-api-provider-aws",
+		Component: v1alpha1.Component{
+			Template: awsPinnedProviderTemplateName,
+		},
+	}
+
+	releaseAwsProvider := v1alpha1.NamedProviderTemplate{
+		Name:                 "cluster-api-provider-aws",
+		CoreProviderTemplate: v1alpha1.CoreProviderTemplate{Template: awsProviderTemplateName},
+	}
+
 	componentK0smotronDefaultTpl := v1alpha1.Provider{
 		Name: "k0smotron",
 		Component: v1alpha1.Component{
@@ -124,6 +142,14 @@ func TestManagementValidateUpdate(t *testing.T) {
 		},
 	}
 
+	componentK0smotronDependsOnAws := *componentK0smotronDefaultTpl.DeepCopy()
+	componentK0smotronDependsOnAws.DependsOn = []string{componentAwsDefaultTpl.Name}
+
+	const newerReleaseName = "release-test-0-0-2"
+
+	downgradedMgmt := management.NewManagement(management.WithRelease(release.DefaultName))
+	downgradedMgmt.Annotations = map[string]string{v1alpha1.AllowDowngradeAnnotation: "true"}
+
 	tests := []struct {
 		name            string
 		oldMgmt         *v1alpha1.Management
@@ -238,6 +264,169 @@ func TestManagementValidateUpdate(t *testing.T) {
 				clusterdeployment.NewClusterDeployment(clusterdeployment.WithClusterTemplate(template.DefaultName)),
 			},
 		},
+		{
+			name: "managed cluster uses the disabled provider, should fail",
+			oldMgmt: management.NewManagement(
+				management.WithProviders(componentAwsDefaultTpl),
+			),
+			management: management.NewManagement(
+				management.WithProviders(componentAwsDisabled),
+				management.WithRelease(release.DefaultName),
+			),
+			existingObjects: []runtime.Object{
+				release.New(),
+				template.NewProviderTemplate(template.WithName(awsProviderTemplateName), template.WithProvidersStatus(infraAWSProvider)),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+				template.NewClusterTemplate(template.WithProvidersStatus(infraAWSProvider)),
+				clusterdeployment.NewClusterDeployment(clusterdeployment.WithClusterTemplate(template.DefaultName)),
+			},
+			warnings: admission.Warnings{"Some of the providers cannot be removed"},
+			err:      fmt.Sprintf(`Management "%s" is invalid: spec.providers: Forbidden: provider %s is required by at least one ClusterDeployment and cannot be removed from the Management %s`, management.DefaultName, infraAWSProvider, management.DefaultName),
+		},
+		{
+			name: "managed cluster uses the already disabled provider, should succeed",
+			oldMgmt: management.NewManagement(
+				management.WithProviders(componentAwsDisabled),
+			),
+			management: management.NewManagement(
+				management.WithProviders(componentAwsDisabled),
+				management.WithRelease(release.DefaultName),
+			),
+			existingObjects: []runtime.Object{
+				release.New(),
+				template.NewProviderTemplate(template.WithName(awsProviderTemplateName), template.WithProvidersStatus(infraAWSProvider)),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+				template.NewClusterTemplate(template.WithProvidersStatus(infraAWSProvider)),
+				clusterdeployment.NewClusterDeployment(clusterdeployment.WithClusterTemplate(template.DefaultName)),
+			},
+		},
+		{
+			name: "provider depends on the disabled provider, should fail",
+			oldMgmt: management.NewManagement(
+				management.WithProviders(componentAwsDefaultTpl, componentK0smotronDefaultTpl),
+			),
+			management: management.NewManagement(
+				management.WithProviders(componentAwsDisabled, componentK0smotronDependsOnAws),
+				management.WithRelease(release.DefaultName),
+			),
+			existingObjects: []runtime.Object{
+				release.New(),
+				template.NewProviderTemplate(template.WithName(awsProviderTemplateName), template.WithProvidersStatus(infraAWSProvider)),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+			},
+			err: fmt.Sprintf(`Management "%s" is invalid: spec.providers: Forbidden: k0smotron depends on cluster-api-provider-aws which is not installed`, management.DefaultName),
+		},
+		{
+			name:       "downgrade is not allowed, should fail",
+			oldMgmt:    management.NewManagement(management.WithRelease(newerReleaseName)),
+			management: management.NewManagement(management.WithRelease(release.DefaultName)),
+			existingObjects: []runtime.Object{
+				release.New(release.WithVersion("0.0.1")),
+				release.New(release.WithName(newerReleaseName), release.WithVersion("0.0.2")),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+			},
+			err: fmt.Sprintf(`Management "%s" is invalid: spec.release: Forbidden: the Release %s is older than the current Release %s, the downgrade must be allowed with the %s annotation`,
+				management.DefaultName, release.DefaultName, newerReleaseName, v1alpha1.AllowDowngradeAnnotation),
+		},
+		{
+			name:       "downgrade while cluster deployments use the templates of the newer release, should fail",
+			oldMgmt:    management.NewManagement(management.WithRelease(newerReleaseName)),
+			management: downgradedMgmt,
+			existingObjects: []runtime.Object{
+				release.New(release.WithVersion("0.0.1")),
+				release.New(release.WithName(newerReleaseName), release.WithVersion("0.0.2")),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+				template.NewClusterTemplate(
+					template.WithName("aws-standalone-cp-0-0-1"),
+					template.WithLabels(map[string]string{v1alpha1.FluxHelmChartNameKey: utils.TemplatesChartFromReleaseName(release.DefaultName)}),
+				),
+				template.NewClusterTemplate(
+					template.WithName("aws-standalone-cp-0-0-1"),
+					template.WithNamespace(metav1.NamespaceSystem),
+					template.WithLabels(map[string]string{v1alpha1.FluxHelmChartNameKey: utils.TemplatesChartFromReleaseName(newerReleaseName)}),
+				),
+				template.NewClusterTemplate(
+					template.WithName("aws-standalone-cp-0-0-2"),
+					template.WithLabels(map[string]string{v1alpha1.FluxHelmChartNameKey: utils.TemplatesChartFromReleaseName(newerReleaseName)}),
+				),
+				clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("old"), clusterdeployment.WithClusterTemplate("aws-standalone-cp-0-0-1")),
+				clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("new"), clusterdeployment.WithClusterTemplate("aws-standalone-cp-0-0-2")),
+			},
+			err: fmt.Sprintf(`Management "%s" is invalid: spec.release: Forbidden: the ClusterDeployments use the templates missing in the Release %s: %s/new (aws-standalone-cp-0-0-2)`,
+				management.DefaultName, release.DefaultName, clusterdeployment.DefaultNamespace),
+		},
+		{
+			name:       "downgrade while cluster deployments use the templates of the newer release as the cluster and the service templates, should fail listing each of them once",
+			oldMgmt:    management.NewManagement(management.WithRelease(newerReleaseName)),
+			management: downgradedMgmt,
+			existingObjects: []runtime.Object{
+				release.New(release.WithVersion("0.0.1")),
+				release.New(release.WithName(newerReleaseName), release.WithVersion("0.0.2")),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+				template.NewClusterTemplate(
+					template.WithName("shared-0-0-2"),
+					template.WithLabels(map[string]string{v1alpha1.FluxHelmChartNameKey: utils.TemplatesChartFromReleaseName(newerReleaseName)}),
+				),
+				template.NewServiceTemplate(
+					template.WithName("shared-0-0-2"),
+					template.WithLabels(map[string]string{v1alpha1.FluxHelmChartNameKey: utils.TemplatesChartFromReleaseName(newerReleaseName)}),
+				),
+				clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("a"), clusterdeployment.WithClusterTemplate("shared-0-0-2"), clusterdeployment.WithServiceTemplate("shared-0-0-2")),
+				clusterdeployment.NewClusterDeployment(clusterdeployment.WithName("b"), clusterdeployment.WithClusterTemplate("shared-0-0-2")),
+			},
+			err: fmt.Sprintf(`Management "%s" is invalid: spec.release: Forbidden: the ClusterDeployments use the templates missing in the Release %s: %s/a (shared-0-0-2), %s/b (shared-0-0-2)`,
+				management.DefaultName, release.DefaultName, clusterdeployment.DefaultNamespace, clusterdeployment.DefaultNamespace),
+		},
+		{
+			name:       "allowed downgrade, should succeed",
+			oldMgmt:    management.NewManagement(management.WithRelease(newerReleaseName)),
+			management: downgradedMgmt,
+			existingObjects: []runtime.Object{
+				release.New(release.WithVersion("0.0.1")),
+				release.New(release.WithName(newerReleaseName), release.WithVersion("0.0.2")),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+			},
+		},
+		{
+			name:    "velero is disabled while management backups exist, should fail",
+			oldMgmt: management.NewManagement(),
+			management: management.NewManagement(
+				management.WithRelease(release.DefaultName),
+				management.WithDisabledComponents(v1alpha1.OptionalComponentVelero),
+			),
+			existingObjects: []runtime.Object{
+				release.New(),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+				&v1alpha1.ManagementBackup{ObjectMeta: metav1.ObjectMeta{Name: "backup"}},
+			},
+			err: fmt.Sprintf(`Management "%s" is invalid: spec.disabledComponents: Forbidden: component velero is required by the ManagementBackups and cannot be disabled until all of them are removed`, management.DefaultName),
+		},
+		{
+			name:    "velero is disabled while backup schedules are set, should fail",
+			oldMgmt: management.NewManagement(),
+			management: management.NewManagement(
+				management.WithRelease(release.DefaultName),
+				management.WithDisabledComponents(v1alpha1.OptionalComponentVelero),
+				management.WithBackups(v1alpha1.ManagementBackupSchedule{Name: "daily", Schedule: "@daily"}),
+			),
+			existingObjects: []runtime.Object{
+				release.New(),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+			},
+			err: fmt.Sprintf(`Management "%s" is invalid: spec.disabledComponents: Forbidden: component velero is required by the backup schedules and cannot be disabled while spec.backups is set`, management.DefaultName),
+		},
+		{
+			name:    "velero is disabled without management backups, should succeed",
+			oldMgmt: management.NewManagement(),
+			management: management.NewManagement(
+				management.WithRelease(release.DefaultName),
+				management.WithDisabledComponents(v1alpha1.OptionalComponentVelero),
+			),
+			existingObjects: []runtime.Object{
+				release.New(),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+			},
+		},
 		{
 			name:            "no capi providertemplate, should fail",
 			oldMgmt:         management.NewManagement(),
@@ -438,6 +627,69 @@ func TestManagementValidateUpdate(t *testing.T) {
 				clusterdeployment.NewClusterDeployment(clusterdeployment.WithClusterTemplate(awsClusterTemplateName)),
 			},
 		},
+		{
+			name: "provider pinned to the template of another release, should succeed with warning",
+			oldMgmt: management.NewManagement(
+				management.WithRelease("old-release"),
+				management.WithProviders(componentAwsPinnedTpl),
+			),
+			management: management.NewManagement(
+				management.WithRelease(release.DefaultName),
+				management.WithProviders(componentAwsPinnedTpl),
+			),
+			existingObjects: []runtime.Object{
+				release.New(release.WithProviders(releaseAwsProvider)),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+				template.NewProviderTemplate(
+					template.WithName(awsProviderTemplateName),
+					template.WithProvidersStatus(infraAWSProvider),
+					template.WithValidationStatus(validStatus),
+				),
+				template.NewProviderTemplate(
+					template.WithName(awsPinnedProviderTemplateName),
+					template.WithProvidersStatus(infraAWSProvider),
+					template.WithValidationStatus(validStatus),
+				),
+			},
+			warnings: admission.Warnings{fmt.Sprintf("The provider cluster-api-provider-aws is pinned to the ProviderTemplate %s instead of %s of the Release %s", awsPinnedProviderTemplateName, awsProviderTemplateName, release.DefaultName)},
+		},
+		{
+			name:    "provider pinned to the template not providing the same providers, should fail",
+			oldMgmt: management.NewManagement(),
+			management: management.NewManagement(
+				management.WithRelease(release.DefaultName),
+				management.WithProviders(componentAwsPinnedTpl),
+			),
+			existingObjects: []runtime.Object{
+				release.New(release.WithProviders(releaseAwsProvider)),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+				template.NewProviderTemplate(
+					template.WithName(awsProviderTemplateName),
+					template.WithProvidersStatus(infraAWSProvider),
+					template.WithValidationStatus(validStatus),
+				),
+				template.NewProviderTemplate(
+					template.WithName(awsPinnedProviderTemplateName),
+					template.WithProvidersStatus(infraOtherProvider),
+					template.WithValidationStatus(validStatus),
+				),
+			},
+			err: fmt.Sprintf(`Management "%s" is invalid: spec.providers: Forbidden: ProviderTemplate %s pinned for the provider cluster-api-provider-aws does not provide %s`, management.DefaultName, awsPinnedProviderTemplateName, infraAWSProvider),
+		},
+		{
+			name:    "provider pinned to the invalid template, should fail",
+			oldMgmt: management.NewManagement(),
+			management: management.NewManagement(
+				management.WithRelease(release.DefaultName),
+				management.WithProviders(componentAwsPinnedTpl),
+			),
+			existingObjects: []runtime.Object{
+				release.New(release.WithProviders(releaseAwsProvider)),
+				template.NewProviderTemplate(template.WithName(release.DefaultCAPITemplateName)),
+				template.NewProviderTemplate(template.WithName(awsPinnedProviderTemplateName)),
+			},
+			err: fmt.Sprintf(`Management "%s" is invalid: spec.providers: Forbidden: ProviderTemplate %s pinned for the provider cluster-api-provider-aws is not valid`, management.DefaultName, awsPinnedProviderTemplateName),
+		},
 		{
 			name: "release is not ready, should fail",
 			oldMgmt: management.NewManagement(
@@ -462,6 +714,7 @@ func TestManagementValidateUpdate(t *testing.T) {
 				WithRuntimeObjects(tt.existingObjects...).
 				WithIndex(&v1alpha1.ClusterTemplate{}, v1alpha1.ClusterTemplateProvidersIndexKey, v1alpha1.ExtractProvidersFromClusterTemplate).
 				WithIndex(&v1alpha1.ClusterDeployment{}, v1alpha1.ClusterDeploymentTemplateIndexKey, v1alpha1.ExtractTemplateNameFromClusterDeployment).
+				WithIndex(&v1alpha1.ClusterDeployment{}, v1alpha1.ClusterDeploymentServiceTemplatesIndexKey, v1alpha1.ExtractServiceTemplateNamesFromClusterDeployment).
 				Build()
 			validator := &ManagementValidator{Client: c}
 
@@ -0,0 +1,38 @@
+{{- define "cluster.name" -}}
+    {{- .Release.Name | trunc 63 | trimSuffix "-" }}
+{{- end }}
+
+{{- define "inmemorymachinetemplate.controlplane.name" -}}
+    {{- include "cluster.name" . }}-cp-mt
+{{- end }}
+
+{{- define "inmemorymachinetemplate.worker.name" -}}
+    {{- include "cluster.name" . }}-worker-mt
+{{- end }}
+
+{{- define "k0scontrolplane.name" -}}
+    {{- include "cluster.name" . }}-cp
+{{- end }}
+
+{{- define "k0sworkerconfigtemplate.name" -}}
+    {{- include "cluster.name" . }}-machine-config
+{{- end }}
+
+{{- define "machinedeployment.name" -}}
+    {{- include "cluster.name" . }}-md
+{{- end }}
+
+{{- define "inmemorymachine.behaviour" -}}
+vm:
+  provisioning:
+    {{- toYaml .provisioning | nindent 4 }}
+node:
+  provisioning:
+    {{- toYaml .provisioning | nindent 4 }}
+apiServer:
+  provisioning:
+    {{- toYaml .provisioning | nindent 4 }}
+etcd:
+  provisioning:
+    {{- toYaml .provisioning | nindent 4 }}
+{{- end }}
@@ -0,0 +1,29 @@
+{{/*
+The deployment of the Cluster API Operator provider manager configured with
+the global values injected by KCM into all of the Management components.
+Renders nothing if none of them are set, include it at the spec level:
+
+spec:
+  {{- include "kcm-provider-common.deployment" . }}
+*/}}
+{{- define "kcm-provider-common.deployment" }}
+{{- with .Values.global }}
+{{- if or .nodeSelector .tolerations .imagePullSecrets .resources }}
+  deployment:
+    {{- with .nodeSelector }}
+    nodeSelector: {{ toYaml . | nindent 6 }}
+    {{- end }}
+    {{- with .tolerations }}
+    tolerations: {{ toYaml . | nindent 6 }}
+    {{- end }}
+    {{- with .imagePullSecrets }}
+    imagePullSecrets: {{ toYaml . | nindent 6 }}
+    {{- end }}
+    {{- with .resources }}
+    containers:
+    - name: manager
+      resources: {{ toYaml . | nindent 8 }}
+    {{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
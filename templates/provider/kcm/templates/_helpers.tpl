@@ -102,6 +102,22 @@ The name of the webhook port. Must be no more than 15 characters
 kcm-webhook
 {{- end }}
 
+{{/*
+The image repository of the controller, the registry of the repository is
+replaced with the global image registry if set
+*/}}
+{{- define "kcm.image.repository" -}}
+{{- $repository := .Values.image.repository }}
+{{- with .Values.global.imageRegistry }}
+{{- $parts := splitList "/" $repository }}
+{{- if and (gt (len $parts) 1) (or (contains "." (first $parts)) (contains ":" (first $parts)) (eq "localhost" (first $parts))) }}
+{{- $repository = rest $parts | join "/" }}
+{{- end }}
+{{- $repository = printf "%s/%s" (trimSuffix "/" .) $repository }}
+{{- end }}
+{{- $repository }}
+{{- end }}
+
 {{- define "rbac.editorVerbs" -}}
 - create
 - delete
@@ -0,0 +1,127 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backup backs up the management cluster with the ManagementBackup
+// and restores the backups with the Velero Restore, reporting the state of
+// the objects once the wait times out.
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/e2e/wait"
+)
+
+// StorageLocation is the name of the BackupStorageLocation configured from
+// the backup storage of the Management, see config/dev/backup-storage-location.yaml.
+const StorageLocation = "kcm-default"
+
+// PhaseIs checks that the Velero object is in the given phase.
+func PhaseIs(phase string) wait.Check {
+	return func(obj *unstructured.Unstructured) error {
+		current, _, err := unstructured.NestedString(obj.Object, "status", "phase")
+		if err != nil {
+			return fmt.Errorf("failed to get phase: %w", err)
+		}
+		if current != phase {
+			return fmt.Errorf("phase is %q, expected %q", current, phase)
+		}
+		return nil
+	}
+}
+
+// StorageLocationAvailable waits for the BackupStorageLocation to become
+// available.
+func StorageLocationAvailable(ctx context.Context, cl crclient.Client, namespace string, timeout time.Duration) {
+	GinkgoHelper()
+
+	By(fmt.Sprintf("waiting for BackupStorageLocation %s to be available", StorageLocation))
+	wait.For(ctx, cl, velerov1.SchemeGroupVersion.WithKind("BackupStorageLocation"), crclient.ObjectKey{Namespace: namespace, Name: StorageLocation}, timeout,
+		PhaseIs(string(velerov1.BackupStorageLocationPhaseAvailable)))
+}
+
+// Create creates the single ManagementBackup stored in the [StorageLocation]
+// and returns the name of its Velero Backup once it has succeeded.
+func Create(ctx context.Context, cl crclient.Client, name string, timeout time.Duration) string {
+	GinkgoHelper()
+
+	By(fmt.Sprintf("creating ManagementBackup %s", name))
+	mgmtBackup := &kcmv1.ManagementBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       kcmv1.ManagementBackupSpec{StorageLocation: StorageLocation},
+	}
+	Expect(cl.Create(ctx, mgmtBackup)).To(Succeed())
+
+	By(fmt.Sprintf("waiting for ManagementBackup %s to succeed", name))
+	Eventually(ctx, func() error {
+		if err := cl.Get(ctx, crclient.ObjectKeyFromObject(mgmtBackup), mgmtBackup); err != nil {
+			return fmt.Errorf("failed to get ManagementBackup %s: %w", name, err)
+		}
+		if !mgmtBackup.IsCompleted() {
+			return fmt.Errorf("ManagementBackup %s is not completed yet, error: %q", name, mgmtBackup.Status.Error)
+		}
+		if phase := mgmtBackup.Status.LastBackup.Phase; phase != velerov1.BackupPhaseCompleted {
+			return StopTrying(fmt.Sprintf("backup %s of ManagementBackup %s has finished in the %s phase", mgmtBackup.Status.LastBackupName, name, phase))
+		}
+		return nil
+	}).WithTimeout(timeout).WithPolling(wait.PollInterval).Should(Succeed())
+
+	return mgmtBackup.Status.LastBackupName
+}
+
+// Restore restores the Velero Backup synced from the storage into all of the
+// namespaces updating the existing objects, e.g. the templates installed
+// along with kcm, and waits for the Restore to complete.
+func Restore(ctx context.Context, cl crclient.Client, namespace, backupName string, timeout time.Duration) {
+	GinkgoHelper()
+
+	By(fmt.Sprintf("waiting for Backup %s to be synced from the storage", backupName))
+	wait.For(ctx, cl, velerov1.SchemeGroupVersion.WithKind("Backup"), crclient.ObjectKey{Namespace: namespace, Name: backupName}, timeout,
+		PhaseIs(string(velerov1.BackupPhaseCompleted)))
+
+	restore := &velerov1.Restore{
+		ObjectMeta: metav1.ObjectMeta{Name: backupName, Namespace: namespace},
+		Spec: velerov1.RestoreSpec{
+			BackupName:             backupName,
+			ExistingResourcePolicy: velerov1.PolicyTypeUpdate,
+			IncludedNamespaces:     []string{"*"},
+		},
+	}
+	By(fmt.Sprintf("restoring Backup %s", backupName))
+	Expect(cl.Create(ctx, restore)).To(Succeed())
+
+	Eventually(ctx, func() error {
+		if err := cl.Get(ctx, crclient.ObjectKeyFromObject(restore), restore); err != nil {
+			return fmt.Errorf("failed to get Restore %s: %w", restore.Name, err)
+		}
+		switch restore.Status.Phase {
+		case velerov1.RestorePhaseCompleted:
+			return nil
+		case velerov1.RestorePhaseFailed, velerov1.RestorePhasePartiallyFailed, velerov1.RestorePhaseFailedValidation:
+			return StopTrying(fmt.Sprintf("Restore %s has finished in the %s phase with %d errors: %s",
+				restore.Name, restore.Status.Phase, restore.Status.Errors, restore.Status.FailureReason))
+		}
+		return fmt.Errorf("Restore %s is in the %q phase", restore.Name, restore.Status.Phase)
+	}).WithTimeout(timeout).WithPolling(wait.PollInterval).Should(Succeed())
+}
@@ -0,0 +1,227 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	internalutils "github.com/K0rdent/kcm/internal/utils"
+	"github.com/K0rdent/kcm/test/e2e/backup"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment/aws"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment/clusteridentity"
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/logs"
+	"github.com/K0rdent/kcm/test/e2e/resume"
+	"github.com/K0rdent/kcm/test/e2e/templates"
+	"github.com/K0rdent/kcm/test/e2e/wait"
+	"github.com/K0rdent/kcm/test/utils"
+)
+
+// The backup specs restore the ManagementBackup onto the recreated kind
+// management cluster. The AWS standalone cluster keeps running meanwhile and
+// has to be adopted by the new management cluster without being provisioned
+// again. The management cluster is shared by all of the specs, so the specs
+// are run serially once the rest of the specs have finished.
+var _ = Describe("Management backup and restore", Label("backup"), Serial, Ordered, func() {
+	var (
+		kc            *kubeclient.KubeClient
+		clusterName   string
+		testingConfig config.ProviderTestingConfig
+	)
+
+	BeforeAll(func() {
+		if resume.Enabled() {
+			Skip("the management cluster is recreated by the backup specs, so they cannot be resumed")
+		}
+		providerConfigs := config.Config.Providers[config.TestingProviderAWS]
+		if len(providerConfigs) == 0 {
+			Skip("the AWS provider is not configured for testing")
+		}
+		testingConfig = providerConfigs[0]
+
+		By("providing cluster identity")
+		kc = specNamespace("backup")
+		ci := clusteridentity.New(kc, clusterdeployment.ProviderAWS)
+		ci.WaitForValidCredential(kc)
+		Expect(os.Setenv(clusterdeployment.EnvVarAWSClusterIdentity, ci.IdentityName)).Should(Succeed())
+
+		By("deploying the backup storage and configuring the Management with it")
+		_, err := utils.Run(exec.Command("make", "backup-storage-deploy", "dev-backup-storage", "NAMESPACE="+internalutils.DefaultSystemNamespace))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterAll(func() {
+		if kc == nil {
+			return
+		}
+
+		if CurrentSpecReport().Failed() && cleanup() {
+			if clusterName != "" {
+				By(fmt.Sprintf("collecting the support bundle from the %s cluster", clusterName))
+				logs.SupportBundle(kc, clusterName)
+			}
+		}
+
+		if !cleanup() {
+			return
+		}
+
+		// the ClusterDeployment is deleted with the client of the current
+		// management cluster, either the original or the recreated one
+		if clusterName != "" {
+			By(fmt.Sprintf("deleting the %s ClusterDeployment", clusterName))
+			cd := &kcmv1.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: kc.Namespace}}
+			Expect(crclient.IgnoreNotFound(kc.CrClient.Delete(context.Background(), cd))).To(Succeed())
+
+			deletionValidator := clusterdeployment.NewProviderValidator(
+				templates.TemplateAWSStandaloneCP,
+				clusterName,
+				clusterdeployment.ValidationActionDelete,
+			)
+			Eventually(func() error {
+				return deletionValidator.Validate(context.Background(), kc)
+			}).WithTimeout(testingConfig.Timeouts.Deletion).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+		}
+
+		By("removing the backup storage")
+		_, err := utils.Run(exec.Command("make", "backup-storage-undeploy"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should adopt the clusters restored onto the recreated management cluster", func() {
+		ctx := context.Background()
+		GinkgoT().Setenv(clusterdeployment.EnvVarAWSInstanceType, aws.InstanceType(testingConfig.Architecture, "small"))
+
+		clusterTemplates, err := templates.GetSortedClusterTemplates(ctx, kc.CrClient, kc.Namespace)
+		Expect(err).NotTo(HaveOccurred())
+		latest := templates.FindLatestTemplatesWithType(clusterTemplates, templates.TemplateAWSStandaloneCP, 1)
+		Expect(latest).NotTo(BeEmpty(), "no %s templates are installed", templates.TemplateAWSStandaloneCP)
+
+		clusterName = clusterdeployment.GenerateClusterName("backup")
+		templateBy(templates.TemplateAWSStandaloneCP, fmt.Sprintf("creating a ClusterDeployment %s with template %s", clusterName, latest[0]))
+		cd := clusterdeployment.GetUnstructured(templates.TemplateAWSStandaloneCP, clusterName, latest[0])
+		kc.CreateClusterDeployment(ctx, cd, testingConfig.Timeouts)
+
+		templateBy(templates.TemplateAWSStandaloneCP, "waiting for infrastructure to deploy successfully")
+		deploymentValidator := clusterdeployment.NewProviderValidator(
+			templates.TemplateAWSStandaloneCP,
+			clusterName,
+			clusterdeployment.ValidationActionDeploy,
+		)
+		Eventually(func() error {
+			return deploymentValidator.Validate(ctx, kc)
+		}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+
+		machines := clusterMachines(ctx, kc, clusterName)
+		nodes := clusterNodes(ctx, kc, clusterName)
+
+		mgmtClient := kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+		backup.StorageLocationAvailable(ctx, mgmtClient.CrClient, mgmtClient.Namespace, config.Config.Timeouts.ControllersReady)
+		backupName := backup.Create(ctx, mgmtClient.CrClient, clusterName, testingConfig.Timeouts.Deployment)
+
+		By("recreating the management cluster without the default objects of kcm")
+		_, err = utils.Run(exec.Command("make", "kind-undeploy"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = utils.Run(exec.Command("make", "test-restore-apply"))
+		Expect(err).NotTo(HaveOccurred())
+
+		// the kubeconfig of the recreated cluster has been written by kind
+		kc = kubeclient.NewFromLocal(kc.Namespace).WithLabels(kc.Labels)
+		mgmtClient = kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+
+		func() {
+			timeout := config.Config.Timeouts.ControllersReady
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			wait.DeploymentsAvailable(ctx, mgmtClient.CrClient, mgmtClient.Namespace, utils.KCMControllerLabel, 1, timeout, controllerManagerName)
+		}()
+
+		By("creating the BackupStorageLocation of the backup storage")
+		_, err = utils.Run(exec.Command("make", "dev-backup-storage-location", "NAMESPACE="+internalutils.DefaultSystemNamespace))
+		Expect(err).NotTo(HaveOccurred())
+		backup.StorageLocationAvailable(ctx, mgmtClient.CrClient, mgmtClient.Namespace, config.Config.Timeouts.ControllersReady)
+
+		backup.Restore(ctx, mgmtClient.CrClient, mgmtClient.Namespace, backupName, testingConfig.Timeouts.Deployment)
+
+		By("validating that the providers are installed by the restored Management")
+		wait.ManagementReady(ctx, mgmtClient.CrClient, config.Config.Timeouts.ControllersReady)
+		waitForControllers(mgmtClient, config.Config.Timeouts.ControllersReady)
+
+		templateBy(templates.TemplateAWSStandaloneCP, "waiting for the restored ClusterDeployment to be adopted")
+		Eventually(func() error {
+			restored, err := kc.GetClusterDeployment(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+			if restoreName, ok := restored.GetLabels()[velerov1.RestoreNameLabel]; ok {
+				return fmt.Errorf("ClusterDeployment %s is held until Restore %s completes", clusterName, restoreName)
+			}
+			return nil
+		}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+		wait.ClusterDeploymentReady(ctx, kc.CrClient, types.NamespacedName{Namespace: kc.Namespace, Name: clusterName}, testingConfig.Timeouts.Deployment)
+		Eventually(func() error {
+			return deploymentValidator.Validate(ctx, kc)
+		}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+
+		templateBy(templates.TemplateAWSStandaloneCP, "validating that the workload cluster has not been provisioned again")
+		Expect(clusterMachines(ctx, kc, clusterName)).To(Equal(machines), "the machines of the cluster have been replaced")
+		Expect(clusterNodes(ctx, kc, clusterName)).To(Equal(nodes), "the nodes of the cluster have been replaced")
+	})
+})
+
+// clusterMachines returns the provider IDs of the machines of the cluster by
+// the names of the machines.
+func clusterMachines(ctx context.Context, kc *kubeclient.KubeClient, clusterName string) map[string]string {
+	GinkgoHelper()
+
+	machines, err := kc.ListMachines(ctx, clusterName)
+	Expect(err).NotTo(HaveOccurred())
+
+	providerIDs := make(map[string]string, len(machines))
+	for _, m := range machines {
+		providerIDs[m.GetName()], _, _ = unstructured.NestedString(m.Object, "spec", "providerID")
+	}
+	return providerIDs
+}
+
+// clusterNodes returns the UIDs of the nodes of the workload cluster by the
+// names of the nodes.
+func clusterNodes(ctx context.Context, kc *kubeclient.KubeClient, clusterName string) map[string]types.UID {
+	GinkgoHelper()
+
+	clusterClient := kc.NewFromCluster(ctx, internalutils.DefaultSystemNamespace, clusterName)
+	nodes, err := clusterClient.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	uids := make(map[string]types.UID, len(nodes.Items))
+	for _, n := range nodes.Items {
+		uids[n.Name] = n.UID
+	}
+	return uids
+}
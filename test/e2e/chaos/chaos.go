@@ -0,0 +1,144 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chaos deletes the pods of the controllers at random points of the
+// specs to prove the reconciliation converges after the controllers crash.
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+)
+
+const (
+	// EnvVarEnabled enables the chaos mode once set to any non-empty value.
+	EnvVarEnabled = "E2E_CHAOS"
+	// EnvVarInterval is the maximum interval between the deletions of the
+	// controller pods, the interval is chosen randomly between its half and
+	// the value.
+	EnvVarInterval = "E2E_CHAOS_INTERVAL"
+
+	defaultInterval = 5 * time.Minute
+
+	// reportEntryName is the name of the report entry with the number of the
+	// deleted pods.
+	reportEntryName = "chaos"
+)
+
+// Enabled reports whether the chaos mode is enabled.
+func Enabled() bool {
+	return os.Getenv(EnvVarEnabled) != ""
+}
+
+// Interval returns the maximum interval between the deletions of the pods.
+func Interval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv(EnvVarInterval))
+	if err != nil || interval <= 0 {
+		return defaultInterval
+	}
+	return interval
+}
+
+// Start starts deleting the pods of the controllers matching the selectors at
+// random intervals in the namespace of the client until the returned function
+// is called. It is a no-op unless the chaos mode is enabled.
+func Start(kc *kubeclient.KubeClient, selectors ...string) func() {
+	GinkgoHelper()
+
+	if !Enabled() {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	interval := Interval()
+
+	var (
+		wg      sync.WaitGroup
+		deleted int
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			wait := interval/2 + rand.N(interval/2+1)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			pod, err := deleteRandomPod(ctx, kc, selectors)
+			if err != nil {
+				if ctx.Err() == nil {
+					_, _ = fmt.Fprintf(GinkgoWriter, "[chaos] failed to delete controller pod: %v\n", err)
+				}
+				continue
+			}
+			deleted++
+			_, _ = fmt.Fprintf(GinkgoWriter, "[chaos] deleted controller pod %s/%s\n", kc.Namespace, pod)
+		}
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+		AddReportEntry(reportEntryName, fmt.Sprintf("%d controller pods deleted", deleted))
+	}
+}
+
+// deleteRandomPod deletes the random pod of the controllers matching the
+// selectors and returns its name.
+func deleteRandomPod(ctx context.Context, kc *kubeclient.KubeClient, selectors []string) (string, error) {
+	var pods []string
+	for _, selector := range selectors {
+		deployments, err := kc.Client.AppsV1().Deployments(kc.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return "", fmt.Errorf("failed to list deployments with %q: %w", selector, err)
+		}
+		for _, deployment := range deployments.Items {
+			podSelector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+			if err != nil {
+				return "", fmt.Errorf("invalid selector of deployment %s: %w", deployment.Name, err)
+			}
+			podList, err := kc.Client.CoreV1().Pods(kc.Namespace).List(ctx, metav1.ListOptions{LabelSelector: podSelector.String()})
+			if err != nil {
+				return "", fmt.Errorf("failed to list pods of deployment %s: %w", deployment.Name, err)
+			}
+			for _, pod := range podList.Items {
+				if pod.DeletionTimestamp.IsZero() {
+					pods = append(pods, pod.Name)
+				}
+			}
+		}
+	}
+	if len(pods) == 0 {
+		return "", fmt.Errorf("no controller pods found with %v", selectors)
+	}
+
+	pod := pods[rand.IntN(len(pods))]
+	if err := kc.Client.CoreV1().Pods(kc.Namespace).Delete(ctx, pod, metav1.DeleteOptions{}); err != nil {
+		return "", fmt.Errorf("failed to delete pod %s: %w", pod, err)
+	}
+	return pod, nil
+}
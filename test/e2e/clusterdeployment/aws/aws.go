@@ -30,9 +30,19 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
+	"github.com/K0rdent/kcm/test/e2e/config"
 	"github.com/K0rdent/kcm/test/e2e/kubeclient"
 )
 
+// InstanceType returns the burstable general purpose instance type of the
+// given size, e.g. xlarge, for the architecture.
+func InstanceType(arch config.Architecture, size string) string {
+	if arch == config.ArchitectureARM64 {
+		return "t4g." + size
+	}
+	return "t3." + size
+}
+
 // PopulateHostedTemplateVars populates the environment variables required for
 // the AWS hosted CP template by querying the standalone CP cluster with the
 // given kubeclient.
@@ -22,7 +22,6 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -117,15 +116,5 @@ func CreateDefaultStorageClass(kc *kubeclient.KubeClient) {
 		},
 	}
 
-	sc, err := kc.Client.StorageV1().StorageClasses().Get(ctx, "azure-disk", metav1.GetOptions{})
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			_, err := kc.Client.StorageV1().StorageClasses().Create(ctx, azureDiskSC, metav1.CreateOptions{})
-			Expect(err).NotTo(HaveOccurred())
-		}
-	} else {
-		azureDiskSC.SetResourceVersion(sc.GetResourceVersion())
-		_, err = kc.Client.StorageV1().StorageClasses().Update(ctx, azureDiskSC, metav1.UpdateOptions{})
-		Expect(err).NotTo(HaveOccurred())
-	}
+	kc.Apply(ctx, azureDiskSC)
 }
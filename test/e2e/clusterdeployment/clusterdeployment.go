@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/cluster-api/api/v1beta1"
 
 	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/report"
 	"github.com/K0rdent/kcm/test/e2e/templates"
 	"github.com/K0rdent/kcm/test/utils"
 )
@@ -36,11 +37,13 @@ import (
 type ProviderType string
 
 const (
-	ProviderCAPI    ProviderType = "cluster-api"
-	ProviderAWS     ProviderType = "infrastructure-aws"
-	ProviderAzure   ProviderType = "infrastructure-azure"
-	ProviderVSphere ProviderType = "infrastructure-vsphere"
-	ProviderAdopted ProviderType = "infrastructure-internal"
+	ProviderCAPI     ProviderType = "cluster-api"
+	ProviderAWS      ProviderType = "infrastructure-aws"
+	ProviderAzure    ProviderType = "infrastructure-azure"
+	ProviderVSphere  ProviderType = "infrastructure-vsphere"
+	ProviderAdopted  ProviderType = "infrastructure-internal"
+	ProviderDocker   ProviderType = "infrastructure-docker"
+	ProviderInMemory ProviderType = "infrastructure-in-memory"
 )
 
 //go:embed resources/aws-standalone-cp.yaml.tpl
@@ -67,6 +70,12 @@ var vsphereStandaloneCPClusterDeploymentTemplateBytes []byte
 //go:embed resources/vsphere-hosted-cp.yaml.tpl
 var vsphereHostedCPClusterDeploymentTemplateBytes []byte
 
+//go:embed resources/docker-hosted-cp.yaml.tpl
+var dockerHostedCPClusterDeploymentTemplateBytes []byte
+
+//go:embed resources/in-memory-standalone-cp.yaml.tpl
+var inMemoryStandaloneCPClusterDeploymentTemplateBytes []byte
+
 //go:embed resources/adopted-cluster.yaml.tpl
 var adoptedClusterDeploymentTemplateBytes []byte
 
@@ -112,6 +121,8 @@ func setTemplate(templateName string) {
 func GetUnstructured(templateType templates.Type, clusterName, template string) *unstructured.Unstructured {
 	GinkgoHelper()
 
+	report.Template(template)
+
 	setClusterName(clusterName)
 	setTemplate(template)
 
@@ -141,6 +152,10 @@ func GetUnstructured(templateType templates.Type, clusterName, template string)
 		clusterDeploymentTemplateBytes = azureStandaloneCPClusterDeploymentTemplateBytes
 	case templates.TemplateAzureAKS:
 		clusterDeploymentTemplateBytes = azureAksClusterDeploymentTemplateBytes
+	case templates.TemplateDockerHostedCP:
+		clusterDeploymentTemplateBytes = dockerHostedCPClusterDeploymentTemplateBytes
+	case templates.TemplateInMemoryStandaloneCP:
+		clusterDeploymentTemplateBytes = inMemoryStandaloneCPClusterDeploymentTemplateBytes
 	case templates.TemplateAdoptedCluster:
 		clusterDeploymentTemplateBytes = adoptedClusterDeploymentTemplateBytes
 	case templates.TemplateRemoteCluster:
@@ -23,13 +23,15 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	internalutils "github.com/K0rdent/kcm/internal/utils"
 	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
 	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/wait"
 )
 
 type ClusterIdentity struct {
@@ -41,6 +43,10 @@ type ClusterIdentity struct {
 	Spec                 map[string]any
 	Namespaced           bool
 	CredentialName       string
+	// Namespace is the namespace of the secret and of the namespaced
+	// ClusterIdentity, the Credential is created in the namespace of the
+	// KubeClient.
+	Namespace string
 }
 
 type secretData struct {
@@ -48,12 +54,28 @@ type secretData struct {
 	optional bool
 }
 
+// rotatedAtAnnotation records the time the secret of the ClusterIdentity has
+// been rotated at, so the secret is changed even if its data is not.
+const rotatedAtAnnotation = "k0rdent.mirantis.com/e2e-rotated-at"
+
 // New creates a ClusterIdentity resource, credential and associated secret for
 // the given provider using the provided KubeClient and returns details about
-// the created ClusterIdentity.
+// the created ClusterIdentity. The secret and the ClusterIdentity are created
+// in the system namespace, so they are shared by the suites run in parallel
+// processes, whereas the credential is created in the namespace of the
+// KubeClient.
 func New(kc *kubeclient.KubeClient, provider clusterdeployment.ProviderType) *ClusterIdentity {
 	GinkgoHelper()
 
+	return NewNamed(kc, provider, string(provider))
+}
+
+// NewNamed creates the ClusterIdentity as New does with the names of the
+// objects prefixed with the given name instead of the provider, so the specs
+// changing the ClusterIdentity do not affect the ones sharing the default one.
+func NewNamed(kc *kubeclient.KubeClient, provider clusterdeployment.ProviderType, name string) *ClusterIdentity {
+	GinkgoHelper()
+
 	var (
 		resource         string
 		kind             string
@@ -63,8 +85,8 @@ func New(kc *kubeclient.KubeClient, provider clusterdeployment.ProviderType) *Cl
 		namespaced       bool
 	)
 
-	secretName := fmt.Sprintf("%s-cluster-identity-secret", provider)
-	identityName := fmt.Sprintf("%s-cluster-identity", provider)
+	secretName := fmt.Sprintf("%s-cluster-identity-secret", name)
+	identityName := fmt.Sprintf("%s-cluster-identity", name)
 	group := "infrastructure.cluster.x-k8s.io"
 
 	switch provider {
@@ -121,7 +143,7 @@ func New(kc *kubeclient.KubeClient, provider clusterdeployment.ProviderType) *Cl
 			"clientID":          os.Getenv(clusterdeployment.EnvVarAzureClientID),
 			"clientSecret": map[string]any{
 				"name":      secretName,
-				"namespace": kc.Namespace,
+				"namespace": internalutils.DefaultSystemNamespace,
 			},
 			"tenantID": os.Getenv(clusterdeployment.EnvVarAzureTenantID),
 			"type":     "ServicePrincipal",
@@ -164,14 +186,15 @@ func New(kc *kubeclient.KubeClient, provider clusterdeployment.ProviderType) *Cl
 		Spec:           spec,
 		Namespaced:     namespaced,
 		CredentialName: fmt.Sprintf("%s-cred", identityName),
+		Namespace:      internalutils.DefaultSystemNamespace,
 	}
 
 	validateSecretDataPopulated(secretStringData)
-	ci.createSecret(kc)
+	ci.createSecret(kc.WithNamespace(ci.Namespace))
 
 	if provider != clusterdeployment.ProviderAdopted {
 		ci.waitForResourceCRD(kc)
-		ci.createClusterIdentity(kc)
+		ci.createClusterIdentity(kc.WithNamespace(ci.Namespace))
 	}
 	ci.createCredential(kc)
 
@@ -218,32 +241,72 @@ func (ci *ClusterIdentity) createSecret(kc *kubeclient.KubeClient) {
 
 	By(fmt.Sprintf("creating ClusterIdentity secret: %s", ci.SecretName))
 
-	ctx := context.Background()
+	// the secret can be concurrently applied by the suites run in parallel
+	// processes
+	kc.Apply(context.Background(), ci.secret(kc.Namespace))
+}
 
+func (ci *ClusterIdentity) secret(namespace string) *corev1.Secret {
 	secretData := make(map[string]string)
 	for k, v := range ci.SecretData {
 		secretData[k] = v.data
 	}
-	secret := &corev1.Secret{
+	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ci.SecretName,
-			Namespace: kc.Namespace,
+			Namespace: namespace,
 		},
 		StringData: secretData,
 		Type:       corev1.SecretTypeOpaque,
 	}
+}
+
+// Rotate rewrites the secret of the ClusterIdentity in place with the given
+// data on top of the current one, the empty values keep the current ones.
+// The secret is changed even if the data is not, so the rotation to the same
+// credentials still exercises the reconciles of the changed secret.
+func (ci *ClusterIdentity) Rotate(kc *kubeclient.KubeClient, data map[string]string) {
+	GinkgoHelper()
 
-	_, err := kc.Client.CoreV1().Secrets(kc.Namespace).Create(ctx, secret, metav1.CreateOptions{})
-	if apierrors.IsAlreadyExists(err) {
-		resp, err := kc.Client.CoreV1().Secrets(kc.Namespace).Get(ctx, ci.SecretName, metav1.GetOptions{})
-		Expect(err).NotTo(HaveOccurred(), "failed to get existing secret")
+	By(fmt.Sprintf("rotating ClusterIdentity secret: %s", ci.SecretName))
 
-		secret.SetResourceVersion(resp.GetResourceVersion())
-		_, err = kc.Client.CoreV1().Secrets(kc.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
-		Expect(err).NotTo(HaveOccurred(), "failed to update existing secret")
-	} else {
-		Expect(err).NotTo(HaveOccurred(), "failed to create secret")
+	for k, v := range data {
+		if v == "" {
+			continue
+		}
+		d := ci.SecretData[k]
+		d.data = v
+		ci.SecretData[k] = d
 	}
+	validateSecretDataPopulated(ci.SecretData)
+
+	secret := ci.secret(ci.Namespace)
+	secret.Annotations = map[string]string{rotatedAtAnnotation: time.Now().UTC().Format(time.RFC3339)}
+	kc.WithNamespace(ci.Namespace).Apply(context.Background(), secret)
+}
+
+// Delete deletes the credential, the ClusterIdentity and its secret, it is
+// meant for the ones created with NewNamed, the default ones are shared.
+func (ci *ClusterIdentity) Delete(kc *kubeclient.KubeClient) {
+	GinkgoHelper()
+
+	By(fmt.Sprintf("deleting ClusterIdentity: %s", ci.IdentityName))
+
+	ctx := context.Background()
+	cred := &unstructured.Unstructured{}
+	cred.SetGroupVersionKind(schema.GroupVersionKind{Group: "k0rdent.mirantis.com", Version: "v1alpha1", Kind: "Credential"})
+	cred.SetNamespace(kc.Namespace)
+	cred.SetName(ci.CredentialName)
+	Expect(crclient.IgnoreNotFound(kc.CrClient.Delete(ctx, cred))).To(Succeed())
+
+	// the identity of the adopted clusters is the secret itself
+	if ci.Kind != "Secret" {
+		err := kc.WithNamespace(ci.Namespace).GetDynamicClient(ci.GroupVersionResource, ci.Namespaced).Delete(ctx, ci.IdentityName, metav1.DeleteOptions{})
+		Expect(crclient.IgnoreNotFound(err)).To(Succeed(), "failed to delete %s %s", ci.Kind, ci.IdentityName)
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: ci.SecretName, Namespace: ci.Namespace}}
+	Expect(crclient.IgnoreNotFound(kc.CrClient.Delete(ctx, secret))).To(Succeed())
 }
 
 func (ci *ClusterIdentity) createCredential(kc *kubeclient.KubeClient) {
@@ -264,13 +327,14 @@ func (ci *ClusterIdentity) createCredential(kc *kubeclient.KubeClient) {
 					"apiVersion": ci.GroupVersionResource.GroupVersion().String(),
 					"kind":       ci.Kind,
 					"name":       ci.IdentityName,
-					"namespace":  kc.Namespace,
+					"namespace":  ci.Namespace,
 				},
 			},
 		},
 	}
+	kc.SetLabels(cred)
 
-	kc.CreateOrUpdateUnstructuredObject(schema.GroupVersionResource{
+	kc.ApplyUnstructuredObject(context.Background(), schema.GroupVersionResource{
 		Group:    "k0rdent.mirantis.com",
 		Version:  "v1alpha1",
 		Resource: "credentials",
@@ -295,30 +359,11 @@ func (ci *ClusterIdentity) createClusterIdentity(kc *kubeclient.KubeClient) {
 		},
 	}
 
-	kc.CreateOrUpdateUnstructuredObject(ci.GroupVersionResource, id, ci.Namespaced)
+	kc.ApplyUnstructuredObject(context.Background(), ci.GroupVersionResource, id, ci.Namespaced)
 }
 
 func (ci *ClusterIdentity) WaitForValidCredential(kc *kubeclient.KubeClient) {
 	GinkgoHelper()
 
-	By(fmt.Sprintf("waiting for %s credential to be ready", ci.CredentialName))
-
-	ctx := context.Background()
-
-	Eventually(func() error {
-		cred, err := kc.GetCredential(ctx, ci.CredentialName)
-		if err != nil {
-			return fmt.Errorf("failed to get credntial: %w", err)
-		}
-
-		ready, found, err := unstructured.NestedBool(cred.Object, "status", "ready")
-		if !found {
-			return fmt.Errorf("failed to get ready status: %w", err)
-		}
-		if !ready {
-			_, _ = fmt.Fprintf(GinkgoWriter, "credential is not ready, retrying...\n")
-			return fmt.Errorf("credential is not ready: %s", ci.GroupVersionResource.String())
-		}
-		return nil
-	}).WithTimeout(time.Minute).WithPolling(5 * time.Second).Should(Succeed())
+	wait.CredentialReady(context.Background(), kc.CrClient, crclient.ObjectKey{Namespace: kc.Namespace, Name: ci.CredentialName}, time.Minute)
 }
@@ -26,6 +26,9 @@ const (
 	// debugging of test failures.
 	EnvVarNoCleanup             = "NO_CLEANUP"
 	EnvVarManagementClusterName = "MANAGEMENT_CLUSTER_NAME"
+	// EnvVarSkipOrphanChecks disables the checks of the cloud resources left
+	// after the deletion of the clusters.
+	EnvVarSkipOrphanChecks = "E2E_SKIP_ORPHAN_CHECKS"
 
 	// AWS
 	EnvVarAWSAccessKeyID     = "AWS_ACCESS_KEY_ID"
@@ -36,11 +39,20 @@ const (
 	EnvVarAWSSecurityGroupID = "AWS_SG_ID"
 	EnvVarAWSClusterIdentity = "AWS_CLUSTER_IDENTITY"
 	EnvVarPublicIP           = "AWS_PUBLIC_IP"
+	EnvVarAWSCLI             = "AWSCLI"
+	// The credentials the secret of the AWS ClusterIdentity is rotated to,
+	// the current ones are kept if unset.
+	EnvVarAWSRotatedAccessKeyID     = "AWS_ROTATED_ACCESS_KEY_ID"
+	EnvVarAWSRotatedSecretAccessKey = "AWS_ROTATED_SECRET_ACCESS_KEY"
+	EnvVarAWSRotatedSessionToken    = "AWS_ROTATED_SESSION_TOKEN"
 
 	// VSphere
 	EnvVarVSphereUser            = "VSPHERE_USER"
 	EnvVarVSpherePassword        = "VSPHERE_PASSWORD"
 	EnvVarVSphereClusterIdentity = "VSPHERE_CLUSTER_IDENTITY"
+	EnvVarVSphereServer          = "VSPHERE_SERVER"
+	EnvVarVSphereFolder          = "VSPHERE_FOLDER"
+	EnvVarGOVC                   = "GOVC"
 
 	// Azure
 	EnvVarAzureClientSecret    = "AZURE_CLIENT_SECRET"
@@ -49,6 +61,8 @@ const (
 	EnvVarAzureSubscription    = "AZURE_SUBSCRIPTION"
 	EnvVarAzureClusterIdentity = "AZURE_CLUSTER_IDENTITY"
 	EnvVarAzureRegion          = "AZURE_REGION"
+	EnvVarAzureSubscriptionID  = "AZURE_SUBSCRIPTION_ID"
+	EnvVarAzureCLI             = "AZURE_CLI"
 
 	// Adopted
 	EnvVarAdoptedKubeconfigPath = "KUBECONFIG_DATA_PATH"
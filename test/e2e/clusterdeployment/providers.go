@@ -0,0 +1,67 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterdeployment
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	capioperatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// providerList is the list of the providers of a type managed by the CAPI
+// operator.
+type providerList interface {
+	crclient.ObjectList
+	capioperatorv1.GenericProviderList
+}
+
+// InstalledProviders returns the providers installed in the namespace by the
+// CAPI operator, sorted. The providers are returned as the values of their
+// provider label, so the controllers of the providers added to the
+// Management are found with no changes of the tests.
+func InstalledProviders(ctx context.Context, cl crclient.Client, namespace string) ([]ProviderType, error) {
+	var providers []ProviderType
+	for _, pl := range []struct {
+		list         providerList
+		providerType clusterctlv1.ProviderType
+	}{
+		{&capioperatorv1.CoreProviderList{}, clusterctlv1.CoreProviderType},
+		{&capioperatorv1.BootstrapProviderList{}, clusterctlv1.BootstrapProviderType},
+		{&capioperatorv1.ControlPlaneProviderList{}, clusterctlv1.ControlPlaneProviderType},
+		{&capioperatorv1.InfrastructureProviderList{}, clusterctlv1.InfrastructureProviderType},
+		{&capioperatorv1.IPAMProviderList{}, clusterctlv1.IPAMProviderType},
+		{&capioperatorv1.AddonProviderList{}, clusterctlv1.AddonProviderType},
+		{&capioperatorv1.RuntimeExtensionProviderList{}, clusterctlv1.RuntimeExtensionProviderType},
+	} {
+		if err := cl.List(ctx, pl.list, crclient.InNamespace(namespace)); err != nil {
+			// the CRDs of the optional provider types may be missing
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", pl.providerType, err)
+		}
+		for _, p := range pl.list.GetItems() {
+			providers = append(providers, ProviderType(clusterctlv1.ManifestLabel(p.GetName(), pl.providerType)))
+		}
+	}
+
+	slices.Sort(providers)
+	return providers, nil
+}
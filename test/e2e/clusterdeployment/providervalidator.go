@@ -75,7 +75,7 @@ func NewProviderValidator(templateType templates.Type, clusterName string, actio
 				"ccm":                        validateCCM,
 			}
 			resourceOrder = []string{"clusters", "machines", "aws-managed-control-planes", "csi-driver", "ccm"}
-		case templates.TemplateAzureStandaloneCP, templates.TemplateAzureHostedCP, templates.TemplateVSphereStandaloneCP:
+		case templates.TemplateAzureStandaloneCP, templates.TemplateAzureHostedCP, templates.TemplateVSphereStandaloneCP, templates.TemplateDockerHostedCP, templates.TemplateInMemoryStandaloneCP:
 			delete(resourcesToValidate, "csi-driver")
 		case templates.TemplateAzureAKS:
 			resourcesToValidate = map[string]resourceValidationFunc{
@@ -106,6 +106,15 @@ func NewProviderValidator(templateType templates.Type, clusterName string, actio
 				"remote-machines": validateRemoteMachines,
 			}
 		}
+
+		// the control planes of the hosted clusters run on the hosting
+		// cluster, the workers are the only nodes of the hosted clusters
+		if templates.IsHosted(templateType) {
+			resourcesToValidate["control-planes"] = validateK0smotronControlPlanes
+			resourcesToValidate["hosted-control-plane-pods"] = validateHostedControlPlanePods
+			resourcesToValidate["hosted-workers"] = validateHostedWorkers
+			resourceOrder = append(resourceOrder, "hosted-control-plane-pods", "hosted-workers")
+		}
 	} else {
 		resourcesToValidate = map[string]resourceValidationFunc{
 			"clusters":           validateClusterDeleted,
@@ -133,6 +142,24 @@ func NewProviderValidator(templateType templates.Type, clusterName string, actio
 			resourcesToValidate["control-planes"] = validateK0sControlPlanesDeleted
 			resourceOrder = append(resourceOrder, "control-planes")
 		}
+
+		// the cloud resources are deleted by the providers before the objects
+		// of the cluster are, the leftovers are orphaned for good
+		if orphanChecksEnabled() {
+			var validateCloudResources resourceValidationFunc
+			switch templateType {
+			case templates.TemplateAWSStandaloneCP, templates.TemplateAWSHostedCP, templates.TemplateAWSEKS:
+				validateCloudResources = validateAWSResourcesDeleted
+			case templates.TemplateAzureStandaloneCP, templates.TemplateAzureHostedCP:
+				validateCloudResources = validateAzureResourcesDeleted
+			case templates.TemplateVSphereStandaloneCP, templates.TemplateVSphereHostedCP:
+				validateCloudResources = validateVSphereResourcesDeleted
+			}
+			if validateCloudResources != nil {
+				resourcesToValidate["cloud-resources"] = validateCloudResources
+				resourceOrder = append(resourceOrder, "cloud-resources")
+			}
+		}
 	}
 
 	return &ProviderValidator{
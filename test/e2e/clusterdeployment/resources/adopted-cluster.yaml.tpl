@@ -2,7 +2,6 @@ apiVersion: k0rdent.mirantis.com/v1alpha1
 kind: ClusterDeployment
 metadata:
   name: ${CLUSTER_DEPLOYMENT_NAME}
-  namespace: ${NAMESPACE}
 spec:
   template: adopted-cluster-0-1-0
   credential: ${ADOPTED_CREDENTIAL}
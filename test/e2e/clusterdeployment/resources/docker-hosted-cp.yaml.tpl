@@ -0,0 +1,17 @@
+apiVersion: k0rdent.mirantis.com/v1alpha1
+kind: ClusterDeployment
+metadata:
+  name: ${CLUSTER_DEPLOYMENT_NAME}
+spec:
+  template: ${CLUSTER_DEPLOYMENT_TEMPLATE}
+  credential: docker-stub-credential
+  config:
+    workersNumber: ${WORKERS_NUMBER:=1}
+    k0smotron:
+      service:
+        type: NodePort
+  serviceSpec:
+    services:
+      - template: ingress-nginx-4-11-0
+        name: managed-ingress-nginx
+        namespace: default
@@ -0,0 +1,10 @@
+apiVersion: k0rdent.mirantis.com/v1alpha1
+kind: ClusterDeployment
+metadata:
+  name: ${CLUSTER_DEPLOYMENT_NAME}
+spec:
+  template: ${CLUSTER_DEPLOYMENT_TEMPLATE}
+  credential: in-memory-stub-credential
+  config:
+    controlPlaneNumber: ${CONTROL_PLANE_NUMBER:=1}
+    workersNumber: ${WORKERS_NUMBER:=1}
@@ -2,7 +2,6 @@ apiVersion: k0rdent.mirantis.com/v1alpha1
 kind: ClusterDeployment
 metadata:
   name: ${CLUSTER_DEPLOYMENT_NAME}
-  namespace: ${NAMESPACE}
 spec:
   template: ${CLUSTER_DEPLOYMENT_TEMPLATE}
   credential: remote-cred
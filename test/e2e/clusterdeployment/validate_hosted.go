@@ -0,0 +1,139 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterdeployment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+)
+
+// controlPlaneNodeRoleLabel is the label of the nodes running the control
+// plane components.
+const controlPlaneNodeRoleLabel = "node-role.kubernetes.io/control-plane"
+
+// validateHostedControlPlanePods validates that the control plane of the
+// hosted cluster runs as the ready pods of k0smotron on the nodes of the
+// hosting cluster, the client of which is given.
+func validateHostedControlPlanePods(ctx context.Context, kc *kubeclient.KubeClient, clusterName string) error {
+	controlPlanes, err := kc.ListK0smotronControlPlanes(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	if len(controlPlanes) == 0 {
+		return fmt.Errorf("waiting for the K0smotronControlPlane of the %s cluster to be created", clusterName)
+	}
+
+	nodes, err := kc.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes of the hosting cluster: %w", err)
+	}
+	hostingNodes := make(map[string]struct{}, len(nodes.Items))
+	for _, node := range nodes.Items {
+		hostingNodes[node.Name] = struct{}{}
+	}
+
+	var errs error
+	for _, controlPlane := range controlPlanes {
+		pods, err := kc.Client.CoreV1().Pods(controlPlane.GetNamespace()).List(ctx, metav1.ListOptions{
+			LabelSelector: "app=k0smotron,cluster=" + controlPlane.GetName(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list control plane pods of %s: %w", controlPlane.GetName(), err)
+		}
+		if len(pods.Items) == 0 {
+			errs = errors.Join(errs, fmt.Errorf("no control plane pods of %s found in the hosting cluster", controlPlane.GetName()))
+			continue
+		}
+
+		for _, pod := range pods.Items {
+			if _, ok := hostingNodes[pod.Spec.NodeName]; !ok {
+				errs = errors.Join(errs, fmt.Errorf("control plane pod %s/%s is not scheduled to the nodes of the hosting cluster", pod.Namespace, pod.Name))
+				continue
+			}
+			if !isPodReady(&pod) {
+				errs = errors.Join(errs, fmt.Errorf("control plane pod %s/%s on the %s node is not ready yet", pod.Namespace, pod.Name, pod.Spec.NodeName))
+			}
+		}
+	}
+	return errs
+}
+
+// validateHostedWorkers validates that the workers of the MachineDeployments
+// of the hosted cluster have joined it and are ready. The control plane of the
+// hosted cluster runs on the hosting cluster, so none of its nodes should be a
+// control plane one.
+func validateHostedWorkers(ctx context.Context, kc *kubeclient.KubeClient, clusterName string) error {
+	machineDeployments, err := kc.ListMachineDeployments(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	var expectedWorkers int64
+	for _, md := range machineDeployments {
+		replicas, _, err := unstructured.NestedInt64(md.Object, "spec", "replicas")
+		if err != nil {
+			return fmt.Errorf("failed to get replicas of the %s MachineDeployment: %w", md.GetName(), err)
+		}
+		expectedWorkers += replicas
+	}
+
+	hostedClient := kc.NewFromCluster(ctx, "default", clusterName)
+	nodes, err := hostedClient.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes of the %s cluster: %w", clusterName, err)
+	}
+
+	var (
+		errs  error
+		ready int64
+	)
+	for _, node := range nodes.Items {
+		if _, ok := node.Labels[controlPlaneNodeRoleLabel]; ok {
+			errs = errors.Join(errs, fmt.Errorf("node %s of the hosted cluster %s is a control plane one", node.Name, clusterName))
+			continue
+		}
+		if isNodeReady(&node) {
+			ready++
+		}
+	}
+	if ready < expectedWorkers {
+		errs = errors.Join(errs, fmt.Errorf("%d of %d workers have joined the %s cluster and are ready", ready, expectedWorkers, clusterName))
+	}
+	return errs
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
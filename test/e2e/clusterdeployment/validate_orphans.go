@@ -0,0 +1,209 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterdeployment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+)
+
+// orphanChecksEnabled reports whether the cloud resources left after the
+// deletion of the clusters are checked.
+func orphanChecksEnabled() bool {
+	skip, _ := strconv.ParseBool(os.Getenv(EnvVarSkipOrphanChecks))
+	return !skip
+}
+
+// cloudCLI returns the path of the CLI set by the env var or the default one.
+func cloudCLI(envVar, def string) string {
+	if cli := os.Getenv(envVar); cli != "" {
+		return cli
+	}
+	return def
+}
+
+// runCloudCLI runs the CLI and returns its standard output.
+func runCloudCLI(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// listCloudResources runs the CLI querying the JSON list of the IDs of the
+// resources.
+func listCloudResources(ctx context.Context, env []string, name string, args ...string) ([]string, error) {
+	out, err := runCloudCLI(ctx, env, name, args...)
+	if err != nil || len(bytes.TrimSpace(out)) == 0 {
+		return nil, err
+	}
+
+	var ids []string
+	if err := json.Unmarshal(out, &ids); err != nil {
+		return nil, fmt.Errorf("failed to parse output of %s: %w", name, err)
+	}
+	return ids, nil
+}
+
+// validateAWSResourcesDeleted validates that no instances, load balancers,
+// volumes or security groups tagged by CAPA or the cloud controller with the
+// name of the cluster remain.
+func validateAWSResourcesDeleted(ctx context.Context, _ *kubeclient.KubeClient, clusterName string) error {
+	cli := cloudCLI(EnvVarAWSCLI, "aws")
+	tagKeys := "Name=tag-key,Values=sigs.k8s.io/cluster-api-provider-aws/cluster/" + clusterName + ",kubernetes.io/cluster/" + clusterName
+
+	var errs error
+	for _, c := range []struct {
+		kind string
+		args []string
+	}{
+		{
+			kind: "instances",
+			args: []string{
+				"ec2", "describe-instances", "--filters", tagKeys,
+				"Name=instance-state-name,Values=pending,running,shutting-down,stopping,stopped",
+				"--query", "Reservations[].Instances[].InstanceId",
+			},
+		},
+		{
+			kind: "volumes",
+			args: []string{"ec2", "describe-volumes", "--filters", tagKeys, "--query", "Volumes[].VolumeId"},
+		},
+		{
+			kind: "security groups",
+			args: []string{"ec2", "describe-security-groups", "--filters", tagKeys, "--query", "SecurityGroups[].GroupId"},
+		},
+	} {
+		ids, err := listCloudResources(ctx, nil, cli, append(c.args, "--output", "json")...)
+		if err != nil {
+			return err
+		}
+		errs = errors.Join(errs, validateCloudResourcesRemoved("AWS "+c.kind, ids))
+	}
+
+	// the load balancers are not filtered by tags, so the tagging API is used
+	var lbs []string
+	for _, key := range []string{"sigs.k8s.io/cluster-api-provider-aws/cluster/" + clusterName, "kubernetes.io/cluster/" + clusterName} {
+		arns, err := listCloudResources(ctx, nil, cli, "resourcegroupstaggingapi", "get-resources",
+			"--resource-type-filters", "elasticloadbalancing:loadbalancer", "--tag-filters", "Key="+key,
+			"--query", "ResourceTagMappingList[].ResourceARN", "--output", "json")
+		if err != nil {
+			return err
+		}
+		lbs = append(lbs, arns...)
+	}
+
+	return errors.Join(errs, validateCloudResourcesRemoved("AWS load balancers", lbs))
+}
+
+var (
+	// azureConfigDir is the configuration directory of the Azure CLI logged in
+	// with the service principal of the tests, so the login of the user is
+	// left intact.
+	azureConfigDir string
+	azureLoginErr  error
+	azureLoginOnce sync.Once
+)
+
+// azureLogin logs the Azure CLI in once and returns the env vars of the CLI
+// using the login.
+func azureLogin(ctx context.Context, cli string) ([]string, error) {
+	azureLoginOnce.Do(func() {
+		azureConfigDir, azureLoginErr = os.MkdirTemp("", "kcm-e2e-azure-")
+		if azureLoginErr != nil {
+			return
+		}
+		azureLoginErr = azureLoginWithSecretFile(ctx, cli)
+	})
+	return []string{"AZURE_CONFIG_DIR=" + azureConfigDir}, azureLoginErr
+}
+
+// azureLoginWithSecretFile logs the Azure CLI in with the client secret read
+// by the CLI from a file, so the secret does not appear in the command line
+// of the process. The file is removed once the CLI is logged in.
+func azureLoginWithSecretFile(ctx context.Context, cli string) error {
+	secretFile := filepath.Join(azureConfigDir, "client-secret")
+	if err := os.WriteFile(secretFile, []byte(os.Getenv(EnvVarAzureClientSecret)), 0o600); err != nil {
+		return fmt.Errorf("failed to write Azure client secret: %w", err)
+	}
+	defer func() { _ = os.Remove(secretFile) }()
+
+	// the values of the arguments prefixed with @ are loaded by the CLI from the files
+	_, err := runCloudCLI(ctx, []string{"AZURE_CONFIG_DIR=" + azureConfigDir}, cli, "login", "--service-principal",
+		"--username", os.Getenv(EnvVarAzureClientID), "--password", "@"+secretFile,
+		"--tenant", os.Getenv(EnvVarAzureTenantID), "--output", "none")
+	return err
+}
+
+// validateAzureResourcesDeleted validates that no resources, e.g. virtual
+// machines, load balancers, disks or network security groups, tagged by CAPZ
+// with the name of the cluster remain.
+func validateAzureResourcesDeleted(ctx context.Context, _ *kubeclient.KubeClient, clusterName string) error {
+	cli := cloudCLI(EnvVarAzureCLI, "az")
+	env, err := azureLogin(ctx, cli)
+	if err != nil {
+		return fmt.Errorf("failed to login to Azure: %w", err)
+	}
+
+	ids, err := listCloudResources(ctx, env, cli, "resource", "list",
+		"--subscription", os.Getenv(EnvVarAzureSubscriptionID),
+		"--tag", "sigs.k8s.io_cluster-api-provider-azure_cluster_"+clusterName+"=owned",
+		"--query", "[].id", "--output", "json")
+	if err != nil {
+		return err
+	}
+	return validateCloudResourcesRemoved("Azure resources", ids)
+}
+
+// validateVSphereResourcesDeleted validates that no virtual machines of the
+// cluster remain in the folder.
+func validateVSphereResourcesDeleted(ctx context.Context, _ *kubeclient.KubeClient, clusterName string) error {
+	env := []string{
+		"GOVC_URL=" + os.Getenv(EnvVarVSphereServer),
+		"GOVC_USERNAME=" + os.Getenv(EnvVarVSphereUser),
+		"GOVC_PASSWORD=" + os.Getenv(EnvVarVSpherePassword),
+		"GOVC_INSECURE=true",
+	}
+	// govc outputs the paths of the found virtual machines line by line
+	out, err := runCloudCLI(ctx, env, cloudCLI(EnvVarGOVC, "govc"), "find", os.Getenv(EnvVarVSphereFolder),
+		"-type", "m", "-name", clusterName+"-*")
+	if err != nil {
+		return err
+	}
+	return validateCloudResourcesRemoved("vSphere virtual machines", strings.Fields(string(out)))
+}
+
+func validateCloudResourcesRemoved(kind string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return fmt.Errorf("one or more %s still exist: %s", kind, strings.Join(ids, ", "))
+}
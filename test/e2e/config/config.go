@@ -17,12 +17,17 @@ package config
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
+	"os"
 	"slices"
 	"sync"
+	"time"
 
+	"github.com/Masterminds/semver/v3"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/yaml.v3"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -38,19 +43,128 @@ const (
 	TestingProviderVsphere TestingProvider = "vsphere"
 	TestingProviderAdopted TestingProvider = "adopted"
 	TestingProviderRemote  TestingProvider = "remote"
+	TestingProviderDocker  TestingProvider = "docker"
+	// TestingProviderInMemory is the provider faking the machines, it is
+	// tested by the scenarios only.
+	TestingProviderInMemory TestingProvider = "in-memory"
+)
+
+// Version is the current version of the configuration format.
+const Version = "v1"
+
+// EnvVarConfigFile is the environment variable with the path of the
+// configuration file used instead of the embedded one, e.g. the one of the
+// profiles in the profiles directory.
+const EnvVarConfigFile = "E2E_CONFIG"
+
+// The labels of the specs testing the configurations, so the subsets of the
+// testing matrix can be selected with the Ginkgo label filter, e.g.
+// "provider:aws && type:hosted" or "!upgrade".
+const (
+	// LabelTypeStandalone is the label of the specs deploying the standalone
+	// clusters, all of the specs of the providers do so.
+	LabelTypeStandalone = "type:standalone"
+	// LabelTypeHosted is the label of the specs deploying the hosted clusters.
+	LabelTypeHosted = "type:hosted"
+	// LabelUpgrade is the label of the specs testing the upgrades of the
+	// clusters.
+	LabelUpgrade = "upgrade"
+)
+
+// Architecture is the architecture of the machines of the cluster.
+type Architecture string
+
+const (
+	ArchitectureAMD64 Architecture = "amd64"
+	ArchitectureARM64 Architecture = "arm64"
 )
 
 var (
 	//go:embed config.yaml
 	configBytes []byte
+	//go:embed config.schema.json
+	schemaBytes []byte
 
 	Config TestingConfig
 
+	// configuredTimeouts are the timeouts set in the configuration, they
+	// take precedence over the defaults of the providers.
+	configuredTimeouts Timeouts
+
 	parseOnce sync.Once
 	errParse  error
 )
 
-type TestingConfig = map[TestingProvider][]ProviderTestingConfig
+type TestingConfig struct {
+	// Version is the version of the configuration format.
+	Version string `yaml:"version"`
+	// Timeouts are the timeouts used by all of the providers unless
+	// overridden by the testing configuration.
+	Timeouts Timeouts `yaml:"timeouts,omitempty"`
+	// Providers contains the testing configurations of the providers, the
+	// providers missing in the map are skipped.
+	Providers map[TestingProvider][]ProviderTestingConfig `yaml:"providers,omitempty"`
+	// ProviderTimeouts override the timeouts of the configuration for all
+	// of the clusters of the provider.
+	ProviderTimeouts map[TestingProvider]Timeouts `yaml:"providerTimeouts,omitempty"`
+	// TemplateTimeouts override the timeouts of the configuration and of the
+	// provider for all of the clusters of the template type, e.g. aws-eks.
+	TemplateTimeouts map[templates.Type]Timeouts `yaml:"templateTimeouts,omitempty"`
+	// Scale contains the configuration of the scale testing, it is skipped
+	// if omitted.
+	Scale *ScaleTestingConfig `yaml:"scale,omitempty"`
+}
+
+// ScaleTestingConfig defines the ClusterDeployments created at once by the
+// scale testing and the regression thresholds of the load of the controller
+// manager reconciling them.
+type ScaleTestingConfig struct {
+	// Provider is the provider the clusters are deployed with, in-memory if
+	// unset.
+	Provider TestingProvider `yaml:"provider,omitempty"`
+	// Template is the name of the template of the clusters, the latest one of
+	// the provider is used if unset.
+	Template string `yaml:"template,omitempty"`
+	// Clusters is the number of the ClusterDeployments created.
+	Clusters int `yaml:"clusters,omitempty"`
+	// Concurrency is the number of the ClusterDeployments created concurrently.
+	Concurrency int `yaml:"concurrency,omitempty"`
+	// Thresholds are the regression thresholds, the unset ones are not checked.
+	Thresholds ScaleThresholds `yaml:"thresholds,omitempty"`
+	// Timeouts override the timeouts of the testing configuration.
+	Timeouts Timeouts `yaml:"timeouts,omitempty"`
+}
+
+// ScaleThresholds are the limits of the load of the controller manager the
+// scale testing fails beyond.
+type ScaleThresholds struct {
+	// ReconcileLatencyP99 is the maximum 99th percentile of the latency of
+	// the reconciles of the ClusterDeployments.
+	ReconcileLatencyP99 time.Duration `yaml:"reconcileLatencyP99,omitempty"`
+	// MaxMemoryMiB is the maximum resident memory of the controller manager.
+	MaxMemoryMiB int `yaml:"maxMemoryMiB,omitempty"`
+	// MaxAPIQPS is the maximum average rate of the requests of the controller
+	// manager to the API server.
+	MaxAPIQPS float64 `yaml:"maxAPIQPS,omitempty"`
+}
+
+// Timeouts defines the time to wait for the operations and the interval of
+// the checks, the unset ones are inherited from the upper level or defaulted
+// per template type and provider.
+type Timeouts struct {
+	// ControllersReady is the time to wait for the controllers and templates
+	// to become ready.
+	ControllersReady time.Duration `yaml:"controllersReady,omitempty"`
+	// Deployment is the time to wait for the cluster deployment to become ready.
+	Deployment time.Duration `yaml:"deployment,omitempty"`
+	// Deletion is the time to wait for the cluster deployment to be deleted.
+	Deletion time.Duration `yaml:"deletion,omitempty"`
+	// Upgrade is the time to wait for the cluster deployment to become ready
+	// after the upgrade.
+	Upgrade time.Duration `yaml:"upgrade,omitempty"`
+	// Polling is the interval the state of the waited objects is checked at.
+	Polling time.Duration `yaml:"polling,omitempty"`
+}
 
 type ProviderTestingConfig struct {
 	// ClusterTestingConfig contains the testing configuration for the cluster deployment.
@@ -58,6 +172,14 @@ type ProviderTestingConfig struct {
 	// Hosted contains the testing configuration for the hosted cluster deployment using the previously deployed
 	// cluster as a management. If omitted, the hosted cluster deployment will be skipped.
 	Hosted *ClusterTestingConfig `yaml:"hosted,omitempty"`
+	// UpgradePath lists the past releases of kcm, oldest first, the templates of which the cluster deployment
+	// is upgraded through. The cluster deployment is created from the latest template of the first release,
+	// upgraded to the latest template of each of the next releases one by one and finally to the latest
+	// available template. Implies the upgrade, the template and the upgrade template can not be set along with it.
+	UpgradePath []string `yaml:"upgradePath,omitempty"`
+	// UpgradeChain is the sequence of the templates the cluster deployment is upgraded through, it is resolved
+	// from the upgrade path.
+	UpgradeChain []string `yaml:"upgradeChain,omitempty"`
 }
 
 type ClusterTestingConfig struct {
@@ -71,19 +193,103 @@ type ClusterTestingConfig struct {
 	// UpgradeTemplate specifies the name of the template to upgrade to. Ignored if upgrade is set to false.
 	// If unset, the latest template available for the upgrade will be chosen.
 	UpgradeTemplate string `yaml:"upgradeTemplate,omitempty"`
+	// Architecture is the architecture of the machines of the cluster, amd64 if unset.
+	Architecture Architecture `yaml:"architecture,omitempty"`
+	// Timeouts override the timeouts of the testing configuration.
+	Timeouts Timeouts `yaml:"timeouts,omitempty"`
 }
 
+// Parse parses the embedded configuration. It is called before the specs are
+// built, so the specs can be generated per testing configuration.
 func Parse() error {
 	parseOnce.Do(func() {
-		err := yaml.Unmarshal(configBytes, &Config)
-		if err != nil {
-			errParse = fmt.Errorf("failed to decode base64 configuration: %w", err)
-			return
+		data := configBytes
+		if path := os.Getenv(EnvVarConfigFile); path != "" {
+			if data, errParse = os.ReadFile(path); errParse != nil {
+				errParse = fmt.Errorf("failed to read configuration: %w", errParse)
+				return
+			}
+		}
+
+		Config, errParse = parse(data)
+		configuredTimeouts = Config.Timeouts
+		Config.Timeouts = Config.Timeouts.withDefaults(getDefaultTimeouts("", ""))
+
+		if len(Config.Providers) == 0 {
+			Config.Providers = map[TestingProvider][]ProviderTestingConfig{
+				TestingProviderAWS:     {},
+				TestingProviderAzure:   {},
+				TestingProviderVsphere: {},
+				TestingProviderAdopted: {},
+				TestingProviderRemote:  {},
+				TestingProviderDocker:  {},
+			}
+		}
+		for provider, configs := range Config.Providers {
+			if len(configs) == 0 {
+				Config.Providers[provider] = getDefaultTestingConfiguration()
+			}
+		}
+		if Config.Scale != nil {
+			Config.Scale.setDefaults(configuredTimeouts)
 		}
 	})
 	return errParse
 }
 
+// parse decodes the configuration and validates it against the schema. The
+// configuration without the version is treated as the legacy one consisting
+// of the providers map only.
+func parse(data []byte) (TestingConfig, error) {
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return TestingConfig{}, fmt.Errorf("failed to decode configuration: %w", err)
+	}
+
+	if _, ok := raw["version"]; !ok {
+		providers := raw
+		raw = map[string]any{"version": Version}
+		if len(providers) > 0 {
+			raw["providers"] = providers
+		}
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schemaBytes), gojsonschema.NewGoLoader(raw))
+	if err != nil {
+		return TestingConfig{}, fmt.Errorf("failed to validate configuration: %w", err)
+	}
+	if !result.Valid() {
+		var errs error
+		for _, e := range result.Errors() {
+			errs = errors.Join(errs, errors.New(e.String()))
+		}
+		return TestingConfig{}, fmt.Errorf("invalid configuration: %w", errs)
+	}
+
+	validated, err := yaml.Marshal(raw)
+	if err != nil {
+		return TestingConfig{}, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+
+	var cfg TestingConfig
+	if err := yaml.Unmarshal(validated, &cfg); err != nil {
+		return TestingConfig{}, fmt.Errorf("failed to decode configuration: %w", err)
+	}
+
+	var errs error
+	for provider, configs := range cfg.Providers {
+		for i := range configs {
+			if err := configs[i].setUpgradePath(); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("invalid %s configuration %d: %w", provider, i, err))
+			}
+		}
+	}
+	if errs != nil {
+		return TestingConfig{}, errs
+	}
+	return cfg, nil
+}
+
 func Show() string {
 	prettyConfig, err := yaml.Marshal(Config)
 	Expect(err).NotTo(HaveOccurred())
@@ -92,7 +298,7 @@ func Show() string {
 }
 
 func UpgradeRequired() bool {
-	for _, configs := range Config {
+	for _, configs := range Config.Providers {
 		for _, config := range configs {
 			if config.Upgrade {
 				return true
@@ -102,37 +308,213 @@ func UpgradeRequired() bool {
 	return false
 }
 
+// UpgradeReleases returns the releases of the upgrade paths of all of the
+// testing configurations, the templates of which are to be installed.
+func UpgradeReleases() []string {
+	var releases []string
+	for _, configs := range Config.Providers {
+		for _, config := range configs {
+			releases = append(releases, config.UpgradePath...)
+		}
+	}
+	slices.Sort(releases)
+	return slices.Compact(releases)
+}
+
 func SetDefaults(ctx context.Context, cl crclient.Client) {
 	clusterTemplates, err := templates.GetSortedClusterTemplates(ctx, cl, internalutils.DefaultSystemNamespace)
 	Expect(err).NotTo(HaveOccurred())
 
 	_, _ = fmt.Fprintf(GinkgoWriter, "Found ClusterTemplates:\n%v\n", clusterTemplates)
 
-	if len(Config) == 0 {
-		Config = map[TestingProvider][]ProviderTestingConfig{
-			TestingProviderAWS:     {},
-			TestingProviderAzure:   {},
-			TestingProviderVsphere: {},
-			TestingProviderAdopted: {},
-			TestingProviderRemote:  {},
-		}
+	releaseTemplates := make(map[string][]string)
+	for _, release := range UpgradeReleases() {
+		releaseTemplates[release], err = templates.GetSortedClusterTemplates(ctx, cl, internalutils.DefaultSystemNamespace, templates.ReleaseLabel(release))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, _ = fmt.Fprintf(GinkgoWriter, "Found ClusterTemplates of the %s release:\n%v\n", release, releaseTemplates[release])
 	}
-	for provider, configs := range Config {
-		if len(configs) == 0 {
-			Config[provider] = getDefaultTestingConfiguration()
-		}
-		for i := range Config[provider] {
-			c := Config[provider][i]
-			err := c.SetTemplates(clusterTemplates, getTemplateType(provider))
+
+	for provider := range Config.Providers {
+		for i := range Config.Providers[provider] {
+			c := Config.Providers[provider][i]
+			if len(c.UpgradePath) > 0 {
+				err = c.setUpgradeChain(clusterTemplates, releaseTemplates, getTemplateType(provider))
+			} else {
+				err = c.SetTemplates(clusterTemplates, getTemplateType(provider))
+			}
+			Expect(err).NotTo(HaveOccurred())
+			err = c.setDefaults(provider, templates.GetType(c.Template), configuredTimeouts)
 			Expect(err).NotTo(HaveOccurred())
 
 			if c.Hosted != nil {
 				err = c.Hosted.SetTemplates(clusterTemplates, getHostedTemplateType(provider))
 				Expect(err).NotTo(HaveOccurred())
+				err = c.Hosted.setDefaults(provider, templates.GetType(c.Hosted.Template), configuredTimeouts)
+				Expect(err).NotTo(HaveOccurred())
 			}
-			Config[provider][i] = c
+			Config.Providers[provider][i] = c
+		}
+	}
+}
+
+// setDefaults defaults the architecture and the timeouts of the cluster
+// testing configuration, the unset timeouts are resolved with
+// resolveTimeouts.
+func (c *ClusterTestingConfig) setDefaults(provider TestingProvider, templateType templates.Type, timeouts Timeouts) error {
+	if c.Architecture == "" {
+		c.Architecture = ArchitectureAMD64
+	}
+	if c.Architecture != ArchitectureAMD64 && provider != TestingProviderAWS {
+		return fmt.Errorf("the %s architecture is not supported by the %s provider", c.Architecture, provider)
+	}
+
+	c.Timeouts = c.Timeouts.withDefaults(resolveTimeouts(provider, templateType, timeouts))
+	return nil
+}
+
+// ApplyDefaults defaults the architecture and the timeouts of the cluster
+// testing configuration of the provider and the template type built outside
+// of the testing configuration, e.g. of the scenario, the configured timeouts
+// take precedence over the defaults.
+func (c *ClusterTestingConfig) ApplyDefaults(provider TestingProvider, templateType templates.Type) error {
+	return c.setDefaults(provider, templateType, configuredTimeouts)
+}
+
+// TimeoutsFor returns the timeouts of the clusters of the provider and the
+// template type deployed outside of the testing configurations, e.g. by the
+// specs deploying the clusters of several providers.
+func TimeoutsFor(provider TestingProvider, templateType templates.Type) Timeouts {
+	return resolveTimeouts(provider, templateType, configuredTimeouts)
+}
+
+// resolveTimeouts returns the timeouts of the clusters of the provider and
+// the template type, the timeouts of the template type set in the
+// configuration take precedence over the ones of the provider, then over the
+// given ones and finally over the defaults of the template type and the
+// provider.
+func resolveTimeouts(provider TestingProvider, templateType templates.Type, timeouts Timeouts) Timeouts {
+	return Config.TemplateTimeouts[templateType].
+		withDefaults(Config.ProviderTimeouts[provider]).
+		withDefaults(timeouts).
+		withDefaults(getDefaultTimeouts(provider, templateType))
+}
+
+// setDefaults defaults the provider, the number of the clusters and the
+// concurrency of the scale testing configuration, the unset timeouts are
+// inherited from the given ones and then from the defaults of the provider.
+func (c *ScaleTestingConfig) setDefaults(timeouts Timeouts) {
+	if c.Provider == "" {
+		c.Provider = TestingProviderInMemory
+	}
+	if c.Clusters == 0 {
+		c.Clusters = defaultScaleClusters
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = defaultScaleConcurrency
+	}
+	c.Timeouts = c.Timeouts.withDefaults(resolveTimeouts(c.Provider, c.TemplateType(), timeouts))
+}
+
+// TemplateType returns the type of the templates of the clusters of the
+// scale testing.
+func (c *ScaleTestingConfig) TemplateType() templates.Type {
+	if c.Provider == TestingProviderInMemory {
+		return templates.TemplateInMemoryStandaloneCP
+	}
+	return getTemplateType(c.Provider)
+}
+
+// withDefaults returns the timeouts with the unset ones taken from the
+// defaults.
+func (t Timeouts) withDefaults(defaults Timeouts) Timeouts {
+	if t.ControllersReady == 0 {
+		t.ControllersReady = defaults.ControllersReady
+	}
+	if t.Deployment == 0 {
+		t.Deployment = defaults.Deployment
+	}
+	if t.Deletion == 0 {
+		t.Deletion = defaults.Deletion
+	}
+	if t.Upgrade == 0 {
+		t.Upgrade = defaults.Upgrade
+	}
+	if t.Polling == 0 {
+		t.Polling = defaults.Polling
+	}
+	return t
+}
+
+// Labels returns the labels of the spec testing the configuration.
+func (c ProviderTestingConfig) Labels() []string {
+	labels := []string{LabelTypeStandalone}
+	if c.Hosted != nil {
+		labels = append(labels, LabelTypeHosted)
+	}
+	if c.Upgrade || (c.Hosted != nil && c.Hosted.Upgrade) {
+		labels = append(labels, LabelUpgrade)
+	}
+	return labels
+}
+
+// setUpgradePath validates the upgrade path of the testing configuration and
+// enables the upgrade if the path is set.
+func (c *ProviderTestingConfig) setUpgradePath() error {
+	if len(c.UpgradePath) == 0 {
+		return nil
+	}
+	if c.Template != "" || c.UpgradeTemplate != "" {
+		return errors.New("the template and the upgrade template can not be set along with the upgrade path")
+	}
+
+	var previous *semver.Version
+	for _, release := range c.UpgradePath {
+		version, err := semver.NewVersion(release)
+		if err != nil {
+			return fmt.Errorf("invalid release %s of the upgrade path: %w", release, err)
+		}
+		if previous != nil && !version.GreaterThan(previous) {
+			return fmt.Errorf("the releases of the upgrade path %v are not in the ascending order", c.UpgradePath)
 		}
+		previous = version
 	}
+
+	c.Upgrade = true
+	return nil
+}
+
+// setUpgradeChain resolves the templates the cluster deployment is upgraded
+// through from the upgrade path and the templates installed from each of its
+// releases. The releases not bringing a new template of the type are skipped.
+func (c *ProviderTestingConfig) setUpgradeChain(clusterTemplates []string, releaseTemplates map[string][]string, templateType templates.Type) error {
+	c.UpgradeChain = nil
+	addTemplate := func(template string) {
+		if len(c.UpgradeChain) == 0 || c.UpgradeChain[len(c.UpgradeChain)-1] != template {
+			c.UpgradeChain = append(c.UpgradeChain, template)
+		}
+	}
+
+	for _, release := range c.UpgradePath {
+		tmpls := templates.FindLatestTemplatesWithType(releaseTemplates[release], templateType, 1)
+		if len(tmpls) == 0 {
+			return fmt.Errorf("no Template of the %s type was found in the %s release", templateType, release)
+		}
+		addTemplate(tmpls[0])
+	}
+
+	tmpls := templates.FindLatestTemplatesWithType(clusterTemplates, templateType, 1)
+	if len(tmpls) == 0 {
+		return fmt.Errorf("no Template of the %s type was found", templateType)
+	}
+	addTemplate(tmpls[0])
+
+	if len(c.UpgradeChain) < 2 {
+		return fmt.Errorf("the upgrade path %v does not upgrade the %s templates, the only one is %s", c.UpgradePath, templateType, c.UpgradeChain[0])
+	}
+	c.Template = c.UpgradeChain[0]
+	c.UpgradeTemplate = c.UpgradeChain[len(c.UpgradeChain)-1]
+	return nil
 }
 
 func (c *ProviderTestingConfig) String() string {
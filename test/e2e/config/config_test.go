@@ -0,0 +1,367 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/K0rdent/kcm/test/e2e/templates"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		data        string
+		expected    TestingConfig
+		expectedErr string
+	}{
+		{
+			name:     "empty",
+			expected: TestingConfig{Version: Version},
+		},
+		{
+			name: "legacy",
+			data: "aws:\n- template: aws-standalone-cp-0-1-0\n  hosted:\n    template: aws-hosted-cp-0-1-0\nazure: []\n",
+			expected: TestingConfig{
+				Version: Version,
+				Providers: map[TestingProvider][]ProviderTestingConfig{
+					TestingProviderAWS: {{
+						ClusterTestingConfig: ClusterTestingConfig{Template: "aws-standalone-cp-0-1-0"},
+						Hosted:               &ClusterTestingConfig{Template: "aws-hosted-cp-0-1-0"},
+					}},
+					TestingProviderAzure: {},
+				},
+			},
+		},
+		{
+			name: "versioned",
+			data: `version: v1
+timeouts:
+  deployment: 45m
+providers:
+  aws:
+  - template: aws-eks-0-1-0
+    architecture: arm64
+    upgrade: true
+    upgradeTemplate: aws-eks-0-1-1
+    timeouts:
+      upgrade: 1h
+  vsphere:
+`,
+			expected: TestingConfig{
+				Version:  Version,
+				Timeouts: Timeouts{Deployment: 45 * time.Minute},
+				Providers: map[TestingProvider][]ProviderTestingConfig{
+					TestingProviderAWS: {{
+						ClusterTestingConfig: ClusterTestingConfig{
+							Template:        "aws-eks-0-1-0",
+							Architecture:    ArchitectureARM64,
+							Upgrade:         true,
+							UpgradeTemplate: "aws-eks-0-1-1",
+							Timeouts:        Timeouts{Upgrade: time.Hour},
+						},
+					}},
+					TestingProviderVsphere: nil,
+				},
+			},
+		},
+		{
+			name: "upgrade path",
+			data: "aws:\n- upgradePath: [v0.1.0, v0.2.0]\n",
+			expected: TestingConfig{
+				Version: Version,
+				Providers: map[TestingProvider][]ProviderTestingConfig{
+					TestingProviderAWS: {{
+						ClusterTestingConfig: ClusterTestingConfig{Upgrade: true},
+						UpgradePath:          []string{"v0.1.0", "v0.2.0"},
+					}},
+				},
+			},
+		},
+		{
+			name: "scale",
+			data: "version: v1\nscale:\n  clusters: 100\n  thresholds:\n    reconcileLatencyP99: 5s\n    maxAPIQPS: 20.5\n",
+			expected: TestingConfig{
+				Version: Version,
+				Scale: &ScaleTestingConfig{
+					Clusters:   100,
+					Thresholds: ScaleThresholds{ReconcileLatencyP99: 5 * time.Second, MaxAPIQPS: 20.5},
+				},
+			},
+		},
+		{
+			name: "provider and template timeouts",
+			data: "version: v1\ntimeouts:\n  polling: 30s\nproviderTimeouts:\n  azure:\n    deployment: 2h\ntemplateTimeouts:\n  aws-eks:\n    deletion: 45m\n",
+			expected: TestingConfig{
+				Version:          Version,
+				Timeouts:         Timeouts{Polling: 30 * time.Second},
+				ProviderTimeouts: map[TestingProvider]Timeouts{TestingProviderAzure: {Deployment: 2 * time.Hour}},
+				TemplateTimeouts: map[templates.Type]Timeouts{templates.TemplateAWSEKS: {Deletion: 45 * time.Minute}},
+			},
+		},
+		{
+			name:        "unknown template type timeouts",
+			data:        "version: v1\ntemplateTimeouts:\n  gcp-gke:\n    deployment: 1h\n",
+			expectedErr: "gcp-gke",
+		},
+		{
+			name:        "unsupported scale provider",
+			data:        "version: v1\nscale:\n  provider: aws\n",
+			expectedErr: "provider",
+		},
+		{
+			name:        "upgrade path with template",
+			data:        "aws:\n- template: aws-standalone-cp-0-1-0\n  upgradePath: [v0.1.0]\n",
+			expectedErr: "can not be set along with the upgrade path",
+		},
+		{
+			name:        "upgrade path in descending order",
+			data:        "aws:\n- upgradePath: [v0.2.0, v0.1.0]\n",
+			expectedErr: "ascending order",
+		},
+		{
+			name:        "invalid upgrade path release",
+			data:        "aws:\n- upgradePath: [0.1]\n",
+			expectedErr: "upgradePath",
+		},
+		{
+			name:        "unsupported version",
+			data:        "version: v2\n",
+			expectedErr: "version",
+		},
+		{
+			name:        "unknown provider",
+			data:        "version: v1\nproviders:\n  gcp: []\n",
+			expectedErr: "gcp",
+		},
+		{
+			name:        "unknown field",
+			data:        "aws:\n- templte: aws-standalone-cp-0-1-0\n",
+			expectedErr: "templte",
+		},
+		{
+			name:        "invalid architecture",
+			data:        "aws:\n- architecture: s390x\n",
+			expectedErr: "architecture",
+		},
+		{
+			name:        "invalid duration",
+			data:        "version: v1\ntimeouts:\n  deletion: 10 minutes\n",
+			expectedErr: "deletion",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cfg, err := parse([]byte(tc.data))
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cfg).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestParseEmbedded(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := parse(configBytes)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestParseProfiles(t *testing.T) {
+	profiles, err := filepath.Glob("profiles/*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, profile := range profiles {
+		t.Run(filepath.Base(profile), func(t *testing.T) {
+			g := NewWithT(t)
+
+			data, err := os.ReadFile(profile)
+			g.Expect(err).NotTo(HaveOccurred())
+			_, err = parse(data)
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestClusterTestingConfigSetDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	c := ClusterTestingConfig{Timeouts: Timeouts{Deletion: time.Minute}}
+	g.Expect(c.setDefaults(TestingProviderAzure, templates.TemplateAzureStandaloneCP, Timeouts{Upgrade: time.Hour})).To(Succeed())
+	g.Expect(c.Architecture).To(Equal(ArchitectureAMD64))
+	g.Expect(c.Timeouts).To(Equal(Timeouts{
+		ControllersReady: 15 * time.Minute,
+		Deployment:       90 * time.Minute,
+		Deletion:         time.Minute,
+		Upgrade:          time.Hour,
+		Polling:          10 * time.Second,
+	}))
+
+	c = ClusterTestingConfig{Architecture: ArchitectureARM64}
+	g.Expect(c.setDefaults(TestingProviderVsphere, templates.TemplateVSphereStandaloneCP, Timeouts{})).To(MatchError(ContainSubstring("not supported")))
+}
+
+func TestResolveTimeouts(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := Config
+	t.Cleanup(func() { Config = cfg })
+
+	g.Expect(resolveTimeouts(TestingProviderAWS, templates.TemplateAWSEKS, Timeouts{})).To(Equal(Timeouts{
+		ControllersReady: 15 * time.Minute,
+		Deployment:       time.Hour,
+		Deletion:         30 * time.Minute,
+		Upgrade:          30 * time.Minute,
+		Polling:          10 * time.Second,
+	}))
+
+	Config.ProviderTimeouts = map[TestingProvider]Timeouts{
+		TestingProviderAzure: {Deployment: 2 * time.Hour, Deletion: 20 * time.Minute, Polling: 30 * time.Second},
+	}
+	Config.TemplateTimeouts = map[templates.Type]Timeouts{
+		templates.TemplateAzureAKS: {Deletion: 45 * time.Minute},
+	}
+	g.Expect(resolveTimeouts(TestingProviderAzure, templates.TemplateAzureAKS, Timeouts{Deployment: time.Hour, Upgrade: time.Hour})).To(Equal(Timeouts{
+		ControllersReady: 15 * time.Minute,
+		Deployment:       2 * time.Hour,
+		Deletion:         45 * time.Minute,
+		Upgrade:          time.Hour,
+		Polling:          30 * time.Second,
+	}))
+	g.Expect(resolveTimeouts(TestingProviderVsphere, templates.TemplateVSphereStandaloneCP, Timeouts{Polling: time.Minute})).To(Equal(Timeouts{
+		ControllersReady: 15 * time.Minute,
+		Deployment:       30 * time.Minute,
+		Deletion:         10 * time.Minute,
+		Upgrade:          30 * time.Minute,
+		Polling:          time.Minute,
+	}))
+}
+
+func TestScaleTestingConfigSetDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	c := ScaleTestingConfig{Concurrency: 5, Timeouts: Timeouts{Deployment: time.Hour}}
+	c.setDefaults(Timeouts{Deletion: time.Minute})
+	g.Expect(c).To(Equal(ScaleTestingConfig{
+		Provider:    TestingProviderInMemory,
+		Clusters:    defaultScaleClusters,
+		Concurrency: 5,
+		Timeouts: Timeouts{
+			ControllersReady: 15 * time.Minute,
+			Deployment:       time.Hour,
+			Deletion:         time.Minute,
+			Upgrade:          10 * time.Minute,
+			Polling:          10 * time.Second,
+		},
+	}))
+	g.Expect(c.TemplateType()).To(Equal(templates.TemplateInMemoryStandaloneCP))
+
+	c = ScaleTestingConfig{Provider: TestingProviderDocker}
+	g.Expect(c.TemplateType()).To(Equal(templates.TemplateDockerHostedCP))
+}
+
+func TestProviderTestingConfigSetUpgradeChain(t *testing.T) {
+	clusterTemplates := []string{"aws-standalone-cp-0-3-0", "aws-standalone-cp-0-2-0", "aws-standalone-cp-0-1-0", "aws-eks-0-1-0"}
+	releaseTemplates := map[string][]string{
+		"v0.1.0": {"aws-standalone-cp-0-1-0", "aws-eks-0-1-0"},
+		"v0.2.0": {"aws-standalone-cp-0-2-0", "aws-eks-0-1-0"},
+	}
+
+	for _, tc := range []struct {
+		name          string
+		upgradePath   []string
+		templateType  templates.Type
+		expectedChain []string
+		expectedErr   string
+	}{
+		{
+			name:          "releases upgrading the template",
+			upgradePath:   []string{"v0.1.0", "v0.2.0"},
+			templateType:  templates.TemplateAWSStandaloneCP,
+			expectedChain: []string{"aws-standalone-cp-0-1-0", "aws-standalone-cp-0-2-0", "aws-standalone-cp-0-3-0"},
+		},
+		{
+			name:         "releases not upgrading the template",
+			upgradePath:  []string{"v0.1.0", "v0.2.0"},
+			templateType: templates.TemplateAWSEKS,
+			expectedErr:  "does not upgrade",
+		},
+		{
+			name:         "release without the template",
+			upgradePath:  []string{"v0.1.0"},
+			templateType: templates.TemplateAzureAKS,
+			expectedErr:  "in the v0.1.0 release",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := ProviderTestingConfig{UpgradePath: tc.upgradePath}
+			err := c.setUpgradeChain(clusterTemplates, releaseTemplates, tc.templateType)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(c.UpgradeChain).To(Equal(tc.expectedChain))
+			g.Expect(c.Template).To(Equal(tc.expectedChain[0]))
+			g.Expect(c.UpgradeTemplate).To(Equal(tc.expectedChain[len(tc.expectedChain)-1]))
+		})
+	}
+}
+
+func TestProviderTestingConfigLabels(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   ProviderTestingConfig
+		expected []string
+	}{
+		{
+			name:     "standalone",
+			expected: []string{LabelTypeStandalone},
+		},
+		{
+			name:     "standalone upgrade",
+			config:   ProviderTestingConfig{ClusterTestingConfig: ClusterTestingConfig{Upgrade: true}},
+			expected: []string{LabelTypeStandalone, LabelUpgrade},
+		},
+		{
+			name:     "hosted",
+			config:   ProviderTestingConfig{Hosted: &ClusterTestingConfig{}},
+			expected: []string{LabelTypeStandalone, LabelTypeHosted},
+		},
+		{
+			name:     "hosted upgrade",
+			config:   ProviderTestingConfig{Hosted: &ClusterTestingConfig{Upgrade: true}},
+			expected: []string{LabelTypeStandalone, LabelTypeHosted, LabelUpgrade},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tc.config.Labels()).To(Equal(tc.expected))
+		})
+	}
+}
@@ -15,13 +15,61 @@
 package config
 
 import (
+	"time"
+
 	"github.com/K0rdent/kcm/test/e2e/templates"
 )
 
+const (
+	// defaultScaleClusters is the number of the ClusterDeployments created by
+	// the scale testing.
+	defaultScaleClusters = 50
+	// defaultScaleConcurrency is the number of the ClusterDeployments created
+	// concurrently by the scale testing.
+	defaultScaleConcurrency = 10
+)
+
 func getDefaultTestingConfiguration() []ProviderTestingConfig {
 	return []ProviderTestingConfig{{ClusterTestingConfig: ClusterTestingConfig{}}}
 }
 
+// getDefaultTimeouts returns the default timeouts of the template type and
+// the provider, the ones of the template type take precedence, the common ones
+// are returned for the empty provider.
+func getDefaultTimeouts(provider TestingProvider, templateType templates.Type) Timeouts {
+	timeouts := Timeouts{
+		ControllersReady: 15 * time.Minute,
+		Deployment:       30 * time.Minute,
+		Deletion:         10 * time.Minute,
+		Upgrade:          30 * time.Minute,
+		Polling:          10 * time.Second,
+	}
+
+	switch provider {
+	case TestingProviderAzure:
+		timeouts.Deployment = 90 * time.Minute
+	case TestingProviderAdopted:
+		timeouts.Deletion = 30 * time.Minute
+	case TestingProviderRemote:
+		timeouts.Deletion = 20 * time.Minute
+	case TestingProviderInMemory:
+		// the machines are faked, so the clusters are ready in minutes
+		timeouts.Deployment = 10 * time.Minute
+		timeouts.Upgrade = 10 * time.Minute
+	}
+
+	switch templateType {
+	case templates.TemplateAWSEKS:
+		// the managed control planes and node groups are slow to be
+		// provisioned and removed
+		timeouts.Deployment = 60 * time.Minute
+		timeouts.Deletion = 30 * time.Minute
+	case templates.TemplateAzureAKS:
+		timeouts.Deletion = 30 * time.Minute
+	}
+	return timeouts
+}
+
 func getTemplateType(provider TestingProvider) templates.Type {
 	switch provider {
 	case TestingProviderAWS:
@@ -34,6 +82,8 @@ func getTemplateType(provider TestingProvider) templates.Type {
 		return templates.TemplateAdoptedCluster
 	case TestingProviderRemote:
 		return templates.TemplateRemoteCluster
+	case TestingProviderDocker:
+		return templates.TemplateDockerHostedCP
 	default:
 		return ""
 	}
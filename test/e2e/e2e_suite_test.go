@@ -19,22 +19,31 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	internalutils "github.com/K0rdent/kcm/internal/utils"
+	"github.com/K0rdent/kcm/test/e2e/chaos"
 	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
 	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/flake"
 	"github.com/K0rdent/kcm/test/e2e/kubeclient"
 	"github.com/K0rdent/kcm/test/e2e/logs"
+	"github.com/K0rdent/kcm/test/e2e/management"
+	"github.com/K0rdent/kcm/test/e2e/report"
+	"github.com/K0rdent/kcm/test/e2e/resume"
+	"github.com/K0rdent/kcm/test/e2e/scenario"
 	"github.com/K0rdent/kcm/test/e2e/templates"
+	"github.com/K0rdent/kcm/test/e2e/upgrade"
+	"github.com/K0rdent/kcm/test/e2e/wait"
 	"github.com/K0rdent/kcm/test/utils"
 )
 
@@ -42,37 +51,54 @@ import (
 func TestE2E(t *testing.T) {
 	RegisterFailHandler(Fail)
 	_, _ = fmt.Fprintf(GinkgoWriter, "Starting kcm suite\n")
+
+	// the specs of the providers are built per testing configuration
+	if err := config.Parse(); err != nil {
+		t.Fatalf("failed to parse the testing configuration: %v", err)
+	}
+	if err := scenario.Parse(); err != nil {
+		t.Fatalf("failed to parse the scenarios: %v", err)
+	}
 	RunSpecs(t, "e2e suite")
 }
 
-var _ = BeforeSuite(func() {
-	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
-
-	err := config.Parse()
-	Expect(err).NotTo(HaveOccurred())
+// The management cluster is set up by the first process only, the suites run
+// in parallel processes share it, but each of them deploys the clusters in its
+// own namespace.
+var _ = SynchronizedBeforeSuite(func() {
+	Expect(flake.ValidateConfiguration()).To(Succeed())
 
 	GinkgoT().Setenv(clusterdeployment.EnvVarNamespace, internalutils.DefaultSystemNamespace)
 
-	cmd := exec.Command("make", "test-apply")
-	_, err = utils.Run(cmd)
-	Expect(err).NotTo(HaveOccurred())
-
-	if config.UpgradeRequired() {
-		By("installing stable templates for further upgrade testing")
-		_, err = utils.Run(exec.Command("make", "stable-templates"))
+	var err error
+	if resume.Enabled() {
+		// the clusters of the previous run are managed by its management
+		// cluster, so it is reused as is
+		By("resuming the previous run on its management cluster")
+	} else if management.Existing() {
+		// the templates of the upgrades are expected to be installed along
+		// with kcm
+		By("running against the existing management cluster")
+	} else {
+		cmd := exec.Command("make", "test-apply")
+		_, err = utils.Run(cmd)
 		Expect(err).NotTo(HaveOccurred())
+
+		if config.UpgradeRequired() {
+			By("installing stable templates for further upgrade testing")
+			_, err = utils.Run(exec.Command("make", "stable-templates"))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		for _, release := range config.UpgradeReleases() {
+			By(fmt.Sprintf("installing the templates of the %s release for the upgrade path testing", release))
+			_, err = utils.Run(exec.Command("make", "stable-templates", "KCM_STABLE_VERSION="+release))
+			Expect(err).NotTo(HaveOccurred())
+		}
 	}
 
 	By("validating that the kcm-controller and CAPI provider controllers are running and ready")
 	kc := kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
-	Eventually(func() error {
-		err = verifyControllersUp(kc)
-		if err != nil {
-			_, _ = fmt.Fprintf(GinkgoWriter, "Controller validation failed: %v\n", err)
-			return err
-		}
-		return nil
-	}).WithTimeout(15 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+	waitForControllers(kc, config.Config.Timeouts.ControllersReady)
 
 	Eventually(func() error {
 		err = clusterdeployment.ValidateClusterTemplates(context.Background(), kc)
@@ -81,17 +107,53 @@ var _ = BeforeSuite(func() {
 			return err
 		}
 		return nil
-	}).WithTimeout(15 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+	}).WithTimeout(config.Config.Timeouts.ControllersReady).WithPolling(config.Config.Timeouts.Polling).Should(Succeed())
+
+	By("distributing the templates to the namespaces of the specs")
+	distributeTemplates(context.Background(), kc)
+}, func() {
+	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+
+	// the identities are shared by all of the processes and are created in
+	// the system namespace
+	GinkgoT().Setenv(clusterdeployment.EnvVarNamespace, internalutils.DefaultSystemNamespace)
 
+	kc := kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
 	config.SetDefaults(context.Background(), kc.CrClient)
 
 	_, _ = fmt.Fprintf(GinkgoWriter, "E2e testing configuration:\n%s\n", config.Show())
 })
 
-var _ = AfterSuite(func() {
+// The diagnostics of the management cluster are collected right after the
+// failed spec, before the cleanup of the spec removes the objects: the logs of
+// the kcm, provider and Flux controllers in the support bundle and the dumps
+// of the objects of kcm, Flux, CAPI and Sveltos along with the events.
+var _ = JustAfterEach(func() {
+	if !CurrentSpecReport().Failed() {
+		return
+	}
+
+	By("collecting the support bundle from the management cluster")
+	logs.SupportBundle(nil, "")
+
+	By("dumping the resources of the management cluster")
+	namespaces := []string{internalutils.DefaultSystemNamespace}
+	if currentSpecNamespace != "" {
+		namespaces = append(namespaces, currentSpecNamespace)
+	}
+	logs.DumpResources(kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace), namespaces...)
+})
+
+// The namespaces of the specs are removed by the specs themselves, only the
+// management cluster is torn down once all of the processes complete.
+var _ = SynchronizedAfterSuite(func() {}, func() {
 	if cleanup() {
 		By("collecting the support bundle from the management cluster")
-		logs.SupportBundle("")
+		logs.SupportBundle(nil, "")
+
+		if management.Existing() {
+			return
+		}
 
 		By("removing the controller-manager")
 		cmd := exec.Command("make", "dev-destroy")
@@ -100,64 +162,166 @@ var _ = AfterSuite(func() {
 	}
 })
 
-// verifyControllersUp validates that controllers for all providers are running
-// and ready.
-func verifyControllersUp(kc *kubeclient.KubeClient) error {
-	if err := validateController(kc, utils.KCMControllerLabel, "kcm-controller-manager"); err != nil {
-		return err
-	}
+// ReportAfterSuite is run by the first process only with the specs of all of
+// the processes aggregated.
+var _ = ReportAfterSuite("e2e report", func(r Report) {
+	dir, err := report.Dir()
+	Expect(err).NotTo(HaveOccurred())
+
+	Expect(report.Generate(r, dir)).To(Succeed())
+	_, _ = fmt.Fprintf(GinkgoWriter, "E2e reports are written to %s\n", dir)
 
-	providers := []clusterdeployment.ProviderType{
-		clusterdeployment.ProviderCAPI,
-		clusterdeployment.ProviderAWS,
-		clusterdeployment.ProviderAzure,
-		clusterdeployment.ProviderVSphere,
+	// the artifacts of the ephemeral runners are lost unless uploaded, the
+	// failed upload is not the failure of the run
+	if logs.UploadEnabled() {
+		url, err := logs.Upload(context.Background(), dir)
+		if err != nil {
+			utils.WarnError(err)
+			return
+		}
+		_, _ = fmt.Fprintf(GinkgoWriter, "E2e artifacts are uploaded to %s\n", url)
 	}
+})
+
+// controllerProviders returns the providers the controllers of which are
+// installed in the management cluster, they are discovered from the providers
+// of the CAPI operator, so the providers added to the Management are validated
+// with no changes of the suite.
+func controllerProviders(ctx context.Context, kc *kubeclient.KubeClient, timeout time.Duration) []clusterdeployment.ProviderType {
+	GinkgoHelper()
 
-	for _, provider := range providers {
-		// Ensure only one controller pod is running.
-		if err := validateController(kc, clusterdeployment.GetProviderLabel(provider), string(provider)); err != nil {
+	var providers []clusterdeployment.ProviderType
+	Eventually(func() error {
+		var err error
+		providers, err = clusterdeployment.InstalledProviders(ctx, kc.CrClient, kc.Namespace)
+		if err != nil {
 			return err
 		}
+		if !slices.Contains(providers, clusterdeployment.ProviderCAPI) {
+			return fmt.Errorf("the %s provider is not installed yet", clusterdeployment.ProviderCAPI)
+		}
+		return nil
+	}).WithContext(ctx).WithTimeout(timeout).WithPolling(config.Config.Timeouts.Polling).Should(Succeed())
+
+	_, _ = fmt.Fprintf(GinkgoWriter, "Found providers: %v\n", providers)
+	return providers
+}
+
+// startChaos starts deleting the pods of the kcm and provider controllers of
+// the management cluster at random points of the spec if the chaos mode is
+// enabled. The returned function stops the deletions and waits for the
+// controllers to recover.
+func startChaos() func() {
+	GinkgoHelper()
+
+	if !chaos.Enabled() {
+		return func() {}
 	}
 
-	return nil
+	kc := kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+
+	selectors := []string{utils.KCMControllerLabel}
+	for _, provider := range controllerProviders(context.Background(), kc, config.Config.Timeouts.ControllersReady) {
+		selectors = append(selectors, clusterdeployment.GetProviderLabel(provider))
+	}
+
+	stop := chaos.Start(kc, selectors...)
+	return func() {
+		stop()
+
+		By("waiting for the controllers to recover after the chaos")
+		waitForControllers(kc, config.Config.Timeouts.ControllersReady)
+	}
 }
 
-func validateController(kc *kubeclient.KubeClient, labelSelector, name string) error {
-	controllerItems := 1
-	if strings.Contains(labelSelector, clusterdeployment.GetProviderLabel(clusterdeployment.ProviderAzure)) {
-		// Azure provider has two controllers.
-		controllerItems = 2
+// upgradeCluster upgrades the cluster deployment to the upgrade template of
+// the testing configuration, through all of the templates of the upgrade
+// chain if the upgrade path is configured.
+func upgradeCluster(kc, childClient *kubeclient.KubeClient, name string, testingConfig config.ProviderTestingConfig) {
+	if len(testingConfig.UpgradeChain) > 0 {
+		pathUpgrade := upgrade.NewPathUpgrade(
+			kc.CrClient,
+			childClient.CrClient,
+			kc.Namespace,
+			name,
+			testingConfig.UpgradeChain,
+			upgrade.NewDefaultClusterValidator(),
+			testingConfig.Timeouts,
+		)
+		pathUpgrade.Run(context.Background())
+		return
 	}
 
-	deployList, err := kc.Client.AppsV1().Deployments(kc.Namespace).List(context.Background(), metav1.ListOptions{
-		LabelSelector: labelSelector,
-		Limit:         int64(controllerItems),
-	})
-	if err != nil {
-		return fmt.Errorf("failed to list %s controller deployments: %w", name, err)
+	clusterUpgrade := upgrade.NewClusterUpgrade(
+		kc.CrClient,
+		childClient.CrClient,
+		kc.Namespace,
+		name,
+		testingConfig.UpgradeTemplate,
+		upgrade.NewDefaultClusterValidator(),
+		testingConfig.Timeouts,
+	)
+	clusterUpgrade.Run(context.Background())
+}
+
+// resumeCluster returns the cluster of the spec, which is identified by the
+// postfix of the name of the cluster, recorded by the previous run in the
+// resume mode, a new one is named with the postfix otherwise.
+func resumeCluster(postfix string) *resume.Cluster {
+	GinkgoHelper()
+
+	c, ok, err := resume.Load(postfix)
+	Expect(err).NotTo(HaveOccurred())
+	if !ok {
+		return &resume.Cluster{Name: clusterdeployment.GenerateClusterName(postfix)}
 	}
 
-	if len(deployList.Items) < controllerItems {
-		return fmt.Errorf("expected at least %d %s controller deployments, got %d", controllerItems, name, len(deployList.Items))
+	By(fmt.Sprintf("resuming the %s cluster from the %s phase", c.Name, c.Phase))
+	return &c
+}
+
+// recordCluster records the phase reached by the cluster of the spec unless
+// it has been reached by the previous run.
+func recordCluster(postfix string, c *resume.Cluster, phase resume.Phase) {
+	GinkgoHelper()
+
+	if c.Reached(phase) {
+		return
 	}
+	c.Phase = phase
+	Expect(resume.Record(postfix, c.Name, phase)).To(Succeed())
+}
 
-	for _, deployment := range deployList.Items {
-		// Ensure the deployment is not being deleted.
-		if deployment.DeletionTimestamp != nil {
-			return fmt.Errorf("controller pod: %s deletion timestamp should be nil, got: %v",
-				deployment.Name, deployment.DeletionTimestamp)
-		}
-		// Ensure the deployment is running and has the expected name.
-		if !strings.Contains(deployment.Name, "controller-manager") {
-			return fmt.Errorf("controller deployment name %s does not contain 'controller-manager'", deployment.Name)
-		}
-		if deployment.Status.ReadyReplicas < 1 {
-			return fmt.Errorf("controller deployment: %s does not yet have any ReadyReplicas", deployment.Name)
-		}
+// forgetCluster removes the recorded state of the spec once its cluster is
+// deleted.
+func forgetCluster(postfix string) {
+	GinkgoHelper()
+	Expect(resume.Forget(postfix)).To(Succeed())
+}
+
+// waitForControllers waits for the controllers of kcm and all of the
+// providers to be running and ready within the timeout.
+func waitForControllers(kc *kubeclient.KubeClient, timeout time.Duration) {
+	GinkgoHelper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	wait.DeploymentsAvailable(ctx, kc.CrClient, kc.Namespace, utils.KCMControllerLabel, 1, timeout, controllerManagerName)
+
+	// all of the controllers of the provider are waited for, e.g. both of the
+	// CAPZ and ASO ones of Azure, since they are deployed at once
+	for _, provider := range controllerProviders(ctx, kc, timeout) {
+		wait.DeploymentsAvailable(ctx, kc.CrClient, kc.Namespace, clusterdeployment.GetProviderLabel(provider), 1, timeout, controllerManagerName)
 	}
+}
 
+// controllerManagerName checks that the name of the controller deployment
+// has the expected suffix.
+func controllerManagerName(obj *unstructured.Unstructured) error {
+	if !strings.Contains(obj.GetName(), "controller-manager") {
+		return fmt.Errorf("controller deployment name %s does not contain 'controller-manager'", obj.GetName())
+	}
 	return nil
 }
 
@@ -0,0 +1,62 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flake marks the known flaky e2e specs to be retried.
+package flake
+
+import (
+	"errors"
+	"os"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+const (
+	// FlakyLabel is the label of the known flaky specs.
+	FlakyLabel = "flaky"
+
+	// EnvVarAttempts is the number of the attempts the flaky specs are run
+	// with before they are failed.
+	EnvVarAttempts = "E2E_FLAKE_ATTEMPTS"
+
+	defaultAttempts = 3
+)
+
+// Retry returns the decorators of the known flaky specs, such specs are
+// labeled with the flaky label and run up to the configured number of
+// attempts, so the rerun succeeded ones are reported as flakes instead of
+// failures.
+func Retry() []any {
+	return []any{Label(FlakyLabel), FlakeAttempts(Attempts())}
+}
+
+// Attempts returns the number of the attempts of the flaky specs.
+func Attempts() int {
+	attempts, err := strconv.Atoi(os.Getenv(EnvVarAttempts))
+	if err != nil || attempts < 1 {
+		return defaultAttempts
+	}
+	return attempts
+}
+
+// ValidateConfiguration ensures the specs not labeled as flaky are not
+// retried, so the new flakes fail the runs instead of being hidden.
+func ValidateConfiguration() error {
+	suiteConfig, _ := GinkgoConfiguration()
+	if suiteConfig.FlakeAttempts > 0 {
+		return errors.New("retrying all of the specs with --flake-attempts is not allowed, label the flaky specs with flake.Retry() instead")
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flake
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAttempts(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected int
+	}{
+		{value: "", expected: defaultAttempts},
+		{value: "5", expected: 5},
+		{value: "0", expected: defaultAttempts},
+		{value: "many", expected: defaultAttempts},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Setenv(EnvVarAttempts, tc.value)
+			g.Expect(Attempts()).To(Equal(tc.expected))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeclient
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/K0rdent/kcm/internal/utils/status"
+)
+
+// FieldManager is the default field manager of the objects applied by the
+// e2e tests.
+const FieldManager = "kcm-e2e"
+
+type applyOptions struct {
+	fieldManager string
+}
+
+// ApplyOption configures the server-side apply.
+type ApplyOption func(*applyOptions)
+
+// WithFieldManager sets the field manager of the applied fields. The fields
+// owned by the manager and missing in the applied object are removed, so the
+// partial objects, e.g. the labels only, should be applied with their own
+// field manager not to remove the fields applied previously.
+func WithFieldManager(fieldManager string) ApplyOption {
+	return func(o *applyOptions) {
+		o.fieldManager = fieldManager
+	}
+}
+
+func newApplyOptions(opts []ApplyOption) applyOptions {
+	o := applyOptions{fieldManager: FieldManager}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Apply applies the typed or unstructured object with the server-side apply
+// taking the ownership of the conflicting fields. The object should contain
+// only the fields the test is interested in, since all of the fields sent are
+// owned by the field manager, e.g. the zero values of the typed objects.
+func Apply(ctx context.Context, cl crclient.Client, obj crclient.Object, opts ...ApplyOption) error {
+	o := newApplyOptions(opts)
+
+	// the apply patch requires the type meta and no managed fields, which
+	// are dropped from the typed objects
+	gvk, err := apiutil.GVKForObject(obj, cl.Scheme())
+	if err != nil {
+		return fmt.Errorf("failed to get GroupVersionKind of %T: %w", obj, err)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	if err := cl.Patch(ctx, obj, crclient.Apply, crclient.FieldOwner(o.fieldManager), crclient.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to apply %s %s: %w", gvk.Kind, crclient.ObjectKeyFromObject(obj), err)
+	}
+	return nil
+}
+
+// Apply applies the typed or unstructured object with the server-side apply
+// using the controller-runtime client.
+func (kc *KubeClient) Apply(ctx context.Context, obj crclient.Object, opts ...ApplyOption) {
+	GinkgoHelper()
+
+	Expect(Apply(ctx, kc.CrClient, obj, opts...)).To(Succeed())
+}
+
+// ApplyUnstructuredObject applies the unstructured object of the resource
+// which is not registered in the scheme with the server-side apply and returns
+// the resulting object.
+func (kc *KubeClient) ApplyUnstructuredObject(
+	ctx context.Context, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, namespaced bool, opts ...ApplyOption,
+) *unstructured.Unstructured {
+	GinkgoHelper()
+
+	o := newApplyOptions(opts)
+	kind, name := status.ObjKindName(obj)
+
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	client := kc.GetDynamicClient(gvr, namespaced)
+
+	var resp *unstructured.Unstructured
+	err := kc.Do(ctx, func(ctx context.Context) (err error) {
+		resp, err = client.Apply(ctx, name, obj, metav1.ApplyOptions{FieldManager: o.fieldManager, Force: true})
+		return err
+	})
+	Expect(err).NotTo(HaveOccurred(), "failed to apply %s: %s", kind, name)
+	return resp
+}
@@ -24,12 +24,14 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -37,7 +39,7 @@ import (
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/K0rdent/kcm/api/v1alpha1"
-	"github.com/K0rdent/kcm/internal/utils/status"
+	"github.com/K0rdent/kcm/test/e2e/config"
 	"github.com/K0rdent/kcm/test/scheme"
 )
 
@@ -48,6 +50,20 @@ type KubeClient struct {
 	Config         *rest.Config
 
 	Namespace string
+
+	// Labels are set on the objects created with the client, so they can be
+	// found and removed along with the namespace of the spec.
+	Labels map[string]string
+
+	// Backoff is the backoff of the retries of the calls failed with the
+	// transient errors.
+	Backoff wait.Backoff
+	// CallTimeout is the deadline of a single call to the API server, the
+	// calls exceeding it are retried.
+	CallTimeout time.Duration
+
+	// rawCrClient is the controller-runtime client without the retries.
+	rawCrClient crclient.WithWatch
 }
 
 // NewFromLocal creates a new instance of KubeClient from a given namespace
@@ -65,6 +81,38 @@ func (kc *KubeClient) NewFromCluster(ctx context.Context, namespace, clusterName
 	return newKubeClient(kc.GetKubeconfigSecretData(ctx, clusterName), namespace)
 }
 
+// WithNamespace returns a copy of the KubeClient operating on the given
+// namespace.
+func (kc *KubeClient) WithNamespace(namespace string) *KubeClient {
+	c := *kc
+	c.Namespace = namespace
+	return &c
+}
+
+// WithLabels returns a copy of the KubeClient setting the given labels on the
+// objects it creates.
+func (kc *KubeClient) WithLabels(labels map[string]string) *KubeClient {
+	c := *kc
+	c.Labels = labels
+	return &c
+}
+
+// SetLabels sets the labels of the KubeClient on the object keeping its own
+// ones.
+func (kc *KubeClient) SetLabels(obj metav1.Object) {
+	if len(kc.Labels) == 0 {
+		return
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string, len(kc.Labels))
+	}
+	for k, v := range kc.Labels {
+		labels[k] = v
+	}
+	obj.SetLabels(labels)
+}
+
 // WriteKubeconfig writes the kubeconfig for the given clusterName to the
 // test/e2e directory returning the path to the file and a function to delete
 // it later.
@@ -98,7 +146,11 @@ func (kc *KubeClient) WriteKubeconfig(ctx context.Context, clusterName string) (
 func (kc *KubeClient) GetKubeconfigSecretData(ctx context.Context, clusterName string) []byte {
 	GinkgoHelper()
 
-	secret, err := kc.Client.CoreV1().Secrets(kc.Namespace).Get(ctx, clusterName+"-kubeconfig", metav1.GetOptions{})
+	var secret *corev1.Secret
+	err := kc.Do(ctx, func(ctx context.Context) (err error) {
+		secret, err = kc.Client.CoreV1().Secrets(kc.Namespace).Get(ctx, clusterName+"-kubeconfig", metav1.GetOptions{})
+		return err
+	})
 	Expect(err).NotTo(HaveOccurred(), "failed to get cluster: %q kubeconfig secret", clusterName)
 
 	secretData, ok := secret.Data["value"]
@@ -141,15 +193,19 @@ func newKubeClient(configBytes []byte, namespace string) *KubeClient {
 	extendedClientSet, err := apiextensionsclientset.NewForConfig(config)
 	Expect(err).NotTo(HaveOccurred(), "failed to initialize apiextensions clientset")
 
-	crClient, err := crclient.New(config, crclient.Options{Scheme: scheme.Scheme})
+	crClient, err := crclient.NewWithWatch(config, crclient.Options{Scheme: scheme.Scheme})
 	Expect(err).NotTo(HaveOccurred(), "failed to create controller runtime client")
 
+	callTimeout := CallTimeout()
 	return &KubeClient{
 		Namespace:      namespace,
 		Client:         clientSet,
-		CrClient:       crClient,
+		CrClient:       retryingClient(crClient, DefaultBackoff, callTimeout),
 		ExtendedClient: extendedClientSet,
 		Config:         config,
+		Backoff:        DefaultBackoff,
+		CallTimeout:    callTimeout,
+		rawCrClient:    crClient,
 	}
 }
 
@@ -167,53 +223,40 @@ func (kc *KubeClient) GetDynamicClient(gvr schema.GroupVersionResource, namespac
 	return client.Resource(gvr).Namespace(kc.Namespace)
 }
 
-func (kc *KubeClient) CreateOrUpdateUnstructuredObject(gvr schema.GroupVersionResource, obj *unstructured.Unstructured, namespaced bool) {
-	GinkgoHelper()
-
-	client := kc.GetDynamicClient(gvr, namespaced)
-
-	kind, name := status.ObjKindName(obj)
-
-	resp, err := client.Get(context.Background(), name, metav1.GetOptions{})
-	if apierrors.IsNotFound(err) {
-		_, err = client.Create(context.Background(), obj, metav1.CreateOptions{})
-		Expect(err).NotTo(HaveOccurred(), "failed to create %s: %s", kind, name)
-	} else {
-		Expect(err).NotTo(HaveOccurred(), "failed to get existing %s: %s", kind, name)
-
-		obj.SetResourceVersion(resp.GetResourceVersion())
-		_, err = client.Update(context.Background(), obj, metav1.UpdateOptions{})
-		Expect(err).NotTo(HaveOccurred(), "failed to update existing %s: %s", kind, name)
-	}
-}
-
 // CreateClusterDeployment creates a clusterdeployment.k0rdent.mirantis.com in the given
 // namespace and returns a DeleteFunc to clean up the deployment.
-// The DeleteFunc is a no-op if the deployment has already been deleted.
+// The DeleteFunc is a no-op if the deployment has already been deleted, it
+// waits for the deletion within the Deletion timeout of the given timeouts.
 func (kc *KubeClient) CreateClusterDeployment(
-	ctx context.Context, clusterDeployment *unstructured.Unstructured,
+	ctx context.Context, clusterDeployment *unstructured.Unstructured, timeouts config.Timeouts,
 ) func() error {
 	GinkgoHelper()
 
 	kind := clusterDeployment.GetKind()
 	Expect(kind).To(Equal("ClusterDeployment"))
+	kc.SetLabels(clusterDeployment)
 
 	client := kc.GetDynamicClient(v1alpha1.GroupVersion.WithResource("clusterdeployments"), true)
 
-	_, err := client.Create(ctx, clusterDeployment, metav1.CreateOptions{})
+	err := kc.Do(ctx, func(ctx context.Context) error {
+		_, err := client.Create(ctx, clusterDeployment, metav1.CreateOptions{})
+		return err
+	})
 	if !apierrors.IsAlreadyExists(err) {
 		Expect(err).NotTo(HaveOccurred(), "failed to create %s", kind)
 	}
 
 	return func() error {
 		name := clusterDeployment.GetName()
-		if err := client.Delete(ctx, name, metav1.DeleteOptions{}); crclient.IgnoreNotFound(err) != nil {
+		if err := kc.Do(ctx, func(ctx context.Context) error {
+			return client.Delete(ctx, name, metav1.DeleteOptions{})
+		}); crclient.IgnoreNotFound(err) != nil {
 			return err
 		}
 		Eventually(func() bool {
 			_, err := client.Get(ctx, name, metav1.GetOptions{})
 			return apierrors.IsNotFound(err)
-		}, 30*time.Minute, 1*time.Minute).Should(BeTrue())
+		}).WithTimeout(timeouts.Deletion).WithPolling(timeouts.Polling).Should(BeTrue())
 		return nil
 	}
 }
@@ -268,7 +311,11 @@ func (kc *KubeClient) getResource(
 ) (*unstructured.Unstructured, error) {
 	client := kc.GetDynamicClient(gvr, true)
 
-	resource, err := client.Get(ctx, name, metav1.GetOptions{})
+	var resource *unstructured.Unstructured
+	err := kc.Do(ctx, func(ctx context.Context) (err error) {
+		resource, err = client.Get(ctx, name, metav1.GetOptions{})
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get %s %s: %w", gvr.Resource, name, err)
 	}
@@ -283,11 +330,15 @@ func (kc *KubeClient) listResource(
 ) ([]unstructured.Unstructured, error) {
 	client := kc.GetDynamicClient(gvr, true)
 
-	resources, err := client.List(ctx, metav1.ListOptions{
-		LabelSelector: "cluster.x-k8s.io/cluster-name=" + clusterName,
+	var resources *unstructured.UnstructuredList
+	err := kc.Do(ctx, func(ctx context.Context) (err error) {
+		resources, err = client.List(ctx, metav1.ListOptions{
+			LabelSelector: "cluster.x-k8s.io/cluster-name=" + clusterName,
+		})
+		return err
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list %s", gvr.Resource)
+		return nil, fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
 	}
 
 	return resources.Items, nil
@@ -303,9 +354,13 @@ func (kc *KubeClient) patchResource(
 ) (*unstructured.Unstructured, error) {
 	client := kc.GetDynamicClient(gvr, true)
 
-	resource, err := client.Patch(ctx, name, pt, data, metav1.PatchOptions{})
+	var resource *unstructured.Unstructured
+	err := kc.Do(ctx, func(ctx context.Context) (err error) {
+		resource, err = client.Patch(ctx, name, pt, data, metav1.PatchOptions{})
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to patch %s %s", gvr.Resource, name)
+		return nil, fmt.Errorf("failed to patch %s %s: %w", gvr.Resource, name, err)
 	}
 	return resource, nil
 }
@@ -417,9 +472,13 @@ func (kc *KubeClient) ListClusterTemplates(ctx context.Context) ([]unstructured.
 		Resource: "clustertemplates",
 	}, true)
 
-	resources, err := client.List(ctx, metav1.ListOptions{})
+	var resources *unstructured.UnstructuredList
+	err := kc.Do(ctx, func(ctx context.Context) (err error) {
+		resources, err = client.List(ctx, metav1.ListOptions{})
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list cluster templates")
+		return nil, fmt.Errorf("failed to list cluster templates: %w", err)
 	}
 
 	return resources.Items, nil
@@ -433,6 +492,7 @@ func (kc *KubeClient) CreateMultiClusterService(
 
 	kind := multiClusterService.GetKind()
 	Expect(kind).To(Equal("MultiClusterService"))
+	kc.SetLabels(multiClusterService)
 
 	client := kc.GetDynamicClient(schema.GroupVersionResource{
 		Group:    "k0rdent.mirantis.com",
@@ -440,13 +500,18 @@ func (kc *KubeClient) CreateMultiClusterService(
 		Resource: "multiclusterservices",
 	}, false)
 
-	_, err := client.Create(ctx, multiClusterService, metav1.CreateOptions{})
+	err := kc.Do(ctx, func(ctx context.Context) error {
+		_, err := client.Create(ctx, multiClusterService, metav1.CreateOptions{})
+		return err
+	})
 	if !apierrors.IsAlreadyExists(err) {
 		Expect(err).NotTo(HaveOccurred(), "failed to create %s", kind)
 	}
 
 	return func() error {
-		err := client.Delete(ctx, multiClusterService.GetName(), metav1.DeleteOptions{})
+		err := kc.Do(ctx, func(ctx context.Context) error {
+			return client.Delete(ctx, multiClusterService.GetName(), metav1.DeleteOptions{})
+		})
 		if apierrors.IsNotFound(err) {
 			return nil
 		}
@@ -0,0 +1,182 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForward forwards a random local port to the given port of the pod and
+// returns the local port and the function stopping the forwarding. The
+// forwarding is stopped once the context is done as well.
+func (kc *KubeClient) PortForward(ctx context.Context, podName string, port int) (int, func(), error) {
+	transport, upgrader, err := spdy.RoundTripperFor(kc.Config)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create round tripper: %w", err)
+	}
+
+	req := kc.Client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(kc.Namespace).
+		Name(podName).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	stopCh, readyCh := make(chan struct{}), make(chan struct{})
+	stop := sync.OnceFunc(func() { close(stopCh) })
+
+	fw, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", port)}, stopCh, readyCh, GinkgoWriter, GinkgoWriter)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create port forwarder to pod %s/%s: %w", kc.Namespace, podName, err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fw.ForwardPorts()
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return 0, nil, fmt.Errorf("failed to forward port %d of pod %s/%s: %w", port, kc.Namespace, podName, err)
+	case <-ctx.Done():
+		stop()
+		return 0, nil, ctx.Err()
+	}
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	ports, err := fw.GetPorts()
+	if err != nil {
+		stop()
+		return 0, nil, fmt.Errorf("failed to get forwarded ports: %w", err)
+	}
+	return int(ports[0].Local), stop, nil
+}
+
+// PortForwardService forwards a random local port to the given port of the
+// service through one of its ready pods, see PortForward.
+func (kc *KubeClient) PortForwardService(ctx context.Context, serviceName string, port int) (int, func(), error) {
+	svc, err := kc.Client.CoreV1().Services(kc.Namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get service %s/%s: %w", kc.Namespace, serviceName, err)
+	}
+
+	var targetPort *intstr.IntOrString
+	for _, p := range svc.Spec.Ports {
+		if int(p.Port) == port {
+			targetPort = &p.TargetPort
+			break
+		}
+	}
+	if targetPort == nil {
+		return 0, nil, fmt.Errorf("service %s/%s has no port %d", kc.Namespace, serviceName, port)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return 0, nil, fmt.Errorf("service %s/%s has no selector", kc.Namespace, serviceName)
+	}
+
+	pods, err := kc.Client.CoreV1().Pods(kc.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to list pods of service %s/%s: %w", kc.Namespace, serviceName, err)
+	}
+
+	for _, pod := range pods.Items {
+		if !isPodReady(&pod) {
+			continue
+		}
+		podPort, err := resolvePodPort(&pod, *targetPort)
+		if err != nil {
+			return 0, nil, err
+		}
+		return kc.PortForward(ctx, pod.Name, podPort)
+	}
+	return 0, nil, fmt.Errorf("service %s/%s has no ready pods", kc.Namespace, serviceName)
+}
+
+// Exec runs the command in the container of the pod and returns its stdout
+// and stderr. The first container of the pod is used if the container is
+// empty.
+func (kc *KubeClient) Exec(ctx context.Context, podName, container string, command ...string) (string, string, error) {
+	req := kc.Client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(kc.Namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, clientgoscheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(kc.Config, http.MethodPost, req.URL())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+		return stdout.String(), stderr.String(), fmt.Errorf("failed to exec %v in pod %s/%s: %w", command, kc.Namespace, podName, err)
+	}
+	return stdout.String(), stderr.String(), nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || !pod.DeletionTimestamp.IsZero() {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// resolvePodPort returns the number of the container port the target port of
+// the service refers to.
+func resolvePodPort(pod *corev1.Pod, targetPort intstr.IntOrString) (int, error) {
+	if targetPort.Type == intstr.Int {
+		return targetPort.IntValue(), nil
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == targetPort.StrVal {
+				return int(p.ContainerPort), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("pod %s has no port named %s", pod.Name, targetPort.StrVal)
+}
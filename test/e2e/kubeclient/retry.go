@@ -0,0 +1,181 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeclient
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	// EnvVarCallTimeout overrides the deadline of a single call to the API
+	// server, e.g. 1m.
+	EnvVarCallTimeout = "E2E_API_CALL_TIMEOUT"
+
+	defaultCallTimeout = 30 * time.Second
+)
+
+// DefaultBackoff is the backoff of the retries of the calls failed with the
+// transient errors, the calls are given up in about half a minute.
+var DefaultBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    6,
+	Cap:      10 * time.Second,
+}
+
+// CallTimeout returns the deadline of a single call to the API server.
+func CallTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv(EnvVarCallTimeout))
+	if err != nil || timeout <= 0 {
+		return defaultCallTimeout
+	}
+	return timeout
+}
+
+// IsTransient reports whether the call failed with the error is likely to
+// succeed once retried: the API server is throttling or unavailable, failed
+// internally or the connection to it has been broken.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsUnexpectedServerError(err) {
+		return true
+	}
+
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return status.Status().Code >= 500
+	}
+
+	return utilnet.IsConnectionReset(err) || utilnet.IsConnectionRefused(err) || utilnet.IsProbableEOF(err)
+}
+
+// Retry calls fn with the deadline of the call and retries it with the
+// backoff while it fails with the transient errors or exceeds the deadline.
+// The last error of fn is returned once it fails with a non-transient error,
+// the retries are exhausted or ctx is done. fn is called once if the backoff
+// has no steps.
+func Retry(ctx context.Context, backoff wait.Backoff, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if backoff.Steps < 1 {
+		backoff.Steps = 1
+	}
+
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, backoff, func(ctx context.Context) (bool, error) {
+		callCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout > 0 {
+			callCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		defer cancel()
+
+		lastErr = fn(callCtx)
+		switch {
+		case lastErr == nil:
+			return true, nil
+		case ctx.Err() != nil:
+			return false, lastErr
+		case IsTransient(lastErr), errors.Is(lastErr, context.DeadlineExceeded):
+			return false, nil
+		default:
+			return false, lastErr
+		}
+	})
+	if err != nil && lastErr != nil {
+		return lastErr
+	}
+	return err
+}
+
+// Do calls fn with the deadline and the retries of the client.
+func (kc *KubeClient) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return Retry(ctx, kc.Backoff, kc.CallTimeout, fn)
+}
+
+// WithRetries returns a copy of the KubeClient the calls of which are
+// retried with the given backoff and deadline of a single call.
+func (kc *KubeClient) WithRetries(backoff wait.Backoff, callTimeout time.Duration) *KubeClient {
+	c := *kc
+	c.Backoff = backoff
+	c.CallTimeout = callTimeout
+	c.CrClient = retryingClient(kc.rawCrClient, backoff, callTimeout)
+	return &c
+}
+
+// retryingClient wraps the controller-runtime client to retry its calls, the
+// watches are not retried since they are long-running.
+func retryingClient(cl crclient.WithWatch, backoff wait.Backoff, timeout time.Duration) crclient.Client {
+	do := func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return Retry(ctx, backoff, timeout, fn)
+	}
+
+	return interceptor.NewClient(cl, interceptor.Funcs{
+		Get: func(ctx context.Context, cl crclient.WithWatch, key crclient.ObjectKey, obj crclient.Object, opts ...crclient.GetOption) error {
+			return do(ctx, func(ctx context.Context) error { return cl.Get(ctx, key, obj, opts...) })
+		},
+		List: func(ctx context.Context, cl crclient.WithWatch, list crclient.ObjectList, opts ...crclient.ListOption) error {
+			return do(ctx, func(ctx context.Context) error { return cl.List(ctx, list, opts...) })
+		},
+		Create: func(ctx context.Context, cl crclient.WithWatch, obj crclient.Object, opts ...crclient.CreateOption) error {
+			return do(ctx, func(ctx context.Context) error { return cl.Create(ctx, obj, opts...) })
+		},
+		Delete: func(ctx context.Context, cl crclient.WithWatch, obj crclient.Object, opts ...crclient.DeleteOption) error {
+			return do(ctx, func(ctx context.Context) error { return cl.Delete(ctx, obj, opts...) })
+		},
+		DeleteAllOf: func(ctx context.Context, cl crclient.WithWatch, obj crclient.Object, opts ...crclient.DeleteAllOfOption) error {
+			return do(ctx, func(ctx context.Context) error { return cl.DeleteAllOf(ctx, obj, opts...) })
+		},
+		Update: func(ctx context.Context, cl crclient.WithWatch, obj crclient.Object, opts ...crclient.UpdateOption) error {
+			return do(ctx, func(ctx context.Context) error { return cl.Update(ctx, obj, opts...) })
+		},
+		Patch: func(ctx context.Context, cl crclient.WithWatch, obj crclient.Object, patch crclient.Patch, opts ...crclient.PatchOption) error {
+			return do(ctx, func(ctx context.Context) error { return cl.Patch(ctx, obj, patch, opts...) })
+		},
+		SubResourceGet: func(ctx context.Context, cl crclient.Client, subResourceName string, obj, subResource crclient.Object, opts ...crclient.SubResourceGetOption) error {
+			return do(ctx, func(ctx context.Context) error {
+				return cl.SubResource(subResourceName).Get(ctx, obj, subResource, opts...)
+			})
+		},
+		SubResourceCreate: func(ctx context.Context, cl crclient.Client, subResourceName string, obj, subResource crclient.Object, opts ...crclient.SubResourceCreateOption) error {
+			return do(ctx, func(ctx context.Context) error {
+				return cl.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+			})
+		},
+		SubResourceUpdate: func(ctx context.Context, cl crclient.Client, subResourceName string, obj crclient.Object, opts ...crclient.SubResourceUpdateOption) error {
+			return do(ctx, func(ctx context.Context) error { return cl.SubResource(subResourceName).Update(ctx, obj, opts...) })
+		},
+		SubResourcePatch: func(ctx context.Context, cl crclient.Client, subResourceName string, obj crclient.Object, patch crclient.Patch, opts ...crclient.SubResourcePatchOption) error {
+			return do(ctx, func(ctx context.Context) error {
+				return cl.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			})
+		},
+	})
+}
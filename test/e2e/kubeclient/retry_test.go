@@ -0,0 +1,144 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var testBackoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+
+func TestIsTransient(t *testing.T) {
+	resource := schema.GroupResource{Group: "k0rdent.mirantis.com", Resource: "clusterdeployments"}
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil"},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("throttled", 1), expected: true},
+		{name: "internal", err: apierrors.NewInternalError(errors.New("etcd is unavailable")), expected: true},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("unavailable"), expected: true},
+		{name: "server timeout", err: apierrors.NewServerTimeout(resource, "get", 1), expected: true},
+		{name: "bad gateway", err: apierrors.NewGenericServerResponse(502, "get", resource, "test", "", 0, true), expected: true},
+		{name: "connection reset", err: fmt.Errorf("failed to get: %w", syscall.ECONNRESET), expected: true},
+		{name: "connection refused", err: fmt.Errorf("failed to get: %w", syscall.ECONNREFUSED), expected: true},
+		{name: "not found", err: apierrors.NewNotFound(resource, "test")},
+		{name: "conflict", err: apierrors.NewConflict(resource, "test", errors.New("modified"))},
+		{name: "forbidden", err: apierrors.NewForbidden(resource, "test", errors.New("denied"))},
+		{name: "other", err: errors.New("invalid object")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			NewWithT(t).Expect(IsTransient(tc.err)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	transient := apierrors.NewTooManyRequests("throttled", 1)
+
+	t.Run("succeeds after transient errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		calls := 0
+		err := Retry(context.Background(), testBackoff, time.Second, func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return transient
+			}
+			return nil
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(calls).To(Equal(3))
+	})
+
+	t.Run("stops on non-transient error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		calls := 0
+		notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "test")
+		err := Retry(context.Background(), testBackoff, time.Second, func(context.Context) error {
+			calls++
+			return notFound
+		})
+		g.Expect(err).To(MatchError(notFound))
+		g.Expect(calls).To(Equal(1))
+	})
+
+	t.Run("returns last error once retries are exhausted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		calls := 0
+		err := Retry(context.Background(), testBackoff, time.Second, func(context.Context) error {
+			calls++
+			return transient
+		})
+		g.Expect(err).To(MatchError(transient))
+		g.Expect(calls).To(Equal(testBackoff.Steps))
+	})
+
+	t.Run("retries calls exceeding deadline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		calls := 0
+		err := Retry(context.Background(), testBackoff, 10*time.Millisecond, func(ctx context.Context) error {
+			calls++
+			if calls == 1 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(calls).To(Equal(2))
+	})
+
+	t.Run("stops once context is done", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := Retry(ctx, testBackoff, time.Second, func(context.Context) error {
+			calls++
+			cancel()
+			return transient
+		})
+		g.Expect(err).To(MatchError(transient))
+		g.Expect(calls).To(Equal(1))
+	})
+
+	t.Run("calls once without backoff steps", func(t *testing.T) {
+		g := NewWithT(t)
+
+		calls := 0
+		err := Retry(context.Background(), wait.Backoff{}, 0, func(context.Context) error {
+			calls++
+			return transient
+		})
+		g.Expect(err).To(MatchError(transient))
+		g.Expect(calls).To(Equal(1))
+	})
+}
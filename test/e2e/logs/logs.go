@@ -15,6 +15,7 @@
 package logs
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -22,27 +23,91 @@ import (
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
-	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/report"
 	"github.com/K0rdent/kcm/test/utils"
 )
 
-// SupportBundle collects the support bundle from the specified cluster.
-// If the clusterName is unset, it collects the support bundle from the management cluster.
-func SupportBundle(clusterName string) {
+const workloadSupportBundleConfig = "config/support-bundle-workload.yaml"
+
+// SupportBundle collects the support bundle from the specified cluster into
+// the artifact directory of the current spec and records it as the artifact of
+// the spec. If the clusterName is unset, it collects the support bundle from
+// the management cluster. Otherwise the logs and events of the workload cluster
+// are collected using its kubeconfig stored in the namespace of the given
+// client. The failures are reported as warnings only, so the cleanup is not
+// interrupted.
+func SupportBundle(kc *kubeclient.KubeClient, clusterName string) {
+	dir, err := report.SpecDir()
+	if err != nil {
+		utils.WarnError(fmt.Errorf("failed to get the artifact directory: %w", err))
+		return
+	}
+
+	bundleName := "management"
 	var args []string
 	if clusterName != "" {
-		dir, err := os.Getwd()
-		Expect(err).NotTo(HaveOccurred())
+		kubeconfig, err := writeKubeconfig(kc, clusterName)
+		if err != nil {
+			utils.WarnError(fmt.Errorf("failed to collect the support bundle from the %s cluster: %w", clusterName, err))
+			return
+		}
+		defer os.Remove(kubeconfig)
+
+		// the clusters of the ephemeral runners are inspected with the
+		// kubeconfigs uploaded along with the bundles while they exist
+		if UploadEnabled() {
+			keepKubeconfig(kubeconfig, filepath.Join(dir, clusterName+"-kubeconfig"))
+		}
 
-		args = append(args, fmt.Sprintf("KUBECONFIG=%s", filepath.Join(dir, clusterName+"-kubeconfig")))
+		args = append(args, "KUBECONFIG="+kubeconfig, "SUPPORT_BUNDLE_CONFIG="+workloadSupportBundleConfig)
+		bundleName = clusterName
 	}
-	args = append(args, "support-bundle")
+
+	output := filepath.Join(dir, fmt.Sprintf("support-bundle-%s-%s", bundleName, time.Now().Format("2006-01-02T15_04_05")))
+	args = append(args, "support-bundle", "SUPPORT_BUNDLE_OUTPUT="+output)
 	cmd := exec.Command("make", args...)
-	_, err := utils.Run(cmd)
-	if err != nil {
+	if _, err := utils.Run(cmd); err != nil {
 		utils.WarnError(fmt.Errorf("failed to collect the support bundle: %w", err))
+		return
+	}
+	report.Artifact(output + ".tar.gz")
+}
+
+// writeKubeconfig writes the kubeconfig of the workload cluster to the
+// temporary file and returns its path.
+func writeKubeconfig(kc *kubeclient.KubeClient, clusterName string) (string, error) {
+	secret, err := kc.Client.CoreV1().Secrets(kc.Namespace).Get(context.Background(), clusterName+"-kubeconfig", metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the kubeconfig: %w", err)
+	}
+
+	f, err := os.CreateTemp("", clusterName+"-kubeconfig-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create the kubeconfig file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(secret.Data["value"]); err != nil {
+		return "", fmt.Errorf("failed to write the kubeconfig: %w", err)
+	}
+	return f.Name(), f.Close()
+}
+
+// keepKubeconfig copies the kubeconfig to the artifact directory of the spec
+// and records it as the artifact of the spec.
+func keepKubeconfig(kubeconfig, path string) {
+	data, err := os.ReadFile(kubeconfig)
+	if err == nil {
+		err = os.WriteFile(path, data, 0o600)
+	}
+	if err != nil {
+		utils.WarnError(fmt.Errorf("failed to keep the kubeconfig: %w", err))
+		return
 	}
+	report.Artifact(path)
 }
 
 func Println(msg string) {
@@ -0,0 +1,141 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/report"
+	"github.com/K0rdent/kcm/test/utils"
+)
+
+// dumpedKinds are the kinds of the objects dumped upon the failure of the
+// spec: the objects of kcm, the Flux sources and releases, the CAPI objects,
+// the providers of the CAPI operator and the Sveltos profiles.
+var dumpedKinds = []schema.GroupVersionKind{
+	{Group: "k0rdent.mirantis.com", Version: "v1alpha1", Kind: "Management"},
+	{Group: "k0rdent.mirantis.com", Version: "v1alpha1", Kind: "Release"},
+	{Group: "k0rdent.mirantis.com", Version: "v1alpha1", Kind: "ProviderTemplate"},
+	{Group: "k0rdent.mirantis.com", Version: "v1alpha1", Kind: "ClusterTemplate"},
+	{Group: "k0rdent.mirantis.com", Version: "v1alpha1", Kind: "ServiceTemplate"},
+	{Group: "k0rdent.mirantis.com", Version: "v1alpha1", Kind: "Credential"},
+	{Group: "k0rdent.mirantis.com", Version: "v1alpha1", Kind: "ClusterDeployment"},
+	{Group: "k0rdent.mirantis.com", Version: "v1alpha1", Kind: "MultiClusterService"},
+	{Group: "helm.toolkit.fluxcd.io", Version: "v2", Kind: "HelmRelease"},
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "HelmChart"},
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "HelmRepository"},
+	{Group: "cluster.x-k8s.io", Version: "v1beta1", Kind: "Cluster"},
+	{Group: "cluster.x-k8s.io", Version: "v1beta1", Kind: "MachineDeployment"},
+	{Group: "cluster.x-k8s.io", Version: "v1beta1", Kind: "MachineSet"},
+	{Group: "cluster.x-k8s.io", Version: "v1beta1", Kind: "Machine"},
+	{Group: "operator.cluster.x-k8s.io", Version: "v1alpha2", Kind: "CoreProvider"},
+	{Group: "operator.cluster.x-k8s.io", Version: "v1alpha2", Kind: "BootstrapProvider"},
+	{Group: "operator.cluster.x-k8s.io", Version: "v1alpha2", Kind: "ControlPlaneProvider"},
+	{Group: "operator.cluster.x-k8s.io", Version: "v1alpha2", Kind: "InfrastructureProvider"},
+	{Group: "config.projectsveltos.io", Version: "v1beta1", Kind: "ClusterProfile"},
+	{Group: "config.projectsveltos.io", Version: "v1beta1", Kind: "Profile"},
+	{Group: "config.projectsveltos.io", Version: "v1beta1", Kind: "ClusterSummary"},
+	{Group: "lib.projectsveltos.io", Version: "v1beta1", Kind: "SveltosCluster"},
+	{Group: "", Version: "v1", Kind: "Event"},
+}
+
+// DumpResources writes the objects of the dumped kinds in the namespaces and
+// the cluster-scoped ones of the management cluster to the artifact directory
+// of the current spec, a file per kind, and records the directory as the
+// artifact of the spec. The failures are reported as warnings only, so the
+// cleanup is not interrupted.
+func DumpResources(kc *kubeclient.KubeClient, namespaces ...string) {
+	dir, err := report.SpecDir()
+	if err != nil {
+		utils.WarnError(fmt.Errorf("failed to get the artifact directory: %w", err))
+		return
+	}
+
+	dir = filepath.Join(dir, "resources-"+time.Now().Format("2006-01-02T15_04_05"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		utils.WarnError(fmt.Errorf("failed to create the resources directory: %w", err))
+		return
+	}
+
+	ctx := context.Background()
+	for _, gvk := range dumpedKinds {
+		if err := dumpKind(ctx, kc.CrClient, dir, gvk, namespaces); err != nil {
+			utils.WarnError(fmt.Errorf("failed to dump %s: %w", gvk.Kind, err))
+		}
+	}
+	report.Artifact(dir)
+}
+
+// dumpKind writes the objects of the kind to the file named after the kind
+// and its group, the kinds not installed in the cluster are skipped.
+func dumpKind(ctx context.Context, cl crclient.Client, dir string, gvk schema.GroupVersionKind, namespaces []string) error {
+	namespaced, err := cl.IsObjectNamespaced(&unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": gvk.GroupVersion().String(),
+		"kind":       gvk.Kind,
+	}})
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	listNamespaces := []string{""}
+	if namespaced {
+		listNamespaces = namespaces
+	}
+
+	var docs []string
+	for _, namespace := range listNamespaces {
+		list := new(unstructured.UnstructuredList)
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := cl.List(ctx, list, crclient.InNamespace(namespace)); err != nil {
+			return fmt.Errorf("failed to list: %w", err)
+		}
+
+		for _, obj := range list.Items {
+			// the managed fields are of no use for the debugging and make
+			// the dumps several times bigger
+			obj.SetManagedFields(nil)
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return fmt.Errorf("failed to encode %s: %w", crclient.ObjectKeyFromObject(&obj), err)
+			}
+			docs = append(docs, string(data))
+		}
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	name := strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		name += "." + gvk.Group
+	}
+	path := filepath.Join(dir, name+".yaml")
+	return os.WriteFile(path, []byte(strings.Join(docs, "---\n")), 0o644)
+}
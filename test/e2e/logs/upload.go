@@ -0,0 +1,127 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
+	"github.com/K0rdent/kcm/test/utils"
+)
+
+const (
+	// EnvVarUploadURL is the URL of the object storage the artifacts of the
+	// run are uploaded to, e.g. s3://bucket/prefix, gs://bucket/prefix or
+	// azblob://account/container/prefix. The artifacts are not uploaded if
+	// unset.
+	EnvVarUploadURL = "E2E_ARTIFACTS_URL"
+	// EnvVarRunID is the ID of the run the artifacts are uploaded under,
+	// defaults to the ID and the attempt of the GitHub Actions run or to the
+	// start time of the run.
+	EnvVarRunID = "E2E_RUN_ID"
+	// EnvVarGCloudCLI is the path to the gcloud CLI uploading to GCS.
+	EnvVarGCloudCLI = "GCLOUD"
+)
+
+// startTime is the default ID of the run outside of GitHub Actions.
+var startTime = time.Now().UTC().Format("20060102T150405Z")
+
+// UploadEnabled reports whether the artifacts are uploaded to the object
+// storage.
+func UploadEnabled() bool {
+	return os.Getenv(EnvVarUploadURL) != ""
+}
+
+// RunID returns the ID of the run the artifacts are uploaded under.
+func RunID() string {
+	if id := os.Getenv(EnvVarRunID); id != "" {
+		return id
+	}
+	if id := os.Getenv("GITHUB_RUN_ID"); id != "" {
+		if attempt := os.Getenv("GITHUB_RUN_ATTEMPT"); attempt != "" {
+			return id + "-" + attempt
+		}
+		return id
+	}
+	return startTime
+}
+
+// Upload uploads the contents of the directory, e.g. the reports, the support
+// bundles and the kubeconfigs of the clusters of the failed specs, to the
+// object storage under the ID of the run and returns the URL of the uploaded
+// artifacts. The S3, GCS and Azure Blob storages are uploaded to with the aws,
+// gcloud and az CLIs respectively, which are authenticated by the environment.
+func Upload(ctx context.Context, dir string) (string, error) {
+	dest, err := url.Parse(os.Getenv(EnvVarUploadURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", EnvVarUploadURL, err)
+	}
+	dest.Path = path.Join("/", dest.Path, RunID())
+
+	cmd, err := uploadCommand(ctx, dest, dir)
+	if err != nil {
+		return "", err
+	}
+	if _, err := utils.Run(cmd); err != nil {
+		return "", fmt.Errorf("failed to upload the artifacts to %s: %w", dest, err)
+	}
+	return dest.String(), nil
+}
+
+// uploadCommand returns the command uploading the directory to the
+// destination of the object storage.
+func uploadCommand(ctx context.Context, dest *url.URL, dir string) (*exec.Cmd, error) {
+	if dest.Host == "" {
+		return nil, fmt.Errorf("no bucket is set in %s", dest)
+	}
+
+	switch dest.Scheme {
+	case "s3":
+		return exec.CommandContext(ctx, cli(clusterdeployment.EnvVarAWSCLI, "aws"),
+			"s3", "sync", dir, dest.String(), "--only-show-errors"), nil
+	case "gs":
+		return exec.CommandContext(ctx, cli(EnvVarGCloudCLI, "gcloud"),
+			"storage", "rsync", dir, dest.String(), "--recursive"), nil
+	case "azblob":
+		container, prefix, _ := strings.Cut(strings.TrimPrefix(dest.Path, "/"), "/")
+		if container == "" || prefix == "" {
+			return nil, fmt.Errorf("no container is set in %s", dest)
+		}
+		return exec.CommandContext(ctx, cli(clusterdeployment.EnvVarAzureCLI, "az"),
+			"storage", "blob", "upload-batch",
+			"--account-name", dest.Host,
+			"--destination", container,
+			"--destination-path", prefix,
+			"--source", dir,
+			"--overwrite", "--only-show-errors"), nil
+	default:
+		return nil, fmt.Errorf("unsupported object storage %q, one of s3, gs and azblob is expected", dest.Scheme)
+	}
+}
+
+// cli returns the path to the CLI set by the env var or the default one.
+func cli(envVar, defaultPath string) string {
+	if p := os.Getenv(envVar); p != "" {
+		return p
+	}
+	return defaultPath
+}
@@ -0,0 +1,88 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestUploadCommand(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		dest     string
+		expected []string
+		err      string
+	}{
+		{
+			name:     "s3",
+			dest:     "s3://bucket/prefix/run",
+			expected: []string{"aws", "s3", "sync", "/report", "s3://bucket/prefix/run", "--only-show-errors"},
+		},
+		{
+			name:     "gcs",
+			dest:     "gs://bucket/run",
+			expected: []string{"gcloud", "storage", "rsync", "/report", "gs://bucket/run", "--recursive"},
+		},
+		{
+			name: "azure blob",
+			dest: "azblob://account/container/prefix/run",
+			expected: []string{
+				"az", "storage", "blob", "upload-batch",
+				"--account-name", "account", "--destination", "container", "--destination-path", "prefix/run",
+				"--source", "/report", "--overwrite", "--only-show-errors",
+			},
+		},
+		{name: "no bucket", dest: "s3:///run", err: "no bucket"},
+		{name: "no container", dest: "azblob://account/run", err: "no container"},
+		{name: "unsupported", dest: "ftp://host/run", err: "unsupported object storage"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Setenv("AWSCLI", "")
+			t.Setenv("AZURE_CLI", "")
+			t.Setenv(EnvVarGCloudCLI, "")
+
+			dest, err := url.Parse(tc.dest)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			cmd, err := uploadCommand(context.Background(), dest, "/report")
+			if tc.err != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.err)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cmd.Args).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestRunID(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Setenv(EnvVarRunID, "")
+	t.Setenv("GITHUB_RUN_ID", "")
+	g.Expect(RunID()).To(Equal(startTime))
+
+	t.Setenv("GITHUB_RUN_ID", "42")
+	t.Setenv("GITHUB_RUN_ATTEMPT", "2")
+	g.Expect(RunID()).To(Equal("42-2"))
+
+	t.Setenv(EnvVarRunID, "custom")
+	g.Expect(RunID()).To(Equal("custom"))
+}
@@ -0,0 +1,30 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package management configures the management cluster the e2e suite is run
+// against.
+package management
+
+import "os"
+
+// EnvVarExisting runs the suite against the management cluster of the current
+// kubeconfig with kcm already installed once set to any non-empty value. The
+// management cluster is neither deployed nor removed by the suite then.
+const EnvVarExisting = "E2E_EXISTING_MANAGEMENT"
+
+// Existing reports whether the suite is run against the existing management
+// cluster.
+func Existing() bool {
+	return os.Getenv(EnvVarExisting) != ""
+}
@@ -16,20 +16,18 @@ package e2e
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/K0rdent/kcm/api/v1alpha1"
-	internalutils "github.com/K0rdent/kcm/internal/utils"
 	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
 	"github.com/K0rdent/kcm/test/e2e/clusterdeployment/clusteridentity"
+	"github.com/K0rdent/kcm/test/e2e/config"
 	"github.com/K0rdent/kcm/test/e2e/kubeclient"
 	"github.com/K0rdent/kcm/test/e2e/logs"
 	"github.com/K0rdent/kcm/test/e2e/templates"
@@ -51,7 +49,7 @@ var _ = Context("Multi Cloud Templates", Label("provider:multi-cloud", "provider
 	)
 
 	BeforeAll(func() {
-		kc = kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+		kc = specNamespace("multi-provider")
 
 		By("ensuring Azure credentials are set", func() {
 			azureCi := clusteridentity.New(kc, clusterdeployment.ProviderAzure)
@@ -69,8 +67,13 @@ var _ = Context("Multi Cloud Templates", Label("provider:multi-cloud", "provider
 	AfterEach(func() {
 		// If we failed collect the support bundle before the cleanup
 		if CurrentSpecReport().Failed() && cleanup() {
-			By("collecting the support bundle from the management cluster")
-			logs.SupportBundle("")
+			for _, clusterName := range []string{awsClusterDeploymentName, azureClusterDeploymentName} {
+				if clusterName == "" {
+					continue
+				}
+				By(fmt.Sprintf("collecting the support bundle from the %s cluster", clusterName))
+				logs.SupportBundle(kc, clusterName)
+			}
 		}
 
 		By("deleting resources")
@@ -87,7 +90,9 @@ var _ = Context("Multi Cloud Templates", Label("provider:multi-cloud", "provider
 	})
 
 	It("should deploy service in multi-cloud environment", func() {
-		clusterTemplates, err := templates.GetSortedClusterTemplates(context.Background(), kc.CrClient, internalutils.DefaultSystemNamespace)
+		DeferCleanup(startChaos())
+
+		clusterTemplates, err := templates.GetSortedClusterTemplates(context.Background(), kc.CrClient, kc.Namespace)
 		Expect(err).NotTo(HaveOccurred())
 
 		By("setting environment variables", func() {
@@ -100,7 +105,8 @@ var _ = Context("Multi Cloud Templates", Label("provider:multi-cloud", "provider
 
 			azureClusterDeploymentName = clusterdeployment.GenerateClusterName("")
 			sd := clusterdeployment.GetUnstructured(templates.TemplateAzureStandaloneCP, azureClusterDeploymentName, azureTemplates[0])
-			azureStandaloneDeleteFunc = kc.CreateClusterDeployment(context.Background(), sd)
+			timeouts := config.TimeoutsFor(config.TestingProviderAzure, templates.TemplateAzureStandaloneCP)
+			azureStandaloneDeleteFunc = kc.CreateClusterDeployment(context.Background(), sd, timeouts)
 
 			deploymentValidator := clusterdeployment.NewProviderValidator(
 				templates.TemplateAzureStandaloneCP,
@@ -110,7 +116,7 @@ var _ = Context("Multi Cloud Templates", Label("provider:multi-cloud", "provider
 
 			Eventually(func() error {
 				return deploymentValidator.Validate(context.Background(), kc)
-			}).WithTimeout(90 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+			}).WithTimeout(timeouts.Deployment).WithPolling(timeouts.Polling).Should(Succeed())
 		})
 
 		By("creating standalone cluster in AWS", func() {
@@ -119,7 +125,8 @@ var _ = Context("Multi Cloud Templates", Label("provider:multi-cloud", "provider
 
 			awsClusterDeploymentName = clusterdeployment.GenerateClusterName("")
 			sd := clusterdeployment.GetUnstructured(templates.TemplateAWSStandaloneCP, awsClusterDeploymentName, awsTemplates[0])
-			awsStandaloneDeleteFunc = kc.CreateClusterDeployment(context.Background(), sd)
+			timeouts := config.TimeoutsFor(config.TestingProviderAWS, templates.TemplateAWSStandaloneCP)
+			awsStandaloneDeleteFunc = kc.CreateClusterDeployment(context.Background(), sd, timeouts)
 
 			deploymentValidator := clusterdeployment.NewProviderValidator(
 				templates.TemplateAWSStandaloneCP,
@@ -129,63 +136,32 @@ var _ = Context("Multi Cloud Templates", Label("provider:multi-cloud", "provider
 
 			Eventually(func() error {
 				return deploymentValidator.Validate(context.Background(), kc)
-			}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+			}).WithTimeout(timeouts.Deployment).WithPolling(timeouts.Polling).Should(Succeed())
 		})
 
 		By("creating multi-cluster service", func() {
-			mcs := &v1alpha1.MultiClusterService{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "test-mcs",
-				},
-				Spec: v1alpha1.MultiClusterServiceSpec{
-					ClusterSelector: metav1.LabelSelector{
-						MatchLabels: map[string]string{
-							multiCloudLabelKey: multiCloudLabelValue,
-						},
-					},
-					ServiceSpec: v1alpha1.ServiceSpec{
-						Services: []v1alpha1.Service{
-							{
-								Name:      "managed-ingress-nginx",
-								Namespace: "default",
-								Template:  "ingress-nginx-4-11-0",
-							},
-						},
-					},
+			mcs := newMultiClusterService("test-mcs",
+				map[string]string{multiCloudLabelKey: multiCloudLabelValue},
+				v1alpha1.Service{
+					Name:      "managed-ingress-nginx",
+					Namespace: "default",
+					Template:  "ingress-nginx-4-11-0",
 				},
-			}
-			data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mcs)
-			Expect(err).NotTo(HaveOccurred())
-			mcsUnstructured := new(unstructured.Unstructured)
-			mcsUnstructured.SetUnstructuredContent(data)
-			mcsUnstructured.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("MultiClusterService"))
-
-			multiClusterServiceDeleteFunc = kc.CreateMultiClusterService(context.Background(), mcsUnstructured)
+			)
+			multiClusterServiceDeleteFunc = kc.CreateMultiClusterService(context.Background(), mcs)
 		})
 
 		By("adding labels to deployed clusters", func() {
-			gvr := schema.GroupVersionResource{
-				Group:    "k0rdent.mirantis.com",
-				Version:  "v1alpha1",
-				Resource: "clusterdeployments",
+			// only the label is applied with its own field manager, so the
+			// rest of the ClusterDeployments is left intact
+			for _, name := range []string{azureClusterDeploymentName, awsClusterDeploymentName} {
+				clusterDeployment := new(unstructured.Unstructured)
+				clusterDeployment.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind(v1alpha1.ClusterDeploymentKind))
+				clusterDeployment.SetName(name)
+				clusterDeployment.SetNamespace(kc.Namespace)
+				clusterDeployment.SetLabels(map[string]string{multiCloudLabelKey: multiCloudLabelValue})
+				kc.Apply(context.Background(), clusterDeployment, kubeclient.WithFieldManager("kcm-e2e-multi-cloud"))
 			}
-			dynClient := kc.GetDynamicClient(gvr, true)
-
-			azureCluster, err := dynClient.Get(context.Background(), azureClusterDeploymentName, metav1.GetOptions{})
-			Expect(err).NotTo(HaveOccurred())
-			azureClusterLabels := azureCluster.GetLabels()
-			azureClusterLabels[multiCloudLabelKey] = multiCloudLabelValue
-			azureCluster.SetLabels(azureClusterLabels)
-			_, err = dynClient.Update(context.Background(), azureCluster, metav1.UpdateOptions{})
-			Expect(err).NotTo(HaveOccurred())
-
-			awsCluster, err := dynClient.Get(context.Background(), awsClusterDeploymentName, metav1.GetOptions{})
-			Expect(err).NotTo(HaveOccurred())
-			awsClusterLabels := awsCluster.GetLabels()
-			awsClusterLabels[multiCloudLabelKey] = multiCloudLabelValue
-			awsCluster.SetLabels(awsClusterLabels)
-			_, err = dynClient.Update(context.Background(), awsCluster, metav1.UpdateOptions{})
-			Expect(err).NotTo(HaveOccurred())
 		})
 
 		By("validating service is deployed", func() {
@@ -0,0 +1,283 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/sveltos"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/logs"
+	"github.com/K0rdent/kcm/test/e2e/templates"
+)
+
+const (
+	// mcsSelectorLabel selects the clusters of the MultiClusterService
+	// propagation specs, its value is the namespace of the spec, so only the
+	// clusters of the spec are selected.
+	mcsSelectorLabel = "k0rdent.mirantis.com/e2e-mcs"
+	// mcsFieldManager is the field manager of the selector label, so the
+	// label is removed by applying the ClusterDeployment without it.
+	mcsFieldManager = "kcm-e2e-mcs"
+
+	mcsServiceName      = "mcs-ingress-nginx"
+	mcsServiceNamespace = "default"
+	mcsServiceTemplate  = "ingress-nginx-4-11-0"
+	mcsServiceTimeout   = 10 * time.Minute
+)
+
+// The MultiClusterService propagation specs check the services follow the
+// selector of the MultiClusterService: they are deployed to the clusters
+// existing before the MultiClusterService is created and to the clusters
+// relabeled into the selector, and withdrawn from the clusters relabeled out
+// of it, while the per-cluster status of the MultiClusterService lists exactly
+// the selected clusters. The clusters are deployed with the Docker provider
+// without the services of their own.
+var _ = Describe("MultiClusterService propagation", Label("provider:local", "provider:docker", "multiclusterservice"), Ordered, func() {
+	var (
+		kc                 *kubeclient.KubeClient
+		testingConfig      config.ProviderTestingConfig
+		clusterNames       []string
+		clusterDeleteFuncs map[string]func() error
+		mcsName            string
+		mcsDeleteFunc      func() error
+	)
+
+	BeforeAll(func() {
+		providerConfigs := config.Config.Providers[config.TestingProviderDocker]
+		if len(providerConfigs) == 0 {
+			Skip("the Docker provider is not configured for testing")
+		}
+		testingConfig = providerConfigs[0]
+		clusterDeleteFuncs = make(map[string]func() error)
+
+		kc = specNamespace("mcs")
+		prepareScenarioProvider(kc, config.TestingProviderDocker)
+	})
+
+	AfterAll(func() {
+		// If we failed collect the support bundle before the cleanup
+		if CurrentSpecReport().Failed() && cleanup() {
+			for _, clusterName := range clusterNames {
+				By(fmt.Sprintf("collecting the support bundle from the %s cluster", clusterName))
+				logs.SupportBundle(kc, clusterName)
+			}
+		}
+
+		if cleanup() {
+			if mcsDeleteFunc != nil {
+				By(fmt.Sprintf("deleting the %s MultiClusterService", mcsName))
+				Expect(mcsDeleteFunc()).To(Succeed())
+			}
+			for _, clusterName := range clusterNames {
+				deleteDockerCluster(kc, clusterName, clusterDeleteFuncs[clusterName], testingConfig.Timeouts.Deletion)
+			}
+		}
+	})
+
+	It("should deploy the clusters without services", func() {
+		for _, postfix := range []string{"mcs-a", "mcs-b"} {
+			clusterName := clusterdeployment.GenerateClusterName(postfix)
+			templateBy(templates.TemplateDockerHostedCP, fmt.Sprintf("creating a ClusterDeployment %s with template %s", clusterName, testingConfig.Template))
+			cd := clusterdeployment.GetUnstructured(templates.TemplateDockerHostedCP, clusterName, testingConfig.Template)
+			// the services are deployed by the MultiClusterService only
+			unstructured.RemoveNestedField(cd.Object, "spec", "serviceSpec")
+			clusterDeleteFuncs[clusterName] = kc.CreateClusterDeployment(context.Background(), cd, testingConfig.Timeouts)
+			clusterNames = append(clusterNames, clusterName)
+		}
+
+		for _, clusterName := range clusterNames {
+			templateBy(templates.TemplateDockerHostedCP, fmt.Sprintf("waiting for the %s cluster to deploy successfully", clusterName))
+			deploymentValidator := clusterdeployment.NewProviderValidator(
+				templates.TemplateDockerHostedCP,
+				clusterName,
+				clusterdeployment.ValidationActionDeploy,
+			)
+			Eventually(func() error {
+				return deploymentValidator.Validate(context.Background(), kc)
+			}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+		}
+	})
+
+	It("should deploy the services to the existing clusters matching the selector", func() {
+		first, second := clusterNames[0], clusterNames[1]
+
+		By(fmt.Sprintf("labeling the %s cluster into the selector", first))
+		setMCSSelectorLabel(kc, first, true)
+
+		// the MultiClusterService is cluster-scoped, the name of the namespace
+		// of the spec keeps it unique
+		mcsName = kc.Namespace
+		By(fmt.Sprintf("creating the %s MultiClusterService after the clusters", mcsName))
+		mcsDeleteFunc = kc.CreateMultiClusterService(context.Background(), newMultiClusterService(mcsName,
+			map[string]string{mcsSelectorLabel: kc.Namespace},
+			kcmv1.Service{Name: mcsServiceName, Namespace: mcsServiceNamespace, Template: mcsServiceTemplate},
+		))
+
+		By(fmt.Sprintf("validating the service is deployed to the %s cluster only", first))
+		Eventually(func() error {
+			return validateServiceDeployed(context.Background(), kc, first)
+		}).WithTimeout(mcsServiceTimeout).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+		Eventually(func() error {
+			return validateMCSStatus(context.Background(), kc, mcsName, first)
+		}).WithTimeout(mcsServiceTimeout).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+		Expect(validateServiceRemoved(context.Background(), kc, second)).To(Succeed())
+	})
+
+	It("should deploy the services to the clusters relabeled into the selector", func() {
+		first, second := clusterNames[0], clusterNames[1]
+
+		By(fmt.Sprintf("labeling the %s cluster into the selector", second))
+		setMCSSelectorLabel(kc, second, true)
+
+		By(fmt.Sprintf("validating the service is deployed to the %s and %s clusters", first, second))
+		Eventually(func() error {
+			return validateServiceDeployed(context.Background(), kc, second)
+		}).WithTimeout(mcsServiceTimeout).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+		Eventually(func() error {
+			return validateMCSStatus(context.Background(), kc, mcsName, first, second)
+		}).WithTimeout(mcsServiceTimeout).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+	})
+
+	It("should withdraw the services from the clusters relabeled out of the selector", func() {
+		first, second := clusterNames[0], clusterNames[1]
+
+		By(fmt.Sprintf("labeling the %s cluster out of the selector", first))
+		setMCSSelectorLabel(kc, first, false)
+
+		By(fmt.Sprintf("validating the service is removed from the %s cluster and kept on the %s cluster", first, second))
+		Eventually(func() error {
+			return validateServiceRemoved(context.Background(), kc, first)
+		}).WithTimeout(mcsServiceTimeout).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+		Eventually(func() error {
+			return validateMCSStatus(context.Background(), kc, mcsName, second)
+		}).WithTimeout(mcsServiceTimeout).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+		Expect(validateServiceDeployed(context.Background(), kc, second)).To(Succeed())
+	})
+})
+
+// newMultiClusterService returns the unstructured MultiClusterService
+// deploying the services to the clusters with the given labels.
+func newMultiClusterService(name string, matchLabels map[string]string, services ...kcmv1.Service) *unstructured.Unstructured {
+	GinkgoHelper()
+
+	mcs := &kcmv1.MultiClusterService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: kcmv1.MultiClusterServiceSpec{
+			ClusterSelector: metav1.LabelSelector{MatchLabels: matchLabels},
+			ServiceSpec:     kcmv1.ServiceSpec{Services: services},
+		},
+	}
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mcs)
+	Expect(err).NotTo(HaveOccurred())
+	mcsUnstructured := new(unstructured.Unstructured)
+	mcsUnstructured.SetUnstructuredContent(data)
+	mcsUnstructured.SetGroupVersionKind(kcmv1.GroupVersion.WithKind(kcmv1.MultiClusterServiceKind))
+	return mcsUnstructured
+}
+
+// setMCSSelectorLabel adds or removes the selector label of the
+// MultiClusterService propagation specs on the ClusterDeployment. Only the
+// label is applied with its own field manager, so the rest of the
+// ClusterDeployment is left intact.
+func setMCSSelectorLabel(kc *kubeclient.KubeClient, clusterName string, selected bool) {
+	GinkgoHelper()
+
+	clusterDeployment := new(unstructured.Unstructured)
+	clusterDeployment.SetGroupVersionKind(kcmv1.GroupVersion.WithKind(kcmv1.ClusterDeploymentKind))
+	clusterDeployment.SetName(clusterName)
+	clusterDeployment.SetNamespace(kc.Namespace)
+	if selected {
+		clusterDeployment.SetLabels(map[string]string{mcsSelectorLabel: kc.Namespace})
+	}
+	kc.Apply(context.Background(), clusterDeployment, kubeclient.WithFieldManager(mcsFieldManager))
+}
+
+// validateMCSStatus checks the status of the MultiClusterService lists exactly
+// the given clusters with the service ready on each of them.
+func validateMCSStatus(ctx context.Context, kc *kubeclient.KubeClient, name string, clusterNames ...string) error {
+	mcs := new(kcmv1.MultiClusterService)
+	if err := kc.CrClient.Get(ctx, crclient.ObjectKey{Name: name}, mcs); err != nil {
+		return err
+	}
+
+	conditionType := sveltos.HelmReleaseReadyConditionType(mcsServiceNamespace, mcsServiceName)
+	statusClusters := make([]string, 0, len(mcs.Status.Services))
+	for _, status := range mcs.Status.Services {
+		statusClusters = append(statusClusters, status.ClusterName)
+		if status.ClusterNamespace != kc.Namespace {
+			continue
+		}
+		condition := apimeta.FindStatusCondition(status.Conditions, conditionType)
+		if condition == nil || condition.Status != metav1.ConditionTrue {
+			return fmt.Errorf("the %s service is not ready on the %s cluster: %v", mcsServiceName, status.ClusterName, condition)
+		}
+	}
+
+	slices.Sort(statusClusters)
+	expected := slices.Sorted(slices.Values(clusterNames))
+	if !slices.Equal(statusClusters, expected) {
+		return fmt.Errorf("the status of the %s MultiClusterService lists the %v clusters instead of %v", name, statusClusters, expected)
+	}
+	return nil
+}
+
+// validateServiceDeployed checks the service of the MultiClusterService
+// propagation specs is deployed to the cluster.
+func validateServiceDeployed(ctx context.Context, kc *kubeclient.KubeClient, clusterName string) error {
+	return clusterdeployment.NewServiceValidator(clusterName, mcsServiceName, mcsServiceNamespace).
+		WithResourceValidation("service", clusterdeployment.ManagedServiceResource{
+			ResourceNameSuffix: "controller",
+			ValidationFunc:     clusterdeployment.ValidateService,
+		}).
+		WithResourceValidation("deployment", clusterdeployment.ManagedServiceResource{
+			ResourceNameSuffix: "controller",
+			ValidationFunc:     clusterdeployment.ValidateDeployment,
+		}).
+		Validate(ctx, kc)
+}
+
+// validateServiceRemoved checks the controller of the service of the
+// MultiClusterService propagation specs is not deployed to the cluster.
+func validateServiceRemoved(ctx context.Context, kc *kubeclient.KubeClient, clusterName string) error {
+	clusterClient := kc.NewFromCluster(ctx, mcsServiceNamespace, clusterName)
+
+	name := mcsServiceName + "-controller"
+	_, err := clusterClient.Client.AppsV1().Deployments(mcsServiceNamespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("the %s deployment of the %s service still exists on the %s cluster", name, mcsServiceName, clusterName)
+}
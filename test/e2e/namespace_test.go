@@ -0,0 +1,237 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/util/retry"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	internalutils "github.com/K0rdent/kcm/internal/utils"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/templates"
+)
+
+const (
+	// testNamespaceLabel labels the namespaces of the specs the templates are
+	// distributed to.
+	testNamespaceLabel = "k0rdent.mirantis.com/e2e"
+	// specNamespaceLabel labels the namespace of the spec and the objects
+	// created by the spec with the name of the namespace.
+	specNamespaceLabel = "k0rdent.mirantis.com/e2e-spec"
+	// testTemplateChainName is the name of the template chains listing all of
+	// the templates of the system namespace.
+	testTemplateChainName = "kcm-e2e"
+
+	// maxSpecNamePrefixLength limits the length of the name of the spec in
+	// the name of its namespace, so the name fits the label value.
+	maxSpecNamePrefixLength = 40
+)
+
+// currentSpecNamespace is the namespace of the running spec of the process,
+// the objects of which are dumped upon failure.
+var currentSpecNamespace string
+
+// distributeTemplates creates the ClusterTemplateChain and the
+// ServiceTemplateChain listing all of the templates of the system namespace
+// and adds the access rule distributing them to the namespaces of the specs.
+func distributeTemplates(ctx context.Context, kc *kubeclient.KubeClient) {
+	GinkgoHelper()
+
+	clusterTemplates, err := templates.GetSortedClusterTemplates(ctx, kc.CrClient, internalutils.DefaultSystemNamespace)
+	Expect(err).NotTo(HaveOccurred())
+
+	serviceTemplates := new(kcmv1.ServiceTemplateList)
+	Expect(kc.CrClient.List(ctx, serviceTemplates, crclient.InNamespace(internalutils.DefaultSystemNamespace))).To(Succeed())
+
+	clusterTemplateChain := &kcmv1.ClusterTemplateChain{
+		ObjectMeta: metav1.ObjectMeta{Name: testTemplateChainName, Namespace: internalutils.DefaultSystemNamespace},
+	}
+	for _, name := range clusterTemplates {
+		clusterTemplateChain.Spec.SupportedTemplates = append(clusterTemplateChain.Spec.SupportedTemplates, kcmv1.SupportedTemplate{Name: name})
+	}
+	Expect(crclient.IgnoreAlreadyExists(kc.CrClient.Create(ctx, clusterTemplateChain))).To(Succeed())
+
+	serviceTemplateChain := &kcmv1.ServiceTemplateChain{
+		ObjectMeta: metav1.ObjectMeta{Name: testTemplateChainName, Namespace: internalutils.DefaultSystemNamespace},
+	}
+	for _, template := range serviceTemplates.Items {
+		serviceTemplateChain.Spec.SupportedTemplates = append(serviceTemplateChain.Spec.SupportedTemplates, kcmv1.SupportedTemplate{Name: template.Name})
+	}
+	Expect(crclient.IgnoreAlreadyExists(kc.CrClient.Create(ctx, serviceTemplateChain))).To(Succeed())
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		accessManagement := new(kcmv1.AccessManagement)
+		if err := kc.CrClient.Get(ctx, crclient.ObjectKey{Name: kcmv1.AccessManagementName}, accessManagement); err != nil {
+			return err
+		}
+
+		if slices.ContainsFunc(accessManagement.Spec.AccessRules, func(rule kcmv1.AccessRule) bool {
+			return slices.Contains(rule.ClusterTemplateChains, testTemplateChainName)
+		}) {
+			return nil
+		}
+
+		accessManagement.Spec.AccessRules = append(accessManagement.Spec.AccessRules, kcmv1.AccessRule{
+			TargetNamespaces: kcmv1.TargetNamespaces{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{testNamespaceLabel: "true"}},
+			},
+			ClusterTemplateChains: []string{testTemplateChainName},
+			ServiceTemplateChains: []string{testTemplateChainName},
+		})
+		return kc.CrClient.Update(ctx, accessManagement)
+	})
+	Expect(err).NotTo(HaveOccurred(), "failed to add the access rule of the spec namespaces")
+}
+
+// specNamespace creates the namespace the current spec deploys its clusters
+// and creates its credentials in and returns the KubeClient operating on it
+// and labeling the objects it creates. The namespace is unique per run of the
+// spec, so the specs never see the objects of each other. It is called in the
+// BeforeAll of the ordered containers or in the spec itself.
+//
+// Unless the cleanup is disabled, the labeled ClusterDeployments and
+// MultiClusterServices left by the spec are removed once it completes, and
+// then the namespace along with the rest of its objects.
+func specNamespace(name string) *kubeclient.KubeClient {
+	GinkgoHelper()
+
+	ctx := context.Background()
+	namespace := specNamespaceName(name)
+	labels := map[string]string{specNamespaceLabel: namespace}
+	kc := kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+
+	By(fmt.Sprintf("preparing the %s namespace of the spec", namespace))
+	prepareNamespace(ctx, kc, namespace, labels)
+	currentSpecNamespace = namespace
+
+	DeferCleanup(func() {
+		currentSpecNamespace = ""
+		if !cleanup() {
+			_, _ = fmt.Fprintf(GinkgoWriter, "Keeping the %s namespace of the spec\n", namespace)
+			return
+		}
+
+		// the management cluster might have been recreated by the spec
+		kc := kubeclient.NewFromLocal(namespace)
+
+		By(fmt.Sprintf("removing the %s namespace of the spec", namespace))
+		deleteSpecObjects(ctx, kc, labels)
+		deleteNamespace(ctx, kc, namespace)
+	})
+
+	return kc.WithNamespace(namespace).WithLabels(labels)
+}
+
+// specNamespaceName returns the unique name of the namespace of the spec with
+// the given name.
+func specNamespaceName(name string) string {
+	prefix := strings.Trim(strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(name)), "-")
+	if len(prefix) > maxSpecNamePrefixLength {
+		prefix = strings.TrimRight(prefix[:maxSpecNamePrefixLength], "-")
+	}
+	return fmt.Sprintf("kcm-e2e-%s-%d-%s", prefix, GinkgoParallelProcess(), utilrand.String(5))
+}
+
+// prepareNamespace creates the namespace with the given labels and waits for
+// the templates to be distributed to it.
+func prepareNamespace(ctx context.Context, kc *kubeclient.KubeClient, name string, labels map[string]string) {
+	GinkgoHelper()
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{testNamespaceLabel: "true"},
+		},
+	}
+	for k, v := range labels {
+		namespace.Labels[k] = v
+	}
+	Expect(crclient.IgnoreAlreadyExists(kc.CrClient.Create(ctx, namespace))).To(Succeed())
+
+	systemTemplates, err := templates.GetSortedClusterTemplates(ctx, kc.CrClient, internalutils.DefaultSystemNamespace)
+	Expect(err).NotTo(HaveOccurred())
+
+	nkc := kc.WithNamespace(namespace.Name)
+	Eventually(func() error {
+		clusterTemplates, err := templates.GetSortedClusterTemplates(ctx, nkc.CrClient, nkc.Namespace)
+		if err != nil {
+			return err
+		}
+		if !slices.Equal(clusterTemplates, systemTemplates) {
+			return fmt.Errorf("cluster templates have not been distributed to the %s namespace yet", nkc.Namespace)
+		}
+		return clusterdeployment.ValidateClusterTemplates(ctx, nkc)
+	}).WithTimeout(config.Config.Timeouts.ControllersReady).WithPolling(config.Config.Timeouts.Polling).Should(Succeed())
+}
+
+// deleteSpecObjects deletes the ClusterDeployments of the namespace and the
+// MultiClusterServices with the given labels and waits for them to be
+// removed, so the clusters are not orphaned by the removal of the namespace.
+func deleteSpecObjects(ctx context.Context, kc *kubeclient.KubeClient, labels map[string]string) {
+	GinkgoHelper()
+
+	selector := crclient.MatchingLabels(labels)
+	Expect(kc.CrClient.DeleteAllOf(ctx, new(kcmv1.MultiClusterService), selector)).To(Succeed())
+	Expect(kc.CrClient.DeleteAllOf(ctx, new(kcmv1.ClusterDeployment), crclient.InNamespace(kc.Namespace), selector)).To(Succeed())
+
+	Eventually(func() error {
+		clusterDeployments := new(kcmv1.ClusterDeploymentList)
+		if err := kc.CrClient.List(ctx, clusterDeployments, crclient.InNamespace(kc.Namespace), selector); err != nil {
+			return err
+		}
+		multiClusterServices := new(kcmv1.MultiClusterServiceList)
+		if err := kc.CrClient.List(ctx, multiClusterServices, selector); err != nil {
+			return err
+		}
+		if n := len(clusterDeployments.Items) + len(multiClusterServices.Items); n > 0 {
+			return fmt.Errorf("%d objects of the %s namespace are still being deleted", n, kc.Namespace)
+		}
+		return nil
+	}).WithTimeout(config.Config.Timeouts.Deletion).WithPolling(config.Config.Timeouts.Polling).Should(Succeed())
+}
+
+// deleteNamespace deletes the namespace and waits for it to be removed.
+func deleteNamespace(ctx context.Context, kc *kubeclient.KubeClient, name string) {
+	GinkgoHelper()
+
+	err := kc.Client.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{})
+	if !apierrors.IsNotFound(err) {
+		Expect(err).NotTo(HaveOccurred(), "failed to delete the %s namespace", name)
+	}
+
+	Eventually(func() bool {
+		_, err := kc.Client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		return apierrors.IsNotFound(err)
+	}).WithTimeout(config.Config.Timeouts.Deletion).WithPolling(config.Config.Timeouts.Polling).Should(BeTrue(), "the %s namespace has not been removed", name)
+}
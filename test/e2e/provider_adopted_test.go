@@ -18,7 +18,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -30,7 +29,6 @@ import (
 	"github.com/K0rdent/kcm/test/e2e/kubeclient"
 	"github.com/K0rdent/kcm/test/e2e/logs"
 	"github.com/K0rdent/kcm/test/e2e/templates"
-	"github.com/K0rdent/kcm/test/e2e/upgrade"
 )
 
 var _ = Describe("Adopted Cluster Templates", Label("provider:cloud", "provider:adopted"), Ordered, func() {
@@ -47,16 +45,12 @@ var _ = Describe("Adopted Cluster Templates", Label("provider:cloud", "provider:
 
 	BeforeAll(func() {
 		By("get testing configuration")
-		providerConfigs = config.Config[config.TestingProviderAdopted]
+		providerConfigs = config.Config.Providers[config.TestingProviderAdopted]
 
-		if len(providerConfigs) == 0 {
-			Skip("Adopted ClusterDeployment testing is skipped")
-		}
-
-		kc = kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+		kc = specNamespace("adopted")
 
 		var err error
-		clusterTemplates, err = templates.GetSortedClusterTemplates(context.Background(), kc.CrClient, internalutils.DefaultSystemNamespace)
+		clusterTemplates, err = templates.GetSortedClusterTemplates(context.Background(), kc.CrClient, kc.Namespace)
 		Expect(err).NotTo(HaveOccurred())
 
 		By("providing cluster identity")
@@ -68,8 +62,10 @@ var _ = Describe("Adopted Cluster Templates", Label("provider:cloud", "provider:
 	AfterAll(func() {
 		// If we failed collect the support bundle before the cleanup
 		if CurrentSpecReport().Failed() && cleanup() {
-			By("collecting the support bundle from the management cluster")
-			logs.SupportBundle("")
+			for _, clusterName := range clusterNames {
+				By(fmt.Sprintf("collecting the support bundle from the %s cluster", clusterName))
+				logs.SupportBundle(kc, clusterName)
+			}
 		}
 
 		if cleanup() {
@@ -87,8 +83,11 @@ var _ = Describe("Adopted Cluster Templates", Label("provider:cloud", "provider:
 		}
 	})
 
-	It("should work with an Adopted cluster provider", func() {
-		for i, testingConfig := range providerConfigs {
+	for i, c := range config.Config.Providers[config.TestingProviderAdopted] {
+		It(fmt.Sprintf("should work with an Adopted cluster provider (configuration %d)", i), Label(c.Labels()...), func() {
+			testingConfig := providerConfigs[i]
+			DeferCleanup(startChaos())
+
 			// Deploy a standalone cluster and verify it is running/ready. Then, delete the management cluster and
 			// recreate it. Next "adopt" the cluster we created and verify the services were deployed. Next we delete
 			// the adopted cluster and finally the management cluster (AWS standalone).
@@ -105,7 +104,7 @@ var _ = Describe("Adopted Cluster Templates", Label("provider:cloud", "provider:
 			templateBy(templates.TemplateAWSStandaloneCP, fmt.Sprintf("creating a ClusterDeployment %s with template %s", clusterName, clusterTemplate))
 			sd := clusterdeployment.GetUnstructured(templates.TemplateAWSStandaloneCP, clusterName, clusterTemplate)
 
-			clusterDeleteFunc = kc.CreateClusterDeployment(context.Background(), sd)
+			clusterDeleteFunc = kc.CreateClusterDeployment(context.Background(), sd, testingConfig.Timeouts)
 			clusterNames = append(clusterNames, clusterName)
 			clusterDeleteFunc = func() error {
 				if err := clusterDeleteFunc(); err != nil {
@@ -119,7 +118,7 @@ var _ = Describe("Adopted Cluster Templates", Label("provider:cloud", "provider:
 				)
 				Eventually(func() error {
 					return deletionValidator.Validate(context.Background(), kc)
-				}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Timeouts.Deletion).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
 				return nil
 			}
 
@@ -132,7 +131,7 @@ var _ = Describe("Adopted Cluster Templates", Label("provider:cloud", "provider:
 
 			Eventually(func() error {
 				return deploymentValidator.Validate(context.Background(), kc)
-			}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+			}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
 
 			// create the adopted cluster using the AWS standalone cluster
 			var kubeCfgFile string
@@ -147,7 +146,7 @@ var _ = Describe("Adopted Cluster Templates", Label("provider:cloud", "provider:
 			adoptedClusterTemplate := testingConfig.Template
 
 			adoptedCluster := clusterdeployment.GetUnstructured(templates.TemplateAdoptedCluster, adoptedClusterName, adoptedClusterTemplate)
-			adoptedDeleteFunc = kc.CreateClusterDeployment(context.Background(), adoptedCluster)
+			adoptedDeleteFunc = kc.CreateClusterDeployment(context.Background(), adoptedCluster, testingConfig.Timeouts)
 
 			// validate the adopted cluster
 			deploymentValidator = clusterdeployment.NewProviderValidator(
@@ -157,24 +156,16 @@ var _ = Describe("Adopted Cluster Templates", Label("provider:cloud", "provider:
 			)
 			Eventually(func() error {
 				return deploymentValidator.Validate(context.Background(), kc)
-			}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+			}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
 
 			if testingConfig.Upgrade {
 				standaloneClient := kc.NewFromCluster(context.Background(), internalutils.DefaultSystemNamespace, adoptedClusterName)
-				clusterUpgrade := upgrade.NewClusterUpgrade(
-					kc.CrClient,
-					standaloneClient.CrClient,
-					internalutils.DefaultSystemNamespace,
-					adoptedClusterName,
-					testingConfig.UpgradeTemplate,
-					upgrade.NewDefaultClusterValidator(),
-				)
-				clusterUpgrade.Run(context.Background())
+				upgradeCluster(kc, standaloneClient, adoptedClusterName, testingConfig)
 
 				Eventually(func() error {
 					return deploymentValidator.Validate(context.Background(), kc)
-				}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Timeouts.Upgrade).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
 			}
-		}
-	})
+		})
+	}
 })
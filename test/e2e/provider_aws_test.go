@@ -34,6 +34,7 @@ import (
 	"github.com/K0rdent/kcm/test/e2e/config"
 	"github.com/K0rdent/kcm/test/e2e/kubeclient"
 	"github.com/K0rdent/kcm/test/e2e/logs"
+	"github.com/K0rdent/kcm/test/e2e/resume"
 	"github.com/K0rdent/kcm/test/e2e/templates"
 	"github.com/K0rdent/kcm/test/e2e/upgrade"
 	"github.com/K0rdent/kcm/test/utils"
@@ -46,20 +47,17 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 		hostedDeleteFuncs     []func() error
 		standaloneDeleteFuncs []func() error
 		kubeconfigDeleteFuncs []func() error
+		resumeKeys            []string
 
 		providerConfigs []config.ProviderTestingConfig
 	)
 
 	BeforeAll(func() {
 		By("get testing configuration")
-		providerConfigs = config.Config[config.TestingProviderAWS]
-
-		if len(providerConfigs) == 0 {
-			Skip("AWS ClusterDeployment testing is skipped")
-		}
+		providerConfigs = config.Config.Providers[config.TestingProviderAWS]
 
 		By("providing cluster identity")
-		kc = kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+		kc = specNamespace("aws")
 		ci := clusteridentity.New(kc, clusterdeployment.ProviderAWS)
 		ci.WaitForValidCredential(kc)
 		Expect(os.Setenv(clusterdeployment.EnvVarAWSClusterIdentity, ci.IdentityName)).Should(Succeed())
@@ -68,12 +66,9 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 	AfterAll(func() {
 		// If we failed collect the support bundle before the cleanup
 		if CurrentSpecReport().Failed() && cleanup() {
-			By("collecting the support bundle from the management cluster")
-			logs.SupportBundle("")
-
 			for _, clusterName := range standaloneClusters {
 				By(fmt.Sprintf("collecting the support bundle from the %s cluster", clusterName))
-				logs.SupportBundle(clusterName)
+				logs.SupportBundle(kc, clusterName)
 			}
 		}
 
@@ -86,18 +81,27 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 					Expect(err).NotTo(HaveOccurred())
 				}
 			}
+			for _, key := range resumeKeys {
+				forgetCluster(key)
+			}
 		}
 	})
 
-	It("should work with an AWS provider", func() {
-		for i, testingConfig := range providerConfigs {
+	for i, c := range config.Config.Providers[config.TestingProviderAWS] {
+		It(fmt.Sprintf("should work with an AWS provider (configuration %d)", i), Label(c.Labels()...), func() {
+			testingConfig := providerConfigs[i]
+			DeferCleanup(startChaos())
+
 			_, _ = fmt.Fprintf(GinkgoWriter, "Testing configuration:\n%s\n", testingConfig.String())
 			// Deploy a standalone cluster and verify it is running/ready.
 			// Deploy standalone with an xlarge instance since it will also be
 			// hosting the hosted cluster.
-			GinkgoT().Setenv(clusterdeployment.EnvVarAWSInstanceType, "t3.xlarge")
+			GinkgoT().Setenv(clusterdeployment.EnvVarAWSInstanceType, aws.InstanceType(testingConfig.Architecture, "xlarge"))
 
-			sdName := clusterdeployment.GenerateClusterName(fmt.Sprintf("aws-%d", i))
+			resumeKey := fmt.Sprintf("aws-%d", i)
+			sdState := resumeCluster(resumeKey)
+			resumeKeys = append(resumeKeys, resumeKey)
+			sdName := sdState.Name
 			sdTemplate := testingConfig.Template
 			sdTemplateType := templates.GetType(sdTemplate)
 
@@ -111,7 +115,8 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 
 			sd := clusterdeployment.GetUnstructured(sdTemplateType, sdName, sdTemplate)
 
-			standaloneDeleteFunc := kc.CreateClusterDeployment(context.Background(), sd)
+			standaloneDeleteFunc := kc.CreateClusterDeployment(context.Background(), sd, testingConfig.Timeouts)
+			recordCluster(resumeKey, sdState, resume.PhaseCreated)
 			standaloneClusters = append(standaloneClusters, sdName)
 			standaloneDeleteFuncs = append(standaloneDeleteFuncs, func() error {
 				By(fmt.Sprintf("Deleting the %s ClusterDeployment", sdName))
@@ -126,8 +131,7 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 				)
 				Eventually(func() error {
 					return deletionValidator.Validate(context.Background(), kc)
-				}).WithTimeout(10 * time.Minute).WithPolling(10 *
-					time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Timeouts.Deletion).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
 				return nil
 			})
 
@@ -167,7 +171,8 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 
 			Eventually(func() error {
 				return deploymentValidator.Validate(context.Background(), kc)
-			}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+			}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+			recordCluster(resumeKey, sdState, resume.PhaseDeployed)
 
 			// validating service included in the cluster deployment is deployed
 			serviceDeployedValidator := clusterdeployment.NewServiceValidator(sdName, "managed-ingress-nginx", "default").
@@ -184,7 +189,7 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 			}).WithTimeout(10 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
 
 			if !testingConfig.Upgrade && testingConfig.Hosted == nil {
-				continue
+				return
 			}
 
 			standaloneClient := kc.NewFromCluster(context.Background(), internalutils.DefaultSystemNamespace, sdName)
@@ -208,16 +213,7 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 				Expect(os.Unsetenv("KUBECONFIG")).To(Succeed())
 
 				templateBy(templates.TemplateAWSHostedCP, "validating that the controller is ready")
-				Eventually(func() error {
-					err := verifyControllersUp(standaloneClient)
-					if err != nil {
-						_, _ = fmt.Fprintf(
-							GinkgoWriter, "[%s] controller validation failed: %v\n",
-							templates.TemplateAWSHostedCP, err)
-						return err
-					}
-					return nil
-				}).WithTimeout(15 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				waitForControllers(standaloneClient, testingConfig.Hosted.Timeouts.ControllersReady)
 
 				if testingConfig.Hosted.Upgrade {
 					By("installing stable templates for further hosted upgrade testing")
@@ -233,7 +229,7 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 						return err
 					}
 					return nil
-				}).WithTimeout(15 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Hosted.Timeouts.ControllersReady).WithPolling(testingConfig.Hosted.Timeouts.Polling).Should(Succeed())
 
 				// Ensure AWS credentials are set in the standalone cluster.
 				standaloneCi := clusteridentity.New(standaloneClient, clusterdeployment.ProviderAWS)
@@ -242,6 +238,7 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 				// Populate the environment variables required for the hosted
 				// cluster.
 				aws.PopulateHostedTemplateVars(context.Background(), kc, sdName)
+				GinkgoT().Setenv(clusterdeployment.EnvVarAWSInstanceType, aws.InstanceType(testingConfig.Hosted.Architecture, "medium"))
 
 				hdName = clusterdeployment.GenerateClusterName(fmt.Sprintf("aws-hosted-%d", i))
 				hdTemplate := testingConfig.Hosted.Template
@@ -249,7 +246,7 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 				hd := clusterdeployment.GetUnstructured(templates.TemplateAWSHostedCP, hdName, hdTemplate)
 
 				// Deploy the hosted cluster on top of the standalone cluster.
-				hostedDeleteFunc := standaloneClient.CreateClusterDeployment(context.Background(), hd)
+				hostedDeleteFunc := standaloneClient.CreateClusterDeployment(context.Background(), hd, testingConfig.Hosted.Timeouts)
 				hostedDeleteFuncs = append(hostedDeleteFuncs, func() error {
 					By(fmt.Sprintf("Deleting the %s ClusterDeployment", hdName))
 					err = hostedDeleteFunc()
@@ -263,7 +260,7 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 					)
 					Eventually(func() error {
 						return deletionValidator.Validate(context.Background(), standaloneClient)
-					}).WithTimeout(10 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+					}).WithTimeout(testingConfig.Hosted.Timeouts.Deletion).WithPolling(testingConfig.Hosted.Timeouts.Polling).Should(Succeed())
 					return nil
 				})
 
@@ -279,29 +276,22 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 				)
 				Eventually(func() error {
 					return deploymentValidator.Validate(context.Background(), standaloneClient)
-				}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Hosted.Timeouts.Deployment).WithPolling(testingConfig.Hosted.Timeouts.Polling).Should(Succeed())
 			}
 
-			if testingConfig.Upgrade {
-				clusterUpgrade := upgrade.NewClusterUpgrade(
-					kc.CrClient,
-					standaloneClient.CrClient,
-					internalutils.DefaultSystemNamespace,
-					sdName,
-					testingConfig.UpgradeTemplate,
-					upgrade.NewDefaultClusterValidator(),
-				)
-				clusterUpgrade.Run(context.Background())
+			if testingConfig.Upgrade && !sdState.Reached(resume.PhaseUpgraded) {
+				upgradeCluster(kc, standaloneClient, sdName, testingConfig)
 
 				Eventually(func() error {
 					return deploymentValidator.Validate(context.Background(), kc)
-				}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Timeouts.Upgrade).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+				recordCluster(resumeKey, sdState, resume.PhaseUpgraded)
 
 				if testingConfig.Hosted != nil {
 					// Validate hosted deployment after the standalone upgrade
 					Eventually(func() error {
 						return deploymentValidator.Validate(context.Background(), standaloneClient)
-					}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+					}).WithTimeout(testingConfig.Timeouts.Upgrade).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
 				}
 			}
 			if testingConfig.Hosted != nil && testingConfig.Hosted.Upgrade {
@@ -315,13 +305,14 @@ var _ = Describe("AWS Templates", Label("provider:cloud", "provider:aws"), Order
 					hdName,
 					testingConfig.Hosted.UpgradeTemplate,
 					upgrade.NewDefaultClusterValidator(),
+					testingConfig.Hosted.Timeouts,
 				)
 				clusterUpgrade.Run(context.Background())
 
 				Eventually(func() error {
 					return deploymentValidator.Validate(context.Background(), standaloneClient)
-				}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Hosted.Timeouts.Upgrade).WithPolling(testingConfig.Hosted.Timeouts.Polling).Should(Succeed())
 			}
-		}
-	})
+		})
+	}
 })
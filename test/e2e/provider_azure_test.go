@@ -19,7 +19,6 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -31,6 +30,7 @@ import (
 	"github.com/K0rdent/kcm/test/e2e/config"
 	"github.com/K0rdent/kcm/test/e2e/kubeclient"
 	"github.com/K0rdent/kcm/test/e2e/logs"
+	"github.com/K0rdent/kcm/test/e2e/resume"
 	"github.com/K0rdent/kcm/test/e2e/templates"
 	"github.com/K0rdent/kcm/test/e2e/upgrade"
 	"github.com/K0rdent/kcm/test/utils"
@@ -43,17 +43,14 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 		hostedDeleteFuncs     []func() error
 		standaloneDeleteFuncs []func() error
 		kubeconfigDeleteFuncs []func() error
+		resumeKeys            []string
 
 		providerConfigs []config.ProviderTestingConfig
 	)
 
 	BeforeAll(func() {
 		By("get testing configuration")
-		providerConfigs = config.Config[config.TestingProviderAzure]
-
-		if len(providerConfigs) == 0 {
-			Skip("Azure ClusterDeployment testing is skipped")
-		}
+		providerConfigs = config.Config.Providers[config.TestingProviderAzure]
 
 		By("ensuring Azure credentials are set")
 		for _, testingConfig := range providerConfigs {
@@ -65,7 +62,7 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 				break
 			}
 		}
-		kc = kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+		kc = specNamespace("azure")
 		ci := clusteridentity.New(kc, clusterdeployment.ProviderAzure)
 		ci.WaitForValidCredential(kc)
 		Expect(os.Setenv(clusterdeployment.EnvVarAzureClusterIdentity, ci.IdentityName)).Should(Succeed())
@@ -74,12 +71,9 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 	AfterAll(func() {
 		// If we failed collect the support bundle before the cleanup
 		if CurrentSpecReport().Failed() && cleanup() {
-			By("collecting the support bundle from the management cluster")
-			logs.SupportBundle("")
-
 			for _, clusterName := range standaloneClusters {
 				By(fmt.Sprintf("collecting the support bundle from the %s cluster", clusterName))
-				logs.SupportBundle(clusterName)
+				logs.SupportBundle(kc, clusterName)
 			}
 		}
 
@@ -92,14 +86,23 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 					Expect(err).NotTo(HaveOccurred())
 				}
 			}
+			for _, key := range resumeKeys {
+				forgetCluster(key)
+			}
 		}
 	})
 
-	It("should work with an Azure provider", func() {
-		for i, testingConfig := range providerConfigs {
+	for i, c := range config.Config.Providers[config.TestingProviderAzure] {
+		It(fmt.Sprintf("should work with an Azure provider (configuration %d)", i), Label(c.Labels()...), func() {
+			testingConfig := providerConfigs[i]
+			DeferCleanup(startChaos())
+
 			_, _ = fmt.Fprintf(GinkgoWriter, "Testing configuration:\n%s\n", testingConfig.String())
 
-			sdName := clusterdeployment.GenerateClusterName(fmt.Sprintf("azure-%d", i))
+			resumeKey := fmt.Sprintf("azure-%d", i)
+			sdState := resumeCluster(resumeKey)
+			resumeKeys = append(resumeKeys, resumeKey)
+			sdName := sdState.Name
 			sdTemplate := testingConfig.Template
 			sdTemplateType := templates.GetType(sdTemplate)
 
@@ -107,7 +110,8 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 
 			sd := clusterdeployment.GetUnstructured(templates.TemplateAzureStandaloneCP, sdName, sdTemplate)
 
-			standaloneDeleteFunc := kc.CreateClusterDeployment(context.Background(), sd)
+			standaloneDeleteFunc := kc.CreateClusterDeployment(context.Background(), sd, testingConfig.Timeouts)
+			recordCluster(resumeKey, sdState, resume.PhaseCreated)
 			standaloneClusters = append(standaloneClusters, sdName)
 			standaloneDeleteFuncs = append(standaloneDeleteFuncs, func() error {
 				By(fmt.Sprintf("Deleting the %s ClusterDeployment", sdName))
@@ -123,7 +127,7 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 
 				Eventually(func() error {
 					return deploymentValidator.Validate(context.Background(), kc)
-				}).WithTimeout(10 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Timeouts.Deletion).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
 				return nil
 			})
 
@@ -137,10 +141,11 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 			templateBy(sdTemplateType, "waiting for infrastructure provider to deploy successfully")
 			Eventually(func() error {
 				return deploymentValidator.Validate(context.Background(), kc)
-			}).WithTimeout(90 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+			}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+			recordCluster(resumeKey, sdState, resume.PhaseDeployed)
 
 			if !testingConfig.Upgrade && testingConfig.Hosted == nil {
-				continue
+				return
 			}
 
 			standaloneClient := new(kubeclient.KubeClient)
@@ -161,14 +166,7 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 
 				standaloneClient = kc.NewFromCluster(context.Background(), internalutils.DefaultSystemNamespace, sdName)
 				// verify the cluster is ready prior to creating credentials
-				Eventually(func() error {
-					err := verifyControllersUp(standaloneClient)
-					if err != nil {
-						_, _ = fmt.Fprintf(GinkgoWriter, "Controller validation failed: %v\n", err)
-						return err
-					}
-					return nil
-				}).WithTimeout(15 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				waitForControllers(standaloneClient, testingConfig.Hosted.Timeouts.ControllersReady)
 
 				if testingConfig.Hosted.Upgrade {
 					By("installing stable templates for further hosted upgrade testing")
@@ -184,7 +182,7 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 						return err
 					}
 					return nil
-				}).WithTimeout(15 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Hosted.Timeouts.ControllersReady).WithPolling(testingConfig.Hosted.Timeouts.Polling).Should(Succeed())
 
 				By("Create azure credential secret")
 				standaloneCi := clusteridentity.New(standaloneClient, clusterdeployment.ProviderAzure)
@@ -203,7 +201,7 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 				hd := clusterdeployment.GetUnstructured(templates.TemplateAzureHostedCP, hdName, hdTemplate)
 
 				templateBy(templates.TemplateAzureHostedCP, "creating a ClusterDeployment")
-				hostedDeleteFunc := standaloneClient.CreateClusterDeployment(context.Background(), hd)
+				hostedDeleteFunc := standaloneClient.CreateClusterDeployment(context.Background(), hd, testingConfig.Hosted.Timeouts)
 				hostedDeleteFuncs = append(hostedDeleteFuncs, func() error {
 					By(fmt.Sprintf("Deleting the %s ClusterDeployment", hdName))
 					err = hostedDeleteFunc()
@@ -217,7 +215,7 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 					)
 					Eventually(func() error {
 						return deploymentValidator.Validate(context.Background(), standaloneClient)
-					}).WithTimeout(10 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+					}).WithTimeout(testingConfig.Hosted.Timeouts.Deletion).WithPolling(testingConfig.Hosted.Timeouts.Polling).Should(Succeed())
 					return nil
 				})
 
@@ -233,29 +231,22 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 
 				Eventually(func() error {
 					return deploymentValidator.Validate(context.Background(), standaloneClient)
-				}).WithTimeout(10 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Hosted.Timeouts.Deployment).WithPolling(testingConfig.Hosted.Timeouts.Polling).Should(Succeed())
 			}
 
-			if testingConfig.Upgrade {
-				clusterUpgrade := upgrade.NewClusterUpgrade(
-					kc.CrClient,
-					standaloneClient.CrClient,
-					internalutils.DefaultSystemNamespace,
-					sdName,
-					testingConfig.UpgradeTemplate,
-					upgrade.NewDefaultClusterValidator(),
-				)
-				clusterUpgrade.Run(context.Background())
+			if testingConfig.Upgrade && !sdState.Reached(resume.PhaseUpgraded) {
+				upgradeCluster(kc, standaloneClient, sdName, testingConfig)
 
 				Eventually(func() error {
 					return deploymentValidator.Validate(context.Background(), kc)
-				}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Timeouts.Upgrade).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+				recordCluster(resumeKey, sdState, resume.PhaseUpgraded)
 
 				if testingConfig.Hosted != nil {
 					// Validate hosted deployment after the standalone upgrade
 					Eventually(func() error {
 						return deploymentValidator.Validate(context.Background(), standaloneClient)
-					}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+					}).WithTimeout(testingConfig.Timeouts.Upgrade).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
 				}
 			}
 			if testingConfig.Hosted != nil && testingConfig.Hosted.Upgrade {
@@ -269,13 +260,14 @@ var _ = Context("Azure Templates", Label("provider:cloud", "provider:azure"), Or
 					hdName,
 					testingConfig.Hosted.UpgradeTemplate,
 					upgrade.NewDefaultClusterValidator(),
+					testingConfig.Hosted.Timeouts,
 				)
 				clusterUpgrade.Run(context.Background())
 
 				Eventually(func() error {
 					return deploymentValidator.Validate(context.Background(), standaloneClient)
-				}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Hosted.Timeouts.Upgrade).WithPolling(testingConfig.Hosted.Timeouts.Polling).Should(Succeed())
 			}
-		}
-	})
+		})
+	}
 })
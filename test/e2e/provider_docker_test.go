@@ -0,0 +1,171 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	internalutils "github.com/K0rdent/kcm/internal/utils"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/logs"
+	"github.com/K0rdent/kcm/test/e2e/resume"
+	"github.com/K0rdent/kcm/test/e2e/templates"
+	"github.com/K0rdent/kcm/test/e2e/wait"
+	"github.com/K0rdent/kcm/test/utils"
+)
+
+// The Docker provider deploys the clusters on the kind management cluster, so
+// the whole lifecycle of the ClusterDeployment is tested without the cloud
+// credentials.
+var _ = Describe("Docker Templates", Label("provider:local", "provider:docker"), Ordered, func() {
+	var (
+		kc                 *kubeclient.KubeClient
+		clusterDeleteFuncs map[string]func() error
+		deletionTimeouts   map[string]time.Duration
+		resumeKeys         []string
+
+		providerConfigs []config.ProviderTestingConfig
+	)
+
+	BeforeAll(func() {
+		By("get testing configuration")
+		providerConfigs = config.Config.Providers[config.TestingProviderDocker]
+
+		clusterDeleteFuncs = make(map[string]func() error)
+		deletionTimeouts = make(map[string]time.Duration)
+
+		kc = specNamespace("docker")
+
+		By("providing the stub credential")
+		_, err := utils.Run(exec.Command("make", "dev-docker-creds", "NAMESPACE="+kc.Namespace))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("validating that the Docker provider controller is ready")
+		mgmtClient := kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+		timeout := config.Config.Timeouts.ControllersReady
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		wait.DeploymentsAvailable(ctx, mgmtClient.CrClient, mgmtClient.Namespace, clusterdeployment.GetProviderLabel(clusterdeployment.ProviderDocker), 1, timeout, controllerManagerName)
+	})
+
+	AfterAll(func() {
+		// If we failed collect the support bundle before the cleanup
+		if CurrentSpecReport().Failed() && cleanup() {
+			for clusterName := range clusterDeleteFuncs {
+				By(fmt.Sprintf("collecting the support bundle from the %s cluster", clusterName))
+				logs.SupportBundle(kc, clusterName)
+			}
+		}
+
+		if cleanup() {
+			for clusterName, deleteFunc := range clusterDeleteFuncs {
+				deleteDockerCluster(kc, clusterName, deleteFunc, deletionTimeouts[clusterName])
+			}
+			for _, key := range resumeKeys {
+				forgetCluster(key)
+			}
+		}
+	})
+
+	for i, c := range config.Config.Providers[config.TestingProviderDocker] {
+		It(fmt.Sprintf("should work with Docker provider (configuration %d)", i), Label(c.Labels()...), func() {
+			testingConfig := providerConfigs[i]
+			DeferCleanup(startChaos())
+
+			_, _ = fmt.Fprintf(GinkgoWriter, "Testing configuration:\n%s\n", testingConfig.String())
+
+			resumeKey := fmt.Sprintf("docker-%d", i)
+			cluster := resumeCluster(resumeKey)
+			resumeKeys = append(resumeKeys, resumeKey)
+			clusterName := cluster.Name
+			clusterTemplate := testingConfig.Template
+
+			// the ClusterDeployment of the resumed cluster already exists, so
+			// it is not created again
+			templateBy(templates.TemplateDockerHostedCP, fmt.Sprintf("creating a ClusterDeployment %s with template %s", clusterName, clusterTemplate))
+			cd := clusterdeployment.GetUnstructured(templates.TemplateDockerHostedCP, clusterName, clusterTemplate)
+			clusterDeleteFuncs[clusterName] = kc.CreateClusterDeployment(context.Background(), cd, testingConfig.Timeouts)
+			deletionTimeouts[clusterName] = testingConfig.Timeouts.Deletion
+			recordCluster(resumeKey, cluster, resume.PhaseCreated)
+
+			templateBy(templates.TemplateDockerHostedCP, "waiting for infrastructure to deploy successfully")
+			deploymentValidator := clusterdeployment.NewProviderValidator(
+				templates.TemplateDockerHostedCP,
+				clusterName,
+				clusterdeployment.ValidationActionDeploy,
+			)
+			Eventually(func() error {
+				return deploymentValidator.Validate(context.Background(), kc)
+			}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+			recordCluster(resumeKey, cluster, resume.PhaseDeployed)
+
+			templateBy(templates.TemplateDockerHostedCP, "validating the service included in the cluster deployment is deployed")
+			serviceDeployedValidator := clusterdeployment.NewServiceValidator(clusterName, "managed-ingress-nginx", "default").
+				WithResourceValidation("service", clusterdeployment.ManagedServiceResource{
+					ResourceNameSuffix: "controller",
+					ValidationFunc:     clusterdeployment.ValidateService,
+				}).
+				WithResourceValidation("deployment", clusterdeployment.ManagedServiceResource{
+					ResourceNameSuffix: "controller",
+					ValidationFunc:     clusterdeployment.ValidateDeployment,
+				})
+			Eventually(func() error {
+				return serviceDeployedValidator.Validate(context.Background(), kc)
+			}).WithTimeout(10 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+
+			if testingConfig.Upgrade && !cluster.Reached(resume.PhaseUpgraded) {
+				clusterClient := kc.NewFromCluster(context.Background(), internalutils.DefaultSystemNamespace, clusterName)
+				upgradeCluster(kc, clusterClient, clusterName, testingConfig)
+
+				Eventually(func() error {
+					return deploymentValidator.Validate(context.Background(), kc)
+				}).WithTimeout(testingConfig.Timeouts.Upgrade).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+				recordCluster(resumeKey, cluster, resume.PhaseUpgraded)
+			}
+
+			// the deletion is a part of the tested lifecycle, so unlike the
+			// cloud providers it is not left to the cleanup
+			deleteDockerCluster(kc, clusterName, clusterDeleteFuncs[clusterName], testingConfig.Timeouts.Deletion)
+			delete(clusterDeleteFuncs, clusterName)
+			forgetCluster(resumeKey)
+		})
+	}
+})
+
+// deleteDockerCluster deletes the ClusterDeployment and waits for the
+// resources of the cluster to be removed within the timeout.
+func deleteDockerCluster(kc *kubeclient.KubeClient, clusterName string, deleteFunc func() error, timeout time.Duration) {
+	GinkgoHelper()
+
+	templateBy(templates.TemplateDockerHostedCP, fmt.Sprintf("deleting the %s ClusterDeployment", clusterName))
+	Expect(deleteFunc()).To(Succeed())
+
+	deletionValidator := clusterdeployment.NewProviderValidator(
+		templates.TemplateDockerHostedCP,
+		clusterName,
+		clusterdeployment.ValidationActionDelete,
+	)
+	Eventually(func() error {
+		return deletionValidator.Validate(context.Background(), kc)
+	}).WithTimeout(timeout).WithPolling(10 * time.Second).Should(Succeed())
+}
@@ -21,7 +21,6 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
-	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -33,7 +32,6 @@ import (
 	"github.com/K0rdent/kcm/test/e2e/kubeclient"
 	"github.com/K0rdent/kcm/test/e2e/logs"
 	"github.com/K0rdent/kcm/test/e2e/templates"
-	"github.com/K0rdent/kcm/test/e2e/upgrade"
 	"github.com/K0rdent/kcm/test/utils"
 )
 
@@ -42,6 +40,7 @@ var _ = Describe("Remote Cluster Templates", Label("provider:cloud", "provider:r
 		kc                    *kubeclient.KubeClient
 		clusterDeleteFuncs    []func() error
 		kubeconfigDeleteFuncs []func() error
+		clusterNames          []string
 		publicKey             string
 
 		providerConfigs []config.ProviderTestingConfig
@@ -49,13 +48,9 @@ var _ = Describe("Remote Cluster Templates", Label("provider:cloud", "provider:r
 
 	BeforeAll(func() {
 		By("get testing configuration")
-		providerConfigs = config.Config[config.TestingProviderRemote]
+		providerConfigs = config.Config.Providers[config.TestingProviderRemote]
 
-		if len(providerConfigs) == 0 {
-			Skip("Remote ClusterDeployment testing is skipped")
-		}
-
-		kc = kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+		kc = specNamespace("remote")
 
 		By("Generating SSH key for the remote cluster")
 		var privateKey string
@@ -67,7 +62,7 @@ var _ = Describe("Remote Cluster Templates", Label("provider:cloud", "provider:r
 		Expect(os.Setenv(clusterdeployment.EnvVarPrivateSSHKeyB64, privateKeyBase64)).Should(Succeed())
 
 		By("Providing cluster identity")
-		cmd := exec.Command("make", "dev-remote-creds")
+		cmd := exec.Command("make", "dev-remote-creds", "NAMESPACE="+kc.Namespace)
 		_, err = utils.Run(cmd)
 		Expect(err).NotTo(HaveOccurred())
 
@@ -80,8 +75,10 @@ var _ = Describe("Remote Cluster Templates", Label("provider:cloud", "provider:r
 	AfterAll(func() {
 		// If we failed collect the support bundle before the cleanup
 		if CurrentSpecReport().Failed() && cleanup() {
-			By("collecting the support bundle from the management cluster")
-			logs.SupportBundle("")
+			for _, clusterName := range clusterNames {
+				By(fmt.Sprintf("collecting the support bundle from the %s cluster", clusterName))
+				logs.SupportBundle(kc, clusterName)
+			}
 		}
 
 		if cleanup() {
@@ -96,15 +93,19 @@ var _ = Describe("Remote Cluster Templates", Label("provider:cloud", "provider:r
 		}
 	})
 
-	It("should work with Remote cluster provider", func() {
-		for i, testingConfig := range providerConfigs {
+	for i, c := range config.Config.Providers[config.TestingProviderRemote] {
+		It(fmt.Sprintf("should work with Remote cluster provider (configuration %d)", i), Label(c.Labels()...), func() {
+			testingConfig := providerConfigs[i]
+			DeferCleanup(startChaos())
+
 			_, _ = fmt.Fprintf(GinkgoWriter, "Testing configuration:\n%s\n", testingConfig.String())
 
 			clusterName := clusterdeployment.GenerateClusterName(fmt.Sprintf("remote-%d", i))
+			clusterNames = append(clusterNames, clusterName)
 			clusterTemplate := testingConfig.Template
 
 			By("Preparing Virtual Machines using KubeVirt")
-			ports, err := remote.PrepareVMs(context.Background(), kc.CrClient, internalutils.DefaultSystemNamespace, clusterName, publicKey, 2)
+			ports, err := remote.PrepareVMs(context.Background(), kc.CrClient, kc.Namespace, clusterName, publicKey, 2)
 			Expect(err).NotTo(HaveOccurred())
 
 			address, err := remote.GetAddress(context.Background(), kc.CrClient)
@@ -119,7 +120,7 @@ var _ = Describe("Remote Cluster Templates", Label("provider:cloud", "provider:r
 			templateBy(templates.TemplateRemoteCluster, fmt.Sprintf("creating a ClusterDeployment %s with template %s", clusterName, clusterTemplate))
 			cd := clusterdeployment.GetUnstructured(templates.TemplateRemoteCluster, clusterName, clusterTemplate)
 
-			clusterDeleteFunc := kc.CreateClusterDeployment(context.Background(), cd)
+			clusterDeleteFunc := kc.CreateClusterDeployment(context.Background(), cd, testingConfig.Timeouts)
 			clusterDeleteFuncs = append(clusterDeleteFuncs, func() error {
 				By(fmt.Sprintf("Deleting the %s ClusterDeployment", clusterName))
 				err := clusterDeleteFunc()
@@ -133,7 +134,7 @@ var _ = Describe("Remote Cluster Templates", Label("provider:cloud", "provider:r
 				)
 				Eventually(func() error {
 					return deletionValidator.Validate(context.Background(), kc)
-				}).WithTimeout(20 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Timeouts.Deletion).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
 				return nil
 			})
 
@@ -146,24 +147,16 @@ var _ = Describe("Remote Cluster Templates", Label("provider:cloud", "provider:r
 
 			Eventually(func() error {
 				return deploymentValidator.Validate(context.Background(), kc)
-			}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+			}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
 
 			if testingConfig.Upgrade {
 				standaloneClient := kc.NewFromCluster(context.Background(), internalutils.DefaultSystemNamespace, clusterName)
-				clusterUpgrade := upgrade.NewClusterUpgrade(
-					kc.CrClient,
-					standaloneClient.CrClient,
-					internalutils.DefaultSystemNamespace,
-					clusterName,
-					testingConfig.UpgradeTemplate,
-					upgrade.NewDefaultClusterValidator(),
-				)
-				clusterUpgrade.Run(context.Background())
+				upgradeCluster(kc, standaloneClient, clusterName, testingConfig)
 
 				Eventually(func() error {
 					return deploymentValidator.Validate(context.Background(), kc)
-				}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Timeouts.Upgrade).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
 			}
-		}
-	})
+		})
+	}
 })
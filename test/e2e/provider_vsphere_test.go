@@ -30,31 +30,32 @@ import (
 	"github.com/K0rdent/kcm/test/e2e/config"
 	"github.com/K0rdent/kcm/test/e2e/kubeclient"
 	"github.com/K0rdent/kcm/test/e2e/logs"
+	"github.com/K0rdent/kcm/test/e2e/resume"
 	"github.com/K0rdent/kcm/test/e2e/templates"
-	"github.com/K0rdent/kcm/test/e2e/upgrade"
 )
 
-var _ = Context("vSphere Templates", Label("provider:cloud", "provider:vsphere"), Ordered, func() {
+var _ = Context("vSphere Templates", Label("provider:onprem", "provider:vsphere"), Ordered, func() {
 	var (
 		kc                     *kubeclient.KubeClient
 		standaloneDeleteFuncs  map[string]func() error
+		deletionTimeouts       map[string]time.Duration
 		standaloneClusterNames []string
+		resumeKeys             []string
 
 		providerConfigs []config.ProviderTestingConfig
 	)
 
 	BeforeAll(func() {
 		By("get testing configuration")
-		providerConfigs = config.Config[config.TestingProviderVsphere]
+		providerConfigs = config.Config.Providers[config.TestingProviderVsphere]
 
-		if len(providerConfigs) == 0 {
-			Skip("Vsphere ClusterDeployment testing is skipped")
-		}
+		standaloneDeleteFuncs = make(map[string]func() error)
+		deletionTimeouts = make(map[string]time.Duration)
 
 		By("ensuring that env vars are set correctly")
 		vsphere.CheckEnv()
 		By("creating kube client")
-		kc = kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+		kc = specNamespace("vsphere")
 		By("providing cluster identity")
 		ci := clusteridentity.New(kc, clusterdeployment.ProviderVSphere)
 		ci.WaitForValidCredential(kc)
@@ -65,8 +66,10 @@ var _ = Context("vSphere Templates", Label("provider:cloud", "provider:vsphere")
 	AfterAll(func() {
 		// If we failed collect the support bundle before the cleanup
 		if CurrentSpecReport().Failed() && cleanup() {
-			By("collecting the support bundle from the management cluster")
-			logs.SupportBundle("")
+			for clusterName := range standaloneDeleteFuncs {
+				By(fmt.Sprintf("collecting the support bundle from the %s cluster", clusterName))
+				logs.SupportBundle(kc, clusterName)
+			}
 		}
 
 		// Run the deletion as part of the cleanup and validate it here.
@@ -89,24 +92,35 @@ var _ = Context("vSphere Templates", Label("provider:cloud", "provider:vsphere")
 					Expect(err).NotTo(HaveOccurred())
 					Eventually(func() error {
 						return deletionValidator.Validate(context.Background(), kc)
-					}).WithTimeout(10 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+					}).WithTimeout(deletionTimeouts[clusterName]).WithPolling(10 * time.Second).Should(Succeed())
 				}
 			}
+			for _, key := range resumeKeys {
+				forgetCluster(key)
+			}
 		}
 	})
 
-	It("should work with Vsphere provider", func() {
-		for i, testingConfig := range providerConfigs {
-			sdName := clusterdeployment.GenerateClusterName(fmt.Sprintf("vsphere-%d", i))
+	for i, c := range config.Config.Providers[config.TestingProviderVsphere] {
+		It(fmt.Sprintf("should work with Vsphere provider (configuration %d)", i), Label(c.Labels()...), func() {
+			testingConfig := providerConfigs[i]
+			DeferCleanup(startChaos())
+
+			resumeKey := fmt.Sprintf("vsphere-%d", i)
+			sdState := resumeCluster(resumeKey)
+			resumeKeys = append(resumeKeys, resumeKey)
+			sdName := sdState.Name
 			sdTemplate := testingConfig.Template
 			templateBy(templates.TemplateVSphereStandaloneCP, fmt.Sprintf("creating a ClusterDeployment %s with template %s", sdName, sdTemplate))
 
 			d := clusterdeployment.GetUnstructured(templates.TemplateVSphereStandaloneCP, sdName, sdTemplate)
 			clusterName := d.GetName()
 
-			deleteFunc := kc.CreateClusterDeployment(context.Background(), d)
+			deleteFunc := kc.CreateClusterDeployment(context.Background(), d, testingConfig.Timeouts)
 			standaloneDeleteFuncs[clusterName] = deleteFunc
+			deletionTimeouts[clusterName] = testingConfig.Timeouts.Deletion
 			standaloneClusterNames = append(standaloneClusterNames, clusterName)
+			recordCluster(resumeKey, sdState, resume.PhaseCreated)
 
 			By("waiting for infrastructure providers to deploy successfully")
 			deploymentValidator := clusterdeployment.NewProviderValidator(
@@ -116,24 +130,18 @@ var _ = Context("vSphere Templates", Label("provider:cloud", "provider:vsphere")
 			)
 			Eventually(func() error {
 				return deploymentValidator.Validate(context.Background(), kc)
-			}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+			}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+			recordCluster(resumeKey, sdState, resume.PhaseDeployed)
 
-			if testingConfig.Upgrade {
+			if testingConfig.Upgrade && !sdState.Reached(resume.PhaseUpgraded) {
 				standaloneClient := kc.NewFromCluster(context.Background(), internalutils.DefaultSystemNamespace, sdName)
-				clusterUpgrade := upgrade.NewClusterUpgrade(
-					kc.CrClient,
-					standaloneClient.CrClient,
-					internalutils.DefaultSystemNamespace,
-					sdName,
-					testingConfig.UpgradeTemplate,
-					upgrade.NewDefaultClusterValidator(),
-				)
-				clusterUpgrade.Run(context.Background())
+				upgradeCluster(kc, standaloneClient, sdName, testingConfig)
 
 				Eventually(func() error {
 					return deploymentValidator.Validate(context.Background(), kc)
-				}).WithTimeout(30 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}).WithTimeout(testingConfig.Timeouts.Upgrade).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+				recordCluster(resumeKey, sdState, resume.PhaseUpgraded)
 			}
-		}
-	})
+		})
+	}
 })
@@ -0,0 +1,322 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package report generates the JUnit and HTML reports of the e2e runs.
+package report
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+
+	"github.com/K0rdent/kcm/test/e2e/flake"
+	"github.com/K0rdent/kcm/test/utils"
+)
+
+const (
+	// EnvVarReportDir is the directory the reports and the artifacts are
+	// written to, defaults to the e2e-report directory of the project.
+	EnvVarReportDir = "E2E_REPORT_DIR"
+
+	defaultReportDir = "e2e-report"
+
+	// JUnitFileName is the name of the JUnit XML report.
+	JUnitFileName = "junit.xml"
+	// HTMLFileName is the name of the HTML summary.
+	HTMLFileName = "summary.html"
+	// FlakesFileName is the name of the JSON report of the flaky specs.
+	FlakesFileName = "flakes.json"
+
+	entryTemplate = "template"
+	entryArtifact = "artifact"
+
+	providerLabelPrefix = "provider:"
+)
+
+// providerCategories are the labels grouping several providers, they are not
+// reported as providers.
+var providerCategories = []string{"provider:cloud", "provider:onprem", "provider:local", "provider:multi-cloud"}
+
+var dashesRegexp = regexp.MustCompile("-+")
+
+//go:embed summary.html.tpl
+var summaryTemplate string
+
+// Dir returns the absolute path to the directory of the reports.
+func Dir() (string, error) {
+	dir := os.Getenv(EnvVarReportDir)
+	if dir == "" {
+		projectDir, err := utils.GetProjectDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get the project directory: %w", err)
+		}
+		dir = filepath.Join(projectDir, defaultReportDir)
+	}
+	return filepath.Abs(dir)
+}
+
+// SpecDir creates and returns the artifact directory of the current spec in
+// the directory of the reports, the artifacts collected outside of the specs
+// are stored in the suite directory.
+func SpecDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	name := "suite"
+	if text := CurrentSpecReport().FullText(); text != "" {
+		name = specDirName(text)
+	}
+
+	dir = filepath.Join(dir, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create the artifact directory %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// specDirName returns the name of the directory of the spec consisting of the
+// lowercase alphanumeric characters and dashes only.
+func specDirName(text string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, text)
+	return strings.Trim(dashesRegexp.ReplaceAllString(name, "-"), "-")
+}
+
+// Template records the template tested by the current spec.
+func Template(name string) {
+	AddReportEntry(entryTemplate, name, ReportEntryVisibilityNever)
+}
+
+// Artifact records the path to the artifact collected by the current spec,
+// e.g. the support bundle.
+func Artifact(path string) {
+	AddReportEntry(entryArtifact, path, ReportEntryVisibilityNever)
+}
+
+// Flake is the run of the spec labeled as flaky which has been retried.
+type Flake struct {
+	Provider string `json:"provider"`
+	Spec     string `json:"spec"`
+	// Attempts is the number of the times the spec has been run.
+	Attempts int `json:"attempts"`
+	// MaxAttempts is the number of the times the spec could be run.
+	MaxAttempts int `json:"maxAttempts"`
+	// Passed reports whether the spec has eventually passed.
+	Passed bool `json:"passed"`
+}
+
+// Generate writes the JUnit XML report, the HTML summary and the report of
+// the flaky specs of the suite report to the given directory.
+func Generate(report types.Report, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create the report directory %s: %w", dir, err)
+	}
+
+	if err := reporters.GenerateJUnitReport(report, filepath.Join(dir, JUnitFileName)); err != nil {
+		return fmt.Errorf("failed to generate the JUnit report: %w", err)
+	}
+
+	tpl, err := template.New(HTMLFileName).Parse(summaryTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse the summary template: %w", err)
+	}
+
+	f, err := os.Create(filepath.Join(dir, HTMLFileName))
+	if err != nil {
+		return fmt.Errorf("failed to create the HTML summary: %w", err)
+	}
+	defer f.Close()
+
+	s := newSummary(report, dir)
+	if err := tpl.Execute(f, s); err != nil {
+		return fmt.Errorf("failed to generate the HTML summary: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write the HTML summary: %w", err)
+	}
+
+	flakes, err := json.MarshalIndent(s.Flakes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the flaky specs: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, FlakesFileName), flakes, 0o644); err != nil {
+		return fmt.Errorf("failed to write the report of the flaky specs: %w", err)
+	}
+	return nil
+}
+
+type summary struct {
+	Description string
+	StartTime   time.Time
+	RunTime     time.Duration
+	Succeeded   bool
+	Artifacts   []artifact
+	Providers   []providerSummary
+	Flakes      []Flake
+	Specs       []specSummary
+}
+
+type providerSummary struct {
+	Name    string
+	RunTime time.Duration
+	Passed  int
+	Failed  int
+	Skipped int
+	Flaked  int
+}
+
+type specSummary struct {
+	Provider  string
+	Name      string
+	State     string
+	Failure   string
+	Templates []string
+	Artifacts []artifact
+	RunTime   time.Duration
+	Attempts  int
+	Failed    bool
+}
+
+type artifact struct {
+	Name string
+	Link string
+}
+
+func newSummary(report types.Report, dir string) summary {
+	s := summary{
+		Description: report.SuiteDescription,
+		StartTime:   report.StartTime,
+		RunTime:     report.RunTime.Round(time.Second),
+		Succeeded:   report.SuiteSucceeded,
+	}
+
+	providers := make(map[string]*providerSummary)
+	for _, spec := range report.SpecReports {
+		// the suite setup nodes are reported only if they have failed, their
+		// artifacts are reported for the whole suite
+		if spec.LeafNodeType != types.NodeTypeIt && !spec.Failed() {
+			for _, entry := range spec.ReportEntries {
+				if entry.Name == entryArtifact {
+					s.Artifacts = append(s.Artifacts, newArtifact(entry.StringRepresentation(), dir))
+				}
+			}
+			continue
+		}
+
+		ss := specSummary{
+			Provider: specProvider(spec),
+			Name:     spec.FullText(),
+			State:    spec.State.String(),
+			Failure:  spec.FailureMessage(),
+			RunTime:  spec.RunTime.Round(time.Second),
+			Attempts: spec.NumAttempts,
+			Failed:   spec.Failed(),
+		}
+		if ss.Name == "" {
+			ss.Name = spec.LeafNodeType.String()
+		}
+		for _, entry := range spec.ReportEntries {
+			switch entry.Name {
+			case entryTemplate:
+				ss.Templates = append(ss.Templates, entry.StringRepresentation())
+			case entryArtifact:
+				ss.Artifacts = append(ss.Artifacts, newArtifact(entry.StringRepresentation(), dir))
+			}
+		}
+		s.Specs = append(s.Specs, ss)
+
+		p, ok := providers[ss.Provider]
+		if !ok {
+			p = &providerSummary{Name: ss.Provider}
+			providers[ss.Provider] = p
+		}
+		p.RunTime += ss.RunTime
+		if slices.Contains(spec.Labels(), flake.FlakyLabel) && spec.NumAttempts > 1 {
+			passed := spec.State == types.SpecStatePassed
+			if passed {
+				p.Flaked++
+			}
+			s.Flakes = append(s.Flakes, Flake{
+				Provider:    ss.Provider,
+				Spec:        ss.Name,
+				Attempts:    spec.NumAttempts,
+				MaxAttempts: spec.MaxFlakeAttempts,
+				Passed:      passed,
+			})
+		}
+		switch {
+		case spec.Failed():
+			p.Failed++
+		case spec.State == types.SpecStatePassed:
+			p.Passed++
+		default:
+			p.Skipped++
+		}
+	}
+
+	for _, p := range providers {
+		s.Providers = append(s.Providers, *p)
+	}
+	slices.SortFunc(s.Providers, func(a, b providerSummary) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortStableFunc(s.Specs, func(a, b specSummary) int { return strings.Compare(a.Provider, b.Provider) })
+	slices.SortStableFunc(s.Flakes, func(a, b Flake) int { return strings.Compare(a.Provider, b.Provider) })
+
+	return s
+}
+
+// specProvider returns the provider the spec is labeled with, or its first
+// label if there is no such one.
+func specProvider(spec types.SpecReport) string {
+	labels := spec.Labels()
+	for _, l := range labels {
+		if strings.HasPrefix(l, providerLabelPrefix) && !slices.Contains(providerCategories, l) {
+			return strings.TrimPrefix(l, providerLabelPrefix)
+		}
+	}
+	if len(labels) > 0 {
+		return labels[0]
+	}
+	return "suite"
+}
+
+// newArtifact returns the artifact with the link relative to the report
+// directory, so the links stay valid once they are archived together.
+func newArtifact(path, dir string) artifact {
+	link := path
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		link = filepath.ToSlash(rel)
+	}
+	return artifact{Name: filepath.Base(path), Link: link}
+}
@@ -0,0 +1,138 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/types"
+	. "github.com/onsi/gomega"
+
+	"github.com/K0rdent/kcm/test/e2e/flake"
+)
+
+func testReport(dir string) types.Report {
+	return types.Report{
+		SuiteDescription: "e2e suite",
+		RunTime:          time.Hour,
+		SpecReports: types.SpecReports{
+			{
+				LeafNodeType: types.NodeTypeSynchronizedAfterSuite,
+				State:        types.SpecStatePassed,
+				ReportEntries: types.ReportEntries{
+					{Name: entryArtifact, Value: types.WrapEntryValue(filepath.Join(dir, "support-bundle-management-1.tar.gz"))},
+				},
+			},
+			{
+				ContainerHierarchyTexts:  []string{"AWS Templates"},
+				ContainerHierarchyLabels: [][]string{{"provider:cloud", "provider:aws"}},
+				LeafNodeType:             types.NodeTypeIt,
+				LeafNodeText:             "should work with an AWS provider",
+				State:                    types.SpecStateFailed,
+				RunTime:                  30 * time.Minute,
+				Failure:                  types.Failure{Message: "cluster is not ready"},
+				ReportEntries: types.ReportEntries{
+					{Name: entryTemplate, Value: types.WrapEntryValue("aws-standalone-cp-0-1-0")},
+					{Name: entryArtifact, Value: types.WrapEntryValue(filepath.Join(dir, "support-bundle-aws-1.tar.gz"))},
+				},
+			},
+			{
+				ContainerHierarchyTexts:  []string{"AWS Templates"},
+				ContainerHierarchyLabels: [][]string{{"provider:cloud", "provider:aws"}},
+				LeafNodeType:             types.NodeTypeIt,
+				LeafNodeText:             "should upgrade",
+				State:                    types.SpecStateSkipped,
+			},
+			{
+				ContainerHierarchyTexts:  []string{"controller"},
+				ContainerHierarchyLabels: [][]string{{"controller"}},
+				LeafNodeType:             types.NodeTypeIt,
+				LeafNodeText:             "should run successfully",
+				State:                    types.SpecStatePassed,
+				RunTime:                  time.Minute,
+			},
+			{
+				ContainerHierarchyTexts:  []string{"vSphere Templates"},
+				ContainerHierarchyLabels: [][]string{{"provider:onprem", "provider:vsphere"}},
+				LeafNodeType:             types.NodeTypeIt,
+				LeafNodeText:             "should deploy",
+				LeafNodeLabels:           []string{flake.FlakyLabel},
+				State:                    types.SpecStatePassed,
+				RunTime:                  time.Minute,
+				NumAttempts:              2,
+				MaxFlakeAttempts:         3,
+			},
+		},
+	}
+}
+
+func TestNewSummary(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	s := newSummary(testReport(dir), dir)
+
+	g.Expect(s.Artifacts).To(Equal([]artifact{{Name: "support-bundle-management-1.tar.gz", Link: "support-bundle-management-1.tar.gz"}}))
+	g.Expect(s.Providers).To(Equal([]providerSummary{
+		{Name: "aws", RunTime: 30 * time.Minute, Failed: 1, Skipped: 1},
+		{Name: "controller", RunTime: time.Minute, Passed: 1},
+		{Name: "vsphere", RunTime: time.Minute, Passed: 1, Flaked: 1},
+	}))
+	g.Expect(s.Flakes).To(Equal([]Flake{{Provider: "vsphere", Spec: "vSphere Templates should deploy", Attempts: 2, MaxAttempts: 3, Passed: true}}))
+	g.Expect(s.Specs).To(HaveLen(4))
+	g.Expect(s.Specs[0]).To(Equal(specSummary{
+		Provider:  "aws",
+		Name:      "AWS Templates should work with an AWS provider",
+		State:     "failed",
+		Failure:   "cluster is not ready",
+		Templates: []string{"aws-standalone-cp-0-1-0"},
+		Artifacts: []artifact{{Name: "support-bundle-aws-1.tar.gz", Link: "support-bundle-aws-1.tar.gz"}},
+		RunTime:   30 * time.Minute,
+		Failed:    true,
+	}))
+}
+
+func TestGenerate(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := filepath.Join(t.TempDir(), "report")
+	g.Expect(Generate(testReport(dir), dir)).To(Succeed())
+
+	junit, err := os.ReadFile(filepath.Join(dir, JUnitFileName))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(junit)).To(ContainSubstring("should work with an AWS provider"))
+
+	html, err := os.ReadFile(filepath.Join(dir, HTMLFileName))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(html)).To(And(
+		ContainSubstring("aws-standalone-cp-0-1-0"),
+		ContainSubstring("cluster is not ready"),
+		ContainSubstring(`<a href="support-bundle-aws-1.tar.gz">`),
+	))
+
+	flakes, err := os.ReadFile(filepath.Join(dir, FlakesFileName))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(flakes)).To(ContainSubstring(`"spec": "vSphere Templates should deploy"`))
+}
+
+func TestSpecDirName(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(specDirName("AWS Templates should work with an AWS provider (configuration 0)")).To(Equal("aws-templates-should-work-with-an-aws-provider-configuration-0"))
+	g.Expect(specDirName("[SynchronizedAfterSuite]")).To(Equal("synchronizedaftersuite"))
+}
@@ -0,0 +1,75 @@
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Description }}</title>
+<style>
+  body { font-family: sans-serif; margin: 2em; }
+  table { border-collapse: collapse; margin-bottom: 2em; }
+  th, td { border: 1px solid #ccc; padding: 0.4em 0.8em; text-align: left; vertical-align: top; }
+  th { background: #f0f0f0; }
+  .passed { color: #1a7f37; }
+  .failed { color: #cf222e; }
+  pre { margin: 0; white-space: pre-wrap; max-width: 60em; }
+</style>
+</head>
+<body>
+<h1>{{ .Description }}</h1>
+<p>
+  Started at {{ .StartTime.Format "2006-01-02 15:04:05 MST" }}, took {{ .RunTime }}:
+  {{ if .Succeeded }}<span class="passed">succeeded</span>{{ else }}<span class="failed">failed</span>{{ end }}
+</p>
+{{- if .Artifacts }}
+<p>
+  Artifacts:
+  {{- range .Artifacts }} <a href="{{ .Link }}">{{ .Name }}</a>{{ end }}
+</p>
+{{- end }}
+
+<h2>Providers</h2>
+<table>
+  <tr><th>Provider</th><th>Passed</th><th>Failed</th><th>Skipped</th><th>Flaked</th><th>Duration</th></tr>
+  {{- range .Providers }}
+  <tr>
+    <td>{{ .Name }}</td>
+    <td class="passed">{{ .Passed }}</td>
+    <td{{ if .Failed }} class="failed"{{ end }}>{{ .Failed }}</td>
+    <td>{{ .Skipped }}</td>
+    <td>{{ .Flaked }}</td>
+    <td>{{ .RunTime }}</td>
+  </tr>
+  {{- end }}
+</table>
+
+{{- if .Flakes }}
+<h2>Flaky specs</h2>
+<table>
+  <tr><th>Provider</th><th>Spec</th><th>Attempts</th><th>State</th></tr>
+  {{- range .Flakes }}
+  <tr>
+    <td>{{ .Provider }}</td>
+    <td>{{ .Spec }}</td>
+    <td>{{ .Attempts }}/{{ .MaxAttempts }}</td>
+    <td class="{{ if .Passed }}passed{{ else }}failed{{ end }}">{{ if .Passed }}flaked{{ else }}failed{{ end }}</td>
+  </tr>
+  {{- end }}
+</table>
+{{- end }}
+
+<h2>Specs</h2>
+<table>
+  <tr><th>Provider</th><th>Spec</th><th>Templates</th><th>State</th><th>Attempts</th><th>Duration</th><th>Artifacts</th></tr>
+  {{- range .Specs }}
+  <tr>
+    <td>{{ .Provider }}</td>
+    <td>{{ .Name }}{{ if .Failure }}<pre class="failed">{{ .Failure }}</pre>{{ end }}</td>
+    <td>{{ range .Templates }}{{ . }}<br>{{ end }}</td>
+    <td class="{{ if .Failed }}failed{{ else }}passed{{ end }}">{{ .State }}</td>
+    <td>{{ .Attempts }}</td>
+    <td>{{ .RunTime }}</td>
+    <td>{{ range .Artifacts }}<a href="{{ .Link }}">{{ .Name }}</a><br>{{ end }}</td>
+  </tr>
+  {{- end }}
+</table>
+</body>
+</html>
@@ -0,0 +1,132 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resume records the progress of the clusters of the e2e specs, so
+// the next run can continue the specs from the phase they reached instead of
+// provisioning the clusters again.
+package resume
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// EnvVarStateDir is the directory the progress of the clusters is
+	// recorded to, the progress is not recorded if unset.
+	EnvVarStateDir = "E2E_STATE_DIR"
+	// EnvVarResume enables the resume mode, in which the specs continue with
+	// the clusters recorded in the state directory by the previous run.
+	EnvVarResume = "E2E_RESUME"
+)
+
+// Phase is the phase of the lifecycle of the cluster reached by the spec.
+type Phase string
+
+const (
+	// PhaseCreated is reached once the ClusterDeployment is created.
+	PhaseCreated Phase = "Created"
+	// PhaseDeployed is reached once the deployment of the cluster is
+	// validated.
+	PhaseDeployed Phase = "Deployed"
+	// PhaseUpgraded is reached once the upgrade of the cluster is validated.
+	PhaseUpgraded Phase = "Upgraded"
+)
+
+// phases are the phases in the order they are reached.
+var phases = []Phase{PhaseCreated, PhaseDeployed, PhaseUpgraded}
+
+// Cluster is the progress of the cluster of the spec.
+type Cluster struct {
+	// UpdatedAt is the time the phase was reached at.
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Name of the cluster.
+	Name string `json:"name"`
+	// Phase reached by the spec.
+	Phase Phase `json:"phase"`
+}
+
+// Reached reports whether the cluster has reached the phase.
+func (c Cluster) Reached(phase Phase) bool {
+	reached := slices.Index(phases, c.Phase)
+	return reached >= 0 && reached >= slices.Index(phases, phase)
+}
+
+// Enabled reports whether the resume mode is enabled.
+func Enabled() bool {
+	resume, _ := strconv.ParseBool(os.Getenv(EnvVarResume))
+	return resume && os.Getenv(EnvVarStateDir) != ""
+}
+
+func statePath(key string) string {
+	return filepath.Join(os.Getenv(EnvVarStateDir), key+".json")
+}
+
+// Load returns the cluster of the spec recorded by the previous run, ok is
+// false if the resume mode is disabled or nothing is recorded for the spec.
+func Load(key string) (c Cluster, ok bool, err error) {
+	if !Enabled() {
+		return c, false, nil
+	}
+
+	data, err := os.ReadFile(statePath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return c, false, nil
+	}
+	if err != nil {
+		return c, false, fmt.Errorf("failed to read the state of %s: %w", key, err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, false, fmt.Errorf("failed to parse the state of %s: %w", key, err)
+	}
+	return c, true, nil
+}
+
+// Record records the phase reached by the cluster of the spec, nothing is
+// recorded if the state directory is unset. Each spec is recorded to its own
+// file, so the specs run in parallel processes do not overwrite each other.
+func Record(key, name string, phase Phase) error {
+	if os.Getenv(EnvVarStateDir) == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(Cluster{Name: name, Phase: phase, UpdatedAt: time.Now().UTC()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the state of %s: %w", key, err)
+	}
+	if err := os.MkdirAll(os.Getenv(EnvVarStateDir), 0o755); err != nil {
+		return fmt.Errorf("failed to create the state directory: %w", err)
+	}
+	if err := os.WriteFile(statePath(key), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write the state of %s: %w", key, err)
+	}
+	return nil
+}
+
+// Forget removes the state of the spec, e.g. once its cluster is deleted.
+func Forget(key string) error {
+	if os.Getenv(EnvVarStateDir) == "" {
+		return nil
+	}
+	if err := os.Remove(statePath(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove the state of %s: %w", key, err)
+	}
+	return nil
+}
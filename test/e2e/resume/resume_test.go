@@ -0,0 +1,59 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resume
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRecordLoad(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Setenv(EnvVarStateDir, t.TempDir())
+	g.Expect(Record("aws-0", "ci-12345-aws-0", PhaseDeployed)).To(Succeed())
+
+	_, ok, err := Load("aws-0")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ok).To(BeFalse(), "the state must not be loaded with the resume mode disabled")
+
+	t.Setenv(EnvVarResume, "true")
+	c, ok, err := Load("aws-0")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ok).To(BeTrue())
+	g.Expect(c.Name).To(Equal("ci-12345-aws-0"))
+	g.Expect(c.Reached(PhaseCreated)).To(BeTrue())
+	g.Expect(c.Reached(PhaseDeployed)).To(BeTrue())
+	g.Expect(c.Reached(PhaseUpgraded)).To(BeFalse())
+
+	_, ok, err = Load("azure-0")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ok).To(BeFalse())
+
+	g.Expect(Forget("aws-0")).To(Succeed())
+	g.Expect(Forget("aws-0")).To(Succeed())
+	_, ok, err = Load("aws-0")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestRecordDisabled(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Setenv(EnvVarStateDir, "")
+	g.Expect(Record("aws-0", "ci-12345-aws-0", PhaseCreated)).To(Succeed())
+	g.Expect(Forget("aws-0")).To(Succeed())
+}
@@ -0,0 +1,204 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment/aws"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment/clusteridentity"
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/logs"
+	"github.com/K0rdent/kcm/test/e2e/templates"
+	"github.com/K0rdent/kcm/test/e2e/wait"
+)
+
+// The credential rotation specs rotate the secret of the AWS ClusterIdentity
+// of the deployed cluster and check the cluster keeps being reconciled and its
+// workers can still be scaled with the rotated credentials. The secret is
+// rotated to the AWS_ROTATED_* credentials, the specs are skipped unless they
+// are set. The ClusterIdentity is dedicated to the specs, so the rotation does
+// not affect the other specs.
+var _ = Describe("Credential rotation", Label("rotation", "provider:cloud", "provider:aws"), Ordered, func() {
+	var (
+		kc                  *kubeclient.KubeClient
+		ci                  *clusteridentity.ClusterIdentity
+		clusterName         string
+		testingConfig       config.ProviderTestingConfig
+		deploymentValidator *clusterdeployment.ProviderValidator
+		machines            map[string]string
+		rotated             map[string]string
+	)
+
+	BeforeAll(func() {
+		providerConfigs := config.Config.Providers[config.TestingProviderAWS]
+		if len(providerConfigs) == 0 {
+			Skip("the AWS provider is not configured for testing")
+		}
+		testingConfig = providerConfigs[0]
+
+		rotated = map[string]string{
+			"AccessKeyID":     os.Getenv(clusterdeployment.EnvVarAWSRotatedAccessKeyID),
+			"SecretAccessKey": os.Getenv(clusterdeployment.EnvVarAWSRotatedSecretAccessKey),
+			"SessionToken":    os.Getenv(clusterdeployment.EnvVarAWSRotatedSessionToken),
+		}
+		if rotated["AccessKeyID"] == "" || rotated["SecretAccessKey"] == "" {
+			Skip(fmt.Sprintf("the rotated credentials are not set with %s and %s",
+				clusterdeployment.EnvVarAWSRotatedAccessKeyID, clusterdeployment.EnvVarAWSRotatedSecretAccessKey))
+		}
+		if rotated["AccessKeyID"] == os.Getenv(clusterdeployment.EnvVarAWSAccessKeyID) {
+			Fail(fmt.Sprintf("the rotated credentials of %s are the current ones", clusterdeployment.EnvVarAWSRotatedAccessKeyID))
+		}
+		ctx := context.Background()
+
+		By("providing the cluster identity dedicated to the rotation")
+		kc = specNamespace("rotation")
+		ci = clusteridentity.NewNamed(kc, clusterdeployment.ProviderAWS, "aws-rotation")
+		ci.WaitForValidCredential(kc)
+		GinkgoT().Setenv(clusterdeployment.EnvVarAWSClusterIdentity, ci.IdentityName)
+		GinkgoT().Setenv(clusterdeployment.EnvVarAWSInstanceType, aws.InstanceType(testingConfig.Architecture, "small"))
+
+		clusterTemplates, err := templates.GetSortedClusterTemplates(ctx, kc.CrClient, kc.Namespace)
+		Expect(err).NotTo(HaveOccurred())
+		latest := templates.FindLatestTemplatesWithType(clusterTemplates, templates.TemplateAWSStandaloneCP, 1)
+		Expect(latest).NotTo(BeEmpty(), "no %s templates are installed", templates.TemplateAWSStandaloneCP)
+
+		clusterName = clusterdeployment.GenerateClusterName("rotation")
+		templateBy(templates.TemplateAWSStandaloneCP, fmt.Sprintf("creating a ClusterDeployment %s with template %s", clusterName, latest[0]))
+		cd := clusterdeployment.GetUnstructured(templates.TemplateAWSStandaloneCP, clusterName, latest[0])
+		kc.CreateClusterDeployment(ctx, cd, testingConfig.Timeouts)
+
+		templateBy(templates.TemplateAWSStandaloneCP, "waiting for infrastructure to deploy successfully")
+		deploymentValidator = clusterdeployment.NewProviderValidator(
+			templates.TemplateAWSStandaloneCP,
+			clusterName,
+			clusterdeployment.ValidationActionDeploy,
+		)
+		Eventually(func() error {
+			return deploymentValidator.Validate(ctx, kc)
+		}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+
+		machines = clusterMachines(ctx, kc, clusterName)
+	})
+
+	AfterAll(func() {
+		if kc == nil {
+			return
+		}
+
+		if CurrentSpecReport().Failed() && cleanup() && clusterName != "" {
+			By(fmt.Sprintf("collecting the support bundle from the %s cluster", clusterName))
+			logs.SupportBundle(kc, clusterName)
+		}
+
+		if !cleanup() {
+			return
+		}
+
+		if clusterName != "" {
+			By(fmt.Sprintf("deleting the %s ClusterDeployment", clusterName))
+			cd := &kcmv1.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: kc.Namespace}}
+			Expect(crclient.IgnoreNotFound(kc.CrClient.Delete(context.Background(), cd))).To(Succeed())
+
+			deletionValidator := clusterdeployment.NewProviderValidator(
+				templates.TemplateAWSStandaloneCP,
+				clusterName,
+				clusterdeployment.ValidationActionDelete,
+			)
+			Eventually(func() error {
+				return deletionValidator.Validate(context.Background(), kc)
+			}).WithTimeout(testingConfig.Timeouts.Deletion).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+		}
+
+		// the identity is deleted once the cluster is, since the deletion of
+		// the cloud resources requires it
+		if ci != nil {
+			ci.Delete(kc)
+		}
+	})
+
+	It("should keep reconciling the cluster once the credential secret is rotated", func() {
+		ctx := context.Background()
+
+		ci.Rotate(kc, rotated)
+
+		By("validating that the Credential stays ready")
+		ci.WaitForValidCredential(kc)
+
+		templateBy(templates.TemplateAWSStandaloneCP, "validating that the cluster stays ready with the rotated credentials")
+		wait.ClusterDeploymentReady(ctx, kc.CrClient, types.NamespacedName{Namespace: kc.Namespace, Name: clusterName}, testingConfig.Timeouts.Deployment)
+		Eventually(func() error {
+			return deploymentValidator.Validate(ctx, kc)
+		}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+
+		Expect(clusterMachines(ctx, kc, clusterName)).To(Equal(machines), "the machines of the cluster have been replaced after the rotation")
+	})
+
+	It("should scale the workers of the cluster with the rotated credentials", func() {
+		ctx := context.Background()
+
+		cd, err := kc.GetClusterDeployment(ctx, clusterName)
+		Expect(err).NotTo(HaveOccurred())
+		workers, _, err := unstructured.NestedInt64(cd.Object, "spec", "config", "workersNumber")
+		Expect(err).NotTo(HaveOccurred())
+
+		templateBy(templates.TemplateAWSStandaloneCP, fmt.Sprintf("scaling the workers of the %s cluster from %d to %d", clusterName, workers, workers+1))
+		patch := fmt.Appendf(nil, `{"spec":{"config":{"workersNumber":%d}}}`, workers+1)
+		Expect(kc.CrClient.Patch(ctx, &kcmv1.ClusterDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: kc.Namespace},
+		}, crclient.RawPatch(types.MergePatchType, patch))).To(Succeed())
+
+		templateBy(templates.TemplateAWSStandaloneCP, "waiting for the new worker machine to be running")
+		Eventually(func() error {
+			current, err := kc.ListMachines(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+			if len(current) != len(machines)+1 {
+				return fmt.Errorf("the cluster has %d machines, expected %d", len(current), len(machines)+1)
+			}
+			var errs error
+			for _, m := range current {
+				if phase, _, _ := unstructured.NestedString(m.Object, "status", "phase"); phase != "Running" {
+					errs = errors.Join(errs, fmt.Errorf("machine %s is %s", m.GetName(), phase))
+				}
+			}
+			return errs
+		}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+
+		scaled := clusterMachines(ctx, kc, clusterName)
+		for name, providerID := range machines {
+			Expect(scaled).To(HaveKeyWithValue(name, providerID), "the machine %s has been replaced upon the scaling", name)
+		}
+
+		Eventually(func() error {
+			return deploymentValidator.Validate(ctx, kc)
+		}).WithTimeout(testingConfig.Timeouts.Deployment).WithPolling(testingConfig.Timeouts.Polling).Should(Succeed())
+		wait.ClusterDeploymentReady(ctx, kc.CrClient, types.NamespacedName{Namespace: kc.Namespace, Name: clusterName}, testingConfig.Timeouts.Deployment)
+	})
+})
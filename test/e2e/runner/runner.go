@@ -0,0 +1,198 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package runner runs the compiled e2e suite against the existing management
+// cluster, so the suite can be run without the Makefile of the project.
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/management"
+	"github.com/K0rdent/kcm/test/e2e/report"
+)
+
+// SuiteBinaryName is the name of the compiled e2e suite looked up next to the
+// runner by default.
+const SuiteBinaryName = "e2e.test"
+
+// interruptGracePeriod is the time the interrupted suite is given to run its
+// cleanup before it is killed.
+const interruptGracePeriod = 10 * time.Minute
+
+// Options are the options of the run of the suite.
+type Options struct {
+	// Stdout and Stderr receive the output of the suite.
+	Stdout io.Writer
+	Stderr io.Writer
+	// Suite is the path to the compiled e2e suite.
+	Suite string
+	// LabelFilter is the Ginkgo label filter of the specs run in addition to
+	// the providers.
+	LabelFilter string
+	// ConfigFile is the path to the testing configuration, the default one
+	// embedded into the suite is used if empty.
+	ConfigFile string
+	// ArtifactsDir is the directory the reports and the artifacts are
+	// written to.
+	ArtifactsDir string
+	// Kubeconfig is the path to the kubeconfig of the management cluster,
+	// the one of the environment is used if empty.
+	Kubeconfig string
+	// Providers are the providers the specs of which are run, all of the
+	// specs are run if empty.
+	Providers []string
+	// Timeout is the timeout of the whole run.
+	Timeout time.Duration
+	// NoCleanup keeps the clusters and the namespaces of the specs.
+	NoCleanup bool
+	// DryRun only lists the specs which would be run.
+	DryRun bool
+}
+
+// DefaultSuite returns the path to the compiled e2e suite next to the runner.
+func DefaultSuite() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the path to the runner: %w", err)
+	}
+	return filepath.Join(filepath.Dir(executable), SuiteBinaryName), nil
+}
+
+// LabelFilter returns the Ginkgo label filter selecting the specs of any of
+// the providers matching the filter.
+func LabelFilter(providers []string, filter string) string {
+	labels := make([]string, 0, len(providers))
+	for _, p := range providers {
+		if p = strings.TrimSpace(p); p != "" {
+			labels = append(labels, "provider:"+p)
+		}
+	}
+
+	var parts []string
+	if len(labels) > 0 {
+		parts = append(parts, "("+strings.Join(labels, " || ")+")")
+	}
+	if filter = strings.TrimSpace(filter); filter != "" {
+		parts = append(parts, "("+filter+")")
+	}
+	return strings.Join(parts, " && ")
+}
+
+// Validate checks the providers are known to the suite.
+func (o Options) Validate() error {
+	var errs error
+	for _, p := range o.Providers {
+		switch config.TestingProvider(strings.TrimSpace(p)) {
+		case config.TestingProviderAWS, config.TestingProviderAzure, config.TestingProviderVsphere,
+			config.TestingProviderAdopted, config.TestingProviderRemote, config.TestingProviderDocker,
+			config.TestingProviderInMemory:
+		default:
+			errs = errors.Join(errs, fmt.Errorf("unknown provider %q", p))
+		}
+	}
+	if o.Timeout < 0 {
+		errs = errors.Join(errs, fmt.Errorf("negative timeout %s", o.Timeout))
+	}
+	return errs
+}
+
+// Args returns the arguments of the suite.
+func (o Options) Args() []string {
+	args := []string{
+		"-test.v",
+		// the run is limited by the timeout of Ginkgo, which reports the
+		// interrupted specs unlike the one of the test binary
+		"-test.timeout=0",
+		"-ginkgo.v",
+	}
+	if o.Timeout > 0 {
+		args = append(args, "-ginkgo.timeout="+o.Timeout.String())
+	}
+	if filter := LabelFilter(o.Providers, o.LabelFilter); filter != "" {
+		args = append(args, "-ginkgo.label-filter="+filter)
+	}
+	if o.DryRun {
+		args = append(args, "-ginkgo.dry-run")
+	}
+	return args
+}
+
+// Env returns the environment of the suite, the given environment with the
+// variables configuring the suite set.
+func (o Options) Env(environ []string) ([]string, error) {
+	env := append([]string{}, environ...)
+	env = append(env, management.EnvVarExisting+"=true")
+
+	// the later variables override the ones of the given environment
+	for _, v := range []struct{ name, path string }{
+		{config.EnvVarConfigFile, o.ConfigFile},
+		{report.EnvVarReportDir, o.ArtifactsDir},
+		{"KUBECONFIG", o.Kubeconfig},
+	} {
+		if v.path == "" {
+			continue
+		}
+		// the suite might be run in another directory
+		abs, err := filepath.Abs(v.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the absolute path of %s: %w", v.path, err)
+		}
+		env = append(env, v.name+"="+abs)
+	}
+	if o.NoCleanup {
+		env = append(env, clusterdeployment.EnvVarNoCleanup+"=1")
+	}
+	return env, nil
+}
+
+// Run runs the suite until it completes, its exit code is reported as the
+// *exec.ExitError. Once the context is canceled, the suite is interrupted, so
+// it still cleans up the clusters of the specs.
+func Run(ctx context.Context, o Options) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(o.Suite); err != nil {
+		return fmt.Errorf("failed to find the e2e suite, build it with the build-e2e target: %w", err)
+	}
+	env, err := o.Env(os.Environ())
+	if err != nil {
+		return err
+	}
+	if o.ArtifactsDir != "" {
+		if err := os.MkdirAll(o.ArtifactsDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create the artifacts directory: %w", err)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, o.Suite, o.Args()...)
+	cmd.Env = env
+	cmd.Stdout, cmd.Stderr = o.Stdout, o.Stderr
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = interruptGracePeriod
+	return cmd.Run()
+}
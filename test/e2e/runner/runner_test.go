@@ -0,0 +1,89 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLabelFilter(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		providers []string
+		filter    string
+		expected  string
+	}{
+		{name: "all specs"},
+		{name: "single provider", providers: []string{"aws"}, expected: "(provider:aws)"},
+		{name: "several providers", providers: []string{"aws", " azure", ""}, expected: "(provider:aws || provider:azure)"},
+		{name: "filter only", filter: "type:hosted", expected: "(type:hosted)"},
+		{name: "providers and filter", providers: []string{"docker"}, filter: "!scenario", expected: "(provider:docker) && (!scenario)"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(LabelFilter(tc.providers, tc.filter)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Options{Providers: []string{"aws", "in-memory"}}.Validate()).To(Succeed())
+	g.Expect(Options{Providers: []string{"gcp"}}.Validate()).To(MatchError(ContainSubstring(`unknown provider "gcp"`)))
+	g.Expect(Options{Timeout: -time.Second}.Validate()).To(MatchError(ContainSubstring("negative timeout")))
+}
+
+func TestArgs(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Options{}.Args()).To(Equal([]string{"-test.v", "-test.timeout=0", "-ginkgo.v"}))
+	g.Expect(Options{Providers: []string{"aws"}, Timeout: time.Hour, DryRun: true}.Args()).To(Equal([]string{
+		"-test.v", "-test.timeout=0", "-ginkgo.v",
+		"-ginkgo.timeout=1h0m0s",
+		"-ginkgo.label-filter=(provider:aws)",
+		"-ginkgo.dry-run",
+	}))
+}
+
+func TestEnv(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := Options{}.Env([]string{"KUBECONFIG=/kubeconfig"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env).To(Equal([]string{"KUBECONFIG=/kubeconfig", "E2E_EXISTING_MANAGEMENT=true"}))
+
+	artifacts, err := filepath.Abs("artifacts")
+	g.Expect(err).NotTo(HaveOccurred())
+	env, err = Options{
+		ConfigFile:   "/config.yaml",
+		ArtifactsDir: "artifacts",
+		Kubeconfig:   "/other-kubeconfig",
+		NoCleanup:    true,
+	}.Env([]string{"KUBECONFIG=/kubeconfig"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env).To(Equal([]string{
+		"KUBECONFIG=/kubeconfig",
+		"E2E_EXISTING_MANAGEMENT=true",
+		"E2E_CONFIG=/config.yaml",
+		"E2E_REPORT_DIR=" + artifacts,
+		"KUBECONFIG=/other-kubeconfig",
+		"NO_CLEANUP=1",
+	}))
+}
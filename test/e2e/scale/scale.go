@@ -0,0 +1,279 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scale measures the load of the controller manager of kcm while it
+// reconciles many ClusterDeployments at once and checks the load against the
+// regression thresholds of the scale testing.
+package scale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+)
+
+const (
+	// MetricsPort is the port of the metrics endpoint of the controller
+	// manager.
+	MetricsPort = 8080
+
+	// clusterDeploymentController is the name of the ClusterDeployment
+	// controller in the metrics of the reconciles.
+	clusterDeploymentController = "clusterdeployment"
+
+	metricReconcileTime   = "controller_runtime_reconcile_time_seconds"
+	metricResidentMemory  = "process_resident_memory_bytes"
+	metricRESTClientCalls = "rest_client_requests_total"
+
+	mib = 1 << 20
+)
+
+// Sample is the snapshot of the metrics of the controller manager.
+type Sample struct {
+	// ReconcileBuckets are the cumulative numbers of the reconciles of the
+	// ClusterDeployments per finite upper bound of the latency in seconds.
+	ReconcileBuckets map[float64]uint64
+	// Reconciles is the number of the reconciles of the ClusterDeployments.
+	Reconciles uint64
+	// ReconcileSeconds is the total time of the reconciles of the
+	// ClusterDeployments.
+	ReconcileSeconds float64
+	// ResidentMemoryBytes is the resident memory of the controller manager.
+	ResidentMemoryBytes float64
+	// APIRequests is the number of the requests to the API server.
+	APIRequests float64
+}
+
+// Result is the load of the controller manager measured between two samples.
+type Result struct {
+	// Clusters is the number of the ClusterDeployments reconciled.
+	Clusters int
+	// Duration is the time elapsed between the samples.
+	Duration time.Duration
+	// Reconciles is the number of the reconciles of the ClusterDeployments.
+	Reconciles uint64
+	// ReconcileLatencyP99 is the upper bound of the latency bucket of the
+	// 99th percentile of the reconciles.
+	ReconcileLatencyP99 time.Duration
+	// ReconcileLatencyAverage is the average latency of the reconciles.
+	ReconcileLatencyAverage time.Duration
+	// PeakMemoryBytes is the maximum resident memory sampled.
+	PeakMemoryBytes float64
+	// APIQPS is the average rate of the requests to the API server.
+	APIQPS float64
+}
+
+// Parse decodes the sample from the metrics in the Prometheus text format.
+func Parse(r io.Reader) (Sample, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return Sample{}, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+
+	sample := Sample{ReconcileBuckets: make(map[float64]uint64)}
+	if f, ok := families[metricReconcileTime]; ok {
+		for _, m := range f.GetMetric() {
+			if labelValue(m, "controller") != clusterDeploymentController {
+				continue
+			}
+			h := m.GetHistogram()
+			sample.Reconciles += h.GetSampleCount()
+			sample.ReconcileSeconds += h.GetSampleSum()
+			for _, b := range h.GetBucket() {
+				// the +Inf bucket is the number of the reconciles
+				if math.IsInf(b.GetUpperBound(), 1) {
+					continue
+				}
+				sample.ReconcileBuckets[b.GetUpperBound()] += b.GetCumulativeCount()
+			}
+		}
+	}
+	if f, ok := families[metricResidentMemory]; ok {
+		for _, m := range f.GetMetric() {
+			sample.ResidentMemoryBytes = max(sample.ResidentMemoryBytes, m.GetGauge().GetValue())
+		}
+	}
+	if f, ok := families[metricRESTClientCalls]; ok {
+		for _, m := range f.GetMetric() {
+			sample.APIRequests += m.GetCounter().GetValue()
+		}
+	}
+	return sample, nil
+}
+
+func labelValue(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
+
+// Merge returns the sample of several replicas of the controller manager, the
+// counters are summed and the maximum of the memory is taken.
+func (s Sample) Merge(other Sample) Sample {
+	merged := Sample{
+		ReconcileBuckets:    make(map[float64]uint64, len(s.ReconcileBuckets)),
+		Reconciles:          s.Reconciles + other.Reconciles,
+		ReconcileSeconds:    s.ReconcileSeconds + other.ReconcileSeconds,
+		ResidentMemoryBytes: max(s.ResidentMemoryBytes, other.ResidentMemoryBytes),
+		APIRequests:         s.APIRequests + other.APIRequests,
+	}
+	for bound, count := range s.ReconcileBuckets {
+		merged.ReconcileBuckets[bound] += count
+	}
+	for bound, count := range other.ReconcileBuckets {
+		merged.ReconcileBuckets[bound] += count
+	}
+	return merged
+}
+
+// Scrape returns the merged sample of the metrics of all of the pods matching
+// the selector in the namespace of the client.
+func Scrape(ctx context.Context, kc *kubeclient.KubeClient, selector string) (Sample, error) {
+	pods, err := kc.Client.CoreV1().Pods(kc.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return Sample{}, fmt.Errorf("failed to list pods %s in namespace %s: %w", selector, kc.Namespace, err)
+	}
+	if len(pods.Items) == 0 {
+		return Sample{}, fmt.Errorf("no pods %s found in namespace %s", selector, kc.Namespace)
+	}
+
+	var merged Sample
+	for _, pod := range pods.Items {
+		sample, err := scrapePod(ctx, kc, pod.Name)
+		if err != nil {
+			return Sample{}, err
+		}
+		merged = merged.Merge(sample)
+	}
+	return merged, nil
+}
+
+func scrapePod(ctx context.Context, kc *kubeclient.KubeClient, podName string) (Sample, error) {
+	port, stop, err := kc.PortForward(ctx, podName, MetricsPort)
+	if err != nil {
+		return Sample{}, err
+	}
+	defer stop()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://127.0.0.1:%d/metrics", port), nil)
+	if err != nil {
+		return Sample{}, fmt.Errorf("failed to create metrics request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Sample{}, fmt.Errorf("failed to get metrics of pod %s/%s: %w", kc.Namespace, podName, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Sample{}, fmt.Errorf("failed to get metrics of pod %s/%s: unexpected status %s", kc.Namespace, podName, resp.Status)
+	}
+
+	sample, err := Parse(resp.Body)
+	if err != nil {
+		return Sample{}, fmt.Errorf("pod %s/%s: %w", kc.Namespace, podName, err)
+	}
+	return sample, nil
+}
+
+// Compare returns the load measured between the samples taken the given time
+// apart, the peak memory is the maximum of the given memory samples and of
+// the last sample. The counters decreased between the samples mean the
+// controller manager has been restarted, which fails the measurement.
+func Compare(before, after Sample, elapsed time.Duration, clusters int, memorySamples ...float64) (Result, error) {
+	if after.Reconciles < before.Reconciles || after.APIRequests < before.APIRequests {
+		return Result{}, errors.New("the counters of the controller manager have been reset, it has been restarted during the measurement")
+	}
+
+	result := Result{
+		Clusters:        clusters,
+		Duration:        elapsed,
+		Reconciles:      after.Reconciles - before.Reconciles,
+		PeakMemoryBytes: slices.Max(append(memorySamples, after.ResidentMemoryBytes)),
+	}
+	if elapsed > 0 {
+		result.APIQPS = (after.APIRequests - before.APIRequests) / elapsed.Seconds()
+	}
+	if result.Reconciles == 0 {
+		return result, nil
+	}
+
+	result.ReconcileLatencyAverage = seconds((after.ReconcileSeconds - before.ReconcileSeconds) / float64(result.Reconciles))
+
+	bounds := make([]float64, 0, len(after.ReconcileBuckets))
+	for bound := range after.ReconcileBuckets {
+		bounds = append(bounds, bound)
+	}
+	slices.Sort(bounds)
+
+	// the latency of the 99th percentile is at most the upper bound of the
+	// first bucket containing it, the largest bound is taken for the
+	// reconciles slower than all of the buckets
+	target := uint64(math.Ceil(0.99 * float64(result.Reconciles)))
+	for _, bound := range bounds {
+		result.ReconcileLatencyP99 = seconds(bound)
+		if after.ReconcileBuckets[bound]-before.ReconcileBuckets[bound] >= target {
+			break
+		}
+	}
+	return result, nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Check returns the error listing the thresholds the load exceeds.
+func (r Result) Check(thresholds config.ScaleThresholds) error {
+	var errs error
+	if thresholds.ReconcileLatencyP99 > 0 && r.ReconcileLatencyP99 > thresholds.ReconcileLatencyP99 {
+		errs = errors.Join(errs, fmt.Errorf("the 99th percentile of the reconcile latency %s exceeds the threshold %s", r.ReconcileLatencyP99, thresholds.ReconcileLatencyP99))
+	}
+	if thresholds.MaxMemoryMiB > 0 && r.PeakMemoryBytes > float64(thresholds.MaxMemoryMiB)*mib {
+		errs = errors.Join(errs, fmt.Errorf("the peak memory %.0fMiB exceeds the threshold %dMiB", r.PeakMemoryBytes/mib, thresholds.MaxMemoryMiB))
+	}
+	if thresholds.MaxAPIQPS > 0 && r.APIQPS > thresholds.MaxAPIQPS {
+		errs = errors.Join(errs, fmt.Errorf("the API QPS %.2f exceeds the threshold %.2f", r.APIQPS, thresholds.MaxAPIQPS))
+	}
+	return errs
+}
+
+// String returns the summary of the load reported by the scale testing.
+func (r Result) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "clusters: %d\n", r.Clusters)
+	fmt.Fprintf(&b, "duration: %s\n", r.Duration.Round(time.Second))
+	fmt.Fprintf(&b, "reconciles: %d\n", r.Reconciles)
+	fmt.Fprintf(&b, "reconcile latency p99: %s\n", r.ReconcileLatencyP99)
+	fmt.Fprintf(&b, "reconcile latency average: %s\n", r.ReconcileLatencyAverage.Round(time.Millisecond))
+	fmt.Fprintf(&b, "peak memory: %.0fMiB\n", r.PeakMemoryBytes/mib)
+	fmt.Fprintf(&b, "API QPS: %.2f\n", r.APIQPS)
+	return b.String()
+}
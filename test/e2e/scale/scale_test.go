@@ -0,0 +1,174 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scale
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/K0rdent/kcm/test/e2e/config"
+)
+
+const testMetrics = `# HELP controller_runtime_reconcile_time_seconds Length of time per reconciliation per controller
+# TYPE controller_runtime_reconcile_time_seconds histogram
+controller_runtime_reconcile_time_seconds_bucket{controller="clusterdeployment",le="0.1"} 80
+controller_runtime_reconcile_time_seconds_bucket{controller="clusterdeployment",le="1"} 98
+controller_runtime_reconcile_time_seconds_bucket{controller="clusterdeployment",le="10"} 100
+controller_runtime_reconcile_time_seconds_bucket{controller="clusterdeployment",le="+Inf"} 100
+controller_runtime_reconcile_time_seconds_sum{controller="clusterdeployment"} 25
+controller_runtime_reconcile_time_seconds_count{controller="clusterdeployment"} 100
+controller_runtime_reconcile_time_seconds_bucket{controller="management",le="0.1"} 0
+controller_runtime_reconcile_time_seconds_bucket{controller="management",le="1"} 0
+controller_runtime_reconcile_time_seconds_bucket{controller="management",le="10"} 5
+controller_runtime_reconcile_time_seconds_bucket{controller="management",le="+Inf"} 5
+controller_runtime_reconcile_time_seconds_sum{controller="management"} 30
+controller_runtime_reconcile_time_seconds_count{controller="management"} 5
+# HELP process_resident_memory_bytes Resident memory size in bytes.
+# TYPE process_resident_memory_bytes gauge
+process_resident_memory_bytes 1.048576e+08
+# HELP rest_client_requests_total Number of HTTP requests, partitioned by status code, method, and host.
+# TYPE rest_client_requests_total counter
+rest_client_requests_total{code="200",host="10.96.0.1:443",method="GET"} 1000
+rest_client_requests_total{code="201",host="10.96.0.1:443",method="POST"} 200
+`
+
+func TestParse(t *testing.T) {
+	g := NewWithT(t)
+
+	sample, err := Parse(strings.NewReader(testMetrics))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sample).To(Equal(Sample{
+		ReconcileBuckets:    map[float64]uint64{0.1: 80, 1: 98, 10: 100},
+		Reconciles:          100,
+		ReconcileSeconds:    25,
+		ResidentMemoryBytes: 100 * mib,
+		APIRequests:         1200,
+	}))
+
+	_, err = Parse(strings.NewReader("invalid metrics{"))
+	g.Expect(err).To(MatchError(ContainSubstring("failed to parse metrics")))
+}
+
+func TestCompare(t *testing.T) {
+	before := Sample{
+		ReconcileBuckets:    map[float64]uint64{0.1: 10, 1: 10, 10: 10},
+		Reconciles:          10,
+		ReconcileSeconds:    1,
+		ResidentMemoryBytes: 50 * mib,
+		APIRequests:         200,
+	}
+	after := Sample{
+		ReconcileBuckets:    map[float64]uint64{0.1: 80, 1: 108, 10: 110},
+		Reconciles:          110,
+		ReconcileSeconds:    51,
+		ResidentMemoryBytes: 100 * mib,
+		APIRequests:         1200,
+	}
+
+	for _, tc := range []struct {
+		name          string
+		before, after Sample
+		memory        []float64
+		expected      Result
+		expectedErr   string
+	}{
+		{
+			name:   "load",
+			before: before,
+			after:  after,
+			memory: []float64{120 * mib, 80 * mib},
+			expected: Result{
+				Clusters:                10,
+				Duration:                100 * time.Second,
+				Reconciles:              100,
+				ReconcileLatencyP99:     10 * time.Second,
+				ReconcileLatencyAverage: 500 * time.Millisecond,
+				PeakMemoryBytes:         120 * mib,
+				APIQPS:                  10,
+			},
+		},
+		{
+			name:   "no reconciles",
+			before: after,
+			after:  after,
+			expected: Result{
+				Clusters:        10,
+				Duration:        100 * time.Second,
+				PeakMemoryBytes: 100 * mib,
+			},
+		},
+		{
+			name:        "restarted controller manager",
+			before:      after,
+			after:       before,
+			expectedErr: "restarted",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result, err := Compare(tc.before, tc.after, 100*time.Second, 10, tc.memory...)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestResultCheck(t *testing.T) {
+	result := Result{
+		ReconcileLatencyP99: 10 * time.Second,
+		PeakMemoryBytes:     600 * mib,
+		APIQPS:              20,
+	}
+
+	for _, tc := range []struct {
+		name         string
+		thresholds   config.ScaleThresholds
+		expectedErrs []string
+	}{
+		{
+			name: "no thresholds",
+		},
+		{
+			name:       "within thresholds",
+			thresholds: config.ScaleThresholds{ReconcileLatencyP99: 10 * time.Second, MaxMemoryMiB: 600, MaxAPIQPS: 20},
+		},
+		{
+			name:         "exceeded thresholds",
+			thresholds:   config.ScaleThresholds{ReconcileLatencyP99: 5 * time.Second, MaxMemoryMiB: 512, MaxAPIQPS: 10},
+			expectedErrs: []string{"reconcile latency 10s", "peak memory 600MiB", "API QPS 20.00"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := result.Check(tc.thresholds)
+			if len(tc.expectedErrs) == 0 {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			for _, expected := range tc.expectedErrs {
+				g.Expect(err).To(MatchError(ContainSubstring(expected)))
+			}
+		})
+	}
+}
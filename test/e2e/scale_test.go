@@ -0,0 +1,214 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	internalutils "github.com/K0rdent/kcm/internal/utils"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/flake"
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/scale"
+	"github.com/K0rdent/kcm/test/e2e/templates"
+	"github.com/K0rdent/kcm/test/utils"
+)
+
+// scaleMemoryPollInterval is the interval the memory of the controller
+// manager is sampled at during the scale testing.
+const scaleMemoryPollInterval = 15 * time.Second
+
+// The scale spec creates the configured number of the ClusterDeployments
+// concurrently and measures the load of the controller manager reconciling
+// them until all of them are ready. It is run serially, so the load of the
+// other specs is not measured, and only with the scale section of the testing
+// configuration set, e.g. by the scale profile. The latencies measured on the
+// shared runners vary from run to run, so the spec is retried as a known flake.
+var _ = Describe("Scale", Label("scale"), Serial, func() {
+	It("should reconcile the ClusterDeployments within the regression thresholds", flake.Retry(), func() {
+		if config.Config.Scale == nil {
+			Skip("the scale testing is not configured, see the scale profile of the testing configuration")
+		}
+		sc := config.Config.Scale
+		ctx := context.Background()
+		templateType := sc.TemplateType()
+
+		kc := specNamespace("scale")
+		mgmtClient := kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+		prepareScenarioProvider(kc, sc.Provider)
+
+		template := sc.Template
+		if template == "" {
+			clusterTemplates, err := templates.GetSortedClusterTemplates(ctx, kc.CrClient, kc.Namespace)
+			Expect(err).NotTo(HaveOccurred())
+			for _, t := range templates.FindLatestTemplatesWithType(clusterTemplates, templateType, 2) {
+				if !strings.HasSuffix(t, upgradeTemplateSuffix) {
+					template = t
+					break
+				}
+			}
+			Expect(template).NotTo(BeEmpty(), "no ClusterTemplate of the %s type was found", templateType)
+		}
+
+		// the objects are built one by one, since the names and the
+		// templates are substituted from the environment
+		clusterDeployments := make([]*unstructured.Unstructured, sc.Clusters)
+		for i := range clusterDeployments {
+			clusterDeployments[i] = clusterdeployment.GetUnstructured(templateType, clusterdeployment.GenerateClusterName(fmt.Sprintf("scale-%d", i)), template)
+			clusterDeployments[i].SetNamespace(kc.Namespace)
+		}
+
+		DeferCleanup(func() {
+			if !cleanup() {
+				return
+			}
+
+			By(fmt.Sprintf("deleting the %d ClusterDeployments", len(clusterDeployments)))
+			for _, cd := range clusterDeployments {
+				Expect(crclient.IgnoreNotFound(kc.CrClient.Delete(ctx, cd))).To(Succeed())
+			}
+			Eventually(func() error {
+				var remaining int
+				for _, cd := range clusterDeployments {
+					if err := kc.CrClient.Get(ctx, crclient.ObjectKeyFromObject(cd), new(kcmv1.ClusterDeployment)); !apierrors.IsNotFound(err) {
+						remaining++
+					}
+				}
+				if remaining > 0 {
+					return fmt.Errorf("%d of %d ClusterDeployments have not been deleted yet", remaining, len(clusterDeployments))
+				}
+				return nil
+			}).WithTimeout(sc.Timeouts.Deletion).WithPolling(sc.Timeouts.Polling).Should(Succeed())
+		})
+
+		before, err := scale.Scrape(ctx, mgmtClient, utils.KCMControllerLabel)
+		Expect(err).NotTo(HaveOccurred())
+		start := time.Now()
+
+		samplerCtx, stopSampler := context.WithCancel(ctx)
+		// stops the sampler if the spec fails before the clusters are ready
+		DeferCleanup(stopSampler)
+		memorySamples, samplerDone := sampleMemory(samplerCtx, mgmtClient)
+
+		By(fmt.Sprintf("creating %d ClusterDeployments with template %s, %d at once", sc.Clusters, template, sc.Concurrency))
+		Expect(createConcurrently(ctx, kc.CrClient, clusterDeployments, sc.Concurrency)).To(Succeed())
+
+		By(fmt.Sprintf("waiting for the %d ClusterDeployments to be ready", sc.Clusters))
+		Eventually(func() error {
+			var ready int
+			for _, cd := range clusterDeployments {
+				current := new(kcmv1.ClusterDeployment)
+				if err := kc.CrClient.Get(ctx, crclient.ObjectKeyFromObject(cd), current); err != nil {
+					return err
+				}
+				if apimeta.IsStatusConditionTrue(current.Status.Conditions, kcmv1.ReadyCondition) {
+					ready++
+				}
+			}
+			if ready < len(clusterDeployments) {
+				return fmt.Errorf("%d of %d ClusterDeployments are ready", ready, len(clusterDeployments))
+			}
+			return nil
+		}).WithTimeout(sc.Timeouts.Deployment).WithPolling(sc.Timeouts.Polling).Should(Succeed())
+
+		elapsed := time.Since(start)
+		stopSampler()
+		<-samplerDone
+
+		after, err := scale.Scrape(ctx, mgmtClient, utils.KCMControllerLabel)
+		Expect(err).NotTo(HaveOccurred())
+		result, err := scale.Compare(before, after, elapsed, sc.Clusters, *memorySamples...)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, _ = fmt.Fprintf(GinkgoWriter, "Load of the controller manager:\n%s", result)
+		AddReportEntry("scale", result.String())
+
+		By("validating the load of the controller manager against the regression thresholds")
+		Expect(result.Check(sc.Thresholds)).To(Succeed())
+	})
+})
+
+// createConcurrently creates the objects with at most the given number of
+// them created at once and returns the errors of all of the failed ones.
+func createConcurrently(ctx context.Context, cl crclient.Client, objs []*unstructured.Unstructured, concurrency int) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs error
+	)
+
+	sem := make(chan struct{}, concurrency)
+	for _, obj := range objs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := cl.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+				mu.Lock()
+				errs = errors.Join(errs, fmt.Errorf("failed to create %s %s: %w", obj.GetKind(), crclient.ObjectKeyFromObject(obj), err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// sampleMemory samples the resident memory of the controller manager until
+// the context is done. The samples are safe to read once the returned channel
+// is closed.
+func sampleMemory(ctx context.Context, mgmtClient *kubeclient.KubeClient) (*[]float64, <-chan struct{}) {
+	samples := new([]float64)
+	done := make(chan struct{})
+
+	go func() {
+		defer GinkgoRecover()
+		defer close(done)
+
+		ticker := time.NewTicker(scaleMemoryPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sample, err := scale.Scrape(ctx, mgmtClient, utils.KCMControllerLabel)
+				if err != nil {
+					// the missed samples only make the peak less accurate
+					_, _ = fmt.Fprintf(GinkgoWriter, "Failed to sample the memory of the controller manager: %v\n", err)
+					continue
+				}
+				*samples = append(*samples, sample.ResidentMemoryBytes)
+			}
+		}
+	}()
+	return samples, done
+}
@@ -0,0 +1,329 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scenario defines the declarative e2e scenarios. Each of them
+// deploys the cluster of the template with the overrides of its config and
+// the services, runs the checks and upgrades the cluster, so the combinations
+// of the providers and templates are added as data instead of the Go code.
+package scenario
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/templates"
+)
+
+// EnvVarScenariosDir is the environment variable with the directory the
+// scenarios are read from instead of the embedded ones.
+const EnvVarScenariosDir = "E2E_SCENARIOS_DIR"
+
+// LabelScenario is the label of the specs run from the scenarios.
+const LabelScenario = "scenario"
+
+// Check is the check run by the scenario.
+type Check string
+
+const (
+	// CheckDeployment validates the resources of the deployed cluster.
+	CheckDeployment Check = "deployment"
+	// CheckServices validates the resources of the services deployed on
+	// the cluster.
+	CheckServices Check = "services"
+	// CheckDeletion validates the resources of the cluster are removed once
+	// it is deleted.
+	CheckDeletion Check = "deletion"
+	// CheckRollback rolls the upgraded cluster back to its first revision
+	// and validates it is deployed again from the initial template.
+	CheckRollback Check = "rollback"
+)
+
+// Checks are all of the checks.
+var Checks = []Check{CheckDeployment, CheckServices, CheckDeletion, CheckRollback}
+
+// defaultChecks are the checks run if the scenario sets none.
+var defaultChecks = []Check{CheckDeployment, CheckServices, CheckDeletion}
+
+// ResourceKind is the kind of the resource of the service validated on the
+// cluster.
+type ResourceKind string
+
+const (
+	ResourceKindService    ResourceKind = "service"
+	ResourceKindDeployment ResourceKind = "deployment"
+)
+
+// providerTemplateTypes are the types of the templates of the clusters the
+// scenarios of the providers can deploy on the management cluster.
+var providerTemplateTypes = map[config.TestingProvider][]templates.Type{
+	config.TestingProviderAWS:      {templates.TemplateAWSStandaloneCP, templates.TemplateAWSEKS},
+	config.TestingProviderAzure:    {templates.TemplateAzureStandaloneCP, templates.TemplateAzureAKS},
+	config.TestingProviderVsphere:  {templates.TemplateVSphereStandaloneCP},
+	config.TestingProviderDocker:   {templates.TemplateDockerHostedCP},
+	config.TestingProviderInMemory: {templates.TemplateInMemoryStandaloneCP},
+}
+
+// Resource is the resource of the service validated on the cluster.
+type Resource struct {
+	// Kind of the resource.
+	Kind ResourceKind `yaml:"kind"`
+	// NameSuffix is appended to the name of the service to get the name of
+	// the resource, the name of the service is used if empty.
+	NameSuffix string `yaml:"nameSuffix,omitempty"`
+}
+
+// Service is the service deployed on the cluster.
+type Service struct {
+	// Name of the service.
+	Name string `yaml:"name"`
+	// Template is the ServiceTemplate of the service.
+	Template string `yaml:"template"`
+	// Namespace the service is deployed to.
+	Namespace string `yaml:"namespace"`
+	// Resources are the resources of the service validated on the cluster.
+	Resources []Resource `yaml:"resources,omitempty"`
+}
+
+// Scenario is the declarative e2e case.
+type Scenario struct {
+	// ClusterTestingConfig is the template, the upgrade target, the
+	// architecture and the timeouts of the cluster.
+	config.ClusterTestingConfig `yaml:",inline"`
+	// Config is merged into the config of the ClusterDeployment.
+	Config map[string]any `yaml:"config,omitempty"`
+	// Name of the scenario, the name of the cluster is generated from it.
+	Name string `yaml:"name"`
+	// Provider is the provider the credentials of which are used.
+	Provider config.TestingProvider `yaml:"provider"`
+	// TemplateType is the type of the template, required if the template is
+	// not set, then the latest template of the type is used.
+	TemplateType templates.Type `yaml:"templateType,omitempty"`
+	// Services replace the services of the ClusterDeployment if set.
+	Services []Service `yaml:"services,omitempty"`
+	// Checks are the checks run, all of them but the rollback are run if
+	// empty.
+	Checks []Check `yaml:"checks,omitempty"`
+}
+
+var (
+	//go:embed scenarios/*.yaml
+	scenariosFS embed.FS
+
+	// Scenarios are the parsed scenarios sorted by their names.
+	Scenarios []Scenario
+
+	parseOnce sync.Once
+	errParse  error
+)
+
+// Parse parses the embedded scenarios or the ones of the scenarios directory.
+// It is called before the specs are built, so a spec is generated per
+// scenario.
+func Parse() error {
+	parseOnce.Do(func() {
+		fsys, err := fs.Sub(scenariosFS, "scenarios")
+		if err != nil {
+			errParse = err
+			return
+		}
+		if dir := os.Getenv(EnvVarScenariosDir); dir != "" {
+			fsys = os.DirFS(dir)
+		}
+		Scenarios, errParse = parseAll(fsys)
+	})
+	return errParse
+}
+
+func parseAll(fsys fs.FS) ([]Scenario, error) {
+	files, err := fs.Glob(fsys, "*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scenarios: %w", err)
+	}
+
+	scenarios := make([]Scenario, 0, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scenario %s: %w", file, err)
+		}
+		s, err := parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scenario %s: %w", filepath.Base(file), err)
+		}
+		if slices.ContainsFunc(scenarios, func(other Scenario) bool { return other.Name == s.Name }) {
+			return nil, fmt.Errorf("invalid scenario %s: the name %s is not unique", filepath.Base(file), s.Name)
+		}
+		scenarios = append(scenarios, s)
+	}
+
+	slices.SortFunc(scenarios, func(a, b Scenario) int { return strings.Compare(a.Name, b.Name) })
+	return scenarios, nil
+}
+
+func parse(data []byte) (Scenario, error) {
+	var s Scenario
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return s, fmt.Errorf("failed to decode: %w", err)
+	}
+	return s, s.validate()
+}
+
+func (s *Scenario) validate() error {
+	var errs error
+	if s.Name == "" {
+		errs = errors.Join(errs, errors.New("the name must be set"))
+	}
+
+	types, ok := providerTemplateTypes[s.Provider]
+	if !ok {
+		errs = errors.Join(errs, fmt.Errorf("the %q provider is not supported", s.Provider))
+	}
+	switch {
+	case s.Type() == "":
+		errs = errors.Join(errs, errors.New("either the template or the template type must be set"))
+	case s.Template != "" && s.TemplateType != "" && templates.GetType(s.Template) != s.TemplateType:
+		errs = errors.Join(errs, fmt.Errorf("the template %s is not of the %s type", s.Template, s.TemplateType))
+	case ok && !slices.Contains(types, s.Type()):
+		errs = errors.Join(errs, fmt.Errorf("the %s templates are not supported by the %s provider", s.Type(), s.Provider))
+	}
+	if s.UpgradeTemplate != "" && !s.Upgrade {
+		errs = errors.Join(errs, errors.New("the upgrade template is set without the upgrade"))
+	}
+	if slices.Contains(s.Checks, CheckRollback) && !s.Upgrade {
+		errs = errors.Join(errs, errors.New("the rollback is checked without the upgrade"))
+	}
+
+	for _, c := range s.Checks {
+		if !slices.Contains(Checks, c) {
+			errs = errors.Join(errs, fmt.Errorf("unknown check %q", c))
+		}
+	}
+	for _, svc := range s.Services {
+		if svc.Name == "" || svc.Template == "" || svc.Namespace == "" {
+			errs = errors.Join(errs, fmt.Errorf("the name, template and namespace of the service %q must be set", svc.Name))
+		}
+		for _, r := range svc.Resources {
+			if r.Kind != ResourceKindService && r.Kind != ResourceKindDeployment {
+				errs = errors.Join(errs, fmt.Errorf("unknown kind %q of the resource of the service %q", r.Kind, svc.Name))
+			}
+		}
+	}
+	return errs
+}
+
+// Type returns the type of the template of the scenario.
+func (s Scenario) Type() templates.Type {
+	if s.TemplateType != "" {
+		return s.TemplateType
+	}
+	return templates.GetType(s.Template)
+}
+
+// HasCheck reports whether the check is run by the scenario.
+func (s Scenario) HasCheck(c Check) bool {
+	if len(s.Checks) == 0 {
+		return slices.Contains(defaultChecks, c)
+	}
+	return slices.Contains(s.Checks, c)
+}
+
+// providerGroupLabels are the labels of the groups of the providers the
+// scenarios are run along with by the CI jobs.
+var providerGroupLabels = map[config.TestingProvider]string{
+	config.TestingProviderAWS:      "provider:cloud",
+	config.TestingProviderAzure:    "provider:cloud",
+	config.TestingProviderVsphere:  "provider:onprem",
+	config.TestingProviderDocker:   "provider:local",
+	config.TestingProviderInMemory: "provider:local",
+}
+
+// Labels returns the labels of the spec running the scenario.
+func (s Scenario) Labels() []string {
+	labels := []string{LabelScenario, LabelScenario + ":" + s.Name, providerGroupLabels[s.Provider], "provider:" + string(s.Provider)}
+	if templates.IsHosted(s.Type()) {
+		labels = append(labels, config.LabelTypeHosted)
+	} else {
+		labels = append(labels, config.LabelTypeStandalone)
+	}
+	if s.Upgrade {
+		labels = append(labels, config.LabelUpgrade)
+	}
+	return labels
+}
+
+// Apply merges the config of the scenario into the config of the
+// ClusterDeployment and replaces its services with the ones of the scenario.
+// The object is modified in place, since it is decoded from YAML and may hold
+// the values not supported by the deep copy of the unstructured helpers.
+func (s Scenario) Apply(cd *unstructured.Unstructured) {
+	spec := nestedMap(cd.Object, "spec")
+	if len(s.Config) > 0 {
+		spec["config"] = merge(nestedMap(spec, "config"), s.Config)
+	}
+
+	if len(s.Services) > 0 {
+		services := make([]any, 0, len(s.Services))
+		for _, svc := range s.Services {
+			services = append(services, map[string]any{
+				"name":      svc.Name,
+				"template":  svc.Template,
+				"namespace": svc.Namespace,
+			})
+		}
+		nestedMap(spec, "serviceSpec")["services"] = services
+	}
+}
+
+// nestedMap returns the map of the field of the object, creating it if
+// missing.
+func nestedMap(obj map[string]any, field string) map[string]any {
+	m, ok := obj[field].(map[string]any)
+	if !ok {
+		m = make(map[string]any)
+		obj[field] = m
+	}
+	return m
+}
+
+// merge returns the dst map with the src one deeply merged into it, the
+// values of src take precedence unless both values are maps.
+func merge(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+	for k, v := range src {
+		srcMap, srcOk := v.(map[string]any)
+		dstMap, dstOk := dst[k].(map[string]any)
+		if srcOk && dstOk {
+			dst[k] = merge(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+	return dst
+}
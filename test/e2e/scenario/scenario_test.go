@@ -0,0 +1,201 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scenario
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/templates"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		data        string
+		expected    Scenario
+		expectedErr string
+	}{
+		{
+			name: "full",
+			data: `name: aws-arm
+provider: aws
+template: aws-standalone-cp-0-1-0
+upgrade: true
+upgradeTemplate: aws-standalone-cp-0-1-1
+architecture: arm64
+timeouts:
+  deployment: 1h
+config:
+  worker:
+    instanceType: t4g.large
+services:
+  - name: ingress
+    template: ingress-nginx-4-11-0
+    namespace: default
+    resources:
+      - kind: deployment
+        nameSuffix: controller
+checks: [deployment, deletion]
+`,
+			expected: Scenario{
+				ClusterTestingConfig: config.ClusterTestingConfig{
+					Template:        "aws-standalone-cp-0-1-0",
+					Upgrade:         true,
+					UpgradeTemplate: "aws-standalone-cp-0-1-1",
+					Architecture:    config.ArchitectureARM64,
+					Timeouts:        config.Timeouts{Deployment: time.Hour},
+				},
+				Name:     "aws-arm",
+				Provider: config.TestingProviderAWS,
+				Config:   map[string]any{"worker": map[string]any{"instanceType": "t4g.large"}},
+				Services: []Service{{
+					Name:      "ingress",
+					Template:  "ingress-nginx-4-11-0",
+					Namespace: "default",
+					Resources: []Resource{{Kind: ResourceKindDeployment, NameSuffix: "controller"}},
+				}},
+				Checks: []Check{CheckDeployment, CheckDeletion},
+			},
+		},
+		{
+			name:     "template type",
+			data:     "name: docker\nprovider: docker\ntemplateType: docker-hosted-cp\n",
+			expected: Scenario{Name: "docker", Provider: config.TestingProviderDocker, TemplateType: templates.TemplateDockerHostedCP},
+		},
+		{
+			name:        "unknown field",
+			data:        "name: docker\nprovider: docker\ntemplateType: docker-hosted-cp\nworkers: 2\n",
+			expectedErr: "field workers not found",
+		},
+		{
+			name:        "missing template",
+			data:        "name: docker\nprovider: docker\n",
+			expectedErr: "either the template or the template type must be set",
+		},
+		{
+			name:        "unsupported template type",
+			data:        "name: aws\nprovider: aws\ntemplateType: aws-hosted-cp\n",
+			expectedErr: "the aws-hosted-cp templates are not supported by the aws provider",
+		},
+		{
+			name:        "mismatched template type",
+			data:        "name: aws\nprovider: aws\ntemplate: aws-eks-0-1-0\ntemplateType: aws-standalone-cp\n",
+			expectedErr: "the template aws-eks-0-1-0 is not of the aws-standalone-cp type",
+		},
+		{
+			name:        "unknown check",
+			data:        "name: docker\nprovider: docker\ntemplateType: docker-hosted-cp\nchecks: [conformance]\n",
+			expectedErr: `unknown check "conformance"`,
+		},
+		{
+			name:        "rollback without upgrade",
+			data:        "name: in-memory\nprovider: in-memory\ntemplateType: in-memory-standalone-cp\nchecks: [rollback]\n",
+			expectedErr: "the rollback is checked without the upgrade",
+		},
+		{
+			name:        "invalid service",
+			data:        "name: docker\nprovider: docker\ntemplateType: docker-hosted-cp\nservices:\n- name: ingress\n  resources:\n  - kind: pod\n",
+			expectedErr: `unknown kind "pod" of the resource of the service "ingress"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			s, err := parse([]byte(tc.data))
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestParseEmbedded(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Parse()).To(Succeed())
+	g.Expect(Scenarios).NotTo(BeEmpty())
+}
+
+func TestHasCheck(t *testing.T) {
+	g := NewWithT(t)
+
+	s := Scenario{}
+	g.Expect(s.HasCheck(CheckDeployment)).To(BeTrue())
+	g.Expect(s.HasCheck(CheckRollback)).To(BeFalse())
+
+	s.Checks = []Check{CheckRollback}
+	g.Expect(s.HasCheck(CheckDeployment)).To(BeFalse())
+	g.Expect(s.HasCheck(CheckRollback)).To(BeTrue())
+}
+
+func TestApply(t *testing.T) {
+	g := NewWithT(t)
+
+	cd := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"config": map[string]any{
+				"workersNumber": 1,
+				"k0smotron":     map[string]any{"service": map[string]any{"type": "NodePort"}},
+			},
+			"serviceSpec": map[string]any{
+				"services": []any{map[string]any{"name": "default-ingress"}},
+			},
+		},
+	}}
+
+	Scenario{
+		Config: map[string]any{
+			"workersNumber": 2,
+			"k0smotron":     map[string]any{"service": map[string]any{"apiPort": 30443}},
+		},
+		Services: []Service{{Name: "ingress", Template: "ingress-nginx-4-11-0", Namespace: "default"}},
+	}.Apply(cd)
+
+	g.Expect(cd.Object).To(Equal(map[string]any{
+		"spec": map[string]any{
+			"config": map[string]any{
+				"workersNumber": 2,
+				"k0smotron":     map[string]any{"service": map[string]any{"type": "NodePort", "apiPort": 30443}},
+			},
+			"serviceSpec": map[string]any{
+				"services": []any{map[string]any{"name": "ingress", "template": "ingress-nginx-4-11-0", "namespace": "default"}},
+			},
+		},
+	}))
+}
+
+func TestLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Scenario{
+		Name:         "docker",
+		Provider:     config.TestingProviderDocker,
+		TemplateType: templates.TemplateDockerHostedCP,
+	}.Labels()).To(Equal([]string{"scenario", "scenario:docker", "provider:local", "provider:docker", config.LabelTypeHosted}))
+
+	g.Expect(Scenario{
+		ClusterTestingConfig: config.ClusterTestingConfig{Template: "aws-standalone-cp-0-1-0", Upgrade: true},
+		Name:                 "aws",
+		Provider:             config.TestingProviderAWS,
+	}.Labels()).To(Equal([]string{"scenario", "scenario:aws", "provider:cloud", "provider:aws", config.LabelTypeStandalone, config.LabelUpgrade}))
+}
@@ -0,0 +1,249 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	internalutils "github.com/K0rdent/kcm/internal/utils"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment/clusteridentity"
+	"github.com/K0rdent/kcm/test/e2e/clusterdeployment/vsphere"
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/logs"
+	"github.com/K0rdent/kcm/test/e2e/scenario"
+	"github.com/K0rdent/kcm/test/e2e/templates"
+	"github.com/K0rdent/kcm/test/e2e/wait"
+	"github.com/K0rdent/kcm/test/utils"
+)
+
+// The specs of the scenarios are generated from the declarative scenarios, a
+// spec per scenario, so the new combinations of the templates, their config
+// and the services are tested without new Go code.
+var _ = Describe("Scenarios", func() {
+	for _, s := range scenario.Scenarios {
+		It(fmt.Sprintf("should pass the %s scenario", s.Name), Label(s.Labels()...), func() {
+			DeferCleanup(startChaos())
+
+			Expect(s.ApplyDefaults(s.Provider, s.Type())).To(Succeed())
+			templateType := s.Type()
+
+			kc := specNamespace("scenario-" + s.Name)
+			prepareScenarioProvider(kc, s.Provider)
+
+			clusterTemplates, err := templates.GetSortedClusterTemplates(context.Background(), kc.CrClient, kc.Namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.SetTemplates(clusterTemplates, templateType)).To(Succeed())
+
+			clusterName := clusterdeployment.GenerateClusterName("scenario-" + s.Name)
+			templateBy(templateType, fmt.Sprintf("creating a ClusterDeployment %s with template %s for the %s scenario", clusterName, s.Template, s.Name))
+			cd := clusterdeployment.GetUnstructured(templateType, clusterName, s.Template)
+			s.Apply(cd)
+			deleteFunc := kc.CreateClusterDeployment(context.Background(), cd, s.Timeouts)
+
+			DeferCleanup(func() {
+				if !cleanup() {
+					return
+				}
+				if CurrentSpecReport().Failed() {
+					By(fmt.Sprintf("collecting the support bundle from the %s cluster", clusterName))
+					logs.SupportBundle(kc, clusterName)
+				}
+
+				templateBy(templateType, fmt.Sprintf("deleting the %s ClusterDeployment", clusterName))
+				Expect(deleteFunc()).To(Succeed())
+				if !s.HasCheck(scenario.CheckDeletion) {
+					return
+				}
+
+				deletionValidator := clusterdeployment.NewProviderValidator(templateType, clusterName, clusterdeployment.ValidationActionDelete)
+				Eventually(func() error {
+					return deletionValidator.Validate(context.Background(), kc)
+				}).WithTimeout(s.Timeouts.Deletion).WithPolling(s.Timeouts.Polling).Should(Succeed())
+			})
+
+			deploymentValidator := clusterdeployment.NewProviderValidator(templateType, clusterName, clusterdeployment.ValidationActionDeploy)
+			if s.HasCheck(scenario.CheckDeployment) {
+				templateBy(templateType, "waiting for infrastructure to deploy successfully")
+				Eventually(func() error {
+					return deploymentValidator.Validate(context.Background(), kc)
+				}).WithTimeout(s.Timeouts.Deployment).WithPolling(s.Timeouts.Polling).Should(Succeed())
+			}
+
+			if s.HasCheck(scenario.CheckServices) {
+				for _, svc := range s.Services {
+					templateBy(templateType, fmt.Sprintf("validating the %s service is deployed", svc.Name))
+					serviceValidator := clusterdeployment.NewServiceValidator(clusterName, svc.Name, svc.Namespace)
+					for _, r := range svc.Resources {
+						validationFunc := clusterdeployment.ValidateService
+						if r.Kind == scenario.ResourceKindDeployment {
+							validationFunc = clusterdeployment.ValidateDeployment
+						}
+						serviceValidator.WithResourceValidation(fmt.Sprintf("%s/%s", r.Kind, r.NameSuffix), clusterdeployment.ManagedServiceResource{
+							ResourceNameSuffix: r.NameSuffix,
+							ValidationFunc:     validationFunc,
+						})
+					}
+					Eventually(func() error {
+						return serviceValidator.Validate(context.Background(), kc)
+					}).WithTimeout(10 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+				}
+			}
+
+			if s.Upgrade {
+				clusterClient := kc.NewFromCluster(context.Background(), internalutils.DefaultSystemNamespace, clusterName)
+				upgradeCluster(kc, clusterClient, clusterName, config.ProviderTestingConfig{ClusterTestingConfig: s.ClusterTestingConfig})
+
+				if s.HasCheck(scenario.CheckDeployment) {
+					Eventually(func() error {
+						return deploymentValidator.Validate(context.Background(), kc)
+					}).WithTimeout(s.Timeouts.Upgrade).WithPolling(s.Timeouts.Polling).Should(Succeed())
+				}
+
+				if s.HasCheck(scenario.CheckRollback) {
+					templateBy(templateType, fmt.Sprintf("rolling the %s cluster back to the %s template", clusterName, s.Template))
+					rollbackCluster(kc, clusterName, s.Template)
+					Eventually(func() error {
+						return deploymentValidator.Validate(context.Background(), kc)
+					}).WithTimeout(s.Timeouts.Upgrade).WithPolling(s.Timeouts.Polling).Should(Succeed())
+				}
+			}
+		})
+	}
+})
+
+// prepareScenarioProvider provides the credentials of the provider the
+// scenarios deploy the clusters with.
+func prepareScenarioProvider(kc *kubeclient.KubeClient, provider config.TestingProvider) {
+	GinkgoHelper()
+
+	var (
+		providerType   clusterdeployment.ProviderType
+		identityEnvVar string
+	)
+	switch provider {
+	case config.TestingProviderInMemory:
+		By("providing the stub credential")
+		_, err := utils.Run(exec.Command("make", "dev-in-memory-creds", "NAMESPACE="+kc.Namespace))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("validating that the In-Memory provider controller is ready")
+		mgmtClient := kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+		timeout := config.Config.Timeouts.ControllersReady
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		wait.DeploymentsAvailable(ctx, mgmtClient.CrClient, mgmtClient.Namespace, clusterdeployment.GetProviderLabel(clusterdeployment.ProviderInMemory), 1, timeout, controllerManagerName)
+
+		ensureUpgradeTemplate(kc, templates.TemplateInMemoryStandaloneCP)
+		return
+	case config.TestingProviderDocker:
+		By("providing the stub credential")
+		_, err := utils.Run(exec.Command("make", "dev-docker-creds", "NAMESPACE="+kc.Namespace))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("validating that the Docker provider controller is ready")
+		mgmtClient := kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+		timeout := config.Config.Timeouts.ControllersReady
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		wait.DeploymentsAvailable(ctx, mgmtClient.CrClient, mgmtClient.Namespace, clusterdeployment.GetProviderLabel(clusterdeployment.ProviderDocker), 1, timeout, controllerManagerName)
+		return
+	case config.TestingProviderAWS:
+		providerType, identityEnvVar = clusterdeployment.ProviderAWS, clusterdeployment.EnvVarAWSClusterIdentity
+	case config.TestingProviderAzure:
+		providerType, identityEnvVar = clusterdeployment.ProviderAzure, clusterdeployment.EnvVarAzureClusterIdentity
+	case config.TestingProviderVsphere:
+		vsphere.CheckEnv()
+		providerType, identityEnvVar = clusterdeployment.ProviderVSphere, clusterdeployment.EnvVarVSphereClusterIdentity
+	default:
+		Fail(fmt.Sprintf("the %s provider is not supported by the scenarios", provider))
+	}
+
+	By("providing cluster identity")
+	ci := clusteridentity.New(kc, providerType)
+	ci.WaitForValidCredential(kc)
+	GinkgoT().Setenv(identityEnvVar, ci.IdentityName)
+}
+
+// ensureUpgradeTemplate creates the copy of the latest ClusterTemplate of
+// the type, the upgrade target of the scenarios of the providers with a
+// single template released, so the upgrades and rollbacks of the
+// ClusterDeployments are tested with them anyway.
+func ensureUpgradeTemplate(kc *kubeclient.KubeClient, templateType templates.Type) {
+	GinkgoHelper()
+
+	clusterTemplates, err := templates.GetSortedClusterTemplates(context.Background(), kc.CrClient, kc.Namespace)
+	Expect(err).NotTo(HaveOccurred())
+	latest := templates.FindLatestTemplatesWithType(clusterTemplates, templateType, 1)
+	Expect(latest).NotTo(BeEmpty(), "no ClusterTemplate of the %s type was found", templateType)
+	if strings.HasSuffix(latest[0], upgradeTemplateSuffix) {
+		return
+	}
+
+	template := new(kcmv1.ClusterTemplate)
+	Expect(kc.CrClient.Get(context.Background(), crclient.ObjectKey{Namespace: kc.Namespace, Name: latest[0]}, template)).To(Succeed())
+
+	By(fmt.Sprintf("creating the %s%s ClusterTemplate to upgrade the clusters to", latest[0], upgradeTemplateSuffix))
+	upgradeTemplate := &kcmv1.ClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: kc.Namespace, Name: latest[0] + upgradeTemplateSuffix},
+		Spec:       *template.Spec.DeepCopy(),
+	}
+	Expect(crclient.IgnoreAlreadyExists(kc.CrClient.Create(context.Background(), upgradeTemplate))).To(Succeed())
+}
+
+// upgradeTemplateSuffix is the suffix of the name of the copy of the
+// ClusterTemplate created as the upgrade target, it is sorted after the name
+// of the copied one, so the copy is the latest template of the type.
+const upgradeTemplateSuffix = "-e2e-upgrade"
+
+// rollbackCluster requests the rollback of the ClusterDeployment to its first
+// revision and waits for the controller to apply the template of the revision.
+func rollbackCluster(kc *kubeclient.KubeClient, name, template string) {
+	GinkgoHelper()
+
+	cd := new(unstructured.Unstructured)
+	cd.SetGroupVersionKind(kcmv1.GroupVersion.WithKind(kcmv1.ClusterDeploymentKind))
+	cd.SetNamespace(kc.Namespace)
+	cd.SetName(name)
+	cd.SetAnnotations(map[string]string{kcmv1.ClusterDeploymentRollbackAnnotation: "1"})
+	Expect(kubeclient.Apply(context.Background(), kc.CrClient, cd, kubeclient.WithFieldManager("kcm-e2e-rollback"))).To(Succeed())
+
+	Eventually(func() error {
+		current := new(kcmv1.ClusterDeployment)
+		if err := kc.CrClient.Get(context.Background(), crclient.ObjectKey{Namespace: kc.Namespace, Name: name}, current); err != nil {
+			return err
+		}
+		if _, ok := current.Annotations[kcmv1.ClusterDeploymentRollbackAnnotation]; ok {
+			return errors.New("the rollback has not been handled yet")
+		}
+		if current.Spec.Template != template {
+			return fmt.Errorf("the template is %s, expected %s after the rollback", current.Spec.Template, template)
+		}
+		return nil
+	}).WithTimeout(time.Minute).WithPolling(5 * time.Second).Should(Succeed())
+}
@@ -0,0 +1,151 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sweeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// awsClusterTagPrefix is the prefix of the tag CAPA tags the resources of the
+// cluster with, the name of the cluster follows it.
+const awsClusterTagPrefix = "sigs.k8s.io/cluster-api-provider-aws/cluster/"
+
+// AWS sweeps the clusters with the AWS CLI, the clusters are found by the
+// tags of their instances and VPCs and deleted with the dev-aws-nuke target.
+type AWS struct {
+	run runFunc
+	now func() time.Time
+	cli string
+}
+
+// NewAWS returns the AWS provider using the given AWS CLI binary.
+func NewAWS(cli string) *AWS {
+	return &AWS{cli: cli, run: run, now: time.Now}
+}
+
+func (*AWS) Name() string { return "aws" }
+
+type awsTag struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+func awsTags(tags []awsTag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		m[t.Key] = t.Value
+	}
+	return m
+}
+
+func awsClusterName(tags map[string]string) string {
+	for k := range tags {
+		if name, ok := strings.CutPrefix(k, awsClusterTagPrefix); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// List returns the clusters, each is as old as its oldest instance or VPC.
+// The VPCs carry no creation time, so they are tagged with the first seen
+// tag once found.
+func (a *AWS) List(ctx context.Context) ([]Resource, error) {
+	filter := "Name=tag-key,Values=" + awsClusterTagPrefix + "*"
+
+	out, err := a.run(ctx, a.cli, "ec2", "describe-instances", "--filters", filter, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var instances struct {
+		Reservations []struct {
+			Instances []struct {
+				LaunchTime time.Time `json:"LaunchTime"`
+				State      struct {
+					Name string `json:"Name"`
+				} `json:"State"`
+				Tags []awsTag `json:"Tags"`
+			} `json:"Instances"`
+		} `json:"Reservations"`
+	}
+	if err := json.Unmarshal(out, &instances); err != nil {
+		return nil, fmt.Errorf("failed to parse instances: %w", err)
+	}
+
+	created := make(map[string]time.Time)
+	observe := func(cluster string, t time.Time) {
+		if cluster == "" {
+			return
+		}
+		if c, ok := created[cluster]; !ok || t.Before(c) {
+			created[cluster] = t
+		}
+	}
+
+	for _, r := range instances.Reservations {
+		for _, i := range r.Instances {
+			if i.State.Name == "terminated" {
+				continue
+			}
+			observe(awsClusterName(awsTags(i.Tags)), i.LaunchTime)
+		}
+	}
+
+	out, err = a.run(ctx, a.cli, "ec2", "describe-vpcs", "--filters", filter, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var vpcs struct {
+		Vpcs []struct {
+			VpcID string   `json:"VpcId"`
+			Tags  []awsTag `json:"Tags"`
+		} `json:"Vpcs"`
+	}
+	if err := json.Unmarshal(out, &vpcs); err != nil {
+		return nil, fmt.Errorf("failed to parse VPCs: %w", err)
+	}
+
+	for _, vpc := range vpcs.Vpcs {
+		tags := awsTags(vpc.Tags)
+		seen, ok := firstSeen(tags)
+		if !ok {
+			seen = a.now().UTC()
+			if _, err := a.run(ctx, a.cli, "ec2", "create-tags", "--resources", vpc.VpcID,
+				"--tags", fmt.Sprintf("Key=%s,Value=%s", FirstSeenTag, seen.Format(time.RFC3339))); err != nil {
+				return nil, fmt.Errorf("failed to tag VPC %s: %w", vpc.VpcID, err)
+			}
+		}
+		observe(awsClusterName(tags), seen)
+	}
+
+	resources := make([]Resource, 0, len(created))
+	for name, t := range created {
+		resources = append(resources, Resource{Kind: "cluster", Name: name, ID: name, CreatedAt: t})
+	}
+	slices.SortFunc(resources, func(a, b Resource) int { return strings.Compare(a.Name, b.Name) })
+	return resources, nil
+}
+
+// Delete nukes all of the resources of the cluster, including the load
+// balancers and volumes created by the cloud controller and CSI driver.
+func (a *AWS) Delete(ctx context.Context, r Resource) error {
+	_, err := a.run(ctx, "make", "dev-aws-nuke", "CLUSTER_NAME="+r.ID)
+	return err
+}
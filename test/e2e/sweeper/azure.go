@@ -0,0 +1,92 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sweeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// azureClusterTagPrefix is the prefix of the tag CAPZ tags the resource
+// groups of the cluster with, the name of the cluster follows it.
+const azureClusterTagPrefix = "sigs.k8s.io_cluster-api-provider-azure_cluster_"
+
+// Azure sweeps the resource groups of the clusters with the Azure CLI.
+type Azure struct {
+	run runFunc
+	now func() time.Time
+	cli string
+}
+
+// NewAzure returns the Azure provider using the given Azure CLI binary.
+func NewAzure(cli string) *Azure {
+	return &Azure{cli: cli, run: run, now: time.Now}
+}
+
+func (*Azure) Name() string { return "azure" }
+
+// List returns the resource groups owned by the clusters. The resource groups
+// carry no creation time, so they are tagged with the first seen tag once
+// found.
+func (a *Azure) List(ctx context.Context) ([]Resource, error) {
+	out, err := a.run(ctx, a.cli, "group", "list", "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		Tags       map[string]string `json:"tags"`
+		Name       string            `json:"name"`
+		Properties struct {
+			ProvisioningState string `json:"provisioningState"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(out, &groups); err != nil {
+		return nil, fmt.Errorf("failed to parse resource groups: %w", err)
+	}
+
+	var resources []Resource
+	for _, g := range groups {
+		if g.Properties.ProvisioningState == "Deleting" || !slices.ContainsFunc(slices.Collect(maps.Keys(g.Tags)), isAzureClusterTag) {
+			continue
+		}
+
+		seen, ok := firstSeen(g.Tags)
+		if !ok {
+			seen = a.now().UTC()
+			if _, err := a.run(ctx, a.cli, "group", "update", "--name", g.Name,
+				"--set", fmt.Sprintf("tags.%s=%s", FirstSeenTag, seen.Format(time.RFC3339))); err != nil {
+				return nil, fmt.Errorf("failed to tag resource group %s: %w", g.Name, err)
+			}
+		}
+		resources = append(resources, Resource{Kind: "resource group", Name: g.Name, ID: g.Name, CreatedAt: seen})
+	}
+	return resources, nil
+}
+
+// Delete deletes the resource group with all of its resources without
+// waiting for the deletion to complete.
+func (a *Azure) Delete(ctx context.Context, r Resource) error {
+	_, err := a.run(ctx, a.cli, "group", "delete", "--name", r.ID, "--yes", "--no-wait")
+	return err
+}
+
+func isAzureClusterTag(key string) bool {
+	return strings.HasPrefix(key, azureClusterTagPrefix)
+}
@@ -0,0 +1,95 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The sweeper command deletes the cloud resources of the e2e runs older than
+// the TTL, it is run on schedule in CI with the dev-sweep target.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/K0rdent/kcm/internal/cloudapi"
+	"github.com/K0rdent/kcm/test/e2e/sweeper"
+)
+
+func main() {
+	if err := sweep(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func sweep() error {
+	var (
+		providers     string
+		prefixes      string
+		awsCLI        string
+		azureCLI      string
+		govc          string
+		vsphereFolder string
+		ttl           time.Duration
+		dryRun        bool
+		limits        = cloudapi.DefaultOptions
+	)
+	flag.StringVar(&providers, "providers", "aws,azure", "Comma-separated list of the providers to sweep, any of aws, azure and vsphere.")
+	flag.StringVar(&prefixes, "prefixes", strings.Join(sweeper.DefaultPrefixes, ","), "Comma-separated list of the prefixes of the names of the swept resources.")
+	flag.DurationVar(&ttl, "ttl", 6*time.Hour, "The age of the resources they are deleted after.")
+	flag.BoolVar(&dryRun, "dry-run", false, "Only report the resources which would be deleted.")
+	flag.Float64Var(&limits.QPS, "qps", limits.QPS, "The maximum average rate of the calls of the APIs per provider, 0 disables the rate limiting.")
+	flag.IntVar(&limits.FailureThreshold, "failure-threshold", limits.FailureThreshold, "The number of the consecutive failed calls of the APIs the provider is not called anymore after, 0 disables it.")
+	flag.StringVar(&awsCLI, "aws-cli", "aws", "Path to the AWS CLI binary.")
+	flag.StringVar(&azureCLI, "azure-cli", "az", "Path to the Azure CLI binary.")
+	flag.StringVar(&govc, "govc", "govc", "Path to the govc binary.")
+	flag.StringVar(&vsphereFolder, "vsphere-folder", os.Getenv("VSPHERE_FOLDER"), "The vSphere folder of the virtual machines of the e2e runs.")
+	flag.Parse()
+
+	var ps []sweeper.Provider
+	for _, p := range strings.Split(providers, ",") {
+		switch strings.TrimSpace(p) {
+		case "aws":
+			ps = append(ps, sweeper.NewAWS(awsCLI))
+		case "azure":
+			ps = append(ps, sweeper.NewAzure(azureCLI))
+		case "vsphere":
+			if vsphereFolder == "" {
+				return errors.New("the vSphere folder must be set to sweep vsphere")
+			}
+			ps = append(ps, sweeper.NewVSphere(govc, vsphereFolder))
+		case "":
+		default:
+			return fmt.Errorf("unknown provider %q", p)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	result, err := sweeper.Sweep(ctx, ps, sweeper.Options{
+		Prefixes: strings.Split(prefixes, ","),
+		TTL:      ttl,
+		DryRun:   dryRun,
+		Limiter:  cloudapi.NewLimiter(limits),
+		Out:      os.Stdout,
+	})
+	fmt.Printf("Swept %d resources, kept %d younger than %s\n", len(result.Deleted), len(result.Kept), ttl)
+	return err
+}
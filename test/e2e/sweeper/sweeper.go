@@ -0,0 +1,191 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sweeper deletes the cloud resources leaked by the e2e runs, e.g.
+// when the runs are aborted before the created clusters are deleted.
+package sweeper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/K0rdent/kcm/internal/cloudapi"
+)
+
+// FirstSeenTag is the tag the resources without the creation time are tagged
+// with once they are found by the sweeper, their age is counted from it.
+const FirstSeenTag = "kcm-e2e-first-seen"
+
+// DefaultPrefixes are the prefixes of the names of the clusters created by the
+// e2e runs locally and in CI.
+var DefaultPrefixes = []string{"e2e-test-", "ci-"}
+
+// Resource is the resource left by the e2e run, which is deleted as a whole,
+// e.g. all of the resources of the cluster.
+type Resource struct {
+	// CreatedAt is the time the resource was created or first seen at.
+	CreatedAt time.Time
+	// Kind of the resource, e.g. cluster or virtual machine.
+	Kind string
+	// Name of the resource, which is matched against the prefixes.
+	Name string
+	// ID identifies the resource for the provider upon deletion.
+	ID string
+}
+
+func (r Resource) String() string {
+	return fmt.Sprintf("%s %s", r.Kind, r.Name)
+}
+
+// Provider finds and deletes the resources of the e2e runs in the cloud.
+type Provider interface {
+	// Name is the name of the provider, e.g. aws.
+	Name() string
+	// List returns the resources created by the clusters of the e2e runs.
+	List(ctx context.Context) ([]Resource, error)
+	// Delete deletes the resource and everything it owns.
+	Delete(ctx context.Context, r Resource) error
+}
+
+// Options configure the sweep.
+type Options struct {
+	// Now returns the current time, time.Now if unset.
+	Now func() time.Time
+	// Out is where the progress of the sweep is written to.
+	Out io.Writer
+	// Prefixes are the prefixes of the names of the swept resources.
+	Prefixes []string
+	// TTL is the age of the resources they are swept after.
+	TTL time.Duration
+	// Limiter limits the calls of the provider APIs, they are not limited if
+	// unset.
+	Limiter *cloudapi.Limiter
+	// DryRun only reports the resources which would be deleted.
+	DryRun bool
+}
+
+// Result is the result of the sweep.
+type Result struct {
+	// Deleted are the resources deleted, or which would be deleted upon the dry run.
+	Deleted []Resource
+	// Kept are the matching resources younger than the TTL.
+	Kept []Resource
+}
+
+// Sweep deletes the resources of all of the providers matching the prefixes
+// and older than the TTL. The failures of the providers do not prevent the
+// others from being swept and are returned joined.
+func Sweep(ctx context.Context, providers []Provider, opts Options) (Result, error) {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+
+	var (
+		result Result
+		errs   error
+	)
+	for _, p := range providers {
+		key := cloudapi.Key{Provider: p.Name()}
+
+		var resources []Resource
+		err := opts.call(ctx, key, func(ctx context.Context) (err error) {
+			resources, err = p.List(ctx)
+			return err
+		})
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to list %s resources: %w", p.Name(), err))
+			continue
+		}
+
+		now := opts.Now()
+		for _, r := range resources {
+			if !HasPrefix(r.Name, opts.Prefixes) {
+				continue
+			}
+			if !IsExpired(r, now, opts.TTL) {
+				result.Kept = append(result.Kept, r)
+				continue
+			}
+
+			age := now.Sub(r.CreatedAt).Round(time.Minute)
+			if opts.DryRun {
+				_, _ = fmt.Fprintf(opts.Out, "[%s] would delete %s, age %s\n", p.Name(), r, age)
+				result.Deleted = append(result.Deleted, r)
+				continue
+			}
+
+			_, _ = fmt.Fprintf(opts.Out, "[%s] deleting %s, age %s\n", p.Name(), r, age)
+			if err := opts.call(ctx, key, func(ctx context.Context) error { return p.Delete(ctx, r) }); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("failed to delete %s %s: %w", p.Name(), r, err))
+				continue
+			}
+			result.Deleted = append(result.Deleted, r)
+		}
+	}
+
+	return result, errs
+}
+
+func (o Options) call(ctx context.Context, key cloudapi.Key, fn func(context.Context) error) error {
+	if o.Limiter == nil {
+		return fn(ctx)
+	}
+	return o.Limiter.Do(ctx, key, fn)
+}
+
+// HasPrefix reports whether the name starts with any of the prefixes.
+func HasPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired reports whether the resource is older than the TTL, the resources
+// of unknown age are never expired.
+func IsExpired(r Resource, now time.Time, ttl time.Duration) bool {
+	return !r.CreatedAt.IsZero() && now.Sub(r.CreatedAt) > ttl
+}
+
+// firstSeen returns the time of the first seen tag or ok=false if the tag is
+// missing or malformed.
+func firstSeen(tags map[string]string) (t time.Time, ok bool) {
+	t, err := time.Parse(time.RFC3339, tags[FirstSeenTag])
+	return t, err == nil
+}
+
+// runFunc runs the command and returns its standard output.
+type runFunc func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+func run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sweeper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+type fakeProvider struct {
+	listErr   error
+	deleteErr error
+	resources []Resource
+	deleted   []string
+}
+
+func (*fakeProvider) Name() string { return "fake" }
+
+func (f *fakeProvider) List(context.Context) ([]Resource, error) {
+	return f.resources, f.listErr
+}
+
+func (f *fakeProvider) Delete(_ context.Context, r Resource) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.deleted = append(f.deleted, r.Name)
+	return nil
+}
+
+func TestSweep(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	resources := []Resource{
+		{Kind: "cluster", Name: "ci-12345-aws", CreatedAt: now.Add(-7 * time.Hour)},
+		{Kind: "cluster", Name: "ci-67890-aws", CreatedAt: now.Add(-time.Hour)},
+		{Kind: "cluster", Name: "e2e-test-abcdef12", CreatedAt: now.Add(-24 * time.Hour)},
+		{Kind: "cluster", Name: "e2e-test-unknown"},
+		{Kind: "cluster", Name: "production", CreatedAt: now.Add(-24 * time.Hour)},
+	}
+
+	for _, tc := range []struct {
+		name            string
+		provider        *fakeProvider
+		dryRun          bool
+		expectedDeleted []string
+		expectedErr     string
+	}{
+		{
+			name:            "deletes expired matching resources",
+			provider:        &fakeProvider{resources: resources},
+			expectedDeleted: []string{"ci-12345-aws", "e2e-test-abcdef12"},
+		},
+		{
+			name:     "dry run",
+			provider: &fakeProvider{resources: resources},
+			dryRun:   true,
+		},
+		{
+			name:        "list failure",
+			provider:    &fakeProvider{listErr: errors.New("unauthorized")},
+			expectedErr: "failed to list fake resources: unauthorized",
+		},
+		{
+			name:        "delete failure",
+			provider:    &fakeProvider{resources: resources, deleteErr: errors.New("dependency violation")},
+			expectedErr: "failed to delete fake cluster ci-12345-aws: dependency violation",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			out := new(strings.Builder)
+			result, err := Sweep(t.Context(), []Provider{tc.provider}, Options{
+				Now:      func() time.Time { return now },
+				Out:      out,
+				Prefixes: DefaultPrefixes,
+				TTL:      6 * time.Hour,
+				DryRun:   tc.dryRun,
+			})
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(tc.provider.deleted).To(Equal(tc.expectedDeleted))
+			g.Expect(result.Deleted).To(HaveLen(2))
+			g.Expect(result.Kept).To(HaveLen(2))
+			if tc.dryRun {
+				g.Expect(out.String()).To(ContainSubstring("would delete cluster ci-12345-aws, age 7h0m0s"))
+			}
+		})
+	}
+}
+
+func TestAWSList(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	outputs := map[string]string{
+		"describe-instances": `{"Reservations": [{"Instances": [
+			{"LaunchTime": "2025-01-01T08:00:00Z", "State": {"Name": "running"}, "Tags": [{"Key": "sigs.k8s.io/cluster-api-provider-aws/cluster/ci-12345-aws", "Value": "owned"}]},
+			{"LaunchTime": "2025-01-01T06:00:00Z", "State": {"Name": "terminated"}, "Tags": [{"Key": "sigs.k8s.io/cluster-api-provider-aws/cluster/ci-12345-aws", "Value": "owned"}]}
+		]}]}`,
+		"describe-vpcs": `{"Vpcs": [
+			{"VpcId": "vpc-1", "Tags": [{"Key": "sigs.k8s.io/cluster-api-provider-aws/cluster/ci-12345-aws", "Value": "owned"}, {"Key": "kcm-e2e-first-seen", "Value": "2025-01-01T07:00:00Z"}]},
+			{"VpcId": "vpc-2", "Tags": [{"Key": "sigs.k8s.io/cluster-api-provider-aws/cluster/ci-67890-aws", "Value": "owned"}]}
+		]}`,
+	}
+	var tagged []string
+	a := &AWS{cli: "aws", now: func() time.Time { return now }, run: func(_ context.Context, _ string, args ...string) ([]byte, error) {
+		if args[1] == "create-tags" {
+			tagged = append(tagged, args[3])
+			return nil, nil
+		}
+		return []byte(outputs[args[1]]), nil
+	}}
+
+	resources, err := a.List(t.Context())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resources).To(Equal([]Resource{
+		{Kind: "cluster", Name: "ci-12345-aws", ID: "ci-12345-aws", CreatedAt: time.Date(2025, 1, 1, 7, 0, 0, 0, time.UTC)},
+		{Kind: "cluster", Name: "ci-67890-aws", ID: "ci-67890-aws", CreatedAt: now},
+	}))
+	g.Expect(tagged).To(Equal([]string{"vpc-2"}))
+}
@@ -0,0 +1,95 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sweeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// VSphere sweeps the virtual machines of the clusters with govc, which is
+// configured with the GOVC_* env vars.
+type VSphere struct {
+	run    runFunc
+	cli    string
+	folder string
+}
+
+// NewVSphere returns the vSphere provider using the given govc binary and
+// sweeping the virtual machines in the folder.
+func NewVSphere(cli, folder string) *VSphere {
+	return &VSphere{cli: cli, folder: folder, run: run}
+}
+
+func (*VSphere) Name() string { return "vsphere" }
+
+// List returns the virtual machines in the folder, the machines of the
+// clusters are named after them.
+func (v *VSphere) List(ctx context.Context) ([]Resource, error) {
+	out, err := v.run(ctx, v.cli, "find", v.folder, "-type", "m")
+	if err != nil {
+		return nil, err
+	}
+	paths := strings.Fields(string(out))
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	out, err = v.run(ctx, v.cli, append([]string{"vm.info", "-json"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+	// the field names are matched case-insensitively, so the output of both
+	// the older and newer govc versions is parsed
+	var info struct {
+		VirtualMachines []struct {
+			Config *struct {
+				CreateDate *time.Time `json:"createDate"`
+			} `json:"config"`
+			Name string `json:"name"`
+		} `json:"virtualMachines"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse virtual machines: %w", err)
+	}
+
+	byName := make(map[string]string, len(paths))
+	for _, p := range paths {
+		byName[path.Base(p)] = p
+	}
+
+	resources := make([]Resource, 0, len(info.VirtualMachines))
+	for _, vm := range info.VirtualMachines {
+		r := Resource{Kind: "virtual machine", Name: vm.Name, ID: byName[vm.Name]}
+		if r.ID == "" {
+			continue
+		}
+		if vm.Config != nil && vm.Config.CreateDate != nil {
+			r.CreatedAt = *vm.Config.CreateDate
+		}
+		resources = append(resources, r)
+	}
+	return resources, nil
+}
+
+// Delete powers off and destroys the virtual machine.
+func (v *VSphere) Delete(ctx context.Context, r Resource) error {
+	_, err := v.run(ctx, v.cli, "vm.destroy", r.ID)
+	return err
+}
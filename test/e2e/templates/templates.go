@@ -28,18 +28,24 @@ import (
 type Type string
 
 const (
-	TemplateAWSStandaloneCP     Type = "aws-standalone-cp"
-	TemplateAWSHostedCP         Type = "aws-hosted-cp"
-	TemplateAWSEKS              Type = "aws-eks"
-	TemplateAzureStandaloneCP   Type = "azure-standalone-cp"
-	TemplateAzureHostedCP       Type = "azure-hosted-cp"
-	TemplateAzureAKS            Type = "azure-aks"
-	TemplateVSphereStandaloneCP Type = "vsphere-standalone-cp"
-	TemplateVSphereHostedCP     Type = "vsphere-hosted-cp"
-	TemplateAdoptedCluster      Type = "adopted-cluster"
-	TemplateRemoteCluster       Type = "remote-cluster"
+	TemplateAWSStandaloneCP      Type = "aws-standalone-cp"
+	TemplateAWSHostedCP          Type = "aws-hosted-cp"
+	TemplateAWSEKS               Type = "aws-eks"
+	TemplateAzureStandaloneCP    Type = "azure-standalone-cp"
+	TemplateAzureHostedCP        Type = "azure-hosted-cp"
+	TemplateAzureAKS             Type = "azure-aks"
+	TemplateVSphereStandaloneCP  Type = "vsphere-standalone-cp"
+	TemplateVSphereHostedCP      Type = "vsphere-hosted-cp"
+	TemplateDockerHostedCP       Type = "docker-hosted-cp"
+	TemplateInMemoryStandaloneCP Type = "in-memory-standalone-cp"
+	TemplateAdoptedCluster       Type = "adopted-cluster"
+	TemplateRemoteCluster        Type = "remote-cluster"
 )
 
+// releaseLabelPrefix prefixes the label marking the templates installed from
+// the release by the stable-templates make target.
+const releaseLabelPrefix = "k0rdent.mirantis.com/e2e-release-"
+
 // Types is an array of all the supported template types
 var Types = []Type{
 	TemplateAWSStandaloneCP,
@@ -50,6 +56,8 @@ var Types = []Type{
 	TemplateAzureAKS,
 	TemplateVSphereStandaloneCP,
 	TemplateVSphereHostedCP,
+	TemplateDockerHostedCP,
+	TemplateInMemoryStandaloneCP,
 	TemplateAdoptedCluster,
 	TemplateRemoteCluster,
 }
@@ -63,10 +71,22 @@ func GetType(template string) Type {
 	return ""
 }
 
-func GetSortedClusterTemplates(ctx context.Context, cl crclient.Client, namespace string) ([]string, error) {
+// ReleaseLabel returns the label selecting the templates installed from the
+// given release of kcm.
+func ReleaseLabel(release string) crclient.MatchingLabels {
+	return crclient.MatchingLabels{releaseLabelPrefix + release: "true"}
+}
+
+// IsHosted reports whether the control planes of the clusters of the type are
+// hosted by k0smotron on the management cluster.
+func IsHosted(templateType Type) bool {
+	return strings.HasSuffix(string(templateType), "-hosted-cp")
+}
+
+func GetSortedClusterTemplates(ctx context.Context, cl crclient.Client, namespace string, opts ...crclient.ListOption) ([]string, error) {
 	itemsList := &metav1.PartialObjectMetadataList{}
 	itemsList.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind(v1alpha1.ClusterTemplateKind))
-	if err := cl.List(ctx, itemsList, crclient.InNamespace(namespace)); err != nil {
+	if err := cl.List(ctx, itemsList, append([]crclient.ListOption{crclient.InNamespace(namespace)}, opts...)...); err != nil {
 		return nil, err
 	}
 	clusterTemplates := make([]string, 0, len(itemsList.Items))
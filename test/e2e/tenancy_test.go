@@ -0,0 +1,305 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	internalutils "github.com/K0rdent/kcm/internal/utils"
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/templates"
+	"github.com/K0rdent/kcm/test/e2e/wait"
+	"github.com/K0rdent/kcm/test/scheme"
+)
+
+const (
+	// tenantChainName is the name of the template chains granted to the
+	// tenants by the access rules of the tenancy specs.
+	tenantChainName = "kcm-e2e-tenant"
+	// tenantCredentialName is the name of the Credential granted to the
+	// first tenant, ungrantedCredentialName is the one granted to none.
+	tenantCredentialName    = "kcm-e2e-tenant-cred"
+	ungrantedCredentialName = "kcm-e2e-ungranted-cred"
+	// tenantIdentityName is the name of the Secret identity of the Credentials.
+	tenantIdentityName = "kcm-e2e-tenant-identity"
+	// tenantUser is the user impersonated as the editor of the first tenant.
+	tenantUser = "kcm-e2e-tenant-editor"
+	// tenantEditorRole is the ClusterRole of the editors of the namespaces
+	// installed along with kcm.
+	tenantEditorRole = "kcm-namespace-editor-role"
+)
+
+// The tenancy specs distribute the templates and the credentials to the
+// namespaces of the tenants with the access rules of the AccessManagement and
+// check the tenants are confined to their grants: the ClusterDeployments
+// referencing the objects not granted to the namespace are rejected and the
+// editor of the tenant cannot access the objects of the other namespaces. The
+// ClusterDeployments are created in the dry-run mode, so no clusters are
+// deployed.
+var _ = Describe("Multi-tenancy", Label("controller", "tenancy"), Ordered, func() {
+	var (
+		kc             *kubeclient.KubeClient
+		tenantClient   crclient.Client
+		tenantA        string
+		tenantB        string
+		granted        string
+		ungranted      string
+		grantedService string
+	)
+
+	BeforeAll(func() {
+		ctx := context.Background()
+		kc = kubeclient.NewFromLocal(internalutils.DefaultSystemNamespace)
+
+		// the namespaces of the tenants are unique to the process
+		tenantA = fmt.Sprintf("kcm-e2e-tenant-a-%d", GinkgoParallelProcess())
+		tenantB = fmt.Sprintf("kcm-e2e-tenant-b-%d", GinkgoParallelProcess())
+
+		clusterTemplates, err := templates.GetSortedClusterTemplates(ctx, kc.CrClient, kc.Namespace)
+		Expect(err).NotTo(HaveOccurred())
+		adopted := templates.FindLatestTemplatesWithType(clusterTemplates, templates.TemplateAdoptedCluster, 1)
+		Expect(adopted).NotTo(BeEmpty(), "no %s templates are installed", templates.TemplateAdoptedCluster)
+		granted = adopted[0]
+		i := slices.IndexFunc(clusterTemplates, func(t string) bool { return t != granted })
+		Expect(i).NotTo(Equal(-1), "no ClusterTemplates other than %s are installed", granted)
+		ungranted = clusterTemplates[i]
+
+		serviceTemplates := new(kcmv1.ServiceTemplateList)
+		Expect(kc.CrClient.List(ctx, serviceTemplates, crclient.InNamespace(kc.Namespace))).To(Succeed())
+		Expect(serviceTemplates.Items).NotTo(BeEmpty(), "no ServiceTemplates are installed")
+		grantedService = serviceTemplates.Items[0].Name
+
+		By("creating the Credentials and the template chains of the tenants")
+		identity := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tenantIdentityName,
+				Namespace: kc.Namespace,
+				Labels:    map[string]string{kcmv1.GenericComponentNameLabel: kcmv1.GenericComponentLabelValueKCM},
+			},
+		}
+		Expect(crclient.IgnoreAlreadyExists(kc.CrClient.Create(ctx, identity))).To(Succeed())
+		for _, name := range []string{tenantCredentialName, ungrantedCredentialName} {
+			cred := &kcmv1.Credential{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: kc.Namespace},
+				Spec: kcmv1.CredentialSpec{
+					IdentityRef: &corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Name: identity.Name, Namespace: identity.Namespace},
+				},
+			}
+			Expect(crclient.IgnoreAlreadyExists(kc.CrClient.Create(ctx, cred))).To(Succeed())
+			wait.CredentialReady(ctx, kc.CrClient, crclient.ObjectKeyFromObject(cred), config.Config.Timeouts.ControllersReady)
+		}
+
+		clusterTemplateChain := &kcmv1.ClusterTemplateChain{
+			ObjectMeta: metav1.ObjectMeta{Name: tenantChainName, Namespace: kc.Namespace},
+			Spec:       kcmv1.TemplateChainSpec{SupportedTemplates: []kcmv1.SupportedTemplate{{Name: granted}}},
+		}
+		Expect(crclient.IgnoreAlreadyExists(kc.CrClient.Create(ctx, clusterTemplateChain))).To(Succeed())
+		serviceTemplateChain := &kcmv1.ServiceTemplateChain{
+			ObjectMeta: metav1.ObjectMeta{Name: tenantChainName, Namespace: kc.Namespace},
+			Spec:       kcmv1.TemplateChainSpec{SupportedTemplates: []kcmv1.SupportedTemplate{{Name: grantedService}}},
+		}
+		Expect(crclient.IgnoreAlreadyExists(kc.CrClient.Create(ctx, serviceTemplateChain))).To(Succeed())
+
+		By(fmt.Sprintf("creating the %s and %s namespaces of the tenants", tenantA, tenantB))
+		for _, name := range []string{tenantA, tenantB} {
+			Expect(crclient.IgnoreAlreadyExists(kc.CrClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}))).To(Succeed())
+		}
+
+		By(fmt.Sprintf("binding the %s user to the %s role in the %s namespace", tenantUser, tenantEditorRole, tenantA))
+		binding := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: tenantUser, Namespace: tenantA},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: tenantEditorRole},
+			Subjects:   []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: tenantUser}},
+		}
+		Expect(crclient.IgnoreAlreadyExists(kc.CrClient.Create(ctx, binding))).To(Succeed())
+
+		tenantConfig := rest.CopyConfig(kc.Config)
+		tenantConfig.Impersonate = rest.ImpersonationConfig{UserName: tenantUser}
+		tenantClient, err = crclient.New(tenantConfig, crclient.Options{Scheme: scheme.Scheme})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("granting the cluster templates and the Credential to the first tenant and the service templates to the second one")
+		updateAccessRules(ctx, kc, []string{tenantA, tenantB},
+			kcmv1.AccessRule{
+				TargetNamespaces:      kcmv1.TargetNamespaces{List: []string{tenantA}},
+				ClusterTemplateChains: []string{tenantChainName},
+				Credentials:           []string{tenantCredentialName},
+			},
+			kcmv1.AccessRule{
+				TargetNamespaces:      kcmv1.TargetNamespaces{List: []string{tenantB}},
+				ServiceTemplateChains: []string{tenantChainName},
+			},
+		)
+	})
+
+	AfterAll(func() {
+		if kc == nil || !cleanup() {
+			return
+		}
+		ctx := context.Background()
+
+		By("removing the access rules, the namespaces and the objects of the tenants")
+		updateAccessRules(ctx, kc, []string{tenantA, tenantB})
+		for _, obj := range []crclient.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tenantA}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tenantB}},
+			&kcmv1.ClusterTemplateChain{ObjectMeta: metav1.ObjectMeta{Name: tenantChainName, Namespace: kc.Namespace}},
+			&kcmv1.ServiceTemplateChain{ObjectMeta: metav1.ObjectMeta{Name: tenantChainName, Namespace: kc.Namespace}},
+			&kcmv1.Credential{ObjectMeta: metav1.ObjectMeta{Name: tenantCredentialName, Namespace: kc.Namespace}},
+			&kcmv1.Credential{ObjectMeta: metav1.ObjectMeta{Name: ungrantedCredentialName, Namespace: kc.Namespace}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: tenantIdentityName, Namespace: kc.Namespace}},
+		} {
+			Expect(crclient.IgnoreNotFound(kc.CrClient.Delete(ctx, obj))).To(Succeed())
+		}
+	})
+
+	It("should distribute the granted templates and credentials to the tenant namespaces", func() {
+		ctx := context.Background()
+
+		By(fmt.Sprintf("waiting for the %s ClusterTemplate and the %s Credential to be distributed to the %s namespace", granted, tenantCredentialName, tenantA))
+		Eventually(func() error {
+			template := new(kcmv1.ClusterTemplate)
+			if err := kc.CrClient.Get(ctx, crclient.ObjectKey{Namespace: tenantA, Name: granted}, template); err != nil {
+				return err
+			}
+			if !template.Status.Valid {
+				return fmt.Errorf("ClusterTemplate %s/%s is not valid: %s", tenantA, granted, template.Status.ValidationError)
+			}
+			return nil
+		}).WithTimeout(config.Config.Timeouts.ControllersReady).WithPolling(config.Config.Timeouts.Polling).Should(Succeed())
+		wait.CredentialReady(ctx, kc.CrClient, crclient.ObjectKey{Namespace: tenantA, Name: tenantCredentialName}, config.Config.Timeouts.ControllersReady)
+
+		By(fmt.Sprintf("waiting for the %s ServiceTemplate to be distributed to the %s namespace", grantedService, tenantB))
+		Eventually(func() error {
+			return kc.CrClient.Get(ctx, crclient.ObjectKey{Namespace: tenantB, Name: grantedService}, new(kcmv1.ServiceTemplate))
+		}).WithTimeout(config.Config.Timeouts.ControllersReady).WithPolling(config.Config.Timeouts.Polling).Should(Succeed())
+
+		By("validating that the objects are distributed only to the namespaces of the grants")
+		for _, obj := range []crclient.Object{
+			&kcmv1.ClusterTemplate{ObjectMeta: metav1.ObjectMeta{Namespace: tenantB, Name: granted}},
+			&kcmv1.ClusterTemplate{ObjectMeta: metav1.ObjectMeta{Namespace: tenantA, Name: ungranted}},
+			&kcmv1.ServiceTemplate{ObjectMeta: metav1.ObjectMeta{Namespace: tenantA, Name: grantedService}},
+			&kcmv1.Credential{ObjectMeta: metav1.ObjectMeta{Namespace: tenantB, Name: tenantCredentialName}},
+			&kcmv1.Credential{ObjectMeta: metav1.ObjectMeta{Namespace: tenantA, Name: ungrantedCredentialName}},
+		} {
+			err := kc.CrClient.Get(ctx, crclient.ObjectKeyFromObject(obj), obj)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "%T %s is expected to be absent, got: %v", obj, crclient.ObjectKeyFromObject(obj), err)
+		}
+	})
+
+	It("should reject the ClusterDeployments referencing the objects outside of the grants", func() {
+		ctx := context.Background()
+
+		By("creating the ClusterDeployment with the granted objects by the editor of the tenant")
+		Expect(tenantClient.Create(ctx, tenantClusterDeployment(tenantA, granted, tenantCredentialName), crclient.DryRunAll)).To(Succeed())
+
+		for _, c := range []struct {
+			description string
+			cd          *kcmv1.ClusterDeployment
+		}{
+			{"the Credential not granted to any tenant", tenantClusterDeployment(tenantA, granted, ungrantedCredentialName)},
+			{"the ClusterTemplate not granted to the tenant", tenantClusterDeployment(tenantA, ungranted, tenantCredentialName)},
+			{"the ClusterTemplate and the Credential granted to the other tenant", tenantClusterDeployment(tenantB, granted, tenantCredentialName)},
+		} {
+			By(fmt.Sprintf("validating that the ClusterDeployment referencing %s is rejected", c.description))
+			err := kc.CrClient.Create(ctx, c.cd, crclient.DryRunAll)
+			Expect(err).To(HaveOccurred(), "the ClusterDeployment referencing %s has been admitted", c.description)
+			Expect(err.Error()).To(ContainSubstring("not found"))
+		}
+	})
+
+	It("should forbid the editor of the tenant to access the other namespaces", func() {
+		ctx := context.Background()
+
+		err := tenantClient.Create(ctx, tenantClusterDeployment(tenantB, granted, tenantCredentialName), crclient.DryRunAll)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue(), "creating the ClusterDeployment in the %s namespace is expected to be forbidden, got: %v", tenantB, err)
+
+		err = tenantClient.Get(ctx, crclient.ObjectKey{Namespace: kc.Namespace, Name: tenantCredentialName}, new(kcmv1.Credential))
+		Expect(apierrors.IsForbidden(err)).To(BeTrue(), "reading the Credentials of the %s namespace is expected to be forbidden, got: %v", kc.Namespace, err)
+
+		err = tenantClient.List(ctx, new(kcmv1.ClusterTemplateList), crclient.InNamespace(kc.Namespace))
+		Expect(apierrors.IsForbidden(err)).To(BeTrue(), "listing the ClusterTemplates of the %s namespace is expected to be forbidden, got: %v", kc.Namespace, err)
+	})
+
+	It("should remove the distributed objects once the grants are revoked", func() {
+		ctx := context.Background()
+
+		By("revoking the grants of the tenants")
+		updateAccessRules(ctx, kc, []string{tenantA, tenantB})
+
+		Eventually(func() error {
+			var errs error
+			for _, obj := range []crclient.Object{
+				&kcmv1.ClusterTemplateChain{ObjectMeta: metav1.ObjectMeta{Namespace: tenantA, Name: tenantChainName}},
+				&kcmv1.ClusterTemplate{ObjectMeta: metav1.ObjectMeta{Namespace: tenantA, Name: granted}},
+				&kcmv1.Credential{ObjectMeta: metav1.ObjectMeta{Namespace: tenantA, Name: tenantCredentialName}},
+				&kcmv1.ServiceTemplateChain{ObjectMeta: metav1.ObjectMeta{Namespace: tenantB, Name: tenantChainName}},
+				&kcmv1.ServiceTemplate{ObjectMeta: metav1.ObjectMeta{Namespace: tenantB, Name: grantedService}},
+			} {
+				if err := kc.CrClient.Get(ctx, crclient.ObjectKeyFromObject(obj), obj); !apierrors.IsNotFound(err) {
+					errs = errors.Join(errs, fmt.Errorf("%T %s has not been removed yet: %v", obj, crclient.ObjectKeyFromObject(obj), err))
+				}
+			}
+			return errs
+		}).WithTimeout(config.Config.Timeouts.ControllersReady).WithPolling(config.Config.Timeouts.Polling).Should(Succeed())
+	})
+})
+
+// tenantClusterDeployment returns the ClusterDeployment of the adopted
+// cluster in the namespace referencing the template and the Credential.
+func tenantClusterDeployment(namespace, template, credential string) *kcmv1.ClusterDeployment {
+	return &kcmv1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "kcm-e2e-tenant", Namespace: namespace},
+		Spec: kcmv1.ClusterDeploymentSpec{
+			Template:   template,
+			Credential: credential,
+		},
+	}
+}
+
+// updateAccessRules replaces the access rules of the AccessManagement
+// targeting the namespaces of the tenants with the given ones.
+func updateAccessRules(ctx context.Context, kc *kubeclient.KubeClient, tenants []string, rules ...kcmv1.AccessRule) {
+	GinkgoHelper()
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		accessManagement := new(kcmv1.AccessManagement)
+		if err := kc.CrClient.Get(ctx, crclient.ObjectKey{Name: kcmv1.AccessManagementName}, accessManagement); err != nil {
+			return err
+		}
+
+		kept := slices.DeleteFunc(slices.Clone(accessManagement.Spec.AccessRules), func(rule kcmv1.AccessRule) bool {
+			return slices.ContainsFunc(rule.TargetNamespaces.List, func(ns string) bool { return slices.Contains(tenants, ns) })
+		})
+		accessManagement.Spec.AccessRules = append(kept, rules...)
+		return kc.CrClient.Update(ctx, accessManagement)
+	})
+	Expect(err).NotTo(HaveOccurred(), "failed to update the access rules of the tenants")
+}
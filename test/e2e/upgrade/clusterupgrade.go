@@ -18,24 +18,24 @@ import (
 	"context"
 
 	. "github.com/onsi/gomega"
-	"k8s.io/apimachinery/pkg/types"
-	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+	"github.com/K0rdent/kcm/test/e2e/report"
 )
 
 func (v *ClusterUpgrade) Run(ctx context.Context) {
-	cluster := &kcmv1.ClusterDeployment{}
-	err := v.mgmtClient.Get(ctx, types.NamespacedName{
-		Namespace: v.namespace,
-		Name:      v.name,
-	}, cluster)
-	Expect(err).NotTo(HaveOccurred())
+	report.Template(v.newTemplate)
 
-	patch := crclient.MergeFrom(cluster.DeepCopy())
-	cluster.Spec.Template = v.newTemplate
-	err = v.mgmtClient.Patch(ctx, cluster, patch)
-	Expect(err).NotTo(HaveOccurred())
+	// only the template is applied with its own field manager, so the rest
+	// of the ClusterDeployment is left intact
+	cluster := new(unstructured.Unstructured)
+	cluster.SetGroupVersionKind(kcmv1.GroupVersion.WithKind(kcmv1.ClusterDeploymentKind))
+	cluster.SetNamespace(v.namespace)
+	cluster.SetName(v.name)
+	Expect(unstructured.SetNestedField(cluster.Object, v.newTemplate, "spec", "template")).To(Succeed())
+	Expect(kubeclient.Apply(ctx, v.mgmtClient, cluster, kubeclient.WithFieldManager("kcm-e2e-upgrade"))).To(Succeed())
 
 	v.Validate(ctx)
 }
@@ -0,0 +1,141 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/e2e/config"
+	"github.com/K0rdent/kcm/test/e2e/kubeclient"
+)
+
+// EnvVarValidateClusterUpgradePath is the environment variable reporting
+// whether the controller validates the upgrade paths of the clusters, the
+// skip-level upgrades are expected to be rejected if so.
+const EnvVarValidateClusterUpgradePath = "VALIDATE_CLUSTER_UPGRADE_PATH"
+
+// PathUpgrade upgrades the cluster deployment through the sequence of the
+// templates one by one.
+type PathUpgrade struct {
+	mgmtClient  crclient.Client
+	childClient crclient.Client
+	namespace   string
+	name        string
+	templates   []string
+	validator   Validator
+	timeouts    config.Timeouts
+}
+
+// NewPathUpgrade returns the upgrade of the cluster deployment created from
+// the first of the templates through all of the next ones.
+func NewPathUpgrade(mgmtClient, childClient crclient.Client, namespace, name string, templates []string, validator Validator, timeouts config.Timeouts) PathUpgrade {
+	return PathUpgrade{
+		mgmtClient:  mgmtClient,
+		childClient: childClient,
+		namespace:   namespace,
+		name:        name,
+		templates:   templates,
+		validator:   validator,
+		timeouts:    timeouts,
+	}
+}
+
+// Run creates the ClusterTemplateChain allowing the upgrades from each of the
+// templates to the next one only and upgrades the cluster deployment step by
+// step. Before each of the steps skipping the templates, the upgrade to the
+// last template is ensured to be rejected if the upgrade paths are validated.
+func (p *PathUpgrade) Run(ctx context.Context) {
+	Expect(len(p.templates)).To(BeNumerically(">=", 2), "the upgrade path should consist of 2 templates at least")
+
+	chain := p.templateChain()
+	Expect(kubeclient.Apply(ctx, p.mgmtClient, chain)).To(Succeed())
+	DeferCleanup(func(ctx SpecContext) {
+		Expect(crclient.IgnoreNotFound(p.mgmtClient.Delete(ctx, chain))).To(Succeed())
+	})
+
+	validatePath, _ := strconv.ParseBool(os.Getenv(EnvVarValidateClusterUpgradePath))
+	last := p.templates[len(p.templates)-1]
+	for i, template := range p.templates[1:] {
+		p.waitForAvailableUpgrade(ctx, template)
+
+		if validatePath && template != last {
+			By(fmt.Sprintf("ensuring the upgrade of the %s/%s ClusterDeployment from %s to %s is rejected", p.namespace, p.name, p.templates[i], last))
+			p.validateUpgradeRejected(ctx, last)
+		}
+
+		By(fmt.Sprintf("upgrading the %s/%s ClusterDeployment from %s to %s", p.namespace, p.name, p.templates[i], template))
+		clusterUpgrade := NewClusterUpgrade(p.mgmtClient, p.childClient, p.namespace, p.name, template, p.validator, p.timeouts)
+		clusterUpgrade.Run(ctx)
+	}
+}
+
+// templateChain returns the ClusterTemplateChain of the upgrade path. The
+// chain is not managed by kcm, so it only provides the available upgrades of
+// the cluster deployment and the templates are not copied by the controller.
+func (p *PathUpgrade) templateChain() *kcmv1.ClusterTemplateChain {
+	chain := &kcmv1.ClusterTemplateChain{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: p.namespace,
+			Name:      p.name + "-upgrade-path",
+		},
+	}
+	for i, template := range p.templates {
+		supported := kcmv1.SupportedTemplate{Name: template}
+		if i+1 < len(p.templates) {
+			supported.AvailableUpgrades = []kcmv1.AvailableUpgrade{{Name: p.templates[i+1]}}
+		}
+		chain.Spec.SupportedTemplates = append(chain.Spec.SupportedTemplates, supported)
+	}
+	return chain
+}
+
+func (p *PathUpgrade) waitForAvailableUpgrade(ctx context.Context, template string) {
+	Eventually(func() error {
+		cd := new(kcmv1.ClusterDeployment)
+		if err := p.mgmtClient.Get(ctx, crclient.ObjectKey{Namespace: p.namespace, Name: p.name}, cd); err != nil {
+			return err
+		}
+		if !slices.Contains(cd.Status.AvailableUpgrades, template) {
+			return fmt.Errorf("waiting for %s to be available for the upgrade of the %s/%s ClusterDeployment, available upgrades: %v",
+				template, p.namespace, p.name, cd.Status.AvailableUpgrades)
+		}
+		return nil
+	}).WithTimeout(5 * time.Minute).WithPolling(10 * time.Second).Should(Succeed())
+}
+
+// validateUpgradeRejected ensures the upgrade to the template is rejected by
+// the webhook, the upgrade is requested with the dry run so the cluster
+// deployment is left intact if it is admitted unexpectedly.
+func (p *PathUpgrade) validateUpgradeRejected(ctx context.Context, template string) {
+	cd := new(kcmv1.ClusterDeployment)
+	Expect(p.mgmtClient.Get(ctx, crclient.ObjectKey{Namespace: p.namespace, Name: p.name}, cd)).To(Succeed())
+	Expect(cd.Status.AvailableUpgrades).NotTo(ContainElement(template))
+
+	cd.Spec.Template = template
+	err := p.mgmtClient.Update(ctx, cd, crclient.DryRunAll)
+	Expect(err).To(MatchError(ContainSubstring("cluster upgrade is forbidden")),
+		"the upgrade of the %s/%s ClusterDeployment to %s skipping the upgrade path should be rejected", p.namespace, p.name, template)
+}
@@ -18,7 +18,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	hcv2 "github.com/fluxcd/helm-controller/api/v2"
 	. "github.com/onsi/ginkgo/v2"
@@ -30,6 +29,7 @@ import (
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/e2e/config"
 	"github.com/K0rdent/kcm/test/utils"
 )
 
@@ -42,13 +42,14 @@ type ClusterUpgrade struct {
 	name        string
 	newTemplate string
 	validator   Validator
+	timeouts    config.Timeouts
 }
 
 type Validator struct {
 	validationFuncs []validationFunc
 }
 
-func NewClusterUpgrade(mgmtClient, childClient crclient.Client, namespace, name, newTemplate string, validator Validator) ClusterUpgrade {
+func NewClusterUpgrade(mgmtClient, childClient crclient.Client, namespace, name, newTemplate string, validator Validator, timeouts config.Timeouts) ClusterUpgrade {
 	return ClusterUpgrade{
 		mgmtClient:  mgmtClient,
 		childClient: childClient,
@@ -56,6 +57,7 @@ func NewClusterUpgrade(mgmtClient, childClient crclient.Client, namespace, name,
 		name:        name,
 		newTemplate: newTemplate,
 		validator:   validator,
+		timeouts:    timeouts,
 	}
 }
 
@@ -75,7 +77,7 @@ func (v *ClusterUpgrade) Validate(ctx context.Context) {
 			}
 		}
 		return true
-	}, 20*time.Minute, 20*time.Second).Should(BeTrue())
+	}).WithTimeout(v.timeouts.Upgrade).WithPolling(v.timeouts.Polling).Should(BeTrue())
 }
 
 func validateHelmRelease(ctx context.Context, mgmtClient, _ crclient.Client, namespace, name, newTemplate string) error {
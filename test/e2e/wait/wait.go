@@ -0,0 +1,220 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wait provides the helpers waiting for the objects to satisfy the
+// checks, e.g. to have the conditions of the given status, reporting the
+// state of the objects once the wait times out.
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/utils/status"
+)
+
+// PollInterval is the interval the objects are checked at.
+const PollInterval = 10 * time.Second
+
+// Check returns the error describing why the object does not satisfy the
+// check yet.
+type Check func(obj *unstructured.Unstructured) error
+
+// ConditionStatus checks that the object has the condition of the given type
+// and status.
+func ConditionStatus(conditionType string, conditionStatus metav1.ConditionStatus) Check {
+	return func(obj *unstructured.Unstructured) error {
+		conditions, err := status.ConditionsFromUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		for _, c := range conditions {
+			if c.Type != conditionType {
+				continue
+			}
+			if c.Status != conditionStatus {
+				return fmt.Errorf("condition %s is %s, expected %s", conditionType, c.Status, conditionStatus)
+			}
+			return nil
+		}
+		return fmt.Errorf("condition %s is not reported", conditionType)
+	}
+}
+
+// ConditionTrue checks that the object has the condition of the given type
+// with the True status.
+func ConditionTrue(conditionType string) Check {
+	return ConditionStatus(conditionType, metav1.ConditionTrue)
+}
+
+// NotDeleting checks that the object is not being deleted.
+func NotDeleting() Check {
+	return func(obj *unstructured.Unstructured) error {
+		if obj.GetDeletionTimestamp() != nil {
+			return fmt.Errorf("being deleted since %s", obj.GetDeletionTimestamp())
+		}
+		return nil
+	}
+}
+
+// For waits for the object to satisfy all of the checks.
+func For(ctx context.Context, cl crclient.Client, gvk schema.GroupVersionKind, key crclient.ObjectKey, timeout time.Duration, checks ...Check) {
+	GinkgoHelper()
+
+	Eventually(ctx, func() error {
+		obj := new(unstructured.Unstructured)
+		obj.SetGroupVersionKind(gvk)
+		if err := cl.Get(ctx, key, obj); err != nil {
+			return fmt.Errorf("failed to get %s %s: %w", gvk.Kind, key, err)
+		}
+		return check(obj, checks)
+	}).WithTimeout(timeout).WithPolling(PollInterval).Should(Succeed())
+}
+
+// ForAll waits for at least the given number of the objects matching the
+// selector to exist and all of them to satisfy all of the checks.
+func ForAll(ctx context.Context, cl crclient.Client, gvk schema.GroupVersionKind, namespace, selector string, minCount int, timeout time.Duration, checks ...Check) {
+	GinkgoHelper()
+
+	labelSelector, err := labels.Parse(selector)
+	Expect(err).NotTo(HaveOccurred(), "invalid label selector %q", selector)
+
+	Eventually(ctx, func() error {
+		list := new(unstructured.UnstructuredList)
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := cl.List(ctx, list, crclient.InNamespace(namespace), crclient.MatchingLabelsSelector{Selector: labelSelector}); err != nil {
+			return fmt.Errorf("failed to list %s with %q: %w", gvk.Kind, selector, err)
+		}
+		if len(list.Items) < minCount {
+			return fmt.Errorf("expected at least %d %s with %q, got %d", minCount, gvk.Kind, selector, len(list.Items))
+		}
+
+		var errs error
+		for _, obj := range list.Items {
+			errs = errors.Join(errs, check(&obj, checks))
+		}
+		return errs
+	}).WithTimeout(timeout).WithPolling(PollInterval).Should(Succeed())
+}
+
+// check runs the checks of the object and returns the error listing the
+// failed checks along with the conditions of the object.
+func check(obj *unstructured.Unstructured, checks []Check) error {
+	var failed []string
+	for _, c := range checks {
+		if err := c(obj); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	kind, name := status.ObjKindName(obj)
+	if obj.GetNamespace() != "" {
+		name = obj.GetNamespace() + "/" + name
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s is not ready:", kind, name)
+	for _, f := range failed {
+		fmt.Fprintf(&b, "\n  - %s", f)
+	}
+	if conditions, err := status.ConditionsFromUnstructured(obj); err == nil && len(conditions) > 0 {
+		b.WriteString("\n  conditions:")
+		for _, c := range conditions {
+			fmt.Fprintf(&b, "\n    %s=%s", c.Type, c.Status)
+			if c.Reason != "" {
+				fmt.Fprintf(&b, " (%s)", c.Reason)
+			}
+			if c.Message != "" {
+				fmt.Fprintf(&b, ": %s", c.Message)
+			}
+		}
+	}
+	return errors.New(b.String())
+}
+
+// ClusterDeploymentReady waits for the ClusterDeployment to become ready.
+func ClusterDeploymentReady(ctx context.Context, cl crclient.Client, key crclient.ObjectKey, timeout time.Duration) {
+	GinkgoHelper()
+
+	By(fmt.Sprintf("waiting for ClusterDeployment %s to be ready", key))
+	For(ctx, cl, kcmv1.GroupVersion.WithKind(kcmv1.ClusterDeploymentKind), key, timeout, NotDeleting(), ConditionTrue(kcmv1.ReadyCondition))
+}
+
+// CredentialReady waits for the Credential to be verified.
+func CredentialReady(ctx context.Context, cl crclient.Client, key crclient.ObjectKey, timeout time.Duration) {
+	GinkgoHelper()
+
+	By(fmt.Sprintf("waiting for Credential %s to be ready", key))
+	For(ctx, cl, kcmv1.GroupVersion.WithKind(kcmv1.CredentialKind), key, timeout, NotDeleting(), ConditionTrue(kcmv1.CredentialReadyCondition))
+}
+
+// ManagementReady waits for the Management to become ready with all of its
+// components installed.
+func ManagementReady(ctx context.Context, cl crclient.Client, timeout time.Duration) {
+	GinkgoHelper()
+
+	By("waiting for Management to be ready")
+	For(ctx, cl, kcmv1.GroupVersion.WithKind(kcmv1.ManagementKind), crclient.ObjectKey{Name: kcmv1.ManagementName}, timeout,
+		ConditionTrue(kcmv1.ReadyCondition), componentsInstalled)
+}
+
+// componentsInstalled checks that all of the components of the Management
+// are successfully installed.
+func componentsInstalled(obj *unstructured.Unstructured) error {
+	components, _, err := unstructured.NestedMap(obj.Object, "status", "components")
+	if err != nil {
+		return fmt.Errorf("failed to get components: %w", err)
+	}
+	if len(components) == 0 {
+		return errors.New("no components are reported")
+	}
+
+	var errs error
+	for name, c := range components {
+		component, ok := c.(map[string]any)
+		if !ok {
+			return fmt.Errorf("unexpected status of component %s: %T", name, c)
+		}
+		if success, _ := component["success"].(bool); !success {
+			msg, _ := component["error"].(string)
+			errs = errors.Join(errs, fmt.Errorf("component %s is not installed: %s", name, msg))
+		}
+	}
+	return errs
+}
+
+// DeploymentsAvailable waits for at least the given number of the Deployments
+// matching the selector to exist and all of them to be available.
+func DeploymentsAvailable(ctx context.Context, cl crclient.Client, namespace, selector string, minCount int, timeout time.Duration, checks ...Check) {
+	GinkgoHelper()
+
+	ForAll(ctx, cl, appsv1.SchemeGroupVersion.WithKind("Deployment"), namespace, selector, minCount, timeout,
+		append([]Check{NotDeleting(), ConditionTrue(string(appsv1.DeploymentAvailable))}, checks...)...)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newObject(conditions ...map[string]any) *unstructured.Unstructured {
+	items := make([]any, 0, len(conditions))
+	for _, c := range conditions {
+		items = append(items, c)
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "k0rdent.mirantis.com/v1alpha1",
+		"kind":       "ClusterDeployment",
+		"metadata":   map[string]any{"name": "test", "namespace": "kcm-system"},
+		"status":     map[string]any{"conditions": items},
+	}}
+}
+
+func TestCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		obj         *unstructured.Unstructured
+		checks      []Check
+		expectedErr string
+	}{
+		{
+			name:   "ready",
+			obj:    newObject(map[string]any{"type": "Ready", "status": "True"}),
+			checks: []Check{NotDeleting(), ConditionTrue("Ready")},
+		},
+		{
+			name: "not ready",
+			obj:  newObject(map[string]any{"type": "Ready", "status": "False", "reason": "Failed", "message": "cluster is not provisioned"}),
+			checks: []Check{
+				NotDeleting(), ConditionTrue("Ready"), ConditionTrue("SveltosClusterReady"),
+			},
+			expectedErr: `ClusterDeployment kcm-system/test is not ready:
+  - condition Ready is False, expected True
+  - condition SveltosClusterReady is not reported
+  conditions:
+    Ready=False (Failed): test: cluster is not provisioned`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := check(tc.obj, tc.checks)
+			if tc.expectedErr == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
+
+func TestComponentsInstalled(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{"components": map[string]any{
+			"kcm":     map[string]any{"success": true},
+			"capi":    map[string]any{"success": true},
+			"sveltos": map[string]any{"success": false, "error": "chart is not ready"},
+		}},
+	}}
+	g.Expect(componentsInstalled(obj)).To(MatchError("component sveltos is not installed: chart is not ready"))
+
+	obj.Object["status"].(map[string]any)["components"].(map[string]any)["sveltos"] = map[string]any{"success": true}
+	g.Expect(componentsInstalled(obj)).To(Succeed())
+}
@@ -0,0 +1,62 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package golden compares the output of the tests with the golden files
+// committed along with them, so the unintended changes of the output fail the
+// tests. The golden files are rewritten with the actual output once the tests
+// are run with the -update flag, e.g. go test ./internal/sveltos/ -update.
+package golden
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the actual output of the tests")
+
+// Assert compares the actual output with the golden file at the path, which
+// is relative to the directory of the package of the test, or rewrites the
+// file with the output if the -update flag is set.
+func Assert(t testing.TB, path string, actual []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create the directory of the golden file %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil { //nolint:gosec // the golden files are committed
+			t.Fatalf("failed to write the golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("the golden file %s does not exist, run the test with the -update flag to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("failed to read the golden file %s: %v", path, err)
+	}
+
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("the output differs from the golden file %s, run the test with the -update flag if the change is intended (-golden +actual):\n%s",
+			path, cmp.Diff(strings.Split(string(expected), "\n"), strings.Split(string(actual), "\n")))
+	}
+}
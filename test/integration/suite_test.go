@@ -0,0 +1,298 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"testing"
+	"time"
+
+	helmcontrollerv2 "github.com/fluxcd/helm-controller/api/v2"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	sveltosv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	capioperator "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	clusterapiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/internal/controller"
+	kcmwebhook "github.com/K0rdent/kcm/internal/webhook"
+	"github.com/K0rdent/kcm/test/objects/release"
+)
+
+// The integration tests run the admission webhooks and the controllers
+// against a real API server started by envtest, unlike the unit tests of the
+// webhooks on the fake clients they exercise the webhook configurations
+// shipped in the kcm chart and the CRD validation.
+
+const (
+	mutatingWebhookKind   = "MutatingWebhookConfiguration"
+	validatingWebhookKind = "ValidatingWebhookConfiguration"
+	testSystemNamespace   = "test-system-namespace"
+	testReleaseName       = "test-release"
+
+	pollingInterval   = 30 * time.Millisecond
+	eventuallyTimeout = 5 * time.Second
+)
+
+var (
+	k8sClient client.Client
+	testEnv   *envtest.Environment
+	ctx       context.Context
+	cancel    context.CancelFunc
+)
+
+func TestIntegration(t *testing.T) {
+	SetDefaultEventuallyPollingInterval(pollingInterval)
+	SetDefaultEventuallyTimeout(eventuallyTimeout)
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Integration Suite")
+}
+
+var _ = BeforeSuite(func() {
+	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+
+	By("bootstrapping test environment")
+
+	ctx, cancel = context.WithCancel(context.TODO())
+
+	validatingWebhooks, mutatingWebhooks, err := loadWebhooks(
+		filepath.Join("..", "..", "templates", "provider", "kcm", "templates", "webhooks.yaml"),
+	)
+	Expect(err).NotTo(HaveOccurred())
+
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "..", "templates", "provider", "kcm", "templates", "crds"),
+			filepath.Join("..", "..", "bin", "crd"),
+		},
+		ErrorIfCRDPathMissing: true,
+
+		// The BinaryAssetsDirectory is only required to run the tests directly
+		// without the make test target, see internal/controller/suite_test.go.
+		BinaryAssetsDirectory: filepath.Join("..", "..", "bin", "k8s",
+			fmt.Sprintf("1.29.0-%s-%s", runtime.GOOS, runtime.GOARCH)),
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			ValidatingWebhooks: validatingWebhooks,
+			MutatingWebhooks:   mutatingWebhooks,
+		},
+	}
+
+	cfg, err := testEnv.Start()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(cfg).NotTo(BeNil())
+
+	Expect(kcmv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(sourcev1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(helmcontrollerv2.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(sveltosv1beta1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(capioperator.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(clusterapiv1beta1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(velerov1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(libsveltosv1beta1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(k8sClient).NotTo(BeNil())
+
+	webhookInstallOptions := &testEnv.WebhookInstallOptions
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme: scheme.Scheme,
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    webhookInstallOptions.LocalServingHost,
+			Port:    webhookInstallOptions.LocalServingPort,
+			CertDir: webhookInstallOptions.LocalServingCertDir,
+		}),
+		LeaderElection: false,
+		Metrics:        metricsserver.Options{BindAddress: "0"},
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	Expect(kcmv1.SetupIndexers(ctx, mgr)).To(Succeed())
+	Expect(setupWebhooks(mgr)).To(Succeed())
+	Expect(setupControllers(mgr)).To(Succeed())
+
+	go func() {
+		defer GinkgoRecover()
+		Expect(mgr.Start(ctx)).To(Succeed())
+	}()
+
+	// wait for the webhook server to get ready
+	dialer := &net.Dialer{Timeout: time.Second}
+	addrPort := fmt.Sprintf("%s:%d", webhookInstallOptions.LocalServingHost, webhookInstallOptions.LocalServingPort)
+	Eventually(func() error {
+		conn, err := tls.DialWithDialer(dialer, "tcp", addrPort, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}).Should(Succeed())
+
+	Expect(seedManagement(ctx)).To(Succeed())
+})
+
+var _ = AfterSuite(func() {
+	By("tearing down the test environment")
+	// the environment is not set up if the BeforeSuite has failed early
+	if cancel != nil {
+		cancel()
+	}
+	if testEnv != nil {
+		Expect(testEnv.Stop()).To(Succeed())
+	}
+})
+
+// setupWebhooks registers the webhooks the same way the manager does, see
+// cmd/main.go.
+func setupWebhooks(mgr ctrl.Manager) error {
+	if err := (&kcmwebhook.ClusterDeploymentValidator{}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&kcmwebhook.MultiClusterServiceValidator{SystemNamespace: testSystemNamespace}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&kcmwebhook.ManagementValidator{}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&kcmwebhook.AccessManagementValidator{SystemNamespace: testSystemNamespace}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&kcmwebhook.ClusterTemplateChainValidator{}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&kcmwebhook.ServiceTemplateChainValidator{}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+
+	templateValidator := kcmwebhook.TemplateValidator{
+		SystemNamespace: testSystemNamespace,
+	}
+	if err := (&kcmwebhook.ClusterTemplateValidator{TemplateValidator: templateValidator}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&kcmwebhook.ServiceTemplateValidator{TemplateValidator: templateValidator}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&kcmwebhook.ProviderTemplateValidator{TemplateValidator: templateValidator}).SetupWebhookWithManager(mgr); err != nil {
+		return err
+	}
+	return (&kcmwebhook.ReleaseValidator{}).SetupWebhookWithManager(mgr)
+}
+
+// setupControllers registers the controllers which do not require the
+// components of the Management to be installed.
+func setupControllers(mgr ctrl.Manager) error {
+	templateChainReconciler := controller.TemplateChainReconciler{
+		Client:          mgr.GetClient(),
+		SystemNamespace: testSystemNamespace,
+	}
+	if err := (&controller.ClusterTemplateChainReconciler{
+		TemplateChainReconciler: templateChainReconciler,
+	}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	return (&controller.ServiceTemplateChainReconciler{
+		TemplateChainReconciler: templateChainReconciler,
+	}).SetupWithManager(mgr)
+}
+
+// seedManagement creates the system namespace, the ready Release and the
+// Management referencing it, the latter passes the Management webhook.
+func seedManagement(ctx context.Context) error {
+	By("creating the system namespace, the Release and the Management")
+	if err := k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testSystemNamespace}}); err != nil {
+		return err
+	}
+
+	rel := release.New(release.WithName(testReleaseName), release.WithVersion("0.0.1"))
+	if err := k8sClient.Create(ctx, rel); err != nil {
+		return err
+	}
+	rel.Status.Ready = true
+	if err := k8sClient.Status().Update(ctx, rel); err != nil {
+		return err
+	}
+
+	return k8sClient.Create(ctx, &kcmv1.Management{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: kcmv1.ManagementName,
+		},
+		Spec: kcmv1.ManagementSpec{
+			Release: testReleaseName,
+		},
+	})
+}
+
+func loadWebhooks(path string) ([]*admissionv1.ValidatingWebhookConfiguration, []*admissionv1.MutatingWebhookConfiguration, error) {
+	var validatingWebhooks []*admissionv1.ValidatingWebhookConfiguration
+	var mutatingWebhooks []*admissionv1.MutatingWebhookConfiguration
+
+	webhookFile, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	re := regexp.MustCompile("{{.*}}")
+	s := re.ReplaceAllString(string(webhookFile), "")
+	objs, err := utilyaml.ToUnstructured([]byte(s))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range objs {
+		o := objs[i]
+		switch o.GetKind() {
+		case validatingWebhookKind:
+			o.SetName("validating-webhook")
+			webhookConfig := &admissionv1.ValidatingWebhookConfiguration{}
+			if err := scheme.Scheme.Convert(&o, webhookConfig, nil); err != nil {
+				return nil, nil, err
+			}
+			validatingWebhooks = append(validatingWebhooks, webhookConfig)
+		case mutatingWebhookKind:
+			o.SetName("mutating-webhook")
+			webhookConfig := &admissionv1.MutatingWebhookConfiguration{}
+			if err := scheme.Scheme.Convert(&o, webhookConfig, nil); err != nil {
+				return nil, nil, err
+			}
+			mutatingWebhooks = append(mutatingWebhooks, webhookConfig)
+		}
+	}
+	return validatingWebhooks, mutatingWebhooks, nil
+}
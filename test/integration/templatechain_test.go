@@ -0,0 +1,80 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	helmcontrollerv2 "github.com/fluxcd/helm-controller/api/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/objects/template"
+	"github.com/K0rdent/kcm/test/objects/templatechain"
+)
+
+var _ = Describe("Template chains", func() {
+	var namespace *corev1.Namespace
+
+	BeforeEach(func() {
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "templatechains-"}}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, namespace)
+	})
+
+	It("should reject the chain with the upgrade to an unsupported template", func() {
+		chain := templatechain.NewClusterTemplateChain(
+			templatechain.WithName("unsupported-upgrade"),
+			templatechain.WithNamespace(namespace.Name),
+			templatechain.WithSupportedTemplates([]kcmv1.SupportedTemplate{
+				{Name: "template-1-0-0", AvailableUpgrades: []kcmv1.AvailableUpgrade{{Name: "template-1-0-1"}}},
+			}),
+		)
+		Expect(k8sClient.Create(ctx, chain)).To(MatchError(ContainSubstring("the template chain spec is invalid")))
+	})
+
+	It("should distribute the templates of the managed chain to its namespace", func() {
+		chartRef := &helmcontrollerv2.CrossNamespaceSourceReference{Kind: "HelmChart", Name: "chart", Namespace: testSystemNamespace}
+
+		source := template.NewClusterTemplate(
+			template.WithName("distributed-1-0-0"),
+			template.WithNamespace(testSystemNamespace),
+			template.WithHelmSpec(kcmv1.HelmSpec{ChartRef: chartRef}),
+		)
+		Expect(k8sClient.Create(ctx, source)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, source)
+
+		source.Status.ChartRef = chartRef
+		Expect(k8sClient.Status().Update(ctx, source)).To(Succeed())
+
+		chain := templatechain.NewClusterTemplateChain(
+			templatechain.WithName("distributed"),
+			templatechain.WithNamespace(namespace.Name),
+			templatechain.ManagedByKCM(),
+			templatechain.WithSupportedTemplates([]kcmv1.SupportedTemplate{{Name: source.Name}}),
+		)
+		Expect(k8sClient.Create(ctx, chain)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			distributed := &kcmv1.ClusterTemplate{}
+			g.Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.Name, Name: source.Name}, distributed)).To(Succeed())
+			g.Expect(distributed.Labels).To(HaveKeyWithValue(kcmv1.KCMManagedLabelKey, kcmv1.KCMManagedLabelValue))
+			g.Expect(distributed.Spec.Helm.ChartRef).To(Equal(chartRef))
+			g.Expect(distributed.OwnerReferences).To(ContainElement(HaveField("Name", chain.Name)))
+		}).Should(Succeed())
+	})
+})
@@ -0,0 +1,84 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	helmcontrollerv2 "github.com/fluxcd/helm-controller/api/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcmv1 "github.com/K0rdent/kcm/api/v1alpha1"
+	"github.com/K0rdent/kcm/test/objects/clusterdeployment"
+	"github.com/K0rdent/kcm/test/objects/template"
+)
+
+var _ = Describe("Admission webhooks", func() {
+	var namespace *corev1.Namespace
+
+	BeforeEach(func() {
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "webhooks-"}}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, namespace)
+	})
+
+	Context("ClusterDeployment", func() {
+		It("should reject the ClusterDeployment referencing a missing template", func() {
+			cd := clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithName("missing-template"),
+				clusterdeployment.WithNamespace(namespace.Name),
+				clusterdeployment.WithClusterTemplate("missing"),
+			)
+			err := k8sClient.Create(ctx, cd)
+			Expect(err).To(HaveOccurred())
+			Expect(apierrors.IsForbidden(err)).To(BeTrue(), "unexpected error: %v", err)
+		})
+
+		It("should reject the ClusterDeployment referencing an invalid template", func() {
+			tpl := template.NewClusterTemplate(
+				template.WithName("invalid"),
+				template.WithNamespace(namespace.Name),
+				template.WithHelmSpec(kcmv1.HelmSpec{ChartRef: &helmcontrollerv2.CrossNamespaceSourceReference{Kind: "HelmChart", Name: "invalid"}}),
+			)
+			Expect(k8sClient.Create(ctx, tpl)).To(Succeed())
+
+			tpl.Status.Valid = false
+			tpl.Status.ValidationError = "chart is not found"
+			Expect(k8sClient.Status().Update(ctx, tpl)).To(Succeed())
+
+			cd := clusterdeployment.NewClusterDeployment(
+				clusterdeployment.WithName("invalid-template"),
+				clusterdeployment.WithNamespace(namespace.Name),
+				clusterdeployment.WithClusterTemplate(tpl.Name),
+			)
+			Eventually(func() error {
+				return k8sClient.Create(ctx, cd)
+			}).Should(MatchError(ContainSubstring("the template is not valid: chart is not found")))
+		})
+	})
+
+	Context("Management", func() {
+		It("should reject the Management referencing a missing Release", func() {
+			mgmt := &kcmv1.Management{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: kcmv1.ManagementName}, mgmt)).To(Succeed())
+
+			mgmt.Spec.Release = "missing"
+			Expect(k8sClient.Update(ctx, mgmt)).To(MatchError(ContainSubstring(`releases.k0rdent.mirantis.com "missing" not found`)))
+		})
+	})
+})
@@ -106,3 +106,9 @@ func WithAvailableUpgrades(availableUpgrades []string) Opt {
 		p.Status.AvailableUpgrades = availableUpgrades
 	}
 }
+
+func WithCreationTimestamp(t metav1.Time) Opt {
+	return func(p *v1alpha1.ClusterDeployment) {
+		p.CreationTimestamp = t
+	}
+}
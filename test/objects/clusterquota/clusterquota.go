@@ -0,0 +1,72 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterquota
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/K0rdent/kcm/api/v1alpha1"
+)
+
+const (
+	DefaultName      = "clusterquota"
+	DefaultNamespace = metav1.NamespaceDefault
+)
+
+type Opt func(quota *v1alpha1.ClusterQuota)
+
+func NewClusterQuota(opts ...Opt) *v1alpha1.ClusterQuota {
+	p := &v1alpha1.ClusterQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultName,
+			Namespace: DefaultNamespace,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func WithName(name string) Opt {
+	return func(p *v1alpha1.ClusterQuota) {
+		p.Name = name
+	}
+}
+
+func WithNamespace(namespace string) Opt {
+	return func(p *v1alpha1.ClusterQuota) {
+		p.Namespace = namespace
+	}
+}
+
+func WithMaxClusterDeployments(n int32) Opt {
+	return func(p *v1alpha1.ClusterQuota) {
+		p.Spec.MaxClusterDeployments = &n
+	}
+}
+
+func WithMaxNodes(n int32) Opt {
+	return func(p *v1alpha1.ClusterQuota) {
+		p.Spec.MaxNodes = &n
+	}
+}
+
+func WithAllowedInstanceTypes(instanceTypes ...string) Opt {
+	return func(p *v1alpha1.ClusterQuota) {
+		p.Spec.AllowedInstanceTypes = instanceTypes
+	}
+}
@@ -72,6 +72,18 @@ func WithProviders(providers ...v1alpha1.Provider) Opt {
 	}
 }
 
+func WithDisabledComponents(components ...v1alpha1.OptionalComponent) Opt {
+	return func(p *v1alpha1.Management) {
+		p.Spec.DisabledComponents = components
+	}
+}
+
+func WithBackups(backups ...v1alpha1.ManagementBackupSchedule) Opt {
+	return func(p *v1alpha1.Management) {
+		p.Spec.Backups = backups
+	}
+}
+
 func WithAvailableProviders(providers v1alpha1.Providers) Opt {
 	return func(p *v1alpha1.Management) {
 		p.Status.AvailableProviders = providers
@@ -89,3 +101,15 @@ func WithRelease(v string) Opt {
 		management.Spec.Release = v
 	}
 }
+
+func WithCloudQuotas(quotas ...v1alpha1.CredentialCloudQuotas) Opt {
+	return func(management *v1alpha1.Management) {
+		management.Status.CloudQuotas = quotas
+	}
+}
+
+func WithCostEstimation(costEstimation *v1alpha1.CostEstimation) Opt {
+	return func(management *v1alpha1.Management) {
+		management.Spec.CostEstimation = costEstimation
+	}
+}
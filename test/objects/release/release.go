@@ -83,3 +83,9 @@ func WithReadyStatus(ready bool) Opt {
 		r.Status.Ready = ready
 	}
 }
+
+func WithVersion(v string) Opt {
+	return func(r *v1alpha1.Release) {
+		r.Spec.Version = v
+	}
+}
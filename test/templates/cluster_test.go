@@ -0,0 +1,88 @@
+// Copyright 2024
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"sigs.k8s.io/yaml"
+
+	"github.com/K0rdent/kcm/internal/helm"
+	"github.com/K0rdent/kcm/test/golden"
+)
+
+const clusterTemplatesDir = "../../templates/cluster"
+
+// TestClusterTemplatesRender renders each of the cluster templates with the
+// fixed values of its testdata/cluster/<template>.values.yaml file, i.e. the
+// config of the ClusterDeployment along with the values set by the
+// controller, and compares the rendered manifests with the golden file.
+func TestClusterTemplatesRender(t *testing.T) {
+	charts, err := os.ReadDir(clusterTemplatesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range charts {
+		if !c.IsDir() {
+			continue
+		}
+
+		t.Run(c.Name(), func(t *testing.T) {
+			g := NewWithT(t)
+
+			chart, err := loader.LoadDir(filepath.Join(clusterTemplatesDir, c.Name()))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			valuesFile := filepath.Join("testdata", "cluster", c.Name()+".values.yaml")
+			data, err := os.ReadFile(valuesFile)
+			g.Expect(err).NotTo(HaveOccurred(), "the values of the template are to be added to %s", valuesFile)
+			values := make(map[string]any)
+			g.Expect(yaml.Unmarshal(data, &values)).To(Succeed())
+
+			manifests, err := helm.RenderChart(chart, values, "test-cluster", "test-namespace")
+			g.Expect(err).NotTo(HaveOccurred())
+
+			golden.Assert(t, filepath.Join("testdata", "cluster", c.Name()+".golden.yaml"), joinManifests(manifests))
+		})
+	}
+}
+
+// joinManifests returns the non-empty rendered manifests ordered by their
+// source files as a single multi-document YAML.
+func joinManifests(manifests map[string]string) []byte {
+	sources := make([]string, 0, len(manifests))
+	for source, manifest := range manifests {
+		if strings.HasSuffix(source, "NOTES.txt") || strings.TrimSpace(manifest) == "" {
+			continue
+		}
+		sources = append(sources, source)
+	}
+	slices.Sort(sources)
+
+	var b bytes.Buffer
+	for _, source := range sources {
+		b.WriteString("---\n# Source: " + source + "\n")
+		b.WriteString(strings.TrimSpace(manifests[source]) + "\n")
+	}
+	return b.Bytes()
+}